@@ -6,6 +6,7 @@
 #include <string.h>
 #include <stdbool.h>
 #include <signal.h>
+#include <unistd.h>
 
 #define len(array) (sizeof(array) / sizeof(array)[0])
 #define no_children(harvest) (harvest == -1 && errno == ECHILD)
@@ -47,10 +48,25 @@ static bool configure_signals(sigset_t *set) {
   return true;
 }
 
-int main(void) {
+/* Stops until guardian has applied a process's overrides, and then execs it,
+ * so that nothing the process forks can escape them. */
+static int stop_before_exec(char **argv) {
+  if (raise(SIGSTOP) != 0) {
+    printf("failed to stop: %s\n", strerror(errno));
+    return 1;
+  }
+
+  execvp(argv[0], argv);
+  fprintf(stderr, "exec: %s: %s\n", argv[0], strerror(errno));
+  return 127;
+}
+
+int main(int argc, char **argv) {
   sigset_t set;
   int sig;
 
+  if (argc > 2 && strcmp(argv[1], "--stop-before-exec") == 0) return stop_before_exec(argv + 2);
+
   if (!configure_signals(&set)) return 1;
 
   while (true) {
@@ -0,0 +1,132 @@
+package commit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/commandrunner"
+	"code.cloudfoundry.org/lager"
+)
+
+// DirCommitter commits a rootfs to a directory in the images directory. The
+// volumizer accepts the path of the directory as the rootfs of later creates,
+// as it does any other directory rootfs.
+type DirCommitter struct {
+	tarPath   string
+	imagesDir string
+	runner    commandrunner.CommandRunner
+}
+
+func NewDirCommitter(tarPath, imagesDir string, runner commandrunner.CommandRunner) *DirCommitter {
+	return &DirCommitter{
+		tarPath:   tarPath,
+		imagesDir: imagesDir,
+		runner:    runner,
+	}
+}
+
+// Commit copies the rootfs into a directory for the named image, replacing any
+// existing image with the same name, and returns the path of the directory
+func (c *DirCommitter) Commit(log lager.Logger, rootfsPath, name string) (string, error) {
+	log = log.Session("dir-commit", lager.Data{"rootfs": rootfsPath, "name": name})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	if err := os.MkdirAll(c.imagesDir, 0700); err != nil {
+		return "", fmt.Errorf("creating images dir: %s", err)
+	}
+
+	// copy into a temporary directory so that a failed commit never replaces
+	// an existing image with a partial one
+	tmpDir, err := ioutil.TempDir(c.imagesDir, ".tmp-"+name)
+	if err != nil {
+		return "", fmt.Errorf("creating image dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := c.copyRootfs(log, rootfsPath, tmpDir); err != nil {
+		return "", err
+	}
+
+	imagePath := filepath.Join(c.imagesDir, name)
+	if err := replaceDir(tmpDir, imagePath); err != nil {
+		return "", fmt.Errorf("saving image: %s", err)
+	}
+
+	return imagePath, nil
+}
+
+// copyRootfs pipes a tar of the rootfs into a tar extracting it into dst, so
+// that owners, modes and special files are kept without an intermediate
+// tarball
+func (c *DirCommitter) copyRootfs(log lager.Logger, rootfsPath, dst string) error {
+	pr, pw := io.Pipe()
+
+	createStderr := new(bytes.Buffer)
+	create := exec.Command(c.tarPath, "-cf", "-", "-C", rootfsPath, ".")
+	create.Stdout = pw
+	create.Stderr = createStderr
+
+	extractStderr := new(bytes.Buffer)
+	extract := exec.Command(c.tarPath, "-xpf", "-", "-C", dst)
+	extract.Stdin = pr
+	extract.Stderr = extractStderr
+
+	if err := c.runner.Start(create); err != nil {
+		return fmt.Errorf("tar rootfs: %s", err)
+	}
+
+	createErr := make(chan error, 1)
+	go func() {
+		createErr <- c.runner.Wait(create)
+		pw.Close()
+	}()
+
+	extractErr := c.runner.Run(extract)
+	// stop the tar of the rootfs blocking on a pipe nothing reads any more
+	pr.Close()
+
+	if err := <-createErr; err != nil {
+		log.Error("tar-failed", err, lager.Data{"stderr": createStderr.String()})
+		return fmt.Errorf("tar rootfs: %s: %s", err, createStderr.String())
+	}
+
+	if extractErr != nil {
+		log.Error("untar-failed", extractErr, lager.Data{"stderr": extractStderr.String()})
+		return fmt.Errorf("untar rootfs: %s: %s", extractErr, extractStderr.String())
+	}
+
+	return nil
+}
+
+// replaceDir moves src to dst, only removing any existing dst once src is in
+// its place
+func replaceDir(src, dst string) error {
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return os.Rename(src, dst)
+	}
+
+	oldDir, err := ioutil.TempDir(filepath.Dir(dst), ".old-"+filepath.Base(dst))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(oldDir)
+
+	oldPath := filepath.Join(oldDir, filepath.Base(dst))
+	if err := os.Rename(dst, oldPath); err != nil {
+		return err
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		os.Rename(oldPath, dst)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package commit_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	"code.cloudfoundry.org/guardian/commit"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirCommitter", func() {
+	var (
+		fakeRunner *fake_command_runner.FakeCommandRunner
+		tmpDir     string
+		imagesDir  string
+		committer  *commit.DirCommitter
+		logger     *lagertest.TestLogger
+		extractErr error
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "dir-committer")
+		Expect(err).NotTo(HaveOccurred())
+
+		imagesDir = filepath.Join(tmpDir, "images")
+		fakeRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+		committer = commit.NewDirCommitter("/path/to/tar", imagesDir, fakeRunner)
+		extractErr = nil
+
+		fakeRunner.WhenRunning(fake_command_runner.CommandSpec{Path: "/path/to/tar"}, func(cmd *exec.Cmd) error {
+			if extractErr != nil {
+				cmd.Stderr.Write([]byte("disk full"))
+				return extractErr
+			}
+
+			dst := cmd.Args[len(cmd.Args)-1]
+			return ioutil.WriteFile(filepath.Join(dst, "committed-file"), []byte("committed"), 0644)
+		})
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("pipes a tar of the rootfs into a tar extracting it in the images dir", func() {
+		_, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeRunner.StartedCommands()).To(HaveLen(1))
+		create := fakeRunner.StartedCommands()[0]
+		Expect(create.Path).To(Equal("/path/to/tar"))
+		Expect(create.Args[1:]).To(Equal([]string{"-cf", "-", "-C", "/path/to/rootfs", "."}))
+
+		Expect(fakeRunner.ExecutedCommands()).To(HaveLen(1))
+		extract := fakeRunner.ExecutedCommands()[0]
+		Expect(extract.Path).To(Equal("/path/to/tar"))
+		Expect(extract.Args[1:4]).To(Equal([]string{"-xpf", "-", "-C"}))
+		Expect(filepath.Dir(extract.Args[4])).To(Equal(imagesDir))
+	})
+
+	It("returns the path of the image directory, named after it", func() {
+		imagePath, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imagePath).To(Equal(filepath.Join(imagesDir, "some-image")))
+		Expect(imagePath).To(BeADirectory())
+		Expect(ioutil.ReadFile(filepath.Join(imagePath, "committed-file"))).To(Equal([]byte("committed")))
+	})
+
+	It("does not leave temporary files behind", func() {
+		_, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := ioutil.ReadDir(imagesDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	Context("when an image with the same name exists", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(imagesDir, "some-image"), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(imagesDir, "some-image", "previous-file"), []byte("previous image"), 0600)).To(Succeed())
+		})
+
+		It("replaces it", func() {
+			imagePath, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(imagePath, "committed-file")).To(BeAnExistingFile())
+			Expect(filepath.Join(imagePath, "previous-file")).NotTo(BeAnExistingFile())
+
+			entries, err := ioutil.ReadDir(imagesDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+	})
+
+	Context("when the tar of the rootfs fails", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(imagesDir, "some-image"), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(imagesDir, "some-image", "previous-file"), []byte("previous image"), 0600)).To(Succeed())
+
+			fakeRunner.WhenWaitingFor(fake_command_runner.CommandSpec{Path: "/path/to/tar"}, func(cmd *exec.Cmd) error {
+				cmd.Stderr.Write([]byte("permission denied"))
+				return errors.New("exit status 2")
+			})
+		})
+
+		It("returns the error", func() {
+			_, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+			Expect(err).To(MatchError("tar rootfs: exit status 2: permission denied"))
+		})
+
+		It("keeps the existing image", func() {
+			_, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+			Expect(err).To(HaveOccurred())
+
+			Expect(ioutil.ReadFile(filepath.Join(imagesDir, "some-image", "previous-file"))).To(Equal([]byte("previous image")))
+			Expect(filepath.Join(imagesDir, "some-image", "committed-file")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when extracting the rootfs fails", func() {
+		BeforeEach(func() {
+			extractErr = errors.New("exit status 2")
+		})
+
+		It("returns the error without saving an image", func() {
+			_, err := committer.Commit(logger, "/path/to/rootfs", "some-image")
+			Expect(err).To(MatchError("untar rootfs: exit status 2: disk full"))
+
+			entries, err := ioutil.ReadDir(imagesDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+})
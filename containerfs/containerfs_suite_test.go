@@ -0,0 +1,13 @@
+package containerfs_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestContainerfs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Containerfs Suite")
+}
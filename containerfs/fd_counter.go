@@ -0,0 +1,31 @@
+package containerfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// FDCounter counts the file descriptors a process has open from the entries
+// of /proc/<pid>/fd
+type FDCounter struct {
+	ProcRoot string
+}
+
+func (c *FDCounter) Count(log lager.Logger, pid int) (int, error) {
+	fdDir, err := os.Open(filepath.Join(c.ProcRoot, strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0, fmt.Errorf("counting file descriptors of process %d: %s", pid, err)
+	}
+	defer fdDir.Close()
+
+	names, err := fdDir.Readdirnames(-1)
+	if err != nil {
+		return 0, fmt.Errorf("counting file descriptors of process %d: %s", pid, err)
+	}
+
+	return len(names), nil
+}
@@ -0,0 +1,48 @@
+package containerfs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/containerfs"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FDCounter", func() {
+	var (
+		procRoot string
+		counter  *containerfs.FDCounter
+	)
+
+	BeforeEach(func() {
+		var err error
+		procRoot, err = ioutil.TempDir("", "proc")
+		Expect(err).NotTo(HaveOccurred())
+
+		fdDir := filepath.Join(procRoot, "470", "fd")
+		Expect(os.MkdirAll(fdDir, 0755)).To(Succeed())
+		for _, fd := range []string{"0", "1", "2"} {
+			Expect(os.Symlink("/dev/null", filepath.Join(fdDir, fd))).To(Succeed())
+		}
+
+		counter = &containerfs.FDCounter{ProcRoot: procRoot}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(procRoot)).To(Succeed())
+	})
+
+	It("counts the open file descriptors of the process", func() {
+		Expect(counter.Count(lagertest.NewTestLogger("test"), 470)).To(Equal(3))
+	})
+
+	Context("when the process does not exist", func() {
+		It("returns an error", func() {
+			_, err := counter.Count(lagertest.NewTestLogger("test"), 471)
+			Expect(err).To(MatchError(ContainSubstring("counting file descriptors of process 471")))
+		})
+	})
+})
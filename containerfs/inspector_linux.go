@@ -0,0 +1,276 @@
+package containerfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+const (
+	maxSymlinks = 255
+
+	// oPath is O_PATH, which the syscall package does not define
+	oPath = 0x200000
+)
+
+// Inspector reports on files in the filesystem of a container by looking
+// through /proc/<pid>/root of a process in the container, so no process needs
+// to be run in the container and no tarball needs to be streamed. Paths are
+// walked one component at a time with openat and O_NOFOLLOW, following
+// symlinks relative to the root of the container, so that neither symlinks
+// nor a container swapping a directory for a symlink part way through can be
+// used to inspect files outside of it.
+type Inspector struct {
+	ProcRoot string
+}
+
+func (i *Inspector) Stat(log lager.Logger, pid int, path string) (gardener.ContainerFileInfo, error) {
+	log = log.Session("stat", lager.Data{"pid": pid, "path": path})
+
+	fd, err := i.open(pid, path, false)
+	if err != nil {
+		return gardener.ContainerFileInfo{}, i.wrap(log, path, err)
+	}
+	defer syscall.Close(fd)
+
+	info, err := statFd(fd, filepath.Base(filepath.Join("/", path)))
+	if err != nil {
+		return gardener.ContainerFileInfo{}, i.wrap(log, path, err)
+	}
+
+	return info, nil
+}
+
+func (i *Inspector) List(log lager.Logger, pid int, path string) ([]gardener.ContainerFileInfo, error) {
+	log = log.Session("list", lager.Data{"pid": pid, "path": path})
+
+	dirFd, err := i.open(pid, path, true)
+	if err != nil {
+		return nil, i.wrap(log, path, err)
+	}
+	defer syscall.Close(dirFd)
+
+	names, err := readDirNames(dirFd)
+	if err != nil {
+		return nil, i.wrap(log, path, err)
+	}
+	sort.Strings(names)
+
+	infos := []gardener.ContainerFileInfo{}
+	for _, name := range names {
+		fd, err := syscall.Openat(dirFd, name, oPath|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			// the entry was removed since the directory was read
+			continue
+		}
+
+		info, err := statFd(fd, name)
+		syscall.Close(fd)
+		if err != nil {
+			return nil, i.wrap(log, path, err)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// open returns an O_PATH descriptor of the file which path resolves to in the
+// container of the process. A symlink at the end of the path is only followed
+// when followLast is set.
+func (i *Inspector) open(pid int, path string, followLast bool) (int, error) {
+	rootFd, err := syscall.Open(filepath.Join(i.ProcRoot, strconv.Itoa(pid), "root"), oPath|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, err
+	}
+
+	return walk(rootFd, path, followLast)
+}
+
+func (i *Inspector) wrap(log lager.Logger, path string, err error) error {
+	if os.IsNotExist(err) {
+		return gardener.FileNotFoundError{Path: path}
+	}
+
+	log.Error("failed", err)
+	return fmt.Errorf("inspecting %s: %s", path, err)
+}
+
+// walk opens path one component at a time, starting from (and taking
+// ownership of) rootFd, and following every symlink along it as if rootFd
+// were /. Only the returned descriptor is left open.
+func walk(rootFd int, path string, followLast bool) (int, error) {
+	// dirs are the open directories from the root to the current one, so that
+	// .. can go back up without ever leaving the root
+	dirs := []int{rootFd}
+	closeDirs := func(from int) {
+		for _, fd := range dirs[from:] {
+			syscall.Close(fd)
+		}
+		dirs = dirs[:from]
+	}
+
+	remaining := filepath.Join("/", path)
+	links := 0
+
+	for remaining != "" {
+		var part string
+		if i := strings.IndexRune(remaining, '/'); i == -1 {
+			part, remaining = remaining, ""
+		} else {
+			part, remaining = remaining[:i], remaining[i+1:]
+		}
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(dirs) > 1 {
+				closeDirs(len(dirs) - 1)
+			}
+			continue
+		}
+
+		fd, err := syscall.Openat(dirs[len(dirs)-1], part, oPath|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			closeDirs(0)
+			return -1, err
+		}
+
+		var stat syscall.Stat_t
+		if err := syscall.Fstat(fd, &stat); err != nil {
+			syscall.Close(fd)
+			closeDirs(0)
+			return -1, err
+		}
+
+		if stat.Mode&syscall.S_IFMT != syscall.S_IFLNK || (remaining == "" && !followLast) {
+			dirs = append(dirs, fd)
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			syscall.Close(fd)
+			closeDirs(0)
+			return -1, fmt.Errorf("too many levels of symbolic links")
+		}
+
+		target, err := readlinkFd(fd)
+		syscall.Close(fd)
+		if err != nil {
+			closeDirs(0)
+			return -1, err
+		}
+
+		if filepath.IsAbs(target) {
+			closeDirs(1)
+		}
+		remaining = target + "/" + remaining
+	}
+
+	fd := dirs[len(dirs)-1]
+	dirs = dirs[:len(dirs)-1]
+	closeDirs(0)
+
+	return fd, nil
+}
+
+// statFd describes the file of an O_PATH descriptor, which is not followed
+// if it is a symlink
+func statFd(fd int, name string) (gardener.ContainerFileInfo, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(fd, &stat); err != nil {
+		return gardener.ContainerFileInfo{}, err
+	}
+
+	mode := fileMode(stat.Mode)
+	fileInfo := gardener.ContainerFileInfo{
+		Name:    name,
+		Size:    stat.Size,
+		Mode:    mode.String(),
+		IsDir:   mode.IsDir(),
+		ModTime: time.Unix(int64(stat.Mtim.Sec), int64(stat.Mtim.Nsec)),
+	}
+
+	if mode&os.ModeSymlink != 0 {
+		fileInfo.LinkTarget, _ = readlinkFd(fd)
+	}
+
+	return fileInfo, nil
+}
+
+// fileMode converts the mode of a stat to an os.FileMode, as os.Lstat does
+func fileMode(statMode uint32) os.FileMode {
+	mode := os.FileMode(statMode & 0777)
+
+	switch statMode & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		mode |= os.ModeDir
+	case syscall.S_IFLNK:
+		mode |= os.ModeSymlink
+	case syscall.S_IFIFO:
+		mode |= os.ModeNamedPipe
+	case syscall.S_IFSOCK:
+		mode |= os.ModeSocket
+	case syscall.S_IFBLK:
+		mode |= os.ModeDevice
+	case syscall.S_IFCHR:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	}
+
+	if statMode&syscall.S_ISUID != 0 {
+		mode |= os.ModeSetuid
+	}
+	if statMode&syscall.S_ISGID != 0 {
+		mode |= os.ModeSetgid
+	}
+	if statMode&syscall.S_ISVTX != 0 {
+		mode |= os.ModeSticky
+	}
+
+	return mode
+}
+
+// readDirNames lists the directory of an O_PATH descriptor
+func readDirNames(dirFd int) ([]string, error) {
+	fd, err := syscall.Openat(dirFd, ".", syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := os.NewFile(uintptr(fd), ".")
+	defer dir.Close()
+
+	return dir.Readdirnames(-1)
+}
+
+// readlinkFd reads the target of the symlink an O_PATH descriptor refers to
+func readlinkFd(fd int) (string, error) {
+	empty, err := syscall.BytePtrFromString("")
+	if err != nil {
+		return "", err
+	}
+
+	for size := 256; ; size *= 2 {
+		buf := make([]byte, size)
+		n, _, errno := syscall.Syscall6(syscall.SYS_READLINKAT, uintptr(fd), uintptr(unsafe.Pointer(empty)), uintptr(unsafe.Pointer(&buf[0])), uintptr(size), 0, 0)
+		if errno != 0 {
+			return "", errno
+		}
+
+		if int(n) < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package containerfs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/containerfs"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inspector", func() {
+	var (
+		procRoot  string
+		root      string
+		inspector *containerfs.Inspector
+		logger    *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		var err error
+		procRoot, err = ioutil.TempDir("", "containerfs")
+		Expect(err).NotTo(HaveOccurred())
+
+		root = filepath.Join(procRoot, "123", "root")
+		Expect(os.MkdirAll(filepath.Join(root, "etc", "app"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "etc", "app", "config"), []byte("hello"), 0640)).To(Succeed())
+		Expect(os.Symlink("/etc/app", filepath.Join(root, "app"))).To(Succeed())
+		Expect(os.Symlink("../../..", filepath.Join(root, "etc", "app", "escape"))).To(Succeed())
+
+		Expect(ioutil.WriteFile(filepath.Join(procRoot, "secret"), []byte("host"), 0600)).To(Succeed())
+
+		inspector = &containerfs.Inspector{ProcRoot: procRoot}
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(procRoot)).To(Succeed())
+	})
+
+	Describe("Stat", func() {
+		It("reports the size and mode of a file", func() {
+			info, err := inspector.Stat(logger, 123, "/etc/app/config")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Name).To(Equal("config"))
+			Expect(info.Size).To(BeEquivalentTo(5))
+			Expect(info.Mode).To(Equal("-rw-r-----"))
+			Expect(info.IsDir).To(BeFalse())
+		})
+
+		It("reports directories", func() {
+			info, err := inspector.Stat(logger, 123, "/etc/app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.IsDir).To(BeTrue())
+		})
+
+		It("follows symlinks in the parent of the path relative to the container root", func() {
+			info, err := inspector.Stat(logger, 123, "/app/config")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Size).To(BeEquivalentTo(5))
+		})
+
+		It("does not follow a symlink at the end of the path", func() {
+			info, err := inspector.Stat(logger, 123, "/app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.LinkTarget).To(Equal("/etc/app"))
+			Expect(info.IsDir).To(BeFalse())
+		})
+
+		It("does not let symlinks escape the container root", func() {
+			_, err := inspector.Stat(logger, 123, "/app/escape/secret")
+			Expect(err).To(MatchError(gardener.FileNotFoundError{Path: "/app/escape/secret"}))
+		})
+
+		It("does not let relative paths escape the container root", func() {
+			_, err := inspector.Stat(logger, 123, "../../secret")
+			Expect(err).To(MatchError(gardener.FileNotFoundError{Path: "../../secret"}))
+		})
+
+		Context("when the path does not exist", func() {
+			It("returns a FileNotFoundError", func() {
+				_, err := inspector.Stat(logger, 123, "/nope/config")
+				Expect(err).To(MatchError(gardener.FileNotFoundError{Path: "/nope/config"}))
+			})
+		})
+
+		Context("when the symlinks loop", func() {
+			BeforeEach(func() {
+				Expect(os.Symlink("/loop", filepath.Join(root, "loop"))).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := inspector.Stat(logger, 123, "/loop/config")
+				Expect(err).To(MatchError(ContainSubstring("too many levels of symbolic links")))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("reports each entry of the directory", func() {
+			infos, err := inspector.List(logger, 123, "/app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infos).To(HaveLen(2))
+			Expect(infos[0].Name).To(Equal("config"))
+			Expect(infos[0].Size).To(BeEquivalentTo(5))
+			Expect(infos[1].Name).To(Equal("escape"))
+			Expect(infos[1].LinkTarget).To(Equal("../../.."))
+		})
+
+		It("lists the container root rather than the host when symlinks lead above it", func() {
+			infos, err := inspector.List(logger, 123, "/app/escape")
+			Expect(err).NotTo(HaveOccurred())
+
+			names := []string{}
+			for _, info := range infos {
+				names = append(names, info.Name)
+			}
+			Expect(names).To(ConsistOf("app", "etc"))
+		})
+
+		Context("when the directory does not exist", func() {
+			It("returns a FileNotFoundError", func() {
+				_, err := inspector.List(logger, 123, "/nope")
+				Expect(err).To(MatchError(gardener.FileNotFoundError{Path: "/nope"}))
+			})
+		})
+	})
+})
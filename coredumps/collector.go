@@ -0,0 +1,105 @@
+package coredumps
+
+import (
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . Mounter
+
+// A Mounter mounts size-limited filesystems, so that the core dump directory
+// of a container cannot grow beyond MaxSize whatever is written to it
+type Mounter interface {
+	MountTmpfs(path string, size uint64) error
+	Unmount(path string) error
+}
+
+// Collector keeps the core dumps of each container in a directory named after
+// its handle under Dir. If MaxSize is set, each directory is a tmpfs of that
+// size, which the kernel charges to the memory of the container writing to it.
+type Collector struct {
+	Dir     string
+	MaxSize uint64
+	Mounter Mounter
+}
+
+// HostDir returns the path of the core dump directory of the container,
+// whether or not it has been prepared
+func (c *Collector) HostDir(handle string) string {
+	return filepath.Join(c.Dir, handle)
+}
+
+// Prepare creates the core dump directory of the container. It is world
+// writable and sticky, like /tmp, so that the core dumps of processes running
+// as any user in the container can be written to it.
+func (c *Collector) Prepare(log lager.Logger, handle string) (string, error) {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		log.Error("creating-core-dump-dir-failed", err)
+		return "", err
+	}
+
+	dir := c.HostDir(handle)
+	if err := os.Mkdir(dir, 0755); os.IsExist(err) {
+		return dir, nil
+	} else if err != nil {
+		log.Error("creating-core-dump-dir-failed", err)
+		return "", err
+	}
+
+	if c.MaxSize > 0 && c.Mounter != nil {
+		if err := c.Mounter.MountTmpfs(dir, c.MaxSize); err != nil {
+			log.Error("mounting-core-dump-dir-failed", err)
+			os.Remove(dir)
+			return "", err
+		}
+	}
+
+	if err := os.Chmod(dir, os.ModeSticky|0777); err != nil {
+		log.Error("chmodding-core-dump-dir-failed", err)
+		c.Remove(log, handle)
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// Usage returns the total size of the core dumps of the container
+func (c *Collector) Usage(log lager.Logger, handle string) (uint64, error) {
+	var total uint64
+	err := filepath.Walk(filepath.Join(c.Dir, handle), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			total += uint64(info.Size())
+		}
+
+		return nil
+	})
+
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	return total, err
+}
+
+// Remove deletes the core dumps of the container
+func (c *Collector) Remove(log lager.Logger, handle string) error {
+	dir := filepath.Join(c.Dir, handle)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	if c.MaxSize > 0 && c.Mounter != nil {
+		if err := c.Mounter.Unmount(dir); err != nil {
+			log.Error("unmounting-core-dump-dir-failed", err)
+			return err
+		}
+	}
+
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,170 @@
+package coredumps_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/coredumps"
+	"code.cloudfoundry.org/guardian/coredumps/coredumpsfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		dir       string
+		collector *coredumps.Collector
+		logger    *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "coredumps")
+		Expect(err).NotTo(HaveOccurred())
+
+		collector = &coredumps.Collector{Dir: dir}
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("Prepare", func() {
+		It("creates a sticky, world writable directory for the container", func() {
+			hostDir, err := collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hostDir).To(Equal(filepath.Join(dir, "some-handle")))
+
+			info, err := os.Stat(hostDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.IsDir()).To(BeTrue())
+			Expect(info.Mode() & os.ModePerm).To(Equal(os.FileMode(0777)))
+			Expect(info.Mode() & os.ModeSticky).NotTo(BeZero())
+		})
+
+		It("succeeds when the directory already exists", func() {
+			_, err := collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not mount anything when there is no size limit", func() {
+			mounter := new(coredumpsfakes.FakeMounter)
+			collector.Mounter = mounter
+
+			_, err := collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mounter.MountTmpfsCallCount()).To(Equal(0))
+		})
+
+		Context("when there is a size limit", func() {
+			var mounter *coredumpsfakes.FakeMounter
+
+			BeforeEach(func() {
+				mounter = new(coredumpsfakes.FakeMounter)
+				collector.MaxSize = 4096
+				collector.Mounter = mounter
+			})
+
+			It("mounts a tmpfs of that size on the directory", func() {
+				hostDir, err := collector.Prepare(logger, "some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mounter.MountTmpfsCallCount()).To(Equal(1))
+				path, size := mounter.MountTmpfsArgsForCall(0)
+				Expect(path).To(Equal(hostDir))
+				Expect(size).To(BeEquivalentTo(4096))
+			})
+
+			It("does not mount it again when the directory already exists", func() {
+				_, err := collector.Prepare(logger, "some-handle")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = collector.Prepare(logger, "some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mounter.MountTmpfsCallCount()).To(Equal(1))
+			})
+
+			Context("when mounting fails", func() {
+				BeforeEach(func() {
+					mounter.MountTmpfsReturns(errors.New("banana"))
+				})
+
+				It("returns the error and removes the directory", func() {
+					_, err := collector.Prepare(logger, "some-handle")
+					Expect(err).To(MatchError("banana"))
+					Expect(filepath.Join(dir, "some-handle")).NotTo(BeAnExistingFile())
+				})
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("adds up the sizes of the container's core dumps", func() {
+			hostDir, err := collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(hostDir, "core.1"), make([]byte, 100), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(hostDir, "core.2"), make([]byte, 50), 0600)).To(Succeed())
+
+			Expect(collector.Usage(logger, "some-handle")).To(BeEquivalentTo(150))
+		})
+
+		It("returns zero when the container has no directory", func() {
+			Expect(collector.Usage(logger, "some-handle")).To(BeZero())
+		})
+	})
+
+	Describe("Remove", func() {
+		It("removes the container's core dumps", func() {
+			hostDir, err := collector.Prepare(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(hostDir, "core.1"), []byte("core"), 0600)).To(Succeed())
+
+			Expect(collector.Remove(logger, "some-handle")).To(Succeed())
+			Expect(hostDir).NotTo(BeAnExistingFile())
+		})
+
+		It("succeeds when the container has no directory", func() {
+			Expect(collector.Remove(logger, "some-handle")).To(Succeed())
+		})
+
+		Context("when there is a size limit", func() {
+			var mounter *coredumpsfakes.FakeMounter
+
+			BeforeEach(func() {
+				mounter = new(coredumpsfakes.FakeMounter)
+				collector.MaxSize = 4096
+				collector.Mounter = mounter
+			})
+
+			It("unmounts the directory before removing it", func() {
+				hostDir, err := collector.Prepare(logger, "some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(collector.Remove(logger, "some-handle")).To(Succeed())
+				Expect(mounter.UnmountCallCount()).To(Equal(1))
+				Expect(mounter.UnmountArgsForCall(0)).To(Equal(hostDir))
+				Expect(hostDir).NotTo(BeAnExistingFile())
+			})
+
+			Context("when unmounting fails", func() {
+				BeforeEach(func() {
+					mounter.UnmountReturns(errors.New("banana"))
+				})
+
+				It("returns the error and leaves the directory", func() {
+					hostDir, err := collector.Prepare(logger, "some-handle")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(collector.Remove(logger, "some-handle")).To(MatchError("banana"))
+					Expect(hostDir).To(BeADirectory())
+				})
+			})
+		})
+	})
+})
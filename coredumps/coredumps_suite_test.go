@@ -0,0 +1,13 @@
+package coredumps_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCoredumps(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Coredumps Suite")
+}
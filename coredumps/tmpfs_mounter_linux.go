@@ -0,0 +1,27 @@
+package coredumps
+
+import (
+	"fmt"
+	"syscall"
+)
+
+type TmpfsMounter struct{}
+
+func (TmpfsMounter) MountTmpfs(path string, size uint64) error {
+	data := fmt.Sprintf("size=%d,mode=1777", size)
+	if err := syscall.Mount("tmpfs", path, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, data); err != nil {
+		return fmt.Errorf("mount tmpfs on %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// Unmount unmounts path, treating a path which is not mounted, e.g. after a
+// reboot, as already unmounted
+func (TmpfsMounter) Unmount(path string) error {
+	if err := syscall.Unmount(path, 0); err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
+		return fmt.Errorf("unmount %s: %s", path, err)
+	}
+
+	return nil
+}
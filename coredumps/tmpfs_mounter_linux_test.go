@@ -0,0 +1,53 @@
+package coredumps_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"code.cloudfoundry.org/guardian/coredumps"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TmpfsMounter", func() {
+	var dir string
+
+	BeforeEach(func() {
+		if os.Getuid() != 0 {
+			Skip("mounting a tmpfs requires root")
+		}
+
+		var err error
+		dir, err = ioutil.TempDir("", "coredumps-tmpfs")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if dir != "" {
+			syscall.Unmount(dir, 0)
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		}
+	})
+
+	It("mounts a tmpfs which cannot grow beyond its size", func() {
+		Expect(coredumps.TmpfsMounter{}.MountTmpfs(dir, 64*1024)).To(Succeed())
+
+		err := ioutil.WriteFile(filepath.Join(dir, "core"), make([]byte, 128*1024), 0600)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*os.PathError).Err).To(Equal(syscall.ENOSPC))
+	})
+
+	It("unmounts the tmpfs", func() {
+		Expect(coredumps.TmpfsMounter{}.MountTmpfs(dir, 64*1024)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "core"), []byte("core"), 0600)).To(Succeed())
+
+		Expect(coredumps.TmpfsMounter{}.Unmount(dir)).To(Succeed())
+		Expect(filepath.Join(dir, "core")).NotTo(BeAnExistingFile())
+	})
+
+	It("succeeds when the path is not mounted", func() {
+		Expect(coredumps.TmpfsMounter{}.Unmount(dir)).To(Succeed())
+	})
+})
@@ -0,0 +1,49 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . BulkCreator
+type BulkCreator interface {
+	BulkCreate(containerSpecs []garden.ContainerSpec) []BulkCreateResult
+}
+
+type bulkCreateResponse struct {
+	Handle string `json:"handle,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkCreateHandler creates a container for each garden.ContainerSpec in the
+// JSON list POSTed to it, responding with the handle or error of each create
+// in the same order.
+func BulkCreateHandler(creator BulkCreator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var containerSpecs []garden.ContainerSpec
+		if err := json.NewDecoder(r.Body).Decode(&containerSpecs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := []bulkCreateResponse{}
+		for _, result := range creator.BulkCreate(containerSpecs) {
+			if result.Err != nil {
+				response = append(response, bulkCreateResponse{Error: result.Err.Error()})
+				continue
+			}
+
+			response = append(response, bulkCreateResponse{Handle: result.Container.Handle()})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
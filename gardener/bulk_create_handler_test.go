@@ -0,0 +1,75 @@
+package gardener_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BulkCreateHandler", func() {
+	var (
+		creator  *fakes.FakeBulkCreator
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		container := new(gardenfakes.FakeContainer)
+		container.HandleReturns("ctr-1")
+
+		creator = new(fakes.FakeBulkCreator)
+		creator.BulkCreateReturns([]gardener.BulkCreateResult{
+			{Container: container},
+			{Err: errors.New("boom")},
+		})
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/bulk-create", strings.NewReader(`[{"Handle":"ctr-1"},{"Handle":"ctr-2"}]`))
+	})
+
+	JustBeforeEach(func() {
+		gardener.BulkCreateHandler(creator).ServeHTTP(recorder, request)
+	})
+
+	It("creates the posted container specs", func() {
+		Expect(creator.BulkCreateCallCount()).To(Equal(1))
+		Expect(creator.BulkCreateArgsForCall(0)).To(Equal([]garden.ContainerSpec{
+			{Handle: "ctr-1"},
+			{Handle: "ctr-2"},
+		}))
+	})
+
+	It("responds with the handle or error of each create", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(MatchJSON(`[{"handle":"ctr-1"},{"error":"boom"}]`))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/bulk-create", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(creator.BulkCreateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not a list of container specs", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/bulk-create", strings.NewReader(`{"Handle":"ctr-1"}`))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(creator.BulkCreateCallCount()).To(Equal(0))
+		})
+	})
+})
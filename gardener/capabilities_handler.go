@@ -0,0 +1,35 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Capabilities reports what this server supports, so that orchestrators and
+// test suites can adapt to it
+type Capabilities struct {
+	Platform             string   `json:"platform"`
+	Runtime              string   `json:"runtime"`
+	GraphDriver          string   `json:"graph_driver"`
+	CgroupVersion        string   `json:"cgroup_version,omitempty"`
+	Seccomp              bool     `json:"seccomp"`
+	AppArmor             bool     `json:"apparmor"`
+	UserNamespaces       bool     `json:"user_namespaces"`
+	PrivilegedContainers bool     `json:"privileged_containers"`
+	NetworkPlugin        bool     `json:"network_plugin"`
+	SecurityPresets      []string `json:"security_presets,omitempty"`
+	MaxContainers        uint64   `json:"max_containers"`
+}
+
+// CapabilitiesHandler serves the capabilities of the server
+func CapabilitiesHandler(capabilities Capabilities) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(capabilities)
+	})
+}
@@ -0,0 +1,57 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CapabilitiesHandler", func() {
+	var (
+		capabilities gardener.Capabilities
+		recorder     *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		capabilities = gardener.Capabilities{
+			Platform:        "linux",
+			Runtime:         "runc",
+			GraphDriver:     "shed",
+			CgroupVersion:   "v1",
+			Seccomp:         true,
+			UserNamespaces:  true,
+			SecurityPresets: []string{"restricted", "baseline"},
+			MaxContainers:   250,
+		}
+		recorder = httptest.NewRecorder()
+	})
+
+	It("serves the capabilities as JSON", func() {
+		request := httptest.NewRequest("GET", "/debug/capabilities", nil)
+		gardener.CapabilitiesHandler(capabilities).ServeHTTP(recorder, request)
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var response map[string]interface{}
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(HaveKeyWithValue("graph_driver", "shed"))
+		Expect(response).To(HaveKeyWithValue("seccomp", true))
+		Expect(response).To(HaveKeyWithValue("privileged_containers", false))
+		Expect(response).To(HaveKeyWithValue("max_containers", BeEquivalentTo(250)))
+		Expect(response).To(HaveKeyWithValue("security_presets", ConsistOf("restricted", "baseline")))
+	})
+
+	Context("when the request is not a GET", func() {
+		It("responds with method not allowed", func() {
+			request := httptest.NewRequest("POST", "/debug/capabilities", nil)
+			gardener.CapabilitiesHandler(capabilities).ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
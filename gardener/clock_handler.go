@@ -0,0 +1,44 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+//go:generate counterfeiter . AdvanceableClock
+type AdvanceableClock interface {
+	Now() time.Time
+	Increment(duration time.Duration)
+}
+
+// ClockHandler serves the time of a fake clock, and advances it by the
+// duration in the 'advance' query parameter of a POST, firing any timers and
+// tickers which fall due. It lets tests drive time-dependent behaviour such as
+// deferred cleanup backoff and orphan collection deterministically.
+func ClockHandler(clock AdvanceableClock) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+		case "POST":
+			advance, err := time.ParseDuration(r.URL.Query().Get("advance"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if advance < 0 {
+				http.Error(w, "the clock cannot go backwards", http.StatusBadRequest)
+				return
+			}
+
+			clock.Increment(advance)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]time.Time{"now": clock.Now()})
+	})
+}
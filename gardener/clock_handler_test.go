@@ -0,0 +1,94 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+)
+
+var _ = Describe("ClockHandler", func() {
+	var (
+		clock    *fakeclock.FakeClock
+		start    time.Time
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		start = time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC)
+		clock = fakeclock.NewFakeClock(start)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/clock", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.ClockHandler(clock).ServeHTTP(recorder, request)
+	})
+
+	responseTime := func() time.Time {
+		var response map[string]time.Time
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		return response["now"]
+	}
+
+	It("serves the time of the clock", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(responseTime()).To(BeTemporally("==", start))
+	})
+
+	Context("when the clock is advanced", func() {
+		var fired <-chan time.Time
+
+		BeforeEach(func() {
+			fired = clock.NewTimer(time.Minute).C()
+			request = httptest.NewRequest("POST", "/debug/clock?advance=90s", nil)
+		})
+
+		It("advances the clock and serves its new time", func() {
+			Expect(responseTime()).To(BeTemporally("==", start.Add(90*time.Second)))
+			Expect(clock.Now()).To(BeTemporally("==", start.Add(90*time.Second)))
+		})
+
+		It("fires the timers which fall due", func() {
+			Eventually(fired).Should(Receive())
+		})
+	})
+
+	Context("when the duration is invalid", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/clock?advance=soon", nil)
+		})
+
+		It("responds with bad request without advancing the clock", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(clock.Now()).To(BeTemporally("==", start))
+		})
+	})
+
+	Context("when the duration is negative", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/clock?advance=-1m", nil)
+		})
+
+		It("responds with bad request without moving the clock", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(clock.Now()).To(BeTemporally("==", start))
+		})
+	})
+
+	Context("when the request is neither a GET nor a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("DELETE", "/debug/clock", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . ContainerCommitter
+type ContainerCommitter interface {
+	Commit(handle, name string) (string, error)
+}
+
+// CommitHandler snapshots the rootfs of the stopped container named by the
+// 'handle' query parameter into an image named by the 'name' query parameter,
+// and serves the rootfs path of the new image.
+func CommitHandler(committer ContainerCommitter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "missing name", http.StatusBadRequest)
+			return
+		}
+
+		rootfsPath, err := committer.Commit(handle, name)
+		if _, ok := err.(garden.ContainerNotFoundError); ok {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"rootfs": rootfsPath})
+	})
+}
@@ -0,0 +1,102 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CommitHandler", func() {
+	var (
+		committer *fakes.FakeContainerCommitter
+		recorder  *httptest.ResponseRecorder
+		request   *http.Request
+	)
+
+	BeforeEach(func() {
+		committer = new(fakes.FakeContainerCommitter)
+		committer.CommitReturns("/images/some-image", nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/commit?handle=some-handle&name=some-image", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.CommitHandler(committer).ServeHTTP(recorder, request)
+	})
+
+	It("commits the requested container to the named image", func() {
+		Expect(committer.CommitCallCount()).To(Equal(1))
+		handle, name := committer.CommitArgsForCall(0)
+		Expect(handle).To(Equal("some-handle"))
+		Expect(name).To(Equal("some-image"))
+	})
+
+	It("responds with the rootfs path of the image", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response map[string]string
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(Equal(map[string]string{"rootfs": "/images/some-image"}))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/commit?handle=some-handle&name=some-image", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(committer.CommitCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no handle is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/commit?name=some-image", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(committer.CommitCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no name is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/commit?handle=some-handle", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(committer.CommitCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			committer.CommitReturns("", garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when committing fails", func() {
+		BeforeEach(func() {
+			committer.CommitReturns("", errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -15,6 +15,9 @@ type ActualContainerSpec struct {
 	// The path to the container's rootfs
 	RootFSPath string
 
+	// The path of the container's cgroup, relative to the cgroup mountpoints
+	CgroupPath string
+
 	// Whether the container is stopped
 	Stopped bool
 
@@ -29,6 +32,10 @@ type ActualContainerSpec struct {
 
 	// Whether the container is privileged
 	Privileged bool
+
+	// Labels the container was created with, keyed by name without the
+	// label prefix
+	Labels map[string]string
 }
 
 type DesiredContainerSpec struct {
@@ -51,5 +58,24 @@ type DesiredContainerSpec struct {
 
 	Limits garden.Limits
 
+	// Name of the security preset confining an unprivileged container, or
+	// empty for the defaults
+	SecurityPreset string
+
+	// Whether an unprivileged container is exempt from the /proc and /sys
+	// hardening applied to unprivileged containers
+	RelaxProcSysHardening bool
+
+	// Properties the container is created with
+	Properties garden.Properties
+
+	// Labels the container is created with, keyed by name without the label
+	// prefix. They are recorded in the bundle rather than as properties, so
+	// that they cannot be changed after create.
+	Labels map[string]string
+
+	// URI of the image the container's rootfs is made from
+	ImageURI string
+
 	BaseConfig specs.Spec
 }
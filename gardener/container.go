@@ -2,8 +2,10 @@ package gardener
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/garden"
@@ -18,6 +20,18 @@ type container struct {
 	volumizer       Volumizer
 	networker       Networker
 	propertyManager PropertyManager
+	resourceStore   ResourceStore
+	limitUpdater    ContainerLimitUpdater
+	maxProcesses    int
+	maxOpenFiles    uint64
+	defaultUser     string
+	coreDumpLimit   func(log lager.Logger, handle string) (uint64, bool, error)
+	recordResources func(handle string, update func(*ContainerResources)) error
+
+	// startLock returns the lock which is held from counting the live
+	// processes until the new one has started, so that concurrent runs are
+	// counted one at a time
+	startLock func(handle string) *sync.Mutex
 }
 
 func (c *container) Handle() string {
@@ -25,6 +39,46 @@ func (c *container) Handle() string {
 }
 
 func (c *container) Run(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+	if c.maxProcesses > 0 {
+		startLock := c.startLock(c.handle)
+		startLock.Lock()
+		defer startLock.Unlock()
+
+		count, err := c.containerizer.LiveProcessCount(c.logger, c.handle)
+		if err != nil {
+			return nil, err
+		}
+
+		if count >= c.maxProcesses {
+			return nil, ProcessLimitExceededError{Handle: c.handle, Limit: c.maxProcesses}
+		}
+	}
+
+	if spec.User == "" {
+		spec.User = c.defaultUser
+	}
+
+	if c.coreDumpLimit != nil {
+		limit, enabled, err := c.coreDumpLimit(c.logger, c.handle)
+		if err != nil {
+			return nil, err
+		}
+
+		if enabled && (spec.Limits.Core == nil || *spec.Limits.Core > limit) {
+			spec.Limits.Core = &limit
+		}
+	}
+
+	spec.Limits = clampNofile(spec.Limits, c.maxOpenFiles)
+
+	env, err := ExpandEnv(spec.Env, func(name string) (string, bool) {
+		return c.propertyManager.Get(c.handle, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	spec.Env = env
+
 	return c.containerizer.Run(c.logger, c.handle, spec, io)
 }
 
@@ -55,6 +109,7 @@ func (c *container) Info() (garden.ContainerInfo, error) {
 	if err != nil {
 		return garden.ContainerInfo{}, err
 	}
+	properties = withLabels(properties, actualContainerSpec.Labels)
 
 	mappedPorts := []garden.PortMapping{}
 	mappedPortsCfg, _ := c.propertyManager.Get(c.handle, MappedPortsKey)
@@ -85,8 +140,11 @@ func (c *container) StreamOut(spec garden.StreamOutSpec) (io.ReadCloser, error)
 	return c.containerizer.StreamOut(c.logger, c.handle, spec)
 }
 
+// LimitBandwidth is not supported: shaping the host side of the container's
+// veth is left to the operator, so only memory and CPU limits can be updated
+// on a running container.
 func (c *container) LimitBandwidth(limits garden.BandwidthLimits) error {
-	return nil
+	return errors.New("bandwidth limits are not supported")
 }
 
 func (c *container) CurrentBandwidthLimits() (garden.BandwidthLimits, error) {
@@ -94,7 +152,7 @@ func (c *container) CurrentBandwidthLimits() (garden.BandwidthLimits, error) {
 }
 
 func (c *container) LimitCPU(limits garden.CPULimits) error {
-	return nil
+	return c.updateLimits(garden.Limits{CPU: limits})
 }
 
 func (c *container) CurrentCPULimits() (garden.CPULimits, error) {
@@ -103,15 +161,20 @@ func (c *container) CurrentCPULimits() (garden.CPULimits, error) {
 }
 
 func (c *container) LimitDisk(limits garden.DiskLimits) error {
-	return nil
+	return errors.New("disk limits can only be set when the container is created")
 }
 
 func (c *container) CurrentDiskLimits() (garden.DiskLimits, error) {
-	return garden.DiskLimits{}, nil
+	resources, err := c.resourceStore.Get(c.handle)
+	if err != nil {
+		return garden.DiskLimits{}, err
+	}
+
+	return garden.DiskLimits{ByteHard: resources.DiskQuota}, nil
 }
 
 func (c *container) LimitMemory(limits garden.MemoryLimits) error {
-	return nil
+	return c.updateLimits(garden.Limits{Memory: limits})
 }
 
 func (c *container) CurrentMemoryLimits() (garden.MemoryLimits, error) {
@@ -119,8 +182,23 @@ func (c *container) CurrentMemoryLimits() (garden.MemoryLimits, error) {
 	return info.Limits.Memory, err
 }
 
+func (c *container) updateLimits(limits garden.Limits) error {
+	return c.limitUpdater.UpdateLimits(c.handle, limits)
+}
+
 func (c *container) NetIn(hostPort, containerPort uint32) (uint32, uint32, error) {
-	return c.networker.NetIn(c.logger, c.handle, hostPort, containerPort)
+	hostPort, containerPort, err := c.networker.NetIn(c.logger, c.handle, hostPort, containerPort)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := c.recordResources(c.handle, func(r *ContainerResources) {
+		r.HostPorts = append(r.HostPorts, hostPort)
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	return hostPort, containerPort, nil
 }
 
 func (c *container) NetOut(netOutRule garden.NetOutRule) error {
@@ -145,10 +223,22 @@ func (c *container) Metrics() (garden.Metrics, error) {
 		}
 	}
 
+	var networkStat garden.ContainerNetworkStat
+	if statser, ok := c.networker.(NetworkStatser); ok {
+		// the other metrics are still worth having without the network ones,
+		// e.g. while the container's interface is being set up or torn down
+		if stats, err := statser.Stats(c.logger, c.handle); err != nil {
+			c.logger.Error("reading-network-stats-failed", err, lager.Data{"handle": c.handle})
+		} else {
+			networkStat = garden.ContainerNetworkStat{RxBytes: stats.RxBytes, TxBytes: stats.TxBytes}
+		}
+	}
+
 	return garden.Metrics{
-		CPUStat:    actualContainerMetrics.CPU,
-		MemoryStat: actualContainerMetrics.Memory,
-		DiskStat:   diskMetrics,
+		CPUStat:     actualContainerMetrics.CPU,
+		MemoryStat:  actualContainerMetrics.Memory,
+		DiskStat:    diskMetrics,
+		NetworkStat: networkStat,
 	}, nil
 }
 
@@ -165,16 +255,33 @@ func (c *container) Property(name string) (string, error) {
 }
 
 func (c *container) SetProperty(name string, value string) error {
-	c.propertyManager.Set(c.handle, name, value)
-	return nil
+	if IsLabel(name) {
+		return ImmutableLabelError{Name: name}
+	}
+
+	return setProperty(c.propertyManager, c.handle, name, value)
 }
 
 func (c *container) RemoveProperty(name string) error {
+	if IsLabel(name) {
+		return ImmutableLabelError{Name: name}
+	}
+
 	c.propertyManager.Remove(c.handle, name)
 	return nil
 }
 
 func (c *container) SetGraceTime(t time.Duration) error {
-	c.propertyManager.Set(c.handle, GraceTimeKey, fmt.Sprintf("%d", t))
+	return setProperty(c.propertyManager, c.handle, GraceTimeKey, fmt.Sprintf("%d", t))
+}
+
+// setProperty sets the property, returning the error if the property manager
+// can report that it failed to store it
+func setProperty(propertyManager PropertyManager, handle, name, value string) error {
+	if storer, ok := propertyManager.(PropertyStorer); ok {
+		return storer.Store(handle, name, value)
+	}
+
+	propertyManager.Set(handle, name, value)
 	return nil
 }
@@ -0,0 +1,64 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// CopySpec describes a path to copy from one container into another
+type CopySpec struct {
+	SrcHandle string `json:"src_handle"`
+	SrcPath   string `json:"src_path"`
+	DstHandle string `json:"dst_handle"`
+	DstPath   string `json:"dst_path"`
+
+	// User is the user to read and write the paths as. Empty means root.
+	User string `json:"user,omitempty"`
+}
+
+//go:generate counterfeiter . ContainerCopier
+type ContainerCopier interface {
+	Copy(spec CopySpec) error
+}
+
+// CopyHandler copies a path between the containers described by the JSON
+// encoded CopySpec in the request body, without the contents passing through
+// the client.
+func CopyHandler(copier ContainerCopier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var spec CopySpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if spec.SrcHandle == "" || spec.DstHandle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		if spec.SrcPath == "" || spec.DstPath == "" {
+			http.Error(w, "missing path", http.StatusBadRequest)
+			return
+		}
+
+		err := copier.Copy(spec)
+		if _, ok := err.(garden.ContainerNotFoundError); ok {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
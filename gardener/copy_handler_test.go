@@ -0,0 +1,118 @@
+package gardener_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CopyHandler", func() {
+	var (
+		copier   *fakes.FakeContainerCopier
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		copier = new(fakes.FakeContainerCopier)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/copy", strings.NewReader(
+			`{"src_handle": "src", "src_path": "/cache", "dst_handle": "dst", "dst_path": "/tmp", "user": "alice"}`,
+		))
+	})
+
+	JustBeforeEach(func() {
+		gardener.CopyHandler(copier).ServeHTTP(recorder, request)
+	})
+
+	It("copies the requested path between the containers", func() {
+		Expect(copier.CopyCallCount()).To(Equal(1))
+		Expect(copier.CopyArgsForCall(0)).To(Equal(gardener.CopySpec{
+			SrcHandle: "src",
+			SrcPath:   "/cache",
+			DstHandle: "dst",
+			DstPath:   "/tmp",
+			User:      "alice",
+		}))
+	})
+
+	It("responds with no content", func() {
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/copy", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(copier.CopyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not valid JSON", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/copy", strings.NewReader("{"))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(copier.CopyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a handle is missing", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/copy", strings.NewReader(
+				`{"src_handle": "src", "src_path": "/cache", "dst_path": "/tmp"}`,
+			))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(copier.CopyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a path is missing", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/copy", strings.NewReader(
+				`{"src_handle": "src", "src_path": "/cache", "dst_handle": "dst"}`,
+			))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(copier.CopyCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when a container does not exist", func() {
+		BeforeEach(func() {
+			copier.CopyReturns(garden.ContainerNotFoundError{Handle: "dst"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when copying fails", func() {
+		BeforeEach(func() {
+			copier.CopyReturns(errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package gardener
+
+import (
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// CoreDumpsKey is the property a container can set to EnabledCoreDumps to
+// have the core dumps of its processes collected into CoreDumpPath
+const CoreDumpsKey = "garden.core-dumps"
+
+const EnabledCoreDumps = "enabled"
+
+// A CoreDumpCollector keeps a directory on the host for the core dumps of
+// each container which has them enabled
+type CoreDumpCollector interface {
+	Prepare(log lager.Logger, handle string) (hostDir string, err error)
+	HostDir(handle string) string
+	Usage(log lager.Logger, handle string) (uint64, error)
+	Remove(log lager.Logger, handle string) error
+}
+
+// coreDumpsEnabled returns whether a container with the given properties
+// should have its core dumps collected
+func coreDumpsEnabled(properties map[string]string, allowed bool) (bool, error) {
+	value, ok := properties[CoreDumpsKey]
+	if !ok {
+		return false, nil
+	}
+
+	if value != EnabledCoreDumps {
+		return false, fmt.Errorf("invalid %s property: '%s'", CoreDumpsKey, value)
+	}
+
+	if !allowed {
+		return false, errors.New("core dump collection is disabled")
+	}
+
+	return true, nil
+}
+
+// coreDumpLimit returns the RLIMIT_CORE new processes in the container should
+// be capped at, so that no dump is larger than MaxCoreDumpSize and the dumps
+// of the container do not add up to more than MaxCoreDumpDirSize. The
+// collector bounds the directory itself; the rlimit only stops a process
+// starting a dump which cannot fit. It returns false if the container does
+// not have core dumps enabled.
+func (g *Gardener) coreDumpLimit(log lager.Logger, handle string) (uint64, bool, error) {
+	if g.CoreDumpCollector == nil {
+		return 0, false, nil
+	}
+
+	if value, _ := g.PropertyManager.Get(handle, CoreDumpsKey); value != EnabledCoreDumps {
+		return 0, false, nil
+	}
+
+	limit := g.MaxCoreDumpSize
+	if limit == 0 {
+		limit = ^uint64(0)
+	}
+
+	if g.MaxCoreDumpDirSize == 0 {
+		return limit, true, nil
+	}
+
+	used, err := g.CoreDumpCollector.Usage(log, handle)
+	if err != nil {
+		return 0, false, fmt.Errorf("measuring core dumps: %s", err)
+	}
+
+	var remaining uint64
+	if used < g.MaxCoreDumpDirSize {
+		remaining = g.MaxCoreDumpDirSize - used
+	}
+
+	if remaining < limit {
+		limit = remaining
+	}
+
+	return limit, true, nil
+}
@@ -0,0 +1,84 @@
+package gardener
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// The phases of a create, in the order they are reached
+const (
+	CreateStartedPhase    = "started"
+	VolumeCreatedPhase    = "volume-created"
+	ContainerCreatedPhase = "container-created"
+	NetworkReadyPhase     = "network-ready"
+)
+
+// CreateProgress is how far a create has got. Phase is the last phase it
+// reached.
+type CreateProgress struct {
+	Handle         string    `json:"handle"`
+	Phase          string    `json:"phase"`
+	StartedAt      time.Time `json:"started_at"`
+	PhaseReachedAt time.Time `json:"phase_reached_at"`
+}
+
+// CreateTimeoutError is returned when a create reaches a phase after its
+// CreateTimeout has passed
+type CreateTimeoutError struct {
+	Handle  string
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e CreateTimeoutError) Error() string {
+	return fmt.Sprintf("creating container '%s' took longer than %s: timed out after reaching phase '%s'", e.Handle, e.Timeout, e.Phase)
+}
+
+// reachPhase records and logs that the create of the container has reached
+// the phase, and fails it if it has taken longer than CreateTimeout
+func (g *Gardener) reachPhase(log lager.Logger, handle, phase string) error {
+	g.creatingMutex.Lock()
+	progress, ok := g.creating[handle]
+	if ok {
+		progress.Phase = phase
+		progress.PhaseReachedAt = g.now()
+	}
+	g.creatingMutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	elapsed := progress.PhaseReachedAt.Sub(progress.StartedAt)
+	log.Info("progress", lager.Data{"phase": phase, "elapsed": elapsed.String()})
+
+	if g.CreateTimeout > 0 && elapsed > g.CreateTimeout {
+		return CreateTimeoutError{Handle: handle, Phase: phase, Timeout: g.CreateTimeout}
+	}
+
+	return nil
+}
+
+// CreatesInProgress returns the progress of every create which has not yet
+// finished, oldest first
+func (g *Gardener) CreatesInProgress() []CreateProgress {
+	g.creatingMutex.Lock()
+	defer g.creatingMutex.Unlock()
+
+	inProgress := []CreateProgress{}
+	for _, progress := range g.creating {
+		inProgress = append(inProgress, *progress)
+	}
+
+	sort.Sort(byStartedAt(inProgress))
+	return inProgress
+}
+
+type byStartedAt []CreateProgress
+
+func (p byStartedAt) Len() int           { return len(p) }
+func (p byStartedAt) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byStartedAt) Less(i, j int) bool { return p[i].StartedAt.Before(p[j].StartedAt) }
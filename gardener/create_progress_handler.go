@@ -0,0 +1,25 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//go:generate counterfeiter . CreateProgressReporter
+type CreateProgressReporter interface {
+	CreatesInProgress() []CreateProgress
+}
+
+// CreateProgressHandler serves the progress of the creates which have not yet
+// finished, so that a slow create can be seen to be stuck in a given phase
+func CreateProgressHandler(reporter CreateProgressReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reporter.CreatesInProgress())
+	})
+}
@@ -0,0 +1,63 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateProgressHandler", func() {
+	var (
+		reporter *fakes.FakeCreateProgressReporter
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		startedAt := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+		reporter = new(fakes.FakeCreateProgressReporter)
+		reporter.CreatesInProgressReturns([]gardener.CreateProgress{{
+			Handle:         "slow-handle",
+			Phase:          gardener.VolumeCreatedPhase,
+			StartedAt:      startedAt,
+			PhaseReachedAt: startedAt.Add(time.Minute),
+		}})
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/create-progress", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.CreateProgressHandler(reporter).ServeHTTP(recorder, request)
+	})
+
+	It("responds with the progress of each create", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response []map[string]string
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(Equal([]map[string]string{{
+			"handle":           "slow-handle",
+			"phase":            "volume-created",
+			"started_at":       "2017-06-01T12:00:00Z",
+			"phase_reached_at": "2017-06-01T12:01:00Z",
+		}}))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/create-progress", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(reporter.CreatesInProgressCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,152 @@
+package gardener
+
+import (
+	"math"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-golang/clock"
+)
+
+//go:generate counterfeiter . DeferredDestroyer
+
+// A DeferredDestroyer can finish destroying a container whose destroy failed
+type DeferredDestroyer interface {
+	RetryDestroy(log lager.Logger, handle string) error
+	RecordFailedDestroy(handle string) (int, error)
+}
+
+// DeferredCleaner retries the destroys which the Gardener deferred, backing off
+// exponentially for each handle. The queue of handles is the set of resource
+// records marked DestroyPending, so it survives restarts.
+type DeferredCleaner struct {
+	Interval   time.Duration
+	MaxBackoff time.Duration
+	Logger     lager.Logger
+	Clock      clock.Clock
+
+	resourceStore ResourceStore
+	destroyer     DeferredDestroyer
+	nextAttempts  map[string]time.Time
+	stopped       chan struct{}
+}
+
+func NewDeferredCleaner(
+	logger lager.Logger,
+	resourceStore ResourceStore,
+	destroyer DeferredDestroyer,
+	interval time.Duration,
+	maxBackoff time.Duration,
+	clock clock.Clock,
+) *DeferredCleaner {
+	return &DeferredCleaner{
+		Interval:   interval,
+		MaxBackoff: maxBackoff,
+		Logger:     logger,
+		Clock:      clock,
+
+		resourceStore: resourceStore,
+		destroyer:     destroyer,
+		nextAttempts:  map[string]time.Time{},
+		stopped:       make(chan struct{}),
+	}
+}
+
+func (c *DeferredCleaner) Start() {
+	log := c.Logger.Session("deferred-cleaner", lager.Data{"interval": c.Interval.String()})
+	log.Info("starting")
+	ticker := c.Clock.NewTicker(c.Interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		log.Info("started")
+		defer log.Info("finished")
+
+		for {
+			select {
+			case <-ticker.C():
+				c.Clean(log)
+			case <-c.stopped:
+				return
+			}
+		}
+	}()
+}
+
+func (c *DeferredCleaner) Stop() {
+	close(c.stopped)
+}
+
+// Clean retries every deferred destroy which is not backing off
+func (c *DeferredCleaner) Clean(log lager.Logger) {
+	log = log.Session("clean")
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	handles, err := c.resourceStore.Handles()
+	if err != nil {
+		log.Error("listing-resources-failed", err)
+		return
+	}
+
+	for _, handle := range handles {
+		resources, err := c.resourceStore.Get(handle)
+		if err != nil {
+			log.Error("getting-resources-failed", err, lager.Data{"handle": handle})
+			continue
+		}
+
+		if !resources.DestroyPending {
+			continue
+		}
+
+		if next, ok := c.nextAttempts[handle]; ok && c.Clock.Now().Before(next) {
+			continue
+		}
+
+		c.retry(log.Session("retry", lager.Data{"handle": handle}), handle)
+	}
+}
+
+func (c *DeferredCleaner) retry(log lager.Logger, handle string) {
+	destroyErr := c.destroyer.RetryDestroy(log, handle)
+	if destroyErr == nil {
+		delete(c.nextAttempts, handle)
+		log.Info("cleaned-up")
+		return
+	}
+
+	attempts, err := c.destroyer.RecordFailedDestroy(handle)
+	if err != nil {
+		log.Error("recording-attempt-failed", err)
+	}
+
+	backoff := c.backoff(attempts)
+	c.nextAttempts[handle] = c.Clock.Now().Add(backoff)
+
+	log.Error("retry-failed", destroyErr, lager.Data{
+		"attempts": attempts,
+		"backoff":  backoff.String(),
+	})
+}
+
+// backoff doubles the interval for each attempt after the first, up to
+// MaxBackoff, or, when there is no MaxBackoff, until doubling it again would
+// overflow
+func (c *DeferredCleaner) backoff(attempts int) time.Duration {
+	backoff := c.Interval
+	for i := 1; i < attempts; i++ {
+		if backoff > math.MaxInt64/2 {
+			return backoff
+		}
+
+		backoff *= 2
+		if c.MaxBackoff > 0 && backoff >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+
+	return backoff
+}
@@ -0,0 +1,167 @@
+package gardener_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeferredCleaner", func() {
+	var (
+		logger        *lagertest.TestLogger
+		resourceStore *fakes.FakeResourceStore
+		destroyer     *fakes.FakeDeferredDestroyer
+		clock         *fakeclock.FakeClock
+		records       map[string]gardener.ContainerResources
+
+		cleaner *gardener.DeferredCleaner
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		resourceStore = new(fakes.FakeResourceStore)
+		destroyer = new(fakes.FakeDeferredDestroyer)
+		clock = fakeclock.NewFakeClock(time.Unix(123, 456))
+
+		records = map[string]gardener.ContainerResources{
+			"running":  {},
+			"pending":  {DestroyPending: true},
+			"pending2": {DestroyPending: true, DestroyAttempts: 3},
+		}
+		resourceStore.HandlesReturns([]string{"running", "pending", "pending2"}, nil)
+		resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+			return records[handle], nil
+		}
+		destroyer.RecordFailedDestroyStub = func(handle string) (int, error) {
+			resources := records[handle]
+			resources.DestroyAttempts++
+			records[handle] = resources
+			return resources.DestroyAttempts, nil
+		}
+
+		cleaner = gardener.NewDeferredCleaner(logger, resourceStore, destroyer, time.Minute, 10*time.Minute, clock)
+	})
+
+	retriedHandles := func() []string {
+		var handles []string
+		for i := 0; i < destroyer.RetryDestroyCallCount(); i++ {
+			_, handle := destroyer.RetryDestroyArgsForCall(i)
+			handles = append(handles, handle)
+		}
+		return handles
+	}
+
+	Describe("Clean", func() {
+		It("retries the destroys which are pending", func() {
+			cleaner.Clean(logger)
+			Expect(retriedHandles()).To(Equal([]string{"pending", "pending2"}))
+		})
+
+		It("does not record an attempt when the retry succeeds", func() {
+			cleaner.Clean(logger)
+			Expect(destroyer.RecordFailedDestroyCallCount()).To(Equal(0))
+		})
+
+		Context("when a retry fails", func() {
+			BeforeEach(func() {
+				destroyer.RetryDestroyReturns(errors.New("device or resource busy"))
+			})
+
+			It("records the failed attempt", func() {
+				cleaner.Clean(logger)
+
+				Expect(records["pending"].DestroyAttempts).To(Equal(1))
+				Expect(records["pending2"].DestroyAttempts).To(Equal(4))
+			})
+
+			It("backs off exponentially before retrying again", func() {
+				cleaner.Clean(logger)
+				Expect(destroyer.RetryDestroyCallCount()).To(Equal(2))
+
+				clock.Increment(time.Minute)
+				cleaner.Clean(logger)
+				Expect(retriedHandles()[2:]).To(Equal([]string{"pending"}))
+
+				clock.Increment(time.Minute)
+				cleaner.Clean(logger)
+				Expect(destroyer.RetryDestroyCallCount()).To(Equal(3))
+
+				clock.Increment(time.Minute)
+				cleaner.Clean(logger)
+				Expect(retriedHandles()[3:]).To(Equal([]string{"pending"}))
+
+				clock.Increment(5 * time.Minute)
+				cleaner.Clean(logger)
+				Expect(retriedHandles()[4:]).To(Equal([]string{"pending", "pending2"}))
+			})
+
+			It("does not back off for longer than the maximum", func() {
+				records["pending2"] = gardener.ContainerResources{DestroyPending: true, DestroyAttempts: 20}
+
+				cleaner.Clean(logger)
+				clock.Increment(10 * time.Minute)
+				cleaner.Clean(logger)
+
+				Expect(retriedHandles()).To(ContainElement("pending2"))
+				Expect(destroyer.RetryDestroyCallCount()).To(Equal(4))
+			})
+
+			Context("when there is no maximum backoff", func() {
+				BeforeEach(func() {
+					cleaner.MaxBackoff = 0
+				})
+
+				It("does not overflow the backoff after many attempts", func() {
+					records["pending2"] = gardener.ContainerResources{DestroyPending: true, DestroyAttempts: 100}
+
+					cleaner.Clean(logger)
+					Expect(destroyer.RetryDestroyCallCount()).To(Equal(2))
+
+					clock.Increment(24 * time.Hour)
+					cleaner.Clean(logger)
+					Expect(retriedHandles()[2:]).NotTo(ContainElement("pending2"))
+				})
+			})
+
+			It("logs the failure", func() {
+				cleaner.Clean(logger)
+				Expect(logger.LogMessages()).To(ContainElement("test.clean.retry.retry-failed"))
+			})
+		})
+
+		Context("when listing the recorded handles fails", func() {
+			BeforeEach(func() {
+				resourceStore.HandlesReturns(nil, errors.New("boom"))
+			})
+
+			It("does not retry anything", func() {
+				cleaner.Clean(logger)
+				Expect(destroyer.RetryDestroyCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		BeforeEach(func() {
+			cleaner.Start()
+		})
+
+		AfterEach(func() {
+			cleaner.Stop()
+		})
+
+		It("cleans when the interval elapses", func() {
+			Consistently(destroyer.RetryDestroyCallCount).Should(Equal(0))
+
+			clock.Increment(time.Minute)
+			Eventually(destroyer.RetryDestroyCallCount).Should(Equal(2))
+		})
+	})
+})
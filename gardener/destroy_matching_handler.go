@@ -0,0 +1,59 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . MatchingDestroyer
+type MatchingDestroyer interface {
+	DestroyMatching(props garden.Properties) ([]BulkDestroyResult, error)
+}
+
+type bulkDestroyResponse struct {
+	Handle string `json:"handle"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DestroyMatchingHandler destroys every container which has all of the
+// properties in the JSON object POSTed to it, responding with the handle and
+// any error of each destroy.
+func DestroyMatchingHandler(destroyer MatchingDestroyer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var props garden.Properties
+		if err := json.NewDecoder(r.Body).Decode(&props); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if len(props) == 0 {
+			http.Error(w, "a property selector is required", http.StatusBadRequest)
+			return
+		}
+
+		results, err := destroyer.DestroyMatching(props)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := []bulkDestroyResponse{}
+		for _, result := range results {
+			entry := bulkDestroyResponse{Handle: result.Handle}
+			if result.Err != nil {
+				entry.Error = result.Err.Error()
+			}
+			response = append(response, entry)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
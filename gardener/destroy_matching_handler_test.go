@@ -0,0 +1,97 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DestroyMatchingHandler", func() {
+	var (
+		destroyer *fakes.FakeMatchingDestroyer
+		recorder  *httptest.ResponseRecorder
+		request   *http.Request
+	)
+
+	BeforeEach(func() {
+		destroyer = new(fakes.FakeMatchingDestroyer)
+		destroyer.DestroyMatchingReturns([]gardener.BulkDestroyResult{
+			{Handle: "ctr-1"},
+			{Handle: "ctr-2", Err: errors.New("busy")},
+		}, nil)
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/destroy-matching", strings.NewReader(`{"tag:app-guid": "some-app"}`))
+	})
+
+	JustBeforeEach(func() {
+		gardener.DestroyMatchingHandler(destroyer).ServeHTTP(recorder, request)
+	})
+
+	It("destroys the containers matching the properties", func() {
+		Expect(destroyer.DestroyMatchingCallCount()).To(Equal(1))
+		Expect(destroyer.DestroyMatchingArgsForCall(0)).To(Equal(garden.Properties{"tag:app-guid": "some-app"}))
+	})
+
+	It("responds with the handle and any error of each destroy", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response []map[string]string
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(Equal([]map[string]string{
+			{"handle": "ctr-1"},
+			{"handle": "ctr-2", "error": "busy"},
+		}))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/destroy-matching", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(destroyer.DestroyMatchingCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no properties are given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/destroy-matching", strings.NewReader(`{}`))
+		})
+
+		It("responds with bad request without destroying anything", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(destroyer.DestroyMatchingCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not a JSON object", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/destroy-matching", strings.NewReader(`["ctr-1"]`))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when selecting the containers fails", func() {
+		BeforeEach(func() {
+			destroyer.DestroyMatchingReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package gardener
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// envPropertyPattern matches references to container properties in the values
+// of process environment variables, e.g. $(property:garden.network.container-ip)
+var envPropertyPattern = regexp.MustCompile(`\$\(property:([^)]+)\)`)
+
+// ExpandEnv replaces each reference to a container property in env with the
+// value of that property, so that processes can learn e.g. the IP of their
+// container without asking for its info. It is an error to reference a
+// property the container does not have.
+func ExpandEnv(env []string, lookup func(name string) (string, bool)) ([]string, error) {
+	var expanded []string
+	for i, variable := range env {
+		if !envPropertyPattern.MatchString(variable) {
+			if expanded != nil {
+				expanded = append(expanded, variable)
+			}
+			continue
+		}
+
+		if expanded == nil {
+			expanded = append([]string{}, env[:i]...)
+		}
+
+		var missing string
+		variable = envPropertyPattern.ReplaceAllStringFunc(variable, func(reference string) string {
+			name := envPropertyPattern.FindStringSubmatch(reference)[1]
+			value, ok := lookup(name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+
+		if missing != "" {
+			return nil, fmt.Errorf("expanding env: unknown property '%s'", missing)
+		}
+
+		expanded = append(expanded, variable)
+	}
+
+	if expanded == nil {
+		return env, nil
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,73 @@
+package gardener
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// ContainerFDUsage is how many file descriptors the processes in a container
+// have open. Limit is the nofile rlimit of each process, or zero if it is not
+// enforced.
+type ContainerFDUsage struct {
+	Open      int              `json:"open"`
+	Limit     uint64           `json:"limit"`
+	Processes []ProcessFDUsage `json:"processes"`
+}
+
+type ProcessFDUsage struct {
+	Pid  int `json:"pid"`
+	Open int `json:"open"`
+}
+
+//go:generate counterfeiter . FDCounter
+
+// An FDCounter counts the file descriptors a process has open
+type FDCounter interface {
+	Count(log lager.Logger, pid int) (int, error)
+}
+
+// FDUsage returns the number of file descriptors open in the container, in
+// total and by process, so that a container close to exhausting them can be
+// found before its processes start failing
+func (g *Gardener) FDUsage(handle string) (ContainerFDUsage, error) {
+	log := g.Logger.Session("fd-usage", lager.Data{"handle": handle})
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	if g.FDCounter == nil {
+		return ContainerFDUsage{}, errors.New("counting file descriptors is not supported")
+	}
+
+	processes, err := g.Processes(handle)
+	if err != nil {
+		return ContainerFDUsage{}, err
+	}
+
+	usage := ContainerFDUsage{Limit: g.MaxOpenFilesPerProcess, Processes: []ProcessFDUsage{}}
+	for _, process := range processes {
+		open, err := g.FDCounter.Count(log, process.Pid)
+		if err != nil {
+			// the process may have exited since it was listed
+			log.Debug("count-failed", lager.Data{"pid": process.Pid, "error": err.Error()})
+			continue
+		}
+
+		usage.Open += open
+		usage.Processes = append(usage.Processes, ProcessFDUsage{Pid: process.Pid, Open: open})
+	}
+
+	return usage, nil
+}
+
+// clampNofile lowers the nofile rlimit of the process to max, also setting it
+// to max if the process did not ask for one. Zero means no limit is enforced.
+func clampNofile(limits garden.ResourceLimits, max uint64) garden.ResourceLimits {
+	if max > 0 && (limits.Nofile == nil || *limits.Nofile > max) {
+		limits.Nofile = &max
+	}
+
+	return limits
+}
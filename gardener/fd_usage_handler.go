@@ -0,0 +1,43 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . ContainerFDUsageReporter
+type ContainerFDUsageReporter interface {
+	FDUsage(handle string) (ContainerFDUsage, error)
+}
+
+// FDUsageHandler serves the number of file descriptors open in the container
+// named by the 'handle' query parameter.
+func FDUsageHandler(reporter ContainerFDUsageReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		usage, err := reporter.FDUsage(handle)
+		if _, ok := err.(garden.ContainerNotFoundError); ok {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(usage)
+	})
+}
@@ -0,0 +1,90 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FDUsageHandler", func() {
+	var (
+		reporter *fakes.FakeContainerFDUsageReporter
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		reporter = new(fakes.FakeContainerFDUsageReporter)
+		reporter.FDUsageReturns(gardener.ContainerFDUsage{
+			Open:      12,
+			Limit:     1024,
+			Processes: []gardener.ProcessFDUsage{{Pid: 470, Open: 12}},
+		}, nil)
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/fd-usage?handle=some-handle", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.FDUsageHandler(reporter).ServeHTTP(recorder, request)
+	})
+
+	It("responds with the file descriptor usage of the container", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(reporter.FDUsageArgsForCall(0)).To(Equal("some-handle"))
+
+		var usage gardener.ContainerFDUsage
+		Expect(json.NewDecoder(recorder.Body).Decode(&usage)).To(Succeed())
+		Expect(usage.Open).To(Equal(12))
+		Expect(usage.Limit).To(BeEquivalentTo(1024))
+		Expect(usage.Processes).To(Equal([]gardener.ProcessFDUsage{{Pid: 470, Open: 12}}))
+	})
+
+	Context("when the handle is missing", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/fd-usage", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(reporter.FDUsageCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			reporter.FDUsageReturns(gardener.ContainerFDUsage{}, garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when counting fails", func() {
+		BeforeEach(func() {
+			reporter.FDUsageReturns(gardener.ContainerFDUsage{}, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/fd-usage?handle=some-handle", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package gardener
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// ContainerFileInfo describes a file in the filesystem of a container
+type ContainerFileInfo struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	Mode       string    `json:"mode"`
+	IsDir      bool      `json:"is_dir"`
+	ModTime    time.Time `json:"mod_time"`
+	LinkTarget string    `json:"link_target,omitempty"`
+}
+
+// FileNotFoundError is returned when a path does not exist in a container
+type FileNotFoundError struct {
+	Path string
+}
+
+func (e FileNotFoundError) Error() string {
+	return fmt.Sprintf("file not found: %s", e.Path)
+}
+
+//go:generate counterfeiter . ContainerFileInspector
+type ContainerFileInspector interface {
+	StatFile(handle, path string) (ContainerFileInfo, error)
+	ListFiles(handle, path string) ([]ContainerFileInfo, error)
+}
+
+// FilesHandler serves the ContainerFileInfo of the path given by the 'path'
+// query parameter in the container named by the 'handle' query parameter, or
+// of each entry of the directory at that path if the 'list' query parameter
+// is 'true'.
+func FilesHandler(inspector ContainerFileInspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		path := r.URL.Query().Get("path")
+		if path == "" {
+			http.Error(w, "missing path", http.StatusBadRequest)
+			return
+		}
+
+		var (
+			response interface{}
+			err      error
+		)
+		if r.URL.Query().Get("list") == "true" {
+			response, err = inspector.ListFiles(handle, path)
+		} else {
+			response, err = inspector.StatFile(handle, path)
+		}
+
+		switch err.(type) {
+		case nil:
+		case garden.ContainerNotFoundError, FileNotFoundError:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
@@ -0,0 +1,122 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilesHandler", func() {
+	var (
+		inspector *fakes.FakeContainerFileInspector
+		recorder  *httptest.ResponseRecorder
+		request   *http.Request
+	)
+
+	BeforeEach(func() {
+		inspector = new(fakes.FakeContainerFileInspector)
+		inspector.StatFileReturns(gardener.ContainerFileInfo{Name: "config", Size: 5, Mode: "-rw-r--r--"}, nil)
+		inspector.ListFilesReturns([]gardener.ContainerFileInfo{{Name: "config"}, {Name: "app", IsDir: true}}, nil)
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/files?handle=some-handle&path=/etc/config", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.FilesHandler(inspector).ServeHTTP(recorder, request)
+	})
+
+	It("stats the path in the container", func() {
+		Expect(inspector.StatFileCallCount()).To(Equal(1))
+		handle, path := inspector.StatFileArgsForCall(0)
+		Expect(handle).To(Equal("some-handle"))
+		Expect(path).To(Equal("/etc/config"))
+	})
+
+	It("responds with the file info as JSON", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response map[string]interface{}
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(HaveKeyWithValue("name", "config"))
+		Expect(response).To(HaveKeyWithValue("size", BeEquivalentTo(5)))
+		Expect(response).To(HaveKeyWithValue("mode", "-rw-r--r--"))
+		Expect(response).NotTo(HaveKey("link_target"))
+	})
+
+	Context("when listing is requested", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/files?handle=some-handle&path=/etc&list=true", nil)
+		})
+
+		It("responds with the info of each entry", func() {
+			Expect(inspector.StatFileCallCount()).To(Equal(0))
+			Expect(inspector.ListFilesCallCount()).To(Equal(1))
+
+			var response []map[string]interface{}
+			Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+			Expect(response).To(HaveLen(2))
+			Expect(response[1]).To(HaveKeyWithValue("is_dir", true))
+		})
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/files?handle=some-handle&path=/etc/config", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Context("when the path is missing", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/files?handle=some-handle", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(inspector.StatFileCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			inspector.StatFileReturns(gardener.ContainerFileInfo{}, garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		BeforeEach(func() {
+			inspector.StatFileReturns(gardener.ContainerFileInfo{}, gardener.FileNotFoundError{Path: "/etc/config"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			Expect(recorder.Body.String()).To(ContainSubstring("file not found: /etc/config"))
+		})
+	})
+
+	Context("when inspecting fails", func() {
+		BeforeEach(func() {
+			inspector.StatFileReturns(gardener.ContainerFileInfo{}, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -1,13 +1,21 @@
 package gardener
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudfoundry/dropsonde/metrics"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock"
 
 	"code.cloudfoundry.org/garden"
 	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
@@ -22,25 +30,42 @@ import (
 //go:generate counterfeiter . UidGenerator
 //go:generate counterfeiter . PropertyManager
 //go:generate counterfeiter . Restorer
+//go:generate counterfeiter . WatchRestorer
 //go:generate counterfeiter . Starter
 //go:generate counterfeiter . BulkStarter
 //go:generate counterfeiter . PeaCleaner
+//go:generate counterfeiter . RootfsCommitter
+//go:generate counterfeiter . FileInspector
+//go:generate counterfeiter . CoreDumpCollector
+//go:generate counterfeiter . NetworkStatser
+//go:generate counterfeiter . NetworkReserver
+//go:generate counterfeiter . RecordedNetworkReleaser
+//go:generate counterfeiter . CgroupRemover
+//go:generate counterfeiter . ContainerCreator
+//go:generate counterfeiter . ContainerDestroyer
+//go:generate counterfeiter . NetworkPlanner
+//go:generate counterfeiter . VolumePlanner
 
 const ContainerIPKey = "garden.network.container-ip"
 const BridgeIPKey = "garden.network.host-ip"
+const ContainerSubnetKey = "garden.network.container-subnet"
 const ExternalIPKey = "garden.network.external-ip"
 const MappedPortsKey = "garden.network.mapped-ports"
 const GraceTimeKey = "garden.grace-time"
+const NetworkQoSClassKey = "garden.network.qos-class"
+const NetworkReservationKey = "garden.network.reservation"
 
 const VolumizerSession = "volumizer"
 
 type SysInfoProvider interface {
 	TotalMemory() (uint64, error)
 	TotalDisk() (uint64, error)
+	TotalCPUs() (uint64, error)
 }
 
 type Containerizer interface {
 	Create(log lager.Logger, desiredContainerSpec spec.DesiredContainerSpec) error
+	Preview(log lager.Logger, desiredContainerSpec spec.DesiredContainerSpec) (specs.Spec, error)
 	Handles() ([]string, error)
 
 	StreamIn(log lager.Logger, handle string, streamInSpec garden.StreamInSpec) error
@@ -54,6 +79,8 @@ type Containerizer interface {
 
 	Info(log lager.Logger, handle string) (spec.ActualContainerSpec, error)
 	Metrics(log lager.Logger, handle string) (ActualContainerMetrics, error)
+	Processes(log lager.Logger, handle string) ([]ContainerProcess, error)
+	LiveProcessCount(log lager.Logger, handle string) (int, error)
 }
 
 type Networker interface {
@@ -90,6 +117,12 @@ type PropertyManager interface {
 	DestroyKeySpace(string) error
 }
 
+// A PropertyStorer is a PropertyManager which can report that a property was
+// set but could not be written to durable storage
+type PropertyStorer interface {
+	Store(handle string, name string, value string) error
+}
+
 type Starter interface {
 	Start() error
 }
@@ -102,11 +135,135 @@ type Restorer interface {
 	Restore(logger lager.Logger, handles []string) []string
 }
 
+// A WatchRestorer is a Containerizer which can re-attach to the events of a
+// container which survived a restart of the server
+type WatchRestorer interface {
+	RestoreWatch(log lager.Logger, handle string)
+}
+
 type PeaCleaner interface {
 	CleanAll(logger lager.Logger) error
 	Clean(logger lager.Logger, handle string) error
 }
 
+// A RootfsCommitter snapshots a rootfs into a named image, returning the
+// rootfs path by which later creates can use it
+type RootfsCommitter interface {
+	Commit(log lager.Logger, rootfsPath, name string) (string, error)
+}
+
+// A FileInspector reports on the files in the filesystem of a running
+// container, as seen by the process with the given pid, without streaming
+// their contents
+type FileInspector interface {
+	Stat(log lager.Logger, pid int, path string) (ContainerFileInfo, error)
+	List(log lager.Logger, pid int, path string) ([]ContainerFileInfo, error)
+}
+
+// A CreateFailureDiagnoser explains why a container could not be created,
+// returning a more actionable error which includes the one given, or nil if
+// it cannot explain the failure
+type CreateFailureDiagnoser interface {
+	Diagnose(spec garden.ContainerSpec, createErr error) error
+}
+
+// A LimitUpdater is a Containerizer which can change the resource limits of a
+// running container. Limits which are zero are left unchanged.
+type LimitUpdater interface {
+	UpdateLimits(log lager.Logger, handle string, limits garden.Limits) error
+}
+
+// A NetworkStatser is a Networker which can report the traffic through the
+// network interface of a container
+type NetworkStatser interface {
+	Stats(log lager.Logger, handle string) (ContainerNetworkStats, error)
+}
+
+// ContainerNetworkStats are the counters of a network interface, from the
+// point of view of the container
+type ContainerNetworkStats struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	TxPackets uint64 `json:"tx_packets"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxDropped uint64 `json:"tx_dropped"`
+
+	// Labels are the container's labels, so that the stats can be tagged
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// A NetworkReserver is a Networker which can keep a container's network
+// reserved under a name, so that a recreated container can get it back
+type NetworkReserver interface {
+	Reservations(log lager.Logger) (map[string]NetworkReservation, error)
+	ReleaseReservation(log lager.Logger, name string) error
+}
+
+// A NetworkPlanner is a Networker which can check the network a container
+// would be given without acquiring it
+type NetworkPlanner interface {
+	PlanNetwork(log lager.Logger, spec garden.ContainerSpec) error
+}
+
+// A VolumePlanner is a Volumizer which can check the volume a container would
+// be given without creating it
+type VolumePlanner interface {
+	Plan(log lager.Logger, spec garden.ContainerSpec) error
+}
+
+// A ContainerCreator creates containers
+type ContainerCreator interface {
+	Create(spec garden.ContainerSpec) (garden.Container, error)
+}
+
+// A ContainerDestroyer destroys containers
+type ContainerDestroyer interface {
+	Destroy(handle string) error
+}
+
+// A RecordedNetworkReleaser is a Networker which can release a container's
+// network from the resources recorded for it, for when the container's
+// properties, from which Destroy finds its network, have been lost
+type RecordedNetworkReleaser interface {
+	ReleaseRecorded(log lager.Logger, handle string, resources ContainerResources) error
+}
+
+// A CgroupRemover removes a container's cgroup, and any cgroups beneath it,
+// from every cgroup hierarchy
+type CgroupRemover interface {
+	RemoveCgroup(log lager.Logger, cgroupPath string) error
+}
+
+// NetworkReservation is the network a reservation holds, and the handle of
+// the container currently using it, if any
+type NetworkReservation struct {
+	Network string `json:"network"`
+	Handle  string `json:"handle,omitempty"`
+}
+
+// ProcessLimitExceededError is returned when running a process in a container
+// which already has the maximum number of live processes
+type ProcessLimitExceededError struct {
+	Handle string
+	Limit  int
+}
+
+func (e ProcessLimitExceededError) Error() string {
+	return fmt.Sprintf("container '%s' has reached its limit of %d processes", e.Handle, e.Limit)
+}
+
+// ExitedContainerDestroyError is returned by a Containerizer which could not
+// delete a container whose init process is no longer running. Nothing is left
+// running in the container, so the delete can be retried in the background.
+type ExitedContainerDestroyError struct {
+	Err error
+}
+
+func (e ExitedContainerDestroyError) Error() string {
+	return e.Err.Error()
+}
+
 type UidGeneratorFunc func() string
 
 func (fn UidGeneratorFunc) Generate() string {
@@ -118,9 +275,17 @@ type ActualContainerMetrics struct {
 	Memory garden.ContainerMemoryStat
 }
 
+// ContainerProcess is a process running in a container. ProcessID is empty if
+// the process was not started through garden, e.g. the container's init
+// process or a child of a garden process.
+type ContainerProcess struct {
+	Pid       int    `json:"pid"`
+	ProcessID string `json:"process_id,omitempty"`
+}
+
 // Gardener orchestrates other components to implement the Garden API
 type Gardener struct {
-	// SysInfoProvider returns total memory and total disk
+	// SysInfoProvider returns total memory, total disk and the number of cpus
 	SysInfoProvider SysInfoProvider
 
 	// Containerizer runs and manages linux containers
@@ -146,13 +311,145 @@ type Gardener struct {
 	// MaxContainers limits the advertised container capacity
 	MaxContainers uint64
 
+	// MaxProcessesPerContainer limits the number of live processes which can
+	// be run in each container. Zero means no limit.
+	MaxProcessesPerContainer int
+
+	// MaxOpenFilesPerProcess is the nofile rlimit of processes run in
+	// containers. Processes which ask for a higher limit, or for none, get
+	// this one. Zero means no limit is enforced.
+	MaxOpenFilesPerProcess uint64
+
+	// FDCounter, if set, counts the file descriptors open in containers
+	FDCounter FDCounter
+
+	// MaxSecurityPreset is the least restrictive security preset containers
+	// may request. Empty means the baseline preset.
+	MaxSecurityPreset string
+
+	// AllowHardeningRelaxation allows containers to be exempted from
+	// the /proc and /sys hardening with the ProcSysHardeningKey property
+	AllowHardeningRelaxation bool
+
+	// DefaultProcessUser is the user processes are run as when their
+	// ProcessSpec does not specify one. Empty means root.
+	DefaultProcessUser string
+
+	// BulkCreateParallelism is the number of containers BulkCreate creates at
+	// once
+	BulkCreateParallelism int
+
+	// BulkCreateServer, if set, is the garden server through whose API
+	// BulkCreate creates containers, so that they are given to its grace time
+	// reaper like any other container. Containers created directly never
+	// expire until the server restarts.
+	BulkCreateServer ContainerCreator
+
+	// DestroyMatchingServer, if set, is the garden server through whose API
+	// DestroyMatching destroys containers, so that it stops tracking them for
+	// its grace time reaper like any other destroyed container
+	DestroyMatchingServer ContainerDestroyer
+
+	// MaxDiskQuotaPercent caps the sum of all containers' disk quotas at this
+	// percentage of the total disk, so that containers filling their quotas
+	// cannot fill the disk. Zero disables the cap.
+	MaxDiskQuotaPercent uint64
+
 	Restorer Restorer
 
 	PeaCleaner PeaCleaner
 
 	AllowPrivilgedContainers bool
+
+	// ResourceStore records the resources allocated to each container so that
+	// they can be released after a crash or a failed destroy
+	ResourceStore ResourceStore
+
+	// DefaultGraceTime is the grace time of containers created without one
+	DefaultGraceTime time.Duration
+
+	// LifecycleNotifier is told when containers are created and destroyed
+	LifecycleNotifier LifecycleNotifier
+
+	// RootfsCommitter snapshots the rootfs of stopped containers into images
+	RootfsCommitter RootfsCommitter
+
+	// CgroupRemover, if set, removes the cgroup recorded for each container
+	// on destroy, which the runtime leaves behind if it lost track of the
+	// container
+	CgroupRemover CgroupRemover
+
+	// FileInspector, if set, lets StatFile and ListFiles report on the files
+	// in running containers
+	FileInspector FileInspector
+
+	// CoreDumpCollector, if set, lets containers enable the collection of
+	// their core dumps with the CoreDumpsKey property. The collected dumps
+	// are bind mounted at CoreDumpPath, where a host core_pattern such as
+	// CoreDumpPath/core.%e.%p writes them, and removed on destroy.
+	CoreDumpCollector CoreDumpCollector
+	CoreDumpPath      string
+
+	// MaxCoreDumpSize caps the size of each core dump, and MaxCoreDumpDirSize
+	// the size of all the core dumps of a container. Zero means no cap.
+	MaxCoreDumpSize    uint64
+	MaxCoreDumpDirSize uint64
+
+	// CreateTimeout, if set, fails creates which are still running this long
+	// after they started. Creates are only failed between phases, so a phase
+	// which hangs is not interrupted.
+	CreateTimeout time.Duration
+
+	// Clock, if set, is the clock creates are timed by. It defaults to the
+	// wall clock.
+	Clock clock.Clock
+
+	// Quarantiner, if set, keeps the artifacts of containers which failed to
+	// create or restore before they are destroyed, so that the failure can be
+	// inspected
+	Quarantiner Quarantiner
+
+	// ContainerHooks run, in order, before each container is created and after
+	// it is destroyed
+	ContainerHooks []ContainerHook
+
+	// DeferFailedDestroys makes Destroy succeed even when some of the
+	// container's resources could not be released, or the container itself
+	// could not be deleted once its init process exited, queueing them to be
+	// cleaned up by a DeferredCleaner instead
+	DeferFailedDestroys bool
+
+	// CreateFailureDiagnoser, if set, explains the errors of failed volume and
+	// container creations which a missing host feature plausibly caused
+	CreateFailureDiagnoser CreateFailureDiagnoser
+
+	// ReservedMemoryPercent, ReservedDiskPercent and ReservedCPUPercent are
+	// the percentages of the host kept back for the system. They are taken
+	// out of the advertised capacity, and creates and limit updates which
+	// would commit more than the rest to containers are rejected.
+	ReservedMemoryPercent uint64
+	ReservedDiskPercent   uint64
+	ReservedCPUPercent    uint64
+
+	creatingMutex sync.Mutex
+	creating      map[string]*CreateProgress
+
+	reservationMutex sync.Mutex
+
+	processStartsMutex sync.Mutex
+	processStarts      map[string]*sync.Mutex
+
+	resourceLocksMutex sync.Mutex
+	resourceLocks      map[string]*sync.Mutex
+
+	labelsMutex sync.Mutex
+	labels      map[string]map[string]string
 }
 
+// SharesPerCPU is the number of CPU shares which are counted as one core when
+// checking the containers' CPU shares against the CPUs reserved for the system
+const SharesPerCPU = 1024
+
 // Create creates a container by combining the results of networker.Network,
 // volumizer.Create and containzer.Create.
 func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Container, err error) {
@@ -167,9 +464,18 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 		_ = metrics.SendValue("ContainerCreationDuration", float64(time.Since(startedAt).Nanoseconds()), "nanos")
 	}(time.Now())
 
-	if !g.AllowPrivilgedContainers && containerSpec.Privileged {
-		return nil, errors.New("privileged container creation is disabled")
+	// hooks run first so that the specs they return are subject to the same
+	// checks as any other
+	containerSpec, err := g.runPreCreateHooks(log, containerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := g.planCreate(log, containerSpec)
+	if err != nil {
+		return nil, err
 	}
+	containerSpec = plan.containerSpec
 
 	knownHandles, err := g.Containerizer.Handles()
 	if err != nil {
@@ -184,6 +490,13 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 		return nil, err
 	}
 
+	g.startCreating(containerSpec.Handle)
+	defer g.finishCreating(containerSpec.Handle)
+
+	if err := g.reserveResources(containerSpec.Handle, containerSpec.Limits); err != nil {
+		return nil, err
+	}
+
 	defer func() {
 		if err != nil {
 			log := log.Session("create-failed-cleaningup", lager.Data{
@@ -192,6 +505,8 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 
 			log.Info("start")
 
+			g.quarantine(log, containerSpec.Handle, fmt.Sprintf("create failed: %s", err))
+
 			err := g.destroy(log, containerSpec.Handle)
 			if err != nil {
 				log.Error("destroy-failed", err)
@@ -207,21 +522,37 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 		log.Error("graph-cleanup-failed", err)
 	}
 
+	if plan.collectCoreDumps {
+		hostDir, err := g.CoreDumpCollector.Prepare(log, containerSpec.Handle)
+		if err != nil {
+			return nil, fmt.Errorf("preparing core dump directory: %s", err)
+		}
+
+		containerSpec = g.withCoreDumpMount(containerSpec, hostDir)
+	}
+
 	runtimeSpec, err := g.Volumizer.Create(log, containerSpec)
 	if err != nil {
+		return nil, g.diagnoseCreateFailure(log, containerSpec, err)
+	}
+
+	if err := g.reachPhase(log, containerSpec.Handle, VolumeCreatedPhase); err != nil {
 		return nil, err
 	}
 
-	desiredSpec := spec.DesiredContainerSpec{
-		Handle:     containerSpec.Handle,
-		Hostname:   containerSpec.Handle,
-		Privileged: containerSpec.Privileged,
-		Env:        containerSpec.Env,
-		BindMounts: containerSpec.BindMounts,
-		Limits:     containerSpec.Limits,
-		BaseConfig: runtimeSpec,
+	if runtimeSpec.Root != nil {
+		if err := g.recordResources(containerSpec.Handle, func(r *ContainerResources) {
+			r.VolumePath = runtimeSpec.Root.Path
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.Containerizer.Create(log, desiredContainerSpec(containerSpec, runtimeSpec, plan.preset, plan.relaxHardening)); err != nil {
+		return nil, g.diagnoseCreateFailure(log, containerSpec, err)
 	}
-	if err := g.Containerizer.Create(log, desiredSpec); err != nil {
+
+	if err := g.reachPhase(log, containerSpec.Handle, ContainerCreatedPhase); err != nil {
 		return nil, err
 	}
 
@@ -236,15 +567,38 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 		return nil, err
 	}
 
+	if err := g.recordResources(containerSpec.Handle, func(r *ContainerResources) {
+		r.CgroupPath = actualSpec.CgroupPath
+	}); err != nil {
+		return nil, err
+	}
+
 	if err = g.Networker.Network(log, containerSpec, actualSpec.Pid); err != nil {
 		return nil, err
 	}
 
+	if err := g.reachPhase(log, containerSpec.Handle, NetworkReadyPhase); err != nil {
+		return nil, err
+	}
+
+	if err := g.recordResources(containerSpec.Handle, func(r *ContainerResources) {
+		r.ContainerIP, _ = g.PropertyManager.Get(containerSpec.Handle, ContainerIPKey)
+		r.Subnet, _ = g.PropertyManager.Get(containerSpec.Handle, ContainerSubnetKey)
+		r.BridgeIP, _ = g.PropertyManager.Get(containerSpec.Handle, BridgeIPKey)
+		r.HostPorts = mappedHostPorts(g.PropertyManager, containerSpec.Handle)
+	}); err != nil {
+		return nil, err
+	}
+
 	container, err := g.Lookup(containerSpec.Handle)
 	if err != nil {
 		return nil, err
 	}
 
+	if containerSpec.GraceTime == 0 {
+		containerSpec.GraceTime = g.DefaultGraceTime
+	}
+
 	if containerSpec.GraceTime != 0 {
 		if err := container.SetGraceTime(containerSpec.GraceTime); err != nil {
 			return nil, err
@@ -252,217 +606,1367 @@ func (g *Gardener) Create(containerSpec garden.ContainerSpec) (ctr garden.Contai
 	}
 
 	for name, value := range containerSpec.Properties {
+		// labels are recorded in the bundle rather than as properties
+		if IsLabel(name) {
+			continue
+		}
+
 		if err := container.SetProperty(name, value); err != nil {
 			return nil, err
 		}
 	}
+	labels := Labels(containerSpec.Properties)
+	g.rememberLabels(containerSpec.Handle, labels)
 
 	if err := container.SetProperty("garden.state", "created"); err != nil {
 		return nil, err
 	}
 
+	properties, _ := g.PropertyManager.All(containerSpec.Handle)
+	g.LifecycleNotifier.Notify(log, LifecycleEvent{
+		Type:       ContainerCreatedEvent,
+		Handle:     containerSpec.Handle,
+		Properties: withLabels(properties, labels),
+	})
+
 	return container, nil
 }
 
-func (g *Gardener) Lookup(handle string) (garden.Container, error) {
-	return g.lookup(handle), nil
+// createPlan is what Create works out from a container spec before it
+// allocates anything for the container
+type createPlan struct {
+	containerSpec    garden.ContainerSpec
+	preset           string
+	relaxHardening   bool
+	collectCoreDumps bool
 }
 
-func (g *Gardener) lookup(handle string) garden.Container {
-	return &container{
-		logger:          g.Logger,
-		handle:          handle,
-		containerizer:   g.Containerizer,
-		volumizer:       g.Volumizer,
-		networker:       g.Networker,
-		propertyManager: g.PropertyManager,
+// planCreate checks the spec and works out how the container is to be
+// created. Create and PreviewContainer share it so that previews match what
+// is created.
+func (g *Gardener) planCreate(log lager.Logger, containerSpec garden.ContainerSpec) (createPlan, error) {
+	if !g.AllowPrivilgedContainers && containerSpec.Privileged {
+		return createPlan{}, errors.New("privileged container creation is disabled")
 	}
-}
 
-func (g *Gardener) Destroy(handle string) error {
-	log := g.Logger.Session("destroy", lager.Data{"handle": handle})
+	preset, err := securityPreset(containerSpec.Properties[SecurityPresetKey], g.MaxSecurityPreset)
+	if err != nil {
+		return createPlan{}, err
+	}
 
-	log.Info("start")
-	defer log.Info("finished")
+	relaxHardening, err := relaxProcSysHardening(containerSpec.Properties, g.AllowHardeningRelaxation)
+	if err != nil {
+		return createPlan{}, err
+	}
 
-	handles, err := g.Containerizer.Handles()
+	collectCoreDumps, err := coreDumpsEnabled(containerSpec.Properties, g.CoreDumpCollector != nil)
 	if err != nil {
-		return err
+		return createPlan{}, err
 	}
 
-	if !g.exists(handles, handle) {
-		return garden.ContainerNotFoundError{Handle: handle}
+	if _, _, err := healthProbe(containerSpec.Properties); err != nil {
+		return createPlan{}, err
 	}
 
-	return g.destroy(log, handle)
+	return createPlan{
+		containerSpec:    containerSpec,
+		preset:           preset,
+		relaxHardening:   relaxHardening,
+		collectCoreDumps: collectCoreDumps,
+	}, nil
 }
 
-// destroy idempotently destroys any resources associated with the given handle
-func (g *Gardener) destroy(log lager.Logger, handle string) error {
-	if err := g.Containerizer.Destroy(log, handle); err != nil {
+// withCoreDumpMount adds the bind mount of the container's core dump
+// directory to its spec
+func (g *Gardener) withCoreDumpMount(containerSpec garden.ContainerSpec, hostDir string) garden.ContainerSpec {
+	containerSpec.BindMounts = append(append([]garden.BindMount{}, containerSpec.BindMounts...), garden.BindMount{
+		SrcPath: hostDir,
+		DstPath: g.CoreDumpPath,
+		Mode:    garden.BindMountModeRW,
+		Origin:  garden.BindMountOriginHost,
+	})
+
+	return containerSpec
+}
+
+func (g *Gardener) diagnoseCreateFailure(log lager.Logger, containerSpec garden.ContainerSpec, err error) error {
+	if g.CreateFailureDiagnoser == nil {
 		return err
 	}
 
-	if err := g.Networker.Destroy(log, handle); err != nil {
-		return err
+	if diagnosed := g.CreateFailureDiagnoser.Diagnose(containerSpec, err); diagnosed != nil {
+		log.Error("host-feature-missing", diagnosed)
+		return diagnosed
 	}
 
-	if err := g.Volumizer.Destroy(log.Session(VolumizerSession), handle); err != nil {
-		return err
+	return err
+}
+
+func desiredContainerSpec(containerSpec garden.ContainerSpec, baseConfig specs.Spec, securityPreset string, relaxHardening bool) spec.DesiredContainerSpec {
+	return spec.DesiredContainerSpec{
+		Handle:                containerSpec.Handle,
+		Hostname:              containerSpec.Handle,
+		Privileged:            containerSpec.Privileged,
+		Env:                   containerSpec.Env,
+		BindMounts:            containerSpec.BindMounts,
+		Limits:                containerSpec.Limits,
+		SecurityPreset:        securityPreset,
+		RelaxProcSysHardening: relaxHardening,
+		Properties:            containerSpec.Properties,
+		Labels:                Labels(containerSpec.Properties),
+		ImageURI:              imageURI(containerSpec),
+		BaseConfig:            baseConfig,
 	}
+}
 
-	if err := g.PropertyManager.DestroyKeySpace(handle); err != nil {
-		return err
+// imageURI returns the URI of the image the container's rootfs is made from,
+// whichever way it was given
+func imageURI(containerSpec garden.ContainerSpec) string {
+	if containerSpec.Image.URI != "" {
+		return containerSpec.Image.URI
 	}
 
-	return g.Containerizer.RemoveBundle(log, handle)
+	return containerSpec.RootFSPath
 }
 
-func (g *Gardener) Stop() {}
-
-func (g *Gardener) GraceTime(container garden.Container) time.Duration {
-	property, ok := g.PropertyManager.Get(container.Handle(), GraceTimeKey)
-	if !ok {
-		return 0
+// PreviewContainer returns the OCI spec that Create would give a container
+// with the given spec, without creating any volumes, networks or bundles. The
+// spec goes through the same checks as in Create, and the Volumizer
+// and Networker check the volume and network where they can, but nothing is
+// allocated. As no volume is created, the rootfs path is the requested rootfs
+// URI rather than the path the Volumizer would produce.
+//
+// The pre-create hooks are not run, as they are the operator's binaries and
+// may act on the container they are told is being created, so the preview is
+// of the spec as given.
+func (g *Gardener) PreviewContainer(containerSpec garden.ContainerSpec) (specs.Spec, error) {
+	if containerSpec.Handle == "" {
+		containerSpec.Handle = g.UidGenerator.Generate()
 	}
 
-	var graceTime time.Duration
-	_, err := fmt.Sscanf(property, "%d", &graceTime)
+	log := g.Logger.Session("preview", lager.Data{"handle": containerSpec.Handle})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	plan, err := g.planCreate(log, containerSpec)
 	if err != nil {
-		return 0
+		return specs.Spec{}, err
 	}
+	containerSpec = plan.containerSpec
 
-	return graceTime
-}
+	if plan.collectCoreDumps {
+		containerSpec = g.withCoreDumpMount(containerSpec, g.CoreDumpCollector.HostDir(containerSpec.Handle))
+	}
 
-func (g *Gardener) Ping() error { return nil }
+	if planner, ok := g.Volumizer.(VolumePlanner); ok {
+		if err := planner.Plan(log, containerSpec); err != nil {
+			return specs.Spec{}, err
+		}
+	}
 
-func (g *Gardener) Capacity() (garden.Capacity, error) {
-	mem, err := g.SysInfoProvider.TotalMemory()
-	if err != nil {
-		return garden.Capacity{}, err
+	if planner, ok := g.Networker.(NetworkPlanner); ok {
+		if err := planner.PlanNetwork(log, containerSpec); err != nil {
+			return specs.Spec{}, err
+		}
 	}
 
-	disk, err := g.SysInfoProvider.TotalDisk()
-	if err != nil {
-		return garden.Capacity{}, err
+	rootFSPath := imageURI(containerSpec)
+	if rootFSURL, err := url.Parse(rootFSPath); err == nil && rootFSURL.Scheme == RawRootFSScheme {
+		rootFSPath = rootFSURL.Path
 	}
 
-	cap := g.Networker.Capacity()
-	if g.MaxContainers > 0 && g.MaxContainers < cap {
-		cap = g.MaxContainers
+	baseConfig := specs.Spec{
+		Root:    &specs.Root{Path: rootFSPath},
+		Process: &specs.Process{},
 	}
 
-	return garden.Capacity{
-		MemoryInBytes: mem,
-		DiskInBytes:   disk,
-		MaxContainers: cap,
-	}, nil
+	return g.Containerizer.Preview(log, desiredContainerSpec(containerSpec, baseConfig, plan.preset, plan.relaxHardening))
 }
 
-func (g *Gardener) Containers(props garden.Properties) ([]garden.Container, error) {
-	log := g.Logger.Session("list-containers")
+// BulkCreateResult is the outcome of creating one of the containers passed to
+// BulkCreate
+type BulkCreateResult struct {
+	Container garden.Container
+	Err       error
+}
 
-	log.Info("starting")
+// BulkCreate creates a container for each of the specs, running at most
+// BulkCreateParallelism creates at once. Images which are used by more than
+// one spec are fetched once up front rather than by every create. Specs which
+// repeat the handle of an earlier spec are rejected without being created.
+// The results are in the same order as the specs.
+func (g *Gardener) BulkCreate(containerSpecs []garden.ContainerSpec) []BulkCreateResult {
+	log := g.Logger.Session("bulk-create", lager.Data{"count": len(containerSpecs)})
+
+	log.Info("start")
 	defer log.Info("finished")
 
-	handles, err := g.Containerizer.Handles()
-	if err != nil {
-		log.Error("handles-failed", err)
-		return []garden.Container{}, err
-	}
+	results := make([]BulkCreateResult, len(containerSpecs))
 
-	if props == nil {
-		props = garden.Properties{}
+	// concurrent creates of one handle would both pass the duplicate handle
+	// check, and the failed one's clean up would destroy the other
+	seen := map[string]bool{}
+	var toCreate []int
+	for i, containerSpec := range containerSpecs {
+		if containerSpec.Handle != "" && seen[containerSpec.Handle] {
+			results[i] = BulkCreateResult{Err: fmt.Errorf("handle '%s' appears more than once in the batch", containerSpec.Handle)}
+			continue
+		}
+
+		seen[containerSpec.Handle] = true
+		toCreate = append(toCreate, i)
 	}
-	props["garden.state"] = "created"
 
-	var containers []garden.Container
-	for _, handle := range handles {
-		matched := g.PropertyManager.MatchesAll(handle, props)
-		if matched {
-			containers = append(containers, g.lookup(handle))
+	for _, containerSpec := range sharedImages(containerSpecs) {
+		if err := g.PrefetchImage(containerSpec); err != nil {
+			// each create will fetch the image itself and report the error
+			log.Error("prefetch-image-failed", err, lager.Data{"image": containerSpec.Image.URI, "rootfs": containerSpec.RootFSPath})
 		}
 	}
 
-	return containers, nil
+	var creator ContainerCreator = g
+	if g.BulkCreateServer != nil {
+		creator = g.BulkCreateServer
+	}
+
+	g.inParallel(len(toCreate), func(i int) {
+		container, err := creator.Create(containerSpecs[toCreate[i]])
+		results[toCreate[i]] = BulkCreateResult{Container: container, Err: err}
+	})
+
+	return results
 }
 
-func (g *Gardener) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
-	result := make(map[string]garden.ContainerInfoEntry)
-	for _, handle := range handles {
-		container := g.lookup(handle)
+// BulkDestroyResult is the outcome of destroying one of the containers
+// selected by DestroyMatching
+type BulkDestroyResult struct {
+	Handle string
+	Err    error
+}
 
-		var infoErr *garden.Error = nil
-		info, err := container.Info()
-		if err != nil {
-			infoErr = garden.NewError(err.Error())
-		}
-		result[handle] = garden.ContainerInfoEntry{
-			Info: info,
-			Err:  infoErr,
-		}
+// DestroyMatching destroys every container which has all of the given
+// properties, which are matched as by Containers, running at most
+// BulkCreateParallelism destroys at once. At least one property which is not
+// just wildcards is required so that a mistake cannot destroy every container.
+func (g *Gardener) DestroyMatching(props garden.Properties) ([]BulkDestroyResult, error) {
+	log := g.Logger.Session("destroy-matching", lager.Data{"properties": props})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	if len(props) == 0 {
+		return nil, errors.New("a property selector is required")
 	}
 
-	return result, nil
-}
+	if onlyWildcards(props) {
+		return nil, errors.New("a property selector must not match every container")
+	}
 
-func (g *Gardener) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
-	result := make(map[string]garden.ContainerMetricsEntry)
-	for _, handle := range handles {
-		var e *garden.Error
-		m, err := g.lookup(handle).Metrics()
-		if err != nil {
-			e = garden.NewError(err.Error())
-		}
+	selector := garden.Properties{}
+	for key, value := range props {
+		selector[key] = value
+	}
 
-		result[handle] = garden.ContainerMetricsEntry{
-			Err:     e,
-			Metrics: m,
-		}
+	containers, err := g.Containers(selector)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	var destroyer ContainerDestroyer = g
+	if g.DestroyMatchingServer != nil {
+		destroyer = g.DestroyMatchingServer
+	}
+
+	results := make([]BulkDestroyResult, len(containers))
+	g.inParallel(len(containers), func(i int) {
+		handle := containers[i].Handle()
+		results[i] = BulkDestroyResult{Handle: handle, Err: destroyer.Destroy(handle)}
+	})
+
+	return results, nil
 }
 
-func (g *Gardener) checkDuplicateHandle(knownHandles []string, handle string) error {
-	if g.exists(knownHandles, handle) {
-		return fmt.Errorf("Handle '%s' already in use", handle)
+// onlyWildcards returns whether every key and value of the selector is made
+// only of wildcards, such as {"*": "*"}, which would select every container
+func onlyWildcards(props garden.Properties) bool {
+	for key, value := range props {
+		if strings.Trim(key, "*") != "" || strings.Trim(value, "*") != "" {
+			return false
+		}
 	}
 
-	return nil
+	return true
 }
 
-func (g *Gardener) exists(handles []string, handle string) bool {
-	for _, h := range handles {
-		if h == handle {
-			return true
-		}
+// inParallel calls f with each index up to n, running at most
+// BulkCreateParallelism calls at once
+func (g *Gardener) inParallel(n int, f func(i int)) {
+	parallelism := g.BulkCreateParallelism
+	if parallelism <= 0 {
+		parallelism = 1
 	}
 
-	return false
+	throttle := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		throttle <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			f(i)
+		}(i)
+	}
+	wg.Wait()
 }
 
-func (g *Gardener) checkMaxContainers(handles []string) error {
-	if g.MaxContainers == 0 {
-		return nil
+// sharedImages returns one spec for each image used by more than one of the
+// specs
+func sharedImages(containerSpecs []garden.ContainerSpec) []garden.ContainerSpec {
+	type imageKey struct {
+		uri        string
+		rootFSPath string
+		privileged bool
 	}
 
-	if len(handles) >= int(g.MaxContainers) {
-		return errors.New("max containers reached")
+	counts := map[imageKey]int{}
+	var shared []garden.ContainerSpec
+	for _, containerSpec := range containerSpecs {
+		key := imageKey{containerSpec.Image.URI, containerSpec.RootFSPath, containerSpec.Privileged}
+		counts[key]++
+		if counts[key] == 2 {
+			shared = append(shared, containerSpec)
+		}
 	}
 
-	return nil
+	return shared
 }
 
-func (g *Gardener) Start() error {
-	log := g.Logger.Session("start")
+// PrefetchImage fetches the image of the given spec into the image cache
+// without creating a container, so that later creates using the image are
+// fast. Only the spec's image, rootfs path and privileged flag are used.
+func (g *Gardener) PrefetchImage(containerSpec garden.ContainerSpec) error {
+	handle := "prefetch-" + g.UidGenerator.Generate()
 
-	log.Info("starting")
-	defer log.Info("completed")
+	log := g.Logger.Session("prefetch-image", lager.Data{"handle": handle, "image": containerSpec.Image.URI, "rootfs": containerSpec.RootFSPath})
 
-	if err := g.BulkStarter.StartAll(); err != nil {
-		return fmt.Errorf("bulk starter: %s", err)
+	log.Info("start")
+	defer log.Info("finished")
+
+	if !g.AllowPrivilgedContainers && containerSpec.Privileged {
+		return errors.New("privileged container creation is disabled")
+	}
+
+	if _, err := g.Volumizer.Create(log, garden.ContainerSpec{
+		Handle:     handle,
+		Image:      containerSpec.Image,
+		RootFSPath: containerSpec.RootFSPath,
+		Privileged: containerSpec.Privileged,
+	}); err != nil {
+		return err
+	}
+
+	// the image's layers stay cached once the volume has gone
+	return g.Volumizer.Destroy(log.Session(VolumizerSession), handle)
+}
+
+// Processes lists the processes running in the container, so that operators
+// can see what is running before stopping or destroying it
+func (g *Gardener) Processes(handle string) ([]ContainerProcess, error) {
+	log := g.Logger.Session("processes", lager.Data{"handle": handle})
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.exists(handles, handle) {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	return g.Containerizer.Processes(log, handle)
+}
+
+// AttachProcess attaches to a process running in the container, so that its
+// output can be streamed to a client other than the one which ran it
+func (g *Gardener) AttachProcess(handle, processID string, processIO garden.ProcessIO) (garden.Process, error) {
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.exists(handles, handle) {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	return g.lookup(handle).Attach(processID, processIO)
+}
+
+// NetworkStats returns the traffic through the network interface of the
+// container, including the packet and drop counts which Metrics leaves out
+func (g *Gardener) NetworkStats(handle string) (ContainerNetworkStats, error) {
+	log := g.Logger.Session("network-stats", lager.Data{"handle": handle})
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return ContainerNetworkStats{}, err
+	}
+
+	if !g.exists(handles, handle) {
+		return ContainerNetworkStats{}, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	statser, ok := g.Networker.(NetworkStatser)
+	if !ok {
+		return ContainerNetworkStats{}, errors.New("the networker does not report network stats")
+	}
+
+	stats, err := statser.Stats(log, handle)
+	if err != nil {
+		return ContainerNetworkStats{}, err
+	}
+
+	stats.Labels, err = g.containerLabels(log, handle)
+	if err != nil {
+		return ContainerNetworkStats{}, err
+	}
+
+	return stats, nil
+}
+
+// LabelledMetrics returns the metrics of the container, tagged with its labels
+func (g *Gardener) LabelledMetrics(handle string) (LabelledMetrics, error) {
+	log := g.Logger.Session("labelled-metrics", lager.Data{"handle": handle})
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return LabelledMetrics{}, err
+	}
+
+	if !g.exists(handles, handle) {
+		return LabelledMetrics{}, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	metrics, err := g.lookup(handle).Metrics()
+	if err != nil {
+		return LabelledMetrics{}, err
+	}
+
+	labels, err := g.containerLabels(log, handle)
+	if err != nil {
+		return LabelledMetrics{}, err
+	}
+
+	labelled := LabelledMetrics{Metrics: metrics, Labels: labels}
+	if g.FDCounter != nil {
+		usage, err := g.FDUsage(handle)
+		if err != nil {
+			return LabelledMetrics{}, err
+		}
+		labelled.FDUsage = &usage
+	}
+
+	return labelled, nil
+}
+
+// NetworkReservations returns every network reservation, by name
+func (g *Gardener) NetworkReservations() (map[string]NetworkReservation, error) {
+	log := g.Logger.Session("network-reservations")
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	reserver, ok := g.Networker.(NetworkReserver)
+	if !ok {
+		return nil, errors.New("the networker does not support network reservations")
+	}
+
+	return reserver.Reservations(log)
+}
+
+// ReleaseNetworkReservation deletes the named network reservation, so that its
+// network can be given to other containers
+func (g *Gardener) ReleaseNetworkReservation(name string) error {
+	log := g.Logger.Session("release-network-reservation", lager.Data{"name": name})
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	reserver, ok := g.Networker.(NetworkReserver)
+	if !ok {
+		return errors.New("the networker does not support network reservations")
+	}
+
+	return reserver.ReleaseReservation(log, name)
+}
+
+var imageNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Commit snapshots the rootfs of a stopped container into a new image with the
+// given name, and returns the rootfs path which later creates can use to start
+// from the snapshot
+func (g *Gardener) Commit(handle, name string) (string, error) {
+	log := g.Logger.Session("commit", lager.Data{"handle": handle, "name": name})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	if !imageNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("invalid image name: '%s'", name)
+	}
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return "", err
+	}
+
+	if !g.exists(handles, handle) {
+		return "", garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	actualSpec, err := g.Containerizer.Info(log, handle)
+	if err != nil {
+		return "", err
+	}
+
+	// processes still writing to the rootfs would leave the snapshot inconsistent
+	if !actualSpec.Stopped {
+		return "", fmt.Errorf("container '%s' must be stopped before it is committed", handle)
+	}
+
+	return g.RootfsCommitter.Commit(log, actualSpec.RootFSPath, name)
+}
+
+// Copy streams a path out of one container and into another. The tar stream
+// is passed straight from one to the other rather than being buffered.
+func (g *Gardener) Copy(spec CopySpec) error {
+	log := g.Logger.Session("copy", lager.Data{"src-handle": spec.SrcHandle, "dst-handle": spec.DstHandle})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return err
+	}
+
+	for _, handle := range []string{spec.SrcHandle, spec.DstHandle} {
+		if !g.exists(handles, handle) {
+			return garden.ContainerNotFoundError{Handle: handle}
+		}
+	}
+
+	stream, err := g.Containerizer.StreamOut(log, spec.SrcHandle, garden.StreamOutSpec{Path: spec.SrcPath, User: spec.User})
+	if err != nil {
+		return fmt.Errorf("streaming out of %s: %s", spec.SrcHandle, err)
+	}
+
+	streamInErr := g.Containerizer.StreamIn(log, spec.DstHandle, garden.StreamInSpec{Path: spec.DstPath, User: spec.User, TarStream: stream})
+
+	// tar may stop reading at the end of the archive, before the padding which
+	// follows it, so the rest is drained so that the source can finish writing
+	var streamOutErr error
+	if streamInErr == nil {
+		_, streamOutErr = io.Copy(ioutil.Discard, stream)
+	}
+
+	// closing the stream reports whether all of the source was streamed out
+	if err := stream.Close(); err != nil && streamOutErr == nil {
+		streamOutErr = err
+	}
+
+	if streamInErr != nil {
+		return fmt.Errorf("streaming into %s: %s", spec.DstHandle, streamInErr)
+	}
+
+	if streamOutErr != nil {
+		return fmt.Errorf("streaming out of %s: %s", spec.SrcHandle, streamOutErr)
+	}
+
+	return nil
+}
+
+// StatFile reports on a path in the filesystem of a running container without
+// streaming it
+func (g *Gardener) StatFile(handle, path string) (ContainerFileInfo, error) {
+	log := g.Logger.Session("stat-file", lager.Data{"handle": handle, "path": path})
+
+	pid, err := g.inspectablePid(log, handle)
+	if err != nil {
+		return ContainerFileInfo{}, err
+	}
+
+	return g.FileInspector.Stat(log, pid, path)
+}
+
+// ListFiles reports on the entries of a directory in the filesystem of a
+// running container without streaming them
+func (g *Gardener) ListFiles(handle, path string) ([]ContainerFileInfo, error) {
+	log := g.Logger.Session("list-files", lager.Data{"handle": handle, "path": path})
+
+	pid, err := g.inspectablePid(log, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.FileInspector.List(log, pid, path)
+}
+
+// inspectablePid returns the pid of the init process of a container whose
+// files can be inspected
+func (g *Gardener) inspectablePid(log lager.Logger, handle string) (int, error) {
+	if g.FileInspector == nil {
+		return 0, errors.New("inspecting container files is not supported")
+	}
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return 0, err
+	}
+
+	if !g.exists(handles, handle) {
+		return 0, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	actualSpec, err := g.Containerizer.Info(log, handle)
+	if err != nil {
+		return 0, err
+	}
+
+	if actualSpec.Stopped || actualSpec.Pid == 0 {
+		return 0, fmt.Errorf("container '%s' is not running", handle)
+	}
+
+	return actualSpec.Pid, nil
+}
+
+// UpdateLimits changes the resource limits of a running container. Unlike the
+// Limit* calls on a container it can also change the pid limit.
+func (g *Gardener) UpdateLimits(handle string, limits garden.Limits) error {
+	log := g.Logger.Session("update-limits", lager.Data{"handle": handle})
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return err
+	}
+
+	if !g.exists(handles, handle) {
+		return garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	updater, ok := g.Containerizer.(LimitUpdater)
+	if !ok {
+		return errors.New("the containerizer cannot update limits")
+	}
+
+	g.reservationMutex.Lock()
+	defer g.reservationMutex.Unlock()
+
+	// the disk quota cannot be changed, so it is not checked again
+	limits.Disk = garden.DiskLimits{}
+	if err := g.checkCommitment(handle, limits); err != nil {
+		return err
+	}
+
+	if err := updater.UpdateLimits(log, handle, limits); err != nil {
+		return err
+	}
+
+	return g.recordResources(handle, func(resources *ContainerResources) {
+		if limits.Memory.LimitInBytes > 0 {
+			resources.MemoryLimit = limits.Memory.LimitInBytes
+		}
+		if limits.CPU.LimitInShares > 0 {
+			resources.CPUShares = limits.CPU.LimitInShares
+		}
+	})
+}
+
+func (g *Gardener) Lookup(handle string) (garden.Container, error) {
+	return g.lookup(handle), nil
+}
+
+func (g *Gardener) lookup(handle string) garden.Container {
+	return &container{
+		logger:          g.Logger,
+		handle:          handle,
+		containerizer:   g.Containerizer,
+		volumizer:       g.Volumizer,
+		networker:       g.Networker,
+		propertyManager: g.PropertyManager,
+		resourceStore:   g.ResourceStore,
+		limitUpdater:    g,
+		maxProcesses:    g.MaxProcessesPerContainer,
+		maxOpenFiles:    g.MaxOpenFilesPerProcess,
+		defaultUser:     g.DefaultProcessUser,
+		coreDumpLimit:   g.coreDumpLimit,
+		recordResources: g.recordResources,
+		startLock:       g.processStartLock,
+	}
+}
+
+func (g *Gardener) Destroy(handle string) error {
+	log := g.Logger.Session("destroy", lager.Data{"handle": handle})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return err
+	}
+
+	if !g.exists(handles, handle) {
+		return garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	// the properties are gone once the container has been destroyed
+	properties, _ := g.PropertyManager.All(handle)
+
+	if err := g.destroyOrDefer(log, handle); err != nil {
+		return err
+	}
+
+	g.forgetProcessStartLock(handle)
+
+	g.LifecycleNotifier.Notify(log, LifecycleEvent{
+		Type:       ContainerDestroyedEvent,
+		Handle:     handle,
+		Properties: properties,
+	})
+
+	return nil
+}
+
+// destroyOrDefer destroys the container, deferring the parts of the destroy
+// which failed when DeferFailedDestroys is set. The container and its
+// processes may still be running when it could not be deleted while its init
+// process was running, so such failures are always returned.
+func (g *Gardener) destroyOrDefer(log lager.Logger, handle string) error {
+	err := g.Containerizer.Destroy(log, handle)
+	if _, exited := err.(ExitedContainerDestroyError); err != nil && !exited {
+		return err
+	}
+
+	if err == nil {
+		err = g.releaseResources(log, handle)
+	}
+
+	if err == nil || !g.DeferFailedDestroys {
+		return err
+	}
+
+	if deferErr := g.deferDestroy(log, handle, err); deferErr != nil {
+		return err
+	}
+
+	return nil
+}
+
+// destroy idempotently destroys any resources associated with the given handle
+func (g *Gardener) destroy(log lager.Logger, handle string) error {
+	if err := g.Containerizer.Destroy(log, handle); err != nil {
+		return err
+	}
+
+	return g.releaseResources(log, handle)
+}
+
+// releaseResources idempotently releases the resources of a container which
+// has been destroyed
+func (g *Gardener) releaseResources(log lager.Logger, handle string) error {
+	resources, err := g.ResourceStore.Get(handle)
+	if err != nil {
+		// the properties are enough to release everything but a lost cgroup
+		log.Error("get-recorded-resources-failed", err)
+	}
+
+	if err := g.releaseNetwork(log, handle, resources); err != nil {
+		return err
+	}
+
+	if err := g.Volumizer.Destroy(log.Session(VolumizerSession), handle); err != nil {
+		return err
+	}
+
+	if g.CoreDumpCollector != nil {
+		if err := g.CoreDumpCollector.Remove(log, handle); err != nil {
+			return err
+		}
+	}
+
+	if err := g.PropertyManager.DestroyKeySpace(handle); err != nil {
+		return err
+	}
+	g.forgetLabels(handle)
+	g.forgetProcessStartLock(handle)
+
+	if err := g.Containerizer.RemoveBundle(log, handle); err != nil {
+		return err
+	}
+
+	if g.CgroupRemover != nil && resources.CgroupPath != "" {
+		if err := g.CgroupRemover.RemoveCgroup(log, resources.CgroupPath); err != nil {
+			return err
+		}
+	}
+
+	// the record is kept until everything else is gone so that a failed
+	// destroy can be retried by the orphan collector
+	if err := g.removeResources(handle); err != nil {
+		return err
+	}
+
+	g.runPostDestroyHooks(log, handle)
+	return nil
+}
+
+// releaseNetwork releases the container's network. The networker finds the
+// network from the container's properties, so if they have been lost the
+// network recorded for the container is released instead.
+func (g *Gardener) releaseNetwork(log lager.Logger, handle string, resources ContainerResources) error {
+	properties, _ := g.PropertyManager.All(handle)
+	releaser, ok := g.Networker.(RecordedNetworkReleaser)
+
+	if len(properties) == 0 && ok && hasRecordedNetwork(resources) {
+		log.Info("properties-lost-releasing-recorded-network")
+		if err := releaser.ReleaseRecorded(log, handle, resources); err != nil {
+			return err
+		}
+	} else if err := g.Networker.Destroy(log, handle); err != nil {
+		return err
+	}
+
+	if !hasRecordedNetwork(resources) {
+		return nil
+	}
+
+	// a retried destroy must not release the network again, once the
+	// properties are gone, as it may since have been given to another container
+	return g.recordResources(handle, func(r *ContainerResources) {
+		r.ContainerIP = ""
+		r.Subnet = ""
+		r.BridgeIP = ""
+		r.HostPorts = nil
+	})
+}
+
+func hasRecordedNetwork(resources ContainerResources) bool {
+	return resources.ContainerIP != "" || len(resources.HostPorts) > 0
+}
+
+// deferDestroy queues a container whose destroy failed to be cleaned up in the
+// background
+func (g *Gardener) deferDestroy(log lager.Logger, handle string, destroyErr error) error {
+	log.Error("destroy-failed-deferring-cleanup", destroyErr)
+
+	if err := g.recordResources(handle, func(r *ContainerResources) {
+		r.DestroyPending = true
+	}); err != nil {
+		log.Error("deferring-cleanup-failed", err)
+		return err
+	}
+
+	// hide the container from listings while it waits to be cleaned up
+	g.PropertyManager.Set(handle, "garden.state", "destroying")
+
+	return nil
+}
+
+// RetryDestroy finishes destroying a container whose destroy was deferred
+func (g *Gardener) RetryDestroy(log lager.Logger, handle string) error {
+	return g.destroy(log, handle)
+}
+
+// Orphans returns the handles which have recorded resources but no container,
+// excluding containers which are still being created or are queued for
+// deferred cleanup
+func (g *Gardener) Orphans(log lager.Logger, knownHandles []string) ([]string, error) {
+	recorded, err := g.ResourceStore.Handles()
+	if err != nil {
+		return nil, fmt.Errorf("listing recorded resources: %s", err)
+	}
+
+	var orphans []string
+	for _, handle := range recorded {
+		if g.exists(knownHandles, handle) || g.isCreating(handle) {
+			continue
+		}
+
+		if resources, err := g.ResourceStore.Get(handle); err == nil && resources.DestroyPending {
+			continue
+		}
+
+		orphans = append(orphans, handle)
+	}
+
+	return orphans, nil
+}
+
+// CleanOrphan releases every resource recorded for the handle
+func (g *Gardener) CleanOrphan(log lager.Logger, handle string) error {
+	return g.destroy(log, handle)
+}
+
+// RecordFailedDestroy counts a failed retry of a deferred destroy, returning
+// the number of attempts made so far
+func (g *Gardener) RecordFailedDestroy(handle string) (int, error) {
+	var attempts int
+	err := g.recordResources(handle, func(r *ContainerResources) {
+		r.DestroyAttempts++
+		attempts = r.DestroyAttempts
+	})
+
+	return attempts, err
+}
+
+// recordResources applies the update to the resources recorded for the
+// handle. Every change to an existing record goes through it, holding the
+// handle's lock, so that concurrent updates cannot lose each other's changes.
+func (g *Gardener) recordResources(handle string, update func(*ContainerResources)) error {
+	lock := g.resourceLock(handle)
+	lock.Lock()
+	defer lock.Unlock()
+
+	resources, err := g.ResourceStore.Get(handle)
+	if err != nil {
+		return fmt.Errorf("recording resources: %s", err)
+	}
+
+	update(&resources)
+
+	if err := g.ResourceStore.Set(handle, resources); err != nil {
+		return fmt.Errorf("recording resources: %s", err)
+	}
+
+	return nil
+}
+
+// reserveResources records the container's limits, first checking that they
+// would not commit more of the host to containers than is allowed
+func (g *Gardener) reserveResources(handle string, limits garden.Limits) error {
+	g.reservationMutex.Lock()
+	defer g.reservationMutex.Unlock()
+
+	if err := g.checkCommitment(handle, limits); err != nil {
+		return err
+	}
+
+	resources := ContainerResources{
+		DiskQuota:   limits.Disk.ByteHard,
+		MemoryLimit: limits.Memory.LimitInBytes,
+		CPUShares:   limits.CPU.LimitInShares,
+	}
+
+	lock := g.resourceLock(handle)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := g.ResourceStore.Set(handle, resources); err != nil {
+		return fmt.Errorf("recording resources: %s", err)
+	}
+
+	return nil
+}
+
+// checkCommitment checks the non-zero limits against the sum of the limits
+// of every container other than handle
+func (g *Gardener) checkCommitment(handle string, limits garden.Limits) error {
+	diskQuota := limits.Disk.ByteHard
+	memoryLimit := limits.Memory.LimitInBytes
+	cpuShares := limits.CPU.LimitInShares
+
+	checkDisk := diskQuota > 0 && (g.MaxDiskQuotaPercent > 0 || g.ReservedDiskPercent > 0)
+	checkMemory := memoryLimit > 0 && g.ReservedMemoryPercent > 0
+	checkCPU := cpuShares > 0 && g.ReservedCPUPercent > 0
+	if !checkDisk && !checkMemory && !checkCPU {
+		return nil
+	}
+
+	committed, err := g.committedResources(handle)
+	if err != nil {
+		return fmt.Errorf("checking resource limits: %s", err)
+	}
+
+	if checkDisk {
+		totalDisk, err := g.SysInfoProvider.TotalDisk()
+		if err != nil {
+			return fmt.Errorf("checking disk quota: %s", err)
+		}
+
+		maxCommitted := unreserved(totalDisk, g.ReservedDiskPercent)
+		if g.MaxDiskQuotaPercent > 0 && totalDisk/100*g.MaxDiskQuotaPercent < maxCommitted {
+			maxCommitted = totalDisk / 100 * g.MaxDiskQuotaPercent
+		}
+
+		if total := committed.DiskQuota + diskQuota; total > maxCommitted {
+			return fmt.Errorf("insufficient disk: a disk quota of %d bytes would commit %d of the %d bytes allowed", diskQuota, total, maxCommitted)
+		}
+	}
+
+	if checkMemory {
+		totalMemory, err := g.SysInfoProvider.TotalMemory()
+		if err != nil {
+			return fmt.Errorf("checking memory limit: %s", err)
+		}
+
+		maxCommitted := unreserved(totalMemory, g.ReservedMemoryPercent)
+		if total := committed.MemoryLimit + memoryLimit; total > maxCommitted {
+			return fmt.Errorf("insufficient memory: a memory limit of %d bytes would commit %d of the %d bytes allowed", memoryLimit, total, maxCommitted)
+		}
+	}
+
+	if checkCPU {
+		totalCPUs, err := g.SysInfoProvider.TotalCPUs()
+		if err != nil {
+			return fmt.Errorf("checking cpu limit: %s", err)
+		}
+
+		maxCommitted := unreserved(totalCPUs*SharesPerCPU, g.ReservedCPUPercent)
+		if total := committed.CPUShares + cpuShares; total > maxCommitted {
+			return fmt.Errorf("insufficient cpu: %d cpu shares would commit %d of the %d shares allowed", cpuShares, total, maxCommitted)
+		}
+	}
+
+	return nil
+}
+
+// committedResources sums the recorded limits of every container other than
+// the one with the given handle
+func (g *Gardener) committedResources(excludedHandle string) (ContainerResources, error) {
+	handles, err := g.ResourceStore.Handles()
+	if err != nil {
+		return ContainerResources{}, err
+	}
+
+	var committed ContainerResources
+	for _, handle := range handles {
+		if handle == excludedHandle {
+			continue
+		}
+
+		resources, err := g.ResourceStore.Get(handle)
+		if err != nil {
+			return ContainerResources{}, err
+		}
+
+		committed.DiskQuota += resources.DiskQuota
+		committed.MemoryLimit += resources.MemoryLimit
+		committed.CPUShares += resources.CPUShares
+	}
+
+	return committed, nil
+}
+
+// unreserved returns what is left of the total once reservedPercent of it is
+// set aside. The percentage is validated to be at most 100 at startup.
+func unreserved(total, reservedPercent uint64) uint64 {
+	return total - total*reservedPercent/100
+}
+
+// processStartLock returns the lock which is held while a process is started
+// in the container, so that concurrent runs cannot exceed the process limit.
+// It is only created when a process is run while the limit is set, and is
+// forgotten when the container is destroyed.
+func (g *Gardener) processStartLock(handle string) *sync.Mutex {
+	g.processStartsMutex.Lock()
+	defer g.processStartsMutex.Unlock()
+
+	if g.processStarts == nil {
+		g.processStarts = map[string]*sync.Mutex{}
+	}
+
+	lock, ok := g.processStarts[handle]
+	if !ok {
+		lock = new(sync.Mutex)
+		g.processStarts[handle] = lock
+	}
+
+	return lock
+}
+
+func (g *Gardener) forgetProcessStartLock(handle string) {
+	g.processStartsMutex.Lock()
+	defer g.processStartsMutex.Unlock()
+
+	delete(g.processStarts, handle)
+}
+
+// resourceLock returns the lock which is held while the resources recorded
+// for the handle are changed
+func (g *Gardener) resourceLock(handle string) *sync.Mutex {
+	g.resourceLocksMutex.Lock()
+	defer g.resourceLocksMutex.Unlock()
+
+	if g.resourceLocks == nil {
+		g.resourceLocks = map[string]*sync.Mutex{}
+	}
+
+	lock, ok := g.resourceLocks[handle]
+	if !ok {
+		lock = new(sync.Mutex)
+		g.resourceLocks[handle] = lock
+	}
+
+	return lock
+}
+
+// removeResources removes the record of the handle's resources, and its lock
+func (g *Gardener) removeResources(handle string) error {
+	lock := g.resourceLock(handle)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := g.ResourceStore.Remove(handle); err != nil {
+		return err
+	}
+
+	g.resourceLocksMutex.Lock()
+	defer g.resourceLocksMutex.Unlock()
+
+	delete(g.resourceLocks, handle)
+	return nil
+}
+
+func (g *Gardener) startCreating(handle string) {
+	g.creatingMutex.Lock()
+	defer g.creatingMutex.Unlock()
+
+	if g.creating == nil {
+		g.creating = map[string]*CreateProgress{}
+	}
+
+	now := g.now()
+	g.creating[handle] = &CreateProgress{Handle: handle, Phase: CreateStartedPhase, StartedAt: now, PhaseReachedAt: now}
+}
+
+func (g *Gardener) now() time.Time {
+	if g.Clock == nil {
+		return time.Now()
+	}
+
+	return g.Clock.Now()
+}
+
+func (g *Gardener) finishCreating(handle string) {
+	g.creatingMutex.Lock()
+	defer g.creatingMutex.Unlock()
+
+	delete(g.creating, handle)
+}
+
+func (g *Gardener) isCreating(handle string) bool {
+	g.creatingMutex.Lock()
+	defer g.creatingMutex.Unlock()
+
+	_, ok := g.creating[handle]
+	return ok
+}
+
+func mappedHostPorts(propertyManager PropertyManager, handle string) []uint32 {
+	mappedPortsCfg, ok := propertyManager.Get(handle, MappedPortsKey)
+	if !ok {
+		return nil
+	}
+
+	var mappedPorts []garden.PortMapping
+	if err := json.Unmarshal([]byte(mappedPortsCfg), &mappedPorts); err != nil {
+		return nil
+	}
+
+	var hostPorts []uint32
+	for _, mapping := range mappedPorts {
+		hostPorts = append(hostPorts, mapping.HostPort)
+	}
+
+	return hostPorts
+}
+
+func (g *Gardener) Stop() {}
+
+// GraceTime returns the container's grace time. It can be overridden per
+// container by setting the GraceTimeKey property, either to a number of
+// nanoseconds (as SetGraceTime does) or to a duration such as "10m". If the
+// property is missing or invalid the DefaultGraceTime is used.
+func (g *Gardener) GraceTime(container garden.Container) time.Duration {
+	property, ok := g.PropertyManager.Get(container.Handle(), GraceTimeKey)
+	if !ok {
+		return g.DefaultGraceTime
+	}
+
+	if nanos, err := strconv.ParseInt(property, 10, 64); err == nil {
+		return time.Duration(nanos)
+	}
+
+	graceTime, err := time.ParseDuration(property)
+	if err != nil {
+		g.Logger.Info("invalid-grace-time", lager.Data{"handle": container.Handle(), "grace-time": property})
+		return g.DefaultGraceTime
+	}
+
+	return graceTime
+}
+
+func (g *Gardener) Ping() error { return nil }
+
+func (g *Gardener) Capacity() (garden.Capacity, error) {
+	mem, err := g.SysInfoProvider.TotalMemory()
+	if err != nil {
+		return garden.Capacity{}, err
+	}
+
+	disk, err := g.SysInfoProvider.TotalDisk()
+	if err != nil {
+		return garden.Capacity{}, err
+	}
+
+	cap := g.Networker.Capacity()
+	if g.MaxContainers > 0 && g.MaxContainers < cap {
+		cap = g.MaxContainers
+	}
+
+	return garden.Capacity{
+		MemoryInBytes: unreserved(mem, g.ReservedMemoryPercent),
+		DiskInBytes:   unreserved(disk, g.ReservedDiskPercent),
+		MaxContainers: cap,
+	}, nil
+}
+
+// Containers lists the created containers which have all of the given
+// properties. Keys and values may contain '*' wildcards, e.g. {"tag:org": "*"}
+// selects every container with a tag:org property.
+func (g *Gardener) Containers(props garden.Properties) ([]garden.Container, error) {
+	log := g.Logger.Session("list-containers")
+
+	log.Info("starting")
+	defer log.Info("finished")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		log.Error("handles-failed", err)
+		return []garden.Container{}, err
+	}
+
+	if props == nil {
+		props = garden.Properties{}
+	}
+	props["garden.state"] = "created"
+
+	propertyFilter, labelFilter := splitLabels(props)
+
+	var containers []garden.Container
+	for _, handle := range handles {
+		if !g.PropertyManager.MatchesAll(handle, propertyFilter) {
+			continue
+		}
+
+		if len(labelFilter) > 0 {
+			labels, err := g.containerLabels(log, handle)
+			if err != nil {
+				log.Error("get-labels-failed", err, lager.Data{"handle": handle})
+				continue
+			}
+
+			if !matchesLabels(labels, labelFilter) {
+				continue
+			}
+		}
+
+		containers = append(containers, g.lookup(handle))
+	}
+
+	return containers, nil
+}
+
+func (g *Gardener) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+	result := make(map[string]garden.ContainerInfoEntry)
+	for _, handle := range handles {
+		container := g.lookup(handle)
+
+		var infoErr *garden.Error = nil
+		info, err := container.Info()
+		if err != nil {
+			infoErr = garden.NewError(err.Error())
+		}
+		result[handle] = garden.ContainerInfoEntry{
+			Info: info,
+			Err:  infoErr,
+		}
+	}
+
+	return result, nil
+}
+
+func (g *Gardener) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
+	result := make(map[string]garden.ContainerMetricsEntry)
+	for _, handle := range handles {
+		var e *garden.Error
+		m, err := g.lookup(handle).Metrics()
+		if err != nil {
+			e = garden.NewError(err.Error())
+		}
+
+		result[handle] = garden.ContainerMetricsEntry{
+			Err:     e,
+			Metrics: m,
+		}
+	}
+
+	return result, nil
+}
+
+func (g *Gardener) checkDuplicateHandle(knownHandles []string, handle string) error {
+	if g.exists(knownHandles, handle) {
+		return fmt.Errorf("Handle '%s' already in use", handle)
+	}
+
+	return nil
+}
+
+func (g *Gardener) exists(handles []string, handle string) bool {
+	for _, h := range handles {
+		if h == handle {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Gardener) checkMaxContainers(handles []string) error {
+	if g.MaxContainers == 0 {
+		return nil
+	}
+
+	if len(handles) >= int(g.MaxContainers) {
+		return errors.New("max containers reached")
+	}
+
+	return nil
+}
+
+func (g *Gardener) Start() error {
+	log := g.Logger.Session("start")
+
+	log.Info("starting")
+	defer log.Info("completed")
+
+	if err := g.BulkStarter.StartAll(); err != nil {
+		return fmt.Errorf("bulk starter: %s", err)
 	}
 
 	if err := g.PeaCleaner.CleanAll(log); err != nil {
@@ -474,10 +1978,46 @@ func (g *Gardener) Start() error {
 		return err
 	}
 
+	// the NoopRestorer fails every container in order to destroy them all,
+	// so there is nothing wrong with them worth keeping
+	_, destroyingAll := g.Restorer.(*NoopRestorer)
+
+	failed := map[string]bool{}
 	for _, handle := range g.Restorer.Restore(log, handles) {
+		failed[handle] = true
+
 		destroyLog := log.Session("clean-up-container", lager.Data{"handle": handle})
 		destroyLog.Info("start")
 
+		if !destroyingAll {
+			g.quarantine(destroyLog, handle, "restore failed")
+		}
+
+		if err := g.destroy(destroyLog, handle); err != nil {
+			destroyLog.Error("failed", err)
+			continue
+		}
+
+		destroyLog.Info("cleaned-up")
+	}
+
+	if watchRestorer, ok := g.Containerizer.(WatchRestorer); ok {
+		for _, handle := range handles {
+			if !failed[handle] {
+				watchRestorer.RestoreWatch(log, handle)
+			}
+		}
+	}
+
+	orphans, err := g.Orphans(log, handles)
+	if err != nil {
+		return err
+	}
+
+	for _, handle := range orphans {
+		destroyLog := log.Session("clean-up-orphan", lager.Data{"handle": handle})
+		destroyLog.Info("start")
+
 		if err := g.destroy(destroyLog, handle); err != nil {
 			destroyLog.Error("failed", err)
 			continue
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
 	"code.cloudfoundry.org/guardian/gardener"
 	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
 	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
@@ -16,6 +18,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock/fakeclock"
 )
 
 var _ = Describe("Gardener", func() {
@@ -29,6 +32,8 @@ var _ = Describe("Gardener", func() {
 		sysinfoProvider *fakes.FakeSysInfoProvider
 		propertyManager *fakes.FakePropertyManager
 		restorer        *fakes.FakeRestorer
+		resourceStore   *fakes.FakeResourceStore
+		lifecycle       *fakes.FakeLifecycleNotifier
 
 		logger lager.Logger
 
@@ -46,6 +51,8 @@ var _ = Describe("Gardener", func() {
 		sysinfoProvider = new(fakes.FakeSysInfoProvider)
 		propertyManager = new(fakes.FakePropertyManager)
 		restorer = new(fakes.FakeRestorer)
+		resourceStore = new(fakes.FakeResourceStore)
+		lifecycle = new(fakes.FakeLifecycleNotifier)
 
 		propertyManager.GetReturns("", true)
 		containerizer.HandlesReturns([]string{"some-handle"}, nil)
@@ -64,6 +71,8 @@ var _ = Describe("Gardener", func() {
 			PeaCleaner:               fakePeaCleaner,
 			MaxContainers:            0,
 			AllowPrivilgedContainers: false,
+			ResourceStore:            resourceStore,
+			LifecycleNotifier:        lifecycle,
 		}
 	})
 
@@ -152,6 +161,298 @@ var _ = Describe("Gardener", func() {
 			Expect(actualContainerSpec).To(Equal(spec))
 		})
 
+		Describe("recording the container's resources", func() {
+			var recorded map[string]gardener.ContainerResources
+
+			BeforeEach(func() {
+				recorded = map[string]gardener.ContainerResources{}
+				resourceStore.SetStub = func(handle string, resources gardener.ContainerResources) error {
+					recorded[handle] = resources
+					return nil
+				}
+				resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+					return recorded[handle], nil
+				}
+
+				volumizer.CreateReturns(specs.Spec{Root: &specs.Root{Path: "/path/to/rootfs"}}, nil)
+				containerizer.InfoReturns(spec.ActualContainerSpec{Pid: 470, CgroupPath: "garden/some-ctr"}, nil)
+				propertyManager.GetStub = func(handle, name string) (string, bool) {
+					switch name {
+					case gardener.ContainerIPKey:
+						return "10.0.0.2", true
+					case gardener.BridgeIPKey:
+						return "10.0.0.1", true
+					case gardener.ContainerSubnetKey:
+						return "10.0.0.0/30", true
+					case gardener.MappedPortsKey:
+						return `[{"HostPort":61001,"ContainerPort":8080}]`, true
+					}
+					return "", false
+				}
+			})
+
+			It("records the volume, cgroup and network of the container", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(recorded).To(HaveKeyWithValue("some-ctr", gardener.ContainerResources{
+					VolumePath:  "/path/to/rootfs",
+					CgroupPath:  "garden/some-ctr",
+					ContainerIP: "10.0.0.2",
+					Subnet:      "10.0.0.0/30",
+					BridgeIP:    "10.0.0.1",
+					HostPorts:   []uint32{61001},
+				}))
+			})
+
+			It("records the handle before creating any resources", func() {
+				volumizer.CreateStub = func(lager.Logger, garden.ContainerSpec) (specs.Spec, error) {
+					Expect(recorded).To(HaveKey("some-ctr"))
+					return specs.Spec{}, nil
+				}
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not report the container as an orphan while it is being created", func() {
+				resourceStore.HandlesReturns([]string{"some-ctr"}, nil)
+				networker.NetworkStub = func(lager.Logger, garden.ContainerSpec, int) error {
+					Expect(gdnr.Orphans(logger, []string{})).To(BeEmpty())
+					return nil
+				}
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gdnr.Orphans(logger, []string{})).To(ConsistOf("some-ctr"))
+			})
+
+			Context("when recording the resources fails", func() {
+				BeforeEach(func() {
+					resourceStore.SetReturns(errors.New("disk full"))
+					resourceStore.SetStub = nil
+				})
+
+				It("returns the error without creating anything", func() {
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+					Expect(err).To(MatchError(ContainSubstring("disk full")))
+
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Describe("limiting the total disk quota", func() {
+			BeforeEach(func() {
+				gdnr.MaxDiskQuotaPercent = 50
+				sysinfoProvider.TotalDiskReturns(1000, nil)
+
+				resourceStore.HandlesReturns([]string{"ctr-1", "ctr-2"}, nil)
+				resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+					return gardener.ContainerResources{DiskQuota: 200}, nil
+				}
+			})
+
+			diskLimit := func(bytes uint64) garden.ContainerSpec {
+				return garden.ContainerSpec{
+					Handle: "some-ctr",
+					Limits: garden.Limits{Disk: garden.DiskLimits{ByteHard: bytes}},
+				}
+			}
+
+			It("records the container's disk quota", func() {
+				_, err := gdnr.Create(diskLimit(100))
+				Expect(err).NotTo(HaveOccurred())
+
+				handle, resources := resourceStore.SetArgsForCall(0)
+				Expect(handle).To(Equal("some-ctr"))
+				Expect(resources.DiskQuota).To(BeEquivalentTo(100))
+			})
+
+			Context("when the quota would take the total over the maximum", func() {
+				It("rejects the create before creating anything", func() {
+					_, err := gdnr.Create(diskLimit(101))
+					Expect(err).To(MatchError(ContainSubstring("insufficient disk")))
+
+					Expect(resourceStore.SetCallCount()).To(Equal(0))
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the container has no disk quota", func() {
+				It("is not counted", func() {
+					_, err := gdnr.Create(diskLimit(0))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(sysinfoProvider.TotalDiskCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when there is no maximum", func() {
+				BeforeEach(func() {
+					gdnr.MaxDiskQuotaPercent = 0
+				})
+
+				It("allows any quota", func() {
+					_, err := gdnr.Create(diskLimit(100000))
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when the total disk cannot be determined", func() {
+				BeforeEach(func() {
+					sysinfoProvider.TotalDiskReturns(0, errors.New("no disk"))
+				})
+
+				It("returns the error", func() {
+					_, err := gdnr.Create(diskLimit(100))
+					Expect(err).To(MatchError(ContainSubstring("no disk")))
+				})
+			})
+		})
+
+		Describe("reserving host headroom", func() {
+			BeforeEach(func() {
+				gdnr.ReservedMemoryPercent = 10
+				gdnr.ReservedDiskPercent = 20
+				gdnr.ReservedCPUPercent = 25
+				sysinfoProvider.TotalMemoryReturns(1000, nil)
+				sysinfoProvider.TotalDiskReturns(1000, nil)
+				sysinfoProvider.TotalCPUsReturns(2, nil)
+
+				resourceStore.HandlesReturns([]string{"ctr-1", "ctr-2"}, nil)
+				resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+					return gardener.ContainerResources{MemoryLimit: 400, DiskQuota: 300, CPUShares: 512}, nil
+				}
+			})
+
+			withLimits := func(limits garden.Limits) garden.ContainerSpec {
+				return garden.ContainerSpec{Handle: "some-ctr", Limits: limits}
+			}
+
+			It("records the container's limits", func() {
+				_, err := gdnr.Create(withLimits(garden.Limits{
+					Memory: garden.MemoryLimits{LimitInBytes: 100},
+					Disk:   garden.DiskLimits{ByteHard: 200},
+					CPU:    garden.CPULimits{LimitInShares: 512},
+				}))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, resources := resourceStore.SetArgsForCall(0)
+				Expect(resources.MemoryLimit).To(BeEquivalentTo(100))
+				Expect(resources.DiskQuota).To(BeEquivalentTo(200))
+				Expect(resources.CPUShares).To(BeEquivalentTo(512))
+			})
+
+			It("rejects a memory limit which would commit the reserved memory", func() {
+				_, err := gdnr.Create(withLimits(garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 101}}))
+				Expect(err).To(MatchError("insufficient memory: a memory limit of 101 bytes would commit 901 of the 900 bytes allowed"))
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+
+			It("rejects a disk quota which would commit the reserved disk", func() {
+				_, err := gdnr.Create(withLimits(garden.Limits{Disk: garden.DiskLimits{ByteHard: 201}}))
+				Expect(err).To(MatchError(ContainSubstring("insufficient disk")))
+			})
+
+			It("rejects cpu shares which would commit the reserved cpus", func() {
+				_, err := gdnr.Create(withLimits(garden.Limits{CPU: garden.CPULimits{LimitInShares: 513}}))
+				Expect(err).To(MatchError("insufficient cpu: 513 cpu shares would commit 1537 of the 1536 shares allowed"))
+			})
+
+			Context("when the maximum disk quota percentage is lower than the unreserved disk", func() {
+				BeforeEach(func() {
+					gdnr.MaxDiskQuotaPercent = 70
+				})
+
+				It("applies the lower of the two", func() {
+					_, err := gdnr.Create(withLimits(garden.Limits{Disk: garden.DiskLimits{ByteHard: 101}}))
+					Expect(err).To(MatchError(ContainSubstring("of the 700 bytes allowed")))
+				})
+			})
+
+			Context("when nothing is reserved", func() {
+				BeforeEach(func() {
+					gdnr.ReservedMemoryPercent = 0
+					gdnr.ReservedDiskPercent = 0
+					gdnr.ReservedCPUPercent = 0
+				})
+
+				It("allows any limits", func() {
+					_, err := gdnr.Create(withLimits(garden.Limits{
+						Memory: garden.MemoryLimits{LimitInBytes: 100000},
+						Disk:   garden.DiskLimits{ByteHard: 100000},
+						CPU:    garden.CPULimits{LimitInShares: 100000},
+					}))
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		It("notifies that the container was created", func() {
+			propertyManager.AllReturns(garden.Properties{"foo": "bar"}, nil)
+
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lifecycle.NotifyCallCount()).To(Equal(1))
+			_, event := lifecycle.NotifyArgsForCall(0)
+			Expect(event).To(Equal(gardener.LifecycleEvent{
+				Type:       gardener.ContainerCreatedEvent,
+				Handle:     "some-ctr",
+				Properties: garden.Properties{"foo": "bar"},
+			}))
+		})
+
+		It("records the labels given as properties in the bundle rather than as properties", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{
+				Handle:     "some-ctr",
+				Properties: garden.Properties{"garden.label.billing-id": "1234"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, desiredSpec := containerizer.CreateArgsForCall(0)
+			Expect(desiredSpec.Labels).To(Equal(map[string]string{"billing-id": "1234"}))
+
+			for i := 0; i < propertyManager.SetCallCount(); i++ {
+				_, name, _ := propertyManager.SetArgsForCall(i)
+				Expect(name).NotTo(HavePrefix(gardener.LabelPrefix))
+			}
+		})
+
+		Context("when the container cannot be created", func() {
+			BeforeEach(func() {
+				containerizer.CreateReturns(errors.New("boom"))
+			})
+
+			It("does not notify", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "some-ctr"})
+				Expect(err).To(HaveOccurred())
+
+				Expect(lifecycle.NotifyCallCount()).To(Equal(0))
+			})
+		})
+
+		It("passes the properties and image URI to the containerizer", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{
+				Handle:     "annotated-handle",
+				Image:      garden.ImageRef{URI: "docker:///busybox"},
+				Properties: garden.Properties{"app-guid": "some-app"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, desiredSpec := containerizer.CreateArgsForCall(0)
+			Expect(desiredSpec.ImageURI).To(Equal("docker:///busybox"))
+			Expect(desiredSpec.Properties).To(HaveKeyWithValue("app-guid", "some-app"))
+		})
+
+		It("uses the rootfs path as the image URI when no image is given", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "annotated-handle", RootFSPath: "/some/rootfs"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, desiredSpec := containerizer.CreateArgsForCall(0)
+			Expect(desiredSpec.ImageURI).To(Equal("/some/rootfs"))
+		})
+
 		It("calls the containerizer with an unprivileged DesiredContainerSpec", func() {
 			_, err := gdnr.Create(garden.ContainerSpec{})
 			Expect(err).NotTo(HaveOccurred())
@@ -230,6 +531,46 @@ var _ = Describe("Gardener", func() {
 				Expect(err).To(HaveOccurred())
 				Eventually(logger).Should(gbytes.Say("failed to create the banana"))
 			})
+
+			Context("and a create failure diagnoser is configured", func() {
+				var diagnoser *gardenerfakes.FakeCreateFailureDiagnoser
+
+				BeforeEach(func() {
+					diagnoser = new(gardenerfakes.FakeCreateFailureDiagnoser)
+					gdnr.CreateFailureDiagnoser = diagnoser
+				})
+
+				It("asks the diagnoser to explain the failure", func() {
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob", Privileged: true})
+					Expect(err).To(HaveOccurred())
+
+					Expect(diagnoser.DiagnoseCallCount()).To(Equal(1))
+					spec, createErr := diagnoser.DiagnoseArgsForCall(0)
+					Expect(spec.Handle).To(Equal("bob"))
+					Expect(spec.Privileged).To(BeTrue())
+					Expect(createErr).To(MatchError("failed to create the banana"))
+				})
+
+				Context("when the diagnoser explains the failure", func() {
+					BeforeEach(func() {
+						diagnoser.DiagnoseReturns(errors.New("user namespaces are disabled"))
+					})
+
+					It("returns the diagnosed error", func() {
+						_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+						Expect(err).To(MatchError("user namespaces are disabled"))
+					})
+
+					ItDestroysEverything()
+				})
+
+				Context("when the diagnoser cannot explain the failure", func() {
+					It("returns the original error", func() {
+						_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+						Expect(err).To(MatchError("failed to create the banana"))
+					})
+				})
+			})
 		})
 
 		Describe("ContainerSpec limits", func() {
@@ -316,6 +657,34 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		Context("when no grace time is specified", func() {
+			It("does not set a grace time", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "something"})
+				Expect(err).NotTo(HaveOccurred())
+
+				for i := 0; i < propertyManager.SetCallCount(); i++ {
+					_, name, _ := propertyManager.SetArgsForCall(i)
+					Expect(name).NotTo(Equal(gardener.GraceTimeKey))
+				}
+			})
+
+			Context("and there is a default grace time", func() {
+				BeforeEach(func() {
+					gdnr.DefaultGraceTime = time.Hour
+				})
+
+				It("sets the default grace time via the property manager", func() {
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "something"})
+					Expect(err).NotTo(HaveOccurred())
+
+					handle, name, value := propertyManager.SetArgsForCall(0)
+					Expect(handle).To(Equal("something"))
+					Expect(name).To(Equal(gardener.GraceTimeKey))
+					Expect(value).To(Equal(fmt.Sprintf("%d", time.Hour)))
+				})
+			})
+		})
+
 		It("passes base config to containerizer", func() {
 			runtimeConfig := specs.Spec{Version: "some-idiosyncratic-version"}
 			volumizer.CreateReturns(runtimeConfig, nil)
@@ -477,324 +846,2196 @@ var _ = Describe("Gardener", func() {
 				Expect(volumizer.CreateCallCount()).To(Equal(0))
 			})
 		})
-	})
-
-	Context("when having a container", func() {
-		var container garden.Container
 
-		BeforeEach(func() {
-			var err error
-			container, err = gdnr.Lookup("banana")
-			Expect(err).NotTo(HaveOccurred())
-		})
+		Describe("security presets", func() {
+			createWithPreset := func(preset string) (spec.DesiredContainerSpec, error) {
+				properties := garden.Properties{}
+				if preset != "" {
+					properties[gardener.SecurityPresetKey] = preset
+				}
 
-		Describe("running a process in a container", func() {
-			It("asks the containerizer to run the process", func() {
-				origSpec := garden.ProcessSpec{Path: "ripe"}
-				origIO := garden.ProcessIO{
-					Stdout: gbytes.NewBuffer(),
+				if _, err := gdnr.Create(garden.ContainerSpec{Properties: properties}); err != nil {
+					return spec.DesiredContainerSpec{}, err
 				}
-				_, err := container.Run(origSpec, origIO)
-				Expect(err).ToNot(HaveOccurred())
 
-				Expect(containerizer.RunCallCount()).To(Equal(1))
-				_, id, spec, io := containerizer.RunArgsForCall(0)
-				Expect(id).To(Equal("banana"))
-				Expect(spec).To(Equal(origSpec))
-				Expect(io).To(Equal(origIO))
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				return desiredSpec, nil
+			}
+
+			It("passes the preset requested by the garden.security-preset property to the containerizer", func() {
+				desiredSpec, err := createWithPreset("restricted")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(desiredSpec.SecurityPreset).To(Equal("restricted"))
 			})
 
-			Context("when the containerizer fails to run a process", func() {
-				BeforeEach(func() {
-					containerizer.RunReturns(nil, errors.New("lost my banana"))
-				})
+			It("leaves the preset empty when none is requested", func() {
+				desiredSpec, err := createWithPreset("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(desiredSpec.SecurityPreset).To(BeEmpty())
+			})
 
-				It("returns the error", func() {
-					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
-					Expect(err).To(MatchError("lost my banana"))
+			Context("when the requested preset is unknown", func() {
+				It("returns an error without provisioning a volume", func() {
+					_, err := createWithPreset("anything-goes")
+					Expect(err).To(MatchError("unknown security preset: anything-goes"))
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
 				})
 			})
-		})
 
-		Describe("attaching to an existing process in a container", func() {
-			It("asks the containerizer to attach to the process", func() {
-				origIO := garden.ProcessIO{
-					Stdout: gbytes.NewBuffer(),
-				}
-				_, err := container.Attach("123", origIO)
-				Expect(err).ToNot(HaveOccurred())
+			Context("when no maximum preset is configured", func() {
+				It("allows presets up to the baseline", func() {
+					desiredSpec, err := createWithPreset("baseline")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(desiredSpec.SecurityPreset).To(Equal("baseline"))
+				})
 
-				Expect(containerizer.AttachCallCount()).To(Equal(1))
-				_, id, processId, io := containerizer.AttachArgsForCall(0)
-				Expect(id).To(Equal("banana"))
-				Expect(processId).To(Equal("123"))
-				Expect(io).To(Equal(origIO))
+				It("rejects presets less restrictive than the baseline", func() {
+					_, err := createWithPreset("privileged-compat")
+					Expect(err).To(MatchError("security preset 'privileged-compat' exceeds the maximum allowed preset 'baseline'"))
+				})
 			})
 
-			Context("when the containerizer fails to attach to a process", func() {
+			Context("when the operator allows the privileged-compat preset", func() {
 				BeforeEach(func() {
-					containerizer.AttachReturns(nil, errors.New("lost my banana"))
+					gdnr.MaxSecurityPreset = "privileged-compat"
 				})
 
-				It("returns the error", func() {
-					_, err := container.Attach("123", garden.ProcessIO{})
-					Expect(err).To(MatchError("lost my banana"))
+				It("allows presets up to the maximum", func() {
+					desiredSpec, err := createWithPreset("privileged-compat")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(desiredSpec.SecurityPreset).To(Equal("privileged-compat"))
 				})
 			})
-		})
 
-		Describe("streaming files in to the container", func() {
-			It("asks the containerizer to stream in the tar stream", func() {
-				spec := garden.StreamInSpec{Path: "potato", User: "chef", TarStream: gbytes.NewBuffer()}
-				Expect(container.StreamIn(spec)).To(Succeed())
+			Context("when a maximum preset is configured", func() {
+				BeforeEach(func() {
+					gdnr.MaxSecurityPreset = "baseline"
+				})
 
-				_, handle, specArg := containerizer.StreamInArgsForCall(0)
-				Expect(handle).To(Equal("banana"))
-				Expect(specArg).To(Equal(spec))
-			})
-		})
+				It("allows presets up to the maximum", func() {
+					desiredSpec, err := createWithPreset("baseline")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(desiredSpec.SecurityPreset).To(Equal("baseline"))
+				})
 
-		Describe("streaming files outside the container", func() {
-			It("asks the containerizer to stream out the files", func() {
-				spec := garden.StreamOutSpec{Path: "potato", User: "chef"}
-				_, err := container.StreamOut(spec)
-				Expect(err).To(Succeed())
+				It("rejects presets less restrictive than the maximum", func() {
+					_, err := createWithPreset("privileged-compat")
+					Expect(err).To(MatchError("security preset 'privileged-compat' exceeds the maximum allowed preset 'baseline'"))
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
+				})
 
-				_, handle, specArg := containerizer.StreamOutArgsForCall(0)
-				Expect(handle).To(Equal("banana"))
-				Expect(specArg).To(Equal(spec))
+				Context("and the maximum is more restrictive than the baseline", func() {
+					BeforeEach(func() {
+						gdnr.MaxSecurityPreset = "restricted"
+					})
+
+					It("confines containers which do not request a preset with the maximum", func() {
+						desiredSpec, err := createWithPreset("")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(desiredSpec.SecurityPreset).To(Equal("restricted"))
+					})
+				})
 			})
 		})
 
-		Describe("NetIn", func() {
-			const (
-				externalPort  uint32 = 8888
-				contianerPort uint32 = 8080
-			)
+		Describe("relaxing /proc and /sys hardening", func() {
+			createWithHardening := func(hardening string) (spec.DesiredContainerSpec, error) {
+				properties := garden.Properties{gardener.ProcSysHardeningKey: hardening}
+				if _, err := gdnr.Create(garden.ContainerSpec{Properties: properties}); err != nil {
+					return spec.DesiredContainerSpec{}, err
+				}
 
-			It("asks the netwoker to forward the correct ports", func() {
-				_, _, err := container.NetIn(externalPort, contianerPort)
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				return desiredSpec, nil
+			}
 
+			It("does not relax the hardening of containers which do not set the property", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(networker.NetInCallCount()).To(Equal(1))
 
-				actualLogger, actualHandle, actualExtPort, actualContainerPort := networker.NetInArgsForCall(0)
-				Expect(actualLogger).To(Equal(logger))
-				Expect(actualHandle).To(Equal(container.Handle()))
-				Expect(actualExtPort).To(Equal(externalPort))
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				Expect(desiredSpec.RelaxProcSysHardening).To(BeFalse())
+			})
+
+			It("rejects containers which request relaxation without provisioning a volume", func() {
+				_, err := createWithHardening("relaxed")
+				Expect(err).To(MatchError("relaxing /proc and /sys hardening is disabled"))
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+
+			Context("when relaxation is allowed", func() {
+				BeforeEach(func() {
+					gdnr.AllowHardeningRelaxation = true
+				})
+
+				It("tells the containerizer to relax the hardening", func() {
+					desiredSpec, err := createWithHardening("relaxed")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(desiredSpec.RelaxProcSysHardening).To(BeTrue())
+				})
+
+				It("rejects unknown values of the property", func() {
+					_, err := createWithHardening("off")
+					Expect(err).To(MatchError("invalid garden.proc-sys-hardening property: 'off'"))
+				})
+			})
+		})
+
+		Describe("reporting progress", func() {
+			It("reports the phase each create in progress has reached", func() {
+				var inProgress []gardener.CreateProgress
+				containerizer.CreateStub = func(lager.Logger, spec.DesiredContainerSpec) error {
+					inProgress = gdnr.CreatesInProgress()
+					return nil
+				}
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "slow-handle"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inProgress).To(HaveLen(1))
+				Expect(inProgress[0].Handle).To(Equal("slow-handle"))
+				Expect(inProgress[0].Phase).To(Equal(gardener.VolumeCreatedPhase))
+				Expect(inProgress[0].PhaseReachedAt).NotTo(BeTemporally("<", inProgress[0].StartedAt))
+			})
+
+			It("stops reporting creates once they finish", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "slow-handle"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gdnr.CreatesInProgress()).To(BeEmpty())
+			})
+
+			It("logs each phase", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "slow-handle"})
+				Expect(err).NotTo(HaveOccurred())
+
+				var progressLogs []lager.LogFormat
+				for _, entry := range logger.(*lagertest.TestLogger).Logs() {
+					if entry.Message == "test.create.progress" {
+						progressLogs = append(progressLogs, entry)
+					}
+				}
+
+				Expect(progressLogs).To(HaveLen(3))
+				Expect(progressLogs[0].Data).To(HaveKeyWithValue("phase", gardener.VolumeCreatedPhase))
+				Expect(progressLogs[1].Data).To(HaveKeyWithValue("phase", gardener.ContainerCreatedPhase))
+				Expect(progressLogs[2].Data).To(HaveKeyWithValue("phase", gardener.NetworkReadyPhase))
+			})
+
+			Context("when there is a create timeout", func() {
+				var clock *fakeclock.FakeClock
+
+				BeforeEach(func() {
+					clock = fakeclock.NewFakeClock(time.Unix(123, 456))
+					gdnr.Clock = clock
+					gdnr.CreateTimeout = time.Minute
+				})
+
+				It("times the create by the clock", func() {
+					volumizer.CreateStub = func(lager.Logger, garden.ContainerSpec) (specs.Spec, error) {
+						time.Sleep(time.Millisecond)
+						return specs.Spec{}, nil
+					}
+
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "slow-handle"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				Context("when the create takes longer than the timeout", func() {
+					BeforeEach(func() {
+						volumizer.CreateStub = func(lager.Logger, garden.ContainerSpec) (specs.Spec, error) {
+							clock.Increment(2 * time.Minute)
+							return specs.Spec{}, nil
+						}
+					})
+
+					It("fails the create at the end of the phase it is in and cleans up", func() {
+						_, err := gdnr.Create(garden.ContainerSpec{Handle: "slow-handle"})
+						Expect(err).To(MatchError(gardener.CreateTimeoutError{Handle: "slow-handle", Phase: gardener.VolumeCreatedPhase, Timeout: time.Minute}))
+						Expect(containerizer.CreateCallCount()).To(Equal(0))
+						Expect(volumizer.DestroyCallCount()).To(Equal(1))
+					})
+				})
+			})
+		})
+
+		Context("when the container defines an invalid health probe", func() {
+			It("returns an error without creating anything", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{
+					Handle: "probed-handle",
+					Properties: garden.Properties{
+						gardener.HealthProbeCommandKey: "true",
+						gardener.HealthProbeTimeoutKey: "-1s",
+					},
+				})
+				Expect(err).To(MatchError("invalid garden.health-probe.timeout property: -1s"))
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("pre-create hooks", func() {
+			var hooks []*fakes.FakeContainerHook
+
+			BeforeEach(func() {
+				hooks = []*fakes.FakeContainerHook{new(fakes.FakeContainerHook), new(fakes.FakeContainerHook)}
+				hooks[0].PreCreateStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+					spec.BindMounts = append(spec.BindMounts, garden.BindMount{SrcPath: "/mandatory", DstPath: "/mandatory"})
+					spec.Handle = "sneaky-handle"
+					return spec, nil
+				}
+				hooks[1].PreCreateStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+					return spec, nil
+				}
+				gdnr.ContainerHooks = []gardener.ContainerHook{hooks[0], hooks[1]}
+			})
+
+			It("passes the spec through each hook in turn and creates the container with the result", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "hooked-handle"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, secondHookSpec := hooks[1].PreCreateArgsForCall(0)
+				Expect(secondHookSpec.BindMounts).To(ConsistOf(garden.BindMount{SrcPath: "/mandatory", DstPath: "/mandatory"}))
+
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				Expect(desiredSpec.Handle).To(Equal("hooked-handle"))
+				Expect(desiredSpec.BindMounts).To(ContainElement(garden.BindMount{SrcPath: "/mandatory", DstPath: "/mandatory"}))
+			})
+
+			Context("when a hook refuses the create", func() {
+				BeforeEach(func() {
+					hooks[0].PreCreateReturns(garden.ContainerSpec{}, errors.New("not on my watch"))
+				})
+
+				It("fails the create without creating anything", func() {
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "hooked-handle"})
+					Expect(err).To(MatchError("pre-create hook: not on my watch"))
+					Expect(hooks[1].PreCreateCallCount()).To(Equal(0))
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
+					Expect(containerizer.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Describe("quarantining failed creates", func() {
+			var quarantiner *fakes.FakeQuarantiner
+
+			BeforeEach(func() {
+				quarantiner = new(fakes.FakeQuarantiner)
+				gdnr.Quarantiner = quarantiner
+			})
+
+			It("does not quarantine containers which were created", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "good-handle"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(quarantiner.QuarantineCallCount()).To(Equal(0))
+			})
+
+			Context("when the create fails", func() {
+				BeforeEach(func() {
+					containerizer.CreateReturns(errors.New("boom"))
+				})
+
+				It("quarantines the container with the reason and then cleans it up", func() {
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "failed-handle"})
+					Expect(err).To(MatchError(ContainSubstring("boom")))
+
+					Expect(quarantiner.QuarantineCallCount()).To(Equal(1))
+					_, handle, reason := quarantiner.QuarantineArgsForCall(0)
+					Expect(handle).To(Equal("failed-handle"))
+					Expect(reason).To(ContainSubstring("boom"))
+					Expect(volumizer.DestroyCallCount()).To(Equal(1))
+				})
+
+				Context("and quarantining fails", func() {
+					BeforeEach(func() {
+						quarantiner.QuarantineReturns(errors.New("no room"))
+					})
+
+					It("still cleans up the container", func() {
+						_, err := gdnr.Create(garden.ContainerSpec{Handle: "failed-handle"})
+						Expect(err).To(MatchError(ContainSubstring("boom")))
+						Expect(volumizer.DestroyCallCount()).To(Equal(1))
+					})
+				})
+			})
+		})
+
+		Describe("collecting core dumps", func() {
+			var coreDumpCollector *fakes.FakeCoreDumpCollector
+
+			BeforeEach(func() {
+				coreDumpCollector = new(fakes.FakeCoreDumpCollector)
+				coreDumpCollector.PrepareReturns("/var/gdn/cores/core-handle", nil)
+				gdnr.CoreDumpCollector = coreDumpCollector
+				gdnr.CoreDumpPath = "/var/cores"
+			})
+
+			createWithCoreDumps := func(value string) (spec.DesiredContainerSpec, error) {
+				containerSpec := garden.ContainerSpec{
+					Handle:     "core-handle",
+					Properties: garden.Properties{gardener.CoreDumpsKey: value},
+					BindMounts: []garden.BindMount{{SrcPath: "/src", DstPath: "/dst"}},
+				}
+				if _, err := gdnr.Create(containerSpec); err != nil {
+					return spec.DesiredContainerSpec{}, err
+				}
+
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				return desiredSpec, nil
+			}
+
+			It("bind mounts the container's core dump directory at the core dump path", func() {
+				desiredSpec, err := createWithCoreDumps("enabled")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(coreDumpCollector.PrepareCallCount()).To(Equal(1))
+				_, handle := coreDumpCollector.PrepareArgsForCall(0)
+				Expect(handle).To(Equal("core-handle"))
+
+				Expect(desiredSpec.BindMounts).To(Equal([]garden.BindMount{
+					{SrcPath: "/src", DstPath: "/dst"},
+					{SrcPath: "/var/gdn/cores/core-handle", DstPath: "/var/cores", Mode: garden.BindMountModeRW, Origin: garden.BindMountOriginHost},
+				}))
+			})
+
+			It("does not prepare a directory for containers which do not set the property", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(coreDumpCollector.PrepareCallCount()).To(Equal(0))
+			})
+
+			It("rejects unknown values of the property", func() {
+				_, err := createWithCoreDumps("on")
+				Expect(err).To(MatchError("invalid garden.core-dumps property: 'on'"))
+			})
+
+			Context("when preparing the directory fails", func() {
+				BeforeEach(func() {
+					coreDumpCollector.PrepareReturns("", errors.New("read-only"))
+				})
+
+				It("returns the error without creating the container", func() {
+					_, err := createWithCoreDumps("enabled")
+					Expect(err).To(MatchError("preparing core dump directory: read-only"))
+					Expect(containerizer.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when core dump collection is disabled", func() {
+				BeforeEach(func() {
+					gdnr.CoreDumpCollector = nil
+				})
+
+				It("rejects containers which enable core dumps", func() {
+					_, err := createWithCoreDumps("enabled")
+					Expect(err).To(MatchError("core dump collection is disabled"))
+					Expect(volumizer.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+	})
+
+	Context("when having a container", func() {
+		var container garden.Container
+
+		BeforeEach(func() {
+			var err error
+			container, err = gdnr.Lookup("banana")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Describe("running a process in a container", func() {
+			It("asks the containerizer to run the process", func() {
+				origSpec := garden.ProcessSpec{Path: "ripe"}
+				origIO := garden.ProcessIO{
+					Stdout: gbytes.NewBuffer(),
+				}
+				_, err := container.Run(origSpec, origIO)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(containerizer.RunCallCount()).To(Equal(1))
+				_, id, spec, io := containerizer.RunArgsForCall(0)
+				Expect(id).To(Equal("banana"))
+				Expect(spec).To(Equal(origSpec))
+				Expect(io).To(Equal(origIO))
+			})
+
+			Context("when the containerizer fails to run a process", func() {
+				BeforeEach(func() {
+					containerizer.RunReturns(nil, errors.New("lost my banana"))
+				})
+
+				It("returns the error", func() {
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).To(MatchError("lost my banana"))
+				})
+			})
+
+			Context("when a default process user is configured", func() {
+				BeforeEach(func() {
+					gdnr.DefaultProcessUser = "vcap"
+
+					var err error
+					container, err = gdnr.Lookup("banana")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("runs processes which do not specify a user as the default user", func() {
+					_, err := container.Run(garden.ProcessSpec{Path: "ripe"}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(spec.User).To(Equal("vcap"))
+				})
+
+				It("does not override the user given in the process spec", func() {
+					_, err := container.Run(garden.ProcessSpec{Path: "ripe", User: "alice"}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(spec.User).To(Equal("alice"))
+				})
+			})
+
+			Context("when the env references container properties", func() {
+				BeforeEach(func() {
+					propertyManager.GetStub = func(handle, name string) (string, bool) {
+						if handle == "banana" && name == gardener.ContainerIPKey {
+							return "10.0.0.2", true
+						}
+						return "", false
+					}
+				})
+
+				It("runs the process with the values of the properties", func() {
+					_, err := container.Run(garden.ProcessSpec{
+						Env: []string{"HOME=/home/vcap", "CF_INSTANCE_ADDR=$(property:garden.network.container-ip):8080"},
+					}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(spec.Env).To(Equal([]string{"HOME=/home/vcap", "CF_INSTANCE_ADDR=10.0.0.2:8080"}))
+				})
+
+				It("returns an error without running the process when a property does not exist", func() {
+					_, err := container.Run(garden.ProcessSpec{
+						Env: []string{"PORT=$(property:garden.network.mapped-ports)"},
+					}, garden.ProcessIO{})
+					Expect(err).To(MatchError("expanding env: unknown property 'garden.network.mapped-ports'"))
+					Expect(containerizer.RunCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the container collects core dumps", func() {
+				var coreDumpCollector *fakes.FakeCoreDumpCollector
+
+				BeforeEach(func() {
+					coreDumpCollector = new(fakes.FakeCoreDumpCollector)
+					coreDumpCollector.UsageReturns(900, nil)
+					gdnr.CoreDumpCollector = coreDumpCollector
+					gdnr.MaxCoreDumpSize = 500
+
+					propertyManager.GetStub = func(handle, name string) (string, bool) {
+						if name == gardener.CoreDumpsKey {
+							return "enabled", true
+						}
+						return "", false
+					}
+
+					var err error
+					container, err = gdnr.Lookup("banana")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("caps the core size of processes at the largest core dump", func() {
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Core).To(BeEquivalentTo(500))
+				})
+
+				It("keeps a smaller core size given in the process spec", func() {
+					core := uint64(100)
+					_, err := container.Run(garden.ProcessSpec{Limits: garden.ResourceLimits{Core: &core}}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Core).To(BeEquivalentTo(100))
+				})
+
+				It("caps the core size at the space left in the core dump directory", func() {
+					gdnr.MaxCoreDumpDirSize = 1000
+
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Core).To(BeEquivalentTo(100))
+				})
+
+				It("does not let processes dump core once the directory is full", func() {
+					gdnr.MaxCoreDumpDirSize = 800
+
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Core).To(BeZero())
+				})
+
+				Context("when measuring the core dumps fails", func() {
+					It("returns the error without running the process", func() {
+						gdnr.MaxCoreDumpDirSize = 1000
+						coreDumpCollector.UsageReturns(0, errors.New("permission denied"))
+
+						_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+						Expect(err).To(MatchError("measuring core dumps: permission denied"))
+						Expect(containerizer.RunCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			It("does not set a nofile limit when there is no open files limit", func() {
+				_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, spec, _ := containerizer.RunArgsForCall(0)
+				Expect(spec.Limits.Nofile).To(BeNil())
+			})
+
+			Context("when there is an open files limit", func() {
+				BeforeEach(func() {
+					gdnr.MaxOpenFilesPerProcess = 1024
+
+					var err error
+					container, err = gdnr.Lookup("banana")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("applies it to processes which do not ask for a nofile limit", func() {
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Nofile).To(BeEquivalentTo(1024))
+				})
+
+				It("lowers the nofile limit of processes which ask for more", func() {
+					nofile := uint64(65536)
+					_, err := container.Run(garden.ProcessSpec{Limits: garden.ResourceLimits{Nofile: &nofile}}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Nofile).To(BeEquivalentTo(1024))
+				})
+
+				It("keeps the nofile limit of processes which ask for less", func() {
+					nofile := uint64(256)
+					_, err := container.Run(garden.ProcessSpec{Limits: garden.ResourceLimits{Nofile: &nofile}}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, _, spec, _ := containerizer.RunArgsForCall(0)
+					Expect(*spec.Limits.Nofile).To(BeEquivalentTo(256))
+				})
+			})
+
+			It("does not count the live processes when there is no process limit", func() {
+				_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(containerizer.LiveProcessCountCallCount()).To(Equal(0))
+			})
+
+			Context("when there is a process limit", func() {
+				BeforeEach(func() {
+					gdnr.MaxProcessesPerContainer = 3
+
+					var err error
+					container, err = gdnr.Lookup("banana")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("runs the process while the container is below the limit", func() {
+					containerizer.LiveProcessCountReturns(2, nil)
+
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, handle := containerizer.LiveProcessCountArgsForCall(0)
+					Expect(handle).To(Equal("banana"))
+					Expect(containerizer.RunCallCount()).To(Equal(1))
+				})
+
+				It("rejects the process with a ProcessLimitExceededError once the limit is reached", func() {
+					containerizer.LiveProcessCountReturns(3, nil)
+
+					_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).To(MatchError(gardener.ProcessLimitExceededError{Handle: "banana", Limit: 3}))
+					Expect(containerizer.RunCallCount()).To(Equal(0))
+				})
+
+				It("does not count the live processes of another run until its process has started", func() {
+					started := make(chan struct{})
+					containerizer.RunStub = func(lager.Logger, string, garden.ProcessSpec, garden.ProcessIO) (garden.Process, error) {
+						<-started
+						return new(gardenfakes.FakeProcess), nil
+					}
+
+					go func() {
+						defer GinkgoRecover()
+						_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+						Expect(err).NotTo(HaveOccurred())
+					}()
+					Eventually(containerizer.RunCallCount).Should(Equal(1))
+
+					otherRunDone := make(chan struct{})
+					go func() {
+						defer GinkgoRecover()
+						defer close(otherRunDone)
+
+						otherContainer, err := gdnr.Lookup("banana")
+						Expect(err).NotTo(HaveOccurred())
+						_, err = otherContainer.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+						Expect(err).NotTo(HaveOccurred())
+					}()
+					Consistently(containerizer.LiveProcessCountCallCount).Should(Equal(1))
+
+					close(started)
+					Eventually(otherRunDone).Should(BeClosed())
+					Expect(containerizer.LiveProcessCountCallCount()).To(Equal(2))
+				})
+
+				Context("when counting the live processes fails", func() {
+					It("returns the error", func() {
+						containerizer.LiveProcessCountReturns(0, errors.New("cannot-count"))
+
+						_, err := container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+						Expect(err).To(MatchError("cannot-count"))
+						Expect(containerizer.RunCallCount()).To(Equal(0))
+					})
+				})
+			})
+		})
+
+		Describe("attaching to an existing process in a container", func() {
+			It("asks the containerizer to attach to the process", func() {
+				origIO := garden.ProcessIO{
+					Stdout: gbytes.NewBuffer(),
+				}
+				_, err := container.Attach("123", origIO)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(containerizer.AttachCallCount()).To(Equal(1))
+				_, id, processId, io := containerizer.AttachArgsForCall(0)
+				Expect(id).To(Equal("banana"))
+				Expect(processId).To(Equal("123"))
+				Expect(io).To(Equal(origIO))
+			})
+
+			Context("when the containerizer fails to attach to a process", func() {
+				BeforeEach(func() {
+					containerizer.AttachReturns(nil, errors.New("lost my banana"))
+				})
+
+				It("returns the error", func() {
+					_, err := container.Attach("123", garden.ProcessIO{})
+					Expect(err).To(MatchError("lost my banana"))
+				})
+			})
+		})
+
+		Describe("streaming files in to the container", func() {
+			It("asks the containerizer to stream in the tar stream", func() {
+				spec := garden.StreamInSpec{Path: "potato", User: "chef", TarStream: gbytes.NewBuffer()}
+				Expect(container.StreamIn(spec)).To(Succeed())
+
+				_, handle, specArg := containerizer.StreamInArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(specArg).To(Equal(spec))
+			})
+		})
+
+		Describe("streaming files outside the container", func() {
+			It("asks the containerizer to stream out the files", func() {
+				spec := garden.StreamOutSpec{Path: "potato", User: "chef"}
+				_, err := container.StreamOut(spec)
+				Expect(err).To(Succeed())
+
+				_, handle, specArg := containerizer.StreamOutArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(specArg).To(Equal(spec))
+			})
+		})
+
+		Describe("NetIn", func() {
+			const (
+				externalPort  uint32 = 8888
+				contianerPort uint32 = 8080
+			)
+
+			It("asks the netwoker to forward the correct ports", func() {
+				_, _, err := container.NetIn(externalPort, contianerPort)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(networker.NetInCallCount()).To(Equal(1))
+
+				actualLogger, actualHandle, actualExtPort, actualContainerPort := networker.NetInArgsForCall(0)
+				Expect(actualLogger).To(Equal(logger))
+				Expect(actualHandle).To(Equal(container.Handle()))
+				Expect(actualExtPort).To(Equal(externalPort))
 				Expect(actualContainerPort).To(Equal(contianerPort))
 			})
 
-			Context("when networker returns an error", func() {
-				It("returns the error", func() {
-					networker.NetInReturns(uint32(0), uint32(0), fmt.Errorf("error"))
+			It("records the host port", func() {
+				resourceStore.GetReturns(gardener.ContainerResources{HostPorts: []uint32{1234}}, nil)
+				networker.NetInReturns(externalPort, contianerPort, nil)
+
+				_, _, err := container.NetIn(externalPort, contianerPort)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(resourceStore.SetCallCount()).To(Equal(1))
+				handle, resources := resourceStore.SetArgsForCall(0)
+				Expect(handle).To(Equal(container.Handle()))
+				Expect(resources.HostPorts).To(Equal([]uint32{1234, externalPort}))
+			})
+
+			It("does not lose host ports recorded concurrently", func() {
+				var (
+					recordMutex sync.Mutex
+					record      gardener.ContainerResources
+				)
+				resourceStore.GetStub = func(string) (gardener.ContainerResources, error) {
+					recordMutex.Lock()
+					defer recordMutex.Unlock()
+					resources := record
+					resources.HostPorts = append([]uint32{}, record.HostPorts...)
+					return resources, nil
+				}
+				resourceStore.SetStub = func(_ string, resources gardener.ContainerResources) error {
+					time.Sleep(time.Millisecond)
+					recordMutex.Lock()
+					defer recordMutex.Unlock()
+					record = resources
+					return nil
+				}
+				networker.NetInStub = func(_ lager.Logger, _ string, hostPort, containerPort uint32) (uint32, uint32, error) {
+					return hostPort, containerPort, nil
+				}
+
+				var wg sync.WaitGroup
+				for port := uint32(1); port <= 10; port++ {
+					wg.Add(1)
+					go func(port uint32) {
+						defer GinkgoRecover()
+						defer wg.Done()
+						_, _, err := container.NetIn(port, contianerPort)
+						Expect(err).NotTo(HaveOccurred())
+					}(port)
+				}
+				wg.Wait()
+
+				Expect(record.HostPorts).To(ConsistOf(uint32(1), uint32(2), uint32(3), uint32(4), uint32(5), uint32(6), uint32(7), uint32(8), uint32(9), uint32(10)))
+			})
+
+			Context("when networker returns an error", func() {
+				It("returns the error", func() {
+					networker.NetInReturns(uint32(0), uint32(0), fmt.Errorf("error"))
+
+					_, _, err := container.NetIn(externalPort, contianerPort)
+
+					Expect(err).To(MatchError("error"))
+				})
+			})
+
+			Context("when recording the host port fails", func() {
+				It("returns the error", func() {
+					resourceStore.SetReturns(errors.New("disk full"))
+
+					_, _, err := container.NetIn(externalPort, contianerPort)
+
+					Expect(err).To(MatchError(ContainSubstring("disk full")))
+				})
+			})
+		})
+
+		Describe("NetOut", func() {
+			var rule garden.NetOutRule
+
+			BeforeEach(func() {
+				rule = garden.NetOutRule{
+					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("8.2.3.4"))},
+					Ports:    []garden.PortRange{garden.PortRangeFromPort(9321)},
+				}
+			})
+
+			It("asks the networker to apply the provided netout rule", func() {
+				Expect(container.NetOut(rule)).To(Succeed())
+				Expect(networker.NetOutCallCount()).To(Equal(1))
+
+				_, handle, actualRule := networker.NetOutArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(actualRule).To(Equal(rule))
+			})
+
+			Context("when networker returns an error", func() {
+				It("return the error", func() {
+					networker.NetOutReturns(fmt.Errorf("banana republic"))
+					Expect(container.NetOut(rule)).To(MatchError("banana republic"))
+				})
+			})
+		})
+
+		Describe("BulkNetOut", func() {
+			var rules []garden.NetOutRule
+
+			BeforeEach(func() {
+				rules = []garden.NetOutRule{
+					{
+						Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("8.2.3.4"))},
+						Ports:    []garden.PortRange{garden.PortRangeFromPort(9321)},
+					},
+					{
+						Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("6.1.2.3"))},
+						Ports:    []garden.PortRange{garden.PortRangeFromPort(7654)},
+					},
+				}
+			})
+
+			It("asks the networker to apply the provided netout rule", func() {
+				Expect(container.BulkNetOut(rules)).To(Succeed())
+				Expect(networker.BulkNetOutCallCount()).To(Equal(1))
+
+				_, handle, actualRules := networker.BulkNetOutArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(actualRules).To(ConsistOf(rules))
+			})
+
+			Context("when networker returns an error", func() {
+				It("return the error", func() {
+					networker.BulkNetOutReturns(fmt.Errorf("banana republic"))
+					Expect(container.BulkNetOut(rules)).To(MatchError("banana republic"))
+				})
+			})
+		})
+	})
+
+	Describe("creating containers in bulk", func() {
+		var containerSpecs []garden.ContainerSpec
+
+		BeforeEach(func() {
+			uidGenerator.GenerateReturns("some-uid")
+			gdnr.BulkCreateParallelism = 2
+
+			containerSpecs = []garden.ContainerSpec{
+				{Handle: "ctr-1", Image: garden.ImageRef{URI: "docker:///cflinuxfs2"}},
+				{Handle: "ctr-2", Image: garden.ImageRef{URI: "docker:///cflinuxfs2"}},
+				{Handle: "ctr-3", Image: garden.ImageRef{URI: "docker:///busybox"}},
+			}
+		})
+
+		It("creates every container, returning the results in order", func() {
+			results := gdnr.BulkCreate(containerSpecs)
+			Expect(results).To(HaveLen(3))
+			for i, result := range results {
+				Expect(result.Err).NotTo(HaveOccurred())
+				Expect(result.Container.Handle()).To(Equal(containerSpecs[i].Handle))
+			}
+
+			Expect(containerizer.CreateCallCount()).To(Equal(3))
+		})
+
+		It("fetches images which are shared by several containers once up front", func() {
+			gdnr.BulkCreate(containerSpecs)
+
+			Expect(volumizer.CreateCallCount()).To(Equal(4))
+			_, prefetchSpec := volumizer.CreateArgsForCall(0)
+			Expect(prefetchSpec.Handle).To(Equal("prefetch-some-uid"))
+			Expect(prefetchSpec.Image.URI).To(Equal("docker:///cflinuxfs2"))
+		})
+
+		It("creates at most BulkCreateParallelism containers at once", func() {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			containerizer.CreateStub = func(lager.Logger, spec.DesiredContainerSpec) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+
+				time.Sleep(50 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			}
+
+			gdnr.BulkCreate(containerSpecs)
+			Expect(maxRunning).To(Equal(2))
+		})
+
+		Context("when some of the creates fail", func() {
+			BeforeEach(func() {
+				containerizer.CreateStub = func(_ lager.Logger, desiredSpec spec.DesiredContainerSpec) error {
+					if desiredSpec.Handle == "ctr-2" {
+						return errors.New("boom")
+					}
+					return nil
+				}
+			})
+
+			It("returns the error for those containers only", func() {
+				results := gdnr.BulkCreate(containerSpecs)
+				Expect(results[0].Err).NotTo(HaveOccurred())
+				Expect(results[1].Err).To(MatchError("boom"))
+				Expect(results[2].Err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when a handle appears more than once in the batch", func() {
+			BeforeEach(func() {
+				containerSpecs[2].Handle = "ctr-1"
+			})
+
+			It("rejects the repeats without creating them", func() {
+				results := gdnr.BulkCreate(containerSpecs)
+				Expect(results[0].Err).NotTo(HaveOccurred())
+				Expect(results[1].Err).NotTo(HaveOccurred())
+				Expect(results[2].Err).To(MatchError("handle 'ctr-1' appears more than once in the batch"))
+
+				Expect(containerizer.CreateCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when a bulk create server is set", func() {
+			var server *gardenerfakes.FakeContainerCreator
+
+			BeforeEach(func() {
+				server = new(gardenerfakes.FakeContainerCreator)
+				server.CreateStub = func(containerSpec garden.ContainerSpec) (garden.Container, error) {
+					return gdnr.Lookup(containerSpec.Handle)
+				}
+				gdnr.BulkCreateServer = server
+			})
+
+			It("creates the containers through the server", func() {
+				results := gdnr.BulkCreate(containerSpecs)
+				for _, result := range results {
+					Expect(result.Err).NotTo(HaveOccurred())
+				}
+
+				Expect(server.CreateCallCount()).To(Equal(3))
+				Expect(containerizer.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when prefetching a shared image fails", func() {
+			BeforeEach(func() {
+				volumizer.CreateReturnsOnCall(0, specs.Spec{}, errors.New("fetch failed"))
+			})
+
+			It("still creates the containers", func() {
+				results := gdnr.BulkCreate(containerSpecs)
+				for _, result := range results {
+					Expect(result.Err).NotTo(HaveOccurred())
+				}
+			})
+		})
+	})
+
+	Describe("destroying containers by property", func() {
+		BeforeEach(func() {
+			gdnr.BulkCreateParallelism = 2
+			containerizer.HandlesReturns([]string{"ctr-1", "ctr-2", "ctr-3"}, nil)
+			propertyManager.MatchesAllStub = func(handle string, props garden.Properties) bool {
+				return handle != "ctr-2"
+			}
+		})
+
+		It("destroys every matching container", func() {
+			results, err := gdnr.DestroyMatching(garden.Properties{"tag:app-guid": "some-app"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(ConsistOf(
+				gardener.BulkDestroyResult{Handle: "ctr-1"},
+				gardener.BulkDestroyResult{Handle: "ctr-3"},
+			))
+
+			Expect(containerizer.DestroyCallCount()).To(Equal(2))
+		})
+
+		It("selects only created containers with the given properties", func() {
+			_, err := gdnr.DestroyMatching(garden.Properties{"tag:app-guid": "some-app"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, props := propertyManager.MatchesAllArgsForCall(0)
+			Expect(props).To(Equal(garden.Properties{"tag:app-guid": "some-app", "garden.state": "created"}))
+		})
+
+		It("does not modify the given properties", func() {
+			props := garden.Properties{"tag:app-guid": "some-app"}
+			_, err := gdnr.DestroyMatching(props)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props).To(Equal(garden.Properties{"tag:app-guid": "some-app"}))
+		})
+
+		Context("when no properties are given", func() {
+			It("returns an error without destroying anything", func() {
+				_, err := gdnr.DestroyMatching(garden.Properties{})
+				Expect(err).To(MatchError("a property selector is required"))
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the properties are only wildcards", func() {
+			It("returns an error without destroying anything", func() {
+				_, err := gdnr.DestroyMatching(garden.Properties{"*": "*", "**": ""})
+				Expect(err).To(MatchError("a property selector must not match every container"))
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
+			})
+
+			It("allows wildcards alongside other properties", func() {
+				_, err := gdnr.DestroyMatching(garden.Properties{"*": "*", "tag:app-guid": "*"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when a destroy matching server is set", func() {
+			var server *gardenerfakes.FakeContainerDestroyer
+
+			BeforeEach(func() {
+				server = new(gardenerfakes.FakeContainerDestroyer)
+				gdnr.DestroyMatchingServer = server
+			})
+
+			It("destroys the containers through the server", func() {
+				results, err := gdnr.DestroyMatching(garden.Properties{"tag:app-guid": "some-app"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(2))
+
+				Expect(server.DestroyCallCount()).To(Equal(2))
+				Expect([]string{server.DestroyArgsForCall(0), server.DestroyArgsForCall(1)}).To(ConsistOf("ctr-1", "ctr-3"))
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when some of the destroys fail", func() {
+			BeforeEach(func() {
+				containerizer.DestroyStub = func(_ lager.Logger, handle string) error {
+					if handle == "ctr-3" {
+						return errors.New("busy")
+					}
+					return nil
+				}
+			})
+
+			It("returns the error for those containers only", func() {
+				results, err := gdnr.DestroyMatching(garden.Properties{"tag:app-guid": "some-app"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(ConsistOf(
+					gardener.BulkDestroyResult{Handle: "ctr-1"},
+					gardener.BulkDestroyResult{Handle: "ctr-3", Err: errors.New("busy")},
+				))
+			})
+		})
+	})
+
+	Describe("prefetching an image", func() {
+		BeforeEach(func() {
+			uidGenerator.GenerateReturns("some-uid")
+		})
+
+		It("creates a volume for the image under a temporary handle", func() {
+			Expect(gdnr.PrefetchImage(garden.ContainerSpec{
+				Handle:     "ignored",
+				Image:      garden.ImageRef{URI: "docker:///cflinuxfs2", Username: "user"},
+				RootFSPath: "/some/rootfs",
+				Privileged: false,
+				Properties: garden.Properties{"ignored": "too"},
+			})).To(Succeed())
+
+			Expect(volumizer.CreateCallCount()).To(Equal(1))
+			_, spec := volumizer.CreateArgsForCall(0)
+			Expect(spec).To(Equal(garden.ContainerSpec{
+				Handle:     "prefetch-some-uid",
+				Image:      garden.ImageRef{URI: "docker:///cflinuxfs2", Username: "user"},
+				RootFSPath: "/some/rootfs",
+			}))
+		})
+
+		It("destroys the volume, leaving the image cached", func() {
+			Expect(gdnr.PrefetchImage(garden.ContainerSpec{})).To(Succeed())
+
+			Expect(volumizer.DestroyCallCount()).To(Equal(1))
+			_, handle := volumizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("prefetch-some-uid"))
+		})
+
+		It("does not create a container", func() {
+			Expect(gdnr.PrefetchImage(garden.ContainerSpec{})).To(Succeed())
+
+			Expect(containerizer.CreateCallCount()).To(Equal(0))
+			Expect(networker.NetworkCallCount()).To(Equal(0))
+		})
+
+		Context("when fetching the image fails", func() {
+			BeforeEach(func() {
+				volumizer.CreateReturns(specs.Spec{}, errors.New("fetch failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.PrefetchImage(garden.ContainerSpec{})).To(MatchError("fetch failed"))
+				Expect(volumizer.DestroyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when destroying the volume fails", func() {
+			BeforeEach(func() {
+				volumizer.DestroyReturns(errors.New("destroy failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.PrefetchImage(garden.ContainerSpec{})).To(MatchError("destroy failed"))
+			})
+		})
+
+		Context("when the image is privileged and privileged containers are disabled", func() {
+			It("returns an error", func() {
+				Expect(gdnr.PrefetchImage(garden.ContainerSpec{Privileged: true})).To(MatchError("privileged container creation is disabled"))
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("previewing a container", func() {
+		It("passes the desired container spec to the containerizer", func() {
+			_, err := gdnr.PreviewContainer(garden.ContainerSpec{
+				Handle:     "some-handle",
+				Env:        []string{"FOO=bar"},
+				RootFSPath: "raw:///some/rootfs",
+				BindMounts: []garden.BindMount{{SrcPath: "/src", DstPath: "/dst"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerizer.PreviewCallCount()).To(Equal(1))
+			_, desiredSpec := containerizer.PreviewArgsForCall(0)
+			Expect(desiredSpec.Handle).To(Equal("some-handle"))
+			Expect(desiredSpec.Hostname).To(Equal("some-handle"))
+			Expect(desiredSpec.Env).To(Equal([]string{"FOO=bar"}))
+			Expect(desiredSpec.BindMounts).To(Equal([]garden.BindMount{{SrcPath: "/src", DstPath: "/dst"}}))
+			Expect(desiredSpec.BaseConfig.Root.Path).To(Equal("/some/rootfs"))
+		})
+
+		It("uses the image URI as the rootfs path when provided", func() {
+			_, err := gdnr.PreviewContainer(garden.ContainerSpec{Image: garden.ImageRef{URI: "docker:///busybox"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, desiredSpec := containerizer.PreviewArgsForCall(0)
+			Expect(desiredSpec.BaseConfig.Root.Path).To(Equal("docker:///busybox"))
+		})
+
+		It("assigns a random handle when none is specified", func() {
+			uidGenerator.GenerateReturns("generated-handle")
+
+			_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, desiredSpec := containerizer.PreviewArgsForCall(0)
+			Expect(desiredSpec.Handle).To(Equal("generated-handle"))
+		})
+
+		It("returns the spec from the containerizer", func() {
+			containerizer.PreviewReturns(specs.Spec{Hostname: "previewed"}, nil)
+
+			runtimeSpec, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runtimeSpec.Hostname).To(Equal("previewed"))
+		})
+
+		It("does not create anything", func() {
+			_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumizer.CreateCallCount()).To(Equal(0))
+			Expect(containerizer.CreateCallCount()).To(Equal(0))
+			Expect(networker.NetworkCallCount()).To(Equal(0))
+		})
+
+		Context("when the containerizer fails to preview", func() {
+			It("returns the error", func() {
+				containerizer.PreviewReturns(specs.Spec{}, errors.New("boom"))
+
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+				Expect(err).To(MatchError("boom"))
+			})
+		})
+
+		Context("when privileged containers are disabled", func() {
+			It("returns an error for a privileged spec", func() {
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{Privileged: true})
+				Expect(err).To(MatchError("privileged container creation is disabled"))
+				Expect(containerizer.PreviewCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when there are pre-create hooks", func() {
+			var hook *fakes.FakeContainerHook
+
+			BeforeEach(func() {
+				hook = new(fakes.FakeContainerHook)
+				hook.PreCreateStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+					spec.BindMounts = append(spec.BindMounts, garden.BindMount{SrcPath: "/mandatory", DstPath: "/mandatory"})
+					return spec, nil
+				}
+				gdnr.ContainerHooks = []gardener.ContainerHook{hook}
+			})
+
+			It("does not run the hooks", func() {
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(hook.PreCreateCallCount()).To(Equal(0))
+				_, desiredSpec := containerizer.PreviewArgsForCall(0)
+				Expect(desiredSpec.BindMounts).To(BeEmpty())
+			})
+		})
+
+		Context("when the container collects core dumps", func() {
+			var coreDumpCollector *fakes.FakeCoreDumpCollector
+
+			BeforeEach(func() {
+				coreDumpCollector = new(fakes.FakeCoreDumpCollector)
+				coreDumpCollector.HostDirReturns("/var/gdn/cores/core-handle")
+				gdnr.CoreDumpCollector = coreDumpCollector
+				gdnr.CoreDumpPath = "/var/cores"
+			})
+
+			It("previews the core dump mount without preparing the directory", func() {
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{
+					Handle:     "core-handle",
+					Properties: garden.Properties{gardener.CoreDumpsKey: gardener.EnabledCoreDumps},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, desiredSpec := containerizer.PreviewArgsForCall(0)
+				Expect(desiredSpec.BindMounts).To(ConsistOf(garden.BindMount{
+					SrcPath: "/var/gdn/cores/core-handle",
+					DstPath: "/var/cores",
+					Mode:    garden.BindMountModeRW,
+					Origin:  garden.BindMountOriginHost,
+				}))
+				Expect(coreDumpCollector.PrepareCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the volumizer can plan volumes", func() {
+			var volumePlanner *fakes.FakeVolumePlanner
+
+			BeforeEach(func() {
+				volumePlanner = new(fakes.FakeVolumePlanner)
+				gdnr.Volumizer = planningVolumizer{volumizer, volumePlanner}
+			})
+
+			It("checks the volume without creating it", func() {
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{Handle: "some-handle"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(volumePlanner.PlanCallCount()).To(Equal(1))
+				_, plannedSpec := volumePlanner.PlanArgsForCall(0)
+				Expect(plannedSpec.Handle).To(Equal("some-handle"))
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+
+			It("returns the error when the volume could not be created", func() {
+				volumePlanner.PlanReturns(errors.New("layers not supported"))
+
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+				Expect(err).To(MatchError("layers not supported"))
+				Expect(containerizer.PreviewCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the networker can plan networks", func() {
+			var networkPlanner *fakes.FakeNetworkPlanner
+
+			BeforeEach(func() {
+				networkPlanner = new(fakes.FakeNetworkPlanner)
+				gdnr.Networker = planningNetworker{networker, networkPlanner}
+			})
+
+			It("checks the network without acquiring it", func() {
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{Handle: "some-handle", Network: "10.0.0.5/30"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(networkPlanner.PlanNetworkCallCount()).To(Equal(1))
+				_, plannedSpec := networkPlanner.PlanNetworkArgsForCall(0)
+				Expect(plannedSpec.Network).To(Equal("10.0.0.5/30"))
+				Expect(networker.NetworkCallCount()).To(Equal(0))
+			})
+
+			It("returns the error when the network could not be acquired", func() {
+				networkPlanner.PlanNetworkReturns(errors.New("unknown network qos class: first-class"))
+
+				_, err := gdnr.PreviewContainer(garden.ContainerSpec{})
+				Expect(err).To(MatchError("unknown network qos class: first-class"))
+				Expect(containerizer.PreviewCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("starting up gardener", func() {
+		BeforeEach(func() {
+			containers := []string{"container1", "container2"}
+			containerizer.HandlesReturns(containers, nil)
+		})
+
+		It("calls the bulk starter", func() {
+			Expect(gdnr.Start()).To(Succeed())
+
+			Expect(fakeBulkStarter.StartAllCallCount()).To(Equal(1))
+		})
+
+		Context("when the bulk starter fails", func() {
+			BeforeEach(func() {
+				fakeBulkStarter.StartAllReturns(errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.Start()).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("does not restore the containers", func() {
+				Expect(gdnr.Start()).NotTo(Succeed())
+				Expect(restorer.RestoreCallCount()).To(Equal(0))
+			})
+		})
+
+		It("should restore the containers", func() {
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(restorer.RestoreCallCount()).To(Equal(1))
+			_, handles := restorer.RestoreArgsForCall(0)
+			Expect(handles).To(Equal([]string{"container1", "container2"}))
+		})
+
+		It("should blow up containers that couldn't restore", func() {
+			restorer.RestoreReturns([]string{"container2"})
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(containerizer.DestroyCallCount()).To(Equal(1))
+			_, handle := containerizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("container2"))
+		})
+
+		Context("when the containerizer can re-attach to container events", func() {
+			var watchRestorer *fakes.FakeWatchRestorer
+
+			BeforeEach(func() {
+				watchRestorer = new(fakes.FakeWatchRestorer)
+				gdnr.Containerizer = watchRestoringContainerizer{containerizer, watchRestorer}
+				restorer.RestoreReturns([]string{"container2"})
+			})
+
+			It("re-attaches to the events of the containers which were restored", func() {
+				Expect(gdnr.Start()).To(Succeed())
+				Expect(watchRestorer.RestoreWatchCallCount()).To(Equal(1))
+				_, handle := watchRestorer.RestoreWatchArgsForCall(0)
+				Expect(handle).To(Equal("container1"))
+			})
+		})
+
+		Context("when a quarantiner is set", func() {
+			var quarantiner *fakes.FakeQuarantiner
+
+			BeforeEach(func() {
+				quarantiner = new(fakes.FakeQuarantiner)
+				gdnr.Quarantiner = quarantiner
+				restorer.RestoreReturns([]string{"container2"})
+			})
+
+			It("quarantines containers that couldn't restore before blowing them up", func() {
+				Expect(gdnr.Start()).To(Succeed())
+				Expect(quarantiner.QuarantineCallCount()).To(Equal(1))
+				_, handle, reason := quarantiner.QuarantineArgsForCall(0)
+				Expect(handle).To(Equal("container2"))
+				Expect(reason).To(Equal("restore failed"))
+				Expect(containerizer.DestroyCallCount()).To(Equal(1))
+			})
+
+			Context("when all containers are being destroyed on startup", func() {
+				BeforeEach(func() {
+					gdnr.Restorer = &gardener.NoopRestorer{}
+				})
+
+				It("does not quarantine them", func() {
+					Expect(gdnr.Start()).To(Succeed())
+					Expect(quarantiner.QuarantineCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("should return the error when it failes to get a list of handles", func() {
+			containerizer.HandlesReturns([]string{}, errors.New("banana"))
+			Expect(gdnr.Start()).To(MatchError("banana"))
+		})
+
+		It("destroys containers which have recorded resources but no bundle", func() {
+			resourceStore.HandlesReturns([]string{"container1", "lost-container"}, nil)
+
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(containerizer.DestroyCallCount()).To(Equal(1))
+			_, handle := containerizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("lost-container"))
+			Expect(resourceStore.RemoveArgsForCall(0)).To(Equal("lost-container"))
+		})
+
+		It("returns an error when it fails to list the recorded resources", func() {
+			resourceStore.HandlesReturns(nil, errors.New("banana"))
+			Expect(gdnr.Start()).To(MatchError(ContainSubstring("banana")))
+		})
+
+		It("should cleanup peas", func() {
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(fakePeaCleaner.CleanAllCallCount()).To(Equal(1))
+		})
+
+		Context("when the bulk starter fails", func() {
+			BeforeEach(func() {
+				fakePeaCleaner.CleanAllReturns(errors.New("bam"))
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.Start()).To(MatchError(ContainSubstring("bam")))
+			})
+		})
+	})
+
+	Describe("listing containers", func() {
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{"banana", "banana2", "cola"}, nil)
+		})
+
+		itOnlyMatchesFullyCreatedContainers := func(props garden.Properties) {
+			It("only matches fully created containers", func() {
+				_, err := gdnr.Containers(props)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, props := propertyManager.MatchesAllArgsForCall(0)
+				Expect(props).To(HaveKeyWithValue("garden.state", "created"))
+			})
+		}
+
+		Context("when passed nil properties to match against", func() {
+			itOnlyMatchesFullyCreatedContainers(nil)
+		})
+
+		Context("when garden.Properties are passed", func() {
+			props := garden.Properties{"somename": "somevalue"}
+
+			It("only returns matching containers", func() {
+				propertyManager.MatchesAllStub = func(handle string, props garden.Properties) bool {
+					return handle != "banana"
+				}
+
+				c, err := gdnr.Containers(props)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c).To(HaveLen(2))
+				Expect(c[0].Handle()).To(Equal("banana2"))
+				Expect(c[1].Handle()).To(Equal("cola"))
+			})
+
+			itOnlyMatchesFullyCreatedContainers(props)
+		})
+
+		Context("when labels are passed", func() {
+			BeforeEach(func() {
+				containerizer.InfoStub = func(_ lager.Logger, handle string) (spec.ActualContainerSpec, error) {
+					if handle == "banana2" {
+						return spec.ActualContainerSpec{Labels: map[string]string{"team": "other"}}, nil
+					}
+					return spec.ActualContainerSpec{Labels: map[string]string{"team": "fruit"}}, nil
+				}
+				propertyManager.MatchesAllReturns(true)
+			})
+
+			It("only returns containers with matching labels", func() {
+				c, err := gdnr.Containers(garden.Properties{"garden.label.team": "fruit", "somename": "somevalue"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c).To(HaveLen(2))
+				Expect(c[0].Handle()).To(Equal("banana"))
+				Expect(c[1].Handle()).To(Equal("cola"))
+			})
+
+			It("does not match the labels against the properties", func() {
+				_, err := gdnr.Containers(garden.Properties{"garden.label.team": "fruit", "somename": "somevalue"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, props := propertyManager.MatchesAllArgsForCall(0)
+				Expect(props).To(Equal(garden.Properties{"somename": "somevalue", "garden.state": "created"}))
+			})
+		})
+	})
+
+	Context("when no containers exist", func() {
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{}, nil)
+		})
+
+		It("should return an empty list", func() {
+			containers, err := gdnr.Containers(garden.Properties{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containers).To(BeEmpty())
+		})
+	})
+
+	Context("when the containerizer returns an error", func() {
+		testErr := errors.New("failure")
+
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{}, testErr)
+		})
+
+		It("should return an error", func() {
+			_, err := gdnr.Containers(garden.Properties{})
+			Expect(err).To(MatchError(testErr))
+		})
+	})
+
+	Describe("stopping a container", func() {
+		It("asks the containerizer to stop all processes", func() {
+			container, err := gdnr.Lookup("banana")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(container.Stop(true)).To(Succeed())
+			Expect(containerizer.StopCallCount()).To(Equal(1))
+
+			_, handle, kill := containerizer.StopArgsForCall(0)
+			Expect(handle).To(Equal("banana"))
+			Expect(kill).To(Equal(true))
+		})
+	})
+
+	Describe("listing the processes in a container", func() {
+		It("asks the containerizer for the processes", func() {
+			containerizer.ProcessesReturns([]gardener.ContainerProcess{{Pid: 1234, ProcessID: "some-process"}}, nil)
+
+			processes, err := gdnr.Processes("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processes).To(Equal([]gardener.ContainerProcess{{Pid: 1234, ProcessID: "some-process"}}))
+
+			_, handle := containerizer.ProcessesArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.Processes("banana")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(containerizer.ProcessesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the containerizer fails", func() {
+			It("returns the error", func() {
+				containerizer.ProcessesReturns(nil, errors.New("boom"))
+
+				_, err := gdnr.Processes("some-handle")
+				Expect(err).To(MatchError("boom"))
+			})
+		})
+	})
+
+	Describe("counting the file descriptors open in a container", func() {
+		var fdCounter *fakes.FakeFDCounter
+
+		BeforeEach(func() {
+			fdCounter = new(fakes.FakeFDCounter)
+			fdCounter.CountStub = func(_ lager.Logger, pid int) (int, error) {
+				if pid == 1235 {
+					return 0, errors.New("no such process")
+				}
+				return pid - 1200, nil
+			}
+			gdnr.FDCounter = fdCounter
+			gdnr.MaxOpenFilesPerProcess = 1024
+
+			containerizer.ProcessesReturns([]gardener.ContainerProcess{{Pid: 1234}, {Pid: 1235}, {Pid: 1236}}, nil)
+		})
+
+		It("counts the file descriptors of each process in the container", func() {
+			usage, err := gdnr.FDUsage("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage).To(Equal(gardener.ContainerFDUsage{
+				Open:  70,
+				Limit: 1024,
+				Processes: []gardener.ProcessFDUsage{
+					{Pid: 1234, Open: 34},
+					{Pid: 1236, Open: 36},
+				},
+			}))
+		})
+
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.FDUsage("banana")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+			})
+		})
+
+		Context("when there is no FDCounter", func() {
+			BeforeEach(func() {
+				gdnr.FDCounter = nil
+			})
+
+			It("returns an error", func() {
+				_, err := gdnr.FDUsage("some-handle")
+				Expect(err).To(MatchError("counting file descriptors is not supported"))
+			})
+		})
+	})
+
+	Describe("reading the network stats of a container", func() {
+		var networkStatser *fakes.FakeNetworkStatser
+
+		BeforeEach(func() {
+			networkStatser = new(fakes.FakeNetworkStatser)
+			networkStatser.StatsReturns(gardener.ContainerNetworkStats{RxBytes: 100, RxDropped: 2}, nil)
+			gdnr.Networker = statsNetworker{FakeNetworker: networker, FakeNetworkStatser: networkStatser}
+		})
+
+		It("asks the networker for the stats", func() {
+			stats, err := gdnr.NetworkStats("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats).To(Equal(gardener.ContainerNetworkStats{RxBytes: 100, RxDropped: 2}))
+
+			_, handle := networkStatser.StatsArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		It("tags the stats with the container's labels", func() {
+			containerizer.InfoReturns(spec.ActualContainerSpec{Labels: map[string]string{"app": "banana"}}, nil)
+
+			stats, err := gdnr.NetworkStats("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Labels).To(Equal(map[string]string{"app": "banana"}))
+		})
+
+		It("reads the labels from the container's bundle only once", func() {
+			_, err := gdnr.NetworkStats("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = gdnr.NetworkStats("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerizer.InfoCallCount()).To(Equal(1))
+		})
+
+		Context("when the labels cannot be read", func() {
+			It("returns the error", func() {
+				containerizer.InfoReturns(spec.ActualContainerSpec{}, errors.New("no-bundle"))
+
+				_, err := gdnr.NetworkStats("some-handle")
+				Expect(err).To(MatchError("no-bundle"))
+			})
+		})
+
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.NetworkStats("banana")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(networkStatser.StatsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the networker does not report network stats", func() {
+			BeforeEach(func() {
+				gdnr.Networker = networker
+			})
+
+			It("returns an error", func() {
+				_, err := gdnr.NetworkStats("some-handle")
+				Expect(err).To(MatchError("the networker does not report network stats"))
+			})
+		})
+	})
+
+	Describe("reading the labelled metrics of a container", func() {
+		BeforeEach(func() {
+			containerizer.MetricsReturns(gardener.ActualContainerMetrics{
+				Memory: garden.ContainerMemoryStat{Cache: 10},
+			}, nil)
+			containerizer.InfoReturns(spec.ActualContainerSpec{Labels: map[string]string{"app": "banana"}}, nil)
+		})
+
+		It("returns the container's metrics tagged with its labels", func() {
+			metrics, err := gdnr.LabelledMetrics("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metrics.MemoryStat.Cache).To(BeEquivalentTo(10))
+			Expect(metrics.Labels).To(Equal(map[string]string{"app": "banana"}))
+			Expect(metrics.FDUsage).To(BeNil())
+		})
+
+		Context("when file descriptors can be counted", func() {
+			BeforeEach(func() {
+				fdCounter := new(fakes.FakeFDCounter)
+				fdCounter.CountReturns(12, nil)
+				gdnr.FDCounter = fdCounter
+				gdnr.MaxOpenFilesPerProcess = 1024
+
+				containerizer.ProcessesReturns([]gardener.ContainerProcess{{Pid: 1234}}, nil)
+			})
+
+			It("includes the file descriptors open in the container", func() {
+				metrics, err := gdnr.LabelledMetrics("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metrics.FDUsage).To(Equal(&gardener.ContainerFDUsage{
+					Open:      12,
+					Limit:     1024,
+					Processes: []gardener.ProcessFDUsage{{Pid: 1234, Open: 12}},
+				}))
+			})
 
-					_, _, err := container.NetIn(externalPort, contianerPort)
+			Context("when the processes cannot be listed", func() {
+				It("returns the error", func() {
+					containerizer.ProcessesReturns(nil, errors.New("no-processes"))
 
-					Expect(err).To(MatchError("error"))
+					_, err := gdnr.LabelledMetrics("some-handle")
+					Expect(err).To(MatchError("no-processes"))
 				})
 			})
 		})
 
-		Describe("NetOut", func() {
-			var rule garden.NetOutRule
-
-			BeforeEach(func() {
-				rule = garden.NetOutRule{
-					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("8.2.3.4"))},
-					Ports:    []garden.PortRange{garden.PortRangeFromPort(9321)},
-				}
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.LabelledMetrics("banana")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
 			})
+		})
 
-			It("asks the networker to apply the provided netout rule", func() {
-				Expect(container.NetOut(rule)).To(Succeed())
-				Expect(networker.NetOutCallCount()).To(Equal(1))
+		Context("when reading the metrics fails", func() {
+			It("returns the error", func() {
+				containerizer.MetricsReturns(gardener.ActualContainerMetrics{}, errors.New("no-metrics"))
 
-				_, handle, actualRule := networker.NetOutArgsForCall(0)
-				Expect(handle).To(Equal("banana"))
-				Expect(actualRule).To(Equal(rule))
+				_, err := gdnr.LabelledMetrics("some-handle")
+				Expect(err).To(MatchError("no-metrics"))
 			})
+		})
+	})
 
-			Context("when networker returns an error", func() {
-				It("return the error", func() {
-					networker.NetOutReturns(fmt.Errorf("banana republic"))
-					Expect(container.NetOut(rule)).To(MatchError("banana republic"))
-				})
-			})
+	Describe("network reservations", func() {
+		var networkReserver *fakes.FakeNetworkReserver
+
+		BeforeEach(func() {
+			networkReserver = new(fakes.FakeNetworkReserver)
+			networkReserver.ReservationsReturns(map[string]gardener.NetworkReservation{
+				"some-name": {Network: "10.0.0.5/30"},
+			}, nil)
+			gdnr.Networker = reservingNetworker{FakeNetworker: networker, FakeNetworkReserver: networkReserver}
 		})
 
-		Describe("BulkNetOut", func() {
-			var rules []garden.NetOutRule
+		It("lists the reservations of the networker", func() {
+			reservations, err := gdnr.NetworkReservations()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reservations).To(Equal(map[string]gardener.NetworkReservation{
+				"some-name": {Network: "10.0.0.5/30"},
+			}))
+		})
+
+		It("releases reservations through the networker", func() {
+			Expect(gdnr.ReleaseNetworkReservation("some-name")).To(Succeed())
+
+			Expect(networkReserver.ReleaseReservationCallCount()).To(Equal(1))
+			_, name := networkReserver.ReleaseReservationArgsForCall(0)
+			Expect(name).To(Equal("some-name"))
+		})
 
+		Context("when releasing the reservation fails", func() {
 			BeforeEach(func() {
-				rules = []garden.NetOutRule{
-					{
-						Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("8.2.3.4"))},
-						Ports:    []garden.PortRange{garden.PortRangeFromPort(9321)},
-					},
-					{
-						Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("6.1.2.3"))},
-						Ports:    []garden.PortRange{garden.PortRangeFromPort(7654)},
-					},
-				}
+				networkReserver.ReleaseReservationReturns(errors.New("in use"))
 			})
 
-			It("asks the networker to apply the provided netout rule", func() {
-				Expect(container.BulkNetOut(rules)).To(Succeed())
-				Expect(networker.BulkNetOutCallCount()).To(Equal(1))
+			It("returns the error", func() {
+				Expect(gdnr.ReleaseNetworkReservation("some-name")).To(MatchError("in use"))
+			})
+		})
 
-				_, handle, actualRules := networker.BulkNetOutArgsForCall(0)
-				Expect(handle).To(Equal("banana"))
-				Expect(actualRules).To(ConsistOf(rules))
+		Context("when the networker does not support reservations", func() {
+			BeforeEach(func() {
+				gdnr.Networker = networker
 			})
 
-			Context("when networker returns an error", func() {
-				It("return the error", func() {
-					networker.BulkNetOutReturns(fmt.Errorf("banana republic"))
-					Expect(container.BulkNetOut(rules)).To(MatchError("banana republic"))
-				})
+			It("returns an error", func() {
+				_, err := gdnr.NetworkReservations()
+				Expect(err).To(MatchError("the networker does not support network reservations"))
+				Expect(gdnr.ReleaseNetworkReservation("some-name")).To(MatchError("the networker does not support network reservations"))
 			})
 		})
 	})
 
-	Describe("starting up gardener", func() {
+	Describe("committing a container", func() {
+		var rootfsCommitter *fakes.FakeRootfsCommitter
+
 		BeforeEach(func() {
-			containers := []string{"container1", "container2"}
-			containerizer.HandlesReturns(containers, nil)
+			rootfsCommitter = new(fakes.FakeRootfsCommitter)
+			rootfsCommitter.CommitReturns("/images/some-image", nil)
+			gdnr.RootfsCommitter = rootfsCommitter
+
+			containerizer.InfoReturns(spec.ActualContainerSpec{Pid: 470, RootFSPath: "/path/to/rootfs", Stopped: true}, nil)
 		})
 
-		It("calls the bulk starter", func() {
-			Expect(gdnr.Start()).To(Succeed())
+		It("commits the container's rootfs to the named image", func() {
+			rootfsPath, err := gdnr.Commit("some-handle", "some-image")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rootfsPath).To(Equal("/images/some-image"))
 
-			Expect(fakeBulkStarter.StartAllCallCount()).To(Equal(1))
+			Expect(rootfsCommitter.CommitCallCount()).To(Equal(1))
+			_, actualRootfsPath, name := rootfsCommitter.CommitArgsForCall(0)
+			Expect(actualRootfsPath).To(Equal("/path/to/rootfs"))
+			Expect(name).To(Equal("some-image"))
 		})
 
-		Context("when the bulk starter fails", func() {
+		Context("when the container is not stopped", func() {
 			BeforeEach(func() {
-				fakeBulkStarter.StartAllReturns(errors.New("boom"))
+				containerizer.InfoReturns(spec.ActualContainerSpec{Pid: 470, RootFSPath: "/path/to/rootfs"}, nil)
 			})
 
-			It("returns the error", func() {
-				Expect(gdnr.Start()).To(MatchError(ContainSubstring("boom")))
+			It("returns an error without committing", func() {
+				_, err := gdnr.Commit("some-handle", "some-image")
+				Expect(err).To(MatchError("container 'some-handle' must be stopped before it is committed"))
+				Expect(rootfsCommitter.CommitCallCount()).To(Equal(0))
 			})
+		})
 
-			It("does not restore the containers", func() {
-				Expect(gdnr.Start()).NotTo(Succeed())
-				Expect(restorer.RestoreCallCount()).To(Equal(0))
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.Commit("banana", "some-image")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(rootfsCommitter.CommitCallCount()).To(Equal(0))
 			})
 		})
 
-		It("should restore the containers", func() {
-			Expect(gdnr.Start()).To(Succeed())
-			Expect(restorer.RestoreCallCount()).To(Equal(1))
-			_, handles := restorer.RestoreArgsForCall(0)
-			Expect(handles).To(Equal([]string{"container1", "container2"}))
+		Context("when the image name is not a plain file name", func() {
+			It("returns an error without committing", func() {
+				_, err := gdnr.Commit("some-handle", "../some-image")
+				Expect(err).To(MatchError("invalid image name: '../some-image'"))
+				Expect(rootfsCommitter.CommitCallCount()).To(Equal(0))
+			})
 		})
 
-		It("should blow up containers that couldn't restore", func() {
-			restorer.RestoreReturns([]string{"container2"})
-			Expect(gdnr.Start()).To(Succeed())
-			Expect(containerizer.DestroyCallCount()).To(Equal(1))
-			_, handle := containerizer.DestroyArgsForCall(0)
-			Expect(handle).To(Equal("container2"))
+		Context("when getting the container's info fails", func() {
+			It("returns the error", func() {
+				containerizer.InfoReturns(spec.ActualContainerSpec{}, errors.New("no-info"))
+				_, err := gdnr.Commit("some-handle", "some-image")
+				Expect(err).To(MatchError("no-info"))
+			})
 		})
 
-		It("should return the error when it failes to get a list of handles", func() {
-			containerizer.HandlesReturns([]string{}, errors.New("banana"))
-			Expect(gdnr.Start()).To(MatchError("banana"))
+		Context("when committing fails", func() {
+			It("returns the error", func() {
+				rootfsCommitter.CommitReturns("", errors.New("boom"))
+				_, err := gdnr.Commit("some-handle", "some-image")
+				Expect(err).To(MatchError("boom"))
+			})
 		})
+	})
 
-		It("should cleanup peas", func() {
-			Expect(gdnr.Start()).To(Succeed())
-			Expect(fakePeaCleaner.CleanAllCallCount()).To(Equal(1))
-		})
+	Describe("attaching to a process", func() {
+		It("attaches to the process in the container", func() {
+			process := new(gardenfakes.FakeProcess)
+			containerizer.AttachReturns(process, nil)
 
-		Context("when the bulk starter fails", func() {
-			BeforeEach(func() {
-				fakePeaCleaner.CleanAllReturns(errors.New("bam"))
-			})
+			attached, err := gdnr.AttachProcess("some-handle", "some-process", garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attached).To(Equal(process))
 
-			It("returns the error", func() {
-				Expect(gdnr.Start()).To(MatchError(ContainSubstring("bam")))
+			Expect(containerizer.AttachCallCount()).To(Equal(1))
+			_, handle, processID, _ := containerizer.AttachArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(processID).To(Equal("some-process"))
+		})
+
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.AttachProcess("banana", "some-process", garden.ProcessIO{})
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(containerizer.AttachCallCount()).To(Equal(0))
 			})
 		})
 	})
 
-	Describe("listing containers", func() {
+	Describe("copying between containers", func() {
+		var (
+			copySpec  gardener.CopySpec
+			tarStream *gbytes.Buffer
+		)
+
 		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{"banana", "banana2", "cola"}, nil)
+			containerizer.HandlesReturns([]string{"src-handle", "dst-handle"}, nil)
+
+			tarStream = gbytes.BufferWithBytes([]byte("some-tar"))
+			containerizer.StreamOutReturns(tarStream, nil)
+
+			copySpec = gardener.CopySpec{
+				SrcHandle: "src-handle",
+				SrcPath:   "/src/path",
+				DstHandle: "dst-handle",
+				DstPath:   "/dst/path",
+				User:      "alice",
+			}
 		})
 
-		itOnlyMatchesFullyCreatedContainers := func(props garden.Properties) {
-			It("only matches fully created containers", func() {
-				_, err := gdnr.Containers(props)
-				Expect(err).NotTo(HaveOccurred())
+		It("streams the path out of the source container into the destination container", func() {
+			Expect(gdnr.Copy(copySpec)).To(Succeed())
 
-				_, props := propertyManager.MatchesAllArgsForCall(0)
-				Expect(props).To(HaveKeyWithValue("garden.state", "created"))
+			Expect(containerizer.StreamOutCallCount()).To(Equal(1))
+			_, handle, streamOutSpec := containerizer.StreamOutArgsForCall(0)
+			Expect(handle).To(Equal("src-handle"))
+			Expect(streamOutSpec).To(Equal(garden.StreamOutSpec{Path: "/src/path", User: "alice"}))
+
+			Expect(containerizer.StreamInCallCount()).To(Equal(1))
+			_, handle, streamInSpec := containerizer.StreamInArgsForCall(0)
+			Expect(handle).To(Equal("dst-handle"))
+			Expect(streamInSpec.Path).To(Equal("/dst/path"))
+			Expect(streamInSpec.User).To(Equal("alice"))
+			Expect(streamInSpec.TarStream).To(Equal(tarStream))
+		})
+
+		It("closes the stream", func() {
+			Expect(gdnr.Copy(copySpec)).To(Succeed())
+			Expect(tarStream.Closed()).To(BeTrue())
+		})
+
+		Context("when either container does not exist", func() {
+			It("returns a ContainerNotFoundError without streaming", func() {
+				copySpec.DstHandle = "banana"
+				Expect(gdnr.Copy(copySpec)).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(containerizer.StreamOutCallCount()).To(Equal(0))
 			})
-		}
+		})
 
-		Context("when passed nil properties to match against", func() {
-			itOnlyMatchesFullyCreatedContainers(nil)
+		Context("when streaming out fails", func() {
+			BeforeEach(func() {
+				containerizer.StreamOutReturns(nil, errors.New("no-such-file"))
+			})
+
+			It("returns the error without streaming in", func() {
+				Expect(gdnr.Copy(copySpec)).To(MatchError("streaming out of src-handle: no-such-file"))
+				Expect(containerizer.StreamInCallCount()).To(Equal(0))
+			})
 		})
 
-		Context("when garden.Properties are passed", func() {
-			props := garden.Properties{"somename": "somevalue"}
+		Context("when streaming in fails", func() {
+			BeforeEach(func() {
+				containerizer.StreamInReturns(errors.New("read-only"))
+			})
 
-			It("only returns matching containers", func() {
-				propertyManager.MatchesAllStub = func(handle string, props garden.Properties) bool {
-					return handle != "banana"
-				}
+			It("returns the error and closes the stream", func() {
+				Expect(gdnr.Copy(copySpec)).To(MatchError("streaming into dst-handle: read-only"))
+				Expect(tarStream.Closed()).To(BeTrue())
+			})
+		})
 
-				c, err := gdnr.Containers(props)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(c).To(HaveLen(2))
-				Expect(c[0].Handle()).To(Equal("banana2"))
-				Expect(c[1].Handle()).To(Equal("cola"))
+		Context("when streaming out fails after it has started", func() {
+			BeforeEach(func() {
+				containerizer.StreamOutReturns(failingCloser{tarStream}, nil)
 			})
 
-			itOnlyMatchesFullyCreatedContainers(props)
+			It("returns the error from closing the stream", func() {
+				Expect(gdnr.Copy(copySpec)).To(MatchError("streaming out of src-handle: tar-failed"))
+			})
 		})
 	})
 
-	Context("when no containers exist", func() {
+	Describe("inspecting files in a container", func() {
+		var fileInspector *fakes.FakeFileInspector
+
 		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{}, nil)
+			fileInspector = new(fakes.FakeFileInspector)
+			fileInspector.StatReturns(gardener.ContainerFileInfo{Name: "config", Size: 5}, nil)
+			fileInspector.ListReturns([]gardener.ContainerFileInfo{{Name: "config"}, {Name: "data"}}, nil)
+			gdnr.FileInspector = fileInspector
+
+			containerizer.InfoReturns(spec.ActualContainerSpec{Pid: 470}, nil)
 		})
 
-		It("should return an empty list", func() {
-			containers, err := gdnr.Containers(garden.Properties{})
+		It("stats the path as seen by the container's init process", func() {
+			info, err := gdnr.StatFile("some-handle", "/etc/config")
 			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(gardener.ContainerFileInfo{Name: "config", Size: 5}))
 
-			Expect(containers).To(BeEmpty())
+			Expect(fileInspector.StatCallCount()).To(Equal(1))
+			_, pid, path := fileInspector.StatArgsForCall(0)
+			Expect(pid).To(Equal(470))
+			Expect(path).To(Equal("/etc/config"))
 		})
-	})
 
-	Context("when the containerizer returns an error", func() {
-		testErr := errors.New("failure")
+		It("lists the directory as seen by the container's init process", func() {
+			infos, err := gdnr.ListFiles("some-handle", "/etc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infos).To(HaveLen(2))
 
-		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{}, testErr)
+			Expect(fileInspector.ListCallCount()).To(Equal(1))
+			_, pid, path := fileInspector.ListArgsForCall(0)
+			Expect(pid).To(Equal(470))
+			Expect(path).To(Equal("/etc"))
 		})
 
-		It("should return an error", func() {
-			_, err := gdnr.Containers(garden.Properties{})
-			Expect(err).To(MatchError(testErr))
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				_, err := gdnr.StatFile("banana", "/etc/config")
+				Expect(err).To(MatchError(garden.ContainerNotFoundError{Handle: "banana"}))
+				Expect(fileInspector.StatCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the container is stopped", func() {
+			BeforeEach(func() {
+				containerizer.InfoReturns(spec.ActualContainerSpec{Pid: 470, Stopped: true}, nil)
+			})
+
+			It("returns an error without inspecting", func() {
+				_, err := gdnr.ListFiles("some-handle", "/etc")
+				Expect(err).To(MatchError("container 'some-handle' is not running"))
+				Expect(fileInspector.ListCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when there is no file inspector", func() {
+			BeforeEach(func() {
+				gdnr.FileInspector = nil
+			})
+
+			It("returns an error", func() {
+				_, err := gdnr.StatFile("some-handle", "/etc/config")
+				Expect(err).To(MatchError("inspecting container files is not supported"))
+			})
+		})
+
+		Context("when getting the container's info fails", func() {
+			It("returns the error", func() {
+				containerizer.InfoReturns(spec.ActualContainerSpec{}, errors.New("no-info"))
+				_, err := gdnr.StatFile("some-handle", "/etc/config")
+				Expect(err).To(MatchError("no-info"))
+			})
 		})
 	})
 
-	Describe("stopping a container", func() {
-		It("asks the containerizer to stop all processes", func() {
-			container, err := gdnr.Lookup("banana")
-			Expect(err).NotTo(HaveOccurred())
+	Describe("orphaned resources", func() {
+		BeforeEach(func() {
+			resourceStore.HandlesReturns([]string{"some-handle", "orphan"}, nil)
+		})
 
-			Expect(container.Stop(true)).To(Succeed())
-			Expect(containerizer.StopCallCount()).To(Equal(1))
+		It("reports the recorded handles which have no container", func() {
+			Expect(gdnr.Orphans(logger, []string{"some-handle"})).To(ConsistOf("orphan"))
+		})
+
+		It("destroys everything belonging to an orphan", func() {
+			Expect(gdnr.CleanOrphan(logger, "orphan")).To(Succeed())
+
+			_, handle := networker.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("orphan"))
+			_, handle = volumizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("orphan"))
+			Expect(resourceStore.RemoveArgsForCall(0)).To(Equal("orphan"))
+		})
+
+		Context("when the orphan's properties have been lost", func() {
+			var (
+				networkReleaser *fakes.FakeRecordedNetworkReleaser
+				cgroupRemover   *fakes.FakeCgroupRemover
+				recorded        gardener.ContainerResources
+			)
+
+			BeforeEach(func() {
+				networkReleaser = new(fakes.FakeRecordedNetworkReleaser)
+				gdnr.Networker = releasingNetworker{networker, networkReleaser}
+
+				cgroupRemover = new(fakes.FakeCgroupRemover)
+				gdnr.CgroupRemover = cgroupRemover
+
+				propertyManager.AllReturns(garden.Properties{}, nil)
+
+				recorded = gardener.ContainerResources{
+					VolumePath:  "/path/to/rootfs",
+					CgroupPath:  "garden/orphan",
+					ContainerIP: "10.0.0.2",
+					Subnet:      "10.0.0.0/30",
+					BridgeIP:    "10.0.0.1",
+					HostPorts:   []uint32{61001},
+				}
+				resourceStore.GetStub = func(string) (gardener.ContainerResources, error) {
+					return recorded, nil
+				}
+				resourceStore.SetStub = func(_ string, resources gardener.ContainerResources) error {
+					recorded = resources
+					return nil
+				}
+			})
+
+			It("releases the recorded network instead of destroying it", func() {
+				Expect(gdnr.CleanOrphan(logger, "orphan")).To(Succeed())
+
+				Expect(networker.DestroyCallCount()).To(Equal(0))
+				Expect(networkReleaser.ReleaseRecordedCallCount()).To(Equal(1))
+				_, handle, resources := networkReleaser.ReleaseRecordedArgsForCall(0)
+				Expect(handle).To(Equal("orphan"))
+				Expect(resources.ContainerIP).To(Equal("10.0.0.2"))
+				Expect(resources.Subnet).To(Equal("10.0.0.0/30"))
+				Expect(resources.HostPorts).To(Equal([]uint32{61001}))
+			})
+
+			It("destroys the volume", func() {
+				Expect(gdnr.CleanOrphan(logger, "orphan")).To(Succeed())
+				_, handle := volumizer.DestroyArgsForCall(0)
+				Expect(handle).To(Equal("orphan"))
+			})
+
+			It("removes the recorded cgroup", func() {
+				Expect(gdnr.CleanOrphan(logger, "orphan")).To(Succeed())
+				Expect(cgroupRemover.RemoveCgroupCallCount()).To(Equal(1))
+				_, cgroupPath := cgroupRemover.RemoveCgroupArgsForCall(0)
+				Expect(cgroupPath).To(Equal("garden/orphan"))
+			})
+
+			Context("when a later step fails", func() {
+				BeforeEach(func() {
+					containerizer.RemoveBundleReturnsOnCall(0, errors.New("busy"))
+				})
+
+				It("does not release the network again when the clean is retried", func() {
+					Expect(gdnr.CleanOrphan(logger, "orphan")).To(MatchError("busy"))
+					Expect(gdnr.CleanOrphan(logger, "orphan")).To(Succeed())
+					Expect(networkReleaser.ReleaseRecordedCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when releasing the recorded network fails", func() {
+				BeforeEach(func() {
+					networkReleaser.ReleaseRecordedReturns(errors.New("release failed"))
+				})
+
+				It("returns the error and keeps the record", func() {
+					Expect(gdnr.CleanOrphan(logger, "orphan")).To(MatchError("release failed"))
+					Expect(resourceStore.RemoveCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("does not report handles which are queued for deferred cleanup", func() {
+			resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+				return gardener.ContainerResources{DestroyPending: handle == "orphan"}, nil
+			}
+
+			Expect(gdnr.Orphans(logger, []string{"some-handle"})).To(BeEmpty())
+		})
 
-			_, handle, kill := containerizer.StopArgsForCall(0)
-			Expect(handle).To(Equal("banana"))
-			Expect(kill).To(Equal(true))
+		Context("when the recorded handles cannot be listed", func() {
+			BeforeEach(func() {
+				resourceStore.HandlesReturns(nil, errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				_, err := gdnr.Orphans(logger, []string{})
+				Expect(err).To(MatchError(ContainSubstring("boom")))
+			})
 		})
 	})
 
@@ -832,12 +3073,151 @@ var _ = Describe("Gardener", func() {
 			Expect(propertyManager.DestroyKeySpaceArgsForCall(0)).To(Equal("some-handle"))
 		})
 
+		It("runs the post-destroy hooks once the container is gone", func() {
+			removedResources := make(chan int, 1)
+			hook := new(fakes.FakeContainerHook)
+			hook.PostDestroyStub = func(lager.Logger, string) error {
+				removedResources <- resourceStore.RemoveCallCount()
+				return nil
+			}
+			gdnr.ContainerHooks = []gardener.ContainerHook{hook}
+
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			Eventually(removedResources).Should(Receive(Equal(1)))
+			_, handle := hook.PostDestroyArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		It("does not wait for the post-destroy hooks", func() {
+			release := make(chan struct{})
+			defer close(release)
+
+			hook := new(fakes.FakeContainerHook)
+			hook.PostDestroyStub = func(lager.Logger, string) error {
+				<-release
+				return nil
+			}
+			gdnr.ContainerHooks = []gardener.ContainerHook{hook}
+
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+		})
+
+		It("still succeeds when a post-destroy hook fails", func() {
+			hook := new(fakes.FakeContainerHook)
+			hook.PostDestroyReturns(errors.New("inventory is down"))
+			gdnr.ContainerHooks = []gardener.ContainerHook{hook}
+
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			Eventually(hook.PostDestroyCallCount).Should(Equal(1))
+		})
+
+		It("removes the container's core dumps", func() {
+			coreDumpCollector := new(fakes.FakeCoreDumpCollector)
+			gdnr.CoreDumpCollector = coreDumpCollector
+
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			Expect(coreDumpCollector.RemoveCallCount()).To(Equal(1))
+			_, handle := coreDumpCollector.RemoveArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
 		It("asks the containerizer to remove the bundle from the depot", func() {
 			Expect(gdnr.Destroy("some-handle")).To(Succeed())
 			_, handle := containerizer.RemoveBundleArgsForCall(0)
 			Expect(handle).To(Equal("some-handle"))
 		})
 
+		It("notifies that the container was destroyed with the properties it had", func() {
+			propertyManager.AllReturns(garden.Properties{"foo": "bar"}, nil)
+
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+			Expect(lifecycle.NotifyCallCount()).To(Equal(1))
+			_, event := lifecycle.NotifyArgsForCall(0)
+			Expect(event).To(Equal(gardener.LifecycleEvent{
+				Type:       gardener.ContainerDestroyedEvent,
+				Handle:     "some-handle",
+				Properties: garden.Properties{"foo": "bar"},
+			}))
+		})
+
+		It("removes the recorded resources", func() {
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			Expect(resourceStore.RemoveCallCount()).To(Equal(1))
+			Expect(resourceStore.RemoveArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Context("when destroying any of the resources fails", func() {
+			BeforeEach(func() {
+				volumizer.DestroyReturns(errors.New("rootfs deletion failed"))
+			})
+
+			It("keeps the recorded resources so that the destroy can be retried", func() {
+				Expect(gdnr.Destroy("some-handle")).NotTo(Succeed())
+				Expect(resourceStore.RemoveCallCount()).To(Equal(0))
+			})
+
+			It("does not notify", func() {
+				Expect(gdnr.Destroy("some-handle")).NotTo(Succeed())
+				Expect(lifecycle.NotifyCallCount()).To(Equal(0))
+			})
+
+			Context("and failed destroys are deferred", func() {
+				BeforeEach(func() {
+					gdnr.DeferFailedDestroys = true
+					resourceStore.GetReturns(gardener.ContainerResources{VolumePath: "/some/volume"}, nil)
+				})
+
+				It("succeeds", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+				})
+
+				It("queues the container for deferred cleanup", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+					Expect(resourceStore.SetCallCount()).To(Equal(1))
+					handle, resources := resourceStore.SetArgsForCall(0)
+					Expect(handle).To(Equal("some-handle"))
+					Expect(resources).To(Equal(gardener.ContainerResources{VolumePath: "/some/volume", DestroyPending: true}))
+				})
+
+				It("hides the container from listings", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+					Expect(propertyManager.SetCallCount()).To(Equal(1))
+					handle, name, value := propertyManager.SetArgsForCall(0)
+					Expect(handle).To(Equal("some-handle"))
+					Expect(name).To(Equal("garden.state"))
+					Expect(value).To(Equal("destroying"))
+				})
+
+				It("notifies that the container was destroyed", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+					Expect(lifecycle.NotifyCallCount()).To(Equal(1))
+				})
+
+				Context("when the container cannot be queued", func() {
+					BeforeEach(func() {
+						resourceStore.SetReturns(errors.New("disk full"))
+					})
+
+					It("returns the original error", func() {
+						Expect(gdnr.Destroy("some-handle")).To(MatchError("rootfs deletion failed"))
+					})
+				})
+			})
+		})
+
+		Context("when removing the recorded resources fails", func() {
+			BeforeEach(func() {
+				resourceStore.RemoveReturns(errors.New("remove failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.Destroy("some-handle")).To(MatchError("remove failed"))
+			})
+		})
+
 		Context("when containerizer fails to destroy the container", func() {
 			BeforeEach(func() {
 				containerizer.DestroyReturns(errors.New("containerized deletion failed"))
@@ -848,6 +3228,18 @@ var _ = Describe("Gardener", func() {
 				Expect(err).To(MatchError("containerized deletion failed"))
 			})
 
+			Context("and failed destroys are deferred", func() {
+				BeforeEach(func() {
+					gdnr.DeferFailedDestroys = true
+				})
+
+				It("still returns the error, as the container may still be running", func() {
+					Expect(gdnr.Destroy("some-handle")).To(MatchError("containerized deletion failed"))
+					Expect(resourceStore.SetCallCount()).To(Equal(0))
+					Expect(lifecycle.NotifyCallCount()).To(Equal(0))
+				})
+			})
+
 			It("should not destroy the network configuration", func() {
 				err := gdnr.Destroy("some-handle")
 				Expect(err).To(HaveOccurred())
@@ -856,6 +3248,38 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		Context("when containerizer fails to delete a container whose init process has exited", func() {
+			BeforeEach(func() {
+				containerizer.DestroyReturns(gardener.ExitedContainerDestroyError{Err: errors.New("cgroup busy")})
+			})
+
+			It("returns the error", func() {
+				Expect(gdnr.Destroy("some-handle")).To(MatchError("cgroup busy"))
+			})
+
+			Context("and failed destroys are deferred", func() {
+				BeforeEach(func() {
+					gdnr.DeferFailedDestroys = true
+				})
+
+				It("succeeds, queueing the container for deferred cleanup", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+					Expect(resourceStore.SetCallCount()).To(Equal(1))
+					_, resources := resourceStore.SetArgsForCall(0)
+					Expect(resources.DestroyPending).To(BeTrue())
+					Expect(lifecycle.NotifyCallCount()).To(Equal(1))
+				})
+
+				It("leaves the release of its other resources to the retry", func() {
+					Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+					Expect(networker.DestroyCallCount()).To(Equal(0))
+					Expect(volumizer.DestroyCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when containerizer fails to remove the bundle from the depot", func() {
 			BeforeEach(func() {
 				containerizer.DestroyReturns(errors.New("containerized deletion failed"))
@@ -865,6 +3289,18 @@ var _ = Describe("Gardener", func() {
 				err := gdnr.Destroy("some-handle")
 				Expect(err).To(MatchError("containerized deletion failed"))
 			})
+
+			Context("and failed destroys are deferred", func() {
+				BeforeEach(func() {
+					gdnr.DeferFailedDestroys = true
+				})
+
+				It("still returns the error, as the container may still be running", func() {
+					Expect(gdnr.Destroy("some-handle")).To(MatchError("containerized deletion failed"))
+					Expect(resourceStore.SetCallCount()).To(Equal(0))
+					Expect(lifecycle.NotifyCallCount()).To(Equal(0))
+				})
+			})
 		})
 
 		Context("when network deletion fails", func() {
@@ -935,6 +3371,21 @@ var _ = Describe("Gardener", func() {
 			Expect(capacity.MaxContainers).To(BeEquivalentTo(1000))
 		})
 
+		Context("when memory and disk are reserved for the system", func() {
+			BeforeEach(func() {
+				gdnr.ReservedMemoryPercent = 10
+				gdnr.ReservedDiskPercent = 50
+			})
+
+			It("leaves them out of the capacity", func() {
+				capacity, err := gdnr.Capacity()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(capacity.MemoryInBytes).To(BeEquivalentTo(900))
+				Expect(capacity.DiskInBytes).To(BeEquivalentTo(444))
+			})
+		})
+
 		Context("when MaxContainers is not set ", func() {
 			BeforeEach(func() {
 				gdnr.MaxContainers = 0
@@ -1039,6 +3490,44 @@ var _ = Describe("Gardener", func() {
 			Expect(handle).To(Equal("some-handle"))
 			Expect(name).To(Equal("name"))
 		})
+
+		Context("when the property manager can report that it failed to store a property", func() {
+			var propertyStorer *fakes.FakePropertyStorer
+
+			BeforeEach(func() {
+				propertyStorer = new(fakes.FakePropertyStorer)
+				propertyStorer.StoreReturns(errors.New("consul is down"))
+				gdnr.PropertyManager = storingPropertyManager{propertyManager, propertyStorer}
+
+				var err error
+				container, err = gdnr.Lookup("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the error from SetProperty", func() {
+				Expect(container.SetProperty("name", "value")).To(MatchError("consul is down"))
+
+				Expect(propertyStorer.StoreCallCount()).To(Equal(1))
+				handle, prop, val := propertyStorer.StoreArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(prop).To(Equal("name"))
+				Expect(val).To(Equal("value"))
+			})
+
+			It("returns the error from SetGraceTime", func() {
+				Expect(container.SetGraceTime(time.Minute)).To(MatchError("consul is down"))
+			})
+		})
+
+		It("does not allow labels to be set", func() {
+			Expect(container.SetProperty("garden.label.billing-id", "free")).To(MatchError(gardener.ImmutableLabelError{Name: "garden.label.billing-id"}))
+			Expect(propertyManager.SetCallCount()).To(Equal(0))
+		})
+
+		It("does not allow labels to be removed", func() {
+			Expect(container.RemoveProperty("garden.label.billing-id")).To(MatchError(ContainSubstring("cannot modify label 'billing-id'")))
+			Expect(propertyManager.RemoveCallCount()).To(Equal(0))
+		})
 	})
 
 	Describe("Info", func() {
@@ -1115,6 +3604,21 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		It("returns the container's labels among its properties", func() {
+			containerProperties := garden.Properties{"spider": "man"}
+			propertyManager.AllReturns(containerProperties, nil)
+			containerizer.InfoReturns(spec.ActualContainerSpec{Labels: map[string]string{"billing-id": "1234"}}, nil)
+
+			info, err := container.Info()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(info.Properties).To(Equal(garden.Properties{
+				"spider":                  "man",
+				"garden.label.billing-id": "1234",
+			}))
+			Expect(containerProperties).To(Equal(garden.Properties{"spider": "man"}))
+		})
+
 		It("returns the container properties", func() {
 			propertyManager.AllReturns(garden.Properties{
 				"spider": "man",
@@ -1336,6 +3840,48 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		Context("when the networker reports network stats", func() {
+			var networkStatser *fakes.FakeNetworkStatser
+
+			BeforeEach(func() {
+				networkStatser = new(fakes.FakeNetworkStatser)
+				networkStatser.StatsReturns(gardener.ContainerNetworkStats{RxBytes: 100, TxBytes: 200, RxPackets: 3}, nil)
+				gdnr.Networker = statsNetworker{FakeNetworker: networker, FakeNetworkStatser: networkStatser}
+
+				var err error
+				container, err = gdnr.Lookup("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should return the network byte counts", func() {
+				metrics, err := container.Metrics()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics.NetworkStat).To(Equal(garden.ContainerNetworkStat{RxBytes: 100, TxBytes: 200}))
+				_, handle := networkStatser.StatsArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+			})
+
+			Context("when the network stats cannot be read", func() {
+				BeforeEach(func() {
+					networkStatser.StatsReturns(gardener.ContainerNetworkStats{}, errors.New("no-interface"))
+				})
+
+				It("returns the other metrics, without network stats", func() {
+					metrics, err := container.Metrics()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(metrics.CPUStat).To(Equal(cpuStat))
+					Expect(metrics.NetworkStat).To(BeZero())
+				})
+
+				It("logs the error", func() {
+					container.Metrics()
+					Expect(logger).To(gbytes.Say("reading-network-stats-failed.*no-interface"))
+				})
+			})
+		})
+
 		It("should return BulkMetrics", func() {
 			containerizer.MetricsStub = func(_ lager.Logger, id string) (gardener.ActualContainerMetrics, error) {
 				if id == "potato" {
@@ -1413,6 +3959,158 @@ var _ = Describe("Gardener", func() {
 				Expect(err).To(MatchError("some-error"))
 			})
 		})
+
+		Describe("updating limits", func() {
+			var limitUpdater *fakes.FakeLimitUpdater
+
+			BeforeEach(func() {
+				limitUpdater = new(fakes.FakeLimitUpdater)
+				gdnr.Containerizer = limitUpdatingContainerizer{FakeContainerizer: containerizer, FakeLimitUpdater: limitUpdater}
+
+				var err error
+				container, err = gdnr.Lookup("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("asks the containerizer to update the memory limit", func() {
+				Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1024})).To(Succeed())
+
+				Expect(limitUpdater.UpdateLimitsCallCount()).To(Equal(1))
+				_, handle, limits := limitUpdater.UpdateLimitsArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(limits).To(Equal(garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 1024}}))
+			})
+
+			It("asks the containerizer to update the CPU limit", func() {
+				Expect(container.LimitCPU(garden.CPULimits{LimitInShares: 512})).To(Succeed())
+
+				_, _, limits := limitUpdater.UpdateLimitsArgsForCall(0)
+				Expect(limits).To(Equal(garden.Limits{CPU: garden.CPULimits{LimitInShares: 512}}))
+			})
+
+			Context("when the update fails", func() {
+				BeforeEach(func() {
+					limitUpdater.UpdateLimitsReturns(errors.New("invalid argument"))
+				})
+
+				It("returns the error", func() {
+					Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1024})).To(MatchError("invalid argument"))
+				})
+			})
+
+			Context("when the containerizer cannot update limits", func() {
+				BeforeEach(func() {
+					gdnr.Containerizer = containerizer
+
+					var err error
+					container, err = gdnr.Lookup("some-handle")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func() {
+					Expect(container.LimitCPU(garden.CPULimits{LimitInShares: 512})).To(MatchError("the containerizer cannot update limits"))
+				})
+			})
+		})
+
+		It("gets the disk limit from the recorded resources", func() {
+			resourceStore.GetReturns(gardener.ContainerResources{DiskQuota: 4096}, nil)
+
+			limits, err := container.CurrentDiskLimits()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(limits.ByteHard).To(BeEquivalentTo(4096))
+		})
+
+		It("does not allow the disk limit to be changed", func() {
+			Expect(container.LimitDisk(garden.DiskLimits{ByteHard: 1})).To(MatchError(ContainSubstring("only be set when the container is created")))
+		})
+
+		It("reports that bandwidth limits are not supported", func() {
+			Expect(container.LimitBandwidth(garden.BandwidthLimits{RateInBytesPerSecond: 1})).To(MatchError("bandwidth limits are not supported"))
+		})
+	})
+
+	Describe("RecordFailedDestroy", func() {
+		It("counts the attempt in the container's resource record", func() {
+			resourceStore.GetReturns(gardener.ContainerResources{DestroyPending: true, DestroyAttempts: 2}, nil)
+
+			attempts, err := gdnr.RecordFailedDestroy("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal(3))
+
+			handle, resources := resourceStore.SetArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(resources).To(Equal(gardener.ContainerResources{DestroyPending: true, DestroyAttempts: 3}))
+		})
+	})
+
+	Describe("UpdateLimits", func() {
+		var limitUpdater *fakes.FakeLimitUpdater
+
+		BeforeEach(func() {
+			limitUpdater = new(fakes.FakeLimitUpdater)
+			gdnr.Containerizer = limitUpdatingContainerizer{FakeContainerizer: containerizer, FakeLimitUpdater: limitUpdater}
+		})
+
+		It("records the new memory and cpu limits", func() {
+			resourceStore.GetReturns(gardener.ContainerResources{DiskQuota: 10, MemoryLimit: 20, CPUShares: 30}, nil)
+
+			Expect(gdnr.UpdateLimits("some-handle", garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 1024}})).To(Succeed())
+
+			Expect(resourceStore.SetCallCount()).To(Equal(1))
+			handle, resources := resourceStore.SetArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(resources).To(Equal(gardener.ContainerResources{DiskQuota: 10, MemoryLimit: 1024, CPUShares: 30}))
+		})
+
+		Context("when memory is reserved for the system", func() {
+			BeforeEach(func() {
+				gdnr.ReservedMemoryPercent = 10
+				sysinfoProvider.TotalMemoryReturns(1000, nil)
+
+				resourceStore.HandlesReturns([]string{"some-handle", "other-handle"}, nil)
+				resourceStore.GetStub = func(handle string) (gardener.ContainerResources, error) {
+					return gardener.ContainerResources{MemoryLimit: 400}, nil
+				}
+			})
+
+			It("allows the container's own limit to be raised up to the unreserved memory", func() {
+				Expect(gdnr.UpdateLimits("some-handle", garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 500}})).To(Succeed())
+			})
+
+			It("rejects a limit which would commit the reserved memory", func() {
+				err := gdnr.UpdateLimits("some-handle", garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 501}})
+				Expect(err).To(MatchError(ContainSubstring("insufficient memory")))
+				Expect(limitUpdater.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		It("asks the containerizer to update the limits, including the pid limit", func() {
+			limits := garden.Limits{Pid: garden.PidLimits{Max: 200}}
+			Expect(gdnr.UpdateLimits("some-handle", limits)).To(Succeed())
+
+			Expect(limitUpdater.UpdateLimitsCallCount()).To(Equal(1))
+			_, handle, actualLimits := limitUpdater.UpdateLimitsArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(actualLimits).To(Equal(limits))
+		})
+
+		Context("when the container does not exist", func() {
+			It("returns a ContainerNotFoundError", func() {
+				Expect(gdnr.UpdateLimits("nonexistent", garden.Limits{})).To(MatchError(garden.ContainerNotFoundError{Handle: "nonexistent"}))
+				Expect(limitUpdater.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the containerizer cannot update limits", func() {
+			BeforeEach(func() {
+				gdnr.Containerizer = containerizer
+			})
+
+			It("returns an error", func() {
+				Expect(gdnr.UpdateLimits("some-handle", garden.Limits{})).To(MatchError("the containerizer cannot update limits"))
+			})
+		})
 	})
 
 	Describe("GraceTime", func() {
@@ -1451,6 +4149,85 @@ var _ = Describe("Gardener", func() {
 			It("returns no grace time", func() {
 				Expect(gdnr.GraceTime(container)).To(BeZero())
 			})
+
+			Context("and there is a default grace time", func() {
+				BeforeEach(func() {
+					gdnr.DefaultGraceTime = time.Hour
+				})
+
+				It("returns the default grace time", func() {
+					Expect(gdnr.GraceTime(container)).To(Equal(time.Hour))
+				})
+			})
+		})
+
+		Context("when the grace time property is a duration", func() {
+			BeforeEach(func() {
+				propertyManager.GetReturns("90s", true)
+			})
+
+			It("returns the parsed duration", func() {
+				Expect(gdnr.GraceTime(container)).To(Equal(90 * time.Second))
+			})
+		})
+
+		Context("when the grace time property is invalid", func() {
+			BeforeEach(func() {
+				propertyManager.GetReturns("banana", true)
+				gdnr.DefaultGraceTime = time.Hour
+			})
+
+			It("returns the default grace time", func() {
+				Expect(gdnr.GraceTime(container)).To(Equal(time.Hour))
+			})
 		})
 	})
 })
+
+type statsNetworker struct {
+	*fakes.FakeNetworker
+	*fakes.FakeNetworkStatser
+}
+
+type releasingNetworker struct {
+	*fakes.FakeNetworker
+	*fakes.FakeRecordedNetworkReleaser
+}
+
+type reservingNetworker struct {
+	*fakes.FakeNetworker
+	*fakes.FakeNetworkReserver
+}
+
+type planningNetworker struct {
+	*fakes.FakeNetworker
+	*fakes.FakeNetworkPlanner
+}
+
+type planningVolumizer struct {
+	*fakes.FakeVolumizer
+	*fakes.FakeVolumePlanner
+}
+
+type limitUpdatingContainerizer struct {
+	*fakes.FakeContainerizer
+	*fakes.FakeLimitUpdater
+}
+
+type storingPropertyManager struct {
+	*fakes.FakePropertyManager
+	*fakes.FakePropertyStorer
+}
+
+type watchRestoringContainerizer struct {
+	*fakes.FakeContainerizer
+	*fakes.FakeWatchRestorer
+}
+
+type failingCloser struct {
+	*gbytes.Buffer
+}
+
+func (failingCloser) Close() error {
+	return errors.New("tar-failed")
+}
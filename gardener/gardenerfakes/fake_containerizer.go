@@ -7,8 +7,9 @@ import (
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
-	"code.cloudfoundry.org/guardian/gardener/container-spec"
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
 	"code.cloudfoundry.org/lager"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type FakeContainerizer struct {
@@ -24,6 +25,20 @@ type FakeContainerizer struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PreviewStub        func(log lager.Logger, desiredContainerSpec spec.DesiredContainerSpec) (specs.Spec, error)
+	previewMutex       sync.RWMutex
+	previewArgsForCall []struct {
+		log                  lager.Logger
+		desiredContainerSpec spec.DesiredContainerSpec
+	}
+	previewReturns struct {
+		result1 specs.Spec
+		result2 error
+	}
+	previewReturnsOnCall map[int]struct {
+		result1 specs.Spec
+		result2 error
+	}
 	HandlesStub        func() ([]string, error)
 	handlesMutex       sync.RWMutex
 	handlesArgsForCall []struct{}
@@ -160,6 +175,34 @@ type FakeContainerizer struct {
 		result1 gardener.ActualContainerMetrics
 		result2 error
 	}
+	ProcessesStub        func(log lager.Logger, handle string) ([]gardener.ContainerProcess, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	processesReturns struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}
+	processesReturnsOnCall map[int]struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}
+	LiveProcessCountStub        func(log lager.Logger, handle string) (int, error)
+	liveProcessCountMutex       sync.RWMutex
+	liveProcessCountArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	liveProcessCountReturns struct {
+		result1 int
+		result2 error
+	}
+	liveProcessCountReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -213,6 +256,58 @@ func (fake *FakeContainerizer) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeContainerizer) Preview(log lager.Logger, desiredContainerSpec spec.DesiredContainerSpec) (specs.Spec, error) {
+	fake.previewMutex.Lock()
+	ret, specificReturn := fake.previewReturnsOnCall[len(fake.previewArgsForCall)]
+	fake.previewArgsForCall = append(fake.previewArgsForCall, struct {
+		log                  lager.Logger
+		desiredContainerSpec spec.DesiredContainerSpec
+	}{log, desiredContainerSpec})
+	fake.recordInvocation("Preview", []interface{}{log, desiredContainerSpec})
+	fake.previewMutex.Unlock()
+	if fake.PreviewStub != nil {
+		return fake.PreviewStub(log, desiredContainerSpec)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.previewReturns.result1, fake.previewReturns.result2
+}
+
+func (fake *FakeContainerizer) PreviewCallCount() int {
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
+	return len(fake.previewArgsForCall)
+}
+
+func (fake *FakeContainerizer) PreviewArgsForCall(i int) (lager.Logger, spec.DesiredContainerSpec) {
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
+	return fake.previewArgsForCall[i].log, fake.previewArgsForCall[i].desiredContainerSpec
+}
+
+func (fake *FakeContainerizer) PreviewReturns(result1 specs.Spec, result2 error) {
+	fake.PreviewStub = nil
+	fake.previewReturns = struct {
+		result1 specs.Spec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerizer) PreviewReturnsOnCall(i int, result1 specs.Spec, result2 error) {
+	fake.PreviewStub = nil
+	if fake.previewReturnsOnCall == nil {
+		fake.previewReturnsOnCall = make(map[int]struct {
+			result1 specs.Spec
+			result2 error
+		})
+	}
+	fake.previewReturnsOnCall[i] = struct {
+		result1 specs.Spec
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeContainerizer) Handles() ([]string, error) {
 	fake.handlesMutex.Lock()
 	ret, specificReturn := fake.handlesReturnsOnCall[len(fake.handlesArgsForCall)]
@@ -719,11 +814,117 @@ func (fake *FakeContainerizer) MetricsReturnsOnCall(i int, result1 gardener.Actu
 	}{result1, result2}
 }
 
+func (fake *FakeContainerizer) Processes(log lager.Logger, handle string) ([]gardener.ContainerProcess, error) {
+	fake.processesMutex.Lock()
+	ret, specificReturn := fake.processesReturnsOnCall[len(fake.processesArgsForCall)]
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Processes", []interface{}{log, handle})
+	fake.processesMutex.Unlock()
+	if fake.ProcessesStub != nil {
+		return fake.ProcessesStub(log, handle)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processesReturns.result1, fake.processesReturns.result2
+}
+
+func (fake *FakeContainerizer) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *FakeContainerizer) ProcessesArgsForCall(i int) (lager.Logger, string) {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return fake.processesArgsForCall[i].log, fake.processesArgsForCall[i].handle
+}
+
+func (fake *FakeContainerizer) ProcessesReturns(result1 []gardener.ContainerProcess, result2 error) {
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerizer) ProcessesReturnsOnCall(i int, result1 []gardener.ContainerProcess, result2 error) {
+	fake.ProcessesStub = nil
+	if fake.processesReturnsOnCall == nil {
+		fake.processesReturnsOnCall = make(map[int]struct {
+			result1 []gardener.ContainerProcess
+			result2 error
+		})
+	}
+	fake.processesReturnsOnCall[i] = struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerizer) LiveProcessCount(log lager.Logger, handle string) (int, error) {
+	fake.liveProcessCountMutex.Lock()
+	ret, specificReturn := fake.liveProcessCountReturnsOnCall[len(fake.liveProcessCountArgsForCall)]
+	fake.liveProcessCountArgsForCall = append(fake.liveProcessCountArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("LiveProcessCount", []interface{}{log, handle})
+	fake.liveProcessCountMutex.Unlock()
+	if fake.LiveProcessCountStub != nil {
+		return fake.LiveProcessCountStub(log, handle)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.liveProcessCountReturns.result1, fake.liveProcessCountReturns.result2
+}
+
+func (fake *FakeContainerizer) LiveProcessCountCallCount() int {
+	fake.liveProcessCountMutex.RLock()
+	defer fake.liveProcessCountMutex.RUnlock()
+	return len(fake.liveProcessCountArgsForCall)
+}
+
+func (fake *FakeContainerizer) LiveProcessCountArgsForCall(i int) (lager.Logger, string) {
+	fake.liveProcessCountMutex.RLock()
+	defer fake.liveProcessCountMutex.RUnlock()
+	return fake.liveProcessCountArgsForCall[i].log, fake.liveProcessCountArgsForCall[i].handle
+}
+
+func (fake *FakeContainerizer) LiveProcessCountReturns(result1 int, result2 error) {
+	fake.LiveProcessCountStub = nil
+	fake.liveProcessCountReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerizer) LiveProcessCountReturnsOnCall(i int, result1 int, result2 error) {
+	fake.LiveProcessCountStub = nil
+	if fake.liveProcessCountReturnsOnCall == nil {
+		fake.liveProcessCountReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.liveProcessCountReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeContainerizer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
 	fake.handlesMutex.RLock()
 	defer fake.handlesMutex.RUnlock()
 	fake.streamInMutex.RLock()
@@ -744,6 +945,10 @@ func (fake *FakeContainerizer) Invocations() map[string][][]interface{} {
 	defer fake.infoMutex.RUnlock()
 	fake.metricsMutex.RLock()
 	defer fake.metricsMutex.RUnlock()
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	fake.liveProcessCountMutex.RLock()
+	defer fake.liveProcessCountMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -30,6 +30,17 @@ type FakeSysInfoProvider struct {
 		result1 uint64
 		result2 error
 	}
+	TotalCPUsStub        func() (uint64, error)
+	totalCPUsMutex       sync.RWMutex
+	totalCPUsArgsForCall []struct{}
+	totalCPUsReturns     struct {
+		result1 uint64
+		result2 error
+	}
+	totalCPUsReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -120,6 +131,49 @@ func (fake *FakeSysInfoProvider) TotalDiskReturnsOnCall(i int, result1 uint64, r
 	}{result1, result2}
 }
 
+func (fake *FakeSysInfoProvider) TotalCPUs() (uint64, error) {
+	fake.totalCPUsMutex.Lock()
+	ret, specificReturn := fake.totalCPUsReturnsOnCall[len(fake.totalCPUsArgsForCall)]
+	fake.totalCPUsArgsForCall = append(fake.totalCPUsArgsForCall, struct{}{})
+	fake.recordInvocation("TotalCPUs", []interface{}{})
+	fake.totalCPUsMutex.Unlock()
+	if fake.TotalCPUsStub != nil {
+		return fake.TotalCPUsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.totalCPUsReturns.result1, fake.totalCPUsReturns.result2
+}
+
+func (fake *FakeSysInfoProvider) TotalCPUsCallCount() int {
+	fake.totalCPUsMutex.RLock()
+	defer fake.totalCPUsMutex.RUnlock()
+	return len(fake.totalCPUsArgsForCall)
+}
+
+func (fake *FakeSysInfoProvider) TotalCPUsReturns(result1 uint64, result2 error) {
+	fake.TotalCPUsStub = nil
+	fake.totalCPUsReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSysInfoProvider) TotalCPUsReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.TotalCPUsStub = nil
+	if fake.totalCPUsReturnsOnCall == nil {
+		fake.totalCPUsReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.totalCPUsReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSysInfoProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -127,6 +181,8 @@ func (fake *FakeSysInfoProvider) Invocations() map[string][][]interface{} {
 	defer fake.totalMemoryMutex.RUnlock()
 	fake.totalDiskMutex.RLock()
 	defer fake.totalDiskMutex.RUnlock()
+	fake.totalCPUsMutex.RLock()
+	defer fake.totalCPUsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,25 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//go:generate counterfeiter . HealthReporter
+type HealthReporter interface {
+	HealthStatuses() map[string]HealthStatus
+}
+
+// HealthHandler serves the health of each container with a health probe, by
+// handle
+func HealthHandler(reporter HealthReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reporter.HealthStatuses())
+	})
+}
@@ -0,0 +1,55 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HealthHandler", func() {
+	var (
+		reporter *fakes.FakeHealthReporter
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		reporter = new(fakes.FakeHealthReporter)
+		reporter.HealthStatusesReturns(map[string]gardener.HealthStatus{
+			"some-handle": {Status: gardener.Unhealthy, ConsecutiveFailures: 3, LastExitCode: 1},
+		})
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/health", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.HealthHandler(reporter).ServeHTTP(recorder, request)
+	})
+
+	It("responds with the health of each probed container", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response map[string]gardener.HealthStatus
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(HaveKey("some-handle"))
+		Expect(response["some-handle"].Status).To(Equal("unhealthy"))
+		Expect(response["some-handle"].ConsecutiveFailures).To(Equal(3))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/health", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(reporter.HealthStatusesCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,385 @@
+package gardener
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/dropsonde/metrics"
+	"github.com/pivotal-golang/clock"
+)
+
+// The properties which define the liveness probe of a container. Only the
+// command is required.
+const (
+	HealthProbeCommandKey          = "garden.health-probe.command"
+	HealthProbeIntervalKey         = "garden.health-probe.interval"
+	HealthProbeTimeoutKey          = "garden.health-probe.timeout"
+	HealthProbeFailureThresholdKey = "garden.health-probe.failure-threshold"
+	HealthProbeEventsKey           = "garden.health-probe.events"
+
+	// HealthStatusKey is the property in which the HealthProber records the
+	// status of the container
+	HealthStatusKey = "garden.health-probe.status"
+
+	EnabledHealthProbeEvents = "enabled"
+)
+
+const (
+	HealthUnknown = "unknown"
+	Healthy       = "healthy"
+	Unhealthy     = "unhealthy"
+)
+
+const (
+	defaultHealthProbeInterval         = 10 * time.Second
+	defaultHealthProbeTimeout          = 5 * time.Second
+	defaultHealthProbeFailureThreshold = 3
+
+	// maxHealthProbeOutput is how much of the output of a probe is kept
+	maxHealthProbeOutput = 4096
+)
+
+// HealthProbe is a command run periodically in a container with sh -c. The
+// container becomes unhealthy once the command has failed FailureThreshold
+// times in a row, and healthy again as soon as it succeeds.
+type HealthProbe struct {
+	Command          string
+	Interval         time.Duration
+	Timeout          time.Duration
+	FailureThreshold int
+
+	// EmitEvents sends a ContainerUnhealthyEvent to the LifecycleNotifier
+	// when the container becomes unhealthy
+	EmitEvents bool
+}
+
+// HealthStatus is the result of the probes of a container so far
+type HealthStatus struct {
+	Status              string    `json:"status"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastProbedAt        time.Time `json:"last_probed_at"`
+	LastExitCode        int       `json:"last_exit_code"`
+	LastOutput          string    `json:"last_output"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// healthProbe returns the probe defined by the properties of a container,
+// and whether it has one
+func healthProbe(properties garden.Properties) (HealthProbe, bool, error) {
+	command := properties[HealthProbeCommandKey]
+	if command == "" {
+		return HealthProbe{}, false, nil
+	}
+
+	probe := HealthProbe{
+		Command:          command,
+		Interval:         defaultHealthProbeInterval,
+		Timeout:          defaultHealthProbeTimeout,
+		FailureThreshold: defaultHealthProbeFailureThreshold,
+	}
+
+	var err error
+	if probe.Interval, err = durationProperty(properties, HealthProbeIntervalKey, probe.Interval); err != nil {
+		return HealthProbe{}, false, err
+	}
+
+	if probe.Timeout, err = durationProperty(properties, HealthProbeTimeoutKey, probe.Timeout); err != nil {
+		return HealthProbe{}, false, err
+	}
+
+	if value, ok := properties[HealthProbeFailureThresholdKey]; ok {
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 1 {
+			return HealthProbe{}, false, fmt.Errorf("invalid %s property: %s", HealthProbeFailureThresholdKey, value)
+		}
+		probe.FailureThreshold = threshold
+	}
+
+	if value, ok := properties[HealthProbeEventsKey]; ok {
+		if value != EnabledHealthProbeEvents {
+			return HealthProbe{}, false, fmt.Errorf("invalid %s property: %s", HealthProbeEventsKey, value)
+		}
+		probe.EmitEvents = true
+	}
+
+	return probe, true, nil
+}
+
+func durationProperty(properties garden.Properties, key string, defaultValue time.Duration) (time.Duration, error) {
+	value, ok := properties[key]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s property: %s", key, value)
+	}
+
+	return duration, nil
+}
+
+//go:generate counterfeiter . ContainerLister
+type ContainerLister interface {
+	Containers(garden.Properties) ([]garden.Container, error)
+}
+
+// HealthProber runs the health probes of the containers which define one,
+// so that clients can read the health of a container from its properties
+// rather than each polling it. It checks which probes are due every
+// Interval.
+type HealthProber struct {
+	Interval time.Duration
+	Logger   lager.Logger
+	Clock    clock.Clock
+
+	containers ContainerLister
+	lifecycle  LifecycleNotifier
+	nextProbes map[string]time.Time
+	stopped    chan struct{}
+
+	statusesMutex sync.Mutex
+	statuses      map[string]HealthStatus
+}
+
+func NewHealthProber(
+	logger lager.Logger,
+	containers ContainerLister,
+	lifecycle LifecycleNotifier,
+	interval time.Duration,
+	clock clock.Clock,
+) *HealthProber {
+	return &HealthProber{
+		Interval: interval,
+		Logger:   logger,
+		Clock:    clock,
+
+		containers: containers,
+		lifecycle:  lifecycle,
+		nextProbes: map[string]time.Time{},
+		stopped:    make(chan struct{}),
+		statuses:   map[string]HealthStatus{},
+	}
+}
+
+func (p *HealthProber) Start() {
+	log := p.Logger.Session("health-prober", lager.Data{"interval": p.Interval.String()})
+	log.Info("starting")
+	ticker := p.Clock.NewTicker(p.Interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		log.Info("started")
+		defer log.Info("finished")
+
+		for {
+			select {
+			case <-ticker.C():
+				p.Probe(log)
+			case <-p.stopped:
+				return
+			}
+		}
+	}()
+}
+
+func (p *HealthProber) Stop() {
+	close(p.stopped)
+}
+
+// HealthStatuses returns the health of each container which has been probed
+func (p *HealthProber) HealthStatuses() map[string]HealthStatus {
+	p.statusesMutex.Lock()
+	defer p.statusesMutex.Unlock()
+
+	statuses := make(map[string]HealthStatus, len(p.statuses))
+	for handle, status := range p.statuses {
+		statuses[handle] = status
+	}
+
+	return statuses
+}
+
+// Probe runs the probes which are due, in parallel, and waits for them
+func (p *HealthProber) Probe(log lager.Logger) {
+	log = log.Session("probe")
+
+	containers, err := p.containers.Containers(nil)
+	if err != nil {
+		log.Error("listing-containers-failed", err)
+		return
+	}
+
+	now := p.Clock.Now()
+	probed := map[string]bool{}
+
+	var wg sync.WaitGroup
+	for _, container := range containers {
+		handle := container.Handle()
+
+		properties, err := container.Properties()
+		if err != nil {
+			log.Error("reading-properties-failed", err, lager.Data{"handle": handle})
+			continue
+		}
+
+		probe, ok, err := healthProbe(properties)
+		if err != nil {
+			log.Error("invalid-probe", err, lager.Data{"handle": handle})
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		probed[handle] = true
+		if next, scheduled := p.nextProbes[handle]; scheduled && now.Before(next) {
+			continue
+		}
+		p.nextProbes[handle] = now.Add(probe.Interval)
+
+		wg.Add(1)
+		go func(container garden.Container, probe HealthProbe) {
+			defer wg.Done()
+			p.probe(log.Session("container", lager.Data{"handle": container.Handle()}), container, probe)
+		}(container, probe)
+	}
+	wg.Wait()
+
+	// forget the containers which have gone or stopped defining a probe
+	for handle := range p.nextProbes {
+		if !probed[handle] {
+			delete(p.nextProbes, handle)
+		}
+	}
+
+	p.statusesMutex.Lock()
+	for handle := range p.statuses {
+		if !probed[handle] {
+			delete(p.statuses, handle)
+		}
+	}
+	p.statusesMutex.Unlock()
+}
+
+func (p *HealthProber) probe(log lager.Logger, container garden.Container, probe HealthProbe) {
+	startedAt := p.Clock.Now()
+	exitCode, output, err := p.run(container, probe)
+	_ = metrics.SendValue("HealthProbeDuration", float64(p.Clock.Since(startedAt).Nanoseconds()), "nanos")
+
+	failed := err != nil || exitCode != 0
+
+	p.statusesMutex.Lock()
+	status, ok := p.statuses[container.Handle()]
+	if !ok {
+		status.Status = HealthUnknown
+	}
+	previousStatus := status.Status
+
+	status.LastProbedAt = startedAt
+	status.LastExitCode = exitCode
+	status.LastOutput = output
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+
+	if failed {
+		status.ConsecutiveFailures++
+		if status.ConsecutiveFailures >= probe.FailureThreshold {
+			status.Status = Unhealthy
+		}
+	} else {
+		status.ConsecutiveFailures = 0
+		status.Status = Healthy
+	}
+
+	p.statuses[container.Handle()] = status
+	p.statusesMutex.Unlock()
+
+	if failed {
+		_ = metrics.IncrementCounter("HealthProbeFailures")
+		log.Info("failed", lager.Data{"exit-code": exitCode, "error": status.LastError, "consecutive-failures": status.ConsecutiveFailures})
+	}
+
+	if status.Status == previousStatus {
+		return
+	}
+
+	log.Info("status-changed", lager.Data{"from": previousStatus, "to": status.Status})
+	if err := container.SetProperty(HealthStatusKey, status.Status); err != nil {
+		log.Error("recording-status-failed", err)
+	}
+
+	if status.Status == Unhealthy && probe.EmitEvents {
+		p.lifecycle.Notify(log, LifecycleEvent{Type: ContainerUnhealthyEvent, Handle: container.Handle()})
+	}
+}
+
+// run runs the probe command in the container, killing it if it does not
+// exit within the probe's timeout
+func (p *HealthProber) run(container garden.Container, probe HealthProbe) (int, string, error) {
+	output := &probeOutput{}
+	process, err := container.Run(garden.ProcessSpec{
+		Path: "sh",
+		Args: []string{"-c", probe.Command},
+	}, garden.ProcessIO{Stdout: output, Stderr: output})
+	if err != nil {
+		return -1, "", fmt.Errorf("running probe: %s", err)
+	}
+
+	type result struct {
+		exitCode int
+		err      error
+	}
+	exited := make(chan result, 1)
+	go func() {
+		exitCode, err := process.Wait()
+		exited <- result{exitCode, err}
+	}()
+
+	timer := p.Clock.NewTimer(probe.Timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-exited:
+		return r.exitCode, output.String(), r.err
+	case <-timer.C():
+		process.Signal(garden.SignalKill)
+		return -1, output.String(), fmt.Errorf("probe timed out after %s", probe.Timeout)
+	}
+}
+
+// probeOutput keeps the first maxHealthProbeOutput bytes written to it
+type probeOutput struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (o *probeOutput) Write(data []byte) (int, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if room := maxHealthProbeOutput - o.buffer.Len(); room > 0 {
+		if len(data) > room {
+			o.buffer.Write(data[:room])
+		} else {
+			o.buffer.Write(data)
+		}
+	}
+
+	return len(data), nil
+}
+
+func (o *probeOutput) String() string {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.buffer.String()
+}
@@ -0,0 +1,232 @@
+package gardener_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HealthProber", func() {
+	var (
+		logger     *lagertest.TestLogger
+		containers *fakes.FakeContainerLister
+		lifecycle  *fakes.FakeLifecycleNotifier
+		container  *gardenfakes.FakeContainer
+		process    *gardenfakes.FakeProcess
+		properties garden.Properties
+		clock      *fakeclock.FakeClock
+
+		prober *gardener.HealthProber
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		containers = new(fakes.FakeContainerLister)
+		lifecycle = new(fakes.FakeLifecycleNotifier)
+		clock = fakeclock.NewFakeClock(time.Unix(123, 456))
+
+		properties = garden.Properties{
+			gardener.HealthProbeCommandKey:          "curl -f localhost:8080/health",
+			gardener.HealthProbeIntervalKey:         "30s",
+			gardener.HealthProbeFailureThresholdKey: "2",
+		}
+
+		process = new(gardenfakes.FakeProcess)
+		container = new(gardenfakes.FakeContainer)
+		container.HandleReturns("some-handle")
+		container.PropertiesStub = func() (garden.Properties, error) {
+			return properties, nil
+		}
+		container.RunStub = func(_ garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+			io.Stdout.Write([]byte("some-output"))
+			return process, nil
+		}
+		containers.ContainersReturns([]garden.Container{container}, nil)
+
+		prober = gardener.NewHealthProber(logger, containers, lifecycle, time.Second, clock)
+	})
+
+	It("runs the probe command in the container", func() {
+		prober.Probe(logger)
+
+		Expect(container.RunCallCount()).To(Equal(1))
+		spec, _ := container.RunArgsForCall(0)
+		Expect(spec.Path).To(Equal("sh"))
+		Expect(spec.Args).To(Equal([]string{"-c", "curl -f localhost:8080/health"}))
+	})
+
+	It("records that the container is healthy when the probe succeeds", func() {
+		prober.Probe(logger)
+
+		status := prober.HealthStatuses()["some-handle"]
+		Expect(status.Status).To(Equal(gardener.Healthy))
+		Expect(status.LastOutput).To(Equal("some-output"))
+		Expect(status.LastProbedAt).To(Equal(clock.Now()))
+
+		Expect(container.SetPropertyCallCount()).To(Equal(1))
+		key, value := container.SetPropertyArgsForCall(0)
+		Expect(key).To(Equal(gardener.HealthStatusKey))
+		Expect(value).To(Equal("healthy"))
+	})
+
+	It("does not run the probe again until its interval has passed", func() {
+		prober.Probe(logger)
+		clock.Increment(29 * time.Second)
+		prober.Probe(logger)
+		Expect(container.RunCallCount()).To(Equal(1))
+
+		clock.Increment(time.Second)
+		prober.Probe(logger)
+		Expect(container.RunCallCount()).To(Equal(2))
+	})
+
+	It("does not probe containers which do not define a probe", func() {
+		properties = garden.Properties{}
+		prober.Probe(logger)
+		Expect(container.RunCallCount()).To(Equal(0))
+		Expect(prober.HealthStatuses()).To(BeEmpty())
+	})
+
+	Context("when the probe fails", func() {
+		BeforeEach(func() {
+			process.WaitReturns(1, nil)
+		})
+
+		probeAgain := func() {
+			clock.Increment(30 * time.Second)
+			prober.Probe(logger)
+		}
+
+		It("records the failure but waits for the failure threshold before marking the container unhealthy", func() {
+			prober.Probe(logger)
+
+			status := prober.HealthStatuses()["some-handle"]
+			Expect(status.Status).To(Equal(gardener.HealthUnknown))
+			Expect(status.ConsecutiveFailures).To(Equal(1))
+			Expect(status.LastExitCode).To(Equal(1))
+
+			probeAgain()
+			Expect(prober.HealthStatuses()["some-handle"].Status).To(Equal(gardener.Unhealthy))
+			key, value := container.SetPropertyArgsForCall(0)
+			Expect(key).To(Equal(gardener.HealthStatusKey))
+			Expect(value).To(Equal("unhealthy"))
+		})
+
+		It("does not notify by default", func() {
+			prober.Probe(logger)
+			probeAgain()
+			Expect(lifecycle.NotifyCallCount()).To(Equal(0))
+		})
+
+		Context("when the container asks for events", func() {
+			BeforeEach(func() {
+				properties[gardener.HealthProbeEventsKey] = gardener.EnabledHealthProbeEvents
+			})
+
+			It("notifies once when the container becomes unhealthy", func() {
+				prober.Probe(logger)
+				probeAgain()
+				probeAgain()
+
+				Expect(lifecycle.NotifyCallCount()).To(Equal(1))
+				_, event := lifecycle.NotifyArgsForCall(0)
+				Expect(event).To(Equal(gardener.LifecycleEvent{Type: gardener.ContainerUnhealthyEvent, Handle: "some-handle"}))
+			})
+		})
+
+		Context("and then succeeds", func() {
+			It("marks the container healthy again", func() {
+				prober.Probe(logger)
+				probeAgain()
+
+				process.WaitReturns(0, nil)
+				probeAgain()
+
+				status := prober.HealthStatuses()["some-handle"]
+				Expect(status.Status).To(Equal(gardener.Healthy))
+				Expect(status.ConsecutiveFailures).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the probe cannot be run", func() {
+		BeforeEach(func() {
+			container.RunStub = nil
+			container.RunReturns(nil, errors.New("container is stopped"))
+		})
+
+		It("counts it as a failure", func() {
+			prober.Probe(logger)
+
+			status := prober.HealthStatuses()["some-handle"]
+			Expect(status.ConsecutiveFailures).To(Equal(1))
+			Expect(status.LastError).To(ContainSubstring("container is stopped"))
+		})
+	})
+
+	Context("when the probe does not exit within its timeout", func() {
+		var exit chan struct{}
+
+		BeforeEach(func() {
+			properties[gardener.HealthProbeTimeoutKey] = "2s"
+
+			exit = make(chan struct{})
+			process.WaitStub = func() (int, error) {
+				<-exit
+				return 137, nil
+			}
+		})
+
+		AfterEach(func() {
+			close(exit)
+		})
+
+		It("kills it and counts it as a failure", func() {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				prober.Probe(logger)
+			}()
+
+			Eventually(clock.WatcherCount).Should(Equal(1))
+			clock.Increment(2 * time.Second)
+			Eventually(done).Should(BeClosed())
+
+			Expect(process.SignalCallCount()).To(Equal(1))
+			Expect(process.SignalArgsForCall(0)).To(Equal(garden.SignalKill))
+
+			status := prober.HealthStatuses()["some-handle"]
+			Expect(status.ConsecutiveFailures).To(Equal(1))
+			Expect(status.LastError).To(Equal("probe timed out after 2s"))
+		})
+	})
+
+	It("forgets containers which have gone", func() {
+		prober.Probe(logger)
+		Expect(prober.HealthStatuses()).To(HaveKey("some-handle"))
+
+		containers.ContainersReturns([]garden.Container{}, nil)
+		prober.Probe(logger)
+		Expect(prober.HealthStatuses()).To(BeEmpty())
+	})
+
+	Context("when the probe properties are invalid", func() {
+		BeforeEach(func() {
+			properties[gardener.HealthProbeIntervalKey] = "often"
+		})
+
+		It("does not probe the container", func() {
+			prober.Probe(logger)
+			Expect(container.RunCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package gardener
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . ContainerHook
+
+// A ContainerHook extends the container lifecycle with site-specific policy.
+// PreCreate runs before a container is created and returns the spec to
+// create it with, or an error to refuse the create. PostDestroy runs once a
+// container has been destroyed.
+type ContainerHook interface {
+	PreCreate(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error)
+	PostDestroy(log lager.Logger, handle string) error
+}
+
+// runPreCreateHooks passes the spec through each hook in turn. Hooks may not
+// change the handle, as it has already been logged and reserved.
+func (g *Gardener) runPreCreateHooks(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+	for _, hook := range g.ContainerHooks {
+		mutated, err := hook.PreCreate(log, spec)
+		if err != nil {
+			return garden.ContainerSpec{}, fmt.Errorf("pre-create hook: %s", err)
+		}
+
+		mutated.Handle = spec.Handle
+		spec = mutated
+	}
+
+	return spec, nil
+}
+
+// runPostDestroyHooks runs each hook in the background, logging rather than
+// returning their errors, as the container is already gone and a slow hook
+// should not hold up the destroy
+func (g *Gardener) runPostDestroyHooks(log lager.Logger, handle string) {
+	if len(g.ContainerHooks) == 0 {
+		return
+	}
+
+	go func() {
+		for _, hook := range g.ContainerHooks {
+			if err := hook.PostDestroy(log, handle); err != nil {
+				log.Error("post-destroy-hook-failed", err)
+			}
+		}
+	}()
+}
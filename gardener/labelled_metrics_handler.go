@@ -0,0 +1,43 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . ContainerLabelledMetricser
+type ContainerLabelledMetricser interface {
+	LabelledMetrics(handle string) (LabelledMetrics, error)
+}
+
+// LabelledMetricsHandler serves the metrics of the container named by the
+// 'handle' query parameter, tagged with the container's labels.
+func LabelledMetricsHandler(metricser ContainerLabelledMetricser) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		metrics, err := metricser.LabelledMetrics(handle)
+		if _, ok := err.(garden.ContainerNotFoundError); ok {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metrics)
+	})
+}
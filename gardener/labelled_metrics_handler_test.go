@@ -0,0 +1,94 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LabelledMetricsHandler", func() {
+	var (
+		metricser *fakes.FakeContainerLabelledMetricser
+		metrics   gardener.LabelledMetrics
+		recorder  *httptest.ResponseRecorder
+		request   *http.Request
+	)
+
+	BeforeEach(func() {
+		metrics = gardener.LabelledMetrics{Labels: map[string]string{"team": "some-team"}}
+		metrics.MemoryStat.Cache = 10
+		metrics.NetworkStat.RxBytes = 20
+
+		metricser = new(fakes.FakeContainerLabelledMetricser)
+		metricser.LabelledMetricsReturns(metrics, nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/metrics?handle=some-handle", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.LabelledMetricsHandler(metricser).ServeHTTP(recorder, request)
+	})
+
+	It("reads the metrics of the requested container", func() {
+		Expect(metricser.LabelledMetricsCallCount()).To(Equal(1))
+		Expect(metricser.LabelledMetricsArgsForCall(0)).To(Equal("some-handle"))
+	})
+
+	It("responds with the metrics and labels", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var served gardener.LabelledMetrics
+		Expect(json.NewDecoder(recorder.Body).Decode(&served)).To(Succeed())
+		Expect(served).To(Equal(metrics))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/metrics?handle=some-handle", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(metricser.LabelledMetricsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no handle is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/metrics", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(metricser.LabelledMetricsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			metricser.LabelledMetricsReturns(gardener.LabelledMetrics{}, garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when reading the metrics fails", func() {
+		BeforeEach(func() {
+			metricser.LabelledMetricsReturns(gardener.LabelledMetrics{}, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package gardener
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// LabelPrefix marks the properties which are container labels. Labels can
+// only be given in the properties of a create, so that metadata such as
+// billing or monitoring tags cannot be changed by users of the container.
+// They are recorded in the container's bundle rather than with its other
+// properties, and are reported in its Info properties, network stats and
+// labelled metrics.
+const LabelPrefix = "garden.label."
+
+// LabelledMetrics are the metrics of a container, tagged with its labels.
+// FDUsage is only reported when file descriptors can be counted.
+type LabelledMetrics struct {
+	garden.Metrics
+
+	Labels  map[string]string `json:"labels,omitempty"`
+	FDUsage *ContainerFDUsage `json:"fd_usage,omitempty"`
+}
+
+// IsLabel returns whether the property with the given name is a label
+func IsLabel(name string) bool {
+	return strings.HasPrefix(name, LabelPrefix)
+}
+
+// Labels returns the labels among the properties, keyed by name without the
+// label prefix, or nil if there are none
+func Labels(properties garden.Properties) map[string]string {
+	var labels map[string]string
+	for name, value := range properties {
+		if !IsLabel(name) {
+			continue
+		}
+
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[strings.TrimPrefix(name, LabelPrefix)] = value
+	}
+
+	return labels
+}
+
+// withLabels returns a copy of the properties with the labels added under the
+// label prefix
+func withLabels(properties garden.Properties, labels map[string]string) garden.Properties {
+	if len(labels) == 0 {
+		return properties
+	}
+
+	merged := make(garden.Properties, len(properties)+len(labels))
+	for name, value := range properties {
+		merged[name] = value
+	}
+	for name, value := range labels {
+		merged[LabelPrefix+name] = value
+	}
+
+	return merged
+}
+
+// splitLabels separates the labels in a filter from the properties
+func splitLabels(filter garden.Properties) (garden.Properties, map[string]string) {
+	properties := garden.Properties{}
+	for name, value := range filter {
+		if !IsLabel(name) {
+			properties[name] = value
+		}
+	}
+
+	return properties, Labels(filter)
+}
+
+// matchesLabels returns whether the container has every one of the labels in
+// the filter, with exactly the same value
+func matchesLabels(labels, filter map[string]string) bool {
+	for name, value := range filter {
+		if actual, ok := labels[name]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containerLabels returns the labels of the container. They are read from
+// its bundle the first time, e.g. after a restart, and kept in memory as they
+// cannot change.
+func (g *Gardener) containerLabels(log lager.Logger, handle string) (map[string]string, error) {
+	g.labelsMutex.Lock()
+	labels, ok := g.labels[handle]
+	g.labelsMutex.Unlock()
+
+	if ok {
+		return labels, nil
+	}
+
+	actualSpec, err := g.Containerizer.Info(log, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	g.rememberLabels(handle, actualSpec.Labels)
+	return actualSpec.Labels, nil
+}
+
+func (g *Gardener) rememberLabels(handle string, labels map[string]string) {
+	g.labelsMutex.Lock()
+	defer g.labelsMutex.Unlock()
+
+	if g.labels == nil {
+		g.labels = map[string]map[string]string{}
+	}
+	g.labels[handle] = labels
+}
+
+func (g *Gardener) forgetLabels(handle string) {
+	g.labelsMutex.Lock()
+	defer g.labelsMutex.Unlock()
+
+	delete(g.labels, handle)
+}
+
+// ImmutableLabelError is returned when setting or removing a label after the
+// container has been created
+type ImmutableLabelError struct {
+	Name string
+}
+
+func (e ImmutableLabelError) Error() string {
+	return fmt.Sprintf("cannot modify label '%s': labels can only be set when the container is created", strings.TrimPrefix(e.Name, LabelPrefix))
+}
@@ -0,0 +1,36 @@
+package gardener
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . LifecycleNotifier
+
+const (
+	ContainerCreatedEvent   = "created"
+	ContainerDestroyedEvent = "destroyed"
+	ContainerOOMedEvent     = "oomed"
+	ContainerExitedEvent    = "exited"
+	ContainerUnhealthyEvent = "unhealthy"
+)
+
+// LifecycleEvent describes something which happened to a container
+type LifecycleEvent struct {
+	Type   string `json:"type"`
+	Handle string `json:"handle"`
+
+	// Properties are the container's properties at the time of the event. They
+	// are nil if the notifier should look them up itself.
+	Properties garden.Properties `json:"properties"`
+}
+
+// LifecycleNotifier tells external systems about container lifecycle events.
+// Notify must not block on the external system.
+type LifecycleNotifier interface {
+	Notify(log lager.Logger, event LifecycleEvent)
+}
+
+type NoopLifecycleNotifier struct{}
+
+func (NoopLifecycleNotifier) Notify(lager.Logger, LifecycleEvent) {}
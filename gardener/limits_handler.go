@@ -0,0 +1,49 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . ContainerLimitUpdater
+type ContainerLimitUpdater interface {
+	UpdateLimits(handle string, limits garden.Limits) error
+}
+
+// LimitsHandler applies the JSON encoded garden.Limits in the request body to
+// the running container named by the 'handle' query parameter. Limits which
+// are zero are left unchanged.
+func LimitsHandler(updater ContainerLimitUpdater) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		var limits garden.Limits
+		if err := json.NewDecoder(r.Body).Decode(&limits); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err := updater.UpdateLimits(handle, limits)
+		if _, ok := err.(garden.ContainerNotFoundError); ok {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
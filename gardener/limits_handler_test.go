@@ -0,0 +1,98 @@
+package gardener_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LimitsHandler", func() {
+	var (
+		updater  *fakes.FakeContainerLimitUpdater
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		updater = new(fakes.FakeContainerLimitUpdater)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("PUT", "/debug/limits?handle=some-handle", strings.NewReader(`{"memory":{"limit_in_bytes":1024},"pid":{"max":200}}`))
+	})
+
+	JustBeforeEach(func() {
+		gardener.LimitsHandler(updater).ServeHTTP(recorder, request)
+	})
+
+	It("updates the limits of the requested container", func() {
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+		Expect(updater.UpdateLimitsCallCount()).To(Equal(1))
+		handle, limits := updater.UpdateLimitsArgsForCall(0)
+		Expect(handle).To(Equal("some-handle"))
+		Expect(limits).To(Equal(garden.Limits{
+			Memory: garden.MemoryLimits{LimitInBytes: 1024},
+			Pid:    garden.PidLimits{Max: 200},
+		}))
+	})
+
+	Context("when the request is not a PUT", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/limits?handle=some-handle", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(updater.UpdateLimitsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no handle is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("PUT", "/debug/limits", strings.NewReader(`{}`))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(updater.UpdateLimitsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not valid JSON", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("PUT", "/debug/limits?handle=some-handle", strings.NewReader(`{"memory":`))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(updater.UpdateLimitsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			updater.UpdateLimitsReturns(garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when updating the limits fails", func() {
+		BeforeEach(func() {
+			updater.UpdateLimitsReturns(errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package gardener
+
+import (
+	"net"
+	"net/http"
+)
+
+// LoopbackOnly serves requests with the handler only when they come from a
+// loopback address, and refuses the rest. The debug server is not
+// authenticated, so endpoints which change containers or the server are for
+// operators on the host itself, whatever address the server is bound to.
+func LoopbackOnly(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			http.Error(w, "this endpoint is only served to clients on the host", http.StatusForbidden)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
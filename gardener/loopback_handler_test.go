@@ -0,0 +1,59 @@
+package gardener_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoopbackOnly", func() {
+	var (
+		served   bool
+		handler  http.Handler
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		served = false
+		handler = gardener.LoopbackOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served = true
+		}))
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/destroy-matching", nil)
+	})
+
+	It("serves requests from IPv4 loopback addresses", func() {
+		request.RemoteAddr = "127.0.0.1:52000"
+		handler.ServeHTTP(recorder, request)
+
+		Expect(served).To(BeTrue())
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("serves requests from the IPv6 loopback address", func() {
+		request.RemoteAddr = "[::1]:52000"
+		handler.ServeHTTP(recorder, request)
+
+		Expect(served).To(BeTrue())
+	})
+
+	It("refuses requests from other addresses", func() {
+		request.RemoteAddr = "10.0.0.5:52000"
+		handler.ServeHTTP(recorder, request)
+
+		Expect(served).To(BeFalse())
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("refuses requests whose address cannot be parsed", func() {
+		request.RemoteAddr = "not-an-address"
+		handler.ServeHTTP(recorder, request)
+
+		Expect(served).To(BeFalse())
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+})
@@ -0,0 +1,45 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//go:generate counterfeiter . NetworkReservationManager
+type NetworkReservationManager interface {
+	NetworkReservations() (map[string]NetworkReservation, error)
+	ReleaseNetworkReservation(name string) error
+}
+
+// NetworkReservationsHandler lists the network reservations on GET, and
+// releases the reservation named by the 'name' query parameter on DELETE.
+func NetworkReservationsHandler(manager NetworkReservationManager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			reservations, err := manager.NetworkReservations()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(reservations)
+		case "DELETE":
+			name := r.URL.Query().Get("name")
+			if name == "" {
+				http.Error(w, "missing name", http.StatusBadRequest)
+				return
+			}
+
+			if err := manager.ReleaseNetworkReservation(name); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
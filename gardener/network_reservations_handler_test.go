@@ -0,0 +1,99 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkReservationsHandler", func() {
+	var (
+		manager  *fakes.FakeNetworkReservationManager
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		manager = new(fakes.FakeNetworkReservationManager)
+		manager.NetworkReservationsReturns(map[string]gardener.NetworkReservation{
+			"some-name": {Network: "10.0.0.5/30", Handle: "some-handle"},
+		}, nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/network-reservations", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.NetworkReservationsHandler(manager).ServeHTTP(recorder, request)
+	})
+
+	It("responds with the reservations", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var reservations map[string]gardener.NetworkReservation
+		Expect(json.NewDecoder(recorder.Body).Decode(&reservations)).To(Succeed())
+		Expect(reservations).To(Equal(map[string]gardener.NetworkReservation{
+			"some-name": {Network: "10.0.0.5/30", Handle: "some-handle"},
+		}))
+	})
+
+	Context("when listing the reservations fails", func() {
+		BeforeEach(func() {
+			manager.NetworkReservationsReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+
+	Context("when the request is a DELETE", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("DELETE", "/debug/network-reservations?name=some-name", nil)
+		})
+
+		It("releases the named reservation", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			Expect(manager.ReleaseNetworkReservationCallCount()).To(Equal(1))
+			Expect(manager.ReleaseNetworkReservationArgsForCall(0)).To(Equal("some-name"))
+		})
+
+		Context("when no name is given", func() {
+			BeforeEach(func() {
+				request = httptest.NewRequest("DELETE", "/debug/network-reservations", nil)
+			})
+
+			It("responds with bad request", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(manager.ReleaseNetworkReservationCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when releasing the reservation fails", func() {
+			BeforeEach(func() {
+				manager.ReleaseNetworkReservationReturns(errors.New("in use"))
+			})
+
+			It("responds with conflict", func() {
+				Expect(recorder.Code).To(Equal(http.StatusConflict))
+				Expect(recorder.Body.String()).To(ContainSubstring("in use"))
+			})
+		})
+	})
+
+	Context("when the request is neither a GET nor a DELETE", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/network-reservations", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkStatsHandler", func() {
+	var (
+		statser  *fakes.FakeContainerNetworkStatser
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		statser = new(fakes.FakeContainerNetworkStatser)
+		statser.NetworkStatsReturns(gardener.ContainerNetworkStats{RxBytes: 10, TxPackets: 2, RxDropped: 1}, nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/network-stats?handle=some-handle", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.NetworkStatsHandler(statser).ServeHTTP(recorder, request)
+	})
+
+	It("reads the stats of the requested container", func() {
+		Expect(statser.NetworkStatsCallCount()).To(Equal(1))
+		Expect(statser.NetworkStatsArgsForCall(0)).To(Equal("some-handle"))
+	})
+
+	It("responds with the stats", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var stats gardener.ContainerNetworkStats
+		Expect(json.NewDecoder(recorder.Body).Decode(&stats)).To(Succeed())
+		Expect(stats).To(Equal(gardener.ContainerNetworkStats{RxBytes: 10, TxPackets: 2, RxDropped: 1}))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/network-stats?handle=some-handle", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(statser.NetworkStatsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no handle is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/network-stats", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(statser.NetworkStatsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			statser.NetworkStatsReturns(gardener.ContainerNetworkStats{}, garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when reading the stats fails", func() {
+		BeforeEach(func() {
+			statser.NetworkStatsReturns(gardener.ContainerNetworkStats{}, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
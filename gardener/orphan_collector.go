@@ -0,0 +1,136 @@
+package gardener
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-golang/clock"
+)
+
+//go:generate counterfeiter . OrphanCleaner
+
+// An OrphanCleaner knows about some kind of per-container resource and can
+// find and remove the ones which no longer belong to a container.
+type OrphanCleaner interface {
+	Orphans(log lager.Logger, knownHandles []string) ([]string, error)
+	CleanOrphan(log lager.Logger, handle string) error
+}
+
+// NamedOrphanCleaner identifies an OrphanCleaner in the collector's logs
+type NamedOrphanCleaner struct {
+	Name string
+	OrphanCleaner
+}
+
+// OrphanCollector periodically removes resources which were left behind by
+// crashes or failed destroys, i.e. those belonging to handles which no longer
+// have a container.
+type OrphanCollector struct {
+	Interval time.Duration
+	Logger   lager.Logger
+	Clock    clock.Clock
+
+	containerizer Containerizer
+	cleaners      []NamedOrphanCleaner
+	stopped       chan struct{}
+}
+
+func NewOrphanCollector(
+	logger lager.Logger,
+	containerizer Containerizer,
+	cleaners []NamedOrphanCleaner,
+	interval time.Duration,
+	clock clock.Clock,
+) *OrphanCollector {
+	return &OrphanCollector{
+		Interval: interval,
+		Logger:   logger,
+		Clock:    clock,
+
+		containerizer: containerizer,
+		cleaners:      cleaners,
+		stopped:       make(chan struct{}),
+	}
+}
+
+func (c *OrphanCollector) Start() {
+	log := c.Logger.Session("orphan-collector", lager.Data{"interval": c.Interval.String()})
+	log.Info("starting")
+	ticker := c.Clock.NewTicker(c.Interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		log.Info("started")
+		defer log.Info("finished")
+
+		for {
+			select {
+			case <-ticker.C():
+				c.Collect(log)
+			case <-c.stopped:
+				return
+			}
+		}
+	}()
+}
+
+func (c *OrphanCollector) Stop() {
+	close(c.stopped)
+}
+
+// Collect runs every cleaner once, in order, removing the orphans it finds
+func (c *OrphanCollector) Collect(log lager.Logger) {
+	log = log.Session("collect")
+
+	log.Debug("start")
+	defer log.Debug("finished")
+
+	knownHandles, err := c.containerizer.Handles()
+	if err != nil {
+		log.Error("handles-failed", err)
+		return
+	}
+
+	for _, cleaner := range c.cleaners {
+		cleanerLog := log.Session(cleaner.Name)
+
+		orphans, err := cleaner.Orphans(cleanerLog, knownHandles)
+		if err != nil {
+			cleanerLog.Error("finding-orphans-failed", err)
+			continue
+		}
+
+		if len(orphans) == 0 {
+			continue
+		}
+
+		// a container may have been created since the handles were listed
+		currentHandles, err := c.containerizer.Handles()
+		if err != nil {
+			cleanerLog.Error("handles-failed", err)
+			continue
+		}
+
+		for _, handle := range orphans {
+			if contains(currentHandles, handle) {
+				continue
+			}
+
+			cleanerLog.Info("cleaning-orphan", lager.Data{"handle": handle})
+			if err := cleaner.CleanOrphan(cleanerLog, handle); err != nil {
+				cleanerLog.Error("cleaning-orphan-failed", err, lager.Data{"handle": handle})
+			}
+		}
+	}
+}
+
+func contains(handles []string, handle string) bool {
+	for _, h := range handles {
+		if h == handle {
+			return true
+		}
+	}
+
+	return false
+}
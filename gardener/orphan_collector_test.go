@@ -0,0 +1,167 @@
+package gardener_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrphanCollector", func() {
+	var (
+		logger        *lagertest.TestLogger
+		containerizer *fakes.FakeContainerizer
+		cleaner       *fakes.FakeOrphanCleaner
+		clock         *fakeclock.FakeClock
+
+		collector *gardener.OrphanCollector
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		containerizer = new(fakes.FakeContainerizer)
+		cleaner = new(fakes.FakeOrphanCleaner)
+		clock = fakeclock.NewFakeClock(time.Unix(123, 456))
+
+		containerizer.HandlesReturns([]string{"handle-1", "handle-2"}, nil)
+		cleaner.OrphansReturns([]string{"orphan-1", "orphan-2"}, nil)
+
+		collector = gardener.NewOrphanCollector(logger, containerizer, []gardener.NamedOrphanCleaner{{Name: "cleaner", OrphanCleaner: cleaner}}, time.Minute, clock)
+	})
+
+	Describe("Collect", func() {
+		It("asks the cleaners for orphans of the known handles", func() {
+			collector.Collect(logger)
+
+			Expect(cleaner.OrphansCallCount()).To(Equal(1))
+			_, knownHandles := cleaner.OrphansArgsForCall(0)
+			Expect(knownHandles).To(Equal([]string{"handle-1", "handle-2"}))
+		})
+
+		It("cleans the orphans", func() {
+			collector.Collect(logger)
+
+			Expect(cleaner.CleanOrphanCallCount()).To(Equal(2))
+			_, handle := cleaner.CleanOrphanArgsForCall(0)
+			Expect(handle).To(Equal("orphan-1"))
+			_, handle = cleaner.CleanOrphanArgsForCall(1)
+			Expect(handle).To(Equal("orphan-2"))
+		})
+
+		Context("when a container is created for an orphan while collecting", func() {
+			BeforeEach(func() {
+				containerizer.HandlesReturnsOnCall(1, []string{"handle-1", "handle-2", "orphan-2"}, nil)
+			})
+
+			It("does not clean it", func() {
+				collector.Collect(logger)
+
+				Expect(cleaner.CleanOrphanCallCount()).To(Equal(1))
+				_, handle := cleaner.CleanOrphanArgsForCall(0)
+				Expect(handle).To(Equal("orphan-1"))
+			})
+		})
+
+		Context("when there are no orphans", func() {
+			BeforeEach(func() {
+				cleaner.OrphansReturns([]string{}, nil)
+			})
+
+			It("does not clean anything", func() {
+				collector.Collect(logger)
+				Expect(cleaner.CleanOrphanCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when listing the handles fails", func() {
+			BeforeEach(func() {
+				containerizer.HandlesReturns(nil, errors.New("boom"))
+			})
+
+			It("does not clean anything", func() {
+				collector.Collect(logger)
+
+				Expect(cleaner.OrphansCallCount()).To(Equal(0))
+				Expect(cleaner.CleanOrphanCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when finding orphans fails", func() {
+			BeforeEach(func() {
+				cleaner.OrphansReturns(nil, errors.New("boom"))
+			})
+
+			It("does not clean anything", func() {
+				collector.Collect(logger)
+				Expect(cleaner.CleanOrphanCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when cleaning an orphan fails", func() {
+			BeforeEach(func() {
+				cleaner.CleanOrphanReturnsOnCall(0, errors.New("boom"))
+			})
+
+			It("carries on cleaning the others", func() {
+				collector.Collect(logger)
+				Expect(cleaner.CleanOrphanCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when there are several cleaners", func() {
+		var otherCleaner *fakes.FakeOrphanCleaner
+
+		BeforeEach(func() {
+			otherCleaner = new(fakes.FakeOrphanCleaner)
+			otherCleaner.OrphansReturns([]string{"orphan-1"}, nil)
+
+			collector = gardener.NewOrphanCollector(logger, containerizer, []gardener.NamedOrphanCleaner{
+				{Name: "cleaner", OrphanCleaner: cleaner},
+				{Name: "other-cleaner", OrphanCleaner: otherCleaner},
+			}, time.Minute, clock)
+		})
+
+		It("runs them in order", func() {
+			var calls []string
+			cleaner.CleanOrphanStub = func(_ lager.Logger, handle string) error {
+				calls = append(calls, "cleaner")
+				return nil
+			}
+			otherCleaner.CleanOrphanStub = func(_ lager.Logger, handle string) error {
+				calls = append(calls, "other-cleaner")
+				return nil
+			}
+
+			collector.Collect(logger)
+			Expect(calls).To(Equal([]string{"cleaner", "cleaner", "other-cleaner"}))
+		})
+	})
+
+	Describe("Start", func() {
+		BeforeEach(func() {
+			collector.Start()
+		})
+
+		AfterEach(func() {
+			collector.Stop()
+		})
+
+		It("collects when the interval elapses", func() {
+			Consistently(cleaner.OrphansCallCount).Should(Equal(0))
+
+			clock.Increment(time.Minute)
+			Eventually(cleaner.OrphansCallCount).Should(Equal(1))
+
+			clock.Increment(time.Minute)
+			Eventually(cleaner.OrphansCallCount).Should(Equal(2))
+		})
+	})
+})
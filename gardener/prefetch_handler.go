@@ -0,0 +1,37 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+)
+
+//go:generate counterfeiter . ImagePrefetcher
+type ImagePrefetcher interface {
+	PrefetchImage(containerSpec garden.ContainerSpec) error
+}
+
+// PrefetchHandler fetches and caches the image of the garden.ContainerSpec
+// POSTed to it, so that later creates using the image are fast.
+func PrefetchHandler(prefetcher ImagePrefetcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var containerSpec garden.ContainerSpec
+		if err := json.NewDecoder(r.Body).Decode(&containerSpec); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := prefetcher.PrefetchImage(containerSpec); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
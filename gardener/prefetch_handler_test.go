@@ -0,0 +1,76 @@
+package gardener_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrefetchHandler", func() {
+	var (
+		prefetcher *fakes.FakeImagePrefetcher
+		recorder   *httptest.ResponseRecorder
+		request    *http.Request
+	)
+
+	BeforeEach(func() {
+		prefetcher = new(fakes.FakeImagePrefetcher)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/prefetch", strings.NewReader(`{"Image":{"URI":"docker:///cflinuxfs2"}}`))
+	})
+
+	JustBeforeEach(func() {
+		gardener.PrefetchHandler(prefetcher).ServeHTTP(recorder, request)
+	})
+
+	It("prefetches the image of the posted container spec", func() {
+		Expect(prefetcher.PrefetchImageCallCount()).To(Equal(1))
+		Expect(prefetcher.PrefetchImageArgsForCall(0)).To(Equal(garden.ContainerSpec{
+			Image: garden.ImageRef{URI: "docker:///cflinuxfs2"},
+		}))
+	})
+
+	It("responds with no content", func() {
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/prefetch", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(prefetcher.PrefetchImageCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not a valid container spec", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/prefetch", strings.NewReader("potato"))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(prefetcher.PrefetchImageCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when prefetching fails", func() {
+		BeforeEach(func() {
+			prefetcher.PrefetchImageReturns(errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+//go:generate counterfeiter . ContainerPreviewer
+type ContainerPreviewer interface {
+	PreviewContainer(containerSpec garden.ContainerSpec) (specs.Spec, error)
+}
+
+// PreviewHandler serves the OCI spec that would be generated for the
+// garden.ContainerSpec POSTed to it, for debugging bundle generation.
+func PreviewHandler(previewer ContainerPreviewer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var containerSpec garden.ContainerSpec
+		if err := json.NewDecoder(r.Body).Decode(&containerSpec); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		runtimeSpec, err := previewer.PreviewContainer(containerSpec)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(runtimeSpec)
+	})
+}
@@ -0,0 +1,81 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		previewer *fakes.FakeContainerPreviewer
+		recorder  *httptest.ResponseRecorder
+		request   *http.Request
+	)
+
+	BeforeEach(func() {
+		previewer = new(fakes.FakeContainerPreviewer)
+		previewer.PreviewContainerReturns(specs.Spec{Hostname: "some-handle"}, nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("POST", "/debug/preview", strings.NewReader(`{"Handle":"some-handle","Privileged":true}`))
+	})
+
+	JustBeforeEach(func() {
+		gardener.PreviewHandler(previewer).ServeHTTP(recorder, request)
+	})
+
+	It("previews the posted container spec", func() {
+		Expect(previewer.PreviewContainerCallCount()).To(Equal(1))
+		Expect(previewer.PreviewContainerArgsForCall(0)).To(Equal(garden.ContainerSpec{Handle: "some-handle", Privileged: true}))
+	})
+
+	It("responds with the previewed OCI spec", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var runtimeSpec specs.Spec
+		Expect(json.NewDecoder(recorder.Body).Decode(&runtimeSpec)).To(Succeed())
+		Expect(runtimeSpec.Hostname).To(Equal("some-handle"))
+	})
+
+	Context("when the request is not a POST", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/preview", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(previewer.PreviewContainerCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the body is not a valid container spec", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/preview", strings.NewReader("potato"))
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(previewer.PreviewContainerCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when previewing fails", func() {
+		BeforeEach(func() {
+			previewer.PreviewContainerReturns(specs.Spec{}, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
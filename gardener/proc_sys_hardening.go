@@ -0,0 +1,32 @@
+package gardener
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProcSysHardeningKey is the property an unprivileged container can set to
+// RelaxedProcSysHardening to be exempt from the /proc and /sys hardening.
+// Only operators who allow relaxation can create such containers.
+const ProcSysHardeningKey = "garden.proc-sys-hardening"
+
+const RelaxedProcSysHardening = "relaxed"
+
+// relaxProcSysHardening returns whether a container with the given properties
+// should be exempt from the /proc and /sys hardening
+func relaxProcSysHardening(properties map[string]string, allowed bool) (bool, error) {
+	value, ok := properties[ProcSysHardeningKey]
+	if !ok {
+		return false, nil
+	}
+
+	if value != RelaxedProcSysHardening {
+		return false, fmt.Errorf("invalid %s property: '%s'", ProcSysHardeningKey, value)
+	}
+
+	if !allowed {
+		return false, errors.New("relaxing /proc and /sys hardening is disabled")
+	}
+
+	return true, nil
+}
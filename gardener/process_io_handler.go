@@ -0,0 +1,92 @@
+package gardener
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/pkg/iomux"
+)
+
+//go:generate counterfeiter . ContainerProcessAttacher
+type ContainerProcessAttacher interface {
+	AttachProcess(handle, processID string, processIO garden.ProcessIO) (garden.Process, error)
+}
+
+// ProcessIOHandler attaches to the process named by the 'process' query
+// parameter in the container named by the 'handle' query parameter, and
+// streams its stdout and stderr followed by its exit status over the response
+// as iomux frames. The process keeps running if the client falls behind or
+// goes away. A client which falls too far behind is sent an error frame and
+// the response ends.
+func ProcessIOHandler(attacher ContainerProcessAttacher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		handle := r.URL.Query().Get("handle")
+		if handle == "" {
+			http.Error(w, "missing handle", http.StatusBadRequest)
+			return
+		}
+
+		processID := r.URL.Query().Get("process")
+		if processID == "" {
+			http.Error(w, "missing process", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+
+		mux := iomux.NewWriter(w)
+		// the process's output must not be written to the response once the
+		// handler has returned
+		defer mux.Close()
+
+		process, err := attacher.AttachProcess(handle, processID, garden.ProcessIO{
+			Stdout: mux.Stdout(),
+			Stderr: mux.Stderr(),
+		})
+		switch err.(type) {
+		case nil:
+		case garden.ContainerNotFoundError, garden.ProcessNotFoundError:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// output may already be being written, so the headers are sent
+		// through the mux rather than directly
+		mux.Flush()
+
+		exited := make(chan processExit, 1)
+		go func() {
+			status, err := process.Wait()
+			exited <- processExit{status: status, err: err}
+		}()
+
+		select {
+		case exit := <-exited:
+			// the stream ends with an error frame rather than an exit status
+			// when the process could not be waited for
+			if exit.err != nil {
+				mux.Error(exit.err)
+				return
+			}
+
+			mux.Exit(exit.status)
+		case <-mux.Done():
+			// the output was detached and the stream ended with an error frame
+		case <-r.Context().Done():
+			// the client has gone away
+		}
+	})
+}
+
+type processExit struct {
+	status int
+	err    error
+}
@@ -0,0 +1,191 @@
+package gardener_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	"code.cloudfoundry.org/guardian/pkg/iomux"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProcessIOHandler", func() {
+	var (
+		attacher *fakes.FakeContainerProcessAttacher
+		process  *gardenfakes.FakeProcess
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		process = new(gardenfakes.FakeProcess)
+		process.WaitReturns(3, nil)
+
+		attacher = new(fakes.FakeContainerProcessAttacher)
+		attacher.AttachProcessStub = func(_, _ string, processIO garden.ProcessIO) (garden.Process, error) {
+			fmt.Fprint(processIO.Stdout, "some-output")
+			fmt.Fprint(processIO.Stderr, "some-error")
+			return process, nil
+		}
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/process-io?handle=some-handle&process=some-process", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.ProcessIOHandler(attacher).ServeHTTP(recorder, request)
+	})
+
+	readFrames := func() []iomux.Frame {
+		var frames []iomux.Frame
+		for {
+			frame, err := iomux.ReadFrame(recorder.Body)
+			if err == io.EOF {
+				return frames
+			}
+			Expect(err).NotTo(HaveOccurred())
+			frames = append(frames, frame)
+		}
+	}
+
+	It("attaches to the requested process", func() {
+		Expect(attacher.AttachProcessCallCount()).To(Equal(1))
+		handle, processID, _ := attacher.AttachProcessArgsForCall(0)
+		Expect(handle).To(Equal("some-handle"))
+		Expect(processID).To(Equal("some-process"))
+	})
+
+	It("streams the output of the process followed by its exit status", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		frames := readFrames()
+		Expect(frames).To(HaveLen(3))
+		Expect(frames[0]).To(Equal(iomux.Frame{Stream: iomux.StdoutStream, Payload: []byte("some-output")}))
+		Expect(frames[1]).To(Equal(iomux.Frame{Stream: iomux.StderrStream, Payload: []byte("some-error")}))
+		Expect(frames[2].Stream).To(Equal(iomux.ExitStream))
+		Expect(frames[2].ExitStatus()).To(Equal(3))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/process-io?handle=some-handle&process=some-process", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(attacher.AttachProcessCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no process is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/process-io?handle=some-handle", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(attacher.AttachProcessCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the process does not exist", func() {
+		BeforeEach(func() {
+			attacher.AttachProcessStub = nil
+			attacher.AttachProcessReturns(nil, garden.ProcessNotFoundError{ProcessID: "some-process"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when attaching fails", func() {
+		BeforeEach(func() {
+			attacher.AttachProcessStub = nil
+			attacher.AttachProcessReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+
+	Context("when the output is detached because the client fell behind", func() {
+		var running chan struct{}
+
+		BeforeEach(func() {
+			running = make(chan struct{})
+			process.WaitStub = func() (int, error) {
+				<-running
+				return 0, nil
+			}
+
+			attacher.AttachProcessStub = func(_, _ string, processIO garden.ProcessIO) (garden.Process, error) {
+				fmt.Fprint(processIO.Stdout, "some-output")
+				processIO.Stdout.(interface {
+					CloseWithError(error) error
+				}).CloseWithError(errors.New("fell behind"))
+				return process, nil
+			}
+		})
+
+		AfterEach(func() {
+			close(running)
+		})
+
+		It("ends the stream with an error frame without waiting for the process", func() {
+			frames := readFrames()
+			Expect(frames).To(HaveLen(2))
+			Expect(frames[0]).To(Equal(iomux.Frame{Stream: iomux.StdoutStream, Payload: []byte("some-output")}))
+			Expect(frames[1]).To(Equal(iomux.Frame{Stream: iomux.ErrorStream, Payload: []byte("fell behind")}))
+		})
+	})
+
+	Context("when the client goes away", func() {
+		var running chan struct{}
+
+		BeforeEach(func() {
+			running = make(chan struct{})
+			process.WaitStub = func() (int, error) {
+				<-running
+				return 0, nil
+			}
+
+			ctx, cancel := context.WithCancel(request.Context())
+			cancel()
+			request = request.WithContext(ctx)
+		})
+
+		AfterEach(func() {
+			close(running)
+		})
+
+		It("stops waiting for the process, without an exit status", func() {
+			frames := readFrames()
+			Expect(frames).To(HaveLen(2))
+			Expect(frames[1].Stream).To(Equal(iomux.StderrStream))
+		})
+	})
+
+	Context("when waiting for the process fails", func() {
+		BeforeEach(func() {
+			process.WaitReturns(0, errors.New("lost"))
+		})
+
+		It("ends the stream with an error frame rather than an exit status", func() {
+			frames := readFrames()
+			Expect(frames).To(HaveLen(3))
+			Expect(frames[1].Stream).To(Equal(iomux.StderrStream))
+			Expect(frames[2]).To(Equal(iomux.Frame{Stream: iomux.ErrorStream, Payload: []byte("lost")}))
+		})
+	})
+})
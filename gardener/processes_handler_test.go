@@ -0,0 +1,95 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProcessesHandler", func() {
+	var (
+		lister   *fakes.FakeContainerProcessLister
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		lister = new(fakes.FakeContainerProcessLister)
+		lister.ProcessesReturns([]gardener.ContainerProcess{
+			{Pid: 1234},
+			{Pid: 1240, ProcessID: "some-process"},
+		}, nil)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/processes?handle=some-handle", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.ProcessesHandler(lister).ServeHTTP(recorder, request)
+	})
+
+	It("lists the processes of the requested container", func() {
+		Expect(lister.ProcessesCallCount()).To(Equal(1))
+		Expect(lister.ProcessesArgsForCall(0)).To(Equal("some-handle"))
+	})
+
+	It("responds with the processes", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var processes []gardener.ContainerProcess
+		Expect(json.NewDecoder(recorder.Body).Decode(&processes)).To(Succeed())
+		Expect(processes).To(Equal([]gardener.ContainerProcess{
+			{Pid: 1234},
+			{Pid: 1240, ProcessID: "some-process"},
+		}))
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/processes?handle=some-handle", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(lister.ProcessesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no handle is given", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("GET", "/debug/processes", nil)
+		})
+
+		It("responds with bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(lister.ProcessesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			lister.ProcessesReturns(nil, garden.ContainerNotFoundError{Handle: "some-handle"})
+		})
+
+		It("responds with not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when listing the processes fails", func() {
+		BeforeEach(func() {
+			lister.ProcessesReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with the error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("boom"))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package gardener
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// QuarantinedContainer describes the artifacts of a failed container which
+// were kept for inspection. Path is where they were kept.
+type QuarantinedContainer struct {
+	Handle        string    `json:"handle"`
+	Reason        string    `json:"reason"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	Path          string    `json:"path"`
+}
+
+//go:generate counterfeiter . Quarantiner
+type Quarantiner interface {
+	Quarantine(log lager.Logger, handle, reason string) error
+}
+
+// quarantine keeps the artifacts of a container which is about to be
+// destroyed because it failed, if a Quarantiner is set. Failing to do so does
+// not stop the container from being destroyed.
+func (g *Gardener) quarantine(log lager.Logger, handle, reason string) {
+	if g.Quarantiner == nil {
+		return
+	}
+
+	if err := g.Quarantiner.Quarantine(log, handle, reason); err != nil {
+		log.Error("quarantine-failed", err)
+	}
+}
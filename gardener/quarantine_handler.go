@@ -0,0 +1,31 @@
+package gardener
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//go:generate counterfeiter . QuarantineLister
+type QuarantineLister interface {
+	List() ([]QuarantinedContainer, error)
+}
+
+// QuarantineHandler serves the containers whose artifacts are being kept in
+// quarantine, so that operators can find and inspect them before they expire
+func QuarantineHandler(lister QuarantineLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		quarantined, err := lister.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(quarantined)
+	})
+}
@@ -0,0 +1,73 @@
+package gardener_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuarantineHandler", func() {
+	var (
+		lister   *fakes.FakeQuarantineLister
+		recorder *httptest.ResponseRecorder
+		request  *http.Request
+	)
+
+	BeforeEach(func() {
+		lister = new(fakes.FakeQuarantineLister)
+		lister.ListReturns([]gardener.QuarantinedContainer{{
+			Handle:        "failed-handle",
+			Reason:        "create failed: boom",
+			QuarantinedAt: time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC),
+			Path:          "/var/gdn/quarantine/failed-handle-1",
+		}}, nil)
+
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest("GET", "/debug/quarantine", nil)
+	})
+
+	JustBeforeEach(func() {
+		gardener.QuarantineHandler(lister).ServeHTTP(recorder, request)
+	})
+
+	It("responds with the quarantined containers", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response []map[string]string
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		Expect(response).To(Equal([]map[string]string{{
+			"handle":         "failed-handle",
+			"reason":         "create failed: boom",
+			"quarantined_at": "2017-06-01T12:00:00Z",
+			"path":           "/var/gdn/quarantine/failed-handle-1",
+		}}))
+	})
+
+	Context("when listing the quarantine fails", func() {
+		BeforeEach(func() {
+			lister.ListReturns(nil, errors.New("boom"))
+		})
+
+		It("responds with an internal server error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("when the request is not a GET", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest("POST", "/debug/quarantine", nil)
+		})
+
+		It("responds with method not allowed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(lister.ListCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package gardener
+
+//go:generate counterfeiter . ResourceStore
+
+// ContainerResources records the host resources allocated to a container, so
+// that they can be found and released even if the container's bundle or
+// properties have been lost
+type ContainerResources struct {
+	VolumePath  string   `json:"volume_path,omitempty"`
+	CgroupPath  string   `json:"cgroup_path,omitempty"`
+	ContainerIP string   `json:"container_ip,omitempty"`
+	Subnet      string   `json:"subnet,omitempty"`
+	BridgeIP    string   `json:"bridge_ip,omitempty"`
+	HostPorts   []uint32 `json:"host_ports,omitempty"`
+
+	// DiskQuota is the container's hard disk limit in bytes, or 0 if it has none
+	DiskQuota uint64 `json:"disk_quota,omitempty"`
+
+	// MemoryLimit and CPUShares are the container's current memory limit in
+	// bytes and CPU shares, or 0 if it has none
+	MemoryLimit uint64 `json:"memory_limit,omitempty"`
+	CPUShares   uint64 `json:"cpu_shares,omitempty"`
+
+	// DestroyPending is set when a destroy failed and was deferred, queueing
+	// the container for the DeferredCleaner
+	DestroyPending bool `json:"destroy_pending,omitempty"`
+
+	// DestroyAttempts counts the failed retries of a deferred destroy
+	DestroyAttempts int `json:"destroy_attempts,omitempty"`
+}
+
+// ResourceStore durably maps container handles to the resources allocated to
+// them. An entry is written as soon as a container starts being created and
+// is only removed once every one of its resources has been released.
+type ResourceStore interface {
+	Get(handle string) (ContainerResources, error)
+	Set(handle string, resources ContainerResources) error
+	Remove(handle string) error
+	Handles() ([]string, error)
+}
@@ -0,0 +1,65 @@
+package gardener
+
+import "fmt"
+
+const SecurityPresetKey = "garden.security-preset"
+
+const (
+	RestrictedSecurityPreset       = "restricted"
+	BaselineSecurityPreset         = "baseline"
+	PrivilegedCompatSecurityPreset = "privileged-compat"
+)
+
+// SecurityPresets lists the security presets an unprivileged container can be
+// confined with, from the most to the least restrictive
+var SecurityPresets = []string{
+	RestrictedSecurityPreset,
+	BaselineSecurityPreset,
+	PrivilegedCompatSecurityPreset,
+}
+
+func securityPresetRank(name string) (int, error) {
+	for i, preset := range SecurityPresets {
+		if preset == name {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown security preset: %s", name)
+}
+
+// securityPreset returns the preset a container requesting the given preset
+// should be confined with. Containers which do not request one keep the
+// defaults, which match the baseline preset, unless max is more restrictive
+// than that. An empty max is the baseline preset, so that operators have to
+// opt in to less restrictive presets.
+func securityPreset(requested, max string) (string, error) {
+	if max == "" {
+		max = BaselineSecurityPreset
+	}
+
+	maxRank, err := securityPresetRank(max)
+	if err != nil {
+		return "", err
+	}
+
+	if requested == "" {
+		baselineRank, _ := securityPresetRank(BaselineSecurityPreset)
+		if maxRank < baselineRank {
+			return max, nil
+		}
+
+		return "", nil
+	}
+
+	requestedRank, err := securityPresetRank(requested)
+	if err != nil {
+		return "", err
+	}
+
+	if requestedRank > maxRank {
+		return "", fmt.Errorf("security preset '%s' exceeds the maximum allowed preset '%s'", requested, max)
+	}
+
+	return requested, nil
+}
@@ -2,9 +2,11 @@ package gardener
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
+	"strings"
 
 	"code.cloudfoundry.org/commandrunner"
 	"code.cloudfoundry.org/garden"
@@ -14,6 +16,10 @@ import (
 
 const RawRootFSScheme = "raw"
 
+// RootfsLayersKey is the property which lists, comma-separated and lowest
+// first, the URIs of read-only layers to combine over the container's rootfs
+const RootfsLayersKey = "garden.rootfs-layers"
+
 type CommandFactory func(rootFSPathFile string, uid, gid int, mode os.FileMode, recreate bool, paths ...string) *exec.Cmd
 
 type VolumeProvider struct {
@@ -40,6 +46,13 @@ type VolumeCreator interface {
 	Create(log lager.Logger, handle string, spec RootfsSpec) (specs.Spec, error)
 }
 
+// A RootfsLayerer is a VolumeCreator which can apply RootfsSpec.Layers. Other
+// VolumeCreators would ignore them, so containers which ask for layers are
+// not created with them.
+type RootfsLayerer interface {
+	AppliesRootfsLayers() bool
+}
+
 // TODO GoRename RootfsSpec
 type RootfsSpec struct {
 	RootFS     *url.URL
@@ -48,17 +61,14 @@ type RootfsSpec struct {
 	Namespaced bool
 	QuotaSize  int64
 	QuotaScope garden.DiskLimitScope
+
+	// Layers are read-only root filesystems to overlay, in order, on top of
+	// RootFS
+	Layers []*url.URL
 }
 
 func (v *VolumeProvider) Create(log lager.Logger, spec garden.ContainerSpec) (specs.Spec, error) {
-	path := spec.Image.URI
-	if path == "" {
-		path = spec.RootFSPath
-	} else if spec.RootFSPath != "" {
-		return specs.Spec{}, errors.New("Cannot provide both Image.URI and RootFSPath")
-	}
-
-	rootFSURL, err := url.Parse(path)
+	rootFSURL, layers, err := v.rootfs(spec)
 	if err != nil {
 		return specs.Spec{}, err
 	}
@@ -76,6 +86,7 @@ func (v *VolumeProvider) Create(log lager.Logger, spec garden.ContainerSpec) (sp
 			QuotaSize:  int64(spec.Limits.Disk.ByteHard),
 			QuotaScope: spec.Limits.Disk.Scope,
 			Namespaced: !spec.Privileged,
+			Layers:     layers,
 		})
 		if err != nil {
 			return specs.Spec{}, err
@@ -87,6 +98,72 @@ func (v *VolumeProvider) Create(log lager.Logger, spec garden.ContainerSpec) (sp
 	return baseConfig, nil
 }
 
+// Plan checks the rootfs and layers the container asks for, without creating
+// its volume
+func (v *VolumeProvider) Plan(log lager.Logger, spec garden.ContainerSpec) error {
+	_, _, err := v.rootfs(spec)
+	return err
+}
+
+// rootfs returns the rootfs and layers the container asks for, if the volume
+// creator can combine them
+func (v *VolumeProvider) rootfs(spec garden.ContainerSpec) (*url.URL, []*url.URL, error) {
+	path := spec.Image.URI
+	if path == "" {
+		path = spec.RootFSPath
+	} else if spec.RootFSPath != "" {
+		return nil, nil, errors.New("Cannot provide both Image.URI and RootFSPath")
+	}
+
+	rootFSURL, err := url.Parse(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	layers, err := rootfsLayers(spec.Properties)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(layers) == 0 {
+		return rootFSURL, nil, nil
+	}
+
+	if rootFSURL.Scheme == RawRootFSScheme {
+		return nil, nil, errors.New("rootfs layers cannot be combined with a raw rootfs")
+	}
+
+	if layerer, ok := v.VolumeCreator.(RootfsLayerer); !ok || !layerer.AppliesRootfsLayers() {
+		return nil, nil, errors.New("rootfs layers are not supported by the configured volume creator: they require graph snapshots")
+	}
+
+	return rootFSURL, layers, nil
+}
+
+func rootfsLayers(properties garden.Properties) ([]*url.URL, error) {
+	value, ok := properties[RootfsLayersKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var layers []*url.URL
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			return nil, fmt.Errorf("invalid %s property: empty layer", RootfsLayersKey)
+		}
+
+		layer, err := url.Parse(uri)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s property: %s", RootfsLayersKey, err)
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
 func (v *VolumeProvider) mkdirAndChown(namespaced bool, spec specs.Spec) error {
 	var uid, gid int
 	if namespaced {
@@ -120,6 +120,22 @@ var _ = Describe("VolumeProvider", func() {
 					Expect(runtimeSpec).To(Equal(specs.Spec{Version: "best-spec", Root: &specs.Root{Path: "/hello"}}))
 				})
 
+				Context("when the container has rootfs layers", func() {
+					BeforeEach(func() {
+						containerSpec.Properties = garden.Properties{
+							gardener.RootfsLayersKey: "/stacks/framework, docker:///buildpack@sha256:abc",
+						}
+						volumeProvider = gardener.NewVolumeProvider(layeringVolumeCreator{volumeCreator}, nil, mkdirCommandStub, cmdRunner, 5, 5)
+					})
+
+					It("passes the layers to the VolumeCreator in order", func() {
+						_, _, rootfsSpec := volumeCreator.CreateArgsForCall(0)
+						Expect(rootfsSpec.Layers).To(HaveLen(2))
+						Expect(rootfsSpec.Layers[0].String()).To(Equal("/stacks/framework"))
+						Expect(rootfsSpec.Layers[1].String()).To(Equal("docker:///buildpack@sha256:abc"))
+					})
+				})
+
 				Context("when the container is not usernamespaced", func() {
 					BeforeEach(func() {
 						containerSpec = garden.ContainerSpec{
@@ -192,6 +208,44 @@ var _ = Describe("VolumeProvider", func() {
 				})
 			})
 
+			Context("when rootfs layers are combined with a raw rootfs", func() {
+				BeforeEach(func() {
+					containerSpec.Properties = garden.Properties{gardener.RootfsLayersKey: "/stacks/framework"}
+				})
+
+				It("returns an error", func() {
+					Expect(createErr).To(MatchError(ContainSubstring("raw rootfs")))
+				})
+			})
+
+			Context("when the volume creator cannot apply rootfs layers", func() {
+				BeforeEach(func() {
+					containerSpec = garden.ContainerSpec{
+						Image:      garden.ImageRef{URI: "/path/to/some/rootfs"},
+						Properties: garden.Properties{gardener.RootfsLayersKey: "/stacks/framework"},
+					}
+				})
+
+				It("returns an error rather than creating the container without them", func() {
+					Expect(createErr).To(MatchError(ContainSubstring("rootfs layers are not supported")))
+					Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the rootfs layers property has an empty layer", func() {
+				BeforeEach(func() {
+					containerSpec = garden.ContainerSpec{
+						Image:      garden.ImageRef{URI: "/path/to/some/rootfs"},
+						Properties: garden.Properties{gardener.RootfsLayersKey: "/stacks/framework,,"},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(createErr).To(MatchError(ContainSubstring(gardener.RootfsLayersKey)))
+					Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when the Image URI is invalid", func() {
 				BeforeEach(func() {
 					containerSpec = garden.ContainerSpec{
@@ -205,4 +259,28 @@ var _ = Describe("VolumeProvider", func() {
 			})
 		})
 	})
+
+	Describe("Plan", func() {
+		It("accepts a rootfs the volume creator can create", func() {
+			Expect(volumeProvider.Plan(logger, garden.ContainerSpec{Image: garden.ImageRef{URI: "docker:///busybox"}})).To(Succeed())
+			Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+		})
+
+		It("returns the error Create would return, without creating the volume", func() {
+			err := volumeProvider.Plan(logger, garden.ContainerSpec{
+				Image:      garden.ImageRef{URI: "/path/to/some/rootfs"},
+				Properties: garden.Properties{gardener.RootfsLayersKey: "/stacks/framework"},
+			})
+			Expect(err).To(MatchError(ContainSubstring("rootfs layers are not supported")))
+			Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+		})
+	})
 })
+
+type layeringVolumeCreator struct {
+	*fakes.FakeVolumeCreator
+}
+
+func (layeringVolumeCreator) AppliesRootfsLayers() bool {
+	return true
+}
@@ -0,0 +1,74 @@
+package gqt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gqt/runner"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Committing a container's rootfs", func() {
+	var client *runner.RunningGarden
+
+	BeforeEach(func() {
+		config = resetImagePluginConfig()
+		config.DebugIP = "127.0.0.1"
+		config.DebugPort = intptr(9877)
+		config.ImageCommitDir = filepath.Join(config.TmpDir, "committed-images")
+	})
+
+	JustBeforeEach(func() {
+		client = runner.Start(config)
+	})
+
+	AfterEach(func() {
+		Expect(client.DestroyAndStop()).To(Succeed())
+	})
+
+	commit := func(handle, name string) string {
+		url := fmt.Sprintf("http://%s:%d/debug/commit?handle=%s&name=%s", config.DebugIP, *config.DebugPort, handle, name)
+		res, err := http.Post(url, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		var response map[string]string
+		Expect(json.NewDecoder(res.Body).Decode(&response)).To(Succeed())
+		return response["rootfs"]
+	}
+
+	It("returns a rootfs path from which containers can be created", func() {
+		container, err := client.Create(garden.ContainerSpec{})
+		Expect(err).NotTo(HaveOccurred())
+
+		process, err := container.Run(garden.ProcessSpec{
+			Path: "sh",
+			Args: []string{"-c", "echo committed > /committed-file"},
+		}, garden.ProcessIO{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(process.Wait()).To(Equal(0))
+		Expect(container.Stop(false)).To(Succeed())
+
+		rootfsPath := commit(container.Handle(), "some-image")
+		Expect(rootfsPath).To(BeADirectory())
+
+		committed, err := client.Create(garden.ContainerSpec{RootFSPath: rootfsPath})
+		Expect(err).NotTo(HaveOccurred())
+
+		stdout := gbytes.NewBuffer()
+		process, err = committed.Run(garden.ProcessSpec{
+			Path: "cat",
+			Args: []string{"/committed-file"},
+		}, garden.ProcessIO{Stdout: stdout})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(process.Wait()).To(Equal(0))
+		Expect(stdout).To(gbytes.Say("committed"))
+	})
+})
@@ -64,6 +64,7 @@ type GdnRunnerConfig struct {
 	DebugIP                        string   `flag:"debug-bind-ip"`
 	DebugPort                      *int     `flag:"debug-bind-port"`
 	PropertiesPath                 string   `flag:"properties-path"`
+	ImageCommitDir                 string   `flag:"image-commit-dir"`
 	PersistentImages               []string `flag:"persistent-image"`
 	GraphCleanupThresholdMB        *int     `flag:"graph-cleanup-threshold-in-megabytes"`
 	LogLevel                       string   `flag:"log-level"`
@@ -0,0 +1,46 @@
+package guardiancmd
+
+import (
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/guardian/sysinfo"
+)
+
+type CheckCommand struct {
+	ProcPath         string `hidden:"true" long:"proc-path" default:"/proc" description:"Path at which procfs is mounted."`
+	MinKernelVersion string `long:"min-kernel-version" default:"4.4" description:"Minimum supported kernel version."`
+	Depot            string `long:"depot" default:"/var/run/gdn/depot" description:"Directory in which to store container data."`
+	Graph            string `long:"graph" default:"/var/gdn/graph" description:"Directory on which to store imported rootfs graph data."`
+	IPTablesBin      string `long:"iptables-bin" default:"/sbin/iptables" description:"path to the iptables binary"`
+	ImagePlugin      bool   `long:"image-plugin" description:"An image plugin will be used, so aufs or overlay support is not required."`
+}
+
+func (cmd *CheckCommand) Execute(args []string) error {
+	checker := sysinfo.PreflightChecker{
+		ProcPath:         cmd.ProcPath,
+		MinKernelVersion: cmd.MinKernelVersion,
+		IPTablesBin:      cmd.IPTablesBin,
+		Dirs:             []string{cmd.Depot},
+		RequireLayeredFS: !cmd.ImagePlugin,
+	}
+	if !cmd.ImagePlugin {
+		checker.Dirs = append(checker.Dirs, cmd.Graph)
+	}
+
+	failures := 0
+	for _, result := range checker.Check() {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(os.Stdout, "FAIL %s: %s\n", result.Name, result.Err)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "ok   %s\n", result.Name)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d preflight check(s) failed", failures)
+	}
+
+	return nil
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -15,21 +17,29 @@ import (
 	"code.cloudfoundry.org/idmapper"
 	"code.cloudfoundry.org/lager"
 
+	"code.cloudfoundry.org/garden/client"
+	"code.cloudfoundry.org/garden/client/connection"
 	"code.cloudfoundry.org/garden/server"
 	"code.cloudfoundry.org/guardian/bindata"
+	"code.cloudfoundry.org/guardian/commit"
+	"code.cloudfoundry.org/guardian/coredumps"
 	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/hooks"
 	"code.cloudfoundry.org/guardian/imageplugin"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	kawasakifactory "code.cloudfoundry.org/guardian/kawasaki/factory"
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
 	"code.cloudfoundry.org/guardian/kawasaki/mtu"
 	"code.cloudfoundry.org/guardian/kawasaki/ports"
+	"code.cloudfoundry.org/guardian/kawasaki/proxy"
 	"code.cloudfoundry.org/guardian/kawasaki/subnets"
 	"code.cloudfoundry.org/guardian/logging"
 	"code.cloudfoundry.org/guardian/metrics"
 	"code.cloudfoundry.org/guardian/netplugin"
 	locksmithpkg "code.cloudfoundry.org/guardian/pkg/locksmith"
 	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/guardian/quarantine"
+	"code.cloudfoundry.org/guardian/resources"
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
 	"code.cloudfoundry.org/guardian/rundmc/depot"
@@ -41,6 +51,7 @@ import (
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/guardian/rundmc/stopper"
 	"code.cloudfoundry.org/guardian/sysinfo"
+	"code.cloudfoundry.org/guardian/webhook"
 	"github.com/cloudfoundry/dropsonde"
 	_ "github.com/docker/docker/daemon/graphdriver/aufs" // aufs needed for garden-shed
 	_ "github.com/docker/docker/pkg/chrootarchive"       // allow reexec of docker-applyLayer
@@ -49,6 +60,7 @@ import (
 	uuid "github.com/nu7hatch/gouuid"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/clock/fakeclock"
 	"github.com/pivotal-golang/localip"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/sigmon"
@@ -124,6 +136,9 @@ type GardenFactory interface {
 	WireCgroupsStarter(logger lager.Logger) gardener.Starter
 	WireExecRunner(runMode string) runrunc.ExecRunner
 	WireRootfsFileCreator() rundmc.RootfsFileCreator
+	WireContainerizer(log lager.Logger, properties gardener.PropertyManager, volumizer peas.Volumizer, peaCleaner gardener.PeaCleaner, lifecycle gardener.LifecycleNotifier) gardener.Containerizer
+	WireBaseBundles(initMount specs.Mount, initPath string) (privileged, unprivileged goci.Bndl)
+	WireIsolationRules() []rundmc.BundlerRule
 }
 
 // These are the maximum capabilities a non-root user gets whether privileged or unprivileged
@@ -167,6 +182,7 @@ var (
 type GdnCommand struct {
 	SetupCommand  *SetupCommand  `command:"setup"`
 	ServerCommand *ServerCommand `command:"server"`
+	CheckCommand  *CheckCommand  `command:"check" description:"Check that the host is able to run garden containers."`
 
 	// This must be present to stop go-flags complaining, but it's not actually
 	// used. We parse this flag outside of the go-flags framework.
@@ -176,15 +192,22 @@ type GdnCommand struct {
 type ServerCommand struct {
 	Logger LagerFlag
 
+	// clock drives the time-dependent behaviour of the server, other than the
+	// garden server's grace time reaper, which uses its own timers. It is a
+	// fake clock when Server.DebugFakeClock is set.
+	clock clock.Clock
+
 	Server struct {
 		BindIP   IPFlag `long:"bind-ip"   description:"Bind with TCP on the given IP."`
 		BindPort uint16 `long:"bind-port" description:"Bind with TCP on the given port."`
 
 		BindSocket string `long:"bind-socket" default:"/tmp/garden.sock" description:"Bind with Unix on the given socket path."`
 
-		DebugBindIP   IPFlag `long:"debug-bind-ip"                   description:"Bind the debug server on the given IP."`
+		DebugBindIP   IPFlag `long:"debug-bind-ip"                   description:"Bind the debug server on the given IP. The debug server is not authenticated; its endpoints which change containers or the server, such as /debug/bulk-create, /debug/commit, /debug/copy, /debug/destroy-matching, /debug/limits, /debug/network-reservations and /debug/prefetch, and those which expose what is in containers, such as /debug/files and /debug/process-io, are for operators on the host and are only served to loopback clients."`
 		DebugBindPort uint16 `long:"debug-bind-port" default:"17013" description:"Bind the debug server to the given port."`
 
+		DebugFakeClock bool `hidden:"true" long:"debug-fake-clock" description:"Drive deferred cleanup, orphan collection, snapshot retention, metrics emission and create timeouts from a fake clock, which only advances when told to through the /debug/clock endpoint of the debug server. Grace time reaping is scheduled by the garden server on the wall clock and is not affected."`
+
 		Tag       string `hidden:"true" long:"tag" description:"Optional 2-character identifier used for namespacing global configuration."`
 		SkipSetup bool   `long:"skip-setup" description:"Skip the preparation part of the host that requires root privileges"`
 	} `group:"Server Configuration"`
@@ -192,19 +215,63 @@ type ServerCommand struct {
 	Containers struct {
 		Dir                        string `long:"depot" default:"/var/run/gdn/depot" description:"Directory in which to store container data."`
 		PropertiesPath             string `long:"properties-path" description:"Path in which to store properties."`
+		PropertiesBackend          string `long:"properties-backend" default:"memory" choice:"memory" choice:"file" choice:"consul" description:"Where to store container properties. 'memory' keeps them in memory and saves them to --properties-path on shutdown, 'file' writes every change to --properties-dir and 'consul' writes every change to the Consul KV store at --properties-consul-url."`
+		PropertiesDir              string `long:"properties-dir" default:"/var/gdn/properties" description:"Directory in which the 'file' properties backend stores properties."`
+		PropertiesConsulURL        string `long:"properties-consul-url" description:"URL of the Consul agent used by the 'consul' properties backend, e.g. http://127.0.0.1:8500."`
+		PropertiesConsulPrefix     string `long:"properties-consul-prefix" description:"Key prefix under which the 'consul' properties backend stores properties. Must be unique to this server. Defaults to garden/properties/<hostname>."`
+		ResourceStoreDir           string `long:"resource-store-dir" default:"/var/run/gdn/resources" description:"Directory in which to record the resources allocated to each container, so that they can be cleaned up after a crash."`
 		ConsoleSocketsPath         string `long:"console-sockets-path" description:"Path in which to store temporary sockets"`
 		CleanupProcessDirsOnWait   bool   `long:"cleanup-process-dirs-on-wait" description:"Clean up proccess dirs on first invocation of wait"`
 		DisablePrivilgedContainers bool   `long:"disable-privileged-containers" description:"Disable creation of privileged containers"`
 
+		PropertiesConsulTimeout time.Duration `long:"properties-consul-timeout" default:"10s" description:"Timeout of each request the 'consul' properties backend makes to Consul."`
+
 		UIDMapStart  uint32 `long:"uid-map-start"  default:"1" description:"The lowest numerical subordinate user ID the user is allowed to map"`
 		UIDMapLength uint32 `long:"uid-map-length" description:"The number of numerical subordinate user IDs the user is allowed to map"`
 		GIDMapStart  uint32 `long:"gid-map-start"  default:"1" description:"The lowest numerical subordinate group ID the user is allowed to map"`
 		GIDMapLength uint32 `long:"gid-map-length" description:"The number of numerical subordinate group IDs the user is allowed to map"`
 
 		DefaultRootFS              string        `long:"default-rootfs"     description:"Default rootfs to use when not specified on container creation."`
-		DefaultGraceTime           time.Duration `long:"default-grace-time" description:"Default time after which idle containers should expire."`
+		DefaultGraceTime           time.Duration `long:"default-grace-time" description:"Default time after which idle containers should expire. Can be overridden per container with the garden.grace-time property."`
 		DestroyContainersOnStartup bool          `long:"destroy-containers-on-startup" description:"Clean up all the existing containers on startup."`
 		ApparmorProfile            string        `long:"apparmor" description:"Apparmor profile to use for unprivileged container processes"`
+		NoNewPrivileges            bool          `long:"no-new-privileges" description:"Set no_new_privs on the processes of unprivileged containers, so that setuid binaries cannot be used to gain privileges."`
+		OOMScoreAdj                *int          `long:"container-oom-score-adj" description:"oom_score_adj to give container init and exec'd processes, e.g. a positive value so the host OOM killer prefers them over the daemon's per-container helpers. Inherited from the daemon if not specified."`
+		NosuidRootfs               bool          `long:"nosuid-rootfs" description:"Remount the rootfs of unprivileged containers nosuid. Requires an image plugin which provides the rootfs as a mount point."`
+		ShareBindMounts            bool          `long:"share-bind-mounts" description:"Serve read-only bind mounts of directories with identical contents from a single shared read-only mount."`
+		SharedBindMountsDir        string        `long:"shared-bind-mounts-dir" default:"/var/run/gdn/shared-bind-mounts" description:"Directory in which to keep the mounts shared between containers."`
+		ProcHidePID                int           `long:"proc-hidepid" choice:"0" choice:"1" choice:"2" default:"0" description:"hidepid option to mount /proc with in unprivileged containers, so that processes cannot see the processes of other users. 0 leaves it unset."`
+		ProcMaskedPaths            []string      `long:"proc-masked-path" description:"Path, such as /proc/meminfo, to mask in unprivileged containers in addition to the defaults. Can be specified multiple times."`
+		ReadOnlySys                bool          `long:"read-only-sys" description:"Mount /sys and everything under it read-only in unprivileged containers, and make the kernel tunables under /proc, such as /proc/sys, read-only."`
+		AllowProcSysRelaxation     bool          `long:"allow-proc-sys-hardening-relaxation" description:"Allow unprivileged containers to be exempted from the /proc and /sys hardening by setting the garden.proc-sys-hardening property to 'relaxed'."`
+		MaxSecurityPreset          string        `long:"max-security-preset" default:"baseline" choice:"restricted" choice:"baseline" choice:"privileged-compat" description:"Least restrictive security preset unprivileged containers may select with the garden.security-preset property. Containers which do not select one get the baseline preset, or this preset if it is more restrictive."`
+		OrphanGCInterval           time.Duration `long:"orphan-gc-interval" default:"10m" description:"Interval on which to clean up resources left behind by crashes or failed destroys, or 0 to disable."`
+		DeferredCleanupInterval    time.Duration `long:"deferred-cleanup-interval" default:"30s" description:"Interval on which to retry destroys which failed to release a container's resources, e.g. because a mount was busy, or to delete a container whose init process had exited. Such destroys succeed and are retried in the background with exponential backoff; failures to delete a container while its init process is still running are always returned. 0 disables this, so that failed destroys return an error."`
+		DeferredCleanupMaxBackoff  time.Duration `long:"deferred-cleanup-max-backoff" default:"10m" description:"Maximum time to wait between retries of a failed destroy. 0 means no maximum."`
+		HealthProbeCheckInterval   time.Duration `long:"health-probe-check-interval" default:"1s" description:"Interval on which to run the health probes which are due, of containers which define one with the garden.health-probe.* properties. The health of probed containers is recorded in their garden.health-probe.status property and served on the /debug/health endpoint of the debug server. 0 disables health probes."`
+		StopKillTimeout            time.Duration `long:"stop-kill-timeout" default:"10s" description:"Time to wait for container processes to exit after sending them TERM on stop, before sending them KILL."`
+		DefaultProcessUser         string        `long:"default-process-user" description:"User to run container processes as when the process spec does not specify one. Defaults to root."`
+		DefaultProcessPath         string        `long:"default-process-path" description:"PATH to give container processes which do not set one. Defaults to a standard PATH, which includes the sbin directories for root."`
+
+		LifecycleWebhookURL     string        `long:"lifecycle-webhook-url" description:"URL to POST to when a container is created, destroyed, runs out of memory or exits."`
+		LifecycleWebhookTimeout time.Duration `long:"lifecycle-webhook-timeout" default:"10s" description:"Timeout for each request to the lifecycle webhook."`
+
+		CoreDumpDir        string `long:"core-dump-dir" description:"Directory in which to collect the core dumps of containers which set the garden.core-dumps property to 'enabled'. Each such container gets its own subdirectory, which is removed when it is destroyed. Disabled if not specified."`
+		CoreDumpPath       string `long:"core-dump-path" default:"/var/cores" description:"Path at which a container's core dump directory is bind mounted. Set the host's kernel.core_pattern to a file in this path, e.g. /var/cores/core.%e.%p, so that core dumps are written to it."`
+		MaxCoreDumpSize    uint64 `long:"max-core-dump-size" default:"1073741824" description:"Largest core dump, in bytes, to collect from a process. 0 means no limit."`
+		MaxCoreDumpDirSize uint64 `long:"max-core-dump-dir-size" default:"4294967296" description:"Most space, in bytes, the core dumps of a container may take up. Each container's core dump directory is a tmpfs of this size, charged to the container's memory. 0 means no limit."`
+
+		CreateTimeout time.Duration `long:"create-timeout" description:"Fail creates which take longer than this, as soon as they finish the phase they are in. A phase which hangs is not interrupted. The progress of creates is logged, and served on the /debug/create-progress endpoint of the debug server. 0 means no timeout."`
+
+		QuarantineDir            string        `long:"quarantine-dir" description:"Directory in which to keep a copy of the depot directory of containers which fail to create or to restore on startup, before they are destroyed. Quarantined containers are listed on the /debug/quarantine endpoint of the debug server. Disabled if not specified."`
+		QuarantineRetention      time.Duration `long:"quarantine-retention" default:"24h" description:"How long to keep quarantined containers. 0 means until there are too many."`
+		MaxQuarantinedContainers int           `long:"max-quarantined-containers" default:"20" description:"Most quarantined containers to keep, removing the oldest first. 0 means no limit."`
+
+		Hooks       []FileFlag    `long:"container-hook" description:"Path to a binary to run with --action pre-create before each container is created, and with --action post-destroy in the background after each container is destroyed. On pre-create it is given the container spec as JSON on stdin, may write a modified spec to stdout, and refuses the create by exiting non-zero. Can be specified multiple times, and the hooks run in order."`
+		HookTimeout time.Duration `long:"container-hook-timeout" default:"5s" description:"Time after which a container hook is killed and treated as failed. 0 means no timeout."`
+
+		TenantProperty      string   `long:"tenant-property" description:"Container property naming the tenant a container belongs to, which is recorded in the org.cloudfoundry.garden.tenant annotation of its OCI bundle alongside its handle and image."`
+		AnnotatedProperties []string `long:"annotate-property" description:"Container property to record in the org.cloudfoundry.garden.property.<name> annotation of the container's OCI bundle. Can be specified multiple times."`
 	} `group:"Container Lifecycle"`
 
 	Bin struct {
@@ -214,6 +281,7 @@ type ServerCommand struct {
 		Tar             FileFlag `long:"tar-bin"        description:"Path to the 'tar' binary."`
 		IPTables        FileFlag `long:"iptables-bin"  default:"/sbin/iptables" description:"path to the iptables binary"`
 		IPTablesRestore FileFlag `long:"iptables-restore-bin"  default:"/sbin/iptables-restore" description:"path to the iptables-restore binary"`
+		IPSet           FileFlag `long:"ipset-bin"      description:"Path to the 'ipset' binary. If specified, the networks and ports of NetOut rules are kept in an ipset per container, matched by a single iptables rule, so that containers with many rules are quick to set up and their traffic quick to filter."`
 		Init            FileFlag `long:"init-bin"       description:"Path execute as pid 1 inside each container."`
 	} `group:"Binary Tools"`
 
@@ -226,6 +294,9 @@ type ServerCommand struct {
 		Dir                         string   `long:"graph"                                default:"/var/gdn/graph" description:"Directory on which to store imported rootfs graph data."`
 		CleanupThresholdInMegabytes int      `long:"graph-cleanup-threshold-in-megabytes" default:"-1" description:"Disk usage of the graph dir at which cleanup should trigger, or -1 to disable graph cleanup."`
 		PersistentImages            []string `long:"persistent-image" description:"Image that should never be garbage collected. Can be specified multiple times."`
+
+		Snapshots         bool          `long:"graph-snapshots"          description:"Provision containers without a disk quota from a local rootfs or digest-pinned image as overlay snapshots of a shared, cached copy."`
+		SnapshotRetention time.Duration `long:"graph-snapshot-retention" default:"1h" description:"How long to keep a cached rootfs which no container is using."`
 	} `group:"Image Graph"`
 
 	Image struct {
@@ -234,11 +305,14 @@ type ServerCommand struct {
 
 		PrivilegedPlugin          FileFlag `long:"privileged-image-plugin"           description:"Path to privileged image plugin binary."`
 		PrivilegedPluginExtraArgs []string `long:"privileged-image-plugin-extra-arg" description:"Extra argument to pass to the image plugin to create privileged images. Can be specified multiple times."`
+
+		CommitDir string `long:"image-commit-dir" default:"/var/gdn/committed-images" description:"Directory in which to store the rootfs directories committed from stopped containers, which can be used as the rootfs of later containers."`
 	} `group:"Image"`
 
 	Docker struct {
 		Registry           string   `long:"docker-registry" default:"registry-1.docker.io" description:"Docker registry API endpoint."`
 		InsecureRegistries []string `long:"insecure-docker-registry" description:"Docker registry to allow connecting to even if not secure. Can be specified multiple times."`
+		LayerParallelism   int      `long:"docker-layer-parallelism" default:"4" description:"Maximum number of image layers to download concurrently while earlier layers are extracted. Set to 0 to download layers one at a time."`
 	} `group:"Docker Image Fetching"`
 
 	Network struct {
@@ -252,22 +326,41 @@ type ServerCommand struct {
 
 		AdditionalHostEntries []string `long:"additional-host-entry" description:"Per line hosts entries. Can be specified multiple times and will be appended verbatim in order to /etc/hosts"`
 
-		ExternalIP             IPFlag `long:"external-ip"                     description:"IP address to use to reach container's mapped ports. Autodetected if not specified."`
+		ExternalIP             IPFlag `long:"external-ip"                     description:"IP address to use to reach container's mapped ports, reported as the external IP in container info. Autodetected if not specified."`
 		PortPoolStart          uint32 `long:"port-pool-start" default:"61001" description:"Start of the ephemeral port range used for mapped container ports."`
 		PortPoolSize           uint32 `long:"port-pool-size"  default:"4534"  description:"Size of the port pool used for mapped container ports."`
 		PortPoolPropertiesPath string `long:"port-pool-properties-path" description:"Path in which to store port pool properties."`
 
+		InstanceIndexPath string `long:"iptables-instance-index-path" default:"/var/run/gdn/iptables-instances.json" description:"Path in which to record the instance ID naming each container's iptables chains, so that chains left behind by failed destroys can be removed."`
+
+		PortMappingsPath string `long:"port-mappings-path" default:"/var/run/gdn/port-mappings.json" description:"Path in which to record each container's mapped ports as they are made, so that they are reported correctly after a restart."`
+
+		ReservationsPath string `long:"network-reservations-path" default:"/var/gdn/network-reservations.json" description:"Path in which to record the networks reserved with the garden.network.reservation property, so that recreated containers keep their IP."`
+
 		Mtu int `long:"mtu" description:"MTU size for container network interfaces. Defaults to the MTU of the interface used for outbound access by the host. Max allowed value is 1500."`
 
+		QoSClasses map[string]string `long:"network-qos-class" value-name:"NAME:DSCP_CLASS" description:"QoS class containers can request with the garden.network.qos-class property, and the DSCP class (e.g. EF, AF41, CS1) their egress traffic is marked with. Can be specified multiple times."`
+
+		NetInProxy         bool `long:"netin-proxy" description:"Forward NetIn ports by proxying connections to containers, rather than with DNAT, so that traffic to containers comes from the host."`
+		NetInProxyProtocol bool `long:"netin-proxy-protocol" description:"Start each connection proxied by --netin-proxy with a PROXY protocol (v1) header, so that containers can learn the address of the client."`
+
 		Plugin          FileFlag `long:"network-plugin"           description:"Path to network plugin binary."`
 		PluginExtraArgs []string `long:"network-plugin-extra-arg" description:"Extra argument to pass to the network plugin. Can be specified multiple times."`
+		PluginEnv       []string `long:"network-plugin-env" value-name:"NAME=VALUE" description:"Environment variable to run the network plugin with. The value may reference container properties, e.g. TENANT=$(property:network.tenant-id). Can be specified multiple times."`
 	} `group:"Container Networking"`
 
 	Limits struct {
-		CPUQuotaPerShare     uint64 `long:"cpu-quota-per-share" default:"0" description:"Maximum number of microseconds each cpu share assigned to a container allows per quota period"`
-		TCPMemoryLimit       uint64 `long:"tcp-memory-limit" default:"0" description:"Set hard limit for the tcp buf memory, value in bytes"`
-		DefaultBlockIOWeight uint16 `long:"default-container-blockio-weight" default:"0" description:"Default block IO weight assigned to a container"`
-		MaxContainers        uint64 `long:"max-containers" default:"0" description:"Maximum number of containers that can be created."`
+		CPUQuotaPerShare         uint64 `long:"cpu-quota-per-share" default:"0" description:"Maximum number of microseconds each cpu share assigned to a container allows per quota period"`
+		TCPMemoryLimit           uint64 `long:"tcp-memory-limit" default:"0" description:"Set hard limit for the tcp buf memory, value in bytes"`
+		DefaultBlockIOWeight     uint16 `long:"default-container-blockio-weight" default:"0" description:"Default block IO weight assigned to a container"`
+		MaxContainers            uint64 `long:"max-containers" default:"0" description:"Maximum number of containers that can be created."`
+		BulkCreateParallelism    int    `long:"bulk-create-parallelism" default:"4" description:"Number of containers to create at once when creating containers in bulk."`
+		MaxDiskQuotaPercent      uint64 `long:"max-disk-quota-percent" default:"0" description:"Maximum percentage of the depot filesystem that the disk quotas of all containers may add up to. Creates which would exceed it are rejected. 0 means no maximum."`
+		MaxProcessesPerContainer int    `long:"max-processes-per-container" default:"0" description:"Maximum number of live processes a container may have. Further runs are rejected until a process exits. 0 means no maximum."`
+		MaxOpenFilesPerProcess   uint64 `long:"max-open-files-per-process" default:"0" description:"The nofile rlimit of processes run in containers. Processes which ask for a higher limit, or for none, get this one. The file descriptors open in a container are served on the /debug/fd-usage endpoint of the debug server. 0 means no limit is enforced."`
+		ReservedMemoryPercent    uint64 `long:"reserved-memory-percent" default:"0" description:"Percentage of host memory reserved for the system. It is left out of the reported capacity, and creates whose memory limits would commit it are rejected."`
+		ReservedDiskPercent      uint64 `long:"reserved-disk-percent" default:"0" description:"Percentage of the depot filesystem reserved for the system. It is left out of the reported capacity, and creates whose disk quotas would commit it are rejected."`
+		ReservedCPUPercent       uint64 `long:"reserved-cpu-percent" default:"0" description:"Percentage of host cpu reserved for the system. Creates whose cpu shares would commit it, at 1024 shares per cpu, are rejected."`
 	} `group:"Limits"`
 
 	Metrics struct {
@@ -275,6 +368,8 @@ type ServerCommand struct {
 
 		DropsondeOrigin      string `long:"dropsonde-origin"      default:"garden-linux"   description:"Origin identifier for Dropsonde-emitted metrics."`
 		DropsondeDestination string `long:"dropsonde-destination" default:"127.0.0.1:3457" description:"Destination for Dropsonde-emitted metrics."`
+
+		DiskUsageWarningThreshold int `long:"disk-usage-warning-threshold" default:"90" description:"Percentage of the depot or graph filesystem in use above which warnings are logged, or 0 to disable."`
 	} `group:"Metrics"`
 }
 
@@ -416,10 +511,26 @@ func (cmd *ServerCommand) idMappings() (idmapper.MappingList, idmapper.MappingLi
 
 func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	logger, reconfigurableSink := cmd.Logger.Logger("guardian")
+	cmd.clock = cmd.wireClock()
+
+	if err := cmd.validateReservedPercents(); err != nil {
+		logger.Error("invalid-reserved-percent", err)
+		return err
+	}
+
+	if err := cmd.validateOOMScoreAdj(); err != nil {
+		logger.Error("invalid-oom-score-adj", err)
+		return err
+	}
 
 	factory := cmd.NewGardenFactory()
 
-	propManager, err := cmd.loadProperties(logger, cmd.Containers.PropertiesPath)
+	if err := cmd.validateMaxProcesses(factory.WireExecRunner("exec")); err != nil {
+		logger.Error("invalid-max-processes", err)
+		return err
+	}
+
+	propManager, err := cmd.wirePropertyManager(logger)
 	if err != nil {
 		return err
 	}
@@ -429,7 +540,7 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 		return err
 	}
 
-	networker, iptablesStarter, err := cmd.wireNetworker(logger, factory, propManager, portPool)
+	networker, networkStarters, err := cmd.wireNetworker(logger, factory, propManager, portPool)
 	if err != nil {
 		logger.Error("failed-to-wire-networker", err)
 		return err
@@ -442,16 +553,25 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 
 	volumizer := factory.WireVolumizer(logger)
 
-	starters := []gardener.Starter{}
+	starters := []gardener.Starter{cmd.wireBundleMigrator(logger)}
 	if !cmd.Server.SkipSetup {
 		starters = append(starters, factory.WireCgroupsStarter(logger))
 	}
 	if cmd.Network.Plugin.Path() == "" {
-		starters = append(starters, iptablesStarter)
+		starters = append(starters, networkStarters...)
+	}
+
+	var quarantiner gardener.Quarantiner
+	quarantineStore := cmd.wireQuarantineStore(logger)
+	if quarantineStore != nil {
+		quarantiner = quarantineStore
+		starters = append(starters, quarantineStore)
 	}
 
 	var bulkStarter gardener.BulkStarter = gardener.NewBulkStarter(starters)
 	peaCleaner := cmd.wirePeaCleaner(factory, volumizer)
+	lifecycleNotifier := cmd.wireLifecycleNotifier(propManager)
+	containerizer := factory.WireContainerizer(logger, propManager, volumizer, peaCleaner, lifecycleNotifier)
 
 	backend := &gardener.Gardener{
 		UidGenerator:    wireUIDGenerator(),
@@ -459,11 +579,39 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 		SysInfoProvider: sysinfo.NewResourcesProvider(cmd.Containers.Dir),
 		Networker:       networker,
 		Volumizer:       volumizer,
-		Containerizer:   cmd.wireContainerizer(logger, factory, propManager, volumizer, peaCleaner),
+		Containerizer:   containerizer,
 		PropertyManager: propManager,
 		MaxContainers:   cmd.Limits.MaxContainers,
 		Restorer:        restorer,
 		PeaCleaner:      peaCleaner,
+		ResourceStore:   resources.NewFileStore(cmd.Containers.ResourceStoreDir),
+
+		DefaultGraceTime:         cmd.Containers.DefaultGraceTime,
+		MaxDiskQuotaPercent:      cmd.Limits.MaxDiskQuotaPercent,
+		ReservedMemoryPercent:    cmd.Limits.ReservedMemoryPercent,
+		ReservedDiskPercent:      cmd.Limits.ReservedDiskPercent,
+		ReservedCPUPercent:       cmd.Limits.ReservedCPUPercent,
+		BulkCreateParallelism:    cmd.Limits.BulkCreateParallelism,
+		MaxProcessesPerContainer: cmd.Limits.MaxProcessesPerContainer,
+		MaxSecurityPreset:        cmd.Containers.MaxSecurityPreset,
+		AllowHardeningRelaxation: cmd.Containers.AllowProcSysRelaxation,
+		DefaultProcessUser:       cmd.Containers.DefaultProcessUser,
+		LifecycleNotifier:        lifecycleNotifier,
+		RootfsCommitter:          commit.NewDirCommitter(cmd.Bin.Tar.Path(), cmd.Image.CommitDir, factory.CommandRunner()),
+		DeferFailedDestroys:      cmd.Containers.DeferredCleanupInterval > 0,
+		CreateFailureDiagnoser:   wireCreateFailureDiagnoser(cmd.Server.Tag, cmd.Image.Plugin.Path() == ""),
+		FileInspector:            wireFileInspector(),
+		MaxOpenFilesPerProcess:   cmd.Limits.MaxOpenFilesPerProcess,
+		FDCounter:                wireFDCounter(),
+		CgroupRemover:            wireCgroupRemover(cmd.Server.Tag),
+		CoreDumpCollector:        cmd.wireCoreDumpCollector(),
+		CoreDumpPath:             cmd.Containers.CoreDumpPath,
+		MaxCoreDumpSize:          cmd.Containers.MaxCoreDumpSize,
+		MaxCoreDumpDirSize:       cmd.Containers.MaxCoreDumpDirSize,
+		CreateTimeout:            cmd.Containers.CreateTimeout,
+		Clock:                    cmd.clock,
+		Quarantiner:              quarantiner,
+		ContainerHooks:           cmd.wireContainerHooks(factory.CommandRunner()),
 
 		// We want to be able to disable privileged containers independently of
 		// whether or not gdn is running as root.
@@ -482,6 +630,9 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 	}
 
 	gardenServer := server.New(listenNetwork, listenAddr, cmd.Containers.DefaultGraceTime, backend, logger.Session("api"))
+	serverClient := client.New(connection.New(listenNetwork, listenAddr))
+	backend.BulkCreateServer = serverClient
+	backend.DestroyMatchingServer = serverClient
 
 	cmd.initializeDropsonde(logger)
 
@@ -493,29 +644,81 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 		"loopDevices":   metricsProvider.LoopDevices,
 		"backingStores": metricsProvider.BackingStores,
 		"depotDirs":     metricsProvider.DepotDirs,
+		"depotDisk":     metricsProvider.DepotDiskUsage,
+		"graphDisk":     metricsProvider.GraphDiskUsage,
 	}
 
 	periodicMetronMetrics := map[string]func() int{
-		"DepotDirs": metricsProvider.DepotDirs,
+		"DepotDirs":      metricsProvider.DepotDirs,
+		"DepotDiskUsage": metricsProvider.DepotDiskUsage,
 	}
 
 	if cmd.Image.Plugin == "" && cmd.Image.PrivilegedPlugin == "" {
 		periodicMetronMetrics["LoopDevices"] = metricsProvider.LoopDevices
 		periodicMetronMetrics["BackingStores"] = metricsProvider.BackingStores
+		periodicMetronMetrics["GraphDiskUsage"] = metricsProvider.GraphDiskUsage
 	}
 
 	metronNotifier := cmd.wireMetronNotifier(logger, periodicMetronMetrics)
 	metronNotifier.Start()
 
+	var healthProber *gardener.HealthProber
+	if cmd.Containers.HealthProbeCheckInterval > 0 {
+		healthProber = gardener.NewHealthProber(logger, backend, lifecycleNotifier, cmd.Containers.HealthProbeCheckInterval, cmd.clock)
+	}
+
+	capabilities := cmd.capabilities(logger, backend)
+
 	if cmd.Server.DebugBindIP != nil {
 		addr := fmt.Sprintf("%s:%d", cmd.Server.DebugBindIP.IP(), cmd.Server.DebugBindPort)
-		metrics.StartDebugServer(addr, reconfigurableSink, debugServerMetrics)
+		debugServerHandlers := map[string]http.Handler{
+			"/debug/preview":              gardener.LoopbackOnly(gardener.PreviewHandler(backend)),
+			"/debug/processes":            gardener.ProcessesHandler(backend),
+			"/debug/prefetch":             gardener.LoopbackOnly(gardener.PrefetchHandler(backend)),
+			"/debug/bulk-create":          gardener.LoopbackOnly(gardener.BulkCreateHandler(backend)),
+			"/debug/commit":               gardener.LoopbackOnly(gardener.CommitHandler(backend)),
+			"/debug/network-stats":        gardener.NetworkStatsHandler(backend),
+			"/debug/metrics":              gardener.LabelledMetricsHandler(backend),
+			"/debug/network-reservations": gardener.LoopbackOnly(gardener.NetworkReservationsHandler(backend)),
+			"/debug/limits":               gardener.LoopbackOnly(gardener.LimitsHandler(backend)),
+			"/debug/copy":                 gardener.LoopbackOnly(gardener.CopyHandler(backend)),
+			"/debug/process-io":           gardener.LoopbackOnly(gardener.ProcessIOHandler(backend)),
+			"/debug/destroy-matching":     gardener.LoopbackOnly(gardener.DestroyMatchingHandler(backend)),
+			"/debug/files":                gardener.LoopbackOnly(gardener.FilesHandler(backend)),
+			"/debug/create-progress":      gardener.CreateProgressHandler(backend),
+			"/debug/fd-usage":             gardener.FDUsageHandler(backend),
+		}
+		if fakeClock, ok := cmd.clock.(*fakeclock.FakeClock); ok {
+			debugServerHandlers["/debug/clock"] = gardener.LoopbackOnly(gardener.ClockHandler(fakeClock))
+		}
+		if quarantineStore != nil {
+			debugServerHandlers["/debug/quarantine"] = gardener.QuarantineHandler(quarantineStore)
+		}
+		if healthProber != nil {
+			debugServerHandlers["/debug/health"] = gardener.HealthHandler(healthProber)
+		}
+		debugServerHandlers["/debug/capabilities"] = gardener.CapabilitiesHandler(capabilities)
+		metrics.StartDebugServer(addr, reconfigurableSink, debugServerMetrics, debugServerHandlers)
 	}
 
 	if err := backend.Start(); err != nil {
 		logger.Error("starting-guardian-backend", err)
 		return err
 	}
+
+	if cmd.Containers.OrphanGCInterval > 0 {
+		orphanCollector := cmd.wireOrphanCollector(logger, containerizer, backend, propManager)
+		orphanCollector.Start()
+	}
+	if healthProber != nil {
+		healthProber.Start()
+	}
+	if cmd.Containers.DeferredCleanupInterval > 0 {
+		deferredCleaner := gardener.NewDeferredCleaner(
+			logger, backend.ResourceStore, backend, cmd.Containers.DeferredCleanupInterval, cmd.Containers.DeferredCleanupMaxBackoff, cmd.clock,
+		)
+		deferredCleaner.Start()
+	}
 	if err := gardenServer.SetupBomberman(); err != nil {
 		logger.Error("setting-up-bomberman", err)
 		return err
@@ -527,15 +730,18 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 	close(ready)
 
 	logger.Info("started", lager.Data{
-		"network": listenNetwork,
-		"addr":    listenAddr,
+		"network":      listenNetwork,
+		"addr":         listenAddr,
+		"capabilities": capabilities,
 	})
 
 	<-signals
 
 	gardenServer.Stop()
 
-	cmd.saveProperties(logger, cmd.Containers.PropertiesPath, propManager)
+	if cmd.Containers.PropertiesBackend == "memory" {
+		cmd.saveProperties(logger, cmd.Containers.PropertiesPath, propManager)
+	}
 
 	portPoolState := portPool.RefreshState()
 	ports.SaveState(cmd.Network.PortPoolPropertiesPath, portPoolState)
@@ -543,6 +749,45 @@ func (cmd *ServerCommand) Run(signals <-chan os.Signal, ready chan<- struct{}) e
 	return nil
 }
 
+func (cmd *ServerCommand) validateReservedPercents() error {
+	for flag, percent := range map[string]uint64{
+		"--reserved-memory-percent": cmd.Limits.ReservedMemoryPercent,
+		"--reserved-disk-percent":   cmd.Limits.ReservedDiskPercent,
+		"--reserved-cpu-percent":    cmd.Limits.ReservedCPUPercent,
+	} {
+		if percent > 100 {
+			return fmt.Errorf("%s must be between 0 and 100, got %d", flag, percent)
+		}
+	}
+
+	return nil
+}
+
+// validateOOMScoreAdj rejects a container oom_score_adj which the kernel would
+// not accept, so that it fails the server start rather than every create
+func (cmd *ServerCommand) validateOOMScoreAdj() error {
+	score := cmd.Containers.OOMScoreAdj
+	if score != nil && (*score < -1000 || *score > 1000) {
+		return fmt.Errorf("--container-oom-score-adj must be between -1000 and 1000, got %d", *score)
+	}
+
+	return nil
+}
+
+// validateMaxProcesses rejects a process limit when the exec runner cannot
+// report when processes exit, as their live processes could not be counted
+func (cmd *ServerCommand) validateMaxProcesses(execRunner runrunc.ExecRunner) error {
+	if cmd.Limits.MaxProcessesPerContainer <= 0 {
+		return nil
+	}
+
+	if runner, ok := execRunner.(runrunc.ExitReportingExecRunner); !ok || !runner.ReportsExits() {
+		return errors.New("--max-processes-per-container is not supported by this platform's exec runner")
+	}
+
+	return nil
+}
+
 func (cmd *ServerCommand) wirePeaCleaner(factory GardenFactory, volumizer gardener.Volumizer) gardener.PeaCleaner {
 	cmdRunner := factory.CommandRunner()
 	runcLogRunner := runrunc.NewLogRunner(cmdRunner, runrunc.LogDir(os.TempDir()).GenerateLogFile)
@@ -602,6 +847,43 @@ func startServer(gardenServer *server.GardenServer, logger lager.Logger) error {
 	return nil
 }
 
+func (cmd *ServerCommand) wirePropertyManager(logger lager.Logger) (*properties.Manager, error) {
+	var backend properties.Backend
+	switch cmd.Containers.PropertiesBackend {
+	case "file":
+		backend = properties.FileBackend{Dir: cmd.Containers.PropertiesDir}
+	case "consul":
+		if cmd.Containers.PropertiesConsulURL == "" {
+			return nil, errors.New("--properties-consul-url must be given to use the consul properties backend")
+		}
+
+		prefix := cmd.Containers.PropertiesConsulPrefix
+		if prefix == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return nil, err
+			}
+			prefix = "garden/properties/" + hostname
+		}
+
+		backend = properties.ConsulBackend{
+			URL:    cmd.Containers.PropertiesConsulURL,
+			Prefix: prefix,
+			Client: &http.Client{Timeout: cmd.Containers.PropertiesConsulTimeout},
+		}
+	default:
+		return cmd.loadProperties(logger, cmd.Containers.PropertiesPath)
+	}
+
+	propManager, err := properties.NewBackedManager(logger, backend)
+	if err != nil {
+		logger.Error("failed-to-load-properties", err, lager.Data{"backend": cmd.Containers.PropertiesBackend})
+		return nil, err
+	}
+
+	return propManager, nil
+}
+
 func (cmd *ServerCommand) loadProperties(logger lager.Logger, propertiesPath string) (*properties.Manager, error) {
 	propManager, err := properties.Load(propertiesPath)
 	if err != nil {
@@ -642,6 +924,15 @@ func (cmd *ServerCommand) wirePortPool(logger lager.Logger) (*ports.PortPool, er
 	return portPool, nil
 }
 
+func (cmd *ServerCommand) wireBundleMigrator(logger lager.Logger) *depot.BundleMigrator {
+	return &depot.BundleMigrator{
+		Logger: logger,
+		Dir:    cmd.Containers.Dir,
+		Loader: &goci.BndlLoader{},
+		Saver:  &goci.BundleSaver{},
+	}
+}
+
 func (cmd *ServerCommand) wireDepot(bundleGenerator depot.BundleGenerator, bundleSaver depot.BundleSaver, bindMountSourceCreator depot.BindMountSourceCreator) *depot.DirectoryDepot {
 	return depot.New(cmd.Containers.Dir, bundleGenerator, bundleSaver, bindMountSourceCreator)
 }
@@ -654,7 +945,7 @@ func extractIPs(ipflags []IPFlag) []net.IP {
 	return ips
 }
 
-func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory, propManager kawasaki.ConfigStore, portPool *ports.PortPool) (gardener.Networker, gardener.Starter, error) {
+func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory, propManager kawasaki.ConfigStore, portPool *ports.PortPool) (gardener.Networker, []gardener.Starter, error) {
 	externalIP, err := defaultExternalIP(cmd.Network.ExternalIP)
 	if err != nil {
 		return nil, nil, err
@@ -674,8 +965,9 @@ func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory,
 			resolvConfigurer,
 			cmd.Network.Plugin.Path(),
 			cmd.Network.PluginExtraArgs,
+			cmd.Network.PluginEnv,
 		)
-		return externalNetworker, externalNetworker, nil
+		return externalNetworker, []gardener.Starter{externalNetworker}, nil
 	}
 
 	var denyNetworksList []string
@@ -685,7 +977,7 @@ func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory,
 
 	interfacePrefix := fmt.Sprintf("w%s", cmd.Server.Tag)
 	chainPrefix := fmt.Sprintf("w-%s-", cmd.Server.Tag)
-	idGenerator := kawasaki.NewSequentialIDGenerator(time.Now().UnixNano())
+	instanceIndex := kawasaki.NewFileInstanceIndex(cmd.Network.InstanceIndexPath)
 	locksmith := &locksmithpkg.FileSystem{}
 
 	iptRunner := &logging.Runner{CommandRunner: factory.CommandRunner(), Logger: log.Session("iptables-runner")}
@@ -694,6 +986,18 @@ func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory,
 	ipTablesStarter := iptables.NewStarter(nonLoggingIPTables, cmd.Network.AllowHostAccess, interfacePrefix, denyNetworksList, cmd.Containers.DestroyContainersOnStartup, log)
 	ruleTranslator := iptables.NewRuleTranslator()
 
+	var portForwarder kawasaki.PortForwarder = iptables.NewPortForwarder(ipTables)
+	if cmd.Network.NetInProxy {
+		portForwarder = proxy.NewPortForwarder(log.Session("netin-proxy"), cmd.Network.NetInProxyProtocol)
+	}
+
+	var ipSets iptables.IPSets
+	firewallOpener := iptables.NewFirewallOpener(ruleTranslator, ipTables)
+	if cmd.Bin.IPSet.Path() != "" {
+		ipSets = iptables.NewIPSetController(cmd.Bin.IPSet.Path(), factory.CommandRunner())
+		firewallOpener = iptables.NewIPSetFirewallOpener(ruleTranslator, ipTables, ipSets)
+	}
+
 	containerMtu := cmd.Network.Mtu
 	if containerMtu == 0 {
 		containerMtu, err = mtu.MTU(externalIP.String())
@@ -702,18 +1006,28 @@ func (cmd *ServerCommand) wireNetworker(log lager.Logger, factory GardenFactory,
 		}
 	}
 
+	subnetPool := subnets.NewPool(cmd.Network.Pool.CIDR())
+	reservations := kawasaki.NewFileReservationStore(cmd.Network.ReservationsPath)
+	reservedIPStarter := &kawasaki.ReservedIPStarter{Logger: log, SubnetPool: subnetPool, Reservations: reservations}
+
 	networker := kawasaki.New(
 		kawasaki.SpecParserFunc(kawasaki.ParseSpec),
-		subnets.NewPool(cmd.Network.Pool.CIDR()),
-		kawasaki.NewConfigCreator(idGenerator, interfacePrefix, chainPrefix, externalIP, dnsServers, additionalDNSServers, cmd.Network.AdditionalHostEntries, containerMtu),
+		subnetPool,
+		kawasaki.NewConfigCreator(instanceIndex, interfacePrefix, chainPrefix, externalIP, dnsServers, additionalDNSServers, cmd.Network.AdditionalHostEntries, containerMtu),
 		propManager,
-		kawasakifactory.NewDefaultConfigurer(ipTables, cmd.Containers.Dir),
+		kawasakifactory.NewDefaultConfigurer(ipTables, ipSets, cmd.Containers.Dir),
 		portPool,
-		iptables.NewPortForwarder(ipTables),
-		iptables.NewFirewallOpener(ruleTranslator, ipTables),
+		portForwarder,
+		firewallOpener,
+		iptables.NewQoSMarker(ipTables),
+		cmd.Network.QoSClasses,
+		instanceIndex,
+		kawasaki.SysfsInterfaceStatser{Root: "/sys/class/net"},
+		reservations,
+		kawasaki.NewFilePortMappingStore(cmd.Network.PortMappingsPath),
 	)
 
-	return networker, ipTablesStarter, nil
+	return networker, []gardener.Starter{ipTablesStarter, reservedIPStarter}, nil
 }
 
 func (cmd *ServerCommand) wireImagePlugin(commandRunner commandrunner.CommandRunner, uid, gid int) gardener.Volumizer {
@@ -750,63 +1064,62 @@ func (cmd *ServerCommand) wireImagePlugin(commandRunner commandrunner.CommandRun
 	return gardener.NewVolumeProvider(imagePlugin, imagePlugin, gardener.CommandFactory(preparerootfs.Command), commandRunner, uid, gid)
 }
 
-func (cmd *ServerCommand) wireContainerizer(log lager.Logger, factory GardenFactory,
-	properties gardener.PropertyManager, volumizer peas.Volumizer, peaCleaner gardener.PeaCleaner) *rundmc.Containerizer {
+func (cmd *ServerCommand) wireLifecycleNotifier(properties gardener.PropertyManager) gardener.LifecycleNotifier {
+	if cmd.Containers.LifecycleWebhookURL == "" {
+		return gardener.NoopLifecycleNotifier{}
+	}
 
-	initMount, initPath := initBindMountAndPath(cmd.Bin.Init.Path())
+	return webhook.NewNotifier(cmd.Containers.LifecycleWebhookURL, properties, cmd.Containers.LifecycleWebhookTimeout)
+}
 
-	defaultMounts := append(defaultBindMounts(), initMount)
-	privilegedMounts := append(defaultMounts, privilegedMounts()...)
-	unprivilegedMounts := append(defaultMounts, unprivilegedMounts()...)
-
-	// TODO centralize knowledge of garden -> runc capability schema translation
-	baseProcess := specs.Process{
-		Capabilities: &specs.LinuxCapabilities{
-			Effective:   unprivilegedMaxCaps,
-			Bounding:    unprivilegedMaxCaps,
-			Inheritable: unprivilegedMaxCaps,
-			Permitted:   unprivilegedMaxCaps,
-		},
-		Args:        []string{initPath},
-		Cwd:         "/",
-		ConsoleSize: &specs.Box{},
+func (cmd *ServerCommand) wireCoreDumpCollector() gardener.CoreDumpCollector {
+	if cmd.Containers.CoreDumpDir == "" {
+		return nil
+	}
+
+	return &coredumps.Collector{
+		Dir:     cmd.Containers.CoreDumpDir,
+		MaxSize: cmd.Containers.MaxCoreDumpDirSize,
+		Mounter: wireCoreDumpMounter(),
 	}
+}
 
-	baseBundle := goci.Bundle().
-		WithNamespaces(PrivilegedContainerNamespaces...).
-		WithRootFS(cmd.Containers.DefaultRootFS).
-		WithProcess(baseProcess).
-		WithRootFSPropagation("private")
+func (cmd *ServerCommand) wireContainerHooks(commandRunner commandrunner.CommandRunner) []gardener.ContainerHook {
+	containerHooks := []gardener.ContainerHook{}
+	for _, hook := range cmd.Containers.Hooks {
+		containerHooks = append(containerHooks, &hooks.ExecHook{
+			Path:          hook.Path(),
+			CommandRunner: commandRunner,
+			Timeout:       cmd.Containers.HookTimeout,
+		})
+	}
 
-	uidMappings, gidMappings := cmd.idMappings()
-	unprivilegedBundle := baseBundle.
-		WithNamespace(goci.UserNamespace).
-		WithUIDMappings(uidMappings...).
-		WithGIDMappings(gidMappings...).
-		WithMounts(unprivilegedMounts...).
-		WithMaskedPaths(defaultMaskedPaths())
-
-	unprivilegedBundle.Spec.Linux.Seccomp = seccomp
-	if cmd.Containers.ApparmorProfile != "" {
-		unprivilegedBundle = unprivilegedBundle.WithApparmorProfile(cmd.Containers.ApparmorProfile)
-	}
-	privilegedBundle := baseBundle.
-		WithMounts(privilegedMounts...).
-		WithDevices(getPrivilegedDevices()...).
-		WithCapabilities(privilegedMaxCaps...).
-		WithDeviceRestrictions(append(
-			[]specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
-			allowedDevices...,
-		))
+	return containerHooks
+}
+
+func (cmd *ServerCommand) wireQuarantineStore(logger lager.Logger) *quarantine.Store {
+	if cmd.Containers.QuarantineDir == "" {
+		return nil
+	}
+
+	return quarantine.NewStore(logger, cmd.Containers.Dir, cmd.Containers.QuarantineDir, cmd.Containers.QuarantineRetention, cmd.Containers.MaxQuarantinedContainers, cmd.clock)
+}
+
+func (cmd *ServerCommand) wireContainerizer(log lager.Logger, factory GardenFactory,
+	properties gardener.PropertyManager, volumizer peas.Volumizer, peaCleaner gardener.PeaCleaner, lifecycle gardener.LifecycleNotifier) *rundmc.Containerizer {
+
+	initMount, initPath := initBindMountAndPath(cmd.Bin.Init.Path())
+	privilegedBundle, unprivilegedBundle := factory.WireBaseBundles(initMount, initPath)
 
 	log.Debug("base-bundles", lager.Data{
 		"privileged":   privilegedBundle,
 		"unprivileged": unprivilegedBundle,
 	})
 
-	cgroupRootPath := "garden"
-	if cmd.Server.Tag != "" {
-		cgroupRootPath = fmt.Sprintf("%s-%s", cgroupRootPath, cmd.Server.Tag)
+	limitsRule := bundlerules.Limits{
+		CpuQuotaPerShare: cmd.Limits.CPUQuotaPerShare,
+		TCPMemoryLimit:   int64(cmd.Limits.TCPMemoryLimit),
+		BlockIOWeight:    cmd.Limits.DefaultBlockIOWeight,
 	}
 
 	bundleRules := []rundmc.BundlerRule{
@@ -814,29 +1127,34 @@ func (cmd *ServerCommand) wireContainerizer(log lager.Logger, factory GardenFact
 			PrivilegedBase:   privilegedBundle,
 			UnprivilegedBase: unprivilegedBundle,
 		},
-		bundlerules.Namespaces{},
-		bundlerules.CGroupPath{
-			Path: cgroupRootPath,
-		},
 		wireMounts(),
 		bundlerules.Env{},
 		bundlerules.Hostname{},
 		bundlerules.Windows{},
 		bundlerules.RootFS{},
-		bundlerules.Limits{
-			CpuQuotaPerShare: cmd.Limits.CPUQuotaPerShare,
-			TCPMemoryLimit:   int64(cmd.Limits.TCPMemoryLimit),
-			BlockIOWeight:    cmd.Limits.DefaultBlockIOWeight,
+		limitsRule,
+		bundlerules.Annotations{
+			TenantProperty: cmd.Containers.TenantProperty,
+			Properties:     cmd.Containers.AnnotatedProperties,
 		},
 	}
+	// the isolation rules come last, as hardening rewrites the mounts
+	bundleRules = append(bundleRules, factory.WireIsolationRules()...)
 	template := &rundmc.BundleTemplate{Rules: bundleRules}
 
 	bundleSaver := &goci.BundleSaver{}
+	uidMappings, gidMappings := cmd.idMappings()
 	bindMountSourceCreator := wireBindMountSourceCreator(uidMappings, gidMappings)
 	depot := cmd.wireDepot(template, bundleSaver, bindMountSourceCreator)
+	if cmd.Containers.NosuidRootfs {
+		depot.RootfsRemounter = wireRootfsRemounter()
+	}
+	if cmd.Containers.ShareBindMounts {
+		depot.BindMountSharer = wireBindMountSharer(cmd.Containers.SharedBindMountsDir)
+	}
 
 	bndlLoader := &goci.BndlLoader{}
-	processBuilder := runrunc.NewProcessBuilder(wireEnvFunc(), nonRootMaxCaps)
+	processBuilder := runrunc.NewProcessBuilder(wireEnvFunc(cmd.Containers.DefaultProcessPath), nonRootMaxCaps)
 
 	cmdRunner := factory.CommandRunner()
 	runcLogRunner := runrunc.NewLogRunner(cmdRunner, runrunc.LogDir(os.TempDir()).GenerateLogFile)
@@ -853,8 +1171,10 @@ func (cmd *ServerCommand) wireContainerizer(log lager.Logger, factory GardenFact
 		processBuilder,
 		factory.WireMkdirer(),
 		runrunc.LookupFunc(runrunc.LookupUser),
+		runrunc.GroupLookupFunc(runrunc.LookupGroups),
 		factory.WireExecRunner("exec"),
 		wireUIDGenerator(),
+		wireProcessOverrider(cmd.Server.Tag, initPath),
 	)
 
 	eventStore := rundmc.NewEventStore(properties)
@@ -894,10 +1214,20 @@ func (cmd *ServerCommand) wireContainerizer(log lager.Logger, factory GardenFact
 	}
 
 	nstar := rundmc.NewNstarRunner(cmd.Bin.NSTar.Path(), cmd.Bin.Tar.Path(), cmdRunner)
-	stopper := stopper.New(stopper.NewRuncStateCgroupPathResolver(runcRoot), nil, retrier.New(retrier.ConstantBackoff(10, 1*time.Second), nil))
-	return rundmc.New(depot, runcrunner, bndlLoader, nstar, stopper, eventStore, stateStore, factory.WireRootfsFileCreator(), peaCreator, peaUsernameResolver)
+	stopper := stopper.New(stopper.NewRuncStateCgroupPathResolver(runcRoot), nil, retrier.New(retrier.ConstantBackoff(10, 1*time.Second), nil), cmd.clock, cmd.Containers.StopKillTimeout)
+	return rundmc.New(depot, runcrunner, bndlLoader, bundleSaver, limitsRule, nstar, stopper, eventStore, stateStore, factory.WireRootfsFileCreator(), peaCreator, peaUsernameResolver, lifecycle)
+}
+
+func (cmd *ServerCommand) wireClock() clock.Clock {
+	if cmd.Server.DebugFakeClock {
+		return fakeclock.NewFakeClock(time.Now())
+	}
+
+	return clock.NewClock()
 }
 
+// wirePidfileReader polls on the real clock even when the server uses a fake
+// one, as polling is not behaviour tests need to control
 func wirePidfileReader() *pidreader.PidFileReader {
 	return &pidreader.PidFileReader{
 		Clock:         clock.NewClock(),
@@ -912,12 +1242,59 @@ func (cmd *ServerCommand) wireMetricsProvider(log lager.Logger) *metrics.Metrics
 		backingStoresPath = filepath.Join(cmd.Graph.Dir, "backing_stores")
 	}
 
-	return metrics.NewMetricsProvider(log, backingStoresPath, cmd.Containers.Dir)
+	return metrics.NewMetricsProvider(log, backingStoresPath, cmd.Containers.Dir, cmd.Graph.Dir, cmd.Metrics.DiskUsageWarningThreshold)
+}
+
+func (cmd *ServerCommand) wireOrphanCollector(log lager.Logger, containerizer gardener.Containerizer, backend *gardener.Gardener, propManager *properties.Manager) *gardener.OrphanCollector {
+	var cleaners []gardener.NamedOrphanCleaner
+	if runtimeCleaner, ok := containerizer.(gardener.OrphanCleaner); ok {
+		cleaners = append(cleaners, gardener.NamedOrphanCleaner{Name: "runtime", OrphanCleaner: runtimeCleaner})
+	}
+	if networkCleaner, ok := backend.Networker.(gardener.OrphanCleaner); ok {
+		cleaners = append(cleaners, gardener.NamedOrphanCleaner{Name: "network", OrphanCleaner: networkCleaner})
+	}
+	// recorded resources are released before the properties they may depend on
+	cleaners = append(cleaners, gardener.NamedOrphanCleaner{Name: "resources", OrphanCleaner: backend})
+	cleaners = append(cleaners, gardener.NamedOrphanCleaner{Name: "properties", OrphanCleaner: propManager})
+
+	return gardener.NewOrphanCollector(
+		log, containerizer, cleaners, cmd.Containers.OrphanGCInterval, cmd.clock,
+	)
+}
+
+// capabilities reports what the server supports, as configured
+func (cmd *ServerCommand) capabilities(log lager.Logger, backend *gardener.Gardener) gardener.Capabilities {
+	capabilities := platformCapabilities()
+	capabilities.Platform = runtime.GOOS
+	capabilities.Runtime = cmd.Runtime.Plugin
+	capabilities.GraphDriver = cmd.graphDriver()
+	capabilities.AppArmor = capabilities.AppArmor && cmd.Containers.ApparmorProfile != ""
+	capabilities.PrivilegedContainers = !cmd.Containers.DisablePrivilgedContainers
+	capabilities.NetworkPlugin = cmd.Network.Plugin.Path() != ""
+
+	maxSecurityPreset := cmd.Containers.MaxSecurityPreset
+	if maxSecurityPreset == "" {
+		maxSecurityPreset = gardener.BaselineSecurityPreset
+	}
+	for _, preset := range gardener.SecurityPresets {
+		capabilities.SecurityPresets = append(capabilities.SecurityPresets, preset)
+		if preset == maxSecurityPreset {
+			break
+		}
+	}
+
+	capacity, err := backend.Capacity()
+	if err != nil {
+		log.Error("getting-capacity", err)
+	}
+	capabilities.MaxContainers = capacity.MaxContainers
+
+	return capabilities
 }
 
 func (cmd *ServerCommand) wireMetronNotifier(log lager.Logger, metricsProvider metrics.Metrics) *metrics.PeriodicMetronNotifier {
 	return metrics.NewPeriodicMetronNotifier(
-		log, metricsProvider, cmd.Metrics.EmissionInterval, clock.NewClock(),
+		log, metricsProvider, cmd.Metrics.EmissionInterval, cmd.clock,
 	)
 }
 
@@ -950,6 +1327,48 @@ func defaultExternalIP(ip IPFlag) (net.IP, error) {
 	return net.ParseIP(localIP), nil
 }
 
+// securityPresets returns the presets unprivileged containers can select. The
+// baseline matches the unprivileged base bundle.
+func securityPresets() map[string]bundlerules.SecurityPreset {
+	return map[string]bundlerules.SecurityPreset{
+		gardener.RestrictedSecurityPreset: {
+			Capabilities: []string{
+				"CAP_CHOWN",
+				"CAP_DAC_OVERRIDE",
+				"CAP_FSETID",
+				"CAP_FOWNER",
+				"CAP_SETGID",
+				"CAP_SETUID",
+				"CAP_NET_BIND_SERVICE",
+				"CAP_KILL",
+			},
+			Seccomp: seccomp,
+			MaskedPaths: append(defaultMaskedPaths(),
+				"/proc/acpi",
+				"/proc/keys",
+				"/sys/firmware",
+			),
+			NoNewPrivileges: true,
+		},
+		gardener.BaselineSecurityPreset: {
+			Capabilities: unprivilegedMaxCaps,
+			Seccomp:      seccomp,
+			MaskedPaths:  defaultMaskedPaths(),
+		},
+		gardener.PrivilegedCompatSecurityPreset: {
+			Capabilities: append(append([]string{}, unprivilegedMaxCaps...),
+				"CAP_IPC_LOCK",
+				"CAP_NET_ADMIN",
+				"CAP_SYS_NICE",
+				"CAP_SYS_PTRACE",
+				"CAP_SYS_RESOURCE",
+			),
+			Seccomp:     seccomp,
+			MaskedPaths: defaultMaskedPaths(),
+		},
+	}
+}
+
 func defaultMaskedPaths() []string {
 	return []string{
 		"/proc/kcore",
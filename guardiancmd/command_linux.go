@@ -17,23 +17,32 @@ import (
 	"code.cloudfoundry.org/garden-shed/quota_manager"
 	"code.cloudfoundry.org/garden-shed/repository_fetcher"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
+	"code.cloudfoundry.org/guardian/containerfs"
+	"code.cloudfoundry.org/guardian/coredumps"
 	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/kawasaki/dns"
+	"code.cloudfoundry.org/guardian/layerfetch"
 	"code.cloudfoundry.org/guardian/logging"
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
 	"code.cloudfoundry.org/guardian/rundmc/cgroups"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
 	"code.cloudfoundry.org/guardian/rundmc/execrunner/dadoo"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/guardian/rundmc/peas"
 	"code.cloudfoundry.org/guardian/rundmc/preparerootfs"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/guardian/rundmc/signals"
+	"code.cloudfoundry.org/guardian/snapshot"
+	"code.cloudfoundry.org/guardian/sysinfo"
 	"code.cloudfoundry.org/idmapper"
 	"code.cloudfoundry.org/lager"
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/graph"
 	"github.com/eapache/go-resiliency/retrier"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock"
 )
 
 type LinuxFactory struct {
@@ -70,11 +79,81 @@ func (f *LinuxFactory) WireVolumizer(logger lager.Logger) gardener.Volumizer {
 	}
 
 	shed := f.wireShed(logger)
+	if f.config.Graph.Snapshots {
+		snapshotter := snapshot.NewSnapshotter(shed, snapshot.OverlayMounter{}, filepath.Join(f.config.Graph.Dir, "snapshots"), f.config.Graph.SnapshotRetention, f.uidMappings.Map(0), f.gidMappings.Map(0), f.config.clock)
+		return gardener.NewVolumeProvider(snapshotter, snapshotter, gardener.CommandFactory(preparerootfs.Command), f.commandRunner, f.uidMappings.Map(0), f.gidMappings.Map(0))
+	}
+
 	return gardener.NewVolumeProvider(shed, shed, gardener.CommandFactory(preparerootfs.Command), f.commandRunner, f.uidMappings.Map(0), f.gidMappings.Map(0))
 }
 
-func wireEnvFunc() runrunc.EnvFunc {
-	return runrunc.EnvFunc(runrunc.UnixEnvFor)
+// platformCapabilities reports what the platform supports. Guardian mounts
+// its own cgroup v1 hierarchies.
+func platformCapabilities() gardener.Capabilities {
+	return gardener.Capabilities{
+		CgroupVersion:  "v1",
+		Seccomp:        true,
+		AppArmor:       true,
+		UserNamespaces: true,
+	}
+}
+
+func (cmd *ServerCommand) graphDriver() string {
+	switch {
+	case cmd.Image.Plugin.Path() != "" || cmd.Image.PrivilegedPlugin.Path() != "":
+		return "image-plugin"
+	case cmd.Graph.Dir == "":
+		return "none"
+	case cmd.Graph.Snapshots:
+		return "shed-snapshots"
+	default:
+		return "shed"
+	}
+}
+
+func wireRootfsRemounter() depot.RootfsRemounter {
+	return rundmc.NosuidRemounter(rundmc.RemountNosuid)
+}
+
+func wireBindMountSharer(dir string) depot.BindMountSharer {
+	return &depot.SharedBindMounts{Dir: dir, Mounter: depot.OSBindMounter{}}
+}
+
+func wireProcessOverrider(tag, initPath string) runrunc.ProcessOverrider {
+	return runrunc.CgroupProcessOverrider{
+		CgroupRoot:  cgroupsMountpoint(tag),
+		ProcRoot:    "/proc",
+		InitPath:    initPath,
+		StopTimeout: 10 * time.Second,
+	}
+}
+
+func wireCgroupRemover(tag string) gardener.CgroupRemover {
+	return cgroups.Remover{CgroupRoot: cgroupsMountpoint(tag)}
+}
+
+func wireCreateFailureDiagnoser(tag string, requireLayeredFS bool) gardener.CreateFailureDiagnoser {
+	return sysinfo.KernelFeatureDiagnoser{
+		ProcPath:         "/proc",
+		MemoryCgroupPath: filepath.Join(cgroupsMountpoint(tag), "memory"),
+		RequireLayeredFS: requireLayeredFS,
+	}
+}
+
+func wireFileInspector() gardener.FileInspector {
+	return &containerfs.Inspector{ProcRoot: "/proc"}
+}
+
+func wireCoreDumpMounter() coredumps.Mounter {
+	return coredumps.TmpfsMounter{}
+}
+
+func wireFDCounter() gardener.FDCounter {
+	return &containerfs.FDCounter{ProcRoot: "/proc"}
+}
+
+func wireEnvFunc(defaultPath string) runrunc.EnvFunc {
+	return runrunc.UnixEnvWithDefaultPath(defaultPath)
 }
 
 func (f *LinuxFactory) WireMkdirer() runrunc.Mkdirer {
@@ -110,16 +189,22 @@ func (cmd *SetupCommand) WireCgroupsStarter(logger lager.Logger) gardener.Starte
 	return createCgroupsStarter(logger, cmd.Tag, &cgroups.OSChowner{UID: cmd.RootlessUID, GID: cmd.RootlessGID}, rundmc.IsMountPoint)
 }
 
+func cgroupsMountpoint(tag string) string {
+	if tag != "" {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("cgroups-%s", tag))
+	}
+
+	return "/sys/fs/cgroup"
+}
+
 func createCgroupsStarter(logger lager.Logger, tag string, chowner cgroups.Chowner, mountPointChecker rundmc.MountPointChecker) gardener.Starter {
-	cgroupsMountpoint := "/sys/fs/cgroup"
 	gardenCgroup := "garden"
 	if tag != "" {
-		cgroupsMountpoint = filepath.Join(os.TempDir(), fmt.Sprintf("cgroups-%s", tag))
 		gardenCgroup = fmt.Sprintf("%s-%s", gardenCgroup, tag)
 	}
 
 	return cgroups.NewStarter(logger, mustOpen("/proc/cgroups"), mustOpen("/proc/self/cgroup"),
-		cgroupsMountpoint, gardenCgroup, allowedDevices, linux_command_runner.New(), chowner, mountPointChecker)
+		cgroupsMountpoint(tag), gardenCgroup, allowedDevices, linux_command_runner.New(), chowner, mountPointChecker)
 }
 
 func (f *LinuxFactory) WireResolvConfigurer() kawasaki.DnsResolvConfigurer {
@@ -135,6 +220,92 @@ func (f *LinuxFactory) WireRootfsFileCreator() rundmc.RootfsFileCreator {
 	return preparerootfs.SymlinkRefusingFileCreator{}
 }
 
+func (f *LinuxFactory) WireContainerizer(log lager.Logger, properties gardener.PropertyManager, volumizer peas.Volumizer, peaCleaner gardener.PeaCleaner, lifecycle gardener.LifecycleNotifier) gardener.Containerizer {
+	return f.config.wireContainerizer(log, f, properties, volumizer, peaCleaner, lifecycle)
+}
+
+// WireBaseBundles isolates containers in namespaces, with the capabilities,
+// seccomp filter and devices of privileged or unprivileged containers
+func (f *LinuxFactory) WireBaseBundles(initMount specs.Mount, initPath string) (goci.Bndl, goci.Bndl) {
+	defaultMounts := append(defaultBindMounts(), initMount)
+	privilegedMounts := append(defaultMounts, privilegedMounts()...)
+	unprivilegedMounts := append(defaultMounts, unprivilegedMounts()...)
+
+	// TODO centralize knowledge of garden -> runc capability schema translation
+	baseProcess := specs.Process{
+		Capabilities: &specs.LinuxCapabilities{
+			Effective:   unprivilegedMaxCaps,
+			Bounding:    unprivilegedMaxCaps,
+			Inheritable: unprivilegedMaxCaps,
+			Permitted:   unprivilegedMaxCaps,
+		},
+		Args:        []string{initPath},
+		Cwd:         "/",
+		ConsoleSize: &specs.Box{},
+	}
+
+	baseBundle := goci.Bundle().
+		WithNamespaces(PrivilegedContainerNamespaces...).
+		WithRootFS(f.config.Containers.DefaultRootFS).
+		WithProcess(baseProcess).
+		WithRootFSPropagation("private")
+
+	uidMappings, gidMappings := f.config.idMappings()
+	unprivilegedBundle := baseBundle.
+		WithNamespace(goci.UserNamespace).
+		WithUIDMappings(uidMappings...).
+		WithGIDMappings(gidMappings...).
+		WithMounts(unprivilegedMounts...).
+		WithMaskedPaths(defaultMaskedPaths())
+
+	unprivilegedBundle.Spec.Linux.Seccomp = seccomp
+	if f.config.Containers.ApparmorProfile != "" {
+		unprivilegedBundle = unprivilegedBundle.WithApparmorProfile(f.config.Containers.ApparmorProfile)
+	}
+	privilegedBundle := baseBundle.
+		WithMounts(privilegedMounts...).
+		WithDevices(getPrivilegedDevices()...).
+		WithCapabilities(privilegedMaxCaps...).
+		WithDeviceRestrictions(append(
+			[]specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+			allowedDevices...,
+		))
+
+	return privilegedBundle, unprivilegedBundle
+}
+
+// WireIsolationRules applies the security presets, joined namespaces, cgroup
+// and hardening of each container
+func (f *LinuxFactory) WireIsolationRules() []rundmc.BundlerRule {
+	cgroupRootPath := "garden"
+	if f.config.Server.Tag != "" {
+		cgroupRootPath = fmt.Sprintf("%s-%s", cgroupRootPath, f.config.Server.Tag)
+	}
+
+	rules := []rundmc.BundlerRule{
+		bundlerules.SecurityPresets{Presets: securityPresets()},
+		bundlerules.Namespaces{},
+		bundlerules.CGroupPath{
+			Path: cgroupRootPath,
+		},
+	}
+	if f.config.Containers.ProcHidePID > 0 || len(f.config.Containers.ProcMaskedPaths) > 0 || f.config.Containers.ReadOnlySys {
+		rules = append(rules, bundlerules.ProcSysHardening{
+			HidePID:     f.config.Containers.ProcHidePID,
+			MaskedPaths: f.config.Containers.ProcMaskedPaths,
+			ReadOnlySys: f.config.Containers.ReadOnlySys,
+		})
+	}
+	if f.config.Containers.NoNewPrivileges {
+		rules = append(rules, bundlerules.NoNewPrivileges{})
+	}
+	if f.config.Containers.OOMScoreAdj != nil {
+		rules = append(rules, bundlerules.OOMScoreAdj{Score: *f.config.Containers.OOMScoreAdj})
+	}
+
+	return rules
+}
+
 func initBindMountAndPath(initPathOnHost string) (specs.Mount, string) {
 	initPathInContainer := filepath.Join("/tmp", "garden-init")
 	return specs.Mount{
@@ -211,6 +382,11 @@ func (f *LinuxFactory) wireShed(logger lager.Logger) *rootfs_provider.CakeOrdina
 		logger.Fatal("failed-to-construct-graph-driver", err)
 	}
 
+	downloadsPath := filepath.Join(graphRoot, "downloads")
+	if mkdirErr := os.MkdirAll(downloadsPath, 0700); mkdirErr != nil {
+		logger.Fatal("failed-to-mkdir-downloads", mkdirErr)
+	}
+
 	backingStoresPath := filepath.Join(graphRoot, "backing_stores")
 	if mkdirErr := os.MkdirAll(backingStoresPath, 0660); mkdirErr != nil {
 		logger.Fatal("failed-to-mkdir-backing-stores", mkdirErr)
@@ -250,6 +426,15 @@ func (f *LinuxFactory) wireShed(logger lager.Logger) *rootfs_provider.CakeOrdina
 		}
 	}
 
+	prefetchingDialer := layerfetch.PrefetchingDialer{
+		Dialer:      distclient.NewDialer(f.config.Docker.InsecureRegistries),
+		Parallelism: f.config.Docker.LayerParallelism,
+		TmpDir:      downloadsPath,
+	}
+	if removeErr := prefetchingDialer.RemoveStaleDownloads(); removeErr != nil {
+		logger.Error("failed-to-remove-stale-downloads", removeErr)
+	}
+
 	repoFetcher := repository_fetcher.Retryable{
 		RepositoryFetcher: &repository_fetcher.CompositeFetcher{
 			LocalFetcher: &repository_fetcher.Local{
@@ -260,7 +445,7 @@ func (f *LinuxFactory) wireShed(logger lager.Logger) *rootfs_provider.CakeOrdina
 			RemoteFetcher: repository_fetcher.NewRemote(
 				f.config.Docker.Registry,
 				cake,
-				distclient.NewDialer(f.config.Docker.InsecureRegistries),
+				prefetchingDialer,
 				repository_fetcher.VerifyFunc(repository_fetcher.Verify),
 			),
 		},
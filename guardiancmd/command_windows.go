@@ -7,11 +7,15 @@ import (
 
 	"code.cloudfoundry.org/commandrunner"
 	"code.cloudfoundry.org/commandrunner/windows_command_runner"
+	"code.cloudfoundry.org/guardian/coredumps"
 	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
 	"code.cloudfoundry.org/guardian/rundmc/execrunner"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/guardian/rundmc/peas"
 	"code.cloudfoundry.org/guardian/rundmc/preparerootfs"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/lager"
@@ -72,6 +76,36 @@ func (f *WindowsFactory) WireRootfsFileCreator() rundmc.RootfsFileCreator {
 	return noopRootfsFileCreator{}
 }
 
+// WireContainerizer drives the configured runtime plugin (e.g. winc) through
+// the OCI containerizer, with the Windows base bundles and isolation rules. A
+// native Windows implementation of gardener.Containerizer can be returned
+// from here instead.
+func (f *WindowsFactory) WireContainerizer(log lager.Logger, properties gardener.PropertyManager, volumizer peas.Volumizer, peaCleaner gardener.PeaCleaner, lifecycle gardener.LifecycleNotifier) gardener.Containerizer {
+	return f.config.wireContainerizer(log, f, properties, volumizer, peaCleaner, lifecycle)
+}
+
+// WireBaseBundles runs the init process of containers from a bind mounted
+// directory. Windows containers have no namespaces, capabilities or seccomp,
+// so privileged and unprivileged containers start from the same bundle.
+func (f *WindowsFactory) WireBaseBundles(initMount specs.Mount, initPath string) (goci.Bndl, goci.Bndl) {
+	baseBundle := goci.Bundle().
+		WithRootFS(f.config.Containers.DefaultRootFS).
+		WithProcess(specs.Process{
+			Args:        []string{initPath},
+			Cwd:         "/",
+			ConsoleSize: &specs.Box{},
+		}).
+		WithMounts(initMount)
+
+	return baseBundle, baseBundle
+}
+
+// WireIsolationRules returns no rules, as the runtime plugin isolates Windows
+// containers itself
+func (f *WindowsFactory) WireIsolationRules() []rundmc.BundlerRule {
+	return nil
+}
+
 type noopRootfsFileCreator struct{}
 
 func (noopRootfsFileCreator) CreateFiles(rootFSPath string, pathsToCreate ...string) error {
@@ -98,7 +132,51 @@ func (f *WindowsFactory) WireMkdirer() runrunc.Mkdirer {
 	return mkdirer{}
 }
 
-func wireEnvFunc() runrunc.EnvFunc {
+func platformCapabilities() gardener.Capabilities {
+	return gardener.Capabilities{}
+}
+
+func (cmd *ServerCommand) graphDriver() string {
+	if cmd.Image.Plugin.Path() != "" || cmd.Image.PrivilegedPlugin.Path() != "" {
+		return "image-plugin"
+	}
+
+	return "none"
+}
+
+func wireRootfsRemounter() depot.RootfsRemounter {
+	return nil
+}
+
+func wireBindMountSharer(dir string) depot.BindMountSharer {
+	return nil
+}
+
+func wireProcessOverrider(tag, initPath string) runrunc.ProcessOverrider {
+	return nil
+}
+
+func wireCreateFailureDiagnoser(tag string, requireLayeredFS bool) gardener.CreateFailureDiagnoser {
+	return nil
+}
+
+func wireFileInspector() gardener.FileInspector {
+	return nil
+}
+
+func wireCoreDumpMounter() coredumps.Mounter {
+	return nil
+}
+
+func wireFDCounter() gardener.FDCounter {
+	return nil
+}
+
+func wireCgroupRemover(tag string) gardener.CgroupRemover {
+	return nil
+}
+
+func wireEnvFunc(defaultPath string) runrunc.EnvFunc {
 	return runrunc.EnvFunc(runrunc.WindowsEnvFor)
 }
 
@@ -124,22 +202,6 @@ func initBindMountAndPath(initPathOnHost string) (specs.Mount, string) {
 	}, initPathInContainer
 }
 
-func defaultBindMounts() []specs.Mount {
-	return []specs.Mount{}
-}
-
-func privilegedMounts() []specs.Mount {
-	return []specs.Mount{}
-}
-
-func unprivilegedMounts() []specs.Mount {
-	return []specs.Mount{}
-}
-
-func getPrivilegedDevices() []specs.LinuxDevice {
-	return nil
-}
-
 func bindMountPoints() []string {
 	return nil
 }
@@ -4,11 +4,7 @@ import "github.com/opencontainers/runtime-spec/specs-go"
 
 var seccomp = &specs.LinuxSeccomp{
 	DefaultAction: specs.ActErrno,
-	Architectures: []specs.Arch{
-		specs.ArchX86_64,
-		specs.ArchX86,
-		specs.ArchX32,
-	},
+	Architectures: seccompArchitectures,
 	Syscalls: []specs.LinuxSyscall{
 		specs.LinuxSyscall{
 			Names:  []string{"accept"},
@@ -0,0 +1,9 @@
+package guardiancmd
+
+import "github.com/opencontainers/runtime-spec/specs-go"
+
+var seccompArchitectures = []specs.Arch{
+	specs.ArchX86_64,
+	specs.ArchX86,
+	specs.ArchX32,
+}
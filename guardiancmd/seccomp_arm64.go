@@ -0,0 +1,8 @@
+package guardiancmd
+
+import "github.com/opencontainers/runtime-spec/specs-go"
+
+var seccompArchitectures = []specs.Arch{
+	specs.ArchAARCH64,
+	specs.ArchARM,
+}
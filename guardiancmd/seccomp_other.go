@@ -0,0 +1,8 @@
+// +build !amd64,!arm64
+
+package guardiancmd
+
+import "github.com/opencontainers/runtime-spec/specs-go"
+
+// leaving the architecture list empty makes runc only allow the native one
+var seccompArchitectures []specs.Arch
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/commandrunner"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// ExecHook is a container hook implemented by an operator-supplied binary.
+// The binary is run with --action pre-create or --action post-destroy and
+// --handle <handle>. On pre-create the container spec is written to its
+// stdin as JSON, and it may write a modified spec to its stdout, or nothing
+// to leave the spec as it is. It refuses a create by exiting non-zero, with
+// the reason on its stderr. A hook which runs for longer than Timeout is
+// killed and treated as failed; 0 means no timeout.
+type ExecHook struct {
+	Path          string
+	CommandRunner commandrunner.CommandRunner
+	Timeout       time.Duration
+}
+
+func (h *ExecHook) PreCreate(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+	log = log.Session("pre-create-hook", lager.Data{"path": h.Path})
+
+	stdin, err := json.Marshal(spec)
+	if err != nil {
+		return garden.ContainerSpec{}, err
+	}
+
+	stdout, err := h.exec(log, "pre-create", spec.Handle, stdin)
+	if err != nil {
+		return garden.ContainerSpec{}, err
+	}
+
+	if len(bytes.TrimSpace(stdout)) == 0 {
+		return spec, nil
+	}
+
+	var mutated garden.ContainerSpec
+	if err := json.Unmarshal(stdout, &mutated); err != nil {
+		return garden.ContainerSpec{}, fmt.Errorf("unmarshaling spec from hook %s: %s", h.Path, err)
+	}
+
+	return mutated, nil
+}
+
+func (h *ExecHook) PostDestroy(log lager.Logger, handle string) error {
+	log = log.Session("post-destroy-hook", lager.Data{"path": h.Path})
+
+	_, err := h.exec(log, "post-destroy", handle, nil)
+	return err
+}
+
+func (h *ExecHook) exec(log lager.Logger, action, handle string, stdin []byte) ([]byte, error) {
+	cmd := exec.Command(h.Path, "--action", action, "--handle", handle)
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	timedOut, err := h.run(cmd)
+	if timedOut {
+		err = fmt.Errorf("timed out after %s", h.Timeout)
+		log.Error("hook-result", err, lager.Data{"action": action})
+		return nil, fmt.Errorf("hook %s %s: %s", h.Path, action, err)
+	}
+
+	logData := lager.Data{"action": action, "stderr": stderr.String()}
+	if err != nil {
+		log.Error("hook-result", err, logData)
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return nil, fmt.Errorf("hook %s %s: %s: %s", h.Path, action, err, reason)
+		}
+		return nil, fmt.Errorf("hook %s %s: %s", h.Path, action, err)
+	}
+
+	log.Debug("hook-result", logData)
+	return stdout.Bytes(), nil
+}
+
+// run runs the command, killing it if it runs for longer than the timeout.
+// A command which times out may still be writing to its stdout and stderr,
+// so they must not be read.
+func (h *ExecHook) run(cmd *exec.Cmd) (bool, error) {
+	if h.Timeout == 0 {
+		return false, h.CommandRunner.Run(cmd)
+	}
+
+	if err := h.CommandRunner.Start(cmd); err != nil {
+		return false, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.CommandRunner.Wait(cmd)
+	}()
+
+	timer := time.NewTimer(h.Timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return false, err
+	case <-timer.C:
+		h.CommandRunner.Kill(cmd)
+		return true, nil
+	}
+}
@@ -0,0 +1,173 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"time"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/hooks"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExecHook", func() {
+	var (
+		fakeCommandRunner *fake_command_runner.FakeCommandRunner
+		hookStdout        string
+		hookStderr        string
+		hookErr           error
+		hook              *hooks.ExecHook
+		logger            *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		fakeCommandRunner = fake_command_runner.New()
+		hookStdout = ""
+		hookStderr = ""
+		hookErr = nil
+
+		fakeCommandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "/some/hook",
+		}, func(cmd *exec.Cmd) error {
+			cmd.Stdout.Write([]byte(hookStdout))
+			cmd.Stderr.Write([]byte(hookStderr))
+			return hookErr
+		})
+
+		logger = lagertest.NewTestLogger("test")
+		hook = &hooks.ExecHook{Path: "/some/hook", CommandRunner: fakeCommandRunner}
+	})
+
+	Describe("PreCreate", func() {
+		var spec garden.ContainerSpec
+
+		BeforeEach(func() {
+			spec = garden.ContainerSpec{Handle: "some-handle", Properties: garden.Properties{"tenant": "some-tenant"}}
+		})
+
+		It("runs the hook with the action and handle, passing it the spec", func() {
+			_, err := hook.PreCreate(logger, spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd := fakeCommandRunner.ExecutedCommands()[0]
+			Expect(cmd.Args).To(Equal([]string{"/some/hook", "--action", "pre-create", "--handle", "some-handle"}))
+
+			var input garden.ContainerSpec
+			stdin, err := ioutil.ReadAll(cmd.Stdin)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(stdin, &input)).To(Succeed())
+			Expect(input).To(Equal(spec))
+		})
+
+		It("leaves the spec as it is when the hook writes nothing", func() {
+			Expect(hook.PreCreate(logger, spec)).To(Equal(spec))
+		})
+
+		Context("when the hook writes a spec", func() {
+			BeforeEach(func() {
+				hookStdout = `{"Handle":"some-handle","BindMounts":[{"SrcPath":"/var/vcap/mandatory","DstPath":"/mandatory"}]}`
+			})
+
+			It("returns the spec the hook wrote", func() {
+				mutated, err := hook.PreCreate(logger, spec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mutated.BindMounts).To(Equal([]garden.BindMount{{SrcPath: "/var/vcap/mandatory", DstPath: "/mandatory"}}))
+			})
+		})
+
+		Context("when the hook writes something which is not a spec", func() {
+			BeforeEach(func() {
+				hookStdout = "banana"
+			})
+
+			It("returns an error", func() {
+				_, err := hook.PreCreate(logger, spec)
+				Expect(err).To(MatchError(ContainSubstring("unmarshaling spec from hook /some/hook")))
+			})
+		})
+
+		Context("when the hook refuses the create", func() {
+			BeforeEach(func() {
+				hookStderr = "tenant is over quota\n"
+				hookErr = errors.New("exit status 1")
+			})
+
+			It("returns an error with the reason", func() {
+				_, err := hook.PreCreate(logger, spec)
+				Expect(err).To(MatchError("hook /some/hook pre-create: exit status 1: tenant is over quota"))
+			})
+		})
+	})
+
+	Describe("PostDestroy", func() {
+		It("runs the hook with the action and handle", func() {
+			Expect(hook.PostDestroy(logger, "some-handle")).To(Succeed())
+
+			cmd := fakeCommandRunner.ExecutedCommands()[0]
+			Expect(cmd.Args).To(Equal([]string{"/some/hook", "--action", "post-destroy", "--handle", "some-handle"}))
+		})
+
+		Context("when the hook fails", func() {
+			BeforeEach(func() {
+				hookErr = errors.New("exit status 2")
+			})
+
+			It("returns an error", func() {
+				Expect(hook.PostDestroy(logger, "some-handle")).To(MatchError("hook /some/hook post-destroy: exit status 2"))
+			})
+		})
+	})
+
+	Context("when the hook has a timeout", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			hook.Timeout = 100 * time.Millisecond
+
+			fakeCommandRunner.WhenStarting(fake_command_runner.CommandSpec{
+				Path: "/some/hook",
+			}, func(cmd *exec.Cmd) error {
+				cmd.Stdout.Write([]byte(hookStdout))
+				cmd.Stderr.Write([]byte(hookStderr))
+				return nil
+			})
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("returns what the hook wrote when it finishes in time", func() {
+			hookStdout = `{"Handle":"some-handle","Env":["FOO=bar"]}`
+
+			spec, err := hook.PreCreate(logger, garden.ContainerSpec{Handle: "some-handle"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Env).To(Equal([]string{"FOO=bar"}))
+		})
+
+		Context("when the hook runs for longer than the timeout", func() {
+			BeforeEach(func() {
+				fakeCommandRunner.WhenWaitingFor(fake_command_runner.CommandSpec{
+					Path: "/some/hook",
+				}, func(cmd *exec.Cmd) error {
+					<-release
+					return nil
+				})
+			})
+
+			It("kills the hook and returns an error", func() {
+				_, err := hook.PreCreate(logger, garden.ContainerSpec{Handle: "some-handle"})
+				Expect(err).To(MatchError("hook /some/hook pre-create: timed out after 100ms"))
+
+				Expect(fakeCommandRunner.KilledCommands()).To(HaveLen(1))
+				Expect(fakeCommandRunner.KilledCommands()[0].Args).To(ContainElement("pre-create"))
+			})
+		})
+	})
+})
@@ -49,6 +49,10 @@ func (p *ImagePlugin) Create(log lager.Logger, handle string, spec gardener.Root
 	log.Debug("start")
 	defer log.Debug("end")
 
+	if len(spec.Layers) > 0 {
+		return specs.Spec{}, errors.New("rootfs layers are not supported by the image plugin")
+	}
+
 	if spec.RootFS.String() == "" {
 		var err error
 		spec.RootFS, err = url.Parse(p.DefaultRootfs)
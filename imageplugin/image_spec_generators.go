@@ -1,6 +1,8 @@
 package imageplugin
 
 import (
+	"runtime"
+
 	digest "github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go"
 	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -18,7 +20,7 @@ func GenerateImageConfig(layerSHAs ...string) imagespec.Image {
 		digests = append(digests, digest)
 	}
 	return imagespec.Image{
-		Architecture: "amd64",
+		Architecture: runtime.GOARCH,
 		OS:           "linux",
 		RootFS: imagespec.RootFS{
 			DiffIDs: digests,
@@ -1,6 +1,8 @@
 package imageplugin_test
 
 import (
+	"runtime"
+
 	"code.cloudfoundry.org/guardian/imageplugin"
 
 	. "github.com/onsi/ginkgo"
@@ -15,7 +17,7 @@ var _ = Describe("GenerateImageConfig", func() {
 		config := imageplugin.GenerateImageConfig("sha1", "sha2")
 		Expect(config).To(Equal(imagespec.Image{
 			OS:           "linux",
-			Architecture: "amd64",
+			Architecture: runtime.GOARCH,
 			RootFS: imagespec.RootFS{
 				DiffIDs: []digest.Digest{"sha256:sha1", "sha256:sha2"},
 				Type:    "layers",
@@ -15,10 +15,12 @@ const (
 	maxAllowedMtuSize     = 1500
 )
 
-//go:generate counterfeiter . IDGenerator
+//go:generate counterfeiter . InstanceIndex
 
-type IDGenerator interface {
-	Generate() string
+type InstanceIndex interface {
+	Acquire(handle string) (string, error)
+	Release(handle string) error
+	Instances() (map[string]string, error)
 }
 
 type NetworkConfig struct {
@@ -41,7 +43,7 @@ type NetworkConfig struct {
 }
 
 type Creator struct {
-	idGenerator           IDGenerator
+	instanceIndex         InstanceIndex
 	interfacePrefix       string
 	chainPrefix           string
 	externalIP            net.IP
@@ -51,7 +53,7 @@ type Creator struct {
 	mtu                   int
 }
 
-func NewConfigCreator(idGenerator IDGenerator, interfacePrefix, chainPrefix string, externalIP net.IP, operatorNameservers, additionalNameservers []net.IP, additionalHostEntries []string, mtu int) *Creator {
+func NewConfigCreator(instanceIndex InstanceIndex, interfacePrefix, chainPrefix string, externalIP net.IP, operatorNameservers, additionalNameservers []net.IP, additionalHostEntries []string, mtu int) *Creator {
 	if len(interfacePrefix) > maxInterfacePrefixLen {
 		panic("interface prefix is too long")
 	}
@@ -61,7 +63,7 @@ func NewConfigCreator(idGenerator IDGenerator, interfacePrefix, chainPrefix stri
 	}
 
 	return &Creator{
-		idGenerator:           idGenerator,
+		instanceIndex:         instanceIndex,
 		interfacePrefix:       interfacePrefix,
 		chainPrefix:           chainPrefix,
 		externalIP:            externalIP,
@@ -73,7 +75,11 @@ func NewConfigCreator(idGenerator IDGenerator, interfacePrefix, chainPrefix stri
 }
 
 func (c *Creator) Create(log lager.Logger, handle string, subnet *net.IPNet, ip net.IP) (NetworkConfig, error) {
-	id := c.idGenerator.Generate()
+	id, err := c.instanceIndex.Acquire(handle)
+	if err != nil {
+		return NetworkConfig{}, fmt.Errorf("acquiring instance id: %s", err)
+	}
+
 	return NetworkConfig{
 		ContainerHandle: handle,
 		HostIntf:        fmt.Sprintf("%s%s-0", c.interfacePrefix, id),
@@ -1,6 +1,7 @@
 package kawasaki_test
 
 import (
+	"errors"
 	"net"
 
 	"code.cloudfoundry.org/guardian/kawasaki"
@@ -21,7 +22,7 @@ var _ = Describe("ConfigCreator", func() {
 		additionalNameservers []net.IP
 		additionalHostEntries []string
 		logger                lager.Logger
-		idGenerator           *fakes.FakeIDGenerator
+		instanceIndex         *fakes.FakeInstanceIndex
 		mtu                   int
 	)
 
@@ -44,24 +45,24 @@ var _ = Describe("ConfigCreator", func() {
 		}
 
 		logger = lagertest.NewTestLogger("test")
-		idGenerator = &fakes.FakeIDGenerator{}
+		instanceIndex = &fakes.FakeInstanceIndex{}
 
 		mtu = 1234
 	})
 
 	JustBeforeEach(func() {
-		creator = kawasaki.NewConfigCreator(idGenerator, "w1", "0123456789abcdef", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
+		creator = kawasaki.NewConfigCreator(instanceIndex, "w1", "0123456789abcdef", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
 	})
 
 	It("panics if the interface prefix is longer than 2 characters", func() {
 		Expect(func() {
-			kawasaki.NewConfigCreator(idGenerator, "too-long", "wc", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
+			kawasaki.NewConfigCreator(instanceIndex, "too-long", "wc", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
 		}).To(Panic())
 	})
 
 	It("panics if the chain prefix is longer than 16 characters", func() {
 		Expect(func() {
-			kawasaki.NewConfigCreator(idGenerator, "w1", "0123456789abcdefg", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
+			kawasaki.NewConfigCreator(instanceIndex, "w1", "0123456789abcdefg", externalIP, operatorNameservers, additionalNameservers, additionalHostEntries, mtu)
 		}).To(Panic())
 	})
 
@@ -110,8 +111,8 @@ var _ = Describe("ConfigCreator", func() {
 		})
 	})
 
-	It("assigns the interface names based on the ID from the ID generator", func() {
-		idGenerator.GenerateReturns("cocacola")
+	It("assigns the interface names based on the ID from the instance index", func() {
+		instanceIndex.AcquireReturns("cocacola", nil)
 
 		config, err := creator.Create(logger, "bananashmanana", subnet, ip)
 		Expect(err).NotTo(HaveOccurred())
@@ -122,11 +123,23 @@ var _ = Describe("ConfigCreator", func() {
 		Expect(config.IPTableInstance).To(Equal("cocacola"))
 	})
 
-	It("only generates 1 ID per invocation", func() {
+	It("only acquires 1 ID per invocation, for the handle", func() {
 		_, err := creator.Create(logger, "bananashmanana", subnet, ip)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(idGenerator.GenerateCallCount()).To(Equal(1))
+		Expect(instanceIndex.AcquireCallCount()).To(Equal(1))
+		Expect(instanceIndex.AcquireArgsForCall(0)).To(Equal("bananashmanana"))
+	})
+
+	Context("when acquiring the ID fails", func() {
+		BeforeEach(func() {
+			instanceIndex.AcquireReturns("", errors.New("index-full"))
+		})
+
+		It("returns the error", func() {
+			_, err := creator.Create(logger, "bananashmanana", subnet, ip)
+			Expect(err).To(MatchError("acquiring instance id: index-full"))
+		})
 	})
 
 	It("saves the external ip", func() {
@@ -11,7 +11,7 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/netns"
 )
 
-func NewDefaultConfigurer(ipt *iptables.IPTablesController, depotDir string) kawasaki.Configurer {
+func NewDefaultConfigurer(ipt *iptables.IPTablesController, ipsets iptables.IPSets, depotDir string) kawasaki.Configurer {
 	resolvConfigurer := &kawasaki.ResolvConfigurer{
 		HostsFileCompiler: &dns.HostsFileCompiler{},
 		ResolvCompiler:    &dns.ResolvCompiler{},
@@ -34,6 +34,6 @@ func NewDefaultConfigurer(ipt *iptables.IPTablesController, depotDir string) kaw
 		resolvConfigurer,
 		hostConfigurer,
 		containerConfigurer,
-		iptables.NewInstanceChainCreator(ipt),
+		iptables.NewInstanceChainCreator(ipt, ipsets),
 	)
 }
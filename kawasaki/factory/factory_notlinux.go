@@ -7,6 +7,6 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
 )
 
-func NewDefaultConfigurer(ipt *iptables.IPTablesController, depotDir string) kawasaki.Configurer {
+func NewDefaultConfigurer(ipt *iptables.IPTablesController, ipsets iptables.IPSets, depotDir string) kawasaki.Configurer {
 	panic("not supported on this platform")
 }
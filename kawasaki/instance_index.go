@@ -0,0 +1,133 @@
+package kawasaki
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// instanceIDLen keeps the longest names derived from an instance ID, such as
+// "<chain-prefix>instance-<id>-log" and "<interface-prefix><id>-0", within the
+// iptables and network interface name limits
+const instanceIDLen = 11
+
+// FileInstanceIndex derives the instance ID which names a container's iptables
+// chains and network interfaces from its handle, and records the IDs it has
+// handed out in a JSON file. The record keeps IDs unique when two handles
+// hash to the same ID, and lets chains left behind by failed destroys be
+// found after a restart.
+type FileInstanceIndex struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileInstanceIndex(path string) *FileInstanceIndex {
+	return &FileInstanceIndex{path: path}
+}
+
+// Acquire returns the instance ID of the handle, deriving and recording a new
+// one if the handle does not have one yet
+func (i *FileInstanceIndex) Acquire(handle string) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	instances, err := i.load()
+	if err != nil {
+		return "", err
+	}
+
+	if id, ok := instances[handle]; ok {
+		return id, nil
+	}
+
+	taken := map[string]bool{}
+	for _, id := range instances {
+		taken[id] = true
+	}
+
+	id := instanceID(handle, 0)
+	for attempt := 1; taken[id]; attempt++ {
+		id = instanceID(handle, attempt)
+	}
+
+	instances[handle] = id
+	if err := i.save(instances); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Release idempotently forgets the instance ID of the handle
+func (i *FileInstanceIndex) Release(handle string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	instances, err := i.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := instances[handle]; !ok {
+		return nil
+	}
+
+	delete(instances, handle)
+	return i.save(instances)
+}
+
+// Instances returns the instance ID of every handle in the index
+func (i *FileInstanceIndex) Instances() (map[string]string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.load()
+}
+
+func (i *FileInstanceIndex) load() (map[string]string, error) {
+	instances := map[string]string{}
+
+	contents, err := ioutil.ReadFile(i.path)
+	if os.IsNotExist(err) {
+		return instances, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading instance index: %s", err)
+	}
+
+	if err := json.Unmarshal(contents, &instances); err != nil {
+		return nil, fmt.Errorf("parsing instance index: %s", err)
+	}
+
+	return instances, nil
+}
+
+func (i *FileInstanceIndex) save(instances map[string]string) error {
+	contents, err := json.Marshal(instances)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(i.path, contents); err != nil {
+		return fmt.Errorf("writing instance index: %s", err)
+	}
+
+	return nil
+}
+
+func instanceID(handle string, attempt int) string {
+	input := handle
+	if attempt > 0 {
+		input = handle + "/" + strconv.Itoa(attempt)
+	}
+
+	sum := sha256.Sum256([]byte(input))
+	encoded := base32.StdEncoding.EncodeToString(sum[:])
+	return strings.ToLower(encoded[:instanceIDLen])
+}
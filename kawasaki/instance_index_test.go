@@ -0,0 +1,133 @@
+package kawasaki_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/kawasaki"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileInstanceIndex", func() {
+	var (
+		tmpDir    string
+		indexPath string
+		index     *kawasaki.FileInstanceIndex
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "instance-index")
+		Expect(err).NotTo(HaveOccurred())
+
+		indexPath = filepath.Join(tmpDir, "index", "instances.json")
+		index = kawasaki.NewFileInstanceIndex(indexPath)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("derives an 11 character ID from the handle", func() {
+		id, err := index.Acquire("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(HaveLen(11))
+	})
+
+	It("derives IDs which are safe to use in interface and chain names", func() {
+		id, err := index.Acquire("a handle/with.odd_characters")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(MatchRegexp("^[a-z2-7]+$"))
+	})
+
+	It("derives the same ID for the same handle", func() {
+		id, err := index.Acquire("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+
+		otherIndex := kawasaki.NewFileInstanceIndex(filepath.Join(tmpDir, "other.json"))
+		otherID, err := otherIndex.Acquire("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(otherID).To(Equal(id))
+	})
+
+	It("derives different IDs for long handles which share a prefix", func() {
+		prefix := strings.Repeat("a", 64)
+
+		id1, err := index.Acquire(prefix + "-1")
+		Expect(err).NotTo(HaveOccurred())
+		id2, err := index.Acquire(prefix + "-2")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(id1).NotTo(Equal(id2))
+	})
+
+	It("returns the recorded ID when the handle is acquired again", func() {
+		id, err := index.Acquire("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(kawasaki.NewFileInstanceIndex(indexPath).Acquire("some-handle")).To(Equal(id))
+	})
+
+	Context("when the derived ID is already recorded for another handle", func() {
+		var derivedID string
+
+		BeforeEach(func() {
+			var err error
+			otherIndex := kawasaki.NewFileInstanceIndex(filepath.Join(tmpDir, "other.json"))
+			derivedID, err = otherIndex.Acquire("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Dir(indexPath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(indexPath, []byte(`{"other-handle":"`+derivedID+`"}`), 0600)).To(Succeed())
+		})
+
+		It("derives a different ID", func() {
+			id, err := index.Acquire("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(HaveLen(11))
+			Expect(id).NotTo(Equal(derivedID))
+		})
+	})
+
+	It("lists the recorded IDs by handle", func() {
+		id1, err := index.Acquire("handle-1")
+		Expect(err).NotTo(HaveOccurred())
+		id2, err := index.Acquire("handle-2")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Instances()).To(Equal(map[string]string{"handle-1": id1, "handle-2": id2}))
+	})
+
+	It("lists no IDs when nothing has been acquired", func() {
+		Expect(index.Instances()).To(BeEmpty())
+	})
+
+	It("forgets released handles", func() {
+		_, err := index.Acquire("handle-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Release("handle-1")).To(Succeed())
+		Expect(index.Instances()).To(BeEmpty())
+	})
+
+	It("releases idempotently", func() {
+		Expect(index.Release("never-acquired")).To(Succeed())
+	})
+
+	Context("when the index file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(indexPath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(indexPath, []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, err := index.Acquire("some-handle")
+			Expect(err).To(MatchError(ContainSubstring("parsing instance index")))
+		})
+	})
+})
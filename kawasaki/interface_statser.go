@@ -0,0 +1,43 @@
+package kawasaki
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/gardener"
+)
+
+// SysfsInterfaceStatser reads the counters of network interfaces from the
+// statistics directories under Root, usually /sys/class/net
+type SysfsInterfaceStatser struct {
+	Root string
+}
+
+func (s SysfsInterfaceStatser) Statistics(intf string) (gardener.ContainerNetworkStats, error) {
+	var stats gardener.ContainerNetworkStats
+
+	counters := map[string]*uint64{
+		"rx_bytes":   &stats.RxBytes,
+		"tx_bytes":   &stats.TxBytes,
+		"rx_packets": &stats.RxPackets,
+		"tx_packets": &stats.TxPackets,
+		"rx_dropped": &stats.RxDropped,
+		"tx_dropped": &stats.TxDropped,
+	}
+
+	for name, counter := range counters {
+		data, err := ioutil.ReadFile(filepath.Join(s.Root, intf, "statistics", name))
+		if err != nil {
+			return gardener.ContainerNetworkStats{}, err
+		}
+
+		*counter, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return gardener.ContainerNetworkStats{}, err
+		}
+	}
+
+	return stats, nil
+}
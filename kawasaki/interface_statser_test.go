@@ -0,0 +1,69 @@
+package kawasaki_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/kawasaki"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SysfsInterfaceStatser", func() {
+	var (
+		root    string
+		statser kawasaki.SysfsInterfaceStatser
+	)
+
+	writeCounter := func(name, value string) {
+		Expect(ioutil.WriteFile(filepath.Join(root, "w1abc-0", "statistics", name), []byte(value), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "sys-class-net")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(root, "w1abc-0", "statistics"), 0755)).To(Succeed())
+
+		writeCounter("rx_bytes", "100\n")
+		writeCounter("tx_bytes", "200\n")
+		writeCounter("rx_packets", "3\n")
+		writeCounter("tx_packets", "4\n")
+		writeCounter("rx_dropped", "5\n")
+		writeCounter("tx_dropped", "0\n")
+
+		statser = kawasaki.SysfsInterfaceStatser{Root: root}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	It("reads the counters of the interface", func() {
+		Expect(statser.Statistics("w1abc-0")).To(Equal(gardener.ContainerNetworkStats{
+			RxBytes:   100,
+			TxBytes:   200,
+			RxPackets: 3,
+			TxPackets: 4,
+			RxDropped: 5,
+			TxDropped: 0,
+		}))
+	})
+
+	Context("when the interface does not exist", func() {
+		It("returns an error", func() {
+			_, err := statser.Statistics("w1missing-0")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when a counter is not a number", func() {
+		It("returns an error", func() {
+			writeCounter("rx_dropped", "lots")
+			_, err := statser.Statistics("w1abc-0")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package iptables
 
 import (
+	"fmt"
+	"sync"
+
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 )
@@ -13,6 +16,8 @@ type RuleTranslator interface {
 type FirewallOpener struct {
 	ruleTranslator RuleTranslator
 	iptables       IPTables
+	ipsets         IPSets
+	ipsetsMutex    sync.Mutex
 }
 
 func NewFirewallOpener(ruleTranslator RuleTranslator, iptables IPTables) *FirewallOpener {
@@ -22,6 +27,18 @@ func NewFirewallOpener(ruleTranslator RuleTranslator, iptables IPTables) *Firewa
 	}
 }
 
+// NewIPSetFirewallOpener returns a FirewallOpener which, rather than adding a
+// rule per network and port, adds the networks and ports of NetOut rules to
+// per-container ipsets, each matched by a single rule. Rules which cannot be
+// held in a set, such as logged and ICMP rules, are still added one by one.
+func NewIPSetFirewallOpener(ruleTranslator RuleTranslator, iptables IPTables, ipsets IPSets) *FirewallOpener {
+	return &FirewallOpener{
+		ruleTranslator: ruleTranslator,
+		iptables:       iptables,
+		ipsets:         ipsets,
+	}
+}
+
 func (f *FirewallOpener) Open(logger lager.Logger, instance, handle string, rule garden.NetOutRule) error {
 	chain := f.iptables.InstanceChain(instance)
 	logger = logger.Session("prepend-filter-rule", lager.Data{
@@ -32,6 +49,10 @@ func (f *FirewallOpener) Open(logger lager.Logger, instance, handle string, rule
 	logger.Debug("started")
 	defer logger.Debug("ending")
 
+	if f.ipsets != nil {
+		return f.openWithIPSets(chain, handle, []garden.NetOutRule{rule})
+	}
+
 	iptableRules, err := f.ruleTranslator.TranslateRule(handle, rule)
 	if err != nil {
 		return err
@@ -56,6 +77,10 @@ func (f *FirewallOpener) BulkOpen(logger lager.Logger, instance, handle string,
 	logger.Debug("started")
 	defer logger.Debug("ending")
 
+	if f.ipsets != nil {
+		return f.openWithIPSets(chain, handle, rules)
+	}
+
 	collatedIPTablesRules := []Rule{}
 	for _, rule := range rules {
 		iptablesRules, err := f.ruleTranslator.TranslateRule(handle, rule)
@@ -68,3 +93,121 @@ func (f *FirewallOpener) BulkOpen(logger lager.Logger, instance, handle string,
 
 	return f.iptables.BulkPrependRules(chain, collatedIPTablesRules)
 }
+
+func (f *FirewallOpener) openWithIPSets(chain, handle string, rules []garden.NetOutRule) error {
+	var portEntries, networkEntries []string
+	otherRules := []Rule{}
+	for _, rule := range rules {
+		iptablesRules, err := f.ruleTranslator.TranslateRule(handle, rule)
+		if err != nil {
+			return err
+		}
+
+		if !fitsInSet(rule) {
+			otherRules = append(otherRules, iptablesRules...)
+			continue
+		}
+
+		if len(rule.Ports) > 0 {
+			portEntries = append(portEntries, portSetEntries(rule)...)
+		} else {
+			networkEntries = append(networkEntries, networkSetEntries(rule)...)
+		}
+	}
+
+	if err := f.iptables.BulkPrependRules(chain, otherRules); err != nil {
+		return err
+	}
+
+	portSet, networkSet := instanceSets(chain)
+	if err := f.addToSet(chain, handle, portSet, portSetType, "dst,dst", portEntries); err != nil {
+		return err
+	}
+
+	return f.addToSet(chain, handle, networkSet, networkSetType, "dst", networkEntries)
+}
+
+// addToSet adds the entries to the set, creating it and the rule which
+// matches it the first time
+func (f *FirewallOpener) addToSet(chain, handle, set, setType, direction string, entries []string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	f.ipsetsMutex.Lock()
+	defer f.ipsetsMutex.Unlock()
+
+	exists := f.ipsets.Exists(set)
+	if !exists {
+		if err := f.ipsets.Create(set, setType); err != nil {
+			return err
+		}
+	}
+
+	if err := f.ipsets.AddEntries(set, entries); err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return f.iptables.PrependRule(chain, SetFilterRule{Set: set, Direction: direction, Handle: handle})
+}
+
+// fitsInSet returns whether a rule can be held in an ipset: it must not be
+// logged or match ICMP, must only match specific networks, and must match
+// specific TCP or UDP ports or any protocol
+func fitsInSet(rule garden.NetOutRule) bool {
+	if rule.Log || rule.ICMPs != nil || len(rule.Networks) == 0 {
+		return false
+	}
+
+	for _, network := range rule.Networks {
+		if network.Start == nil && network.End == nil {
+			return false
+		}
+	}
+
+	if len(rule.Ports) > 0 {
+		return allowsPort(rule.Protocol)
+	}
+
+	return rule.Protocol == garden.ProtocolAll
+}
+
+func networkSetEntries(rule garden.NetOutRule) []string {
+	entries := []string{}
+	for _, network := range rule.Networks {
+		entries = append(entries, setNetwork(network))
+	}
+
+	return entries
+}
+
+func portSetEntries(rule garden.NetOutRule) []string {
+	entries := []string{}
+	for _, network := range rule.Networks {
+		for _, ports := range rule.Ports {
+			portRange := fmt.Sprintf("%d", ports.Start)
+			if ports.End != ports.Start {
+				portRange = fmt.Sprintf("%d-%d", ports.Start, ports.End)
+			}
+
+			entries = append(entries, fmt.Sprintf("%s,%s:%s", setNetwork(network), protocols[rule.Protocol], portRange))
+		}
+	}
+
+	return entries
+}
+
+func setNetwork(network garden.IPRange) string {
+	switch {
+	case network.Start != nil && network.End != nil && !network.Start.Equal(network.End):
+		return network.Start.String() + "-" + network.End.String()
+	case network.Start != nil:
+		return network.Start.String()
+	default:
+		return network.End.String()
+	}
+}
@@ -2,6 +2,7 @@ package iptables_test
 
 import (
 	"errors"
+	"net"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
@@ -181,4 +182,120 @@ var _ = Describe("FirewallOpenner", func() {
 			})
 		})
 	})
+
+	Describe("with ipsets", func() {
+		var (
+			fakeIPSets *fakes.FakeIPSets
+			rules      []garden.NetOutRule
+		)
+
+		BeforeEach(func() {
+			fakeIPSets = new(fakes.FakeIPSets)
+			opener = iptables.NewIPSetFirewallOpener(fakeRuleTranslator, fakeIPTablesController, fakeIPSets)
+
+			rules = []garden.NetOutRule{
+				{
+					Protocol: garden.ProtocolTCP,
+					Networks: []garden.IPRange{
+						garden.IPRangeFromIP(net.ParseIP("10.0.0.1")),
+						{Start: net.ParseIP("10.0.1.0"), End: net.ParseIP("10.0.1.255")},
+					},
+					Ports: []garden.PortRange{garden.PortRangeFromPort(443), {Start: 8080, End: 8090}},
+				},
+				{
+					Protocol: garden.ProtocolAll,
+					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("10.0.2.1"))},
+				},
+				{
+					Protocol: garden.ProtocolTCP,
+					Networks: []garden.IPRange{garden.IPRangeFromIP(net.ParseIP("10.0.3.1"))},
+					Ports:    []garden.PortRange{garden.PortRangeFromPort(22)},
+					Log:      true,
+				},
+			}
+		})
+
+		It("adds the networks and ports of the rules to the container's sets", func() {
+			Expect(opener.BulkOpen(logger, "foo-bar-baz", "some-handle", rules)).To(Succeed())
+
+			Expect(fakeIPSets.AddEntriesCallCount()).To(Equal(2))
+			set, entries := fakeIPSets.AddEntriesArgsForCall(0)
+			Expect(set).To(Equal("prefix-foo-bar-baz-p"))
+			Expect(entries).To(Equal([]string{
+				"10.0.0.1,tcp:443",
+				"10.0.0.1,tcp:8080-8090",
+				"10.0.1.0-10.0.1.255,tcp:443",
+				"10.0.1.0-10.0.1.255,tcp:8080-8090",
+			}))
+
+			set, entries = fakeIPSets.AddEntriesArgsForCall(1)
+			Expect(set).To(Equal("prefix-foo-bar-baz-n"))
+			Expect(entries).To(Equal([]string{"10.0.2.1"}))
+		})
+
+		It("prepends the rules which cannot be kept in a set", func() {
+			Expect(opener.BulkOpen(logger, "foo-bar-baz", "some-handle", rules)).To(Succeed())
+
+			Expect(fakeRuleTranslator.TranslateRuleCallCount()).To(Equal(3))
+			_, prependedRules := fakeIPTablesController.BulkPrependRulesArgsForCall(0)
+			Expect(prependedRules).To(HaveLen(1))
+		})
+
+		Context("when the sets do not exist yet", func() {
+			It("creates them and prepends a rule matching each", func() {
+				Expect(opener.BulkOpen(logger, "foo-bar-baz", "some-handle", rules)).To(Succeed())
+
+				Expect(fakeIPSets.CreateCallCount()).To(Equal(2))
+				set, setType := fakeIPSets.CreateArgsForCall(0)
+				Expect(set).To(Equal("prefix-foo-bar-baz-p"))
+				Expect(setType).To(Equal("hash:net,port"))
+				set, setType = fakeIPSets.CreateArgsForCall(1)
+				Expect(set).To(Equal("prefix-foo-bar-baz-n"))
+				Expect(setType).To(Equal("hash:net"))
+
+				Expect(fakeIPTablesController.PrependRuleCallCount()).To(Equal(2))
+				chain, rule := fakeIPTablesController.PrependRuleArgsForCall(0)
+				Expect(chain).To(Equal("prefix-foo-bar-baz"))
+				Expect(rule).To(Equal(iptables.SetFilterRule{Set: "prefix-foo-bar-baz-p", Direction: "dst,dst", Handle: "some-handle"}))
+				_, rule = fakeIPTablesController.PrependRuleArgsForCall(1)
+				Expect(rule).To(Equal(iptables.SetFilterRule{Set: "prefix-foo-bar-baz-n", Direction: "dst", Handle: "some-handle"}))
+			})
+		})
+
+		Context("when the sets already exist", func() {
+			BeforeEach(func() {
+				fakeIPSets.ExistsReturns(true)
+			})
+
+			It("only adds to them", func() {
+				Expect(opener.Open(logger, "foo-bar-baz", "some-handle", rules[0])).To(Succeed())
+
+				Expect(fakeIPSets.CreateCallCount()).To(Equal(0))
+				Expect(fakeIPSets.AddEntriesCallCount()).To(Equal(1))
+				Expect(fakeIPTablesController.PrependRuleCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a rule is invalid", func() {
+			BeforeEach(func() {
+				fakeRuleTranslator.TranslateRuleReturns(nil, errors.New("invalid protocol"))
+			})
+
+			It("returns the error without changing the sets", func() {
+				Expect(opener.BulkOpen(logger, "foo-bar-baz", "some-handle", rules)).To(MatchError("invalid protocol"))
+				Expect(fakeIPSets.AddEntriesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when adding to a set fails", func() {
+			BeforeEach(func() {
+				fakeIPSets.AddEntriesReturns(errors.New("set is full"))
+			})
+
+			It("returns the error without matching the set", func() {
+				Expect(opener.BulkOpen(logger, "foo-bar-baz", "some-handle", rules)).To(MatchError("set is full"))
+				Expect(fakeIPTablesController.PrependRuleCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
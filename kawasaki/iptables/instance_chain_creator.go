@@ -10,11 +10,16 @@ import (
 
 type InstanceChainCreator struct {
 	iptables *IPTablesController
+	ipsets   IPSets
 }
 
-func NewInstanceChainCreator(iptables *IPTablesController) *InstanceChainCreator {
+// NewInstanceChainCreator returns an InstanceChainCreator. If ipsets is not
+// nil, the ipsets a FirewallOpener keeps NetOut rules in are destroyed with
+// the chains which match them.
+func NewInstanceChainCreator(iptables *IPTablesController, ipsets IPSets) *InstanceChainCreator {
 	return &InstanceChainCreator{
 		iptables: iptables,
+		ipsets:   ipsets,
 	}
 }
 
@@ -137,5 +142,12 @@ func (cc *InstanceChainCreator) Destroy(logger lager.Logger, instanceId string)
 	cc.iptables.FlushChain("filter", instanceLoggingChain)
 	cc.iptables.DeleteChain("filter", instanceLoggingChain)
 
+	// destroy the NetOut sets, now that no rule matches them
+	if cc.ipsets != nil {
+		portSet, networkSet := instanceSets(instanceChain)
+		cc.ipsets.Destroy(portSet)
+		cc.ipsets.Destroy(networkSet)
+	}
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"code.cloudfoundry.org/commandrunner/fake_command_runner"
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
+	fakes "code.cloudfoundry.org/guardian/kawasaki/iptables/iptablesfakes"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 
@@ -42,6 +43,7 @@ var _ = Describe("Create", func() {
 		fakeLocksmith := NewFakeLocksmith()
 		creator = iptables.NewInstanceChainCreator(
 			iptables.New("/sbin/iptables", "/sbin/iptables-restore", fakeRunner, fakeLocksmith, "prefix-"),
+			nil,
 		)
 	})
 
@@ -193,6 +195,27 @@ var _ = Describe("Create", func() {
 			Expect(fakeRunner).To(HaveExecutedSerially(specs...))
 		})
 
+		Context("when NetOut rules are kept in ipsets", func() {
+			var fakeIPSets *fakes.FakeIPSets
+
+			BeforeEach(func() {
+				fakeIPSets = new(fakes.FakeIPSets)
+				creator = iptables.NewInstanceChainCreator(
+					iptables.New("/sbin/iptables", "/sbin/iptables-restore", fakeRunner, NewFakeLocksmith(), "prefix-"),
+					fakeIPSets,
+				)
+			})
+
+			It("destroys the sets once the chain is torn down", func() {
+				Expect(creator.Destroy(logger, "some-id")).To(Succeed())
+				Expect(fakeRunner).To(HaveExecutedSerially(specs...))
+
+				Expect(fakeIPSets.DestroyCallCount()).To(Equal(2))
+				Expect(fakeIPSets.DestroyArgsForCall(0)).To(Equal("prefix-instance-some-id-p"))
+				Expect(fakeIPSets.DestroyArgsForCall(1)).To(Equal("prefix-instance-some-id-n"))
+			})
+		})
+
 		Describe("iptables failure", func() {
 			It("returns an error", func() {
 				fakeRunner.WhenRunning(specs[0], func(cmd *exec.Cmd) error {
@@ -0,0 +1,82 @@
+package iptables
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+
+	"code.cloudfoundry.org/commandrunner"
+)
+
+const (
+	portSetType    = "hash:net,port"
+	networkSetType = "hash:net"
+)
+
+// instanceSets returns the names of the sets holding the NetOut rules of the
+// instance chain which match ports, and which match whole networks
+func instanceSets(instanceChain string) (portSet, networkSet string) {
+	return instanceChain + "-p", instanceChain + "-n"
+}
+
+//go:generate counterfeiter . IPSets
+type IPSets interface {
+	Exists(name string) bool
+	Create(name, setType string) error
+	AddEntries(name string, entries []string) error
+	Destroy(name string) error
+}
+
+type IPSetController struct {
+	runner       commandrunner.CommandRunner
+	ipsetBinPath string
+}
+
+func NewIPSetController(ipsetBinPath string, runner commandrunner.CommandRunner) *IPSetController {
+	return &IPSetController{
+		runner:       runner,
+		ipsetBinPath: ipsetBinPath,
+	}
+}
+
+func (s *IPSetController) Exists(name string) bool {
+	// ipset fails to list a set which does not exist
+	return s.runner.Run(exec.Command(s.ipsetBinPath, "list", "-name", name)) == nil
+}
+
+func (s *IPSetController) Create(name, setType string) error {
+	return s.run("create-set", exec.Command(s.ipsetBinPath, "create", name, setType, "-exist"))
+}
+
+func (s *IPSetController) AddEntries(name string, entries []string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	in := bytes.NewBuffer([]byte{})
+	for _, entry := range entries {
+		in.WriteString(fmt.Sprintf("add %s %s\n", name, entry))
+	}
+
+	cmd := exec.Command(s.ipsetBinPath, "restore", "-exist")
+	cmd.Stdin = in
+
+	return s.run("add-entries", cmd)
+}
+
+func (s *IPSetController) Destroy(name string) error {
+	shellCmd := fmt.Sprintf(`%s destroy %s 2> /dev/null || true`, s.ipsetBinPath, name)
+	return s.run("destroy-set", exec.Command("sh", "-c", shellCmd))
+}
+
+func (s *IPSetController) run(action string, cmd *exec.Cmd) error {
+	var buff bytes.Buffer
+	cmd.Stdout = &buff
+	cmd.Stderr = &buff
+
+	if err := s.runner.Run(cmd); err != nil {
+		return fmt.Errorf("ipset: %s: %s", action, buff.String())
+	}
+
+	return nil
+}
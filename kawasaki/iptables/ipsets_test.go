@@ -0,0 +1,96 @@
+package iptables_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os/exec"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	"code.cloudfoundry.org/guardian/kawasaki/iptables"
+
+	. "code.cloudfoundry.org/commandrunner/fake_command_runner/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IPSetController", func() {
+	var (
+		fakeRunner *fake_command_runner.FakeCommandRunner
+		ipsets     *iptables.IPSetController
+	)
+
+	BeforeEach(func() {
+		fakeRunner = fake_command_runner.New()
+		ipsets = iptables.NewIPSetController("/sbin/ipset", fakeRunner)
+	})
+
+	Describe("Exists", func() {
+		It("lists the set", func() {
+			Expect(ipsets.Exists("some-set")).To(BeTrue())
+			Expect(fakeRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+				Path: "/sbin/ipset",
+				Args: []string{"list", "-name", "some-set"},
+			}))
+		})
+
+		It("returns false when the set cannot be listed", func() {
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{Path: "/sbin/ipset"}, func(*exec.Cmd) error {
+				return errors.New("exit status 1")
+			})
+
+			Expect(ipsets.Exists("some-set")).To(BeFalse())
+		})
+	})
+
+	Describe("Create", func() {
+		It("creates the set if it does not exist", func() {
+			Expect(ipsets.Create("some-set", "hash:net")).To(Succeed())
+			Expect(fakeRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+				Path: "/sbin/ipset",
+				Args: []string{"create", "some-set", "hash:net", "-exist"},
+			}))
+		})
+
+		It("returns the output of ipset when it fails", func() {
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{Path: "/sbin/ipset"}, func(cmd *exec.Cmd) error {
+				cmd.Stderr.Write([]byte("Kernel error received: ipset protocol error"))
+				return errors.New("exit status 1")
+			})
+
+			Expect(ipsets.Create("some-set", "hash:net")).To(MatchError("ipset: create-set: Kernel error received: ipset protocol error"))
+		})
+	})
+
+	Describe("AddEntries", func() {
+		It("restores the entries into the set in one call", func() {
+			var stdin string
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{Path: "/sbin/ipset"}, func(cmd *exec.Cmd) error {
+				contents, err := ioutil.ReadAll(cmd.Stdin)
+				stdin = string(contents)
+				return err
+			})
+
+			Expect(ipsets.AddEntries("some-set", []string{"10.0.0.1,tcp:443", "10.0.0.2,udp:53"})).To(Succeed())
+			Expect(fakeRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+				Path: "/sbin/ipset",
+				Args: []string{"restore", "-exist"},
+			}))
+			Expect(stdin).To(Equal("add some-set 10.0.0.1,tcp:443\nadd some-set 10.0.0.2,udp:53\n"))
+		})
+
+		It("does nothing when there are no entries", func() {
+			Expect(ipsets.AddEntries("some-set", nil)).To(Succeed())
+			Expect(fakeRunner.ExecutedCommands()).To(BeEmpty())
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("destroys the set, ignoring sets which do not exist", func() {
+			Expect(ipsets.Destroy("some-set")).To(Succeed())
+			Expect(fakeRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+				Path: "sh",
+				Args: []string{"-c", "/sbin/ipset destroy some-set 2> /dev/null || true"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package iptables
+
+import (
+	"net"
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type QoSMarker struct {
+	iptables *IPTablesController
+}
+
+func NewQoSMarker(iptables *IPTablesController) *QoSMarker {
+	return &QoSMarker{
+		iptables: iptables,
+	}
+}
+
+func (m *QoSMarker) Mark(logger lager.Logger, instanceId, handle string, ip net.IP, dscpClass string) error {
+	instanceChain := m.iptables.InstanceChain(instanceId)
+
+	if err := m.iptables.CreateChain("mangle", instanceChain); err != nil {
+		return err
+	}
+
+	// Mark all traffic passing through the mangle instance chain
+	cmd := exec.Command(m.iptables.iptablesBinPath, "--wait", "--table", "mangle", "-A", instanceChain, "--jump", "DSCP", "--set-dscp-class", dscpClass, "-m", "comment", "--comment", handle)
+	if err := m.iptables.run("mark-qos-class", cmd); err != nil {
+		return err
+	}
+
+	// Bind mangle instance chain to traffic leaving the container
+	cmd = exec.Command(m.iptables.iptablesBinPath, "--wait", "--table", "mangle", "-A", "PREROUTING", "--source", ip.String(), "--jump", instanceChain, "-m", "comment", "--comment", handle)
+	return m.iptables.run("mark-qos-class", cmd)
+}
+
+func (m *QoSMarker) Unmark(logger lager.Logger, instanceId string) error {
+	instanceChain := m.iptables.InstanceChain(instanceId)
+
+	if err := m.iptables.DeleteChainReferences("mangle", "PREROUTING", instanceChain); err != nil {
+		return err
+	}
+
+	if err := m.iptables.FlushChain("mangle", instanceChain); err != nil {
+		return err
+	}
+
+	return m.iptables.DeleteChain("mangle", instanceChain)
+}
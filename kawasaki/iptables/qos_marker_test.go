@@ -0,0 +1,102 @@
+package iptables_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	. "code.cloudfoundry.org/commandrunner/fake_command_runner/matchers"
+	"code.cloudfoundry.org/guardian/kawasaki/iptables"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QoSMarker", func() {
+	var (
+		fakeRunner *fake_command_runner.FakeCommandRunner
+		marker     *iptables.QoSMarker
+		logger     lager.Logger
+	)
+
+	BeforeEach(func() {
+		fakeRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+		fakeLocksmith := NewFakeLocksmith()
+		marker = iptables.NewQoSMarker(
+			iptables.New("/sbin/iptables", "/sbin/iptables-restore", fakeRunner, fakeLocksmith, "prefix-"),
+		)
+	})
+
+	Describe("Mark", func() {
+		var specs []fake_command_runner.CommandSpec
+
+		BeforeEach(func() {
+			specs = []fake_command_runner.CommandSpec{
+				{
+					Path: "/sbin/iptables",
+					Args: []string{"--wait", "--table", "mangle", "-N", "prefix-instance-some-id"},
+				},
+				{
+					Path: "/sbin/iptables",
+					Args: []string{"--wait", "--table", "mangle", "-A", "prefix-instance-some-id",
+						"--jump", "DSCP", "--set-dscp-class", "EF",
+						"-m", "comment", "--comment", "some-handle",
+					},
+				},
+				{
+					Path: "/sbin/iptables",
+					Args: []string{"--wait", "--table", "mangle", "-A", "PREROUTING",
+						"--source", "1.2.3.4", "--jump", "prefix-instance-some-id",
+						"-m", "comment", "--comment", "some-handle",
+					},
+				},
+			}
+		})
+
+		It("marks the container's egress traffic with the DSCP class", func() {
+			Expect(marker.Mark(logger, "some-id", "some-handle", net.ParseIP("1.2.3.4"), "EF")).To(Succeed())
+			Expect(fakeRunner).To(HaveExecutedSerially(specs...))
+		})
+
+		Context("when iptables fails", func() {
+			BeforeEach(func() {
+				fakeRunner.WhenRunning(specs[1], func(cmd *exec.Cmd) error {
+					cmd.Stderr.Write([]byte("iptables failed"))
+					return errors.New("exit status foo")
+				})
+			})
+
+			It("returns an error", func() {
+				Expect(marker.Mark(logger, "some-id", "some-handle", net.ParseIP("1.2.3.4"), "EF")).To(MatchError("iptables: mark-qos-class: iptables failed"))
+			})
+		})
+	})
+
+	Describe("Unmark", func() {
+		It("removes the mangle instance chain and its references", func() {
+			Expect(marker.Unmark(logger, "some-id")).To(Succeed())
+			Expect(fakeRunner).To(HaveExecutedSerially(
+				fake_command_runner.CommandSpec{
+					Path: "sh",
+					Args: []string{"-c", fmt.Sprintf(
+						`set -e; /sbin/iptables --wait --table mangle -S PREROUTING | grep "%s" | sed -e "s/-A/-D/" | xargs --no-run-if-empty --max-lines=1 /sbin/iptables -w -t mangle`,
+						"prefix-instance-some-id",
+					)},
+				},
+				fake_command_runner.CommandSpec{
+					Path: "sh",
+					Args: []string{"-c", "/sbin/iptables --wait --table mangle -F prefix-instance-some-id 2> /dev/null || true"},
+				},
+				fake_command_runner.CommandSpec{
+					Path: "sh",
+					Args: []string{"-c", "/sbin/iptables --wait --table mangle -X prefix-instance-some-id 2> /dev/null || true"},
+				},
+			))
+		})
+	})
+})
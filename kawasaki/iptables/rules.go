@@ -82,3 +82,18 @@ func (r SingleFilterRule) Flags(chain string) (params []string) {
 
 	return params
 }
+
+// SetFilterRule accepts traffic which matches an ipset
+type SetFilterRule struct {
+	Set       string
+	Direction string
+	Handle    string
+}
+
+func (r SetFilterRule) Flags(chain string) []string {
+	return []string{
+		"-m", "set", "--match-set", r.Set, r.Direction,
+		"--jump", "RETURN",
+		"-m", "comment", "--comment", r.Handle,
+	}
+}
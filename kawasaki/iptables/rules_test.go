@@ -158,4 +158,15 @@ var _ = Describe("Rules", func() {
 			}))
 		})
 	})
+
+	Describe("SetFilterRule Flags", func() {
+		It("returns traffic matching the set", func() {
+			rule := iptables.SetFilterRule{Set: "some-set", Direction: "dst,dst", Handle: "some-handle"}
+			Expect(rule.Flags("some-chain")).To(Equal([]string{
+				"-m", "set", "--match-set", "some-set", "dst,dst",
+				"--jump", "RETURN",
+				"-m", "comment", "--comment", "some-handle",
+			}))
+		})
+	})
 })
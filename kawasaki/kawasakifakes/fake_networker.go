@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/lager"
 )
@@ -44,7 +45,7 @@ type FakeNetworker struct {
 	destroyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	NetInStub        func(log lager.Logger, handle string, externalPort, containerPort uint32) (uint32, uint32, error)
+	NetInStub        func(log lager.Logger, handle string, externalPort uint32, containerPort uint32) (uint32, uint32, error)
 	netInMutex       sync.RWMutex
 	netInArgsForCall []struct {
 		log           lager.Logger
@@ -100,6 +101,20 @@ type FakeNetworker struct {
 	restoreReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StatsStub        func(log lager.Logger, handle string) (gardener.ContainerNetworkStats, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	statsReturns struct {
+		result1 gardener.ContainerNetworkStats
+		result2 error
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 gardener.ContainerNetworkStats
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -454,6 +469,58 @@ func (fake *FakeNetworker) RestoreReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeNetworker) Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStats, error) {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Stats", []interface{}{log, handle})
+	fake.statsMutex.Unlock()
+	if fake.StatsStub != nil {
+		return fake.StatsStub(log, handle)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.statsReturns.result1, fake.statsReturns.result2
+}
+
+func (fake *FakeNetworker) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *FakeNetworker) StatsArgsForCall(i int) (lager.Logger, string) {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return fake.statsArgsForCall[i].log, fake.statsArgsForCall[i].handle
+}
+
+func (fake *FakeNetworker) StatsReturns(result1 gardener.ContainerNetworkStats, result2 error) {
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 gardener.ContainerNetworkStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNetworker) StatsReturnsOnCall(i int, result1 gardener.ContainerNetworkStats, result2 error) {
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 gardener.ContainerNetworkStats
+			result2 error
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 gardener.ContainerNetworkStats
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNetworker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -471,6 +538,8 @@ func (fake *FakeNetworker) Invocations() map[string][][]interface{} {
 	defer fake.bulkNetOutMutex.RUnlock()
 	fake.restoreMutex.RLock()
 	defer fake.restoreMutex.RUnlock()
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
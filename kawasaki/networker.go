@@ -17,6 +17,7 @@ import (
 const containerIpKey = gardener.ContainerIPKey
 const bridgeIpKey = gardener.BridgeIPKey
 const externalIpKey = gardener.ExternalIPKey
+const containerSubnetKey = gardener.ContainerSubnetKey
 
 // kawasaki-specific state properties
 const hostIntfKey = "kawasaki.host-interface"
@@ -28,6 +29,8 @@ const iptableInstanceKey = "kawasaki.iptable-inst"
 const mtuKey = "kawasaki.mtu"
 const dnsServerKey = "kawasaki.dns-servers"
 const hostEntriesKey = "kawasaki.host-entries"
+const qosClassKey = "kawasaki.qos-class"
+const reservationKey = "kawasaki.network-reservation"
 
 //go:generate counterfeiter . SpecParser
 
@@ -70,6 +73,14 @@ type PortForwarder interface {
 	Forward(spec PortForwarderSpec) error
 }
 
+// A TransientPortForwarder is a PortForwarder whose forwards only last as long
+// as the server, so they are redone when containers are restored and must be
+// undone when they are destroyed
+type TransientPortForwarder interface {
+	PortForwarder
+	Unforward(handle string) error
+}
+
 type PortForwarderSpec struct {
 	InstanceID  string
 	Handle      string
@@ -86,6 +97,37 @@ type FirewallOpener interface {
 	BulkOpen(log lager.Logger, instance, handle string, rule []garden.NetOutRule) error
 }
 
+//go:generate counterfeiter . QoSMarker
+
+type QoSMarker interface {
+	Mark(log lager.Logger, instanceId, handle string, ip net.IP, dscpClass string) error
+	Unmark(log lager.Logger, instanceId string) error
+}
+
+//go:generate counterfeiter . InterfaceStatser
+
+type InterfaceStatser interface {
+	Statistics(intf string) (gardener.ContainerNetworkStats, error)
+}
+
+//go:generate counterfeiter . ReservationStore
+
+type ReservationStore interface {
+	Get(name string) (gardener.NetworkReservation, bool, error)
+	Claim(name, handle, network string) error
+	Unclaim(name, handle string) error
+	Release(name string) error
+	All() (map[string]gardener.NetworkReservation, error)
+}
+
+//go:generate counterfeiter . PortMappingStore
+
+type PortMappingStore interface {
+	Add(handle, externalIP string, mapping garden.PortMapping) error
+	Get(handle string) (ContainerPortMappings, bool, error)
+	Forget(handle string) error
+}
+
 //go:generate counterfeiter . Networker
 
 type Networker interface {
@@ -96,6 +138,7 @@ type Networker interface {
 	NetOut(log lager.Logger, handle string, rule garden.NetOutRule) error
 	BulkNetOut(log lager.Logger, handle string, rules []garden.NetOutRule) error
 	Restore(log lager.Logger, handle string) error
+	Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStats, error)
 }
 
 type networker struct {
@@ -107,6 +150,15 @@ type networker struct {
 	portPool       PortPool
 	firewallOpener FirewallOpener
 	configurer     Configurer
+	qosMarker      QoSMarker
+	instanceIndex  InstanceIndex
+	statser        InterfaceStatser
+	reservations   ReservationStore
+	portMappings   PortMappingStore
+
+	// qosClasses maps the QoS class names containers may request to the DSCP
+	// class their egress traffic is marked with
+	qosClasses map[string]string
 }
 
 func New(
@@ -118,6 +170,12 @@ func New(
 	portPool PortPool,
 	portForwarder PortForwarder,
 	firewallOpener FirewallOpener,
+	qosMarker QoSMarker,
+	qosClasses map[string]string,
+	instanceIndex InstanceIndex,
+	statser InterfaceStatser,
+	reservations ReservationStore,
+	portMappings PortMappingStore,
 ) *networker {
 	return &networker{
 		specParser:    specParser,
@@ -130,6 +188,14 @@ func New(
 		portPool:      portPool,
 
 		firewallOpener: firewallOpener,
+
+		qosMarker:  qosMarker,
+		qosClasses: qosClasses,
+
+		instanceIndex: instanceIndex,
+		statser:       statser,
+		reservations:  reservations,
+		portMappings:  portMappings,
 	}
 }
 
@@ -142,15 +208,21 @@ func (n *networker) Network(log lager.Logger, containerSpec garden.ContainerSpec
 	log.Info("started")
 	defer log.Info("finished")
 
-	subnetReq, ipReq, err := n.specParser.Parse(log, containerSpec.Network)
+	reservation := containerSpec.Properties[gardener.NetworkReservationKey]
+	network, reserved, err := n.reservedNetwork(containerSpec.Handle, reservation, containerSpec.Network)
 	if err != nil {
-		log.Error("parse-failed", err)
+		log.Error("network-reservation-failed", err)
 		return err
 	}
 
-	subnet, ip, err := n.subnetPool.Acquire(log, subnetReq, ipReq)
+	dscpClass, err := n.dscpClass(containerSpec.Properties)
+	if err != nil {
+		log.Error("qos-class-failed", err)
+		return err
+	}
+
+	subnet, ip, err := n.acquire(log, network, reserved)
 	if err != nil {
-		log.Error("acquire-failed", err)
 		return err
 	}
 
@@ -163,10 +235,34 @@ func (n *networker) Network(log lager.Logger, containerSpec garden.ContainerSpec
 
 	save(n.configStore, containerSpec.Handle, config)
 
+	if reservation != "" {
+		// the name is stored first so that destroy keeps the IP in the pool
+		// for the reservation if it exists, even when claiming it failed
+		n.configStore.Set(containerSpec.Handle, reservationKey, reservation)
+
+		ones, _ := config.Subnet.Mask.Size()
+		reservedNetwork := fmt.Sprintf("%s/%d", config.ContainerIP, ones)
+		if err := n.reservations.Claim(reservation, containerSpec.Handle, reservedNetwork); err != nil {
+			log.Error("claim-network-reservation-failed", err)
+			return err
+		}
+	}
+
 	if err := n.configurer.Apply(log, config, pid); err != nil {
 		return err
 	}
 
+	if dscpClass != "" {
+		// the class is stored first so that destroy unmarks a partly marked
+		// container, whose chain would otherwise be left for the next
+		// container given the same instance
+		n.configStore.Set(containerSpec.Handle, qosClassKey, dscpClass)
+		if err := n.qosMarker.Mark(log, config.IPTableInstance, containerSpec.Handle, config.ContainerIP, dscpClass); err != nil {
+			log.Error("mark-qos-class-failed", err)
+			return err
+		}
+	}
+
 	for _, netIn := range containerSpec.NetIn {
 		if _, _, err := n.NetIn(log, containerSpec.Handle, netIn.HostPort, netIn.ContainerPort); err != nil {
 			return err
@@ -180,6 +276,206 @@ func (n *networker) Network(log lager.Logger, containerSpec garden.ContainerSpec
 	return nil
 }
 
+// PlanNetwork checks the network reservation, QoS class and network spec of a
+// container, without acquiring its network
+func (n *networker) PlanNetwork(log lager.Logger, containerSpec garden.ContainerSpec) error {
+	log = log.Session("plan-network", lager.Data{
+		"handle": containerSpec.Handle,
+		"spec":   containerSpec.Network,
+	})
+
+	reservation := containerSpec.Properties[gardener.NetworkReservationKey]
+	network, reserved, err := n.reservedNetwork(containerSpec.Handle, reservation, containerSpec.Network)
+	if err != nil {
+		return err
+	}
+
+	if _, err := n.dscpClass(containerSpec.Properties); err != nil {
+		return err
+	}
+
+	if reserved {
+		return nil
+	}
+
+	if _, _, err := n.specParser.Parse(log, network); err != nil {
+		log.Error("parse-failed", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *networker) dscpClass(properties garden.Properties) (string, error) {
+	class, ok := properties[gardener.NetworkQoSClassKey]
+	if !ok || class == "" {
+		return "", nil
+	}
+
+	dscpClass, ok := n.qosClasses[class]
+	if !ok {
+		return "", fmt.Errorf("unknown network qos class: %s", class)
+	}
+
+	return dscpClass, nil
+}
+
+// reservedNetwork returns the network spec to acquire for a container, and
+// whether it is the network of an existing reservation. A container using an
+// existing reservation gets the reserved network, and may only request that
+// network explicitly. A container creating a reservation, or not using one,
+// may not request an IP which another reservation holds.
+func (n *networker) reservedNetwork(handle, name, requested string) (string, bool, error) {
+	if name != "" {
+		reservation, ok, err := n.reservations.Get(name)
+		if err != nil {
+			return "", false, err
+		}
+
+		if ok {
+			if reservation.Handle != "" && reservation.Handle != handle {
+				return "", false, fmt.Errorf("network reservation '%s' is in use by container '%s'", name, reservation.Handle)
+			}
+
+			if requested != "" && !networkMatches(requested, reservation.Network) {
+				return "", false, fmt.Errorf("the requested network %s does not match the network %s held by reservation '%s'", requested, reservation.Network, name)
+			}
+
+			return reservation.Network, true, nil
+		}
+
+		if requested == "" {
+			return "", false, fmt.Errorf("network reservation '%s' does not exist, and creating it requires a requested network", name)
+		}
+	}
+
+	if requested == "" {
+		return requested, false, nil
+	}
+
+	requestedIP, _, err := net.ParseCIDR(suffixIfNeeded(requested))
+	if err != nil {
+		// leave reporting the malformed spec to the spec parser
+		return requested, false, nil
+	}
+
+	reservations, err := n.reservations.All()
+	if err != nil {
+		return "", false, err
+	}
+
+	for reservationName, reservation := range reservations {
+		reservedIP, _, err := net.ParseCIDR(reservation.Network)
+		if err == nil && reservedIP.Equal(requestedIP) {
+			return "", false, fmt.Errorf("the requested IP %s is held by network reservation '%s'", requestedIP, reservationName)
+		}
+	}
+
+	return requested, false, nil
+}
+
+// acquire returns the subnet and IP for the network spec. The IPs of
+// reservations stay out of the pool until the reservation is released, so a
+// reserved network is used as it is rather than acquired.
+func (n *networker) acquire(log lager.Logger, network string, reserved bool) (*net.IPNet, net.IP, error) {
+	if reserved {
+		ip, subnet, err := net.ParseCIDR(network)
+		if err != nil {
+			log.Error("parse-reserved-network-failed", err)
+			return nil, nil, fmt.Errorf("parsing reserved network: %s", err)
+		}
+
+		return subnet, ip, nil
+	}
+
+	subnetReq, ipReq, err := n.specParser.Parse(log, network)
+	if err != nil {
+		log.Error("parse-failed", err)
+		return nil, nil, err
+	}
+
+	subnet, ip, err := n.subnetPool.Acquire(log, subnetReq, ipReq)
+	if err != nil {
+		log.Error("acquire-failed", err)
+		return nil, nil, err
+	}
+
+	return subnet, ip, nil
+}
+
+// holdsReservedIP reports whether the named reservation exists and holds the
+// given IP, in which case the IP stays out of the pool for the reservation
+func (n *networker) holdsReservedIP(name string, ip net.IP) (bool, error) {
+	reservation, ok, err := n.reservations.Get(name)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	reservedIP, _, err := net.ParseCIDR(reservation.Network)
+	if err != nil {
+		return false, nil
+	}
+
+	return reservedIP.Equal(ip), nil
+}
+
+// networkMatches reports whether a requested network spec is satisfied by the
+// reserved network, i.e. it names either the reserved IP or its subnet
+func networkMatches(requested, reserved string) bool {
+	requestedIP, requestedNet, err := net.ParseCIDR(suffixIfNeeded(requested))
+	if err != nil {
+		return false
+	}
+
+	reservedIP, reservedNet, err := net.ParseCIDR(reserved)
+	if err != nil {
+		return false
+	}
+
+	if requestedNet.String() != reservedNet.String() {
+		return false
+	}
+
+	return requestedIP.Equal(subnets.NetworkIP(requestedNet)) || requestedIP.Equal(reservedIP)
+}
+
+// Reservations returns every network reservation, by name
+func (n *networker) Reservations(log lager.Logger) (map[string]gardener.NetworkReservation, error) {
+	return n.reservations.All()
+}
+
+// ReleaseReservation deletes the named network reservation, and returns its IP
+// to the pool. Reservations held by a container cannot be released.
+func (n *networker) ReleaseReservation(log lager.Logger, name string) error {
+	reservation, ok, err := n.reservations.Get(name)
+	if err != nil {
+		log.Error("get-network-reservation-failed", err, lager.Data{"name": name})
+		return err
+	}
+
+	if err := n.reservations.Release(name); err != nil {
+		log.Error("release-network-reservation-failed", err, lager.Data{"name": name})
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	ip, subnet, err := net.ParseCIDR(reservation.Network)
+	if err != nil {
+		log.Error("parse-reserved-network-failed", err, lager.Data{"name": name})
+		return nil
+	}
+
+	if err := n.subnetPool.Release(subnet, ip); err != nil && err != subnets.ErrReleasedUnallocatedSubnet {
+		log.Error("release-reserved-ip-failed", err, lager.Data{"name": name})
+		return err
+	}
+
+	return nil
+}
+
 // Capacity returns the number of subnets this network can host
 func (n *networker) Capacity() uint64 {
 	return uint64(n.subnetPool.Capacity())
@@ -215,16 +511,48 @@ func (n *networker) NetIn(log lager.Logger, handle string, externalPort, contain
 		return 0, 0, err
 	}
 
-	if err := AddPortMapping(log, n.configStore, handle, garden.PortMapping{
+	mapping := garden.PortMapping{
 		HostPort:      externalPort,
 		ContainerPort: containerPort,
-	}); err != nil {
+	}
+
+	if err := AddPortMapping(log, n.configStore, handle, mapping); err != nil {
+		return 0, 0, err
+	}
+
+	if err := n.portMappings.Add(handle, cfg.ExternalIP.String(), mapping); err != nil {
+		log.Error("record-port-mapping-failed", err)
 		return 0, 0, err
 	}
 
 	return externalPort, containerPort, nil
 }
 
+// Stats returns the traffic through the container's network interface. The
+// counters are read from the host end of the container's veth pair, so what
+// the host end transmits the container receives and vice versa.
+func (n *networker) Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStats, error) {
+	cfg, err := load(n.configStore, handle)
+	if err != nil {
+		return gardener.ContainerNetworkStats{}, err
+	}
+
+	hostStats, err := n.statser.Statistics(cfg.HostIntf)
+	if err != nil {
+		log.Error("read-interface-stats-failed", err, lager.Data{"interface": cfg.HostIntf})
+		return gardener.ContainerNetworkStats{}, err
+	}
+
+	return gardener.ContainerNetworkStats{
+		RxBytes:   hostStats.TxBytes,
+		TxBytes:   hostStats.RxBytes,
+		RxPackets: hostStats.TxPackets,
+		TxPackets: hostStats.RxPackets,
+		RxDropped: hostStats.TxDropped,
+		TxDropped: hostStats.RxDropped,
+	}, nil
+}
+
 func (n *networker) NetOut(log lager.Logger, handle string, rule garden.NetOutRule) error {
 	cfg, err := load(n.configStore, handle)
 	if err != nil {
@@ -254,11 +582,49 @@ func (n *networker) Destroy(log lager.Logger, handle string) error {
 		return err
 	}
 
-	if err := n.subnetPool.Release(cfg.Subnet, cfg.ContainerIP); err != nil && err != subnets.ErrReleasedUnallocatedSubnet {
-		log.Error("release-failed", err)
+	if forwarder, ok := n.portForwarder.(TransientPortForwarder); ok {
+		if err := forwarder.Unforward(handle); err != nil {
+			log.Error("unforward-ports-failed", err)
+			return err
+		}
+	}
+
+	if _, ok := n.configStore.Get(handle, qosClassKey); ok {
+		if err := n.qosMarker.Unmark(log, cfg.IPTableInstance); err != nil {
+			log.Error("unmark-qos-class-failed", err)
+			return err
+		}
+	}
+
+	if err := n.instanceIndex.Release(handle); err != nil {
+		log.Error("release-instance-id-failed", err)
 		return err
 	}
 
+	reservation, reserved := n.configStore.Get(handle, reservationKey)
+	if reserved {
+		if reserved, err = n.holdsReservedIP(reservation, cfg.ContainerIP); err != nil {
+			log.Error("get-network-reservation-failed", err)
+			return err
+		}
+	}
+
+	// a reserved IP stays out of the pool until the reservation is released,
+	// so that no other container can be given it in the meantime
+	if !reserved {
+		if err := n.subnetPool.Release(cfg.Subnet, cfg.ContainerIP); err != nil && err != subnets.ErrReleasedUnallocatedSubnet {
+			log.Error("release-failed", err)
+			return err
+		}
+	}
+
+	if reservation != "" {
+		if err := n.reservations.Unclaim(reservation, handle); err != nil {
+			log.Error("unclaim-network-reservation-failed", err)
+			return err
+		}
+	}
+
 	if ports, ok := n.configStore.Get(handle, gardener.MappedPortsKey); ok {
 		mappings, err := portsFromJson(ports)
 		if err != nil {
@@ -270,6 +636,11 @@ func (n *networker) Destroy(log lager.Logger, handle string) error {
 		}
 	}
 
+	if err := n.portMappings.Forget(handle); err != nil {
+		log.Error("forget-port-mappings-failed", err)
+		return err
+	}
+
 	err = n.subnetPool.RunIfFree(cfg.Subnet, func() error {
 		return n.configurer.DestroyBridge(log, cfg)
 	})
@@ -277,14 +648,129 @@ func (n *networker) Destroy(log lager.Logger, handle string) error {
 	return err
 }
 
+// Orphans returns the handles which still have iptables chains recorded in the
+// instance index but no longer have a container
+func (n *networker) Orphans(log lager.Logger, knownHandles []string) ([]string, error) {
+	instances, err := n.instanceIndex.Instances()
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, handle := range knownHandles {
+		known[handle] = true
+	}
+
+	orphans := []string{}
+	for handle := range instances {
+		if !known[handle] {
+			orphans = append(orphans, handle)
+		}
+	}
+
+	return orphans, nil
+}
+
+// CleanOrphan removes the iptables chains which a failed destroy left behind
+// for the handle, gives up any network reservation it still holds, and then
+// releases its instance ID
+func (n *networker) CleanOrphan(log lager.Logger, handle string) error {
+	instances, err := n.instanceIndex.Instances()
+	if err != nil {
+		return err
+	}
+
+	instanceId, ok := instances[handle]
+	if !ok {
+		return nil
+	}
+
+	cfg := NetworkConfig{ContainerHandle: handle, IPTableInstance: instanceId}
+	if err := n.configurer.DestroyIPTablesRules(log, cfg); err != nil {
+		return err
+	}
+
+	if err := n.qosMarker.Unmark(log, instanceId); err != nil {
+		return err
+	}
+
+	reservations, err := n.reservations.All()
+	if err != nil {
+		return err
+	}
+
+	for name, reservation := range reservations {
+		if reservation.Handle == handle {
+			if err := n.reservations.Unclaim(name, handle); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := n.portMappings.Forget(handle); err != nil {
+		return err
+	}
+
+	return n.instanceIndex.Release(handle)
+}
+
+// ReleaseRecorded releases the network recorded for a container whose
+// properties have been lost, which Destroy can no longer find: its IP, its
+// host ports, and everything CleanOrphan releases. The bridge is left, as its
+// name cannot be recovered without the properties.
+func (n *networker) ReleaseRecorded(log lager.Logger, handle string, resources gardener.ContainerResources) error {
+	log = log.Session("release-recorded", lager.Data{"handle": handle})
+
+	if resources.Subnet != "" && resources.ContainerIP != "" {
+		_, subnet, err := net.ParseCIDR(resources.Subnet)
+		if err != nil {
+			return fmt.Errorf("parsing recorded subnet: %s", err)
+		}
+
+		ip := net.ParseIP(resources.ContainerIP)
+		if ip == nil {
+			return fmt.Errorf("parsing recorded container IP: %s", resources.ContainerIP)
+		}
+
+		reserved, err := n.isReservedIP(ip)
+		if err != nil {
+			return err
+		}
+
+		if !reserved {
+			if err := n.subnetPool.Release(subnet, ip); err != nil && err != subnets.ErrReleasedUnallocatedSubnet {
+				log.Error("release-failed", err)
+				return err
+			}
+		}
+	}
+
+	for _, port := range resources.HostPorts {
+		n.portPool.Release(port)
+	}
+
+	if forwarder, ok := n.portForwarder.(TransientPortForwarder); ok {
+		if err := forwarder.Unforward(handle); err != nil {
+			log.Error("unforward-ports-failed", err)
+			return err
+		}
+	}
+
+	return n.CleanOrphan(log, handle)
+}
+
 func (n *networker) Restore(log lager.Logger, handle string) error {
+	if err := n.recoverPortMappings(handle); err != nil {
+		return fmt.Errorf("recovering port mappings %s: %v", handle, err)
+	}
+
 	networkConfig, err := load(n.configStore, handle)
 	if err != nil {
 		return fmt.Errorf("loading %s: %v", handle, err)
 	}
 
 	err = n.subnetPool.Remove(networkConfig.Subnet, networkConfig.ContainerIP)
-	if err != nil {
+	if err != nil && !(err == subnets.ErrOverlapsExistingSubnet && n.restoresReservedIP(handle, networkConfig.ContainerIP)) {
 		return fmt.Errorf("subnet pool removing %s: %v", handle, err)
 	}
 
@@ -304,6 +790,70 @@ func (n *networker) Restore(log lager.Logger, handle string) error {
 		}
 	}
 
+	if _, ok := n.portForwarder.(TransientPortForwarder); !ok {
+		return nil
+	}
+
+	for _, mapping := range currentMappings {
+		if err := n.portForwarder.Forward(PortForwarderSpec{
+			InstanceID:  networkConfig.IPTableInstance,
+			Handle:      handle,
+			FromPort:    mapping.HostPort,
+			ToPort:      mapping.ContainerPort,
+			ContainerIP: networkConfig.ContainerIP,
+			ExternalIP:  networkConfig.ExternalIP,
+		}); err != nil {
+			return fmt.Errorf("forwarding port %d of %s: %v", mapping.HostPort, handle, err)
+		}
+	}
+
+	return nil
+}
+
+// isReservedIP reports whether any network reservation holds the IP
+func (n *networker) isReservedIP(ip net.IP) (bool, error) {
+	reservations, err := n.reservations.All()
+	if err != nil {
+		return false, err
+	}
+
+	for _, reservation := range reservations {
+		reservedIP, _, err := net.ParseCIDR(reservation.Network)
+		if err == nil && reservedIP.Equal(ip) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// restoresReservedIP reports whether the container holds a reservation for
+// the IP, which the ReservedIPStarter has already taken out of the pool
+func (n *networker) restoresReservedIP(handle string, ip net.IP) bool {
+	reservation, ok := n.configStore.Get(handle, reservationKey)
+	if !ok {
+		return false
+	}
+
+	holds, err := n.holdsReservedIP(reservation, ip)
+	return err == nil && holds
+}
+
+// recoverPortMappings restores the NetIn mappings and external IP properties of
+// the container from the port mapping store. The store is written as each
+// mapping is made, so it is up to date even when the properties were last
+// saved before the server crashed.
+func (n *networker) recoverPortMappings(handle string) error {
+	recorded, ok, err := n.portMappings.Get(handle)
+	if err != nil || !ok {
+		return err
+	}
+
+	n.configStore.Set(handle, gardener.MappedPortsKey, portMappingList(recorded.Mappings).toJson())
+	if _, ok := n.configStore.Get(handle, externalIpKey); !ok {
+		n.configStore.Set(handle, externalIpKey, recorded.ExternalIP)
+	}
+
 	return nil
 }
 
@@ -342,6 +892,7 @@ func save(config ConfigStore, handle string, netConfig NetworkConfig) {
 	config.Set(handle, bridgeIpKey, netConfig.BridgeIP.String())
 	config.Set(handle, containerIpKey, netConfig.ContainerIP.String())
 	config.Set(handle, subnetKey, netConfig.Subnet.String())
+	config.Set(handle, containerSubnetKey, netConfig.Subnet.String())
 	config.Set(handle, iptablePrefixKey, netConfig.IPTablePrefix)
 	config.Set(handle, iptableInstanceKey, netConfig.IPTableInstance)
 	config.Set(handle, mtuKey, strconv.Itoa(netConfig.Mtu))
@@ -29,6 +29,11 @@ var _ = Describe("Networker", func() {
 		fakePortPool       *fakes.FakePortPool
 		fakeFirewallOpener *fakes.FakeFirewallOpener
 		fakeConfigurer     *fakes.FakeConfigurer
+		fakeQoSMarker      *fakes.FakeQoSMarker
+		fakeInstanceIndex  *fakes.FakeInstanceIndex
+		fakeStatser        *fakes.FakeInterfaceStatser
+		fakeReservations   *fakes.FakeReservationStore
+		fakePortMappings   *fakes.FakePortMappingStore
 		containerSpec      garden.ContainerSpec
 		networker          kawasaki.Networker
 		logger             lager.Logger
@@ -45,6 +50,11 @@ var _ = Describe("Networker", func() {
 		fakePortPool = new(fakes.FakePortPool)
 		fakeFirewallOpener = new(fakes.FakeFirewallOpener)
 		fakeConfigurer = new(fakes.FakeConfigurer)
+		fakeQoSMarker = new(fakes.FakeQoSMarker)
+		fakeInstanceIndex = new(fakes.FakeInstanceIndex)
+		fakeStatser = new(fakes.FakeInterfaceStatser)
+		fakeReservations = new(fakes.FakeReservationStore)
+		fakePortMappings = new(fakes.FakePortMappingStore)
 
 		containerSpec = garden.ContainerSpec{
 			Handle:  "some-handle",
@@ -83,6 +93,12 @@ var _ = Describe("Networker", func() {
 			fakePortPool,
 			fakePortForwarder,
 			fakeFirewallOpener,
+			fakeQoSMarker,
+			map[string]string{"system": "EF"},
+			fakeInstanceIndex,
+			fakeStatser,
+			fakeReservations,
+			fakePortMappings,
 		)
 
 		ip, subnet, err := net.ParseCIDR("123.123.123.12/24")
@@ -199,6 +215,7 @@ var _ = Describe("Networker", func() {
 			Expect(config[gardener.ContainerIPKey]).To(Equal(networkConfig.ContainerIP.String()))
 			Expect(config[gardener.ExternalIPKey]).To(Equal(networkConfig.ExternalIP.String()))
 			Expect(config["kawasaki.subnet"]).To(Equal(networkConfig.Subnet.String()))
+			Expect(config[gardener.ContainerSubnetKey]).To(Equal(networkConfig.Subnet.String()))
 			Expect(config["kawasaki.iptable-prefix"]).To(Equal(networkConfig.IPTablePrefix))
 			Expect(config["kawasaki.iptable-inst"]).To(Equal(networkConfig.IPTableInstance))
 			Expect(config["kawasaki.mtu"]).To(Equal(strconv.Itoa(networkConfig.Mtu)))
@@ -258,6 +275,202 @@ var _ = Describe("Networker", func() {
 				Expect(err).To(MatchError("some error"))
 			})
 		})
+
+		It("does not mark traffic when no QoS class is requested", func() {
+			Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+			Expect(fakeQoSMarker.MarkCallCount()).To(Equal(0))
+		})
+
+		Context("when a QoS class is requested", func() {
+			BeforeEach(func() {
+				containerSpec.Properties = garden.Properties{gardener.NetworkQoSClassKey: "system"}
+			})
+
+			It("marks the container's traffic with the class's DSCP class", func() {
+				Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+				Expect(fakeQoSMarker.MarkCallCount()).To(Equal(1))
+				_, instanceId, handle, ip, dscpClass := fakeQoSMarker.MarkArgsForCall(0)
+				Expect(instanceId).To(Equal(networkConfig.IPTableInstance))
+				Expect(handle).To(Equal("some-handle"))
+				Expect(ip).To(Equal(networkConfig.ContainerIP))
+				Expect(dscpClass).To(Equal("EF"))
+			})
+
+			It("stores the DSCP class in the ConfigStore", func() {
+				config := make(map[string]string)
+				fakeConfigStore.SetStub = func(handle, name, value string) {
+					Expect(handle).To(Equal("some-handle"))
+					config[name] = value
+				}
+
+				Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+				Expect(config["kawasaki.qos-class"]).To(Equal("EF"))
+			})
+
+			Context("when marking fails", func() {
+				BeforeEach(func() {
+					fakeQoSMarker.MarkReturns(errors.New("no-marks"))
+				})
+
+				It("returns the error", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(MatchError("no-marks"))
+				})
+
+				It("still stores the DSCP class, so that destroy removes what was marked", func() {
+					config := make(map[string]string)
+					fakeConfigStore.SetStub = func(handle, name, value string) {
+						config[name] = value
+					}
+
+					Expect(networker.Network(logger, containerSpec, 42)).NotTo(Succeed())
+					Expect(config["kawasaki.qos-class"]).To(Equal("EF"))
+				})
+			})
+
+			Context("when the QoS class is unknown", func() {
+				BeforeEach(func() {
+					containerSpec.Properties = garden.Properties{gardener.NetworkQoSClassKey: "first-class"}
+				})
+
+				It("returns an error before acquiring a subnet", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(MatchError("unknown network qos class: first-class"))
+					Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the requested IP is held by a network reservation", func() {
+			BeforeEach(func() {
+				fakeReservations.AllReturns(map[string]gardener.NetworkReservation{
+					"someone-else": {Network: "1.2.3.4/30"},
+				}, nil)
+			})
+
+			It("returns an error before acquiring a subnet", func() {
+				err := networker.Network(logger, containerSpec, 42)
+				Expect(err).To(MatchError("the requested IP 1.2.3.4 is held by network reservation 'someone-else'"))
+				Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a network reservation is requested", func() {
+			BeforeEach(func() {
+				containerSpec.Properties = garden.Properties{gardener.NetworkReservationKey: "stable"}
+			})
+
+			Context("and the reservation does not exist yet", func() {
+				It("acquires the requested network", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+					_, spec := fakeSpecParser.ParseArgsForCall(0)
+					Expect(spec).To(Equal("1.2.3.4/30"))
+				})
+
+				It("claims the reservation for the acquired IP", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+					Expect(fakeReservations.ClaimCallCount()).To(Equal(1))
+					name, handle, network := fakeReservations.ClaimArgsForCall(0)
+					Expect(name).To(Equal("stable"))
+					Expect(handle).To(Equal("some-handle"))
+					Expect(network).To(Equal("123.123.123.12/24"))
+				})
+
+				It("stores the reservation name in the ConfigStore", func() {
+					config := make(map[string]string)
+					fakeConfigStore.SetStub = func(handle, name, value string) {
+						config[name] = value
+					}
+
+					Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+					Expect(config["kawasaki.network-reservation"]).To(Equal("stable"))
+				})
+
+				Context("when no network is requested", func() {
+					BeforeEach(func() {
+						containerSpec.Network = ""
+					})
+
+					It("returns an error before acquiring a subnet", func() {
+						err := networker.Network(logger, containerSpec, 42)
+						Expect(err).To(MatchError("network reservation 'stable' does not exist, and creating it requires a requested network"))
+						Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when claiming the reservation fails", func() {
+					BeforeEach(func() {
+						fakeReservations.ClaimReturns(errors.New("claimed"))
+					})
+
+					It("returns the error", func() {
+						Expect(networker.Network(logger, containerSpec, 42)).To(MatchError("claimed"))
+					})
+				})
+			})
+
+			Context("and the reservation exists", func() {
+				BeforeEach(func() {
+					containerSpec.Network = ""
+					fakeReservations.GetReturns(gardener.NetworkReservation{Network: "10.0.0.5/30"}, true, nil)
+				})
+
+				It("uses the reserved network without acquiring it, as the pool already holds it", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+					Expect(fakeReservations.GetArgsForCall(0)).To(Equal("stable"))
+					Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+
+					_, _, subnet, ip := fakeConfigCreator.CreateArgsForCall(0)
+					Expect(subnet.String()).To(Equal("10.0.0.4/30"))
+					Expect(ip.String()).To(Equal("10.0.0.5"))
+				})
+
+				Context("when the reserved network is requested explicitly", func() {
+					BeforeEach(func() {
+						containerSpec.Network = "10.0.0.5/30"
+					})
+
+					It("uses the reserved network", func() {
+						Expect(networker.Network(logger, containerSpec, 42)).To(Succeed())
+						Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+						_, _, _, ip := fakeConfigCreator.CreateArgsForCall(0)
+						Expect(ip.String()).To(Equal("10.0.0.5"))
+					})
+				})
+
+				Context("when a different network is requested", func() {
+					BeforeEach(func() {
+						containerSpec.Network = "10.0.0.9/30"
+					})
+
+					It("returns an error before acquiring a subnet", func() {
+						err := networker.Network(logger, containerSpec, 42)
+						Expect(err).To(MatchError("the requested network 10.0.0.9/30 does not match the network 10.0.0.5/30 held by reservation 'stable'"))
+						Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when another container holds the reservation", func() {
+					BeforeEach(func() {
+						fakeReservations.GetReturns(gardener.NetworkReservation{Network: "10.0.0.5/30", Handle: "other-handle"}, true, nil)
+					})
+
+					It("returns an error before acquiring a subnet", func() {
+						err := networker.Network(logger, containerSpec, 42)
+						Expect(err).To(MatchError("network reservation 'stable' is in use by container 'other-handle'"))
+						Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when reading the reservation fails", func() {
+				BeforeEach(func() {
+					fakeReservations.GetReturns(gardener.NetworkReservation{}, false, errors.New("no-store"))
+				})
+
+				It("returns the error", func() {
+					Expect(networker.Network(logger, containerSpec, 42)).To(MatchError("no-store"))
+				})
+			})
+		})
 	})
 
 	Describe("Capacity", func() {
@@ -281,6 +494,43 @@ var _ = Describe("Networker", func() {
 			})
 		})
 
+		Context("when the port forwarder is transient", func() {
+			var transientForwarder *fakes.FakeTransientPortForwarder
+
+			BeforeEach(func() {
+				transientForwarder = new(fakes.FakeTransientPortForwarder)
+				networker = kawasaki.New(
+					fakeSpecParser,
+					fakeSubnetPool,
+					fakeConfigCreator,
+					fakeConfigStore,
+					fakeConfigurer,
+					fakePortPool,
+					transientForwarder,
+					fakeFirewallOpener,
+					fakeQoSMarker,
+					map[string]string{"system": "EF"},
+					fakeInstanceIndex,
+					fakeStatser,
+					fakeReservations,
+					fakePortMappings,
+				)
+			})
+
+			It("stops forwarding the container's ports", func() {
+				Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+				Expect(transientForwarder.UnforwardCallCount()).To(Equal(1))
+				Expect(transientForwarder.UnforwardArgsForCall(0)).To(Equal("some-handle"))
+			})
+
+			Context("when unforwarding fails", func() {
+				It("returns the error", func() {
+					transientForwarder.UnforwardReturns(errors.New("boom"))
+					Expect(networker.Destroy(logger, "some-handle")).To(MatchError("boom"))
+				})
+			})
+		})
+
 		It("releases the subnet", func() {
 			Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
 
@@ -332,6 +582,19 @@ var _ = Describe("Networker", func() {
 				config[gardener.MappedPortsKey] = `potato`
 				Expect(networker.Destroy(logger, "some-handle")).To(MatchError(ContainSubstring("invalid")))
 			})
+
+			It("forgets the recorded port mappings", func() {
+				Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+				Expect(fakePortMappings.ForgetCallCount()).To(Equal(1))
+				Expect(fakePortMappings.ForgetArgsForCall(0)).To(Equal("some-handle"))
+			})
+
+			Context("when forgetting the port mappings fails", func() {
+				It("returns the error", func() {
+					fakePortMappings.ForgetReturns(errors.New("unforgettable"))
+					Expect(networker.Destroy(logger, "some-handle")).To(MatchError("unforgettable"))
+				})
+			})
 		})
 
 		Describe("destroying network configuration", func() {
@@ -349,6 +612,103 @@ var _ = Describe("Networker", func() {
 				})
 			})
 
+			It("releases the instance ID", func() {
+				Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+				Expect(fakeInstanceIndex.ReleaseCallCount()).To(Equal(1))
+				Expect(fakeInstanceIndex.ReleaseArgsForCall(0)).To(Equal("some-handle"))
+			})
+
+			Context("when releasing the instance ID fails", func() {
+				It("returns the error", func() {
+					fakeInstanceIndex.ReleaseReturns(errors.New("unreleasable"))
+					Expect(networker.Destroy(logger, "some-handle")).To(MatchError("unreleasable"))
+				})
+			})
+
+			Context("when destroying the iptables rules fails", func() {
+				It("keeps the instance ID so that the chains can be swept later", func() {
+					fakeConfigurer.DestroyIPTablesRulesReturns(errors.New("boom"))
+					Expect(networker.Destroy(logger, "some-handle")).NotTo(Succeed())
+					Expect(fakeInstanceIndex.ReleaseCallCount()).To(Equal(0))
+				})
+			})
+
+			It("does not unmark traffic for containers without a QoS class", func() {
+				Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+				Expect(fakeQoSMarker.UnmarkCallCount()).To(Equal(0))
+			})
+
+			Context("when the container's traffic was marked", func() {
+				BeforeEach(func() {
+					config["kawasaki.qos-class"] = "EF"
+				})
+
+				It("unmarks the container's traffic", func() {
+					Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+					Expect(fakeQoSMarker.UnmarkCallCount()).To(Equal(1))
+					_, instanceId := fakeQoSMarker.UnmarkArgsForCall(0)
+					Expect(instanceId).To(Equal(networkConfig.IPTableInstance))
+				})
+
+				Context("when unmarking fails", func() {
+					It("returns the error", func() {
+						fakeQoSMarker.UnmarkReturns(errors.New("marked-for-life"))
+						Expect(networker.Destroy(logger, "some-handle")).To(MatchError("marked-for-life"))
+					})
+				})
+			})
+
+			Context("when the container holds a network reservation", func() {
+				BeforeEach(func() {
+					config["kawasaki.network-reservation"] = "stable"
+				})
+
+				It("gives up the reservation but keeps it", func() {
+					Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+					Expect(fakeReservations.UnclaimCallCount()).To(Equal(1))
+					name, handle := fakeReservations.UnclaimArgsForCall(0)
+					Expect(name).To(Equal("stable"))
+					Expect(handle).To(Equal("some-handle"))
+					Expect(fakeReservations.ReleaseCallCount()).To(Equal(0))
+				})
+
+				Context("and the reservation holds the container's IP", func() {
+					BeforeEach(func() {
+						fakeReservations.GetReturns(gardener.NetworkReservation{Network: "123.123.123.12/24", Handle: "some-handle"}, true, nil)
+					})
+
+					It("keeps the IP out of the subnet pool", func() {
+						Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+						Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("and the reservation was never created", func() {
+					It("returns the IP to the subnet pool", func() {
+						Expect(networker.Destroy(logger, "some-handle")).To(Succeed())
+						Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when reading the reservation fails", func() {
+					BeforeEach(func() {
+						fakeReservations.GetReturns(gardener.NetworkReservation{}, false, errors.New("no-store"))
+					})
+
+					It("returns the error without releasing the IP", func() {
+						Expect(networker.Destroy(logger, "some-handle")).To(MatchError("no-store"))
+						Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when giving up the reservation fails", func() {
+					It("returns the error", func() {
+						fakeReservations.UnclaimReturns(errors.New("stuck"))
+						Expect(networker.Destroy(logger, "some-handle")).To(MatchError("stuck"))
+					})
+				})
+			})
+
 			Context("when the subnet pool has allocated an IP from the subnet", func() {
 				BeforeEach(func() {
 					fakeSubnetPool.RunIfFreeStub = func(_ *net.IPNet, _ func() error) error {
@@ -391,6 +751,241 @@ var _ = Describe("Networker", func() {
 		})
 	})
 
+	Describe("sweeping orphaned chains", func() {
+		var cleaner gardener.OrphanCleaner
+
+		BeforeEach(func() {
+			var ok bool
+			cleaner, ok = networker.(gardener.OrphanCleaner)
+			Expect(ok).To(BeTrue())
+
+			fakeInstanceIndex.InstancesReturns(map[string]string{
+				"live-handle":     "live-id",
+				"orphaned-handle": "orphaned-id",
+			}, nil)
+		})
+
+		It("finds the indexed handles which no longer have a container", func() {
+			Expect(cleaner.Orphans(logger, []string{"live-handle"})).To(ConsistOf("orphaned-handle"))
+		})
+
+		Context("when listing the index fails", func() {
+			It("returns the error", func() {
+				fakeInstanceIndex.InstancesReturns(nil, errors.New("no-index"))
+				_, err := cleaner.Orphans(logger, []string{"live-handle"})
+				Expect(err).To(MatchError("no-index"))
+			})
+		})
+
+		It("destroys the orphan's chains and releases its instance ID", func() {
+			Expect(cleaner.CleanOrphan(logger, "orphaned-handle")).To(Succeed())
+
+			Expect(fakeConfigurer.DestroyIPTablesRulesCallCount()).To(Equal(1))
+			_, cfg := fakeConfigurer.DestroyIPTablesRulesArgsForCall(0)
+			Expect(cfg.IPTableInstance).To(Equal("orphaned-id"))
+
+			Expect(fakeQoSMarker.UnmarkCallCount()).To(Equal(1))
+			_, instanceId := fakeQoSMarker.UnmarkArgsForCall(0)
+			Expect(instanceId).To(Equal("orphaned-id"))
+
+			Expect(fakeInstanceIndex.ReleaseCallCount()).To(Equal(1))
+			Expect(fakeInstanceIndex.ReleaseArgsForCall(0)).To(Equal("orphaned-handle"))
+		})
+
+		It("gives up the network reservations the orphan held", func() {
+			fakeReservations.AllReturns(map[string]gardener.NetworkReservation{
+				"stable": {Network: "10.0.0.5/30", Handle: "orphaned-handle"},
+				"other":  {Network: "10.0.0.9/30", Handle: "live-handle"},
+			}, nil)
+
+			Expect(cleaner.CleanOrphan(logger, "orphaned-handle")).To(Succeed())
+			Expect(fakeReservations.UnclaimCallCount()).To(Equal(1))
+			name, handle := fakeReservations.UnclaimArgsForCall(0)
+			Expect(name).To(Equal("stable"))
+			Expect(handle).To(Equal("orphaned-handle"))
+		})
+
+		It("does nothing for handles which are not in the index", func() {
+			Expect(cleaner.CleanOrphan(logger, "unknown-handle")).To(Succeed())
+			Expect(fakeConfigurer.DestroyIPTablesRulesCallCount()).To(Equal(0))
+			Expect(fakeInstanceIndex.ReleaseCallCount()).To(Equal(0))
+		})
+
+		Context("when destroying the chains fails", func() {
+			BeforeEach(func() {
+				fakeConfigurer.DestroyIPTablesRulesReturns(errors.New("boom"))
+			})
+
+			It("returns the error and keeps the instance ID", func() {
+				Expect(cleaner.CleanOrphan(logger, "orphaned-handle")).To(MatchError("boom"))
+				Expect(fakeInstanceIndex.ReleaseCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("releasing a recorded network", func() {
+		var (
+			releaser gardener.RecordedNetworkReleaser
+			recorded gardener.ContainerResources
+		)
+
+		BeforeEach(func() {
+			var ok bool
+			releaser, ok = networker.(gardener.RecordedNetworkReleaser)
+			Expect(ok).To(BeTrue())
+
+			fakeInstanceIndex.InstancesReturns(map[string]string{"lost-handle": "lost-id"}, nil)
+
+			recorded = gardener.ContainerResources{
+				ContainerIP: "10.0.0.2",
+				Subnet:      "10.0.0.0/30",
+				HostPorts:   []uint32{61001, 61002},
+			}
+		})
+
+		It("releases the recorded IP from the subnet pool", func() {
+			Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(Succeed())
+
+			Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(1))
+			subnet, ip := fakeSubnetPool.ReleaseArgsForCall(0)
+			Expect(subnet.String()).To(Equal("10.0.0.0/30"))
+			Expect(ip.String()).To(Equal("10.0.0.2"))
+		})
+
+		It("releases the recorded host ports", func() {
+			Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(Succeed())
+
+			Expect(fakePortPool.ReleaseCallCount()).To(Equal(2))
+			Expect(fakePortPool.ReleaseArgsForCall(0)).To(BeEquivalentTo(61001))
+			Expect(fakePortPool.ReleaseArgsForCall(1)).To(BeEquivalentTo(61002))
+		})
+
+		It("destroys the chains and releases the instance ID", func() {
+			Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(Succeed())
+
+			_, cfg := fakeConfigurer.DestroyIPTablesRulesArgsForCall(0)
+			Expect(cfg.IPTableInstance).To(Equal("lost-id"))
+			Expect(fakeInstanceIndex.ReleaseArgsForCall(0)).To(Equal("lost-handle"))
+		})
+
+		It("keeps IPs which a network reservation holds", func() {
+			fakeReservations.AllReturns(map[string]gardener.NetworkReservation{
+				"stable": {Network: "10.0.0.2/30", Handle: "lost-handle"},
+			}, nil)
+
+			Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(Succeed())
+			Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(0))
+		})
+
+		It("succeeds when the IP was already released", func() {
+			fakeSubnetPool.ReleaseReturns(subnets.ErrReleasedUnallocatedSubnet)
+			Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(Succeed())
+		})
+
+		Context("when releasing the IP fails", func() {
+			BeforeEach(func() {
+				fakeSubnetPool.ReleaseReturns(errors.New("pool broken"))
+			})
+
+			It("returns the error", func() {
+				Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(MatchError("pool broken"))
+			})
+		})
+
+		Context("when the recorded subnet is malformed", func() {
+			It("returns an error", func() {
+				recorded.Subnet = "banana"
+				Expect(releaser.ReleaseRecorded(logger, "lost-handle", recorded)).To(MatchError(ContainSubstring("parsing recorded subnet")))
+			})
+		})
+	})
+
+	Describe("PlanNetwork", func() {
+		var planner gardener.NetworkPlanner
+
+		BeforeEach(func() {
+			var ok bool
+			planner, ok = networker.(gardener.NetworkPlanner)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("parses the spec without acquiring a subnet", func() {
+			Expect(planner.PlanNetwork(logger, containerSpec)).To(Succeed())
+
+			Expect(fakeSpecParser.ParseCallCount()).To(Equal(1))
+			_, spec := fakeSpecParser.ParseArgsForCall(0)
+			Expect(spec).To(Equal("1.2.3.4/30"))
+			Expect(fakeSubnetPool.AcquireCallCount()).To(Equal(0))
+			Expect(fakeConfigStore.SetCallCount()).To(Equal(0))
+		})
+
+		It("returns an error if the spec can't be parsed", func() {
+			fakeSpecParser.ParseReturns(nil, nil, errors.New("no parsey"))
+			Expect(planner.PlanNetwork(logger, containerSpec)).To(MatchError("no parsey"))
+		})
+
+		It("returns an error if the QoS class is unknown", func() {
+			containerSpec.Properties = garden.Properties{gardener.NetworkQoSClassKey: "first-class"}
+			Expect(planner.PlanNetwork(logger, containerSpec)).To(MatchError("unknown network qos class: first-class"))
+		})
+
+		It("returns an error if the network reservation is in use by another container", func() {
+			fakeReservations.GetReturns(gardener.NetworkReservation{Network: "1.2.3.4/30", Handle: "other-handle"}, true, nil)
+			containerSpec.Properties = garden.Properties{gardener.NetworkReservationKey: "stable"}
+			Expect(planner.PlanNetwork(logger, containerSpec)).To(MatchError("network reservation 'stable' is in use by container 'other-handle'"))
+		})
+	})
+
+	Describe("network reservations", func() {
+		var reserver gardener.NetworkReserver
+
+		BeforeEach(func() {
+			var ok bool
+			reserver, ok = networker.(gardener.NetworkReserver)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("lists the reservations in the store", func() {
+			fakeReservations.AllReturns(map[string]gardener.NetworkReservation{
+				"stable": {Network: "10.0.0.5/30"},
+			}, nil)
+
+			Expect(reserver.Reservations(logger)).To(Equal(map[string]gardener.NetworkReservation{
+				"stable": {Network: "10.0.0.5/30"},
+			}))
+		})
+
+		It("releases reservations from the store", func() {
+			Expect(reserver.ReleaseReservation(logger, "stable")).To(Succeed())
+			Expect(fakeReservations.ReleaseArgsForCall(0)).To(Equal("stable"))
+		})
+
+		It("returns the IP of released reservations to the subnet pool", func() {
+			fakeReservations.GetReturns(gardener.NetworkReservation{Network: "10.0.0.5/30"}, true, nil)
+
+			Expect(reserver.ReleaseReservation(logger, "stable")).To(Succeed())
+			Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(1))
+			subnet, ip := fakeSubnetPool.ReleaseArgsForCall(0)
+			Expect(subnet.String()).To(Equal("10.0.0.4/30"))
+			Expect(ip.String()).To(Equal("10.0.0.5"))
+		})
+
+		It("keeps the IP in the pool when the reservation cannot be released", func() {
+			fakeReservations.GetReturns(gardener.NetworkReservation{Network: "10.0.0.5/30", Handle: "some-handle"}, true, nil)
+			fakeReservations.ReleaseReturns(errors.New("in use"))
+
+			Expect(reserver.ReleaseReservation(logger, "stable")).To(MatchError("in use"))
+			Expect(fakeSubnetPool.ReleaseCallCount()).To(Equal(0))
+		})
+
+		Context("when releasing the reservation fails", func() {
+			It("returns the error", func() {
+				fakeReservations.ReleaseReturns(errors.New("in use"))
+				Expect(reserver.ReleaseReservation(logger, "stable")).To(MatchError("in use"))
+			})
+		})
+	})
+
 	Describe("NetOut", func() {
 		It("delegates to FirewallOpener", func() {
 			rule := garden.NetOutRule{Protocol: garden.ProtocolICMP}
@@ -405,6 +1000,47 @@ var _ = Describe("Networker", func() {
 		})
 	})
 
+	Describe("Stats", func() {
+		BeforeEach(func() {
+			fakeStatser.StatisticsReturns(gardener.ContainerNetworkStats{
+				RxBytes:   1,
+				TxBytes:   2,
+				RxPackets: 3,
+				TxPackets: 4,
+				RxDropped: 5,
+				TxDropped: 6,
+			}, nil)
+		})
+
+		It("reads the counters of the host interface", func() {
+			_, err := networker.Stats(lagertest.NewTestLogger(""), "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStatser.StatisticsCallCount()).To(Equal(1))
+			Expect(fakeStatser.StatisticsArgsForCall(0)).To(Equal(networkConfig.HostIntf))
+		})
+
+		It("reports the counters from the point of view of the container", func() {
+			Expect(networker.Stats(lagertest.NewTestLogger(""), "some-handle")).To(Equal(gardener.ContainerNetworkStats{
+				RxBytes:   2,
+				TxBytes:   1,
+				RxPackets: 4,
+				TxPackets: 3,
+				RxDropped: 6,
+				TxDropped: 5,
+			}))
+		})
+
+		Context("when reading the counters fails", func() {
+			It("returns the error", func() {
+				fakeStatser.StatisticsReturns(gardener.ContainerNetworkStats{}, errors.New("no-such-interface"))
+
+				_, err := networker.Stats(lagertest.NewTestLogger(""), "some-handle")
+				Expect(err).To(MatchError("no-such-interface"))
+			})
+		})
+	})
+
 	Describe("BulkNetOut", func() {
 		It("delegates to FirewallOpener", func() {
 			rules := []garden.NetOutRule{
@@ -528,6 +1164,28 @@ var _ = Describe("Networker", func() {
 			Expect(actualValue).To(Equal(`[{"HostPort":123,"ContainerPort":456},{"HostPort":654,"ContainerPort":987}]`))
 		})
 
+		It("records the port mapping with the external IP", func() {
+			_, _, err := networker.NetIn(logger, handle, externalPort, containerPort)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePortMappings.AddCallCount()).To(Equal(1))
+			actualHandle, externalIP, mapping := fakePortMappings.AddArgsForCall(0)
+			Expect(actualHandle).To(Equal(handle))
+			Expect(externalIP).To(Equal("128.128.90.90"))
+			Expect(mapping).To(Equal(garden.PortMapping{HostPort: 123, ContainerPort: 456}))
+		})
+
+		Context("when recording the port mapping fails", func() {
+			BeforeEach(func() {
+				fakePortMappings.AddReturns(errors.New("no-record"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := networker.NetIn(logger, handle, externalPort, containerPort)
+				Expect(err).To(MatchError("no-record"))
+			})
+		})
+
 		Context("when the PortForwarder fails", func() {
 			var err error
 
@@ -566,6 +1224,80 @@ var _ = Describe("Networker", func() {
 			Expect(calledContainerIP.String()).To(Equal("123.123.123.12"))
 		})
 
+		It("does not forward the ports again", func() {
+			Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+			Expect(fakePortForwarder.ForwardCallCount()).To(Equal(0))
+		})
+
+		Context("when the container holds a network reservation for its IP", func() {
+			BeforeEach(func() {
+				config["kawasaki.network-reservation"] = "stable"
+				fakeReservations.GetReturns(gardener.NetworkReservation{Network: "123.123.123.12/24", Handle: "some-handle"}, true, nil)
+				fakeSubnetPool.RemoveReturns(subnets.ErrOverlapsExistingSubnet)
+			})
+
+			It("succeeds, as the pool already holds the reserved IP", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+			})
+		})
+
+		Context("when the IP is already in the pool and not reserved", func() {
+			BeforeEach(func() {
+				fakeSubnetPool.RemoveReturns(subnets.ErrOverlapsExistingSubnet)
+			})
+
+			It("returns the error", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(MatchError(ContainSubstring("subnet pool removing some-handle")))
+			})
+		})
+
+		Context("when the port forwarder is transient", func() {
+			var transientForwarder *fakes.FakeTransientPortForwarder
+
+			BeforeEach(func() {
+				transientForwarder = new(fakes.FakeTransientPortForwarder)
+				networker = kawasaki.New(
+					fakeSpecParser,
+					fakeSubnetPool,
+					fakeConfigCreator,
+					fakeConfigStore,
+					fakeConfigurer,
+					fakePortPool,
+					transientForwarder,
+					fakeFirewallOpener,
+					fakeQoSMarker,
+					map[string]string{"system": "EF"},
+					fakeInstanceIndex,
+					fakeStatser,
+					fakeReservations,
+					fakePortMappings,
+				)
+			})
+
+			It("forwards the mapped ports again", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+				Expect(transientForwarder.ForwardCallCount()).To(Equal(1))
+				Expect(transientForwarder.ForwardArgsForCall(0)).To(Equal(kawasaki.PortForwarderSpec{
+					InstanceID:  "table",
+					Handle:      "some-handle",
+					FromPort:    60000,
+					ToPort:      8080,
+					ContainerIP: networkConfig.ContainerIP,
+					ExternalIP:  networkConfig.ExternalIP,
+				}))
+			})
+
+			Context("when forwarding fails", func() {
+				BeforeEach(func() {
+					transientForwarder.ForwardReturns(errors.New("address in use"))
+				})
+
+				It("returns an appropriate error", func() {
+					Expect(networker.Restore(logger, "some-handle")).To(MatchError("forwarding port 60000 of some-handle: address in use"))
+				})
+			})
+		})
+
 		It("removes the port from port mapping list", func() {
 			Expect(networker.Restore(logger, "some-handle")).To(Succeed())
 			Expect(fakePortPool.RemoveCallCount()).To(Equal(1))
@@ -599,6 +1331,43 @@ var _ = Describe("Networker", func() {
 			})
 		})
 
+		Context("when the port mappings were recorded in the store", func() {
+			BeforeEach(func() {
+				delete(config, gardener.MappedPortsKey)
+				delete(config, gardener.ExternalIPKey)
+				fakeConfigStore.SetStub = func(handle, name, value string) {
+					config[name] = value
+				}
+				fakePortMappings.GetReturns(kawasaki.ContainerPortMappings{
+					ExternalIP: "10.0.0.1",
+					Mappings:   []garden.PortMapping{{HostPort: 61001, ContainerPort: 8080}},
+				}, true, nil)
+			})
+
+			It("restores the mapped ports and external IP properties", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+				Expect(fakePortMappings.GetArgsForCall(0)).To(Equal("some-handle"))
+				Expect(config[gardener.MappedPortsKey]).To(Equal(`[{"HostPort":61001,"ContainerPort":8080}]`))
+				Expect(config[gardener.ExternalIPKey]).To(Equal("10.0.0.1"))
+			})
+
+			It("removes the recorded ports from the port pool", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+				Expect(fakePortPool.RemoveCallCount()).To(Equal(1))
+				Expect(fakePortPool.RemoveArgsForCall(0)).To(BeEquivalentTo(61001))
+			})
+		})
+
+		Context("when reading the port mapping store fails", func() {
+			BeforeEach(func() {
+				fakePortMappings.GetReturns(kawasaki.ContainerPortMappings{}, false, errors.New("no-store"))
+			})
+
+			It("returns an appropriate error", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(MatchError("recovering port mappings some-handle: no-store"))
+			})
+		})
+
 		Context("when the port mapping json can't be marshaled", func() {
 			BeforeEach(func() {
 				config[gardener.MappedPortsKey] = "not-json"
@@ -0,0 +1,113 @@
+package kawasaki
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// ContainerPortMappings are the NetIn mappings of a container, and the
+// external IP on which the mapped ports can be reached
+type ContainerPortMappings struct {
+	ExternalIP string               `json:"external_ip"`
+	Mappings   []garden.PortMapping `json:"mappings"`
+}
+
+// FilePortMappingStore records the NetIn mappings of each container in a JSON
+// file as they are made, so that they can be reported after a restart even if
+// the properties were not saved
+type FilePortMappingStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFilePortMappingStore(path string) *FilePortMappingStore {
+	return &FilePortMappingStore{path: path}
+}
+
+// Add records a NetIn mapping of the container with the given handle
+func (s *FilePortMappingStore) Add(handle, externalIP string, mapping garden.PortMapping) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	mappings := all[handle]
+	mappings.ExternalIP = externalIP
+	mappings.Mappings = append(mappings.Mappings, mapping)
+	all[handle] = mappings
+
+	return s.save(all)
+}
+
+// Get returns the NetIn mappings of the container with the given handle, if
+// any were recorded
+func (s *FilePortMappingStore) Get(handle string) (ContainerPortMappings, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return ContainerPortMappings{}, false, err
+	}
+
+	mappings, ok := all[handle]
+	return mappings, ok, nil
+}
+
+// Forget idempotently deletes the NetIn mappings of the container with the
+// given handle
+func (s *FilePortMappingStore) Forget(handle string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := all[handle]; !ok {
+		return nil
+	}
+
+	delete(all, handle)
+	return s.save(all)
+}
+
+func (s *FilePortMappingStore) load() (map[string]ContainerPortMappings, error) {
+	all := map[string]ContainerPortMappings{}
+
+	contents, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading port mappings: %s", err)
+	}
+
+	if err := json.Unmarshal(contents, &all); err != nil {
+		return nil, fmt.Errorf("parsing port mappings: %s", err)
+	}
+
+	return all, nil
+}
+
+func (s *FilePortMappingStore) save(all map[string]ContainerPortMappings) error {
+	contents, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(s.path, contents); err != nil {
+		return fmt.Errorf("writing port mappings: %s", err)
+	}
+
+	return nil
+}
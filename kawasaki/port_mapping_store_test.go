@@ -0,0 +1,79 @@
+package kawasaki_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/kawasaki"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilePortMappingStore", func() {
+	var (
+		tmpDir    string
+		storePath string
+		store     *kawasaki.FilePortMappingStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "port-mapping-store")
+		Expect(err).NotTo(HaveOccurred())
+
+		storePath = filepath.Join(tmpDir, "store", "port-mappings.json")
+		store = kawasaki.NewFilePortMappingStore(storePath)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("records the mappings of each container with its external IP", func() {
+		Expect(store.Add("some-handle", "10.0.0.1", garden.PortMapping{HostPort: 61001, ContainerPort: 8080})).To(Succeed())
+		Expect(store.Add("some-handle", "10.0.0.1", garden.PortMapping{HostPort: 61002, ContainerPort: 8081})).To(Succeed())
+		Expect(store.Add("other-handle", "10.0.0.1", garden.PortMapping{HostPort: 61003, ContainerPort: 8080})).To(Succeed())
+
+		mappings, ok, err := kawasaki.NewFilePortMappingStore(storePath).Get("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(mappings).To(Equal(kawasaki.ContainerPortMappings{
+			ExternalIP: "10.0.0.1",
+			Mappings: []garden.PortMapping{
+				{HostPort: 61001, ContainerPort: 8080},
+				{HostPort: 61002, ContainerPort: 8081},
+			},
+		}))
+	})
+
+	It("reports containers without recorded mappings", func() {
+		_, ok, err := store.Get("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("forgets the mappings of a container", func() {
+		Expect(store.Add("some-handle", "10.0.0.1", garden.PortMapping{HostPort: 61001, ContainerPort: 8080})).To(Succeed())
+		Expect(store.Forget("some-handle")).To(Succeed())
+		Expect(store.Forget("some-handle")).To(Succeed())
+
+		_, ok, err := store.Get("some-handle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("when the store file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(storePath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(storePath, []byte("{not-json"), 0600)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, _, err := store.Get("some-handle")
+			Expect(err).To(MatchError(ContainSubstring("parsing port mappings")))
+		})
+	})
+})
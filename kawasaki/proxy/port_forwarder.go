@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/lager"
+)
+
+// PortForwarder forwards NetIn ports by proxying connections to the container
+// in userspace, rather than with DNAT, so that traffic to the container comes
+// from the host. If ProxyProtocol is set, each connection starts with a PROXY
+// protocol (v1) header so that the container can still learn the client's
+// address.
+type PortForwarder struct {
+	logger        lager.Logger
+	proxyProtocol bool
+
+	mu        sync.Mutex
+	listeners map[string][]net.Listener
+}
+
+func NewPortForwarder(logger lager.Logger, proxyProtocol bool) *PortForwarder {
+	return &PortForwarder{
+		logger:        logger,
+		proxyProtocol: proxyProtocol,
+		listeners:     map[string][]net.Listener{},
+	}
+}
+
+func (p *PortForwarder) Forward(spec kawasaki.PortForwarderSpec) error {
+	listenAddr := net.JoinHostPort(spec.ExternalIP.String(), strconv.Itoa(int(spec.FromPort)))
+	targetAddr := net.JoinHostPort(spec.ContainerIP.String(), strconv.Itoa(int(spec.ToPort)))
+
+	log := p.logger.Session("forward", lager.Data{"handle": spec.Handle, "listen": listenAddr, "target": targetAddr})
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		log.Error("listen-failed", err)
+		return fmt.Errorf("proxying port %d: %s", spec.FromPort, err)
+	}
+
+	p.mu.Lock()
+	p.listeners[spec.Handle] = append(p.listeners[spec.Handle], listener)
+	p.mu.Unlock()
+
+	go p.serve(log, listener, targetAddr)
+
+	return nil
+}
+
+// Unforward stops proxying the ports of the container. Connections which are
+// already proxied end when the container goes away.
+func (p *PortForwarder) Unforward(handle string) error {
+	p.mu.Lock()
+	listeners := p.listeners[handle]
+	delete(p.listeners, handle)
+	p.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener.Close()
+	}
+
+	return nil
+}
+
+func (p *PortForwarder) serve(log lager.Logger, listener net.Listener, targetAddr string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Debug("stopped-accepting", lager.Data{"reason": err.Error()})
+			return
+		}
+
+		go p.proxy(log, conn, targetAddr)
+	}
+}
+
+func (p *PortForwarder) proxy(log lager.Logger, client net.Conn, targetAddr string) {
+	defer client.Close()
+
+	upstream, err := net.Dial("tcp", targetAddr)
+	if err != nil {
+		log.Error("dial-container-failed", err)
+		return
+	}
+	defer upstream.Close()
+
+	if p.proxyProtocol {
+		if _, err := io.WriteString(upstream, proxyHeader(client.RemoteAddr(), client.LocalAddr())); err != nil {
+			log.Error("write-proxy-header-failed", err)
+			return
+		}
+	}
+
+	done := make(chan struct{}, 2)
+	go pipe(upstream, client, done)
+	go pipe(client, upstream, done)
+	<-done
+	<-done
+}
+
+// pipe copies from src to dst until src is done, then closes dst for writing
+// so that the other end sees EOF while the reply can still be read
+func pipe(dst, src net.Conn, done chan<- struct{}) {
+	io.Copy(dst, src)
+	if tcpConn, ok := dst.(*net.TCPConn); ok {
+		tcpConn.CloseWrite()
+	}
+	done <- struct{}{}
+}
+
+// proxyHeader returns the PROXY protocol (v1) header of a connection from
+// client to local
+func proxyHeader(client, local net.Addr) string {
+	clientAddr, clientOK := client.(*net.TCPAddr)
+	localAddr, localOK := local.(*net.TCPAddr)
+	if !clientOK || !localOK {
+		return "PROXY UNKNOWN\r\n"
+	}
+
+	family := "TCP4"
+	if clientAddr.IP.To4() == nil {
+		family = "TCP6"
+	}
+
+	return fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, clientAddr.IP, localAddr.IP, clientAddr.Port, localAddr.Port)
+}
@@ -0,0 +1,119 @@
+package proxy_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/guardian/kawasaki/proxy"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PortForwarder", func() {
+	var (
+		container     net.Listener
+		received      chan string
+		proxyProtocol bool
+		forwarder     *proxy.PortForwarder
+		spec          kawasaki.PortForwarderSpec
+	)
+
+	freePort := func() uint32 {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+		return uint32(listener.Addr().(*net.TCPAddr).Port)
+	}
+
+	dialAndSend := func(message string) string {
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", spec.FromPort))
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+
+		_, err = conn.Write([]byte(message))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conn.(*net.TCPConn).CloseWrite()).To(Succeed())
+
+		reply, err := ioutil.ReadAll(conn)
+		Expect(err).NotTo(HaveOccurred())
+		return string(reply)
+	}
+
+	BeforeEach(func() {
+		var err error
+		container, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		received = make(chan string, 1)
+		go func() {
+			defer GinkgoRecover()
+
+			conn, err := container.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			contents, err := ioutil.ReadAll(conn)
+			Expect(err).NotTo(HaveOccurred())
+			received <- string(contents)
+
+			_, err = conn.Write([]byte("pong"))
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		proxyProtocol = false
+		spec = kawasaki.PortForwarderSpec{
+			Handle:      "some-handle",
+			FromPort:    freePort(),
+			ToPort:      uint32(container.Addr().(*net.TCPAddr).Port),
+			ContainerIP: net.ParseIP("127.0.0.1"),
+			ExternalIP:  net.ParseIP("127.0.0.1"),
+		}
+	})
+
+	JustBeforeEach(func() {
+		forwarder = proxy.NewPortForwarder(lagertest.NewTestLogger("test"), proxyProtocol)
+		Expect(forwarder.Forward(spec)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(forwarder.Unforward("some-handle")).To(Succeed())
+		container.Close()
+	})
+
+	It("proxies connections to the external port to the container", func() {
+		Expect(dialAndSend("ping")).To(Equal("pong"))
+		Eventually(received).Should(Receive(Equal("ping")))
+	})
+
+	Context("when the PROXY protocol is enabled", func() {
+		BeforeEach(func() {
+			proxyProtocol = true
+		})
+
+		It("tells the container the address of the client", func() {
+			Expect(dialAndSend("ping")).To(Equal("pong"))
+
+			var contents string
+			Eventually(received).Should(Receive(&contents))
+			Expect(contents).To(MatchRegexp(`^PROXY TCP4 127\.0\.0\.1 127\.0\.0\.1 \d+ %d\r\nping$`, spec.FromPort))
+		})
+	})
+
+	It("stops accepting connections once the container's ports are unforwarded", func() {
+		Expect(forwarder.Unforward("some-handle")).To(Succeed())
+
+		_, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", spec.FromPort))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("when the external port is in use", func() {
+		It("returns an error", func() {
+			Expect(forwarder.Forward(spec)).To(MatchError(ContainSubstring(fmt.Sprintf("proxying port %d", spec.FromPort))))
+		})
+	})
+})
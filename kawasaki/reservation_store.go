@@ -0,0 +1,176 @@
+package kawasaki
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/kawasaki/subnets"
+	"code.cloudfoundry.org/lager"
+)
+
+// FileReservationStore records network reservations in a JSON file, so that
+// they survive restarts and container recreates
+type FileReservationStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileReservationStore(path string) *FileReservationStore {
+	return &FileReservationStore{path: path}
+}
+
+// Get returns the named reservation, if it exists
+func (s *FileReservationStore) Get(name string) (gardener.NetworkReservation, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.load()
+	if err != nil {
+		return gardener.NetworkReservation{}, false, err
+	}
+
+	reservation, ok := reservations[name]
+	return reservation, ok, nil
+}
+
+// Claim records that the container with the given handle holds the named
+// reservation, creating the reservation for the network if it does not exist
+// yet. It fails if another container holds the reservation.
+func (s *FileReservationStore) Claim(name, handle, network string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := reservations[name]; ok && existing.Handle != "" && existing.Handle != handle {
+		return fmt.Errorf("network reservation '%s' is in use by container '%s'", name, existing.Handle)
+	}
+
+	reservations[name] = gardener.NetworkReservation{Network: network, Handle: handle}
+	return s.save(reservations)
+}
+
+// Unclaim idempotently records that the container with the given handle no
+// longer holds the named reservation. The reservation itself is kept.
+func (s *FileReservationStore) Unclaim(name, handle string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	reservation, ok := reservations[name]
+	if !ok || reservation.Handle != handle {
+		return nil
+	}
+
+	reservation.Handle = ""
+	reservations[name] = reservation
+	return s.save(reservations)
+}
+
+// Release deletes the named reservation, freeing its network for other
+// containers. It fails if a container holds the reservation.
+func (s *FileReservationStore) Release(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	reservation, ok := reservations[name]
+	if !ok {
+		return fmt.Errorf("network reservation '%s' does not exist", name)
+	}
+
+	if reservation.Handle != "" {
+		return fmt.Errorf("network reservation '%s' is in use by container '%s'", name, reservation.Handle)
+	}
+
+	delete(reservations, name)
+	return s.save(reservations)
+}
+
+// All returns every reservation in the store
+func (s *FileReservationStore) All() (map[string]gardener.NetworkReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+func (s *FileReservationStore) load() (map[string]gardener.NetworkReservation, error) {
+	reservations := map[string]gardener.NetworkReservation{}
+
+	contents, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return reservations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading network reservations: %s", err)
+	}
+
+	if err := json.Unmarshal(contents, &reservations); err != nil {
+		return nil, fmt.Errorf("parsing network reservations: %s", err)
+	}
+
+	return reservations, nil
+}
+
+func (s *FileReservationStore) save(reservations map[string]gardener.NetworkReservation) error {
+	contents, err := json.Marshal(reservations)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomically(s.path, contents); err != nil {
+		return fmt.Errorf("writing network reservations: %s", err)
+	}
+
+	return nil
+}
+
+// ReservedIPStarter takes the IP of every network reservation out of the
+// subnet pool at startup, so that while no container holds a reservation its
+// IP cannot be given to another container. It must run before containers are
+// restored.
+type ReservedIPStarter struct {
+	Logger       lager.Logger
+	SubnetPool   subnets.Pool
+	Reservations ReservationStore
+}
+
+func (s *ReservedIPStarter) Start() error {
+	log := s.Logger.Session("hold-reserved-ips")
+
+	reservations, err := s.Reservations.All()
+	if err != nil {
+		return err
+	}
+
+	for name, reservation := range reservations {
+		ip, subnet, err := net.ParseCIDR(reservation.Network)
+		if err != nil {
+			log.Error("parse-reserved-network-failed", err, lager.Data{"name": name})
+			continue
+		}
+
+		if err := s.SubnetPool.Remove(subnet, ip); err != nil && err != subnets.ErrOverlapsExistingSubnet {
+			return fmt.Errorf("holding the IP of network reservation '%s': %s", name, err)
+		}
+	}
+
+	return nil
+}
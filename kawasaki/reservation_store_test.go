@@ -0,0 +1,173 @@
+package kawasaki_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/kawasaki"
+	fakes "code.cloudfoundry.org/guardian/kawasaki/kawasakifakes"
+	"code.cloudfoundry.org/guardian/kawasaki/subnets"
+	"code.cloudfoundry.org/guardian/kawasaki/subnets/fake_subnet_pool"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileReservationStore", func() {
+	var (
+		tmpDir    string
+		storePath string
+		store     *kawasaki.FileReservationStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "reservation-store")
+		Expect(err).NotTo(HaveOccurred())
+
+		storePath = filepath.Join(tmpDir, "store", "reservations.json")
+		store = kawasaki.NewFileReservationStore(storePath)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("creates reservations when they are first claimed", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+
+		reservation, ok, err := store.Get("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(reservation).To(Equal(gardener.NetworkReservation{Network: "10.0.0.5/30", Handle: "some-handle"}))
+	})
+
+	It("reports reservations which do not exist", func() {
+		_, ok, err := store.Get("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("persists the reservations across instances", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+
+		reservations, err := kawasaki.NewFileReservationStore(storePath).All()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reservations).To(Equal(map[string]gardener.NetworkReservation{
+			"stable": {Network: "10.0.0.5/30", Handle: "some-handle"},
+		}))
+	})
+
+	It("keeps reservations when they are unclaimed", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Unclaim("stable", "some-handle")).To(Succeed())
+
+		reservation, ok, err := store.Get("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(reservation).To(Equal(gardener.NetworkReservation{Network: "10.0.0.5/30"}))
+	})
+
+	It("lets another container claim an unclaimed reservation", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Unclaim("stable", "some-handle")).To(Succeed())
+		Expect(store.Claim("stable", "new-handle", "10.0.0.5/30")).To(Succeed())
+	})
+
+	It("does not let another container claim a claimed reservation", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Claim("stable", "new-handle", "10.0.0.5/30")).To(
+			MatchError("network reservation 'stable' is in use by container 'some-handle'"),
+		)
+	})
+
+	It("ignores unclaims by containers which do not hold the reservation", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Unclaim("stable", "new-handle")).To(Succeed())
+		Expect(store.Unclaim("unknown", "some-handle")).To(Succeed())
+
+		reservation, _, err := store.Get("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reservation.Handle).To(Equal("some-handle"))
+	})
+
+	It("deletes released reservations", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Unclaim("stable", "some-handle")).To(Succeed())
+		Expect(store.Release("stable")).To(Succeed())
+
+		_, ok, err := store.Get("stable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("does not release claimed reservations", func() {
+		Expect(store.Claim("stable", "some-handle", "10.0.0.5/30")).To(Succeed())
+		Expect(store.Release("stable")).To(
+			MatchError("network reservation 'stable' is in use by container 'some-handle'"),
+		)
+	})
+
+	It("fails to release reservations which do not exist", func() {
+		Expect(store.Release("stable")).To(MatchError("network reservation 'stable' does not exist"))
+	})
+
+	Context("when the store file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(storePath), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(storePath, []byte("{not-json"), 0600)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, err := store.All()
+			Expect(err).To(MatchError(ContainSubstring("parsing network reservations")))
+		})
+	})
+})
+
+var _ = Describe("ReservedIPStarter", func() {
+	var (
+		pool         *fake_subnet_pool.FakePool
+		reservations *fakes.FakeReservationStore
+		starter      *kawasaki.ReservedIPStarter
+	)
+
+	BeforeEach(func() {
+		pool = new(fake_subnet_pool.FakePool)
+		reservations = new(fakes.FakeReservationStore)
+		reservations.AllReturns(map[string]gardener.NetworkReservation{
+			"stable": {Network: "10.0.0.5/30"},
+		}, nil)
+
+		starter = &kawasaki.ReservedIPStarter{
+			Logger:       lagertest.NewTestLogger("test"),
+			SubnetPool:   pool,
+			Reservations: reservations,
+		}
+	})
+
+	It("takes the reserved IPs out of the subnet pool", func() {
+		Expect(starter.Start()).To(Succeed())
+
+		Expect(pool.RemoveCallCount()).To(Equal(1))
+		subnet, ip := pool.RemoveArgsForCall(0)
+		Expect(subnet.String()).To(Equal("10.0.0.4/30"))
+		Expect(ip.String()).To(Equal("10.0.0.5"))
+	})
+
+	It("ignores IPs which the pool already holds", func() {
+		pool.RemoveReturns(subnets.ErrOverlapsExistingSubnet)
+		Expect(starter.Start()).To(Succeed())
+	})
+
+	Context("when listing the reservations fails", func() {
+		It("returns the error", func() {
+			reservations.AllReturns(nil, errors.New("no-store"))
+			Expect(starter.Start()).To(MatchError("no-store"))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package layerfetch_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLayerfetch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Layerfetch Suite")
+}
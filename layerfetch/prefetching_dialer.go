@@ -0,0 +1,268 @@
+package layerfetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/garden-shed/distclient"
+	"code.cloudfoundry.org/lager"
+	"github.com/docker/distribution/digest"
+)
+
+// PrefetchingDialer dials registry connections which download the layers of
+// an image concurrently. The fetcher asks for the layers of an image one at a
+// time, base first, and only for those which are not already in the graph.
+// When it asks for the first one, that layer is streamed straight to it and
+// the layers above it are downloaded and verified in the background, at most
+// Parallelism at a time, so that they are ready to be extracted as soon as
+// the fetcher gets to them.
+type PrefetchingDialer struct {
+	Dialer      distclient.Dialer
+	Parallelism int
+	TmpDir      string
+}
+
+const downloadPrefix = "layer-"
+
+// RemoveStaleDownloads removes the layers left in TmpDir by a previous run of
+// the server which stopped while it was prefetching. It must be called before
+// anything is dialled.
+func (d PrefetchingDialer) RemoveStaleDownloads() error {
+	stale, err := filepath.Glob(filepath.Join(d.TmpDir, downloadPrefix+"*"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d PrefetchingDialer) Dial(logger lager.Logger, host, repo string) (distclient.Conn, error) {
+	conn, err := d.Dialer.Dial(logger, host, repo)
+	if err != nil || d.Parallelism <= 0 {
+		return conn, err
+	}
+
+	return &prefetchingConn{
+		Conn:        conn,
+		logger:      logger.Session("prefetching-conn", lager.Data{"host": host, "repo": repo}),
+		parallelism: d.Parallelism,
+		tmpDir:      d.TmpDir,
+		downloads:   map[digest.Digest]*download{},
+	}, nil
+}
+
+type prefetchingConn struct {
+	distclient.Conn
+	logger      lager.Logger
+	parallelism int
+	tmpDir      string
+
+	// downloads holds the prefetched layers which have not yet been served.
+	// Once the conn is discarded nothing more is prefetched.
+	mu          sync.Mutex
+	layers      []digest.Digest
+	prefetching bool
+	discarded   bool
+	downloads   map[digest.Digest]*download
+}
+
+type download struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+func (c *prefetchingConn) GetManifest(logger lager.Logger, tag string) (*distclient.Manifest, error) {
+	manifest, err := c.Conn.GetManifest(logger, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.layers = nil
+	for _, layer := range manifest.Layers {
+		c.layers = append(c.layers, layer.BlobSum)
+	}
+
+	return manifest, nil
+}
+
+func (c *prefetchingConn) GetBlobReader(logger lager.Logger, dgst digest.Digest) (io.Reader, error) {
+	c.mu.Lock()
+	dl, ok := c.downloads[dgst]
+	delete(c.downloads, dgst)
+	if !ok && !c.prefetching && !c.discarded {
+		c.prefetching = true
+		c.prefetchAbove(logger.Session("prefetch"), dgst)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		blob, err := c.Conn.GetBlobReader(logger, dgst)
+		if err != nil {
+			c.discard(logger)
+		}
+
+		return blob, err
+	}
+
+	<-dl.done
+	if dl.err != nil {
+		c.discard(logger)
+		return nil, dl.err
+	}
+
+	f, err := os.Open(dl.path)
+	if err != nil {
+		c.discard(logger)
+		return nil, err
+	}
+
+	// the open file can still be read once it is unlinked, so nothing is left
+	// behind if the fetcher stops reading part way through
+	os.Remove(dl.path)
+	return &closeAtEOFReader{f: f}, nil
+}
+
+// Close removes the layers which were prefetched but not served
+func (c *prefetchingConn) Close() error {
+	c.discard(c.logger)
+	return nil
+}
+
+// discard stops prefetching and removes the layers which were prefetched but
+// not served, once they have finished downloading. The fetcher gives up on
+// the image when a layer fails, so they would never be asked for.
+func (c *prefetchingConn) discard(logger lager.Logger) {
+	c.mu.Lock()
+	outstanding := c.downloads
+	c.downloads = map[digest.Digest]*download{}
+	c.discarded = true
+	c.mu.Unlock()
+
+	for dgst, dl := range outstanding {
+		go func(dgst digest.Digest, dl *download) {
+			<-dl.done
+			if dl.path == "" {
+				return
+			}
+
+			if err := os.Remove(dl.path); err != nil {
+				logger.Error("removing-prefetched-layer-failed", err, lager.Data{"digest": dgst})
+			}
+		}(dgst, dl)
+	}
+}
+
+// prefetchAbove starts downloading the layers above dgst. It must be called
+// with the mutex held.
+func (c *prefetchingConn) prefetchAbove(logger lager.Logger, dgst digest.Digest) {
+	var above []digest.Digest
+	for i, layer := range c.layers {
+		if layer == dgst {
+			above = c.layers[i+1:]
+			break
+		}
+	}
+
+	slots := make(chan struct{}, c.parallelism)
+	for _, layer := range above {
+		if _, ok := c.downloads[layer]; ok {
+			continue
+		}
+
+		dl := &download{done: make(chan struct{})}
+		c.downloads[layer] = dl
+
+		go func(layer digest.Digest) {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			defer close(dl.done)
+
+			dl.path, dl.err = c.download(logger.Session("download", lager.Data{"digest": layer}), layer)
+		}(layer)
+	}
+}
+
+func (c *prefetchingConn) download(logger lager.Logger, dgst digest.Digest) (string, error) {
+	logger.Debug("start")
+	defer logger.Debug("finished")
+
+	blob, err := c.Conn.GetBlobReader(logger, dgst)
+	if err != nil {
+		logger.Error("get-blob-failed", err)
+		return "", err
+	}
+
+	f, err := ioutil.TempFile(c.tmpDir, downloadPrefix)
+	if err != nil {
+		return "", fmt.Errorf("creating layer download file: %s", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), blob); err != nil {
+		os.Remove(f.Name())
+		logger.Error("download-failed", err)
+		return "", fmt.Errorf("downloading layer %s: %s", dgst, err)
+	}
+
+	if err := verify(dgst, hex.EncodeToString(hash.Sum(nil))); err != nil {
+		os.Remove(f.Name())
+		logger.Error("verify-failed", err)
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// verify checks sha256 digests early, before the layer is extracted. The
+// fetcher still verifies every layer as it extracts it, whatever the
+// algorithm.
+func verify(dgst digest.Digest, sha256Hex string) error {
+	parts := strings.SplitN(string(dgst), ":", 2)
+	if len(parts) != 2 || parts[0] != "sha256" {
+		return nil
+	}
+
+	if parts[1] != sha256Hex {
+		return fmt.Errorf("layer %s has digest sha256:%s", dgst, sha256Hex)
+	}
+
+	return nil
+}
+
+// closeAtEOFReader closes its file once it has been read to the end
+type closeAtEOFReader struct {
+	f   *os.File
+	err error
+}
+
+func (r *closeAtEOFReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.f.Read(p)
+	if err != nil {
+		r.f.Close()
+		r.err = err
+	}
+
+	return n, err
+}
@@ -0,0 +1,259 @@
+package layerfetch_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden-shed/distclient"
+	"code.cloudfoundry.org/guardian/layerfetch"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/docker/distribution/digest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrefetchingDialer", func() {
+	var (
+		logger *lagertest.TestLogger
+		tmpDir string
+		layers []digest.Digest
+		conn   *fakeConn
+		dialer layerfetch.PrefetchingDialer
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "layerfetch")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		conn = &fakeConn{blobs: map[digest.Digest]string{}}
+
+		layers = nil
+		manifest := &distclient.Manifest{}
+		for i := 0; i < 5; i++ {
+			contents := fmt.Sprintf("layer-%d", i)
+			dgst := digest.Digest(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(contents))))
+			conn.blobs[dgst] = contents
+			layers = append(layers, dgst)
+			manifest.Layers = append(manifest.Layers, distclient.Layer{BlobSum: dgst})
+		}
+		conn.manifest = manifest
+
+		dialer = layerfetch.PrefetchingDialer{
+			Dialer:      &fakeDialer{conn: conn},
+			Parallelism: 2,
+			TmpDir:      tmpDir,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	dialAndGetManifest := func() distclient.Conn {
+		c, err := dialer.Dial(logger, "registry", "some/repo")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.GetManifest(logger, "latest")
+		Expect(err).NotTo(HaveOccurred())
+		return c
+	}
+
+	readBlob := func(c distclient.Conn, dgst digest.Digest) string {
+		r, err := c.GetBlobReader(logger, dgst)
+		Expect(err).NotTo(HaveOccurred())
+		contents, err := ioutil.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	It("streams the first layer requested and prefetches the layers above it", func() {
+		c := dialAndGetManifest()
+
+		Expect(readBlob(c, layers[2])).To(Equal("layer-2"))
+		Eventually(conn.Requested).Should(ConsistOf(layers[2], layers[3], layers[4]))
+	})
+
+	It("serves the prefetched layers", func() {
+		c := dialAndGetManifest()
+
+		for i := 1; i < 5; i++ {
+			Expect(readBlob(c, layers[i])).To(Equal(fmt.Sprintf("layer-%d", i)))
+		}
+		Expect(conn.Requested()).To(HaveLen(4))
+	})
+
+	It("does not leave the downloaded layers behind", func() {
+		c := dialAndGetManifest()
+		for i := 0; i < 5; i++ {
+			readBlob(c, layers[i])
+		}
+
+		files, err := ioutil.ReadDir(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("downloads no more than Parallelism layers at a time", func() {
+		conn.delay = 20 * time.Millisecond
+		c := dialAndGetManifest()
+
+		readBlob(c, layers[0])
+		Eventually(conn.Requested).Should(HaveLen(5))
+		for i := 1; i < 5; i++ {
+			readBlob(c, layers[i])
+		}
+
+		// two prefetches and the layer streamed to the fetcher
+		Expect(conn.MaxInFlight()).To(BeNumerically("<=", 3))
+	})
+
+	Context("when a prefetched layer does not match its digest", func() {
+		BeforeEach(func() {
+			conn.blobs[layers[3]] = "tampered"
+		})
+
+		It("returns an error when the layer is requested", func() {
+			c := dialAndGetManifest()
+			readBlob(c, layers[0])
+
+			_, err := c.GetBlobReader(logger, layers[3])
+			Expect(err).To(MatchError(ContainSubstring(string(layers[3]))))
+		})
+	})
+
+	Context("when a prefetched layer fails to download", func() {
+		BeforeEach(func() {
+			conn.blobs[layers[2]] = "tampered"
+		})
+
+		It("removes the other prefetched layers, which will not be asked for", func() {
+			c := dialAndGetManifest()
+			readBlob(c, layers[0])
+			readBlob(c, layers[1])
+
+			_, err := c.GetBlobReader(logger, layers[2])
+			Expect(err).To(HaveOccurred())
+
+			Eventually(func() ([]os.FileInfo, error) { return ioutil.ReadDir(tmpDir) }).Should(BeEmpty())
+		})
+	})
+
+	Context("when the layer streamed to the fetcher fails", func() {
+		BeforeEach(func() {
+			conn.failures = map[digest.Digest]error{layers[0]: errors.New("connection reset")}
+		})
+
+		It("does not leave the prefetched layers behind", func() {
+			c := dialAndGetManifest()
+
+			_, err := c.GetBlobReader(logger, layers[0])
+			Expect(err).To(MatchError("connection reset"))
+
+			Eventually(func() ([]os.FileInfo, error) { return ioutil.ReadDir(tmpDir) }).Should(BeEmpty())
+			Consistently(func() ([]os.FileInfo, error) { return ioutil.ReadDir(tmpDir) }).Should(BeEmpty())
+		})
+	})
+
+	It("removes the layers which were prefetched but not served when the conn is closed", func() {
+		c := dialAndGetManifest()
+		readBlob(c, layers[0])
+		Eventually(conn.Requested).Should(HaveLen(5))
+
+		Expect(c.(io.Closer).Close()).To(Succeed())
+		Eventually(func() ([]os.FileInfo, error) { return ioutil.ReadDir(tmpDir) }).Should(BeEmpty())
+	})
+
+	Describe("RemoveStaleDownloads", func() {
+		It("removes the layers left behind by a previous run", func() {
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "layer-123"), []byte("stale"), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, "something-else"), []byte("other"), 0600)).To(Succeed())
+
+			Expect(dialer.RemoveStaleDownloads()).To(Succeed())
+
+			Expect(filepath.Join(tmpDir, "layer-123")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(tmpDir, "something-else")).To(BeAnExistingFile())
+		})
+	})
+
+	Context("when Parallelism is zero", func() {
+		BeforeEach(func() {
+			dialer.Parallelism = 0
+		})
+
+		It("does not prefetch", func() {
+			c := dialAndGetManifest()
+			readBlob(c, layers[0])
+
+			Consistently(conn.Requested).Should(HaveLen(1))
+		})
+	})
+})
+
+type fakeDialer struct {
+	conn *fakeConn
+}
+
+func (d *fakeDialer) Dial(logger lager.Logger, host, repo string) (distclient.Conn, error) {
+	return d.conn, nil
+}
+
+type fakeConn struct {
+	manifest *distclient.Manifest
+	blobs    map[digest.Digest]string
+	failures map[digest.Digest]error
+	delay    time.Duration
+
+	mu          sync.Mutex
+	requested   []digest.Digest
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *fakeConn) GetManifest(logger lager.Logger, tag string) (*distclient.Manifest, error) {
+	return c.manifest, nil
+}
+
+func (c *fakeConn) GetBlobReader(logger lager.Logger, dgst digest.Digest) (io.Reader, error) {
+	c.mu.Lock()
+	c.requested = append(c.requested, dgst)
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if err := c.failures[dgst]; err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(c.blobs[dgst]), nil
+}
+
+func (c *fakeConn) Requested() []digest.Digest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]digest.Digest{}, c.requested...)
+}
+
+func (c *fakeConn) MaxInFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxInFlight
+}
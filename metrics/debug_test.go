@@ -27,7 +27,7 @@ var _ = Describe("Debug", func() {
 		}
 
 		sink := lager.NewReconfigurableSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG), lager.DEBUG)
-		serverProc, err = metrics.StartDebugServer("127.0.0.1:5123", sink, testMetrics)
+		serverProc, err = metrics.StartDebugServer("127.0.0.1:5123", sink, testMetrics, nil)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -46,3 +46,35 @@ var _ = Describe("Debug", func() {
 		Expect(expvar.Get("metric2").String()).To(Equal("12"))
 	})
 })
+
+var _ = Describe("Debug handlers", func() {
+	Context("when handlers are configured", func() {
+		var handlerServerProc ifrit.Process
+
+		BeforeEach(func() {
+			var err error
+
+			testHandlers := map[string]http.Handler{
+				"/debug/potato": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusTeapot)
+				}),
+			}
+
+			sink := lager.NewReconfigurableSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG), lager.DEBUG)
+			handlerServerProc, err = metrics.StartDebugServer("127.0.0.1:5124", sink, nil, testHandlers)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			handlerServerProc.Signal(os.Kill)
+		})
+
+		It("should serve them", func() {
+			resp, err := http.Get("http://127.0.0.1:5124/debug/potato")
+			Expect(err).ToNot(HaveOccurred())
+
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+		})
+	})
+})
@@ -8,19 +8,24 @@ import (
 	"runtime"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/gosigar"
 )
 
 type MetricsProvider struct {
-	backingStoresPath string
-	depotPath         string
-	logger            lager.Logger
+	backingStoresPath         string
+	depotPath                 string
+	graphPath                 string
+	diskUsageWarningThreshold int
+	logger                    lager.Logger
 }
 
-func NewMetricsProvider(logger lager.Logger, backingStoresPath, depotPath string) *MetricsProvider {
+func NewMetricsProvider(logger lager.Logger, backingStoresPath, depotPath, graphPath string, diskUsageWarningThreshold int) *MetricsProvider {
 	return &MetricsProvider{
-		backingStoresPath: backingStoresPath,
-		depotPath:         depotPath,
-		logger:            logger.Session("metrics"),
+		backingStoresPath:         backingStoresPath,
+		depotPath:                 depotPath,
+		graphPath:                 graphPath,
+		diskUsageWarningThreshold: diskUsageWarningThreshold,
+		logger:                    logger.Session("metrics"),
 	}
 }
 
@@ -65,3 +70,36 @@ func (m *MetricsProvider) DepotDirs() int {
 
 	return len(entries)
 }
+
+// DepotDiskUsage returns the percentage of the depot filesystem in use
+func (m *MetricsProvider) DepotDiskUsage() int {
+	return m.diskUsage("depot", m.depotPath)
+}
+
+// GraphDiskUsage returns the percentage of the graph filesystem in use
+func (m *MetricsProvider) GraphDiskUsage() int {
+	if m.graphPath == "" {
+		// graph is disabled
+		return -1
+	}
+
+	return m.diskUsage("graph", m.graphPath)
+}
+
+func (m *MetricsProvider) diskUsage(name, path string) int {
+	usage := sigar.FileSystemUsage{}
+	if err := usage.Get(path); err != nil {
+		m.logger.Error(fmt.Sprintf("cannot-get-%s-disk-usage", name), err)
+		return -1
+	}
+
+	percent := int(usage.UsePercent())
+	if m.diskUsageWarningThreshold > 0 && percent >= m.diskUsageWarningThreshold {
+		m.logger.Error(fmt.Sprintf("%s-disk-usage-above-threshold", name),
+			fmt.Errorf("%s filesystem is %d%% full", name, percent),
+			lager.Data{"path": path, "threshold": m.diskUsageWarningThreshold},
+		)
+	}
+
+	return percent
+}
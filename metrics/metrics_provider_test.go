@@ -42,7 +42,7 @@ var _ = Describe("MetricsProvider", func() {
 
 		Expect(err).ToNot(HaveOccurred())
 		logger = lagertest.NewTestLogger("test")
-		m = metrics.NewMetricsProvider(logger, backingStorePath, depotPath)
+		m = metrics.NewMetricsProvider(logger, backingStorePath, depotPath, backingStorePath, 0)
 	})
 
 	AfterEach(func() {
@@ -60,10 +60,48 @@ var _ = Describe("MetricsProvider", func() {
 
 	Context("when the backing store path is empty", func() {
 		It("reports BackingStores as -1 without doing any funny business", func() {
-			m := metrics.NewMetricsProvider(logger, "", depotPath)
+			m := metrics.NewMetricsProvider(logger, "", depotPath, backingStorePath, 0)
 			Expect(m.BackingStores()).To(Equal(-1))
 
 			Expect(logger.LogMessages()).To(BeEmpty())
 		})
 	})
+
+	It("should report the disk usage of the depot and graph filesystems", func() {
+		Expect(m.DepotDiskUsage()).To(BeNumerically(">=", 0))
+		Expect(m.DepotDiskUsage()).To(BeNumerically("<=", 100))
+		Expect(m.GraphDiskUsage()).To(BeNumerically(">=", 0))
+		Expect(m.GraphDiskUsage()).To(BeNumerically("<=", 100))
+
+		Expect(logger.LogMessages()).To(BeEmpty())
+	})
+
+	Context("when the graph path is empty", func() {
+		It("reports GraphDiskUsage as -1", func() {
+			m := metrics.NewMetricsProvider(logger, backingStorePath, depotPath, "", 0)
+			Expect(m.GraphDiskUsage()).To(Equal(-1))
+
+			Expect(logger.LogMessages()).To(BeEmpty())
+		})
+	})
+
+	Context("when the depot path does not exist", func() {
+		It("reports DepotDiskUsage as -1 and logs the error", func() {
+			m := metrics.NewMetricsProvider(logger, backingStorePath, "/does/not/exist", "", 0)
+			Expect(m.DepotDiskUsage()).To(Equal(-1))
+
+			Expect(logger.LogMessages()).To(ContainElement("test.metrics.cannot-get-depot-disk-usage"))
+		})
+	})
+
+	Context("when the disk usage is above the warning threshold", func() {
+		It("logs a warning", func() {
+			m := metrics.NewMetricsProvider(logger, backingStorePath, depotPath, "", 1)
+			if m.DepotDiskUsage() < 1 {
+				Skip("depot filesystem is empty")
+			}
+
+			Expect(logger.LogMessages()).To(ContainElement("test.metrics.depot-disk-usage-above-threshold"))
+		})
+	})
 })
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -27,6 +28,7 @@ type externalBinaryNetworker struct {
 	resolvConfigurer      kawasaki.DnsResolvConfigurer
 	path                  string
 	extraArg              []string
+	env                   []string
 }
 
 func New(
@@ -38,6 +40,7 @@ func New(
 	resolvConfigurer kawasaki.DnsResolvConfigurer,
 	path string,
 	extraArg []string,
+	env []string,
 ) ExternalNetworker {
 	return &externalBinaryNetworker{
 		commandRunner:         commandRunner,
@@ -48,6 +51,7 @@ func New(
 		resolvConfigurer:      resolvConfigurer,
 		path:                  path,
 		extraArg:              extraArg,
+		env:                   env,
 	}
 }
 
@@ -95,7 +99,7 @@ func (p *externalBinaryNetworker) Network(log lager.Logger, containerSpec garden
 	}
 
 	outputs := UpOutputs{}
-	err := p.exec(log, "up", containerSpec.Handle, inputs, &outputs)
+	err := p.exec(log, "up", containerSpec.Handle, containerSpec.Properties, inputs, &outputs)
 	if err != nil {
 		return err
 	}
@@ -137,7 +141,7 @@ func (p *externalBinaryNetworker) Network(log lager.Logger, containerSpec garden
 }
 
 func (p *externalBinaryNetworker) Destroy(log lager.Logger, handle string) error {
-	return p.exec(log, "down", handle, nil, nil)
+	return p.exec(log, "down", handle, nil, nil, nil)
 }
 
 func (p *externalBinaryNetworker) Restore(log lager.Logger, handle string) error {
@@ -174,7 +178,7 @@ func (p *externalBinaryNetworker) NetIn(log lager.Logger, handle string, hostPor
 	}
 	outputs := NetInOutputs{}
 
-	err := p.exec(log, "net-in", handle, inputs, &outputs)
+	err := p.exec(log, "net-in", handle, nil, inputs, &outputs)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -206,7 +210,7 @@ func (p *externalBinaryNetworker) NetOut(log lager.Logger, handle string, rule g
 		NetOutRule:  rule,
 	}
 
-	err := p.exec(log, "net-out", handle, inputs, nil)
+	err := p.exec(log, "net-out", handle, nil, inputs, nil)
 	if err != nil {
 		return err
 	}
@@ -230,10 +234,12 @@ func (p *externalBinaryNetworker) BulkNetOut(log lager.Logger, handle string, ru
 		NetOutRules: rules,
 	}
 
-	return p.exec(log, "bulk-net-out", handle, inputs, nil)
+	return p.exec(log, "bulk-net-out", handle, nil, inputs, nil)
 }
 
-func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string,
+// exec runs the plugin. The properties are those the container is being
+// created with, which are not yet in the config store.
+func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string, properties garden.Properties,
 	inputData interface{}, outputData interface{}) error {
 
 	stdinBytes, err := json.Marshal(inputData)
@@ -241,8 +247,14 @@ func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string,
 		return err
 	}
 
+	env, err := p.pluginEnv(handle, properties)
+	if err != nil {
+		return err
+	}
+
 	args := append(p.extraArg, "--action", action, "--handle", handle)
 	cmd := exec.Command(p.path, args...)
+	cmd.Env = env
 	stdout := &bytes.Buffer{}
 	cmd.Stdout = stdout
 	stderr := &bytes.Buffer{}
@@ -268,3 +280,28 @@ func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string,
 	log.Debug("external-networker-result", logData)
 	return nil
 }
+
+// pluginEnv returns the environment to run the plugin with: the server's
+// environment plus the operator's extra variables, with any references to
+// container properties expanded. Properties the container does not have
+// expand to nothing. It returns nil, so that the plugin inherits the server's
+// environment, if there are no extra variables.
+func (p *externalBinaryNetworker) pluginEnv(handle string, properties garden.Properties) ([]string, error) {
+	if len(p.env) == 0 {
+		return nil, nil
+	}
+
+	env, err := gardener.ExpandEnv(p.env, func(name string) (string, bool) {
+		if value, ok := properties[name]; ok {
+			return value, true
+		}
+
+		value, _ := p.configStore.Get(handle, name)
+		return value, true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(os.Environ(), env...), nil
+}
@@ -71,6 +71,7 @@ var _ = Describe("ExternalNetworker", func() {
 			resolvConfigurer,
 			"some/path",
 			[]string{"arg1", "arg2", "arg3"},
+			nil,
 		)
 
 		pluginErr = nil
@@ -434,6 +435,46 @@ var _ = Describe("ExternalNetworker", func() {
 		})
 	})
 
+	Describe("plugin environment", func() {
+		It("runs the plugin with the server's environment", func() {
+			Expect(plugin.Destroy(logger, "some-handle")).To(Succeed())
+			Expect(fakeCommandRunner.ExecutedCommands()[0].Env).To(BeNil())
+		})
+
+		Context("when the operator configures extra environment variables", func() {
+			BeforeEach(func() {
+				plugin = netplugin.New(
+					fakeCommandRunner,
+					configStore,
+					net.ParseIP("1.2.3.4"),
+					dnsServers,
+					additionalDNSServers,
+					resolvConfigurer,
+					"some/path",
+					[]string{"arg1", "arg2", "arg3"},
+					[]string{"LOG_DESTINATION=syslog", "TENANT=$(property:network.some-key)", "MISSING=$(property:network.missing)"},
+				)
+			})
+
+			It("adds them to the environment, expanding the properties the container is created with", func() {
+				Expect(plugin.Network(logger, containerSpec, 42)).To(Succeed())
+
+				env := fakeCommandRunner.ExecutedCommands()[0].Env
+				Expect(env).To(ContainElement("LOG_DESTINATION=syslog"))
+				Expect(env).To(ContainElement("TENANT=some-network-value"))
+				Expect(env).To(ContainElement("MISSING="))
+				Expect(env).To(ContainElement(HavePrefix("PATH=")))
+			})
+
+			It("expands the properties of existing containers from the config store", func() {
+				configStore.Set("some-handle", "network.some-key", "stored-value")
+				Expect(plugin.Destroy(logger, "some-handle")).To(Succeed())
+
+				Expect(fakeCommandRunner.ExecutedCommands()[0].Env).To(ContainElement("TENANT=stored-value"))
+			})
+		})
+	})
+
 	Describe("Destroy", func() {
 		It("executes the external plugin with the correct args", func() {
 			Expect(plugin.Destroy(logger, "my-handle")).To(Succeed())
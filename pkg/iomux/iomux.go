@@ -0,0 +1,213 @@
+// Package iomux carries the stdout and stderr of a process, followed by its
+// exit status, over a single stream as a sequence of frames. Each frame is a
+// one byte stream type, a four byte big-endian payload length and the payload.
+// A stream which ends early, e.g. because the reader fell too far behind the
+// output, ends with an error frame instead of the exit status.
+package iomux
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	StdoutStream byte = 1
+	StderrStream byte = 2
+	ExitStream   byte = 3
+	ErrorStream  byte = 4
+)
+
+// ErrClosed is returned by writes after the exit status or an error has been
+// written, or the Writer has been closed
+var ErrClosed = errors.New("iomux: writer closed")
+
+// MaxPayloadSize is the largest payload a frame carries. Larger writes are
+// split, so that a large write to one stream does not hold up the other.
+const MaxPayloadSize = 32 * 1024
+
+const headerSize = 5
+
+type flusher interface {
+	Flush()
+}
+
+// Writer writes frames to an underlying writer. The underlying writer is
+// flushed after each frame if it can be, e.g. an http.ResponseWriter.
+type Writer struct {
+	mutex  sync.Mutex
+	w      io.Writer
+	closed bool
+	done   chan struct{}
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, done: make(chan struct{})}
+}
+
+// Stdout returns a writer which writes stdout frames
+func (m *Writer) Stdout() io.Writer {
+	return streamWriter{mux: m, stream: StdoutStream}
+}
+
+// Stderr returns a writer which writes stderr frames
+func (m *Writer) Stderr() io.Writer {
+	return streamWriter{mux: m, stream: StderrStream}
+}
+
+// Exit writes the exit status frame, which is the last frame of the stream
+func (m *Writer) Exit(status int) error {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(int32(status)))
+	return m.writeLastFrame(ExitStream, payload)
+}
+
+// Error writes an error frame carrying the message of err, which ends the
+// stream without an exit status
+func (m *Writer) Error(err error) error {
+	return m.writeLastFrame(ErrorStream, []byte(err.Error()))
+}
+
+// Close stops any further frames being written, without writing an exit
+// status, e.g. once the underlying writer can no longer be written to
+func (m *Writer) Close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.close()
+}
+
+// Done is closed once the last frame has been written or the Writer has been
+// closed
+func (m *Writer) Done() <-chan struct{} {
+	return m.done
+}
+
+func (m *Writer) close() {
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+}
+
+// Flush flushes the underlying writer if it can be flushed, so that e.g. the
+// headers of an http.ResponseWriter are sent before the first frame
+func (m *Writer) Flush() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.closed {
+		m.flush()
+	}
+}
+
+func (m *Writer) flush() {
+	if f, ok := m.w.(flusher); ok {
+		f.Flush()
+	}
+}
+
+func (m *Writer) writeLastFrame(stream byte, payload []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	defer m.close()
+
+	return m.write(stream, payload)
+}
+
+func (m *Writer) writeFrame(stream byte, payload []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.write(stream, payload)
+}
+
+func (m *Writer) write(stream byte, payload []byte) error {
+	if m.closed {
+		return ErrClosed
+	}
+
+	header := make([]byte, headerSize)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	if _, err := m.w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+
+	m.flush()
+
+	return nil
+}
+
+type streamWriter struct {
+	mux    *Writer
+	stream byte
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := written + MaxPayloadSize
+		if end > len(p) {
+			end = len(p)
+		}
+
+		if err := w.mux.writeFrame(w.stream, p[written:end]); err != nil {
+			return written, err
+		}
+		written = end
+	}
+
+	return written, nil
+}
+
+// CloseWithError ends the stream with an error frame, e.g. when the output
+// written to the stream has been detached from it. The frame is written in
+// the background, as the write which was detached may still be in progress,
+// and Done is closed once it has been written.
+func (w streamWriter) CloseWithError(err error) error {
+	go w.mux.Error(err)
+	return nil
+}
+
+// Frame is a single frame read from a stream
+type Frame struct {
+	Stream  byte
+	Payload []byte
+}
+
+// ExitStatus returns the exit status carried by an exit frame
+func (f Frame) ExitStatus() int {
+	return int(int32(binary.BigEndian.Uint32(f.Payload)))
+}
+
+// ReadFrame reads the next frame from r. It returns io.EOF if r ends between
+// frames, and io.ErrUnexpectedEOF if it ends part way through one.
+func ReadFrame(r io.Reader) (Frame, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Frame{}, err
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > MaxPayloadSize {
+		return Frame{}, fmt.Errorf("frame payload of %d bytes exceeds the maximum of %d", length, MaxPayloadSize)
+	}
+
+	frame := Frame{Stream: header[0], Payload: make([]byte, length)}
+	if _, err := io.ReadFull(r, frame.Payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Frame{}, err
+	}
+
+	if frame.Stream == ExitStream && length != 4 {
+		return Frame{}, fmt.Errorf("exit frame payload is %d bytes rather than 4", length)
+	}
+
+	return frame, nil
+}
@@ -0,0 +1,13 @@
+package iomux_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestIomux(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Iomux Suite")
+}
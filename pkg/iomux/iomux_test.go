@@ -0,0 +1,144 @@
+package iomux_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/pkg/iomux"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("iomux", func() {
+	var (
+		buf *bytes.Buffer
+		mux *iomux.Writer
+	)
+
+	BeforeEach(func() {
+		buf = new(bytes.Buffer)
+		mux = iomux.NewWriter(buf)
+	})
+
+	readFrames := func() []iomux.Frame {
+		var frames []iomux.Frame
+		for {
+			frame, err := iomux.ReadFrame(buf)
+			if err == io.EOF {
+				return frames
+			}
+			Expect(err).NotTo(HaveOccurred())
+			frames = append(frames, frame)
+		}
+	}
+
+	It("carries interleaved stdout and stderr followed by the exit status", func() {
+		fmt.Fprint(mux.Stdout(), "hello")
+		fmt.Fprint(mux.Stderr(), "oops")
+		fmt.Fprint(mux.Stdout(), "goodbye")
+		Expect(mux.Exit(42)).To(Succeed())
+
+		frames := readFrames()
+		Expect(frames).To(HaveLen(4))
+		Expect(frames[0]).To(Equal(iomux.Frame{Stream: iomux.StdoutStream, Payload: []byte("hello")}))
+		Expect(frames[1]).To(Equal(iomux.Frame{Stream: iomux.StderrStream, Payload: []byte("oops")}))
+		Expect(frames[2]).To(Equal(iomux.Frame{Stream: iomux.StdoutStream, Payload: []byte("goodbye")}))
+		Expect(frames[3].Stream).To(Equal(iomux.ExitStream))
+		Expect(frames[3].ExitStatus()).To(Equal(42))
+	})
+
+	It("carries negative exit statuses", func() {
+		Expect(mux.Exit(-1)).To(Succeed())
+
+		frames := readFrames()
+		Expect(frames[0].ExitStatus()).To(Equal(-1))
+	})
+
+	It("splits large writes into frames of at most MaxPayloadSize", func() {
+		data := strings.Repeat("x", iomux.MaxPayloadSize*2+1)
+		n, err := mux.Stdout().Write([]byte(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(len(data)))
+
+		frames := readFrames()
+		Expect(frames).To(HaveLen(3))
+		Expect(frames[0].Payload).To(HaveLen(iomux.MaxPayloadSize))
+		Expect(frames[2].Payload).To(HaveLen(1))
+	})
+
+	It("flushes the underlying writer after each frame", func() {
+		flushing := &flushingWriter{}
+		mux = iomux.NewWriter(flushing)
+
+		fmt.Fprint(mux.Stdout(), "hello")
+		Expect(mux.Exit(0)).To(Succeed())
+
+		Expect(flushing.flushes).To(Equal(2))
+	})
+
+	It("ends the stream with an error frame", func() {
+		fmt.Fprint(mux.Stdout(), "hello")
+		Expect(mux.Error(errors.New("fell behind"))).To(Succeed())
+		Expect(mux.Done()).To(BeClosed())
+
+		_, err := mux.Stdout().Write([]byte("more"))
+		Expect(err).To(Equal(iomux.ErrClosed))
+		Expect(mux.Exit(0)).To(Equal(iomux.ErrClosed))
+
+		frames := readFrames()
+		Expect(frames).To(HaveLen(2))
+		Expect(frames[1]).To(Equal(iomux.Frame{Stream: iomux.ErrorStream, Payload: []byte("fell behind")}))
+	})
+
+	It("ends the stream with an error frame when a stream writer is closed with an error", func() {
+		closer, ok := mux.Stderr().(interface {
+			CloseWithError(error) error
+		})
+		Expect(ok).To(BeTrue())
+
+		Expect(closer.CloseWithError(errors.New("detached"))).To(Succeed())
+		Eventually(mux.Done()).Should(BeClosed())
+
+		Expect(readFrames()).To(Equal([]iomux.Frame{{Stream: iomux.ErrorStream, Payload: []byte("detached")}}))
+	})
+
+	It("writes no more frames once closed", func() {
+		fmt.Fprint(mux.Stdout(), "hello")
+		mux.Close()
+		Expect(mux.Done()).To(BeClosed())
+
+		_, err := mux.Stdout().Write([]byte("more"))
+		Expect(err).To(Equal(iomux.ErrClosed))
+		Expect(mux.Exit(0)).To(Equal(iomux.ErrClosed))
+		Expect(readFrames()).To(HaveLen(1))
+	})
+
+	Context("when the stream ends part way through a frame", func() {
+		It("returns io.ErrUnexpectedEOF", func() {
+			fmt.Fprint(mux.Stdout(), "hello")
+			buf.Truncate(buf.Len() - 1)
+
+			_, err := iomux.ReadFrame(buf)
+			Expect(err).To(Equal(io.ErrUnexpectedEOF))
+		})
+	})
+
+	Context("when a frame claims a payload larger than the maximum", func() {
+		It("returns an error", func() {
+			_, err := iomux.ReadFrame(bytes.NewReader([]byte{iomux.StdoutStream, 0xff, 0xff, 0xff, 0xff}))
+			Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+		})
+	})
+})
+
+type flushingWriter struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushingWriter) Flush() {
+	w.flushes++
+}
@@ -0,0 +1,17 @@
+package properties
+
+//go:generate counterfeiter . Backend
+
+// A Backend durably stores the key spaces of a Manager, so that properties
+// survive restarts of the server and, for remote backends, can be kept in a
+// central store.
+type Backend interface {
+	// Load returns every stored key space, by handle
+	Load() (map[string]map[string]string, error)
+
+	// Save replaces the stored key space of the handle
+	Save(handle string, props map[string]string) error
+
+	// Delete idempotently removes the stored key space of the handle
+	Delete(handle string) error
+}
@@ -0,0 +1,135 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultConsulTimeout bounds each request to Consul made by a ConsulBackend
+// without a Client, so that an unresponsive agent cannot hang the server
+const DefaultConsulTimeout = 10 * time.Second
+
+var defaultConsulClient = &http.Client{Timeout: DefaultConsulTimeout}
+
+// ConsulBackend stores the key space of each container as a JSON value in
+// the Consul KV store, under Prefix. Every cell must use its own prefix, as a
+// cell treats every key space it loads as belonging to its own containers.
+type ConsulBackend struct {
+	URL    string
+	Prefix string
+	Client *http.Client
+}
+
+type consulKV struct {
+	Key   string
+	Value []byte
+}
+
+func (b ConsulBackend) Load() (map[string]map[string]string, error) {
+	kvURL, err := b.kvURL("")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client().Get(kvURL + "/?recurse")
+	if err != nil {
+		return nil, fmt.Errorf("loading properties from consul: %s", err)
+	}
+	defer resp.Body.Close()
+
+	keySpaces := map[string]map[string]string{}
+	if resp.StatusCode == http.StatusNotFound {
+		return keySpaces, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loading properties from consul: %s", responseError(resp))
+	}
+
+	var kvs []consulKV
+	if err := json.NewDecoder(resp.Body).Decode(&kvs); err != nil {
+		return nil, fmt.Errorf("loading properties from consul: %s", err)
+	}
+
+	for _, kv := range kvs {
+		handle := strings.TrimPrefix(kv.Key, strings.Trim(b.Prefix, "/")+"/")
+		if handle == "" || strings.Contains(handle, "/") {
+			continue
+		}
+
+		var props map[string]string
+		if err := json.Unmarshal(kv.Value, &props); err != nil {
+			return nil, fmt.Errorf("parsing properties for '%s': %s", handle, err)
+		}
+
+		keySpaces[handle] = props
+	}
+
+	return keySpaces, nil
+}
+
+func (b ConsulBackend) Save(handle string, props map[string]string) error {
+	contents, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+
+	return b.do("PUT", handle, contents)
+}
+
+func (b ConsulBackend) Delete(handle string) error {
+	return b.do("DELETE", handle, nil)
+}
+
+func (b ConsulBackend) do(method, handle string, body []byte) error {
+	kvURL, err := b.kvURL(handle)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, kvURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("storing properties for '%s' in consul: %s", handle, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storing properties for '%s' in consul: %s", handle, responseError(resp))
+	}
+
+	return nil
+}
+
+func (b ConsulBackend) kvURL(handle string) (string, error) {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return "", fmt.Errorf("parsing consul URL: %s", err)
+	}
+
+	u.Path = path.Join(u.Path, "v1", "kv", b.Prefix, handle)
+	return u.String(), nil
+}
+
+func (b ConsulBackend) client() *http.Client {
+	if b.Client != nil {
+		return b.Client
+	}
+
+	return defaultConsulClient
+}
+
+func responseError(resp *http.Response) string {
+	body, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Sprintf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
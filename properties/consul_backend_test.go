@@ -0,0 +1,107 @@
+package properties_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/guardian/properties"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConsulBackend", func() {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+		bodies   []string
+		status   int
+		response string
+		backend  properties.ConsulBackend
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+		status = http.StatusOK
+		response = "true"
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+
+			w.WriteHeader(status)
+			fmt.Fprint(w, response)
+		}))
+
+		backend = properties.ConsulBackend{URL: server.URL, Prefix: "garden/properties/cell-1"}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("puts the key space of a handle under the prefix", func() {
+		Expect(backend.Save("handle-1", map[string]string{"foo": "bar"})).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal("PUT"))
+		Expect(requests[0].URL.Path).To(Equal("/v1/kv/garden/properties/cell-1/handle-1"))
+		Expect(bodies[0]).To(MatchJSON(`{"foo":"bar"}`))
+	})
+
+	It("deletes the key space of a handle", func() {
+		Expect(backend.Delete("handle-1")).To(Succeed())
+
+		Expect(requests[0].Method).To(Equal("DELETE"))
+		Expect(requests[0].URL.Path).To(Equal("/v1/kv/garden/properties/cell-1/handle-1"))
+	})
+
+	It("loads every key space under the prefix", func() {
+		response = fmt.Sprintf(`[
+			{"Key": "garden/properties/cell-1/handle-1", "Value": "%s"},
+			{"Key": "garden/properties/cell-1/handle-2", "Value": "%s"}
+		]`,
+			base64.StdEncoding.EncodeToString([]byte(`{"foo":"bar"}`)),
+			base64.StdEncoding.EncodeToString([]byte(`{"baz":"qux"}`)),
+		)
+
+		Expect(backend.Load()).To(Equal(map[string]map[string]string{
+			"handle-1": {"foo": "bar"},
+			"handle-2": {"baz": "qux"},
+		}))
+
+		Expect(requests[0].Method).To(Equal("GET"))
+		Expect(requests[0].URL.Path).To(Equal("/v1/kv/garden/properties/cell-1/"))
+		Expect(requests[0].URL.Query()).To(HaveKey("recurse"))
+	})
+
+	Context("when nothing is stored under the prefix", func() {
+		BeforeEach(func() {
+			status = http.StatusNotFound
+			response = ""
+		})
+
+		It("loads nothing", func() {
+			Expect(backend.Load()).To(BeEmpty())
+		})
+	})
+
+	Context("when consul returns an error", func() {
+		BeforeEach(func() {
+			status = http.StatusInternalServerError
+			response = "no cluster leader"
+		})
+
+		It("returns it", func() {
+			Expect(backend.Save("handle-1", map[string]string{})).To(MatchError(ContainSubstring("no cluster leader")))
+			Expect(backend.Delete("handle-1")).To(MatchError(ContainSubstring("no cluster leader")))
+			_, err := backend.Load()
+			Expect(err).To(MatchError(ContainSubstring("no cluster leader")))
+		})
+	})
+})
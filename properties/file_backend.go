@@ -0,0 +1,98 @@
+package properties
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const fileExtension = ".json"
+
+// FileBackend stores the key space of each container in its own JSON file,
+// writing every change as it happens rather than only on shutdown
+type FileBackend struct {
+	Dir string
+}
+
+func (b FileBackend) Load() (map[string]map[string]string, error) {
+	keySpaces := map[string]map[string]string{}
+
+	entries, err := ioutil.ReadDir(b.Dir)
+	if os.IsNotExist(err) {
+		return keySpaces, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing properties: %s", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(b.Dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading properties: %s", err)
+		}
+
+		handle := strings.TrimSuffix(name, fileExtension)
+		var props map[string]string
+		if err := json.Unmarshal(contents, &props); err != nil {
+			return nil, fmt.Errorf("parsing properties for '%s': %s", handle, err)
+		}
+
+		keySpaces[handle] = props
+	}
+
+	return keySpaces, nil
+}
+
+// Save writes the key space to a temporary file and renames it into place so
+// that a crash can never leave a partially written file behind
+func (b FileBackend) Save(handle string, props map[string]string) error {
+	contents, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(b.Dir, 0700); err != nil {
+		return fmt.Errorf("creating properties dir: %s", err)
+	}
+
+	tmpFile, err := ioutil.TempFile(b.Dir, ".tmp-"+handle)
+	if err != nil {
+		return fmt.Errorf("writing properties for '%s': %s", handle, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing properties for '%s': %s", handle, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("writing properties for '%s': %s", handle, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), b.path(handle)); err != nil {
+		return fmt.Errorf("writing properties for '%s': %s", handle, err)
+	}
+
+	return nil
+}
+
+func (b FileBackend) Delete(handle string) error {
+	if err := os.Remove(b.path(handle)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing properties for '%s': %s", handle, err)
+	}
+
+	return nil
+}
+
+func (b FileBackend) path(handle string) string {
+	return filepath.Join(b.Dir, handle+fileExtension)
+}
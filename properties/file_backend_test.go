@@ -0,0 +1,66 @@
+package properties_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/properties"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileBackend", func() {
+	var (
+		dir     string
+		backend properties.FileBackend
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "properties")
+		Expect(err).NotTo(HaveOccurred())
+
+		backend = properties.FileBackend{Dir: filepath.Join(dir, "props")}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("loads nothing when nothing has been saved", func() {
+		Expect(backend.Load()).To(BeEmpty())
+	})
+
+	It("loads the saved key spaces", func() {
+		Expect(backend.Save("handle-1", map[string]string{"foo": "bar"})).To(Succeed())
+		Expect(backend.Save("handle-2", map[string]string{"baz": "qux"})).To(Succeed())
+		Expect(backend.Save("handle-1", map[string]string{"foo": "baz"})).To(Succeed())
+
+		Expect(backend.Load()).To(Equal(map[string]map[string]string{
+			"handle-1": {"foo": "baz"},
+			"handle-2": {"baz": "qux"},
+		}))
+	})
+
+	It("does not load deleted key spaces", func() {
+		Expect(backend.Save("handle-1", map[string]string{"foo": "bar"})).To(Succeed())
+		Expect(backend.Delete("handle-1")).To(Succeed())
+
+		Expect(backend.Load()).To(BeEmpty())
+	})
+
+	It("deletes idempotently", func() {
+		Expect(backend.Delete("handle-1")).To(Succeed())
+	})
+
+	Context("when a stored file is corrupt", func() {
+		It("returns an error", func() {
+			Expect(os.MkdirAll(backend.Dir, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(backend.Dir, "handle-1.json"), []byte("{"), 0600)).To(Succeed())
+
+			_, err := backend.Load()
+			Expect(err).To(MatchError(ContainSubstring("parsing properties for 'handle-1'")))
+		})
+	})
+})
@@ -3,14 +3,29 @@ package properties
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
 )
 
 type Manager struct {
 	propMutex sync.RWMutex
 	prop      map[string]map[string]string
+
+	backend Backend
+	logger  lager.Logger
+
+	// versions counts the changes to each key space, and writers orders the
+	// writes of each key space to the backend, which happen outside propMutex
+	versions map[string]uint64
+	writers  map[string]*keySpaceWriter
+}
+
+type keySpaceWriter struct {
+	mutex   sync.Mutex
+	written uint64
 }
 
 func NewManager() *Manager {
@@ -19,13 +34,55 @@ func NewManager() *Manager {
 	}
 }
 
+// NewBackedManager returns a Manager holding the properties stored in the
+// backend, which writes every change through to the backend
+func NewBackedManager(logger lager.Logger, backend Backend) (*Manager, error) {
+	prop, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		prop:     prop,
+		backend:  backend,
+		logger:   logger.Session("properties"),
+		versions: make(map[string]uint64),
+		writers:  make(map[string]*keySpaceWriter),
+	}, nil
+}
+
 func (m *Manager) DestroyKeySpace(handle string) error {
 	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
-
 	delete(m.prop, handle)
+	writer, version := m.changed(handle)
+	m.propMutex.Unlock()
 
-	return nil
+	return m.write(writer, handle, version, nil)
+}
+
+// Orphans returns the handles of key spaces which do not belong to any of the
+// known handles
+func (m *Manager) Orphans(log lager.Logger, knownHandles []string) ([]string, error) {
+	m.propMutex.RLock()
+	defer m.propMutex.RUnlock()
+
+	known := map[string]bool{}
+	for _, handle := range knownHandles {
+		known[handle] = true
+	}
+
+	orphans := []string{}
+	for handle := range m.prop {
+		if !known[handle] {
+			orphans = append(orphans, handle)
+		}
+	}
+
+	return orphans, nil
+}
+
+func (m *Manager) CleanOrphan(log lager.Logger, handle string) error {
+	return m.DestroyKeySpace(handle)
 }
 
 func (m *Manager) MarshalJSON() ([]byte, error) {
@@ -37,21 +94,34 @@ func (m *Manager) UnmarshalJSON(data []byte) error {
 }
 
 func (m *Manager) Set(handle string, name string, value string) {
-	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
+	if err := m.Store(handle, name, value); err != nil {
+		m.logger.Error("saving-failed", err, lager.Data{"handle": handle, "name": name})
+	}
+}
 
+// Store sets the property like Set, but returns an error if the change could
+// not be written to the backend. The property is set either way.
+func (m *Manager) Store(handle string, name string, value string) error {
+	m.propMutex.Lock()
 	if _, ok := m.prop[handle]; !ok {
 		m.prop[handle] = make(map[string]string)
 	}
 
 	m.prop[handle][name] = value
+	writer, version := m.changed(handle)
+	props := m.copyKeySpace(handle)
+	m.propMutex.Unlock()
+
+	return m.write(writer, handle, version, props)
 }
 
+// All returns a copy of the properties of the handle, which callers may hold
+// on to while the properties keep changing
 func (m *Manager) All(handle string) (garden.Properties, error) {
 	m.propMutex.RLock()
 	defer m.propMutex.RUnlock()
 
-	return m.prop[handle], nil
+	return m.copyKeySpace(handle), nil
 }
 
 func (m *Manager) Get(handle string, name string) (string, bool) {
@@ -69,25 +139,109 @@ func (m *Manager) Get(handle string, name string) (string, bool) {
 
 func (m *Manager) Remove(handle string, name string) error {
 	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
-
 	if _, exists := m.prop[handle][name]; !exists {
+		m.propMutex.Unlock()
 		return NoSuchPropertyError{
 			Message: fmt.Sprintf("cannot Remove %s:%s", handle, name),
 		}
 	}
 
 	delete(m.prop[handle], name)
+	writer, version := m.changed(handle)
+	props := m.copyKeySpace(handle)
+	m.propMutex.Unlock()
+
+	return m.write(writer, handle, version, props)
+}
+
+// changed records a change to the key space of the handle and returns its
+// version, along with the writer which orders its writes to the backend. It
+// must be called with propMutex held.
+func (m *Manager) changed(handle string) (*keySpaceWriter, uint64) {
+	if m.backend == nil {
+		return nil, 0
+	}
+
+	writer, ok := m.writers[handle]
+	if !ok {
+		writer = &keySpaceWriter{}
+		m.writers[handle] = writer
+	}
+
+	m.versions[handle]++
+	return writer, m.versions[handle]
+}
+
+// copyKeySpace returns a copy of the key space of the handle. It must be
+// called with propMutex held.
+func (m *Manager) copyKeySpace(handle string) map[string]string {
+	props := make(map[string]string, len(m.prop[handle]))
+	for name, value := range m.prop[handle] {
+		props[name] = value
+	}
+
+	return props
+}
+
+// write saves the given version of the key space of the handle to the
+// backend, or deletes it if props is nil, without holding propMutex so that
+// a slow backend only holds up writes to the same key space. A version older
+// than one already written is skipped, so that the backend always ends up
+// with the latest change.
+func (m *Manager) write(writer *keySpaceWriter, handle string, version uint64, props map[string]string) error {
+	if m.backend == nil {
+		return nil
+	}
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if version <= writer.written {
+		return nil
+	}
+
+	var err error
+	if props == nil {
+		err = m.backend.Delete(handle)
+	} else {
+		err = m.backend.Save(handle, props)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	writer.written = version
+	if props == nil {
+		m.forgetWriter(handle, version)
+	}
 
 	return nil
 }
 
+// forgetWriter stops tracking the writes of a deleted key space, unless it
+// has changed again since
+func (m *Manager) forgetWriter(handle string, version uint64) {
+	m.propMutex.Lock()
+	defer m.propMutex.Unlock()
+
+	if m.versions[handle] == version {
+		delete(m.versions, handle)
+		delete(m.writers, handle)
+	}
+}
+
+// MatchesAll returns whether the handle has every one of the given properties.
+// A '*' in a key or value matches any run of characters, so that e.g.
+// {"tag:org": "*"} matches every handle which has a tag:org property and
+// {"tag:*": "prod-*"} matches every handle with a tag:* property whose value
+// starts with prod-.
 func (m *Manager) MatchesAll(handle string, props garden.Properties) bool {
 	m.propMutex.RLock()
 	defer m.propMutex.RUnlock()
 
 	for key, val := range props {
-		if m.prop[handle][key] != val {
+		if !m.matches(handle, key, val) {
 			return false
 		}
 	}
@@ -95,6 +249,51 @@ func (m *Manager) MatchesAll(handle string, props garden.Properties) bool {
 	return true
 }
 
+func (m *Manager) matches(handle, keyPattern, valuePattern string) bool {
+	if !strings.Contains(keyPattern, wildcard) {
+		value, exists := m.prop[handle][keyPattern]
+		if !strings.Contains(valuePattern, wildcard) {
+			return value == valuePattern
+		}
+
+		return exists && globMatch(valuePattern, value)
+	}
+
+	for key, value := range m.prop[handle] {
+		if globMatch(keyPattern, key) && globMatch(valuePattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const wildcard = "*"
+
+// globMatch returns whether s matches the pattern, where '*' matches any run
+// of characters and every other character matches itself
+func globMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, wildcard)
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
 type NoSuchPropertyError struct {
 	Message string
 }
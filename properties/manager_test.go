@@ -2,11 +2,14 @@ package properties_test
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"os"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/guardian/properties/propertiesfakes"
+	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -34,6 +37,28 @@ var _ = Describe("Properties", func() {
 		})
 	})
 
+	Describe("Orphans", func() {
+		BeforeEach(func() {
+			propertyManager.Set("other-handle", "name", "value")
+		})
+
+		It("returns the key spaces which do not belong to a known handle", func() {
+			orphans, err := propertyManager.Orphans(lagertest.NewTestLogger("test"), []string{"handle", "unrelated-handle"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans).To(ConsistOf("other-handle"))
+		})
+	})
+
+	Describe("CleanOrphan", func() {
+		It("removes the key space", func() {
+			Expect(propertyManager.CleanOrphan(lagertest.NewTestLogger("test"), "handle")).To(Succeed())
+
+			props, err := propertyManager.All("handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props).To(BeEmpty())
+		})
+	})
+
 	Describe("All", func() {
 		It("returns the properties", func() {
 			props, err := propertyManager.All("handle")
@@ -42,6 +67,16 @@ var _ = Describe("Properties", func() {
 			Expect(props).To(HaveLen(1))
 			Expect(props).To(HaveKeyWithValue("name", "value"))
 		})
+
+		It("returns a copy which later changes do not touch", func() {
+			props, err := propertyManager.All("handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			propertyManager.Set("handle", "name", "some-other-value")
+			propertyManager.Set("handle", "other-name", "other-value")
+
+			Expect(props).To(Equal(garden.Properties{"name": "value"}))
+		})
 	})
 
 	Describe("Get", func() {
@@ -150,6 +185,41 @@ var _ = Describe("Properties", func() {
 		})
 	})
 
+	Describe("MatchesAll with wildcards", func() {
+		BeforeEach(func() {
+			propertyManager.Set("flintstones", "tag:org", "bedrock-quarry")
+			propertyManager.Set("flintstones", "tag:space", "prod")
+			propertyManager.Set("rubbles", "tag:space", "dev")
+		})
+
+		It("matches any value of a property which exists", func() {
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "*"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("rubbles", garden.Properties{"tag:org": "*"})).To(BeFalse())
+		})
+
+		It("matches values by prefix", func() {
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "bedrock-*"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "slate-*"})).To(BeFalse())
+		})
+
+		It("matches values with wildcards anywhere in them", func() {
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "*rock*"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "b*k-q*y"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "b*k-q*x"})).To(BeFalse())
+		})
+
+		It("matches keys by prefix", func() {
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:*": "prod"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("rubbles", garden.Properties{"tag:*": "prod"})).To(BeFalse())
+			Expect(propertyManager.MatchesAll("rubbles", garden.Properties{"label:*": "*"})).To(BeFalse())
+		})
+
+		It("requires every pattern to match", func() {
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "*", "tag:space": "prod"})).To(BeTrue())
+			Expect(propertyManager.MatchesAll("flintstones", garden.Properties{"tag:org": "*", "tag:space": "dev"})).To(BeFalse())
+		})
+	})
+
 	Describe("MarshalJSON", func() {
 		It("can be saved and restored from JSON", func() {
 			mgr := properties.NewManager()
@@ -175,4 +245,156 @@ var _ = Describe("Properties", func() {
 		})
 	})
 
+	Describe("a manager with a backend", func() {
+		var (
+			backend *propertiesfakes.FakeBackend
+			logger  *lagertest.TestLogger
+		)
+
+		BeforeEach(func() {
+			backend = new(propertiesfakes.FakeBackend)
+			backend.LoadReturns(map[string]map[string]string{"stored": {"foo": "bar"}}, nil)
+			logger = lagertest.NewTestLogger("test")
+
+			var err error
+			propertyManager, err = properties.NewBackedManager(logger, backend)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("loads the stored properties", func() {
+			val, ok := propertyManager.Get("stored", "foo")
+			Expect(ok).To(BeTrue())
+			Expect(val).To(Equal("bar"))
+		})
+
+		It("writes the key space through to the backend when a property is set", func() {
+			propertyManager.Set("stored", "baz", "qux")
+
+			Expect(backend.SaveCallCount()).To(Equal(1))
+			handle, props := backend.SaveArgsForCall(0)
+			Expect(handle).To(Equal("stored"))
+			Expect(props).To(Equal(map[string]string{"foo": "bar", "baz": "qux"}))
+		})
+
+		It("writes the key space through to the backend when a property is removed", func() {
+			Expect(propertyManager.Remove("stored", "foo")).To(Succeed())
+
+			handle, props := backend.SaveArgsForCall(0)
+			Expect(handle).To(Equal("stored"))
+			Expect(props).To(BeEmpty())
+		})
+
+		It("deletes the key space from the backend when it is destroyed", func() {
+			Expect(propertyManager.DestroyKeySpace("stored")).To(Succeed())
+
+			Expect(backend.DeleteCallCount()).To(Equal(1))
+			Expect(backend.DeleteArgsForCall(0)).To(Equal("stored"))
+		})
+
+		Context("when the backend fails to save", func() {
+			BeforeEach(func() {
+				backend.SaveReturns(errors.New("consul is down"))
+			})
+
+			It("still sets the property and logs the error", func() {
+				propertyManager.Set("stored", "baz", "qux")
+
+				val, _ := propertyManager.Get("stored", "baz")
+				Expect(val).To(Equal("qux"))
+				Expect(logger.LogMessages()).To(ContainElement("test.properties.saving-failed"))
+			})
+
+			It("returns the error from Store, having set the property", func() {
+				Expect(propertyManager.Store("stored", "baz", "qux")).To(MatchError("consul is down"))
+
+				val, _ := propertyManager.Get("stored", "baz")
+				Expect(val).To(Equal("qux"))
+			})
+
+			It("returns the error from Remove", func() {
+				Expect(propertyManager.Remove("stored", "foo")).To(MatchError("consul is down"))
+			})
+		})
+
+		Context("when the backend is slow to save", func() {
+			var release chan struct{}
+
+			BeforeEach(func() {
+				release = make(chan struct{})
+				backend.SaveStub = func(handle string, props map[string]string) error {
+					if props["slow"] == "true" {
+						<-release
+					}
+					return nil
+				}
+			})
+
+			AfterEach(func() {
+				close(release)
+			})
+
+			It("does not hold up reading properties", func() {
+				go propertyManager.Set("stored", "slow", "true")
+				Eventually(backend.SaveCallCount).Should(Equal(1))
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					propertyManager.Get("stored", "foo")
+					propertyManager.MatchesAll("other", garden.Properties{"foo": "bar"})
+				}()
+				Eventually(done).Should(BeClosed())
+			})
+
+			It("does not hold up writing other key spaces", func() {
+				go propertyManager.Set("stored", "slow", "true")
+				Eventually(backend.SaveCallCount).Should(Equal(1))
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					propertyManager.Set("other", "foo", "bar")
+				}()
+				Eventually(done).Should(BeClosed())
+			})
+		})
+
+		It("writes the changes to a key space in order", func() {
+			saved := make(chan struct{})
+			release := make(chan struct{})
+			backend.SaveStub = func(handle string, props map[string]string) error {
+				if props["first"] == "true" && props["second"] == "" {
+					close(saved)
+					<-release
+				}
+				return nil
+			}
+
+			go propertyManager.Set("stored", "first", "true")
+			Eventually(saved).Should(BeClosed())
+
+			// the second change is made while the first is being written, so it
+			// waits for the first to reach the backend
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				propertyManager.Set("stored", "second", "true")
+			}()
+			Consistently(done).ShouldNot(BeClosed())
+			close(release)
+			Eventually(done).Should(BeClosed())
+
+			_, props := backend.SaveArgsForCall(backend.SaveCallCount() - 1)
+			Expect(props).To(HaveKeyWithValue("second", "true"))
+		})
+
+		Context("when the backend fails to load", func() {
+			It("returns the error", func() {
+				backend.LoadReturns(nil, errors.New("consul is down"))
+
+				_, err := properties.NewBackedManager(logger, backend)
+				Expect(err).To(MatchError("consul is down"))
+			})
+		})
+	})
 })
@@ -0,0 +1,241 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-golang/clock"
+)
+
+const (
+	metadataFile = "quarantine.json"
+
+	// maxFileSize is the largest file which is kept, so that a container
+	// which filled its depot directory cannot fill the quarantine too
+	maxFileSize = 16 * 1024 * 1024
+)
+
+// Store keeps a copy of the depot directory of failed containers (their
+// bundle, process state and logs) in a quarantine directory, and removes
+// them once they are older than the retention period or there are more than
+// MaxEntries of them. Expired containers are removed at startup, and whenever
+// a container is quarantined or the quarantine is listed.
+type Store struct {
+	log      lager.Logger
+	depotDir string
+	dir      string
+	clock    clock.Clock
+
+	// Retention is how long quarantined containers are kept. Zero means
+	// they are kept until there are too many.
+	Retention time.Duration
+
+	// MaxEntries is the most quarantined containers to keep, the oldest
+	// being removed first. Zero means no limit.
+	MaxEntries int
+
+	pruneMutex sync.Mutex
+}
+
+func NewStore(log lager.Logger, depotDir, dir string, retention time.Duration, maxEntries int, clock clock.Clock) *Store {
+	return &Store{
+		log:        log,
+		depotDir:   depotDir,
+		dir:        dir,
+		clock:      clock,
+		Retention:  retention,
+		MaxEntries: maxEntries,
+	}
+}
+
+// Start creates the quarantine directory and removes the quarantined
+// containers which expired while the server was down
+func (s *Store) Start() error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating quarantine directory: %s", err)
+	}
+
+	_, err := s.prune(s.log.Session("quarantine-start"))
+	return err
+}
+
+// Quarantine copies the depot directory of the container into a new entry
+// in the quarantine directory, with a record of why it was quarantined. A
+// partly written entry is removed if quarantining fails.
+func (s *Store) Quarantine(log lager.Logger, handle, reason string) error {
+	log = log.Session("quarantine", lager.Data{"handle": handle})
+
+	now := s.clock.Now()
+	entryDir := filepath.Join(s.dir, fmt.Sprintf("%s-%d", handle, now.UnixNano()))
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("quarantining container '%s': %s", handle, err)
+	}
+
+	// the entry must be new, so that removing it on failure cannot remove
+	// an earlier entry of the same container
+	if err := os.Mkdir(entryDir, 0700); err != nil {
+		return fmt.Errorf("quarantining container '%s': %s", handle, err)
+	}
+
+	if err := s.writeEntry(log, entryDir, gardener.QuarantinedContainer{
+		Handle:        handle,
+		Reason:        reason,
+		QuarantinedAt: now,
+	}); err != nil {
+		if removeErr := os.RemoveAll(entryDir); removeErr != nil {
+			log.Error("removing-partial-entry-failed", removeErr, lager.Data{"path": entryDir})
+		}
+		return fmt.Errorf("quarantining container '%s': %s", handle, err)
+	}
+
+	log.Info("quarantined", lager.Data{"path": entryDir})
+	_, err := s.prune(log)
+	return err
+}
+
+func (s *Store) writeEntry(log lager.Logger, entryDir string, entry gardener.QuarantinedContainer) error {
+	bundleDir := filepath.Join(s.depotDir, entry.Handle)
+	if _, err := os.Stat(bundleDir); err == nil {
+		if err := copyTree(log, bundleDir, filepath.Join(entryDir, "bundle")); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(entryDir, metadataFile), metadata, 0600)
+}
+
+// List removes the quarantined containers which have expired since the last
+// quarantine, and returns the rest, oldest first
+func (s *Store) List() ([]gardener.QuarantinedContainer, error) {
+	return s.prune(s.log.Session("quarantine-list"))
+}
+
+func (s *Store) entries() ([]gardener.QuarantinedContainer, error) {
+	entryDirs, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing quarantine: %s", err)
+	}
+
+	quarantined := []gardener.QuarantinedContainer{}
+	for _, entryDir := range entryDirs {
+		path := filepath.Join(s.dir, entryDir.Name())
+
+		contents, err := ioutil.ReadFile(filepath.Join(path, metadataFile))
+		if err != nil {
+			continue
+		}
+
+		var entry gardener.QuarantinedContainer
+		if err := json.Unmarshal(contents, &entry); err != nil {
+			continue
+		}
+
+		entry.Path = path
+		quarantined = append(quarantined, entry)
+	}
+
+	sort.Sort(byQuarantinedAt(quarantined))
+	return quarantined, nil
+}
+
+// prune removes the quarantined containers which have expired or are in
+// excess of MaxEntries, returning the ones which are kept
+func (s *Store) prune(log lager.Logger) ([]gardener.QuarantinedContainer, error) {
+	s.pruneMutex.Lock()
+	defer s.pruneMutex.Unlock()
+
+	quarantined, err := s.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := []gardener.QuarantinedContainer{}
+	for i, entry := range quarantined {
+		expired := s.Retention > 0 && s.clock.Since(entry.QuarantinedAt) > s.Retention
+		excess := s.MaxEntries > 0 && len(quarantined)-i > s.MaxEntries
+		if !expired && !excess {
+			kept = append(kept, entry)
+			continue
+		}
+
+		if err := os.RemoveAll(entry.Path); err != nil {
+			log.Error("removing-quarantined-container-failed", err, lager.Data{"path": entry.Path})
+			kept = append(kept, entry)
+			continue
+		}
+
+		log.Info("removed-quarantined-container", lager.Data{"handle": entry.Handle, "path": entry.Path})
+	}
+
+	return kept, nil
+}
+
+// copyTree copies the directories, regular files and symlinks under src to
+// dst. Anything else, such as the fifos of running processes, is skipped, as
+// are files larger than maxFileSize.
+func copyTree(log lager.Logger, src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, strings.TrimPrefix(path, src))
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			if info.Size() > maxFileSize {
+				log.Info("skipping-large-file", lager.Data{"path": path, "size": info.Size()})
+				return nil
+			}
+			return copyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = io.Copy(w, r)
+	return err
+}
+
+type byQuarantinedAt []gardener.QuarantinedContainer
+
+func (q byQuarantinedAt) Len() int           { return len(q) }
+func (q byQuarantinedAt) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q byQuarantinedAt) Less(i, j int) bool { return q[i].QuarantinedAt.Before(q[j].QuarantinedAt) }
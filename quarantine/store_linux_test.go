@@ -0,0 +1,176 @@
+package quarantine_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/guardian/quarantine"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		depotDir      string
+		quarantineDir string
+		clock         *fakeclock.FakeClock
+		logger        *lagertest.TestLogger
+		store         *quarantine.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		depotDir, err = ioutil.TempDir("", "depot")
+		Expect(err).NotTo(HaveOccurred())
+		quarantineDir, err = ioutil.TempDir("", "quarantine")
+		Expect(err).NotTo(HaveOccurred())
+
+		bundleDir := filepath.Join(depotDir, "failed-handle")
+		Expect(os.MkdirAll(filepath.Join(bundleDir, "processes", "some-process"), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bundleDir, "config.json"), []byte("some-config"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bundleDir, "processes", "some-process", "exitcode"), []byte("2"), 0600)).To(Succeed())
+		Expect(syscall.Mkfifo(filepath.Join(bundleDir, "processes", "some-process", "stdout"), 0600)).To(Succeed())
+
+		clock = fakeclock.NewFakeClock(time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC))
+		logger = lagertest.NewTestLogger("test")
+		store = quarantine.NewStore(logger, depotDir, quarantineDir, time.Hour, 3, clock)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(depotDir)).To(Succeed())
+		Expect(os.RemoveAll(quarantineDir)).To(Succeed())
+	})
+
+	It("keeps a copy of the depot directory of the container and why it was quarantined", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "create failed: boom")).To(Succeed())
+
+		quarantined, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quarantined).To(HaveLen(1))
+		Expect(quarantined[0].Handle).To(Equal("failed-handle"))
+		Expect(quarantined[0].Reason).To(Equal("create failed: boom"))
+		Expect(quarantined[0].QuarantinedAt).To(BeTemporally("==", clock.Now()))
+
+		bundleCopy := filepath.Join(quarantined[0].Path, "bundle")
+		Expect(ioutil.ReadFile(filepath.Join(bundleCopy, "config.json"))).To(Equal([]byte("some-config")))
+		Expect(ioutil.ReadFile(filepath.Join(bundleCopy, "processes", "some-process", "exitcode"))).To(Equal([]byte("2")))
+	})
+
+	It("does not copy the fifos of processes", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "create failed: boom")).To(Succeed())
+
+		quarantined, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(quarantined[0].Path, "bundle", "processes", "some-process", "stdout")).NotTo(BeAnExistingFile())
+	})
+
+	It("leaves the depot directory of the container alone", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "create failed: boom")).To(Succeed())
+		Expect(filepath.Join(depotDir, "failed-handle", "config.json")).To(BeAnExistingFile())
+	})
+
+	Context("when the container has no depot directory", func() {
+		It("still records why it was quarantined", func() {
+			Expect(store.Quarantine(logger, "early-failure", "create failed: no rootfs")).To(Succeed())
+
+			quarantined, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quarantined).To(HaveLen(1))
+			Expect(quarantined[0].Handle).To(Equal("early-failure"))
+		})
+	})
+
+	Context("when copying the depot directory fails", func() {
+		BeforeEach(func() {
+			if os.Getuid() == 0 {
+				Skip("root can read unreadable files")
+			}
+
+			Expect(os.Chmod(filepath.Join(depotDir, "failed-handle", "config.json"), 0000)).To(Succeed())
+		})
+
+		It("returns an error and removes the partly written entry", func() {
+			Expect(store.Quarantine(logger, "failed-handle", "create failed: boom")).NotTo(Succeed())
+
+			entries, err := ioutil.ReadDir(quarantineDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Context("when the container is quarantined twice at the same time", func() {
+		It("fails the second time and keeps the first entry", func() {
+			Expect(store.Quarantine(logger, "failed-handle", "first")).To(Succeed())
+			Expect(store.Quarantine(logger, "failed-handle", "second")).NotTo(Succeed())
+
+			quarantined, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quarantined).To(HaveLen(1))
+			Expect(quarantined[0].Reason).To(Equal("first"))
+		})
+	})
+
+	It("lists the quarantined containers oldest first", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "first")).To(Succeed())
+		clock.Increment(time.Minute)
+		Expect(store.Quarantine(logger, "failed-handle", "second")).To(Succeed())
+
+		quarantined, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quarantined).To(HaveLen(2))
+		Expect(quarantined[0].Reason).To(Equal("first"))
+		Expect(quarantined[1].Reason).To(Equal("second"))
+	})
+
+	It("removes quarantined containers once they are older than the retention period", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "old")).To(Succeed())
+		clock.Increment(2 * time.Hour)
+		Expect(store.Quarantine(logger, "failed-handle", "new")).To(Succeed())
+
+		quarantined, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quarantined).To(HaveLen(1))
+		Expect(quarantined[0].Reason).To(Equal("new"))
+	})
+
+	It("removes the oldest quarantined containers when there are too many", func() {
+		for _, reason := range []string{"1", "2", "3", "4"} {
+			Expect(store.Quarantine(logger, "failed-handle", reason)).To(Succeed())
+			clock.Increment(time.Second)
+		}
+
+		quarantined, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quarantined).To(HaveLen(3))
+		Expect(quarantined[0].Reason).To(Equal("2"))
+	})
+
+	Describe("Start", func() {
+		It("creates the quarantine directory", func() {
+			Expect(os.RemoveAll(quarantineDir)).To(Succeed())
+			Expect(store.Start()).To(Succeed())
+			Expect(quarantineDir).To(BeADirectory())
+		})
+
+		It("removes the quarantined containers which expired while the server was down", func() {
+			Expect(store.Quarantine(logger, "failed-handle", "old")).To(Succeed())
+			clock.Increment(2 * time.Hour)
+
+			Expect(store.Start()).To(Succeed())
+			Expect(ioutil.ReadDir(quarantineDir)).To(BeEmpty())
+		})
+	})
+
+	It("removes quarantined containers which expire between quarantines when they are listed", func() {
+		Expect(store.Quarantine(logger, "failed-handle", "old")).To(Succeed())
+		clock.Increment(2 * time.Hour)
+
+		Expect(store.List()).To(BeEmpty())
+		Expect(ioutil.ReadDir(quarantineDir)).To(BeEmpty())
+	})
+})
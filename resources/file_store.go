@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/gardener"
+)
+
+const fileExtension = ".json"
+
+// FileStore records the resources of each container in its own JSON file, so
+// that the records survive a restart of the server
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Get returns the resources recorded for the handle, or empty resources if
+// nothing has been recorded
+func (s *FileStore) Get(handle string) (gardener.ContainerResources, error) {
+	var resources gardener.ContainerResources
+
+	contents, err := ioutil.ReadFile(s.path(handle))
+	if os.IsNotExist(err) {
+		return resources, nil
+	}
+	if err != nil {
+		return resources, fmt.Errorf("reading resources for '%s': %s", handle, err)
+	}
+
+	if err := json.Unmarshal(contents, &resources); err != nil {
+		return resources, fmt.Errorf("parsing resources for '%s': %s", handle, err)
+	}
+
+	return resources, nil
+}
+
+// Set replaces the resources recorded for the handle. The record is written to
+// a temporary file and renamed into place so that a crash can never leave a
+// partially written record behind.
+func (s *FileStore) Set(handle string, resources gardener.ContainerResources) error {
+	contents, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating resource store dir: %s", err)
+	}
+
+	tmpFile, err := ioutil.TempFile(s.dir, ".tmp-"+handle)
+	if err != nil {
+		return fmt.Errorf("writing resources for '%s': %s", handle, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing resources for '%s': %s", handle, err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing resources for '%s': %s", handle, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("writing resources for '%s': %s", handle, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), s.path(handle)); err != nil {
+		return fmt.Errorf("writing resources for '%s': %s", handle, err)
+	}
+
+	return nil
+}
+
+// Remove idempotently removes the resources recorded for the handle
+func (s *FileStore) Remove(handle string) error {
+	if err := os.Remove(s.path(handle)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing resources for '%s': %s", handle, err)
+	}
+
+	return nil
+}
+
+// Handles returns every handle which has recorded resources
+func (s *FileStore) Handles() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing resource store: %s", err)
+	}
+
+	handles := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+
+		handles = append(handles, strings.TrimSuffix(name, fileExtension))
+	}
+
+	return handles, nil
+}
+
+func (s *FileStore) path(handle string) string {
+	return filepath.Join(s.dir, handle+fileExtension)
+}
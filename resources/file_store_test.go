@@ -0,0 +1,114 @@
+package resources_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/resources"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		storeDir string
+		store    *resources.FileStore
+	)
+
+	BeforeEach(func() {
+		tmpDir, err := ioutil.TempDir("", "resource-store")
+		Expect(err).NotTo(HaveOccurred())
+
+		storeDir = filepath.Join(tmpDir, "resources")
+		store = resources.NewFileStore(storeDir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(filepath.Dir(storeDir))).To(Succeed())
+	})
+
+	It("returns the resources which were set", func() {
+		expected := gardener.ContainerResources{
+			VolumePath:  "/path/to/rootfs",
+			CgroupPath:  "garden/some-handle",
+			ContainerIP: "10.0.0.2",
+			BridgeIP:    "10.0.0.1",
+			HostPorts:   []uint32{61001, 61002},
+		}
+		Expect(store.Set("some-handle", expected)).To(Succeed())
+
+		Expect(store.Get("some-handle")).To(Equal(expected))
+	})
+
+	It("persists the resources across instances", func() {
+		Expect(store.Set("some-handle", gardener.ContainerResources{VolumePath: "/rootfs"})).To(Succeed())
+
+		Expect(resources.NewFileStore(storeDir).Get("some-handle")).To(Equal(gardener.ContainerResources{VolumePath: "/rootfs"}))
+	})
+
+	It("does not leave temporary files behind", func() {
+		Expect(store.Set("some-handle", gardener.ContainerResources{})).To(Succeed())
+
+		entries, err := ioutil.ReadDir(storeDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	Context("when nothing has been recorded for the handle", func() {
+		It("returns empty resources", func() {
+			Expect(store.Get("some-handle")).To(Equal(gardener.ContainerResources{}))
+		})
+	})
+
+	Context("when the record is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(storeDir, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(storeDir, "some-handle.json"), []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, err := store.Get("some-handle")
+			Expect(err).To(MatchError(ContainSubstring("parsing resources for 'some-handle'")))
+		})
+	})
+
+	Describe("Handles", func() {
+		It("lists the handles which have recorded resources", func() {
+			Expect(store.Set("handle-1", gardener.ContainerResources{})).To(Succeed())
+			Expect(store.Set("handle-2", gardener.ContainerResources{})).To(Succeed())
+
+			Expect(store.Handles()).To(ConsistOf("handle-1", "handle-2"))
+		})
+
+		It("ignores unrelated files", func() {
+			Expect(store.Set("handle-1", gardener.ContainerResources{})).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(storeDir, ".tmp-handle-2123"), []byte("{}"), 0600)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(storeDir, "some-dir"), 0700)).To(Succeed())
+
+			Expect(store.Handles()).To(ConsistOf("handle-1"))
+		})
+
+		Context("when the store directory does not exist yet", func() {
+			It("returns no handles", func() {
+				Expect(store.Handles()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Remove", func() {
+		It("removes the recorded resources", func() {
+			Expect(store.Set("some-handle", gardener.ContainerResources{VolumePath: "/rootfs"})).To(Succeed())
+			Expect(store.Remove("some-handle")).To(Succeed())
+
+			Expect(store.Handles()).To(BeEmpty())
+			Expect(store.Get("some-handle")).To(Equal(gardener.ContainerResources{}))
+		})
+
+		It("is idempotent", func() {
+			Expect(store.Remove("some-handle")).To(Succeed())
+		})
+	})
+})
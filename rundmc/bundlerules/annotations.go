@@ -0,0 +1,42 @@
+package bundlerules
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+)
+
+const AnnotationPrefix = "org.cloudfoundry.garden."
+
+// Annotations records which container a bundle belongs to in its OCI
+// annotations, so that tools which inspect runc state can attribute
+// containers without asking garden. The handle and image URI are always
+// recorded, the tenant if TenantProperty names a property the container has,
+// each of the Properties the container has under
+// org.cloudfoundry.garden.property.<name>, and each of the container's labels
+// under org.cloudfoundry.garden.label.<name>.
+type Annotations struct {
+	TenantProperty string
+	Properties     []string
+}
+
+func (r Annotations) Apply(bndl goci.Bndl, spec spec.DesiredContainerSpec, _ string) (goci.Bndl, error) {
+	annotations := map[string]string{
+		AnnotationPrefix + "handle": spec.Handle,
+	}
+
+	if spec.ImageURI != "" {
+		annotations[AnnotationPrefix+"image"] = spec.ImageURI
+	}
+
+	if tenant, ok := spec.Properties[r.TenantProperty]; ok && r.TenantProperty != "" {
+		annotations[AnnotationPrefix+"tenant"] = tenant
+	}
+
+	for _, name := range r.Properties {
+		if value, ok := spec.Properties[name]; ok {
+			annotations[AnnotationPrefix+"property."+name] = value
+		}
+	}
+
+	return bndl.WithAnnotations(annotations).WithLabels(spec.Labels), nil
+}
@@ -0,0 +1,78 @@
+package bundlerules_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Annotations", func() {
+	var (
+		rule        bundlerules.Annotations
+		desiredSpec spec.DesiredContainerSpec
+	)
+
+	BeforeEach(func() {
+		rule = bundlerules.Annotations{}
+		desiredSpec = spec.DesiredContainerSpec{
+			Handle:   "some-handle",
+			ImageURI: "docker:///busybox",
+			Properties: garden.Properties{
+				"network.tenant-id": "some-tenant",
+				"app-guid":          "some-app",
+				"secret":            "do-not-annotate",
+			},
+		}
+	})
+
+	It("annotates the bundle with the handle and image", func() {
+		bndl, err := rule.Apply(goci.Bundle(), desiredSpec, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bndl.Annotations()).To(Equal(map[string]string{
+			"org.cloudfoundry.garden.handle": "some-handle",
+			"org.cloudfoundry.garden.image":  "docker:///busybox",
+		}))
+	})
+
+	Context("when there is a tenant property", func() {
+		BeforeEach(func() {
+			rule.TenantProperty = "network.tenant-id"
+		})
+
+		It("annotates the bundle with the tenant", func() {
+			bndl, err := rule.Apply(goci.Bundle(), desiredSpec, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bndl.Annotations()).To(HaveKeyWithValue("org.cloudfoundry.garden.tenant", "some-tenant"))
+		})
+	})
+
+	Context("when the container has labels", func() {
+		BeforeEach(func() {
+			desiredSpec.Labels = map[string]string{"team": "some-team"}
+		})
+
+		It("annotates the bundle with the labels", func() {
+			bndl, err := rule.Apply(goci.Bundle(), desiredSpec, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bndl.Annotations()).To(HaveKeyWithValue("org.cloudfoundry.garden.label.team", "some-team"))
+			Expect(bndl.Labels()).To(Equal(map[string]string{"team": "some-team"}))
+		})
+	})
+
+	Context("when properties are selected", func() {
+		BeforeEach(func() {
+			rule.Properties = []string{"app-guid", "not-set"}
+		})
+
+		It("annotates the bundle with only the selected properties the container has", func() {
+			bndl, err := rule.Apply(goci.Bundle(), desiredSpec, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bndl.Annotations()).To(HaveKeyWithValue("org.cloudfoundry.garden.property.app-guid", "some-app"))
+			Expect(bndl.Annotations()).NotTo(HaveKey("org.cloudfoundry.garden.property.not-set"))
+			Expect(bndl.Annotations()).NotTo(HaveKey("org.cloudfoundry.garden.property.secret"))
+		})
+	})
+})
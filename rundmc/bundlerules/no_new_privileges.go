@@ -0,0 +1,21 @@
+package bundlerules
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+)
+
+// NoNewPrivileges sets no_new_privs on the processes of unprivileged
+// containers, so that setuid binaries cannot be used to gain privileges
+type NoNewPrivileges struct {
+}
+
+func (r NoNewPrivileges) Apply(bndl goci.Bndl, spec spec.DesiredContainerSpec, _ string) (goci.Bndl, error) {
+	if spec.Privileged {
+		return bndl, nil
+	}
+
+	bndl = bndl.CloneProcess()
+	bndl.Spec.Process.NoNewPrivileges = true
+	return bndl, nil
+}
@@ -0,0 +1,34 @@
+package bundlerules_test
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("NoNewPrivileges", func() {
+	var bndl goci.Bndl
+
+	BeforeEach(func() {
+		bndl = goci.Bundle().WithProcess(specs.Process{Cwd: "/"})
+	})
+
+	It("sets no_new_privs for unprivileged containers", func() {
+		newBndl, err := bundlerules.NoNewPrivileges{}.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.Process().NoNewPrivileges).To(BeTrue())
+		Expect(newBndl.Process().Cwd).To(Equal("/"))
+		Expect(bndl.Process().NoNewPrivileges).To(BeFalse())
+	})
+
+	It("does not set no_new_privs for privileged containers", func() {
+		newBndl, err := bundlerules.NoNewPrivileges{}.Apply(bndl, spec.DesiredContainerSpec{Privileged: true}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.Process().NoNewPrivileges).To(BeFalse())
+	})
+})
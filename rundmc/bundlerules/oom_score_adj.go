@@ -0,0 +1,20 @@
+package bundlerules
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+)
+
+// OOMScoreAdj sets the oom_score_adj of container processes, so that the host
+// OOM killer can be made to prefer them over the daemon's helpers
+type OOMScoreAdj struct {
+	Score int
+}
+
+func (r OOMScoreAdj) Apply(bndl goci.Bndl, spec spec.DesiredContainerSpec, _ string) (goci.Bndl, error) {
+	score := r.Score
+
+	bndl = bndl.CloneProcess()
+	bndl.Spec.Process.OOMScoreAdj = &score
+	return bndl, nil
+}
@@ -0,0 +1,23 @@
+package bundlerules_test
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("OOMScoreAdj", func() {
+	It("sets the oom_score_adj of the container process", func() {
+		bndl := goci.Bundle().WithProcess(specs.Process{Cwd: "/"})
+
+		newBndl, err := bundlerules.OOMScoreAdj{Score: 500}.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(*newBndl.Process().OOMScoreAdj).To(Equal(500))
+		Expect(newBndl.Process().Cwd).To(Equal("/"))
+		Expect(bndl.Process().OOMScoreAdj).To(BeNil())
+	})
+})
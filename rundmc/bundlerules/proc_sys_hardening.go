@@ -0,0 +1,90 @@
+package bundlerules
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// ReadonlyProcPaths are the kernel tunables under /proc which ReadOnlySys
+// makes read-only along with /sys
+var ReadonlyProcPaths = []string{
+	"/proc/bus",
+	"/proc/irq",
+	"/proc/sys",
+	"/proc/sysrq-trigger",
+}
+
+// ProcSysHardening restricts what the processes of unprivileged containers can
+// see and change through /proc and /sys. Privileged containers and containers
+// whose hardening has been relaxed are left unchanged.
+type ProcSysHardening struct {
+	// HidePID is the hidepid option /proc is mounted with, so that processes
+	// cannot see the processes of other users. Zero leaves it unset.
+	HidePID int
+
+	// MaskedPaths are masked in addition to those of the base bundle or
+	// security preset
+	MaskedPaths []string
+
+	// ReadOnlySys mounts /sys and everything under it read-only, and makes
+	// ReadonlyProcPaths read-only
+	ReadOnlySys bool
+}
+
+func (r ProcSysHardening) Apply(bndl goci.Bndl, spec spec.DesiredContainerSpec, _ string) (goci.Bndl, error) {
+	if spec.Privileged || spec.RelaxProcSysHardening {
+		return bndl, nil
+	}
+
+	mounts := make([]specs.Mount, 0, len(bndl.Mounts()))
+	for _, mount := range bndl.Mounts() {
+		if r.HidePID > 0 && mount.Type == "proc" && mount.Destination == "/proc" {
+			mount.Options = withOption(mount.Options, "hidepid", fmt.Sprintf("hidepid=%d", r.HidePID))
+		}
+
+		if r.ReadOnlySys && isUnder(mount.Destination, "/sys") {
+			mount.Options = withOption(withOption(mount.Options, "rw", ""), "ro", "ro")
+		}
+
+		mounts = append(mounts, mount)
+	}
+	bndl.Spec.Mounts = mounts
+
+	if len(r.MaskedPaths) > 0 {
+		bndl = bndl.WithMaskedPaths(append(append([]string{}, bndl.MaskedPaths()...), r.MaskedPaths...))
+	}
+
+	if r.ReadOnlySys {
+		bndl = bndl.WithReadonlyPaths(append(append([]string{}, bndl.ReadonlyPaths()...), ReadonlyProcPaths...))
+	}
+
+	return bndl, nil
+}
+
+// withOption returns options with every option named name, with or without a
+// value, replaced by option. An empty option removes them.
+func withOption(options []string, name, option string) []string {
+	result := []string{}
+	for _, o := range options {
+		if o == name || strings.HasPrefix(o, name+"=") {
+			continue
+		}
+		result = append(result, o)
+	}
+
+	if option != "" {
+		result = append(result, option)
+	}
+
+	return result
+}
+
+func isUnder(path, dir string) bool {
+	path = filepath.Clean(path)
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
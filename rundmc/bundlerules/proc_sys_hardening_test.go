@@ -0,0 +1,100 @@
+package bundlerules_test
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("ProcSysHardening", func() {
+	var (
+		rule bundlerules.ProcSysHardening
+		bndl goci.Bndl
+	)
+
+	BeforeEach(func() {
+		rule = bundlerules.ProcSysHardening{
+			HidePID:     2,
+			MaskedPaths: []string{"/proc/meminfo"},
+			ReadOnlySys: true,
+		}
+
+		bndl = goci.Bundle().
+			WithMounts(
+				specs.Mount{Type: "proc", Source: "proc", Destination: "/proc", Options: []string{"nosuid", "hidepid=0"}},
+				specs.Mount{Type: "sysfs", Source: "sysfs", Destination: "/sys", Options: []string{"nosuid", "rw"}},
+				specs.Mount{Type: "cgroup", Source: "cgroup", Destination: "/sys/fs/cgroup", Options: []string{"nosuid"}},
+				specs.Mount{Type: "bind", Source: "/src", Destination: "/system", Options: []string{"bind", "rw"}},
+			).
+			WithMaskedPaths([]string{"/proc/kcore"})
+	})
+
+	It("mounts /proc with the hidepid option", func() {
+		newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.Mounts()[0].Options).To(Equal([]string{"nosuid", "hidepid=2"}))
+	})
+
+	It("masks the paths in addition to those of the bundle", func() {
+		newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.MaskedPaths()).To(Equal([]string{"/proc/kcore", "/proc/meminfo"}))
+	})
+
+	It("mounts /sys and the mounts under it read-only", func() {
+		newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.Mounts()[1].Options).To(Equal([]string{"nosuid", "ro"}))
+		Expect(newBndl.Mounts()[2].Options).To(Equal([]string{"nosuid", "ro"}))
+		Expect(newBndl.Mounts()[3].Options).To(Equal([]string{"bind", "rw"}))
+	})
+
+	It("makes the kernel tunables under /proc read-only", func() {
+		newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.ReadonlyPaths()).To(Equal(bundlerules.ReadonlyProcPaths))
+	})
+
+	It("does not modify the original bundle", func() {
+		_, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bndl.Mounts()[0].Options).To(Equal([]string{"nosuid", "hidepid=0"}))
+		Expect(bndl.MaskedPaths()).To(Equal([]string{"/proc/kcore"}))
+		Expect(bndl.ReadonlyPaths()).To(BeEmpty())
+	})
+
+	Context("when no options are set", func() {
+		It("leaves the bundle unchanged", func() {
+			newBndl, err := bundlerules.ProcSysHardening{}.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(newBndl).To(Equal(bndl))
+		})
+	})
+
+	Context("when the container is privileged", func() {
+		It("leaves the bundle unchanged", func() {
+			newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{Privileged: true}, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(newBndl).To(Equal(bndl))
+		})
+	})
+
+	Context("when the container's hardening is relaxed", func() {
+		It("leaves the bundle unchanged", func() {
+			newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{RelaxProcSysHardening: true}, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(newBndl).To(Equal(bndl))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package bundlerules
+
+import (
+	"fmt"
+
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// SecurityPreset is a coordinated set of the settings which confine the
+// processes of an unprivileged container
+type SecurityPreset struct {
+	Capabilities    []string
+	Seccomp         *specs.LinuxSeccomp
+	MaskedPaths     []string
+	NoNewPrivileges bool
+}
+
+// SecurityPresets applies the named preset requested for an unprivileged
+// container in place of the settings of the unprivileged base bundle.
+// Privileged containers and containers without a preset are left unchanged.
+type SecurityPresets struct {
+	Presets map[string]SecurityPreset
+}
+
+func (r SecurityPresets) Apply(bndl goci.Bndl, spec spec.DesiredContainerSpec, _ string) (goci.Bndl, error) {
+	if spec.Privileged || spec.SecurityPreset == "" {
+		return bndl, nil
+	}
+
+	preset, ok := r.Presets[spec.SecurityPreset]
+	if !ok {
+		return goci.Bndl{}, fmt.Errorf("unknown security preset: %s", spec.SecurityPreset)
+	}
+
+	bndl = bndl.WithMaskedPaths(preset.MaskedPaths)
+	bndl.Spec.Linux.Seccomp = preset.Seccomp
+
+	bndl = bndl.CloneProcess()
+	bndl.Spec.Process.NoNewPrivileges = preset.NoNewPrivileges
+	bndl.Spec.Process.Capabilities = &specs.LinuxCapabilities{
+		Effective:   preset.Capabilities,
+		Bounding:    preset.Capabilities,
+		Inheritable: preset.Capabilities,
+		Permitted:   preset.Capabilities,
+	}
+
+	return bndl, nil
+}
@@ -0,0 +1,80 @@
+package bundlerules_test
+
+import (
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("SecurityPresets", func() {
+	var (
+		rule    bundlerules.SecurityPresets
+		seccomp *specs.LinuxSeccomp
+		bndl    goci.Bndl
+	)
+
+	BeforeEach(func() {
+		seccomp = &specs.LinuxSeccomp{DefaultAction: specs.ActErrno}
+		rule = bundlerules.SecurityPresets{
+			Presets: map[string]bundlerules.SecurityPreset{
+				"restricted": {
+					Capabilities:    []string{"CAP_KILL"},
+					Seccomp:         seccomp,
+					MaskedPaths:     []string{"/proc/kcore", "/proc/keys"},
+					NoNewPrivileges: true,
+				},
+			},
+		}
+
+		bndl = goci.Bundle().
+			WithProcess(specs.Process{}).
+			WithCapabilities("CAP_KILL", "CAP_MKNOD").
+			WithMaskedPaths([]string{"/proc/kcore"})
+	})
+
+	It("applies the settings of the requested preset", func() {
+		newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{SecurityPreset: "restricted"}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newBndl.Capabilities()).To(Equal([]string{"CAP_KILL"}))
+		Expect(newBndl.Process().Capabilities.Effective).To(Equal([]string{"CAP_KILL"}))
+		Expect(newBndl.Spec.Linux.Seccomp).To(Equal(seccomp))
+		Expect(newBndl.MaskedPaths()).To(Equal([]string{"/proc/kcore", "/proc/keys"}))
+		Expect(newBndl.Process().NoNewPrivileges).To(BeTrue())
+	})
+
+	It("does not modify the original bundle", func() {
+		_, err := rule.Apply(bndl, spec.DesiredContainerSpec{SecurityPreset: "restricted"}, "not-needed-path")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bndl.Capabilities()).To(Equal([]string{"CAP_KILL", "CAP_MKNOD"}))
+		Expect(bndl.MaskedPaths()).To(Equal([]string{"/proc/kcore"}))
+		Expect(bndl.Process().NoNewPrivileges).To(BeFalse())
+	})
+
+	Context("when no preset is requested", func() {
+		It("leaves the bundle unchanged", func() {
+			newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{}, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newBndl).To(Equal(bndl))
+		})
+	})
+
+	Context("when the container is privileged", func() {
+		It("leaves the bundle unchanged", func() {
+			newBndl, err := rule.Apply(bndl, spec.DesiredContainerSpec{Privileged: true, SecurityPreset: "restricted"}, "not-needed-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newBndl).To(Equal(bndl))
+		})
+	})
+
+	Context("when the preset is unknown", func() {
+		It("returns an error", func() {
+			_, err := rule.Apply(bndl, spec.DesiredContainerSpec{SecurityPreset: "anything-goes"}, "not-needed-path")
+			Expect(err).To(MatchError("unknown security preset: anything-goes"))
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package cgroups
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// Remover removes containers' cgroups from the hierarchies mounted beneath
+// CgroupRoot
+type Remover struct {
+	CgroupRoot string
+}
+
+// RemoveCgroup removes the cgroup at cgroupPath, and any cgroups beneath it,
+// in every hierarchy, deepest first, as the kernel only removes empty cgroups.
+// Hierarchies without the cgroup are skipped.
+func (r Remover) RemoveCgroup(log lager.Logger, cgroupPath string) error {
+	if clean := filepath.Clean("/" + cgroupPath); clean == "/" {
+		return fmt.Errorf("refusing to remove the root cgroup: '%s'", cgroupPath)
+	}
+
+	hierarchies, err := ioutil.ReadDir(r.CgroupRoot)
+	if err != nil {
+		return fmt.Errorf("listing cgroup hierarchies: %s", err)
+	}
+
+	for _, hierarchy := range hierarchies {
+		containerCgroup := filepath.Join(r.CgroupRoot, hierarchy.Name(), cgroupPath)
+
+		var cgroups []string
+		filepath.Walk(containerCgroup, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				cgroups = append(cgroups, path)
+			}
+			return nil
+		})
+
+		sort.Sort(sort.Reverse(sort.StringSlice(cgroups)))
+		for _, cgroup := range cgroups {
+			if err := os.Remove(cgroup); err != nil && !os.IsNotExist(err) {
+				log.Error("remove-cgroup-failed", err, lager.Data{"cgroup": cgroup})
+				return fmt.Errorf("removing cgroup '%s': %s", cgroup, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package cgroups_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/rundmc/cgroups"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remover", func() {
+	var (
+		cgroupRoot string
+		remover    cgroups.Remover
+	)
+
+	BeforeEach(func() {
+		var err error
+		cgroupRoot, err = ioutil.TempDir("", "cgroup-remover")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(cgroupRoot, "memory", "garden", "some-handle", "process", "nested"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(cgroupRoot, "cpu", "garden", "some-handle"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(cgroupRoot, "devices", "garden", "other-handle"), 0755)).To(Succeed())
+
+		remover = cgroups.Remover{CgroupRoot: cgroupRoot}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(cgroupRoot)).To(Succeed())
+	})
+
+	It("removes the cgroup and the cgroups beneath it from every hierarchy", func() {
+		Expect(remover.RemoveCgroup(lagertest.NewTestLogger("test"), "/garden/some-handle")).To(Succeed())
+
+		Expect(filepath.Join(cgroupRoot, "memory", "garden", "some-handle")).NotTo(BeADirectory())
+		Expect(filepath.Join(cgroupRoot, "cpu", "garden", "some-handle")).NotTo(BeADirectory())
+		Expect(filepath.Join(cgroupRoot, "memory", "garden")).To(BeADirectory())
+		Expect(filepath.Join(cgroupRoot, "devices", "garden", "other-handle")).To(BeADirectory())
+	})
+
+	It("refuses to remove the root cgroup", func() {
+		Expect(remover.RemoveCgroup(lagertest.NewTestLogger("test"), "/")).To(MatchError(ContainSubstring("root cgroup")))
+		Expect(filepath.Join(cgroupRoot, "memory", "garden")).To(BeADirectory())
+	})
+})
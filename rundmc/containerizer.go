@@ -3,7 +3,10 @@ package rundmc
 import (
 	"fmt"
 	"io"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -17,20 +20,28 @@ import (
 	"github.com/cloudfoundry/dropsonde/metrics"
 )
 
+// StopEscalatedEvent is the event reported when a stop has to KILL processes
+// which were still running after the kill timeout
+const StopEscalatedEvent = "Killed after stop timeout"
+
 //go:generate counterfeiter . Depot
 //go:generate counterfeiter . OCIRuntime
 //go:generate counterfeiter . NstarRunner
 //go:generate counterfeiter . EventStore
 //go:generate counterfeiter . BundleLoader
+//go:generate counterfeiter . BundleSaver
 //go:generate counterfeiter . Stopper
 //go:generate counterfeiter . StateStore
 //go:generate counterfeiter . RootfsFileCreator
 //go:generate counterfeiter . PeaCreator
 //go:generate counterfeiter . PeaUsernameResolver
+//go:generate counterfeiter . ProcessCgroupRemover
 
 type Depot interface {
 	Create(log lager.Logger, handle string, desiredContainerSpec spec.DesiredContainerSpec) error
+	Preview(log lager.Logger, handle string, desiredContainerSpec spec.DesiredContainerSpec) (goci.Bndl, error)
 	Lookup(log lager.Logger, handle string) (path string, err error)
+	BundlePath(handle string) string
 	Destroy(log lager.Logger, handle string) error
 	Handles() ([]string, error)
 }
@@ -39,6 +50,10 @@ type BundleLoader interface {
 	Load(path string) (goci.Bndl, error)
 }
 
+type BundleSaver interface {
+	Save(bundle goci.Bndl, path string) error
+}
+
 type OCIRuntime interface {
 	Create(log lager.Logger, bundlePath, id string, io garden.ProcessIO) error
 	Exec(log lager.Logger, bundlePath, id string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
@@ -48,6 +63,16 @@ type OCIRuntime interface {
 	State(log lager.Logger, id string) (runrunc.State, error)
 	Stats(log lager.Logger, id string) (gardener.ActualContainerMetrics, error)
 	WatchEvents(log lager.Logger, id string, eventsNotifier runrunc.EventsNotifier) error
+	List(log lager.Logger) ([]runrunc.ListedContainer, error)
+	Processes(log lager.Logger, id, bundlePath string) ([]gardener.ContainerProcess, error)
+	UpdateLimits(log lager.Logger, id string, resources specs.LinuxResources) error
+}
+
+// A ProcessCgroupRemover removes the cgroups a runtime created beneath a
+// container's cgroup for individual processes, which would otherwise outlive
+// the container
+type ProcessCgroupRemover interface {
+	RemoveProcessCgroups(log lager.Logger, cgroupsPath string) error
 }
 
 type PeaCreator interface {
@@ -60,7 +85,7 @@ type NstarRunner interface {
 }
 
 type Stopper interface {
-	StopAll(log lager.Logger, cgroupName string, save []int, kill bool) error
+	StopAll(log lager.Logger, cgroupName string, save []int, kill bool) ([]int, error)
 }
 
 type EventStore interface {
@@ -85,6 +110,8 @@ type PeaUsernameResolver interface {
 type Containerizer struct {
 	depot               Depot
 	loader              BundleLoader
+	saver               BundleSaver
+	limitsRule          BundlerRule
 	runtime             OCIRuntime
 	stopper             Stopper
 	nstar               NstarRunner
@@ -93,13 +120,34 @@ type Containerizer struct {
 	rootfsFileCreator   RootfsFileCreator
 	peaCreator          PeaCreator
 	peaUsernameResolver PeaUsernameResolver
+	lifecycle           gardener.LifecycleNotifier
+
+	destroyingMutex sync.Mutex
+	destroying      map[string]bool
+
+	liveProcessesMutex sync.Mutex
+	liveProcesses      map[string]*liveProcessCount
+}
+
+// liveProcessCount is the number of processes started in a container which
+// have not exited yet
+type liveProcessCount struct {
+	count int
 }
 
-func New(depot Depot, runtime OCIRuntime, loader BundleLoader, nstarRunner NstarRunner, stopper Stopper, events EventStore, states StateStore, rootfsFileCreator RootfsFileCreator, peaCreator PeaCreator, peaUsernameResolver PeaUsernameResolver) *Containerizer {
+// exitNotifier is implemented by processes which can report their exit
+// without being waited for, which would clean them up under their waiters
+type exitNotifier interface {
+	Exited() <-chan struct{}
+}
+
+func New(depot Depot, runtime OCIRuntime, loader BundleLoader, saver BundleSaver, limitsRule BundlerRule, nstarRunner NstarRunner, stopper Stopper, events EventStore, states StateStore, rootfsFileCreator RootfsFileCreator, peaCreator PeaCreator, peaUsernameResolver PeaUsernameResolver, lifecycle gardener.LifecycleNotifier) *Containerizer {
 	return &Containerizer{
 		depot:               depot,
 		runtime:             runtime,
 		loader:              loader,
+		saver:               saver,
+		limitsRule:          limitsRule,
 		nstar:               nstarRunner,
 		stopper:             stopper,
 		events:              events,
@@ -107,6 +155,9 @@ func New(depot Depot, runtime OCIRuntime, loader BundleLoader, nstarRunner Nstar
 		rootfsFileCreator:   rootfsFileCreator,
 		peaCreator:          peaCreator,
 		peaUsernameResolver: peaUsernameResolver,
+		lifecycle:           lifecycle,
+		destroying:          map[string]bool{},
+		liveProcesses:       map[string]*liveProcessCount{},
 	}
 }
 
@@ -138,15 +189,94 @@ func (c *Containerizer) Create(log lager.Logger, spec spec.DesiredContainerSpec)
 		return err
 	}
 
-	go func() {
-		if err := c.runtime.WatchEvents(log, spec.Handle, c.events); err != nil {
-			log.Error("watch-failed", err)
-		}
-	}()
+	go c.watch(log, spec.Handle)
 
 	return nil
 }
 
+// RestoreWatch re-attaches to the events of a container which was running
+// before the server restarted, so that its OOMs and exit are still reported
+func (c *Containerizer) RestoreWatch(log lager.Logger, handle string) {
+	log = log.Session("restore-watch", lager.Data{"handle": handle})
+
+	state, err := c.runtime.State(log, handle)
+	if err != nil {
+		log.Error("state-failed", err)
+		return
+	}
+
+	if state.Status != runrunc.RunningStatus {
+		log.Info("not-running", lager.Data{"status": state.Status})
+		return
+	}
+
+	go c.watch(log, handle)
+}
+
+// watch records the events of the container until its events stream ends,
+// and then reports that it exited if its init process has exited of its own
+// accord. The stream also ends when the container is destroyed or the events
+// cannot be read, neither of which is an exit.
+func (c *Containerizer) watch(log lager.Logger, handle string) {
+	events := &lifecycleEvents{EventStore: c.events, log: log, lifecycle: c.lifecycle}
+	if err := c.runtime.WatchEvents(log, handle, events); err != nil {
+		log.Error("watch-failed", err)
+		return
+	}
+
+	if c.isDestroying(handle) {
+		return
+	}
+
+	state, err := c.runtime.State(log, handle)
+	if err != nil {
+		log.Info("state-after-watch-failed", lager.Data{"error": err.Error()})
+		return
+	}
+
+	if state.Status != runrunc.StoppedStatus {
+		log.Info("events-ended-while-not-stopped", lager.Data{"status": state.Status})
+		return
+	}
+
+	c.lifecycle.Notify(log, gardener.LifecycleEvent{Type: gardener.ContainerExitedEvent, Handle: handle})
+}
+
+func (c *Containerizer) setDestroying(handle string, destroying bool) {
+	c.destroyingMutex.Lock()
+	defer c.destroyingMutex.Unlock()
+
+	if destroying {
+		c.destroying[handle] = true
+	} else {
+		delete(c.destroying, handle)
+	}
+}
+
+func (c *Containerizer) isDestroying(handle string) bool {
+	c.destroyingMutex.Lock()
+	defer c.destroyingMutex.Unlock()
+
+	return c.destroying[handle]
+}
+
+// Preview returns the OCI spec that Create would generate for the given spec,
+// without creating the bundle or starting the container
+func (c *Containerizer) Preview(log lager.Logger, spec spec.DesiredContainerSpec) (specs.Spec, error) {
+	log = log.Session("containerizer-preview", lager.Data{"handle": spec.Handle})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	bundle, err := c.depot.Preview(log, spec.Handle, spec)
+	if err != nil {
+		log.Error("depot-preview-failed", err)
+		return specs.Spec{}, err
+	}
+
+	return bundle.Spec, nil
+}
+
 // Run runs a process inside a running container
 func (c *Containerizer) Run(log lager.Logger, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
 	log = log.Session("run", lager.Data{"handle": handle, "path": spec.Path})
@@ -170,7 +300,13 @@ func (c *Containerizer) Run(log lager.Logger, handle string, spec garden.Process
 			spec.User = fmt.Sprintf("%d:%d", resolvedUID, resolvedGID)
 		}
 
-		return c.peaCreator.CreatePea(log, spec, io, handle, bundlePath)
+		process, err := c.peaCreator.CreatePea(log, spec, io, handle, bundlePath)
+		if err != nil {
+			return nil, err
+		}
+
+		c.countLive(handle, process)
+		return process, nil
 	}
 
 	if spec.BindMounts != nil {
@@ -179,7 +315,41 @@ func (c *Containerizer) Run(log lager.Logger, handle string, spec garden.Process
 		return nil, err
 	}
 
-	return c.runtime.Exec(log, bundlePath, handle, spec, io)
+	process, err := c.runtime.Exec(log, bundlePath, handle, spec, io)
+	if err != nil {
+		return nil, err
+	}
+
+	c.countLive(handle, process)
+	return process, nil
+}
+
+// countLive counts a process which has been started in the container as live
+// until it exits. Processes which cannot report their exit are not counted,
+// which is why the server refuses a process limit with an exec runner whose
+// processes cannot.
+func (c *Containerizer) countLive(handle string, process garden.Process) {
+	notifier, ok := process.(exitNotifier)
+	if !ok {
+		return
+	}
+
+	c.liveProcessesMutex.Lock()
+	live, ok := c.liveProcesses[handle]
+	if !ok {
+		live = &liveProcessCount{}
+		c.liveProcesses[handle] = live
+	}
+	live.count++
+	c.liveProcessesMutex.Unlock()
+
+	go func() {
+		<-notifier.Exited()
+
+		c.liveProcessesMutex.Lock()
+		live.count--
+		c.liveProcessesMutex.Unlock()
+	}()
 }
 
 func shouldResolveUsername(username string) bool {
@@ -260,15 +430,55 @@ func (c *Containerizer) Stop(log lager.Logger, handle string, kill bool) error {
 		return fmt.Errorf("stop: pid not found for container: %s", err)
 	}
 
-	if err = c.stopper.StopAll(log, handle, []int{state.Pid}, kill); err != nil {
+	// look up the garden process IDs up front, as the processes will be gone
+	// by the time we know which ones had to be killed
+	var processes []gardener.ContainerProcess
+	if !kill {
+		processes, err = c.Processes(log, handle)
+		if err != nil {
+			log.Info("list-processes-failed", lager.Data{"error": err.Error()})
+		}
+	}
+
+	escalated, err := c.stopper.StopAll(log, handle, []int{state.Pid}, kill)
+	if err != nil {
 		log.Error("stop-all-failed", err, lager.Data{"pid": state.Pid})
 		return fmt.Errorf("stop: %s", err)
 	}
 
+	if len(escalated) > 0 {
+		event := stopEscalatedEvent(escalated, processes)
+		log.Info("escalated-to-kill", lager.Data{"event": event})
+		if err := c.events.OnEvent(handle, event); err != nil {
+			log.Error("record-escalation-failed", err)
+		}
+	}
+
 	c.states.StoreStopped(handle)
 	return nil
 }
 
+// stopEscalatedEvent describes the processes which ignored TERM for the whole
+// kill timeout, by garden process ID where they have one
+func stopEscalatedEvent(pids []int, processes []gardener.ContainerProcess) string {
+	processIDs := map[int]string{}
+	for _, p := range processes {
+		processIDs[p.Pid] = p.ProcessID
+	}
+
+	var killed []string
+	for _, pid := range pids {
+		if processID := processIDs[pid]; processID != "" {
+			killed = append(killed, fmt.Sprintf("%d (%s)", pid, processID))
+			continue
+		}
+
+		killed = append(killed, strconv.Itoa(pid))
+	}
+
+	return fmt.Sprintf("%s: %s", StopEscalatedEvent, strings.Join(killed, ", "))
+}
+
 // Destroy deletes the container and the bundle directory
 func (c *Containerizer) Destroy(log lager.Logger, handle string) error {
 	log = log.Session("destroy", lager.Data{"handle": handle})
@@ -276,9 +486,15 @@ func (c *Containerizer) Destroy(log lager.Logger, handle string) error {
 	log.Info("started")
 	defer log.Info("finished")
 
+	// the init process is about to be killed, which is not an exit to report.
+	// The mark stays if the destroy fails, until a retry succeeds.
+	c.setDestroying(handle, true)
+
 	state, err := c.runtime.State(log, handle)
 	if err != nil {
 		log.Info("state-failed-skipping-delete", lager.Data{"error": err.Error()})
+		c.forgetLiveProcesses(handle)
+		c.setDestroying(handle, false)
 		return nil
 	}
 
@@ -289,22 +505,205 @@ func (c *Containerizer) Destroy(log lager.Logger, handle string) error {
 	if shouldDelete(state.Status) {
 		if err := c.runtime.Delete(log, state.Status == runrunc.RunningStatus, handle); err != nil {
 			log.Error("delete-failed", err)
-			return err
+			return c.deleteError(log, handle, err)
 		}
 	}
 
+	c.removeProcessCgroups(log, handle)
+	c.forgetLiveProcesses(handle)
+	c.setDestroying(handle, false)
 	return nil
 }
 
+// forgetLiveProcesses drops the count of a destroyed container, so that the
+// exits of its processes are not counted against a new container with the
+// same handle
+func (c *Containerizer) forgetLiveProcesses(handle string) {
+	c.liveProcessesMutex.Lock()
+	defer c.liveProcessesMutex.Unlock()
+
+	delete(c.liveProcesses, handle)
+}
+
+// deleteError marks a failed delete of a container whose init process is no
+// longer running, which can be retried later without leaving anything
+// running in the meantime
+func (c *Containerizer) deleteError(log lager.Logger, handle string, err error) error {
+	state, stateErr := c.runtime.State(log, handle)
+	if stateErr != nil || state.Status != runrunc.StoppedStatus {
+		return err
+	}
+
+	return gardener.ExitedContainerDestroyError{Err: err}
+}
+
+func (c *Containerizer) removeProcessCgroups(log lager.Logger, handle string) {
+	remover, ok := c.runtime.(ProcessCgroupRemover)
+	if !ok {
+		return
+	}
+
+	bundlePath, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		log.Error("lookup-failed", err)
+		return
+	}
+
+	bundle, err := c.loader.Load(bundlePath)
+	if err != nil {
+		log.Error("load-bundle-failed", err)
+		return
+	}
+
+	if bundle.Spec.Linux == nil || bundle.CGroupPath() == "" {
+		return
+	}
+
+	if err := remover.RemoveProcessCgroups(log, bundle.CGroupPath()); err != nil {
+		log.Error("remove-process-cgroups-failed", err)
+	}
+}
+
 func shouldDelete(status runrunc.Status) bool {
 	return status == runrunc.CreatedStatus || status == runrunc.StoppedStatus || status == runrunc.RunningStatus
 }
 
+// Orphans returns the ids of runtime containers which have no bundle in the
+// depot, e.g. because a previous destroy failed part way through. Only
+// containers created from the depot directory of their handle are considered,
+// so that peas (whose bundles are beneath their container's directory) and
+// containers which other tools created in the same runtime root are left alone.
+func (c *Containerizer) Orphans(log lager.Logger, knownHandles []string) ([]string, error) {
+	containers, err := c.runtime.List(log)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, handle := range knownHandles {
+		known[handle] = true
+	}
+
+	orphans := []string{}
+	for _, container := range containers {
+		if known[container.ID] {
+			continue
+		}
+
+		if filepath.Clean(container.Bundle) != c.depot.BundlePath(container.ID) {
+			log.Debug("skipping-unowned-container", lager.Data{"id": container.ID, "bundle": container.Bundle})
+			continue
+		}
+
+		orphans = append(orphans, container.ID)
+	}
+
+	return orphans, nil
+}
+
+// CleanOrphan force-deletes a runtime container which has no bundle in the depot
+func (c *Containerizer) CleanOrphan(log lager.Logger, handle string) error {
+	log = log.Session("clean-orphan", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	return c.runtime.Delete(log, true, handle)
+}
+
 func (c *Containerizer) RemoveBundle(log lager.Logger, handle string) error {
 	log = log.Session("depot", lager.Data{"handle": handle})
 	return c.depot.Destroy(log, handle)
 }
 
+// UpdateLimits applies new memory, CPU and pid limits to a running container.
+// runc writes the limits to the container's cgroups, and they are saved to the
+// bundle so that Info reports them. Limits which are zero are left unchanged.
+func (c *Containerizer) UpdateLimits(log lager.Logger, handle string, limits garden.Limits) error {
+	log = log.Session("update-limits", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	bundlePath, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := c.loader.Load(bundlePath)
+	if err != nil {
+		log.Error("load-bundle-failed", err)
+		return err
+	}
+
+	// the limits rule is reapplied so that derived limits, such as the CPU
+	// quota, follow the new values in the same way as they do at create
+	desiredSpec := spec.DesiredContainerSpec{Handle: handle, Limits: mergeLimits(bundleLimits(bundle), limits)}
+	bundle, err = c.limitsRule.Apply(bundle, desiredSpec, bundlePath)
+	if err != nil {
+		log.Error("apply-limits-failed", err)
+		return err
+	}
+
+	var resources specs.LinuxResources
+	if limits.Memory.LimitInBytes > 0 {
+		resources.Memory = bundle.Resources().Memory
+	}
+	if limits.CPU.LimitInShares > 0 {
+		resources.CPU = bundle.Resources().CPU
+	}
+	if limits.Pid.Max > 0 {
+		resources.Pids = bundle.Resources().Pids
+	}
+
+	if err := c.runtime.UpdateLimits(log, handle, resources); err != nil {
+		log.Error("runtime-update-limits-failed", err)
+		return err
+	}
+
+	if err := c.saver.Save(bundle, bundlePath); err != nil {
+		log.Error("save-bundle-failed", err)
+		return err
+	}
+
+	return nil
+}
+
+func bundleLimits(bundle goci.Bndl) garden.Limits {
+	var limits garden.Limits
+
+	resources := bundle.Resources()
+	if resources == nil {
+		return limits
+	}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil {
+		limits.Memory.LimitInBytes = uint64(*resources.Memory.Limit)
+	}
+	if resources.CPU != nil && resources.CPU.Shares != nil {
+		limits.CPU.LimitInShares = *resources.CPU.Shares
+	}
+	if resources.Pids != nil {
+		limits.Pid.Max = uint64(resources.Pids.Limit)
+	}
+
+	return limits
+}
+
+func mergeLimits(current, update garden.Limits) garden.Limits {
+	if update.Memory.LimitInBytes > 0 {
+		current.Memory = update.Memory
+	}
+	if update.CPU.LimitInShares > 0 {
+		current.CPU = update.CPU
+	}
+	if update.Pid.Max > 0 {
+		current.Pid = update.Pid
+	}
+
+	return current
+}
+
 func (c *Containerizer) Info(log lager.Logger, handle string) (spec.ActualContainerSpec, error) {
 	bundlePath, err := c.depot.Lookup(log, handle)
 	if err != nil {
@@ -329,11 +728,7 @@ func (c *Containerizer) Info(log lager.Logger, handle string) (spec.ActualContai
 		}
 	}
 
-	var cpuShares, limitInBytes uint64
-	if bundle.Resources() != nil {
-		cpuShares = *bundle.Resources().CPU.Shares
-		limitInBytes = uint64(*bundle.Resources().Memory.Limit)
-	} else {
+	if bundle.Resources() == nil {
 		log.Debug("bundle-resources-is-nil", lager.Data{"bundle": bundle})
 	}
 
@@ -341,17 +736,12 @@ func (c *Containerizer) Info(log lager.Logger, handle string) (spec.ActualContai
 		Pid:        state.Pid,
 		BundlePath: bundlePath,
 		RootFSPath: bundle.RootFS(),
+		CgroupPath: bundle.CGroupPath(),
 		Events:     c.events.Events(handle),
 		Stopped:    c.states.IsStopped(handle),
-		Limits: garden.Limits{
-			CPU: garden.CPULimits{
-				LimitInShares: cpuShares,
-			},
-			Memory: garden.MemoryLimits{
-				LimitInBytes: limitInBytes,
-			},
-		},
+		Limits:     bundleLimits(bundle),
 		Privileged: privileged,
+		Labels:     bundle.Labels(),
 	}, nil
 }
 
@@ -359,6 +749,34 @@ func (c *Containerizer) Metrics(log lager.Logger, handle string) (gardener.Actua
 	return c.runtime.Stats(log, handle)
 }
 
+// Processes lists the processes running in the container
+func (c *Containerizer) Processes(log lager.Logger, handle string) ([]gardener.ContainerProcess, error) {
+	log = log.Session("processes", lager.Data{"handle": handle})
+
+	bundlePath, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		log.Error("lookup-failed", err)
+		return nil, err
+	}
+
+	return c.runtime.Processes(log, handle, bundlePath)
+}
+
+// LiveProcessCount returns the number of processes this server has started in
+// the container which have not exited yet. Processes which failed to start do
+// not count, and neither do processes started before the server restarted.
+func (c *Containerizer) LiveProcessCount(log lager.Logger, handle string) (int, error) {
+	c.liveProcessesMutex.Lock()
+	defer c.liveProcessesMutex.Unlock()
+
+	live, ok := c.liveProcesses[handle]
+	if !ok {
+		return 0, nil
+	}
+
+	return live.count, nil
+}
+
 // Handles returns a list of all container handles
 func (c *Containerizer) Handles() ([]string, error) {
 	return c.depot.Handles()
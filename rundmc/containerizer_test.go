@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"path/filepath"
 	"time"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/gardenfakes"
 	"code.cloudfoundry.org/guardian/gardener"
 	specpkg "code.cloudfoundry.org/guardian/gardener/container-spec"
+	"code.cloudfoundry.org/guardian/gardener/gardenerfakes"
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/goci"
 	fakes "code.cloudfoundry.org/guardian/rundmc/rundmcfakes"
@@ -26,6 +28,8 @@ var _ = Describe("Rundmc", func() {
 	var (
 		fakeDepot               *fakes.FakeDepot
 		fakeBundleLoader        *fakes.FakeBundleLoader
+		fakeBundleSaver         *fakes.FakeBundleSaver
+		fakeLimitsRule          *fakes.FakeBundlerRule
 		fakeOCIRuntime          *fakes.FakeOCIRuntime
 		fakeNstarRunner         *fakes.FakeNstarRunner
 		fakeStopper             *fakes.FakeStopper
@@ -34,6 +38,7 @@ var _ = Describe("Rundmc", func() {
 		fakeRootfsFileCreator   *fakes.FakeRootfsFileCreator
 		fakePeaCreator          *fakes.FakePeaCreator
 		fakePeaUsernameResolver *fakes.FakePeaUsernameResolver
+		fakeLifecycleNotifier   *gardenerfakes.FakeLifecycleNotifier
 
 		logger        lager.Logger
 		containerizer *rundmc.Containerizer
@@ -43,6 +48,8 @@ var _ = Describe("Rundmc", func() {
 		fakeDepot = new(fakes.FakeDepot)
 		fakeOCIRuntime = new(fakes.FakeOCIRuntime)
 		fakeBundleLoader = new(fakes.FakeBundleLoader)
+		fakeBundleSaver = new(fakes.FakeBundleSaver)
+		fakeLimitsRule = new(fakes.FakeBundlerRule)
 		fakeNstarRunner = new(fakes.FakeNstarRunner)
 		fakeStopper = new(fakes.FakeStopper)
 		fakeEventStore = new(fakes.FakeEventStore)
@@ -50,13 +57,14 @@ var _ = Describe("Rundmc", func() {
 		fakeRootfsFileCreator = new(fakes.FakeRootfsFileCreator)
 		fakePeaCreator = new(fakes.FakePeaCreator)
 		fakePeaUsernameResolver = new(fakes.FakePeaUsernameResolver)
+		fakeLifecycleNotifier = new(gardenerfakes.FakeLifecycleNotifier)
 		logger = lagertest.NewTestLogger("test")
 
 		fakeDepot.LookupStub = func(_ lager.Logger, handle string) (string, error) {
 			return "/path/to/" + handle, nil
 		}
 
-		containerizer = rundmc.New(fakeDepot, fakeOCIRuntime, fakeBundleLoader, fakeNstarRunner, fakeStopper, fakeEventStore, fakeStateStore, fakeRootfsFileCreator, fakePeaCreator, fakePeaUsernameResolver)
+		containerizer = rundmc.New(fakeDepot, fakeOCIRuntime, fakeBundleLoader, fakeBundleSaver, fakeLimitsRule, fakeNstarRunner, fakeStopper, fakeEventStore, fakeStateStore, fakeRootfsFileCreator, fakePeaCreator, fakePeaUsernameResolver, fakeLifecycleNotifier)
 	})
 
 	Describe("Create", func() {
@@ -159,7 +167,159 @@ var _ = Describe("Rundmc", func() {
 
 			_, handle, eventsNotifier := fakeOCIRuntime.WatchEventsArgsForCall(0)
 			Expect(handle).To(Equal("some-container"))
-			Expect(eventsNotifier).To(Equal(fakeEventStore))
+
+			Expect(eventsNotifier.OnEvent("some-container", "some-event")).To(Succeed())
+			Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+			actualHandle, event := fakeEventStore.OnEventArgsForCall(0)
+			Expect(actualHandle).To(Equal("some-container"))
+			Expect(event).To(Equal("some-event"))
+		})
+
+		Describe("lifecycle events", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{Status: runrunc.StoppedStatus}, nil)
+			})
+
+			create := func() {
+				Expect(containerizer.Create(logger, specpkg.DesiredContainerSpec{
+					Handle:     "some-container",
+					BaseConfig: specs.Spec{Root: &specs.Root{}},
+				})).To(Succeed())
+			}
+
+			It("notifies when the container runs out of memory", func() {
+				fakeOCIRuntime.WatchEventsStub = func(_ lager.Logger, handle string, eventsNotifier runrunc.EventsNotifier) error {
+					return eventsNotifier.OnEvent(handle, runrunc.OOMEvent)
+				}
+
+				create()
+
+				Eventually(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(2))
+				_, event := fakeLifecycleNotifier.NotifyArgsForCall(0)
+				Expect(event).To(Equal(gardener.LifecycleEvent{Type: gardener.ContainerOOMedEvent, Handle: "some-container"}))
+				Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+			})
+
+			It("notifies when the container exits", func() {
+				create()
+
+				Eventually(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(1))
+				_, event := fakeLifecycleNotifier.NotifyArgsForCall(0)
+				Expect(event).To(Equal(gardener.LifecycleEvent{Type: gardener.ContainerExitedEvent, Handle: "some-container"}))
+			})
+
+			Context("when watching the events fails", func() {
+				BeforeEach(func() {
+					fakeOCIRuntime.WatchEventsReturns(errors.New("boom"))
+				})
+
+				It("does not report an exit", func() {
+					create()
+
+					Eventually(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(1))
+					Consistently(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(0))
+				})
+			})
+
+			Context("when the events end but the container is still running", func() {
+				BeforeEach(func() {
+					fakeOCIRuntime.StateReturns(runrunc.State{Status: runrunc.RunningStatus}, nil)
+				})
+
+				It("does not report an exit", func() {
+					create()
+
+					Eventually(fakeOCIRuntime.StateCallCount).Should(Equal(1))
+					Consistently(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(0))
+				})
+			})
+
+			Context("when the container is gone once the events end", func() {
+				BeforeEach(func() {
+					fakeOCIRuntime.StateReturns(runrunc.State{}, errors.New("container does not exist"))
+				})
+
+				It("does not report an exit", func() {
+					create()
+
+					Eventually(fakeOCIRuntime.StateCallCount).Should(Equal(1))
+					Consistently(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(0))
+				})
+			})
+
+			Context("when the container is being destroyed", func() {
+				var (
+					eventsEnd chan struct{}
+					deleted   chan struct{}
+				)
+
+				BeforeEach(func() {
+					eventsEnd = make(chan struct{})
+					deleted = make(chan struct{})
+
+					fakeOCIRuntime.WatchEventsStub = func(lager.Logger, string, runrunc.EventsNotifier) error {
+						<-eventsEnd
+						return nil
+					}
+					fakeOCIRuntime.DeleteStub = func(lager.Logger, bool, string) error {
+						close(eventsEnd)
+						<-deleted
+						return nil
+					}
+				})
+
+				It("does not report the init process being killed as an exit", func() {
+					create()
+
+					destroyed := make(chan struct{})
+					go func() {
+						defer GinkgoRecover()
+						Expect(containerizer.Destroy(logger, "some-container")).To(Succeed())
+						close(destroyed)
+					}()
+
+					Consistently(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(0))
+					close(deleted)
+					Eventually(destroyed).Should(BeClosed())
+				})
+			})
+		})
+	})
+
+	Describe("Preview", func() {
+		It("asks the depot to preview the bundle", func() {
+			spec := specpkg.DesiredContainerSpec{Handle: "exuberant!"}
+			_, err := containerizer.Preview(logger, spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDepot.PreviewCallCount()).To(Equal(1))
+			_, handle, actualSpec := fakeDepot.PreviewArgsForCall(0)
+			Expect(handle).To(Equal("exuberant!"))
+			Expect(actualSpec).To(Equal(spec))
+		})
+
+		It("returns the spec of the previewed bundle", func() {
+			fakeDepot.PreviewReturns(goci.Bndl{Spec: specs.Spec{Hostname: "exuberant!"}}, nil)
+
+			runtimeSpec, err := containerizer.Preview(logger, specpkg.DesiredContainerSpec{Handle: "exuberant!"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runtimeSpec.Hostname).To(Equal("exuberant!"))
+		})
+
+		It("does not create anything", func() {
+			_, err := containerizer.Preview(logger, specpkg.DesiredContainerSpec{Handle: "exuberant!"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDepot.CreateCallCount()).To(Equal(0))
+			Expect(fakeOCIRuntime.CreateCallCount()).To(Equal(0))
+		})
+
+		Context("when previewing the bundle fails", func() {
+			It("returns the error", func() {
+				fakeDepot.PreviewReturns(goci.Bndl{}, errors.New("blam"))
+				_, err := containerizer.Preview(logger, specpkg.DesiredContainerSpec{Handle: "exuberant!"})
+				Expect(err).To(MatchError("blam"))
+			})
 		})
 	})
 
@@ -417,9 +577,55 @@ var _ = Describe("Rundmc", func() {
 			})
 		})
 
+		It("does not record an event when nothing needed escalating to KILL", func() {
+			Expect(containerizer.Stop(logger, "some-handle", false)).To(Succeed())
+			Expect(fakeEventStore.OnEventCallCount()).To(Equal(0))
+		})
+
+		Context("when processes had to be escalated to KILL", func() {
+			BeforeEach(func() {
+				fakeDepot.LookupReturns("/path/to/bundle", nil)
+				fakeOCIRuntime.ProcessesReturns([]gardener.ContainerProcess{
+					{Pid: 1},
+					{Pid: 12, ProcessID: "some-process"},
+					{Pid: 13},
+				}, nil)
+				fakeStopper.StopAllReturns([]int{12, 13}, nil)
+			})
+
+			It("records which processes were killed as an event", func() {
+				Expect(containerizer.Stop(logger, "some-handle", false)).To(Succeed())
+
+				Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+				handle, event := fakeEventStore.OnEventArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(event).To(Equal("Killed after stop timeout: 12 (some-process), 13"))
+			})
+
+			Context("when listing the processes fails", func() {
+				BeforeEach(func() {
+					fakeOCIRuntime.ProcessesReturns(nil, errors.New("no-ps"))
+				})
+
+				It("still stops, and reports the killed processes by pid", func() {
+					Expect(containerizer.Stop(logger, "some-handle", false)).To(Succeed())
+
+					_, event := fakeEventStore.OnEventArgsForCall(0)
+					Expect(event).To(Equal("Killed after stop timeout: 12, 13"))
+				})
+			})
+		})
+
+		Context("when killing", func() {
+			It("does not list the processes", func() {
+				Expect(containerizer.Stop(logger, "some-handle", true)).To(Succeed())
+				Expect(fakeOCIRuntime.ProcessesCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the stop fails", func() {
 			BeforeEach(func() {
-				fakeStopper.StopAllReturns(errors.New("boom"))
+				fakeStopper.StopAllReturns(nil, errors.New("boom"))
 			})
 
 			It("does not transition to the stopped state", func() {
@@ -444,6 +650,57 @@ var _ = Describe("Rundmc", func() {
 		})
 	})
 
+	Describe("RestoreWatch", func() {
+		Context("when the container is running", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{Status: runrunc.RunningStatus}, nil)
+			})
+
+			It("watches the container's events again", func() {
+				containerizer.RestoreWatch(logger, "some-container")
+
+				Eventually(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(1))
+				_, handle, _ := fakeOCIRuntime.WatchEventsArgsForCall(0)
+				Expect(handle).To(Equal("some-container"))
+			})
+
+			It("notifies when the container then exits", func() {
+				fakeOCIRuntime.WatchEventsStub = func(lager.Logger, string, runrunc.EventsNotifier) error {
+					fakeOCIRuntime.StateReturns(runrunc.State{Status: runrunc.StoppedStatus}, nil)
+					return nil
+				}
+
+				containerizer.RestoreWatch(logger, "some-container")
+
+				Eventually(fakeLifecycleNotifier.NotifyCallCount).Should(Equal(1))
+				_, event := fakeLifecycleNotifier.NotifyArgsForCall(0)
+				Expect(event).To(Equal(gardener.LifecycleEvent{Type: gardener.ContainerExitedEvent, Handle: "some-container"}))
+			})
+		})
+
+		Context("when the container is not running", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{Status: runrunc.StoppedStatus}, nil)
+			})
+
+			It("does not watch it", func() {
+				containerizer.RestoreWatch(logger, "some-container")
+				Consistently(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(0))
+			})
+		})
+
+		Context("when the state of the container cannot be read", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{}, errors.New("boom"))
+			})
+
+			It("does not watch it", func() {
+				containerizer.RestoreWatch(logger, "some-container")
+				Consistently(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(0))
+			})
+		})
+	})
+
 	Describe("Destroy", func() {
 		Context("when getting state fails", func() {
 			BeforeEach(func() {
@@ -497,6 +754,12 @@ var _ = Describe("Rundmc", func() {
 				})
 
 				stateThatShouldResultInADelete(false)
+
+				It("marks a failed delete as one of an exited container", func() {
+					fakeOCIRuntime.DeleteReturns(errors.New("delete failed"))
+					err := containerizer.Destroy(logger, "some-handle")
+					Expect(err).To(Equal(gardener.ExitedContainerDestroyError{Err: errors.New("delete failed")}))
+				})
 			})
 
 			Context("when in the 'running' state", func() {
@@ -505,6 +768,54 @@ var _ = Describe("Rundmc", func() {
 				})
 
 				stateThatShouldResultInADelete(true)
+
+				Context("when delete fails", func() {
+					BeforeEach(func() {
+						fakeOCIRuntime.DeleteReturns(errors.New("delete failed"))
+					})
+
+					It("returns the error as it is while the init process is running", func() {
+						err := containerizer.Destroy(logger, "some-handle")
+						Expect(err).To(MatchError("delete failed"))
+						Expect(err).NotTo(BeAssignableToTypeOf(gardener.ExitedContainerDestroyError{}))
+					})
+
+					It("marks the failure as one of an exited container once the init process was killed", func() {
+						fakeOCIRuntime.StateReturnsOnCall(1, runrunc.State{Status: "stopped"}, nil)
+						err := containerizer.Destroy(logger, "some-handle")
+						Expect(err).To(BeAssignableToTypeOf(gardener.ExitedContainerDestroyError{}))
+					})
+				})
+			})
+		})
+
+		Context("when the runtime removes process cgroups", func() {
+			var cgroupRemover *fakes.FakeProcessCgroupRemover
+
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{Status: "running"}, nil)
+				fakeDepot.LookupReturns("/path/to/bundle", nil)
+				fakeBundleLoader.LoadReturns(goci.Bndl{Spec: specs.Spec{Linux: &specs.Linux{CgroupsPath: "/garden/some-handle"}}}, nil)
+
+				cgroupRemover = new(fakes.FakeProcessCgroupRemover)
+				runtime := struct {
+					*fakes.FakeOCIRuntime
+					*fakes.FakeProcessCgroupRemover
+				}{fakeOCIRuntime, cgroupRemover}
+				containerizer = rundmc.New(fakeDepot, runtime, fakeBundleLoader, fakeBundleSaver, fakeLimitsRule, fakeNstarRunner, fakeStopper, fakeEventStore, fakeStateStore, fakeRootfsFileCreator, fakePeaCreator, fakePeaUsernameResolver, fakeLifecycleNotifier)
+			})
+
+			It("removes the process cgroups beneath the container's cgroup", func() {
+				Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
+
+				Expect(cgroupRemover.RemoveProcessCgroupsCallCount()).To(Equal(1))
+				_, cgroupsPath := cgroupRemover.RemoveProcessCgroupsArgsForCall(0)
+				Expect(cgroupsPath).To(Equal("/garden/some-handle"))
+			})
+
+			It("does not fail the destroy when removing them fails", func() {
+				cgroupRemover.RemoveProcessCgroupsReturns(errors.New("busy"))
+				Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
 			})
 		})
 
@@ -522,6 +833,71 @@ var _ = Describe("Rundmc", func() {
 		})
 	})
 
+	Describe("Orphans", func() {
+		BeforeEach(func() {
+			fakeDepot.BundlePathStub = func(handle string) string {
+				return filepath.Join("/depot", handle)
+			}
+			fakeOCIRuntime.ListReturns([]runrunc.ListedContainer{
+				{ID: "known-handle", Bundle: "/depot/known-handle"},
+				{ID: "orphaned-handle", Bundle: "/depot/orphaned-handle/"},
+			}, nil)
+		})
+
+		It("returns the runtime containers which are not known", func() {
+			orphans, err := containerizer.Orphans(logger, []string{"known-handle", "other-handle"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans).To(ConsistOf("orphaned-handle"))
+		})
+
+		Context("when a runtime container was not created from the depot directory of its id", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.ListReturns([]runrunc.ListedContainer{
+					{ID: "some-pea", Bundle: "/depot/known-handle/processes/some-pea"},
+					{ID: "other-tool", Bundle: "/var/lib/other-tool/other-tool"},
+					{ID: "renamed", Bundle: "/depot/other-handle"},
+				}, nil)
+			})
+
+			It("is not an orphan", func() {
+				orphans, err := containerizer.Orphans(logger, []string{"known-handle"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(orphans).To(BeEmpty())
+			})
+		})
+
+		Context("when listing the runtime containers fails", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.ListReturns(nil, errors.New("list failed"))
+			})
+
+			It("returns the error", func() {
+				_, err := containerizer.Orphans(logger, []string{})
+				Expect(err).To(MatchError("list failed"))
+			})
+		})
+	})
+
+	Describe("CleanOrphan", func() {
+		It("force-deletes the runtime container", func() {
+			Expect(containerizer.CleanOrphan(logger, "orphaned-handle")).To(Succeed())
+			Expect(fakeOCIRuntime.DeleteCallCount()).To(Equal(1))
+			_, force, handle := fakeOCIRuntime.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
+			Expect(handle).To(Equal("orphaned-handle"))
+		})
+
+		Context("when deleting fails", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.DeleteReturns(errors.New("delete failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(containerizer.CleanOrphan(logger, "orphaned-handle")).To(MatchError("delete failed"))
+			})
+		})
+	})
+
 	Describe("RemoveBundle", func() {
 		It("removes the bundle from the depot", func() {
 			Expect(containerizer.RemoveBundle(logger, "some-handle")).To(Succeed())
@@ -541,6 +917,135 @@ var _ = Describe("Rundmc", func() {
 		})
 	})
 
+	Describe("UpdateLimits", func() {
+		var (
+			bundle  goci.Bndl
+			updated goci.Bndl
+		)
+
+		BeforeEach(func() {
+			bundle = goci.Bundle().
+				WithMemoryLimit(specs.LinuxMemory{Limit: int64Ptr(2048)}).
+				WithCPUShares(specs.LinuxCPU{Shares: uint64Ptr(256)}).
+				WithPidLimit(specs.LinuxPids{Limit: 100})
+			fakeBundleLoader.LoadReturns(bundle, nil)
+
+			quota := int64(51200)
+			updated = goci.Bundle().
+				WithMemoryLimit(specs.LinuxMemory{Limit: int64Ptr(1024), Swap: int64Ptr(1024)}).
+				WithCPUShares(specs.LinuxCPU{Shares: uint64Ptr(512), Quota: &quota}).
+				WithPidLimit(specs.LinuxPids{Limit: 200})
+			fakeLimitsRule.ApplyReturns(updated, nil)
+		})
+
+		It("reapplies the limits rule to the bundle with the new limits merged into the current ones", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{
+				CPU: garden.CPULimits{LimitInShares: 512},
+			})).To(Succeed())
+
+			Expect(fakeBundleLoader.LoadArgsForCall(0)).To(Equal("/path/to/some-handle"))
+			Expect(fakeLimitsRule.ApplyCallCount()).To(Equal(1))
+			actualBundle, desiredSpec, containerDir := fakeLimitsRule.ApplyArgsForCall(0)
+			Expect(actualBundle).To(Equal(bundle))
+			Expect(containerDir).To(Equal("/path/to/some-handle"))
+			Expect(desiredSpec.Limits).To(Equal(garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 2048},
+				CPU:    garden.CPULimits{LimitInShares: 512},
+				Pid:    garden.PidLimits{Max: 100},
+			}))
+		})
+
+		It("asks the runtime to apply the updated limits", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 1024},
+				CPU:    garden.CPULimits{LimitInShares: 512},
+				Pid:    garden.PidLimits{Max: 200},
+			})).To(Succeed())
+
+			Expect(fakeOCIRuntime.UpdateLimitsCallCount()).To(Equal(1))
+			_, id, resources := fakeOCIRuntime.UpdateLimitsArgsForCall(0)
+			Expect(id).To(Equal("some-handle"))
+			Expect(resources).To(Equal(*updated.Resources()))
+		})
+
+		It("only asks the runtime to apply the limits which are not zero", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{
+				CPU: garden.CPULimits{LimitInShares: 512},
+			})).To(Succeed())
+
+			_, _, resources := fakeOCIRuntime.UpdateLimitsArgsForCall(0)
+			Expect(resources.Memory).To(BeNil())
+			Expect(resources.Pids).To(BeNil())
+			Expect(*resources.CPU.Quota).To(BeEquivalentTo(51200))
+		})
+
+		It("saves the updated bundle", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{
+				CPU: garden.CPULimits{LimitInShares: 512},
+			})).To(Succeed())
+
+			Expect(fakeBundleSaver.SaveCallCount()).To(Equal(1))
+			savedBundle, path := fakeBundleSaver.SaveArgsForCall(0)
+			Expect(savedBundle).To(Equal(updated))
+			Expect(path).To(Equal("/path/to/some-handle"))
+		})
+
+		Context("when the container does not exist", func() {
+			BeforeEach(func() {
+				fakeDepot.LookupReturns("", errors.New("not found"))
+				fakeDepot.LookupStub = nil
+			})
+
+			It("returns the error without updating anything", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(MatchError("not found"))
+				Expect(fakeOCIRuntime.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when loading the bundle fails", func() {
+			BeforeEach(func() {
+				fakeBundleLoader.LoadReturns(goci.Bndl{}, errors.New("no config.json"))
+			})
+
+			It("returns the error without updating anything", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(MatchError("no config.json"))
+				Expect(fakeOCIRuntime.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when applying the limits rule fails", func() {
+			BeforeEach(func() {
+				fakeLimitsRule.ApplyReturns(goci.Bndl{}, errors.New("bad limits"))
+			})
+
+			It("returns the error without updating anything", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(MatchError("bad limits"))
+				Expect(fakeOCIRuntime.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the runtime fails to update the limits", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.UpdateLimitsReturns(errors.New("invalid argument"))
+			})
+
+			It("returns the error without saving the bundle", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(MatchError("invalid argument"))
+				Expect(fakeBundleSaver.SaveCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when saving the bundle fails", func() {
+			BeforeEach(func() {
+				fakeBundleSaver.SaveReturns(errors.New("disk full"))
+			})
+
+			It("returns the error", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(MatchError("disk full"))
+			})
+		})
+	})
+
 	Describe("Info", func() {
 		var namespaces []specs.LinuxNamespace
 		var resources *specs.LinuxResources
@@ -558,6 +1063,9 @@ var _ = Describe("Rundmc", func() {
 				CPU: &specs.LinuxCPU{
 					Shares: &shares,
 				},
+				Pids: &specs.LinuxPids{
+					Limit: 30,
+				},
 			}
 
 		})
@@ -572,20 +1080,33 @@ var _ = Describe("Rundmc", func() {
 					Spec: specs.Spec{
 						Root: &specs.Root{},
 						Linux: &specs.Linux{
-							Namespaces: namespaces,
-							Resources:  resources,
+							Namespaces:  namespaces,
+							Resources:   resources,
+							CgroupsPath: "garden/some-handle",
 						},
 					},
-				}, nil
+				}.WithLabels(map[string]string{"team": "some-team"}), nil
 			}
 		})
 
+		It("should return the ActualContainerSpec with the labels recorded in the bundle", func() {
+			actualSpec, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSpec.Labels).To(Equal(map[string]string{"team": "some-team"}))
+		})
+
 		It("should return the ActualContainerSpec with the correct bundlePath", func() {
 			actualSpec, err := containerizer.Info(logger, "some-handle")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSpec.BundlePath).To(Equal("/path/to/some-handle"))
 		})
 
+		It("should return the ActualContainerSpec with the correct cgroup path", func() {
+			actualSpec, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSpec.CgroupPath).To(Equal("garden/some-handle"))
+		})
+
 		It("should return the ActualContainerSpec with the correct CPU limits", func() {
 			actualSpec, err := containerizer.Info(logger, "some-handle")
 			Expect(err).NotTo(HaveOccurred())
@@ -598,6 +1119,12 @@ var _ = Describe("Rundmc", func() {
 			Expect(actualSpec.Limits.Memory.LimitInBytes).To(BeEquivalentTo(10))
 		})
 
+		It("should return the ActualContainerSpec with the correct pid limits", func() {
+			actualSpec, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSpec.Limits.Pid.Max).To(BeEquivalentTo(30))
+		})
+
 		It("should return the ActualContainerSpec with the correct pid", func() {
 			actualSpec, err := containerizer.Info(logger, "some-handle")
 			Expect(err).NotTo(HaveOccurred())
@@ -711,6 +1238,109 @@ var _ = Describe("Rundmc", func() {
 		})
 	})
 
+	Describe("Processes", func() {
+		BeforeEach(func() {
+			fakeDepot.LookupReturns("/path/to/bundle", nil)
+			fakeOCIRuntime.ProcessesReturns([]gardener.ContainerProcess{{Pid: 1234}}, nil)
+		})
+
+		It("asks the runtime for the processes in the container's bundle", func() {
+			Expect(containerizer.Processes(logger, "some-handle")).To(Equal([]gardener.ContainerProcess{{Pid: 1234}}))
+
+			_, handle, bundlePath := fakeOCIRuntime.ProcessesArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(bundlePath).To(Equal("/path/to/bundle"))
+		})
+
+		Context("when the bundle cannot be found", func() {
+			BeforeEach(func() {
+				fakeDepot.LookupReturns("", errors.New("not-found"))
+			})
+
+			It("returns the error", func() {
+				_, err := containerizer.Processes(logger, "some-handle")
+				Expect(err).To(MatchError("not-found"))
+				Expect(fakeOCIRuntime.ProcessesCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("LiveProcessCount", func() {
+		var (
+			exited      chan struct{}
+			peaExited   chan struct{}
+			otherExited chan struct{}
+		)
+
+		BeforeEach(func() {
+			exited = make(chan struct{})
+			peaExited = make(chan struct{})
+			otherExited = make(chan struct{})
+
+			fakeOCIRuntime.ExecReturnsOnCall(0, &exitNotifyingProcess{FakeProcess: new(gardenfakes.FakeProcess), exited: exited}, nil)
+			fakeOCIRuntime.ExecReturnsOnCall(1, &exitNotifyingProcess{FakeProcess: new(gardenfakes.FakeProcess), exited: otherExited}, nil)
+			fakePeaCreator.CreatePeaReturns(&exitNotifyingProcess{FakeProcess: new(gardenfakes.FakeProcess), exited: peaExited}, nil)
+		})
+
+		It("counts the processes started in the container until they exit", func() {
+			Expect(containerizer.LiveProcessCount(logger, "some-handle")).To(Equal(0))
+
+			_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = containerizer.Run(logger, "some-handle", garden.ProcessSpec{Image: garden.ImageRef{URI: "some-image"}, User: "1:1"}, garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = containerizer.Run(logger, "other-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerizer.LiveProcessCount(logger, "some-handle")).To(Equal(2))
+			Expect(containerizer.LiveProcessCount(logger, "other-handle")).To(Equal(1))
+
+			close(exited)
+			Eventually(func() (int, error) { return containerizer.LiveProcessCount(logger, "some-handle") }).Should(Equal(1))
+
+			close(peaExited)
+			Eventually(func() (int, error) { return containerizer.LiveProcessCount(logger, "some-handle") }).Should(Equal(0))
+			Expect(containerizer.LiveProcessCount(logger, "other-handle")).To(Equal(1))
+		})
+
+		It("does not ask the runtime for the container's processes", func() {
+			_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerizer.LiveProcessCount(logger, "some-handle")).To(Equal(1))
+			Expect(fakeOCIRuntime.ProcessesCallCount()).To(Equal(0))
+		})
+
+		Context("when a process fails to start", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.ExecReturnsOnCall(0, nil, errors.New("exec-failed"))
+			})
+
+			It("does not count it", func() {
+				_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).To(MatchError("exec-failed"))
+
+				Expect(containerizer.LiveProcessCount(logger, "some-handle")).To(Equal(0))
+			})
+		})
+
+		Context("when the container is destroyed", func() {
+			It("forgets its processes", func() {
+				_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
+				Expect(containerizer.LiveProcessCount(logger, "some-handle")).To(Equal(0))
+
+				_, err = containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				close(exited)
+				Consistently(func() (int, error) { return containerizer.LiveProcessCount(logger, "some-handle") }).Should(Equal(1))
+			})
+		})
+	})
+
 	Describe("handles", func() {
 		Context("when handles exist", func() {
 			BeforeEach(func() {
@@ -736,3 +1366,20 @@ var _ = Describe("Rundmc", func() {
 		})
 	})
 })
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func uint64Ptr(n uint64) *uint64 {
+	return &n
+}
+
+type exitNotifyingProcess struct {
+	*gardenfakes.FakeProcess
+	exited chan struct{}
+}
+
+func (p *exitNotifyingProcess) Exited() <-chan struct{} {
+	return p.exited
+}
@@ -0,0 +1,33 @@
+package depot
+
+import (
+	"fmt"
+	"syscall"
+)
+
+type OSBindMounter struct{}
+
+func (OSBindMounter) BindMountReadOnly(src, dst string) error {
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("bind mount %s: %s", src, err)
+	}
+
+	// the read-only flag is ignored when a bind mount is created, so it has to
+	// be applied with a remount
+	if err := syscall.Mount("", dst, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+		syscall.Unmount(dst, 0)
+		return fmt.Errorf("remount %s read-only: %s", dst, err)
+	}
+
+	return nil
+}
+
+// Unmount unmounts path, treating a path which is not mounted, e.g. after a
+// reboot, as already unmounted
+func (OSBindMounter) Unmount(path string) error {
+	if err := syscall.Unmount(path, 0); err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
+		return fmt.Errorf("unmount %s: %s", path, err)
+	}
+
+	return nil
+}
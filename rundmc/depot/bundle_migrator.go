@@ -0,0 +1,76 @@
+package depot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/lager"
+)
+
+// BundleMigrator upgrades the bundles in a depot which were saved by an older
+// version of guardian to the current bundle format, so that their containers
+// can still be restored and destroyed after an upgrade
+type BundleMigrator struct {
+	Logger lager.Logger
+	Dir    string
+	Loader BundleLoader
+	Saver  BundleSaver
+}
+
+// Start migrates each bundle in the depot. A bundle which cannot be migrated
+// is logged and left as it is rather than preventing the server from starting.
+func (m *BundleMigrator) Start() error {
+	log := m.Logger.Session("migrate-bundles")
+
+	fileInfos, err := ioutil.ReadDir(m.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid depot directory %s: %s", m.Dir, err)
+	}
+
+	for _, f := range fileInfos {
+		if !f.IsDir() {
+			continue
+		}
+
+		bundleLog := log.Session("migrate", lager.Data{"handle": f.Name()})
+		if err := m.migrate(bundleLog, filepath.Join(m.Dir, f.Name())); err != nil {
+			bundleLog.Error("failed", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *BundleMigrator) migrate(log lager.Logger, bundlePath string) error {
+	if _, err := os.Stat(filepath.Join(bundlePath, "config.json")); os.IsNotExist(err) {
+		return nil
+	}
+
+	version, err := goci.LoadBundleVersion(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	if version == goci.BundleVersion {
+		return nil
+	}
+
+	bundle, err := m.Loader.Load(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := goci.Migrate(bundle, version)
+	if err != nil {
+		return err
+	}
+
+	log.Info("migrating", lager.Data{"from": version, "to": goci.BundleVersion})
+	return m.Saver.Save(migrated, bundlePath)
+}
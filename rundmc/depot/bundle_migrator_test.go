@@ -0,0 +1,120 @@
+package depot_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/rundmc/depot"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("BundleMigrator", func() {
+	var (
+		depotDir string
+		logger   *lagertest.TestLogger
+		migrator *depot.BundleMigrator
+	)
+
+	BeforeEach(func() {
+		var err error
+		depotDir, err = ioutil.TempDir("", "migrator-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		migrator = &depot.BundleMigrator{
+			Logger: logger,
+			Dir:    depotDir,
+			Loader: &goci.BndlLoader{},
+			Saver:  &goci.BundleSaver{},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(depotDir)).To(Succeed())
+	})
+
+	writeBundle := func(handle, config string) string {
+		bundlePath := filepath.Join(depotDir, handle)
+		Expect(os.MkdirAll(bundlePath, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bundlePath, "config.json"), []byte(config), 0600)).To(Succeed())
+		return bundlePath
+	}
+
+	Context("when a bundle has no version", func() {
+		var bundlePath string
+
+		BeforeEach(func() {
+			bundlePath = writeBundle("old-handle", `{"ociVersion":"1.0.0-rc1","root":{"path":"some-rootfs"}}`)
+		})
+
+		It("upgrades it to the current version", func() {
+			Expect(migrator.Start()).To(Succeed())
+
+			version, err := goci.LoadBundleVersion(bundlePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(goci.BundleVersion))
+
+			bndl, err := (&goci.BndlLoader{}).Load(bundlePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bndl.Spec.Version).To(Equal("1.0.0-rc1"))
+			Expect(bndl.Spec.Root.Path).To(Equal("some-rootfs"))
+			Expect(bndl.Spec.Linux).NotTo(BeNil())
+			Expect(bndl.Spec.Process).NotTo(BeNil())
+		})
+	})
+
+	Context("when a bundle is already the current version", func() {
+		var bundlePath string
+
+		BeforeEach(func() {
+			bundlePath = writeBundle("new-handle", `{"ociVersion":"1.0.0"}`)
+			Expect(ioutil.WriteFile(filepath.Join(bundlePath, "bundle-version"), []byte("1"), 0600)).To(Succeed())
+		})
+
+		It("leaves it alone", func() {
+			Expect(migrator.Start()).To(Succeed())
+
+			contents, err := ioutil.ReadFile(filepath.Join(bundlePath, "config.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal(`{"ociVersion":"1.0.0"}`))
+		})
+	})
+
+	Context("when a bundle cannot be migrated", func() {
+		var goodBundlePath string
+
+		BeforeEach(func() {
+			writeBundle("bad-handle", "not-json")
+			goodBundlePath = writeBundle("good-handle", `{"ociVersion":"1.0.0"}`)
+		})
+
+		It("logs the failure and migrates the other bundles", func() {
+			Expect(migrator.Start()).To(Succeed())
+			Expect(logger).To(gbytes.Say("migrate.failed"))
+
+			version, err := goci.LoadBundleVersion(goodBundlePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(goci.BundleVersion))
+		})
+	})
+
+	Context("when a depot entry has no config.json", func() {
+		It("skips it", func() {
+			Expect(os.MkdirAll(filepath.Join(depotDir, "half-created"), 0700)).To(Succeed())
+			Expect(migrator.Start()).To(Succeed())
+			Expect(filepath.Join(depotDir, "half-created", "config.json")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when the depot directory does not exist", func() {
+		It("does nothing", func() {
+			migrator.Dir = filepath.Join(depotDir, "missing")
+			Expect(migrator.Start()).To(Succeed())
+		})
+	})
+})
@@ -30,12 +30,31 @@ type BindMountSourceCreator interface {
 	Create(containerDir string, privileged bool) ([]garden.BindMount, error)
 }
 
+//go:generate counterfeiter . RootfsRemounter
+type RootfsRemounter interface {
+	RemountNosuid(rootfsPath string) error
+}
+
+//go:generate counterfeiter . BindMountSharer
+type BindMountSharer interface {
+	Share(log lager.Logger, handle string, bindMounts []garden.BindMount) ([]garden.BindMount, error)
+	Release(log lager.Logger, handle string) error
+}
+
 // a depot which stores containers as subdirs of a depot directory
 type DirectoryDepot struct {
 	dir                    string
 	bundler                BundleGenerator
 	bundleSaver            BundleSaver
 	BindMountSourceCreator BindMountSourceCreator
+
+	// RootfsRemounter, if set, remounts the rootfs of unprivileged containers
+	// nosuid before their bundles are created
+	RootfsRemounter RootfsRemounter
+
+	// BindMountSharer, if set, replaces the sources of the requested bind
+	// mounts with mounts shared between containers
+	BindMountSharer BindMountSharer
 }
 
 func New(dir string, bundler BundleGenerator, bundleSaver BundleSaver, bindMountSourceCreator BindMountSourceCreator) *DirectoryDepot {
@@ -60,17 +79,32 @@ func (d *DirectoryDepot) Create(log lager.Logger, handle string, spec spec.Desir
 	}
 
 	errs := func(msg string, err error) error {
+		d.releaseOrLog(log, handle)
 		removeOrLog(log, containerDir)
 		log.Error(msg, err, lager.Data{"path": containerDir})
 		return err
 	}
 
+	if d.BindMountSharer != nil {
+		sharedBindMounts, err := d.BindMountSharer.Share(log, handle, spec.BindMounts)
+		if err != nil {
+			return errs("share-bind-mounts-failed", err)
+		}
+		spec.BindMounts = sharedBindMounts
+	}
+
 	defaultBindMounts, err := d.BindMountSourceCreator.Create(containerDir, !spec.Privileged)
 	if err != nil {
 		return errs("create-bindmount-sources-failed", err)
 	}
 	spec.BindMounts = append(spec.BindMounts, defaultBindMounts...)
 
+	if d.RootfsRemounter != nil && !spec.Privileged && spec.BaseConfig.Root != nil {
+		if err := d.RootfsRemounter.RemountNosuid(spec.BaseConfig.Root.Path); err != nil {
+			return errs("remount-rootfs-nosuid-failed", err)
+		}
+	}
+
 	bundle, err := d.bundler.Generate(spec, containerDir)
 	if err != nil {
 		return errs("generate-failed", err)
@@ -83,6 +117,24 @@ func (d *DirectoryDepot) Create(log lager.Logger, handle string, spec spec.Desir
 	return nil
 }
 
+// Preview runs the bundle rules for the given spec without touching the
+// depot. Default bind mount sources (e.g. /etc/hosts) are not created, so they
+// are absent from the resulting bundle.
+func (d *DirectoryDepot) Preview(log lager.Logger, handle string, spec spec.DesiredContainerSpec) (goci.Bndl, error) {
+	log = log.Session("depot-preview", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	bundle, err := d.bundler.Generate(spec, d.toDir(handle))
+	if err != nil {
+		log.Error("generate-failed", err)
+		return goci.Bndl{}, err
+	}
+
+	return bundle, nil
+}
+
 func (d *DirectoryDepot) Lookup(log lager.Logger, handle string) (string, error) {
 	log = log.Session("lookup", lager.Data{"handle": handle})
 
@@ -96,12 +148,25 @@ func (d *DirectoryDepot) Lookup(log lager.Logger, handle string) (string, error)
 	return d.toDir(handle), nil
 }
 
+// BundlePath returns the directory the bundle of the handle is, or was,
+// created in, whether or not it exists
+func (d *DirectoryDepot) BundlePath(handle string) string {
+	return d.toDir(handle)
+}
+
 func (d *DirectoryDepot) Destroy(log lager.Logger, handle string) error {
 	log = log.Session("destroy", lager.Data{"handle": handle})
 
 	log.Info("started")
 	defer log.Info("finished")
 
+	if d.BindMountSharer != nil {
+		if err := d.BindMountSharer.Release(log, handle); err != nil {
+			log.Error("release-bind-mounts-failed", err)
+			return err
+		}
+	}
+
 	return os.RemoveAll(d.toDir(handle))
 }
 
@@ -131,6 +196,16 @@ func (d *DirectoryDepot) toDir(handle string) string {
 	return filepath.Join(d.dir, handle)
 }
 
+func (d *DirectoryDepot) releaseOrLog(log lager.Logger, handle string) {
+	if d.BindMountSharer == nil {
+		return
+	}
+
+	if err := d.BindMountSharer.Release(log, handle); err != nil {
+		log.Error("release-bind-mounts-failed", err)
+	}
+}
+
 func removeOrLog(log lager.Logger, path string) {
 	if err := os.RemoveAll(path); err != nil {
 		log.Error("remove-failed", err, lager.Data{"path": path})
@@ -111,6 +111,93 @@ var _ = Describe("Depot", func() {
 			})
 		})
 
+		Context("when a rootfs remounter is configured", func() {
+			var rootfsRemounter *fakes.FakeRootfsRemounter
+
+			BeforeEach(func() {
+				rootfsRemounter = new(fakes.FakeRootfsRemounter)
+				dirdepot.RootfsRemounter = rootfsRemounter
+				desiredContainerSpec.BaseConfig = specs.Spec{Root: &specs.Root{Path: "/path/to/rootfs"}}
+			})
+
+			It("remounts the rootfs of unprivileged containers nosuid", func() {
+				Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).To(Succeed())
+				Expect(rootfsRemounter.RemountNosuidCallCount()).To(Equal(1))
+				Expect(rootfsRemounter.RemountNosuidArgsForCall(0)).To(Equal("/path/to/rootfs"))
+			})
+
+			It("does not remount the rootfs of privileged containers", func() {
+				desiredContainerSpec.Privileged = true
+				Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).To(Succeed())
+				Expect(rootfsRemounter.RemountNosuidCallCount()).To(Equal(0))
+			})
+
+			Context("when remounting fails", func() {
+				BeforeEach(func() {
+					rootfsRemounter.RemountNosuidReturns(errors.New("not-a-mount-point"))
+				})
+
+				It("returns the error without generating the bundle", func() {
+					Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).To(MatchError("not-a-mount-point"))
+					Expect(bundleGenerator.GenerateCallCount()).To(Equal(0))
+					Expect(filepath.Join(depotDir, "aardvaark")).NotTo(BeADirectory())
+				})
+			})
+		})
+
+		Context("when a bind mount sharer is configured", func() {
+			var (
+				sharer       *fakes.FakeBindMountSharer
+				sharedMounts []garden.BindMount
+			)
+
+			BeforeEach(func() {
+				sharer = new(fakes.FakeBindMountSharer)
+				dirdepot.BindMountSharer = sharer
+
+				desiredContainerSpec.BindMounts = []garden.BindMount{{SrcPath: "/lifecycle", DstPath: "/tmp/lifecycle"}}
+				sharedMounts = []garden.BindMount{{SrcPath: "/shared/abc/mnt", DstPath: "/tmp/lifecycle"}}
+				sharer.ShareReturns(sharedMounts, nil)
+			})
+
+			It("generates the bundle with the shared bind mounts", func() {
+				Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).To(Succeed())
+
+				Expect(sharer.ShareCallCount()).To(Equal(1))
+				_, handle, bindMounts := sharer.ShareArgsForCall(0)
+				Expect(handle).To(Equal("aardvaark"))
+				Expect(bindMounts).To(Equal(desiredContainerSpec.BindMounts))
+
+				actualDesiredSpec, _ := bundleGenerator.GenerateArgsForCall(0)
+				Expect(actualDesiredSpec.BindMounts).To(Equal(sharedMounts))
+			})
+
+			Context("when sharing fails", func() {
+				BeforeEach(func() {
+					sharer.ShareReturns(nil, errors.New("hash failed"))
+				})
+
+				It("returns the error without generating the bundle", func() {
+					Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).To(MatchError("hash failed"))
+					Expect(bundleGenerator.GenerateCallCount()).To(Equal(0))
+					Expect(filepath.Join(depotDir, "aardvaark")).NotTo(BeADirectory())
+				})
+			})
+
+			Context("when a later step fails", func() {
+				BeforeEach(func() {
+					bundleGenerator.GenerateReturns(goci.Bndl{}, errors.New("didn't work"))
+				})
+
+				It("releases the shared bind mounts", func() {
+					Expect(dirdepot.Create(logger, "aardvaark", desiredContainerSpec)).NotTo(Succeed())
+					Expect(sharer.ReleaseCallCount()).To(Equal(1))
+					_, handle := sharer.ReleaseArgsForCall(0)
+					Expect(handle).To(Equal("aardvaark"))
+				})
+			})
+		})
+
 		It("generates the bundle", func() {
 			mounts := []garden.BindMount{{
 				DstPath: "/some/dest",
@@ -157,6 +244,38 @@ var _ = Describe("Depot", func() {
 		})
 	})
 
+	Describe("preview", func() {
+		It("generates the bundle", func() {
+			bundleGenerator.GenerateReturns(bndle, nil)
+
+			bundle, err := dirdepot.Preview(logger, "aardvaark", desiredContainerSpec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bundle).To(Equal(bndle))
+
+			Expect(bundleGenerator.GenerateCallCount()).To(Equal(1))
+			actualDesiredSpec, actualContainerDir := bundleGenerator.GenerateArgsForCall(0)
+			Expect(actualDesiredSpec).To(Equal(desiredContainerSpec))
+			Expect(actualContainerDir).To(Equal(filepath.Join(depotDir, "aardvaark")))
+		})
+
+		It("does not create anything in the depot", func() {
+			_, err := dirdepot.Preview(logger, "aardvaark", desiredContainerSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(depotDir, "aardvaark")).NotTo(BeADirectory())
+			Expect(bindMountSourceCreator.CreateCallCount()).To(Equal(0))
+			Expect(bundleSaver.SaveCallCount()).To(Equal(0))
+		})
+
+		Context("when generation fails", func() {
+			It("returns the error", func() {
+				bundleGenerator.GenerateReturns(goci.Bndl{}, errors.New("didn't work"))
+				_, err := dirdepot.Preview(logger, "aardvaark", desiredContainerSpec)
+				Expect(err).To(MatchError("didn't work"))
+			})
+		})
+	})
+
 	Describe("destroy", func() {
 		It("should destroy the container directory", func() {
 			Expect(os.MkdirAll(filepath.Join(depotDir, "potato"), 0755)).To(Succeed())
@@ -169,6 +288,34 @@ var _ = Describe("Depot", func() {
 				Expect(dirdepot.Destroy(logger, "potato")).To(Succeed())
 			})
 		})
+
+		Context("when a bind mount sharer is configured", func() {
+			var sharer *fakes.FakeBindMountSharer
+
+			BeforeEach(func() {
+				sharer = new(fakes.FakeBindMountSharer)
+				dirdepot.BindMountSharer = sharer
+				Expect(os.MkdirAll(filepath.Join(depotDir, "potato"), 0755)).To(Succeed())
+			})
+
+			It("releases the shared bind mounts", func() {
+				Expect(dirdepot.Destroy(logger, "potato")).To(Succeed())
+				Expect(sharer.ReleaseCallCount()).To(Equal(1))
+				_, handle := sharer.ReleaseArgsForCall(0)
+				Expect(handle).To(Equal("potato"))
+			})
+
+			Context("when releasing fails", func() {
+				BeforeEach(func() {
+					sharer.ReleaseReturns(errors.New("device busy"))
+				})
+
+				It("returns the error and keeps the container directory", func() {
+					Expect(dirdepot.Destroy(logger, "potato")).To(MatchError("device busy"))
+					Expect(filepath.Join(depotDir, "potato")).To(BeADirectory())
+				})
+			})
+		})
 	})
 
 	Describe("handles", func() {
@@ -203,6 +350,13 @@ var _ = Describe("Depot", func() {
 		})
 	})
 
+	Describe("BundlePath", func() {
+		It("returns the directory of the handle, whether or not it exists", func() {
+			dirDepot := depot.New("/path/to/depot", nil, nil, nil)
+			Expect(dirDepot.BundlePath("some-handle")).To(Equal("/path/to/depot/some-handle"))
+		})
+	})
+
 	Describe("GetDir", func() {
 		It("returns the depot dir", func() {
 			dirDepot := depot.New("/path/to/depot", nil, nil, nil)
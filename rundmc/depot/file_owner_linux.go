@@ -0,0 +1,52 @@
+package depot
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const capabilitiesXattr = "security.capability"
+
+func copyOwner(info os.FileInfo, path string) error {
+	stat := info.Sys().(*syscall.Stat_t)
+	return os.Lchown(path, int(stat.Uid), int(stat.Gid))
+}
+
+// fileOwner returns the uid and gid which own the file, for hashing
+func fileOwner(info os.FileInfo) string {
+	stat := info.Sys().(*syscall.Stat_t)
+	return fmt.Sprintf("%d:%d", stat.Uid, stat.Gid)
+}
+
+// fileCapabilities returns the file capabilities of the regular file at path,
+// or nil if it has none
+func fileCapabilities(path string) ([]byte, error) {
+	size, err := syscall.Getxattr(path, capabilitiesXattr, nil)
+	if err == syscall.ENODATA || err == syscall.ENOTSUP {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make([]byte, size)
+	size, err = syscall.Getxattr(path, capabilitiesXattr, caps)
+	if err != nil {
+		return nil, err
+	}
+
+	return caps[:size], nil
+}
+
+// copyCapabilities gives the regular file at dst the file capabilities of the
+// one at src. It must be called after the owner is copied, as changing the
+// owner of a file drops its capabilities.
+func copyCapabilities(src, dst string) error {
+	caps, err := fileCapabilities(src)
+	if err != nil || caps == nil {
+		return err
+	}
+
+	return syscall.Setxattr(dst, capabilitiesXattr, caps, 0)
+}
@@ -0,0 +1,19 @@
+package depot
+
+import "os"
+
+func copyOwner(info os.FileInfo, path string) error {
+	return nil
+}
+
+func fileOwner(info os.FileInfo) string {
+	return ""
+}
+
+func fileCapabilities(path string) ([]byte, error) {
+	return nil, nil
+}
+
+func copyCapabilities(src, dst string) error {
+	return nil
+}
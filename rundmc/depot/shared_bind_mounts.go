@@ -0,0 +1,447 @@
+package depot
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . BindMounter
+type BindMounter interface {
+	BindMountReadOnly(src, dst string) error
+	Unmount(path string) error
+}
+
+// SharedBindMounts serves read-only bind mounts of directories with identical
+// contents, such as the lifecycle binaries which are mounted into every
+// container, from a single read-only mount of a copy of those contents which
+// guardian owns, so that no container sees later changes to the directory
+// another container asked for. The shared mounts are keyed on a hash of the
+// directory contents and are unmounted once the last container using them
+// releases them. The containers using each shared mount are recorded in Dir,
+// so they survive restarts. Each shared mount is locked separately, so that
+// hashing and copying one source does not hold up containers using others.
+type SharedBindMounts struct {
+	Dir     string
+	Mounter BindMounter
+
+	mu       sync.Mutex
+	keyLocks map[string]*keyLock
+
+	hashesMu sync.Mutex
+	hashes   map[string]cachedFileHash
+}
+
+// maxCachedFileHashes bounds the file hash cache, which is emptied once it
+// is full, so that the hashes of sources which are no longer used are dropped
+const maxCachedFileHashes = 10000
+
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+type cachedFileHash struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// Share returns the bind mounts with the sources of read-only directory mounts
+// replaced by shared mounts of the same contents, which the container with the
+// given handle then uses until it is released. Directories containing files
+// which cannot be copied, such as sockets, FIFOs and devices, are bind mounted
+// from their original source instead.
+func (s *SharedBindMounts) Share(log lager.Logger, handle string, bindMounts []garden.BindMount) ([]garden.BindMount, error) {
+	log = log.Session("share-bind-mounts", lager.Data{"handle": handle})
+
+	shared := make([]garden.BindMount, len(bindMounts))
+	for i, bindMount := range bindMounts {
+		shared[i] = bindMount
+
+		if bindMount.Mode != garden.BindMountModeRO || bindMount.Origin != garden.BindMountOriginHost {
+			continue
+		}
+
+		info, err := os.Stat(bindMount.SrcPath)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+
+		key, err := s.contentHash(bindMount.SrcPath, true)
+		if _, ok := err.(uncopyableFileError); ok {
+			log.Info("not-sharing-uncopyable-source", lager.Data{"src": bindMount.SrcPath, "reason": err.Error()})
+			continue
+		}
+		if err != nil {
+			s.release(log, handle)
+			return nil, fmt.Errorf("hashing bind mount source %s: %s", bindMount.SrcPath, err)
+		}
+
+		s.lockKey(key)
+		mountPath, err := s.acquire(log, key, bindMount.SrcPath, handle)
+		s.unlockKey(key)
+		if _, ok := err.(uncopyableFileError); ok {
+			log.Info("not-sharing-uncopyable-source", lager.Data{"src": bindMount.SrcPath, "reason": err.Error()})
+			continue
+		}
+		if err != nil {
+			s.release(log, handle)
+			return nil, err
+		}
+
+		shared[i].SrcPath = mountPath
+	}
+
+	return shared, nil
+}
+
+// Release stops the container with the given handle using any shared mounts,
+// unmounting those which no other container uses
+func (s *SharedBindMounts) Release(log lager.Logger, handle string) error {
+	log = log.Session("release-bind-mounts", lager.Data{"handle": handle})
+
+	return s.release(log, handle)
+}
+
+func (s *SharedBindMounts) lockKey(key string) {
+	s.mu.Lock()
+	if s.keyLocks == nil {
+		s.keyLocks = map[string]*keyLock{}
+	}
+	lock, ok := s.keyLocks[key]
+	if !ok {
+		lock = &keyLock{}
+		s.keyLocks[key] = lock
+	}
+	lock.waiters++
+	s.mu.Unlock()
+
+	lock.Lock()
+}
+
+func (s *SharedBindMounts) unlockKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock := s.keyLocks[key]
+	lock.Unlock()
+
+	lock.waiters--
+	if lock.waiters == 0 {
+		delete(s.keyLocks, key)
+	}
+}
+
+func (s *SharedBindMounts) acquire(log lager.Logger, key, srcPath, handle string) (string, error) {
+	sharedDir := filepath.Join(s.Dir, key)
+	mountPath := filepath.Join(sharedDir, "mnt")
+	usersDir := filepath.Join(sharedDir, "users")
+
+	if _, err := os.Stat(usersDir); os.IsNotExist(err) {
+		if err := s.mount(log, key, srcPath, sharedDir); err != nil {
+			os.RemoveAll(sharedDir)
+			return "", err
+		}
+	}
+
+	if err := touchFile(filepath.Join(usersDir, handle)); err != nil {
+		return "", fmt.Errorf("recording shared bind mount user: %s", err)
+	}
+
+	return mountPath, nil
+}
+
+// mount copies the contents of srcPath into sharedDir and mounts the copy
+// read-only. The copy must still have the contents which key hashes, in case
+// srcPath changed after it was hashed.
+func (s *SharedBindMounts) mount(log lager.Logger, key, srcPath, sharedDir string) error {
+	copyPath := filepath.Join(sharedDir, "src")
+	mountPath := filepath.Join(sharedDir, "mnt")
+
+	if err := os.MkdirAll(mountPath, 0755); err != nil {
+		return fmt.Errorf("creating shared bind mount dir: %s", err)
+	}
+
+	if err := copyDir(srcPath, copyPath); err != nil {
+		if _, ok := err.(uncopyableFileError); ok {
+			return err
+		}
+		return fmt.Errorf("copying shared bind mount source %s: %s", srcPath, err)
+	}
+
+	copyKey, err := s.contentHash(copyPath, false)
+	if err != nil {
+		return fmt.Errorf("hashing shared bind mount copy of %s: %s", srcPath, err)
+	}
+	if copyKey != key {
+		return fmt.Errorf("shared bind mount source %s changed while it was copied", srcPath)
+	}
+
+	if err := s.Mounter.BindMountReadOnly(copyPath, mountPath); err != nil {
+		return fmt.Errorf("creating shared bind mount of %s: %s", srcPath, err)
+	}
+
+	if err := os.Mkdir(filepath.Join(sharedDir, "users"), 0755); err != nil {
+		if unmountErr := s.Mounter.Unmount(mountPath); unmountErr != nil {
+			log.Error("unmount-failed", unmountErr, lager.Data{"path": mountPath})
+		}
+		return fmt.Errorf("creating shared bind mount dir: %s", err)
+	}
+
+	log.Info("mounted", lager.Data{"src": srcPath, "key": key})
+	return nil
+}
+
+// copyDir copies the directories, regular files and symlinks beneath src to
+// dst, keeping their modes, owners and file capabilities
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		switch {
+		case info.IsDir():
+			if err := os.Mkdir(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if err := copyFile(path, target, info.Mode()); err != nil {
+				return err
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		default:
+			return uncopyableFileError{path: path, mode: info.Mode()}
+		}
+
+		if err := copyOwner(info, target); err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			if err := os.Chmod(target, info.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)); err != nil {
+				return err
+			}
+		}
+
+		if info.Mode().IsRegular() {
+			return copyCapabilities(path, target)
+		}
+
+		return nil
+	})
+}
+
+// uncopyableFileError is returned for files which copyDir cannot copy, in
+// which case the source is bind mounted as it is rather than shared
+type uncopyableFileError struct {
+	path string
+	mode os.FileMode
+}
+
+func (e uncopyableFileError) Error() string {
+	return fmt.Sprintf("cannot copy %s: unsupported file type %s", e.path, e.mode.String())
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func (s *SharedBindMounts) release(log lager.Logger, handle string) error {
+	userFiles, err := filepath.Glob(filepath.Join(s.Dir, "*", "users", handle))
+	if err != nil {
+		return err
+	}
+
+	for _, userFile := range userFiles {
+		key := filepath.Base(filepath.Dir(filepath.Dir(userFile)))
+
+		s.lockKey(key)
+		err := s.releaseUser(log, userFile)
+		s.unlockKey(key)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SharedBindMounts) releaseUser(log lager.Logger, userFile string) error {
+	usersDir := filepath.Dir(userFile)
+	users, err := ioutil.ReadDir(usersDir)
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 1 {
+		if err := os.Remove(userFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	// the last user is only forgotten once the mount is gone, so that a
+	// failed release can be retried
+	sharedDir := filepath.Dir(usersDir)
+	if err := s.Mounter.Unmount(filepath.Join(sharedDir, "mnt")); err != nil {
+		log.Error("unmount-failed", err, lager.Data{"path": sharedDir})
+		return err
+	}
+
+	if err := os.RemoveAll(sharedDir); err != nil {
+		return err
+	}
+
+	log.Info("unmounted", lager.Data{"key": filepath.Base(sharedDir)})
+	return nil
+}
+
+// contentHash hashes the names, modes, owners and contents of everything in
+// dir, and the capabilities of its files, as the copy keeps all of them. If
+// cache is set the hashes of files are cached by path, size and modification
+// time, as the same large binaries are hashed for every container.
+func (s *SharedBindMounts) contentHash(dir string, cache bool) (string, error) {
+	var entries []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		entry := fmt.Sprintf("%s %s %s", relPath, info.Mode(), fileOwner(info))
+		switch {
+		case info.Mode().IsRegular():
+			fileHash, err := s.fileHash(path, info, cache)
+			if err != nil {
+				return err
+			}
+			entry += " " + fileHash
+
+			caps, err := fileCapabilities(path)
+			if err != nil {
+				return err
+			}
+			if caps != nil {
+				entry += fmt.Sprintf(" caps:%x", caps)
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			entry += " " + target
+		case !info.IsDir():
+			return uncopyableFileError{path: path, mode: info.Mode()}
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(entries)
+	hash := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintln(hash, entry)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func (s *SharedBindMounts) fileHash(path string, info os.FileInfo, cache bool) (string, error) {
+	if cache {
+		if fileHash, ok := s.cachedFileHash(path, info); ok {
+			return fileHash, nil
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	fileHash := fmt.Sprintf("%x", hash.Sum(nil))
+	if cache {
+		s.cacheFileHash(path, info, fileHash)
+	}
+	return fileHash, nil
+}
+
+func (s *SharedBindMounts) cachedFileHash(path string, info os.FileInfo) (string, bool) {
+	s.hashesMu.Lock()
+	defer s.hashesMu.Unlock()
+
+	cached, ok := s.hashes[path]
+	if !ok || cached.size != info.Size() || !cached.modTime.Equal(info.ModTime()) {
+		return "", false
+	}
+
+	return cached.hash, true
+}
+
+func (s *SharedBindMounts) cacheFileHash(path string, info os.FileInfo, fileHash string) {
+	s.hashesMu.Lock()
+	defer s.hashesMu.Unlock()
+
+	if _, ok := s.hashes[path]; !ok && len(s.hashes) >= maxCachedFileHashes {
+		s.hashes = nil
+	}
+	if s.hashes == nil {
+		s.hashes = map[string]cachedFileHash{}
+	}
+
+	s.hashes[path] = cachedFileHash{size: info.Size(), modTime: info.ModTime(), hash: fileHash}
+}
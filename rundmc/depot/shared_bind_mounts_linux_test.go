@@ -0,0 +1,110 @@
+package depot_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
+	fakes "code.cloudfoundry.org/guardian/rundmc/depot/depotfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SharedBindMounts of sources which cannot be copied", func() {
+	var (
+		tmpDir    string
+		sharedDir string
+		lifecycle string
+		mounter   *fakes.FakeBindMounter
+		shared    *depot.SharedBindMounts
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "shared-bind-mounts")
+		Expect(err).NotTo(HaveOccurred())
+
+		lifecycle = filepath.Join(tmpDir, "lifecycle")
+		Expect(os.Mkdir(lifecycle, 0755)).To(Succeed())
+		Expect(syscall.Mkfifo(filepath.Join(lifecycle, "fifo"), 0644)).To(Succeed())
+
+		sharedDir = filepath.Join(tmpDir, "shared")
+		mounter = new(fakes.FakeBindMounter)
+		shared = &depot.SharedBindMounts{Dir: sharedDir, Mounter: mounter}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("bind mounts the original source without sharing it", func() {
+		bindMounts := []garden.BindMount{{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"}}
+		result, err := shared.Share(lagertest.NewTestLogger("test"), "first", bindMounts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(bindMounts))
+		Expect(mounter.BindMountReadOnlyCallCount()).To(Equal(0))
+
+		entries, err := ioutil.ReadDir(sharedDir)
+		if !os.IsNotExist(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(entries).To(BeEmpty())
+	})
+})
+
+var _ = Describe("SharedBindMounts of sources which differ only in ownership", func() {
+	var (
+		tmpDir  string
+		mounter *fakes.FakeBindMounter
+		shared  *depot.SharedBindMounts
+	)
+
+	makeSource := func(name string, uid, gid int) string {
+		dir := filepath.Join(tmpDir, name)
+		Expect(os.Mkdir(dir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "launcher"), []byte("launcher-binary"), 0755)).To(Succeed())
+		Expect(os.Chown(filepath.Join(dir, "launcher"), uid, gid)).To(Succeed())
+		return dir
+	}
+
+	BeforeEach(func() {
+		if os.Getuid() != 0 {
+			Skip("changing the owner of the sources requires root")
+		}
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "shared-bind-mounts")
+		Expect(err).NotTo(HaveOccurred())
+
+		mounter = new(fakes.FakeBindMounter)
+		shared = &depot.SharedBindMounts{Dir: filepath.Join(tmpDir, "shared"), Mounter: mounter}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("does not share a copy between them", func() {
+		rootOwned := makeSource("root-owned", 0, 0)
+		userOwned := makeSource("user-owned", 1000, 1000)
+
+		_, err := shared.Share(lagertest.NewTestLogger("test"), "first", []garden.BindMount{{SrcPath: rootOwned, DstPath: "/tmp/lifecycle"}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = shared.Share(lagertest.NewTestLogger("test"), "second", []garden.BindMount{{SrcPath: userOwned, DstPath: "/tmp/lifecycle"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mounter.BindMountReadOnlyCallCount()).To(Equal(2))
+		firstSrc, _ := mounter.BindMountReadOnlyArgsForCall(0)
+		secondSrc, _ := mounter.BindMountReadOnlyArgsForCall(1)
+		Expect(firstSrc).NotTo(Equal(secondSrc))
+
+		info, err := os.Stat(filepath.Join(secondSrc, "launcher"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Sys().(*syscall.Stat_t).Uid).To(BeEquivalentTo(1000))
+	})
+})
@@ -0,0 +1,170 @@
+package depot_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
+	fakes "code.cloudfoundry.org/guardian/rundmc/depot/depotfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SharedBindMounts", func() {
+	var (
+		logger    *lagertest.TestLogger
+		tmpDir    string
+		sharedDir string
+		mounter   *fakes.FakeBindMounter
+		lifecycle string
+		shared    *depot.SharedBindMounts
+	)
+
+	makeDir := func(name, contents string) string {
+		dir := filepath.Join(tmpDir, name)
+		Expect(os.MkdirAll(filepath.Join(dir, "bin"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "bin", "launcher"), []byte(contents), 0755)).To(Succeed())
+		return dir
+	}
+
+	share := func(handle string, bindMounts ...garden.BindMount) []garden.BindMount {
+		result, err := shared.Share(logger, handle, bindMounts)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "shared-bind-mounts")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		sharedDir = filepath.Join(tmpDir, "shared")
+		mounter = new(fakes.FakeBindMounter)
+		lifecycle = makeDir("lifecycle", "launcher-binary")
+
+		shared = &depot.SharedBindMounts{Dir: sharedDir, Mounter: mounter}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("replaces the source of a read-only directory mount with a shared read-only mount", func() {
+		result := share("first", garden.BindMount{SrcPath: lifecycle, DstPath: "/tmp/lifecycle", Mode: garden.BindMountModeRO})
+
+		Expect(mounter.BindMountReadOnlyCallCount()).To(Equal(1))
+		_, dst := mounter.BindMountReadOnlyArgsForCall(0)
+		Expect(dst).To(HavePrefix(sharedDir))
+
+		Expect(result).To(Equal([]garden.BindMount{{SrcPath: dst, DstPath: "/tmp/lifecycle", Mode: garden.BindMountModeRO}}))
+	})
+
+	It("mounts a copy of the source which guardian owns, so later changes to the source are not shared", func() {
+		share("first", garden.BindMount{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"})
+
+		src, _ := mounter.BindMountReadOnlyArgsForCall(0)
+		Expect(src).To(HavePrefix(sharedDir))
+		Expect(ioutil.ReadFile(filepath.Join(src, "bin", "launcher"))).To(Equal([]byte("launcher-binary")))
+
+		info, err := os.Stat(filepath.Join(src, "bin", "launcher"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+
+		Expect(ioutil.WriteFile(filepath.Join(lifecycle, "bin", "launcher"), []byte("tampered"), 0755)).To(Succeed())
+		Expect(ioutil.ReadFile(filepath.Join(src, "bin", "launcher"))).To(Equal([]byte("launcher-binary")))
+	})
+
+	Context("when mounting fails", func() {
+		BeforeEach(func() {
+			mounter.BindMountReadOnlyReturns(errors.New("permission denied"))
+		})
+
+		It("returns the error and removes the copy of the source", func() {
+			_, err := shared.Share(logger, "first", []garden.BindMount{{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"}})
+			Expect(err).To(MatchError(ContainSubstring("permission denied")))
+
+			entries, err := ioutil.ReadDir(sharedDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	It("mounts sources with the same contents once when they are shared concurrently", func() {
+		var wg sync.WaitGroup
+		mountPaths := make([]string, 5)
+		for i := range mountPaths {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				mountPaths[i] = share(fmt.Sprintf("ctr-%d", i), garden.BindMount{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"})[0].SrcPath
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(mounter.BindMountReadOnlyCallCount()).To(Equal(1))
+		for _, mountPath := range mountPaths {
+			Expect(mountPath).To(Equal(mountPaths[0]))
+		}
+	})
+
+	Describe("Release", func() {
+		var mountPath string
+
+		BeforeEach(func() {
+			mountPath = share("first", garden.BindMount{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"})[0].SrcPath
+			share("second", garden.BindMount{SrcPath: lifecycle, DstPath: "/tmp/lifecycle"})
+		})
+
+		It("keeps a shared mount which other containers still use", func() {
+			Expect(shared.Release(logger, "first")).To(Succeed())
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+		})
+
+		It("unmounts a shared mount once the last container using it is released", func() {
+			Expect(shared.Release(logger, "first")).To(Succeed())
+			Expect(shared.Release(logger, "second")).To(Succeed())
+
+			Expect(mounter.UnmountCallCount()).To(Equal(1))
+			Expect(mounter.UnmountArgsForCall(0)).To(Equal(mountPath))
+			Expect(mountPath).NotTo(BeADirectory())
+		})
+
+		It("remembers the containers using a shared mount across restarts", func() {
+			restarted := &depot.SharedBindMounts{Dir: sharedDir, Mounter: mounter}
+			Expect(restarted.Release(logger, "first")).To(Succeed())
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+
+			Expect(restarted.Release(logger, "second")).To(Succeed())
+			Expect(mounter.UnmountCallCount()).To(Equal(1))
+		})
+
+		It("does nothing for a container which uses no shared mounts", func() {
+			Expect(shared.Release(logger, "unknown")).To(Succeed())
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+		})
+
+		Context("when unmounting fails", func() {
+			BeforeEach(func() {
+				mounter.UnmountReturns(errors.New("device busy"))
+			})
+
+			It("returns the error and unmounts it when the release is retried", func() {
+				Expect(shared.Release(logger, "first")).To(Succeed())
+				Expect(shared.Release(logger, "second")).To(MatchError("device busy"))
+
+				mounter.UnmountReturns(nil)
+				Expect(shared.Release(logger, "second")).To(Succeed())
+				Expect(mounter.UnmountCallCount()).To(Equal(2))
+			})
+		})
+	})
+})
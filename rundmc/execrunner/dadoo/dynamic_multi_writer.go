@@ -1,17 +1,50 @@
 package dadoo
 
 import (
+	"errors"
 	"io"
 	"sync"
+	"time"
 )
 
+const (
+	// DefaultMaxBufferedBytes is how much output each attached writer may
+	// fall behind by before it is detached
+	DefaultMaxBufferedBytes = 4 * 1024 * 1024
+
+	// DefaultCloseTimeout is how long Close waits for the attached writers to
+	// take the rest of their output before detaching them
+	DefaultCloseTimeout = 10 * time.Second
+)
+
+// ErrDetached is what a detached writer is closed with, when it can be closed
+// with an error
+var ErrDetached = errors.New("process output detached: the writer fell too far behind")
+
+// DynamicMultiWriter copies everything written to it to each attached writer.
+// Every attached writer is written to from its own goroutine through a
+// bounded buffer, so a slow or stalled writer does not block the others or
+// the process whose output is being written. A writer which falls more than
+// MaxBufferedBytes behind, or has not taken the rest of its output
+// CloseTimeout after Close, is detached and receives no further output. A
+// detached writer is closed (with ErrDetached if it has a CloseWithError
+// method, like an io.PipeWriter), so that its reader sees the end of the
+// output rather than waiting for more.
 type DynamicMultiWriter struct {
+	MaxBufferedBytes int
+	CloseTimeout     time.Duration
+
 	mutex   *sync.RWMutex
-	writers []io.Writer
+	writers []*bufferedWriter
+	closed  bool
 }
 
 func NewDynamicMultiWriter() *DynamicMultiWriter {
-	return &DynamicMultiWriter{mutex: &sync.RWMutex{}}
+	return &DynamicMultiWriter{
+		MaxBufferedBytes: DefaultMaxBufferedBytes,
+		CloseTimeout:     DefaultCloseTimeout,
+		mutex:            &sync.RWMutex{},
+	}
 }
 
 func (w *DynamicMultiWriter) Write(p []byte) (int, error) {
@@ -19,7 +52,7 @@ func (w *DynamicMultiWriter) Write(p []byte) (int, error) {
 	defer w.mutex.RUnlock()
 
 	for _, writer := range w.writers {
-		writer.Write(p)
+		writer.enqueue(p)
 	}
 
 	return len(p), nil
@@ -29,12 +62,190 @@ func (w *DynamicMultiWriter) Attach(writer io.Writer) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	w.writers = append(w.writers, writer)
+	if w.closed {
+		return
+	}
+
+	w.writers = append(w.writers, newBufferedWriter(writer, w.MaxBufferedBytes, w.remove))
 }
 
+// Count returns the number of attached writers, which does not include the
+// writers which have been detached
 func (w *DynamicMultiWriter) Count() int {
 	w.mutex.RLock()
 	defer w.mutex.RUnlock()
 
 	return len(w.writers)
 }
+
+// remove forgets a writer which has been detached
+func (w *DynamicMultiWriter) remove(detached *bufferedWriter) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for i, writer := range w.writers {
+		if writer == detached {
+			w.writers = append(w.writers[:i:i], w.writers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close waits, for at most CloseTimeout, for the output buffered for each
+// attached writer to be written to it. Writers which are still writing then
+// are detached. Writers attached after Close receive no output.
+func (w *DynamicMultiWriter) Close() error {
+	w.mutex.Lock()
+	w.closed = true
+	writers := w.writers
+	w.mutex.Unlock()
+
+	for _, writer := range writers {
+		writer.close()
+	}
+
+	timeout := time.NewTimer(w.CloseTimeout)
+	defer timeout.Stop()
+
+	for _, writer := range writers {
+		select {
+		case <-writer.done:
+		case <-timeout.C:
+			for _, writer := range writers {
+				writer.abandon()
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+type bufferedWriter struct {
+	writer      io.Writer
+	maxBuffered int
+	onDetached  func(*bufferedWriter)
+
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	chunks   [][]byte
+	buffered int
+	closed   bool
+	detached bool
+	done     chan struct{}
+
+	notifyOnce sync.Once
+}
+
+func newBufferedWriter(writer io.Writer, maxBuffered int, onDetached func(*bufferedWriter)) *bufferedWriter {
+	w := &bufferedWriter{writer: writer, maxBuffered: maxBuffered, onDetached: onDetached, done: make(chan struct{})}
+	w.cond = sync.NewCond(&w.mutex)
+
+	go w.run()
+
+	return w
+}
+
+func (w *bufferedWriter) enqueue(p []byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed || w.detached {
+		return
+	}
+
+	if w.buffered+len(p) > w.maxBuffered {
+		w.detach()
+		return
+	}
+
+	w.chunks = append(w.chunks, append([]byte{}, p...))
+	w.buffered += len(p)
+	w.cond.Signal()
+}
+
+// detach drops the buffered output and stops the writer. The caller must hold
+// the mutex.
+func (w *bufferedWriter) detach() {
+	w.detached = true
+	w.chunks = nil
+	w.buffered = 0
+	w.cond.Signal()
+}
+
+func (w *bufferedWriter) run() {
+	defer close(w.done)
+	defer func() {
+		w.mutex.Lock()
+		detached := w.detached
+		w.mutex.Unlock()
+
+		if detached {
+			w.notifyDetached()
+		}
+	}()
+
+	for {
+		w.mutex.Lock()
+		for len(w.chunks) == 0 && !w.closed && !w.detached {
+			w.cond.Wait()
+		}
+
+		if len(w.chunks) == 0 {
+			w.mutex.Unlock()
+			return
+		}
+
+		chunk := w.chunks[0]
+		w.chunks = w.chunks[1:]
+		w.buffered -= len(chunk)
+		w.mutex.Unlock()
+
+		if _, err := w.writer.Write(chunk); err != nil {
+			w.mutex.Lock()
+			w.detach()
+			w.mutex.Unlock()
+		}
+	}
+}
+
+// close stops the writer once the buffered output has been written
+func (w *bufferedWriter) close() {
+	w.mutex.Lock()
+	w.closed = true
+	w.cond.Signal()
+	w.mutex.Unlock()
+}
+
+// abandon detaches a writer which has not finished, which also unblocks a
+// write it is stuck in if the writer can be closed
+func (w *bufferedWriter) abandon() {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	w.mutex.Lock()
+	w.detach()
+	w.mutex.Unlock()
+
+	w.notifyDetached()
+}
+
+// notifyDetached closes the writer and removes it from the writers it was
+// attached to
+func (w *bufferedWriter) notifyDetached() {
+	w.notifyOnce.Do(func() {
+		defer w.onDetached(w)
+
+		switch writer := w.writer.(type) {
+		case interface {
+			CloseWithError(error) error
+		}:
+			writer.CloseWithError(ErrDetached)
+		case io.Closer:
+			writer.Close()
+		}
+	})
+}
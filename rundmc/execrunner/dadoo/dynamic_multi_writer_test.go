@@ -3,6 +3,9 @@ package dadoo_test
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
 
 	"code.cloudfoundry.org/guardian/rundmc/execrunner/dadoo"
 
@@ -24,6 +27,7 @@ var _ = Describe("DynamicMultiWriter", func() {
 		multiW.Attach(&buf2)
 		fmt.Fprint(multiW, " hello both writers")
 
+		Expect(multiW.Close()).To(Succeed())
 		Expect(buf1.String()).To(Equal("hello one writer hello both writers"))
 		Expect(buf2.String()).To(Equal(" hello both writers"))
 	})
@@ -37,4 +41,123 @@ var _ = Describe("DynamicMultiWriter", func() {
 
 		Expect(multiW.Count()).To(Equal(2))
 	})
+
+	It("stops counting a writer once it is detached", func() {
+		var buf bytes.Buffer
+
+		multiW := dadoo.NewDynamicMultiWriter()
+		multiW.MaxBufferedBytes = 10
+		multiW.Attach(&buf)
+		multiW.Attach(&failingWriter{})
+
+		fmt.Fprint(multiW, "hello")
+
+		Eventually(multiW.Count).Should(Equal(1))
+		Expect(multiW.Close()).To(Succeed())
+		Expect(buf.String()).To(Equal("hello"))
+	})
+
+	It("does not block writes on an attached writer which is stalled", func() {
+		unblock := make(chan struct{})
+
+		multiW := dadoo.NewDynamicMultiWriter()
+		multiW.MaxBufferedBytes = 10
+		multiW.Attach(blockingWriter{unblock: unblock})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				fmt.Fprint(multiW, "x")
+			}
+		}()
+		Eventually(done).Should(BeClosed())
+
+		close(unblock)
+		Expect(multiW.Close()).To(Succeed())
+	})
+
+	It("closes a writer which is detached for falling too far behind with ErrDetached", func() {
+		r, w := io.Pipe()
+
+		multiW := dadoo.NewDynamicMultiWriter()
+		multiW.MaxBufferedBytes = 10
+		multiW.Attach(w)
+
+		for i := 0; i < 100; i++ {
+			fmt.Fprint(multiW, "x")
+		}
+
+		_, err := ioutil.ReadAll(r)
+		Expect(err).To(MatchError(dadoo.ErrDetached))
+		Expect(multiW.Close()).To(Succeed())
+	})
+
+	Context("when a writer is still writing CloseTimeout after Close", func() {
+		It("detaches it rather than waiting for it, closing it with ErrDetached", func() {
+			r, w := io.Pipe()
+			var buf bytes.Buffer
+
+			multiW := dadoo.NewDynamicMultiWriter()
+			multiW.CloseTimeout = 100 * time.Millisecond
+			multiW.Attach(w)
+			multiW.Attach(&buf)
+
+			fmt.Fprint(multiW, "hello")
+
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				Expect(multiW.Close()).To(Succeed())
+			}()
+			Eventually(closed).Should(BeClosed())
+			Expect(buf.String()).To(Equal("hello"))
+
+			_, err := r.Read(make([]byte, 5))
+			Expect(err).To(MatchError(dadoo.ErrDetached))
+		})
+	})
+
+	It("stops writing to a writer which returns an error", func() {
+		multiW := dadoo.NewDynamicMultiWriter()
+		failing := &failingWriter{}
+		multiW.Attach(failing)
+
+		fmt.Fprint(multiW, "hello")
+		fmt.Fprint(multiW, "goodbye")
+		Expect(multiW.Close()).To(Succeed())
+
+		Expect(failing.writes).To(BeNumerically("<=", 1))
+	})
+
+	It("does not write to writers attached after it is closed", func() {
+		var buf bytes.Buffer
+
+		multiW := dadoo.NewDynamicMultiWriter()
+		Expect(multiW.Close()).To(Succeed())
+
+		multiW.Attach(&buf)
+		fmt.Fprint(multiW, "too late")
+
+		Expect(multiW.Count()).To(Equal(0))
+		Expect(buf.String()).To(BeEmpty())
+	})
 })
+
+type blockingWriter struct {
+	unblock chan struct{}
+}
+
+func (w blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	return len(p), nil
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, io.ErrClosedPipe
+}
@@ -2,6 +2,7 @@ package dadoo
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,8 +12,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"code.cloudfoundry.org/commandrunner"
 	"code.cloudfoundry.org/garden"
@@ -21,6 +24,17 @@ import (
 	"code.cloudfoundry.org/lager"
 )
 
+const (
+	// maxCapturedStderr bounds how much of what a process writes to stderr
+	// before runc has exited is kept, to explain a failure to exec it
+	maxCapturedStderr = 4096
+
+	// stderrSettleTime is how long a failed exec waits for what runc wrote to
+	// stderr to be streamed. runc has exited by then, so it is normally
+	// already in the pipe.
+	stderrSettleTime = 100 * time.Millisecond
+)
+
 type ExecRunner struct {
 	dadooPath                string
 	runcPath                 string
@@ -95,6 +109,7 @@ func (d *ExecRunner) Run(
 	cmd.Stdout = dadooLogFile
 	cmd.Stderr = dadooLogFile
 
+	start := time.Now()
 	if err := d.commandRunner.Start(cmd); err != nil {
 		return nil, err
 	}
@@ -121,6 +136,13 @@ func (d *ExecRunner) Run(
 		return nil, err
 	}
 
+	// runc reports why it could not exec the process on the process's stderr
+	var runcStderr *stderrCapture
+	if pio.Stderr != nil {
+		runcStderr = &stderrCapture{Writer: pio.Stderr, capturing: true}
+		pio.Stderr = runcStderr
+	}
+
 	process.streamData(pio, stdin, stdout, stderr)
 
 	doneReadingRuncLogs := make(chan []byte)
@@ -155,11 +177,24 @@ func (d *ExecRunner) Run(
 	if bytesRead == 0 || err != nil {
 		return nil, fmt.Errorf("failed to read runc exit code %v", err)
 	}
-	log.Info("runc-exit-status", lager.Data{"status": runcExitStatus[0]})
+	log.Info("runc-exit-status", lager.Data{
+		"status":   runcExitStatus[0],
+		"args":     cmd.Args,
+		"duration": time.Since(start).String(),
+	})
 	if runcExitStatus[0] != 0 {
+		if msg := strings.TrimSpace(process.runcStderr(runcStderr, stderr)); msg != "" {
+			return nil, fmt.Errorf("exit status %d: stderr: %s", runcExitStatus[0], msg)
+		}
 		return nil, fmt.Errorf("exit status %d", runcExitStatus[0])
 	}
 
+	if runcStderr != nil {
+		runcStderr.stop()
+	}
+
+	go process.watchExit()
+
 	return process, nil
 }
 
@@ -215,11 +250,19 @@ type process struct {
 	cleanup                                      func() error
 	stdoutWriter                                 *DynamicMultiWriter
 	stderrWriter                                 *DynamicMultiWriter
+	stdoutCopy, stderrCopy                       *sync.Once
 	streamMutex                                  *sync.Mutex
+	exited                                       chan struct{}
 
 	signals.Signaller
 }
 
+// ReportsExits is true, as the processes it runs close their Exited channel
+// once dadoo reports that they have exited
+func (d *ExecRunner) ReportsExits() bool {
+	return true
+}
+
 func (d *ExecRunner) getProcess(log lager.Logger, id, processPath, pidFilePath string, extraCleanup func() error) *process {
 	d.processesMutex.Lock()
 	defer d.processesMutex.Unlock()
@@ -260,7 +303,10 @@ func (d *ExecRunner) getProcess(log lager.Logger, id, processPath, pidFilePath s
 		Signaller:    d.signallerFactory.NewSignaller(pidFilePath),
 		stdoutWriter: NewDynamicMultiWriter(),
 		stderrWriter: NewDynamicMultiWriter(),
+		stdoutCopy:   new(sync.Once),
+		stderrCopy:   new(sync.Once),
 		streamMutex:  new(sync.Mutex),
+		exited:       make(chan struct{}),
 	}
 	return d.processes[processPath]
 }
@@ -323,27 +369,144 @@ func (p process) streamData(pio garden.ProcessIO, stdin, stdout, stderr *os.File
 
 	if pio.Stdout != nil {
 		p.stdoutWriter.Attach(pio.Stdout)
-		if p.stdoutWriter.Count() == 1 {
+		// the output is copied once, by the first attach, as writers which
+		// are detached leave the count of attached writers
+		p.stdoutCopy.Do(func() {
 			p.ioWg.Add(1)
 			go func() {
 				io.Copy(p.stdoutWriter, stdout)
 				stdout.Close()
+				p.stdoutWriter.Close()
 				p.ioWg.Done()
 			}()
-		}
+		})
 	}
 
 	if pio.Stderr != nil {
 		p.stderrWriter.Attach(pio.Stderr)
-		if p.stderrWriter.Count() == 1 {
+		// the output is copied once, by the first attach, as writers which
+		// are detached leave the count of attached writers
+		p.stderrCopy.Do(func() {
 			p.ioWg.Add(1)
 			go func() {
 				io.Copy(p.stderrWriter, stderr)
 				stderr.Close()
+				p.stderrWriter.Close()
 				p.ioWg.Done()
 			}()
+		})
+	}
+}
+
+// runcStderr returns what runc wrote to the stderr of a process it failed to
+// exec, either from the capture of the streamed stderr or, when the stderr is
+// not streamed, straight from the pipe
+func (p process) runcStderr(capture *stderrCapture, stderr *os.File) string {
+	if capture == nil {
+		return readAvailable(stderr, maxCapturedStderr)
+	}
+
+	streamed := make(chan struct{})
+	go func() {
+		p.ioWg.Wait()
+		close(streamed)
+	}()
+
+	select {
+	case <-streamed:
+	case <-time.After(stderrSettleTime):
+	}
+
+	return capture.String()
+}
+
+// readAvailable reads at most max bytes from the pipe without waiting for
+// more to be written to it
+func readAvailable(pipe *os.File, max int) string {
+	if err := syscall.SetNonblock(int(pipe.Fd()), true); err != nil {
+		return ""
+	}
+
+	buf := make([]byte, max)
+	n, _ := io.ReadFull(pipe, buf)
+	return string(buf[:n])
+}
+
+// stderrCapture passes the stderr of a process through to its writer, keeping
+// the start of what is written until it is stopped
+type stderrCapture struct {
+	io.Writer
+
+	mutex     sync.Mutex
+	captured  bytes.Buffer
+	capturing bool
+}
+
+func (c *stderrCapture) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	if c.capturing {
+		if remaining := maxCapturedStderr - c.captured.Len(); remaining > 0 {
+			if len(p) < remaining {
+				remaining = len(p)
+			}
+			c.captured.Write(p[:remaining])
 		}
 	}
+	c.mutex.Unlock()
+
+	return c.Writer.Write(p)
+}
+
+func (c *stderrCapture) String() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.captured.String()
+}
+
+func (c *stderrCapture) stop() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.capturing = false
+	c.captured.Reset()
+}
+
+// CloseWithError closes the underlying writer, so that it is still closed
+// when it is detached from the process's output
+func (c *stderrCapture) CloseWithError(err error) error {
+	switch writer := c.Writer.(type) {
+	case interface {
+		CloseWithError(error) error
+	}:
+		return writer.CloseWithError(err)
+	case io.Closer:
+		return writer.Close()
+	}
+
+	return nil
+}
+
+// Exited is closed once the process has exited. Unlike Wait, it does not
+// clean up after the process, so it can be watched alongside its waiters.
+func (p *process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// watchExit closes exited when dadoo closes the exit pipe, which it does once
+// the process has exited
+func (p *process) watchExit() {
+	defer close(p.exited)
+
+	exit, err := openNonBlocking(p.exit)
+	if err != nil {
+		p.logger.Error("watch-exit-open-failed", err)
+		return
+	}
+	defer exit.Close()
+
+	buf := make([]byte, 1)
+	exit.Read(buf)
 }
 
 func (p process) attach(pio garden.ProcessIO) error {
@@ -397,6 +397,33 @@ var _ = Describe("Dadoo ExecRunner", func() {
 				}, 10.0)
 			})
 
+			Context("when runc writes to stderr", func() {
+				BeforeEach(func() {
+					stderrContents = "runc: permission denied\n"
+				})
+
+				It("includes the stderr in the error", func() {
+					_, err := runner.Run(log, processID, processPath, "some-handle", bundlePath, 123, 456, defaultProcessIO(), false, nil, nil)
+					Expect(err).To(MatchError(ContainSubstring("exit status 3: stderr: runc: permission denied")))
+				})
+
+				It("still streams the stderr to the client", func() {
+					pio := defaultProcessIO()
+					_, err := runner.Run(log, processID, processPath, "some-handle", bundlePath, 123, 456, pio, false, nil, nil)
+					Expect(err).To(HaveOccurred())
+					Eventually(pio.Stderr).Should(gbytes.Say("runc: permission denied"))
+				})
+
+				Context("when the process has no stderr", func() {
+					It("reads the stderr from the pipe", func() {
+						pio := defaultProcessIO()
+						pio.Stderr = nil
+						_, err := runner.Run(log, processID, processPath, "some-handle", bundlePath, 123, 456, pio, false, nil, nil)
+						Expect(err).To(MatchError(ContainSubstring("exit status 3: stderr: runc: permission denied")))
+					})
+				})
+			})
+
 			Context("when runc tries to exec a non-existent binary", func() {
 				Context("when the binary is not on the $PATH", func() {
 					BeforeEach(func() {
@@ -534,6 +561,35 @@ var _ = Describe("Dadoo ExecRunner", func() {
 				})
 			})
 
+			Describe("Exited", func() {
+				BeforeEach(func() {
+					closeExitPipeCh = make(chan struct{})
+				})
+
+				It("is closed once dadoo closes the exit pipe", func() {
+					process, err := runner.Run(log, processID, processPath, "some-handle", bundlePath, 123, 456, defaultProcessIO(), false, nil, nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					exited := process.(interface{ Exited() <-chan struct{} }).Exited()
+					Consistently(exited).ShouldNot(BeClosed())
+
+					close(closeExitPipeCh)
+					Eventually(exited).Should(BeClosed())
+				})
+
+				It("does not clean up the process", func() {
+					runner = dadoo.NewExecRunner("path-to-dadoo", "path-to-runc",
+						signallerFactory, fakeCommandRunner, true, "exec")
+
+					process, err := runner.Run(log, processID, processPath, "some-handle", bundlePath, 123, 456, defaultProcessIO(), false, nil, nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					close(closeExitPipeCh)
+					Eventually(process.(interface{ Exited() <-chan struct{} }).Exited()).Should(BeClosed())
+					Expect(processPath).To(BeAnExistingFile())
+				})
+			})
+
 			Describe("SetTTY", func() {
 				BeforeEach(func() {
 					closeExitPipeCh = make(chan struct{})
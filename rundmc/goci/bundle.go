@@ -1,6 +1,14 @@
 package goci
 
-import specs "github.com/opencontainers/runtime-spec/specs-go"
+import (
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// LabelAnnotationPrefix is the prefix of the annotations which record the
+// labels of a container, so that they are kept apart from its properties
+const LabelAnnotationPrefix = "org.cloudfoundry.garden.label."
 
 // Bndl represents an in-memory OCI bundle
 type Bndl struct {
@@ -58,6 +66,52 @@ func (b Bndl) WithHostname(hostname string) Bndl {
 	return b
 }
 
+func (b Bndl) Annotations() map[string]string {
+	return b.Spec.Annotations
+}
+
+// WithAnnotations returns a bundle with the given annotations added. The original bundle is not modified.
+func (b Bndl) WithAnnotations(annotations map[string]string) Bndl {
+	merged := make(map[string]string, len(b.Spec.Annotations)+len(annotations))
+	for key, value := range b.Spec.Annotations {
+		merged[key] = value
+	}
+	for key, value := range annotations {
+		merged[key] = value
+	}
+
+	b.Spec.Annotations = merged
+	return b
+}
+
+// WithLabels returns a bundle annotated with the given container labels. The original bundle is not modified.
+func (b Bndl) WithLabels(labels map[string]string) Bndl {
+	annotations := make(map[string]string, len(labels))
+	for name, value := range labels {
+		annotations[LabelAnnotationPrefix+name] = value
+	}
+
+	return b.WithAnnotations(annotations)
+}
+
+// Labels returns the container labels recorded in the bundle's annotations,
+// or nil if there are none
+func (b Bndl) Labels() map[string]string {
+	var labels map[string]string
+	for key, value := range b.Spec.Annotations {
+		if !strings.HasPrefix(key, LabelAnnotationPrefix) {
+			continue
+		}
+
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[strings.TrimPrefix(key, LabelAnnotationPrefix)] = value
+	}
+
+	return labels
+}
+
 func (b Bndl) Process() specs.Process {
 	return *(b.Spec.Process)
 }
@@ -292,6 +346,15 @@ func (b Bndl) MaskedPaths() []string {
 	return b.Spec.Linux.MaskedPaths
 }
 
+func (b Bndl) WithReadonlyPaths(readonlyPaths []string) Bndl {
+	b.CloneLinux().Spec.Linux.ReadonlyPaths = readonlyPaths
+	return b
+}
+
+func (b Bndl) ReadonlyPaths() []string {
+	return b.Spec.Linux.ReadonlyPaths
+}
+
 type NamespaceSlice []specs.LinuxNamespace
 
 func (slice NamespaceSlice) Set(ns specs.LinuxNamespace) NamespaceSlice {
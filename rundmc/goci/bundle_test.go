@@ -26,6 +26,30 @@ var _ = Describe("Bundle", func() {
 		})
 	})
 
+	Describe("WithAnnotations", func() {
+		It("adds the annotations to the bundle without modifying the original", func() {
+			annotatedBundle := initialBundle.WithAnnotations(map[string]string{"some-key": "some-value"})
+			returnedBundle := annotatedBundle.WithAnnotations(map[string]string{"other-key": "other-value"})
+
+			Expect(returnedBundle.Annotations()).To(Equal(map[string]string{"some-key": "some-value", "other-key": "other-value"}))
+			Expect(annotatedBundle.Annotations()).To(Equal(map[string]string{"some-key": "some-value"}))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("records the labels in the annotations, apart from the other annotations", func() {
+			annotatedBundle := initialBundle.WithAnnotations(map[string]string{"some-key": "some-value"})
+			returnedBundle := annotatedBundle.WithLabels(map[string]string{"team": "some-team"})
+
+			Expect(returnedBundle.Annotations()).To(Equal(map[string]string{
+				"some-key":                           "some-value",
+				"org.cloudfoundry.garden.label.team": "some-team",
+			}))
+			Expect(returnedBundle.Labels()).To(Equal(map[string]string{"team": "some-team"}))
+			Expect(annotatedBundle.Labels()).To(BeNil())
+		})
+	})
+
 	Describe("WithRootFSPropagation", func() {
 		It("sets the RootFSPropagation in the bundle", func() {
 			returnedBundle := initialBundle.WithRootFSPropagation("rshared")
@@ -444,4 +468,11 @@ var _ = Describe("Bundle", func() {
 			Expect(paths[1]).To(Equal("path2"))
 		})
 	})
+
+	Describe("WithReadonlyPaths", func() {
+		It("sets the ReadonlyPaths in the bundle", func() {
+			returnedBundle := initialBundle.WithReadonlyPaths([]string{"path1", "path2"})
+			Expect(returnedBundle.ReadonlyPaths()).To(Equal([]string{"path1", "path2"}))
+		})
+	})
 })
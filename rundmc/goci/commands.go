@@ -1,6 +1,11 @@
 package goci
 
-import "os/exec"
+import (
+	"os/exec"
+	"strconv"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
 
 // The DefaultRuncBinary, i.e. 'runc'.
 var DefaultRuncBinary = RuncBinary{Path: "runc"}
@@ -43,6 +48,21 @@ func EventsCommand(id string) *exec.Cmd {
 	return DefaultRuncBinary.EventsCommand(id)
 }
 
+// ListCommand creates a command that lists all containers using the default runc binary name.
+func ListCommand(logFile string) *exec.Cmd {
+	return DefaultRuncBinary.ListCommand(logFile)
+}
+
+// PsCommand creates a command that lists the PIDs in a container using the default runc binary name.
+func PsCommand(id, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.PsCommand(id, logFile)
+}
+
+// UpdateCommand creates a command that updates the resource limits of a container using the default runc binary name.
+func UpdateCommand(id string, resources specs.LinuxResources, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.UpdateCommand(id, resources, logFile)
+}
+
 // StartCommand returns an *exec.Cmd that, when run, will execute a given bundle.
 func (runc RuncBinary) StartCommand(path, id string, detach bool, log string) *exec.Cmd {
 	args := []string{"--debug", "--log", log, "--log-format", "json", "start"}
@@ -99,3 +119,49 @@ func (runc RuncBinary) DeleteCommand(id string, force bool, logFile string) *exe
 	}
 	return exec.Command(runc.Path, append(deleteArgs, id)...)
 }
+
+// ListCommand returns an *exec.Cmd that, when run, will list all the
+// containers known to runc in JSON format.
+func (runc RuncBinary) ListCommand(logFile string) *exec.Cmd {
+	return exec.Command(runc.Path, []string{"--debug", "--log", logFile, "--log-format", "json", "list", "--format", "json"}...)
+}
+
+// PsCommand returns an *exec.Cmd that, when run, will list the PIDs of the
+// processes in the container in JSON format.
+func (runc RuncBinary) PsCommand(id, logFile string) *exec.Cmd {
+	return exec.Command(runc.Path, []string{"--debug", "--log", logFile, "--log-format", "json", "ps", "--format", "json", id}...)
+}
+
+// UpdateCommand returns an *exec.Cmd that, when run, will apply the memory,
+// CPU and pids limits among the resources to the running container. Limits
+// which are not given are left unchanged.
+func (runc RuncBinary) UpdateCommand(id string, resources specs.LinuxResources, logFile string) *exec.Cmd {
+	args := []string{"--debug", "--log", logFile, "--log-format", "json", "update"}
+
+	if memory := resources.Memory; memory != nil {
+		if memory.Limit != nil {
+			args = append(args, "--memory", strconv.FormatInt(*memory.Limit, 10))
+		}
+		if memory.Swap != nil {
+			args = append(args, "--memory-swap", strconv.FormatInt(*memory.Swap, 10))
+		}
+	}
+
+	if cpu := resources.CPU; cpu != nil {
+		if cpu.Shares != nil {
+			args = append(args, "--cpu-share", strconv.FormatUint(*cpu.Shares, 10))
+		}
+		if cpu.Period != nil {
+			args = append(args, "--cpu-period", strconv.FormatUint(*cpu.Period, 10))
+		}
+		if cpu.Quota != nil {
+			args = append(args, "--cpu-quota", strconv.FormatInt(*cpu.Quota, 10))
+		}
+	}
+
+	if resources.Pids != nil {
+		args = append(args, "--pids-limit", strconv.FormatInt(resources.Pids.Limit, 10))
+	}
+
+	return exec.Command(runc.Path, append(args, id)...)
+}
@@ -4,6 +4,7 @@ import (
 	"code.cloudfoundry.org/guardian/rundmc/goci"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 var _ = Describe("Commands", func() {
@@ -77,4 +78,40 @@ var _ = Describe("Commands", func() {
 			})
 		})
 	})
+
+	Describe("ListCommand", func() {
+		It("creates an *exec.Cmd to list the containers in JSON format", func() {
+			cmd := goci.ListCommand("log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "--log-format", "json", "list", "--format", "json"}))
+		})
+	})
+
+	Describe("UpdateCommand", func() {
+		It("creates an *exec.Cmd to update the given limits of the container", func() {
+			limit := int64(1024)
+			shares := uint64(512)
+			cmd := goci.UpdateCommand("my-container", specs.LinuxResources{
+				Memory: &specs.LinuxMemory{Limit: &limit, Swap: &limit},
+				CPU:    &specs.LinuxCPU{Shares: &shares},
+				Pids:   &specs.LinuxPids{Limit: 100},
+			}, "log.file")
+			Expect(cmd.Args).To(Equal([]string{
+				"funC", "--debug", "--log", "log.file", "--log-format", "json", "update",
+				"--memory", "1024", "--memory-swap", "1024", "--cpu-share", "512", "--pids-limit", "100",
+				"my-container",
+			}))
+		})
+
+		It("does not pass limits which are not given", func() {
+			cmd := goci.UpdateCommand("my-container", specs.LinuxResources{}, "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "--log-format", "json", "update", "my-container"}))
+		})
+	})
+
+	Describe("PsCommand", func() {
+		It("creates an *exec.Cmd to list the PIDs in the container in JSON format", func() {
+			cmd := goci.PsCommand("my-container", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "--log-format", "json", "ps", "--format", "json", "my-container"}))
+		})
+	})
 })
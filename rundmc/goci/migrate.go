@@ -0,0 +1,48 @@
+package goci
+
+import (
+	"fmt"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// BundleMigration upgrades a bundle from one version of the bundle format to
+// the next
+type BundleMigration func(Bndl) Bndl
+
+// BundleMigrations[n] upgrades a bundle of version n to version n+1, so there
+// must be exactly BundleVersion of them
+var BundleMigrations = []BundleMigration{
+	addMissingSections,
+}
+
+// Migrate upgrades a bundle of the given version to BundleVersion by applying
+// each migration in turn
+func Migrate(bundle Bndl, version int) (Bndl, error) {
+	if version < 0 || version > BundleVersion {
+		return Bndl{}, fmt.Errorf("unsupported bundle version %d (current version is %d)", version, BundleVersion)
+	}
+
+	for v := version; v < BundleVersion; v++ {
+		bundle = BundleMigrations[v](bundle)
+	}
+
+	return bundle, nil
+}
+
+// addMissingSections ensures the sections which guardian dereferences when
+// restoring and destroying containers are present, as bundles saved by older
+// versions may have omitted them
+func addMissingSections(bundle Bndl) Bndl {
+	if bundle.Spec.Linux == nil {
+		bundle.Spec.Linux = &specs.Linux{}
+	}
+	if bundle.Spec.Process == nil {
+		bundle.Spec.Process = &specs.Process{}
+	}
+	if bundle.Spec.Root == nil {
+		bundle.Spec.Root = &specs.Root{}
+	}
+
+	return bundle
+}
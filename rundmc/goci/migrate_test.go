@@ -0,0 +1,53 @@
+package goci_test
+
+import (
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Migrate", func() {
+	It("has a migration for each previous bundle version", func() {
+		Expect(goci.BundleMigrations).To(HaveLen(goci.BundleVersion))
+	})
+
+	Context("when the bundle is version 0", func() {
+		It("adds the missing linux, process and root sections", func() {
+			migrated, err := goci.Migrate(goci.Bndl{Spec: specs.Spec{Version: "1.0.0"}}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrated.Spec.Version).To(Equal("1.0.0"))
+			Expect(migrated.Spec.Linux).NotTo(BeNil())
+			Expect(migrated.Spec.Process).NotTo(BeNil())
+			Expect(migrated.Spec.Root).NotTo(BeNil())
+		})
+
+		It("keeps the sections which are present", func() {
+			bndl := goci.Bndl{Spec: specs.Spec{
+				Linux: &specs.Linux{CgroupsPath: "some-cgroup"},
+				Root:  &specs.Root{Path: "some-rootfs"},
+			}}
+
+			migrated, err := goci.Migrate(bndl, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrated.Spec.Linux.CgroupsPath).To(Equal("some-cgroup"))
+			Expect(migrated.Spec.Root.Path).To(Equal("some-rootfs"))
+		})
+	})
+
+	Context("when the bundle is already the current version", func() {
+		It("returns it unchanged", func() {
+			bndl := goci.Bndl{Spec: specs.Spec{Version: "abcd"}}
+			migrated, err := goci.Migrate(bndl, goci.BundleVersion)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrated).To(Equal(bndl))
+		})
+	})
+
+	Context("when the bundle is newer than the current version", func() {
+		It("returns an error", func() {
+			_, err := goci.Migrate(goci.Bndl{}, goci.BundleVersion+1)
+			Expect(err).To(MatchError(ContainSubstring("unsupported bundle version")))
+		})
+	})
+})
@@ -6,8 +6,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// BundleVersion is the version of the bundle format written by BundleSaver.
+// Bundles saved before the format was versioned have no version file and are
+// considered to be version 0.
+const BundleVersion = 1
+
+const bundleVersionFile = "bundle-version"
+
 type BndlLoader struct {
 }
 
@@ -23,18 +32,79 @@ func (b *BndlLoader) Load(path string) (Bndl, error) {
 
 type BundleSaver struct{}
 
+// Save writes the bundle's config.json and format version. Each file is
+// written to a temporary file, synced and renamed into place, so that a crash
+// part way through leaves either the old or the new file, never a truncated
+// one.
 func (b BundleSaver) Save(bundle Bndl, path string) error {
-	return save(bundle.Spec, filepath.Join(path, "config.json"))
+	if err := save(bundle.Spec, filepath.Join(path, "config.json")); err != nil {
+		return err
+	}
+
+	versionPath := filepath.Join(path, bundleVersionFile)
+	if err := writeFileAtomically(versionPath, []byte(strconv.Itoa(BundleVersion))); err != nil {
+		return fmt.Errorf("Failed to save bundle version: %s", err)
+	}
+
+	return nil
+}
+
+// LoadBundleVersion returns the version of the bundle format of the bundle at
+// the given path
+func LoadBundleVersion(path string) (int, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(path, bundleVersionFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Failed to load bundle version: %s", err)
+	}
+
+	version, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0, fmt.Errorf("Failed to load bundle version: %s", err)
+	}
+
+	return version, nil
 }
 
 func save(value interface{}, path string) error {
-	w, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	defer w.Close()
+	contents, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("Failed to save bundle: %s", err)
 	}
 
-	return json.NewEncoder(w).Encode(value)
+	if err := writeFileAtomically(path, append(contents, '\n')); err != nil {
+		return fmt.Errorf("Failed to save bundle: %s", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomically writes the contents to a temporary file next to the
+// path, syncs it and renames it into place
+func writeFileAtomically(path string, contents []byte) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
 }
 
 func readJSONInto(path string, object interface{}) error {
@@ -67,6 +67,23 @@ var _ = Describe("Bundle Serialization", func() {
 			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
 		})
 
+		It("records the bundle format version", func() {
+			version, err := goci.LoadBundleVersion(tmp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(goci.BundleVersion))
+		})
+
+		It("leaves no temporary files in the bundle", func() {
+			entries, err := ioutil.ReadDir(tmp)
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			Expect(names).To(ConsistOf("config.json", "bundle-version"))
+		})
+
 		Context("when saving fails", func() {
 			It("returns an error", func() {
 				err := bundleSaver.Save(bndle, "non-existent-dir")
@@ -114,6 +131,23 @@ var _ = Describe("Bundle Serialization", func() {
 			})
 		})
 
+		Context("when the bundle has no version file", func() {
+			It("reports version 0", func() {
+				Expect(os.Remove(filepath.Join(tmp, "bundle-version"))).To(Succeed())
+				version, err := goci.LoadBundleVersion(tmp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal(0))
+			})
+		})
+
+		Context("when the version file is not a number", func() {
+			It("returns an error", func() {
+				Expect(ioutil.WriteFile(filepath.Join(tmp, "bundle-version"), []byte("banana"), 0600)).To(Succeed())
+				_, err := goci.LoadBundleVersion(tmp)
+				Expect(err).To(MatchError(ContainSubstring("Failed to load bundle version")))
+			})
+		})
+
 		Context("when config.json is not valid bundle", func() {
 			JustBeforeEach(func() {
 				ioutil.WriteFile(path.Join(tmp, "config.json"), []byte("appended-nonsense"), 0755)
@@ -5,3 +5,6 @@ type MountPointChecker func(path string) (bool, error)
 
 //go:generate counterfeiter . MountOptionsGetter
 type MountOptionsGetter func(path string) ([]string, error)
+
+//go:generate counterfeiter . NosuidRemounter
+type NosuidRemounter func(path string) error
@@ -0,0 +1,38 @@
+package rundmc
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const preservedMountFlags = syscall.MS_RDONLY | syscall.MS_NODEV | syscall.MS_NOEXEC
+
+func (r NosuidRemounter) RemountNosuid(path string) error {
+	return r(path)
+}
+
+// RemountNosuid remounts the mount at path nosuid, keeping its other flags,
+// so that setuid binaries beneath it cannot be used to gain privileges
+func RemountNosuid(path string) error {
+	mountInfo, err := getMountInfo(path)
+	if err != nil {
+		return err
+	}
+
+	if mountInfo == nil {
+		return fmt.Errorf("%s is not a mount point", path)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return fmt.Errorf("statfs %s: %s", path, err)
+	}
+
+	// the ST_* flags reported by statfs share their values with the MS_* flags
+	flags := uintptr(syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_NOSUID) | uintptr(stat.Flags)&preservedMountFlags
+	if err := syscall.Mount("", path, "", flags, ""); err != nil {
+		return fmt.Errorf("remount %s nosuid: %s", path, err)
+	}
+
+	return nil
+}
@@ -66,17 +66,55 @@ func (n *nstar) StreamOut(log lager.Logger, pid int, path, user string) (io.Read
 
 	writer.Close()
 
+	stream := &tarStream{reader: reader, waited: make(chan struct{})}
 	go func() {
+		defer close(stream.waited)
+
 		if err := n.CommandRunner.Wait(cmd); err != nil {
 			log.Error("wait", err, lager.Data{
 				"pid":    pid,
 				"path":   path,
 				"user":   user,
 				"stdout": errOut.String()})
+			stream.err = fmt.Errorf("error streaming out: %v. Output: %s", err, errOut.String())
 		}
 	}()
 
-	return reader, nil
+	return stream, nil
+}
+
+// tarStream is the output of tar streaming out of a container. The failure of
+// tar is returned in place of the end of the stream, and by Close, so that a
+// missing or unreadable path is not mistaken for an empty one.
+type tarStream struct {
+	reader *os.File
+
+	waited chan struct{}
+	err    error
+}
+
+func (s *tarStream) Read(p []byte) (int, error) {
+	n, err := s.reader.Read(p)
+	if err == io.EOF {
+		<-s.waited
+		if s.err != nil {
+			return n, s.err
+		}
+	}
+
+	return n, err
+}
+
+// Close waits for tar to exit and returns its failure. Tar may fail because
+// it could no longer write when the stream is closed before it was read to
+// the end.
+func (s *tarStream) Close() error {
+	if err := s.reader.Close(); err != nil {
+		return err
+	}
+
+	<-s.waited
+	return s.err
 }
 
 func (n *nstar) streamUser(usr string) string {
@@ -213,6 +213,33 @@ var _ = Describe("Nstar", func() {
 		})
 	})
 
+	Context("when tar fails after it has started", func() {
+		BeforeEach(func() {
+			fakeCommandRunner.WhenRunning(fake_command_runner.CommandSpec{}, func(cmd *exec.Cmd) error {
+				cmd.Stderr.Write([]byte("tar: some-path: Cannot stat"))
+				return nil
+			})
+			fakeCommandRunner.WhenWaitingFor(fake_command_runner.CommandSpec{}, func(cmd *exec.Cmd) error {
+				return errors.New("exit status 2")
+			})
+		})
+
+		It("returns the error in place of the end of the stream", func() {
+			stream, err := nstar.StreamOut(lagertest.NewTestLogger("test"), 12, "some-path", "some-user")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ioutil.ReadAll(stream)
+			Expect(err).To(MatchError(ContainSubstring("tar: some-path: Cannot stat")))
+		})
+
+		It("returns the error from Close", func() {
+			stream, err := nstar.StreamOut(lagertest.NewTestLogger("test"), 12, "some-path", "some-user")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stream.Close()).To(MatchError(ContainSubstring("exit status 2")))
+		})
+	})
+
 	Context("when it fails", func() {
 		It("returns the contents of stderr on failure", func() {
 			fakeCommandRunner.WhenRunning(fake_command_runner.CommandSpec{}, func(cmd *exec.Cmd) error {
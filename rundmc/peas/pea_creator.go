@@ -74,7 +74,7 @@ func (p *PeaCreator) CreatePea(log lager.Logger, processSpec garden.ProcessSpec,
 
 	peaBundlePath := filepath.Join(sandboxBundlePath, "processes", processID)
 	if mkdirErr := os.MkdirAll(peaBundlePath, 0700); mkdirErr != nil {
-		return nil, err
+		return errs("creating-bundle-dir", mkdirErr)
 	}
 
 	privileged, err := p.PrivilegedGetter.Privileged(sandboxBundlePath)
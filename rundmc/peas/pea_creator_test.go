@@ -372,6 +372,20 @@ var _ = Describe("PeaCreator", func() {
 			_, createErr = peaCreator.CreatePea(log, processSpec, garden.ProcessIO{}, ctrHandle, ctrBundleDir)
 		})
 
+		Context("when the bundle directory cannot be created", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(ctrBundleDir, "processes"), nil, 0600)).To(Succeed())
+			})
+
+			It("returns a wrapped error", func() {
+				Expect(createErr).To(MatchError(ContainSubstring("creating-bundle-dir")))
+			})
+
+			It("does not create a volume", func() {
+				Expect(volumizer.CreateCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the bind mount source creator return an error", func() {
 			BeforeEach(func() {
 				bindMountSourceCreator.CreateReturns(nil, errors.New("explode"))
@@ -4,8 +4,9 @@ package rundmcfakes
 import (
 	"sync"
 
-	"code.cloudfoundry.org/guardian/gardener/container-spec"
+	spec "code.cloudfoundry.org/guardian/gardener/container-spec"
 	"code.cloudfoundry.org/guardian/rundmc"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -23,7 +24,22 @@ type FakeDepot struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
-	LookupStub        func(log lager.Logger, handle string) (path string, err error)
+	PreviewStub        func(log lager.Logger, handle string, desiredContainerSpec spec.DesiredContainerSpec) (goci.Bndl, error)
+	previewMutex       sync.RWMutex
+	previewArgsForCall []struct {
+		log                  lager.Logger
+		handle               string
+		desiredContainerSpec spec.DesiredContainerSpec
+	}
+	previewReturns struct {
+		result1 goci.Bndl
+		result2 error
+	}
+	previewReturnsOnCall map[int]struct {
+		result1 goci.Bndl
+		result2 error
+	}
+	LookupStub        func(log lager.Logger, handle string) (string, error)
 	lookupMutex       sync.RWMutex
 	lookupArgsForCall []struct {
 		log    lager.Logger
@@ -37,6 +53,17 @@ type FakeDepot struct {
 		result1 string
 		result2 error
 	}
+	BundlePathStub        func(handle string) string
+	bundlePathMutex       sync.RWMutex
+	bundlePathArgsForCall []struct {
+		handle string
+	}
+	bundlePathReturns struct {
+		result1 string
+	}
+	bundlePathReturnsOnCall map[int]struct {
+		result1 string
+	}
 	DestroyStub        func(log lager.Logger, handle string) error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct {
@@ -114,7 +141,60 @@ func (fake *FakeDepot) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeDepot) Lookup(log lager.Logger, handle string) (path string, err error) {
+func (fake *FakeDepot) Preview(log lager.Logger, handle string, desiredContainerSpec spec.DesiredContainerSpec) (goci.Bndl, error) {
+	fake.previewMutex.Lock()
+	ret, specificReturn := fake.previewReturnsOnCall[len(fake.previewArgsForCall)]
+	fake.previewArgsForCall = append(fake.previewArgsForCall, struct {
+		log                  lager.Logger
+		handle               string
+		desiredContainerSpec spec.DesiredContainerSpec
+	}{log, handle, desiredContainerSpec})
+	fake.recordInvocation("Preview", []interface{}{log, handle, desiredContainerSpec})
+	fake.previewMutex.Unlock()
+	if fake.PreviewStub != nil {
+		return fake.PreviewStub(log, handle, desiredContainerSpec)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.previewReturns.result1, fake.previewReturns.result2
+}
+
+func (fake *FakeDepot) PreviewCallCount() int {
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
+	return len(fake.previewArgsForCall)
+}
+
+func (fake *FakeDepot) PreviewArgsForCall(i int) (lager.Logger, string, spec.DesiredContainerSpec) {
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
+	return fake.previewArgsForCall[i].log, fake.previewArgsForCall[i].handle, fake.previewArgsForCall[i].desiredContainerSpec
+}
+
+func (fake *FakeDepot) PreviewReturns(result1 goci.Bndl, result2 error) {
+	fake.PreviewStub = nil
+	fake.previewReturns = struct {
+		result1 goci.Bndl
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDepot) PreviewReturnsOnCall(i int, result1 goci.Bndl, result2 error) {
+	fake.PreviewStub = nil
+	if fake.previewReturnsOnCall == nil {
+		fake.previewReturnsOnCall = make(map[int]struct {
+			result1 goci.Bndl
+			result2 error
+		})
+	}
+	fake.previewReturnsOnCall[i] = struct {
+		result1 goci.Bndl
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDepot) Lookup(log lager.Logger, handle string) (string, error) {
 	fake.lookupMutex.Lock()
 	ret, specificReturn := fake.lookupReturnsOnCall[len(fake.lookupArgsForCall)]
 	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
@@ -166,6 +246,54 @@ func (fake *FakeDepot) LookupReturnsOnCall(i int, result1 string, result2 error)
 	}{result1, result2}
 }
 
+func (fake *FakeDepot) BundlePath(handle string) string {
+	fake.bundlePathMutex.Lock()
+	ret, specificReturn := fake.bundlePathReturnsOnCall[len(fake.bundlePathArgsForCall)]
+	fake.bundlePathArgsForCall = append(fake.bundlePathArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("BundlePath", []interface{}{handle})
+	fake.bundlePathMutex.Unlock()
+	if fake.BundlePathStub != nil {
+		return fake.BundlePathStub(handle)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.bundlePathReturns.result1
+}
+
+func (fake *FakeDepot) BundlePathCallCount() int {
+	fake.bundlePathMutex.RLock()
+	defer fake.bundlePathMutex.RUnlock()
+	return len(fake.bundlePathArgsForCall)
+}
+
+func (fake *FakeDepot) BundlePathArgsForCall(i int) string {
+	fake.bundlePathMutex.RLock()
+	defer fake.bundlePathMutex.RUnlock()
+	return fake.bundlePathArgsForCall[i].handle
+}
+
+func (fake *FakeDepot) BundlePathReturns(result1 string) {
+	fake.BundlePathStub = nil
+	fake.bundlePathReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeDepot) BundlePathReturnsOnCall(i int, result1 string) {
+	fake.BundlePathStub = nil
+	if fake.bundlePathReturnsOnCall == nil {
+		fake.bundlePathReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.bundlePathReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeDepot) Destroy(log lager.Logger, handle string) error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
@@ -263,8 +391,12 @@ func (fake *FakeDepot) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.previewMutex.RLock()
+	defer fake.previewMutex.RUnlock()
 	fake.lookupMutex.RLock()
 	defer fake.lookupMutex.RUnlock()
+	fake.bundlePathMutex.RLock()
+	defer fake.bundlePathMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
 	fake.handlesMutex.RLock()
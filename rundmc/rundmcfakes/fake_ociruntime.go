@@ -9,10 +9,11 @@ import (
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/lager"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type FakeOCIRuntime struct {
-	CreateStub        func(log lager.Logger, bundlePath, id string, io garden.ProcessIO) error
+	CreateStub        func(log lager.Logger, bundlePath string, id string, io garden.ProcessIO) error
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		log        lager.Logger
@@ -26,7 +27,7 @@ type FakeOCIRuntime struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExecStub        func(log lager.Logger, bundlePath, id string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
+	ExecStub        func(log lager.Logger, bundlePath string, id string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
 	execMutex       sync.RWMutex
 	execArgsForCall []struct {
 		log        lager.Logger
@@ -43,7 +44,7 @@ type FakeOCIRuntime struct {
 		result1 garden.Process
 		result2 error
 	}
-	AttachStub        func(log lager.Logger, bundlePath, id, processId string, io garden.ProcessIO) (garden.Process, error)
+	AttachStub        func(log lager.Logger, bundlePath string, id string, processId string, io garden.ProcessIO) (garden.Process, error)
 	attachMutex       sync.RWMutex
 	attachArgsForCall []struct {
 		log        lager.Logger
@@ -126,6 +127,47 @@ type FakeOCIRuntime struct {
 	watchEventsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ListStub        func(log lager.Logger) ([]runrunc.ListedContainer, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		log lager.Logger
+	}
+	listReturns struct {
+		result1 []runrunc.ListedContainer
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []runrunc.ListedContainer
+		result2 error
+	}
+	ProcessesStub        func(log lager.Logger, id string, bundlePath string) ([]gardener.ContainerProcess, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct {
+		log        lager.Logger
+		id         string
+		bundlePath string
+	}
+	processesReturns struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}
+	processesReturnsOnCall map[int]struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}
+	UpdateLimitsStub        func(log lager.Logger, id string, resources specs.LinuxResources) error
+	updateLimitsMutex       sync.RWMutex
+	updateLimitsArgsForCall []struct {
+		log       lager.Logger
+		id        string
+		resources specs.LinuxResources
+	}
+	updateLimitsReturns struct {
+		result1 error
+	}
+	updateLimitsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -544,6 +586,160 @@ func (fake *FakeOCIRuntime) WatchEventsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeOCIRuntime) List(log lager.Logger) ([]runrunc.ListedContainer, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		log lager.Logger
+	}{log})
+	fake.recordInvocation("List", []interface{}{log})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(log)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.listReturns.result1, fake.listReturns.result2
+}
+
+func (fake *FakeOCIRuntime) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) ListArgsForCall(i int) lager.Logger {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return fake.listArgsForCall[i].log
+}
+
+func (fake *FakeOCIRuntime) ListReturns(result1 []runrunc.ListedContainer, result2 error) {
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []runrunc.ListedContainer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOCIRuntime) ListReturnsOnCall(i int, result1 []runrunc.ListedContainer, result2 error) {
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []runrunc.ListedContainer
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []runrunc.ListedContainer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOCIRuntime) Processes(log lager.Logger, id string, bundlePath string) ([]gardener.ContainerProcess, error) {
+	fake.processesMutex.Lock()
+	ret, specificReturn := fake.processesReturnsOnCall[len(fake.processesArgsForCall)]
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct {
+		log        lager.Logger
+		id         string
+		bundlePath string
+	}{log, id, bundlePath})
+	fake.recordInvocation("Processes", []interface{}{log, id, bundlePath})
+	fake.processesMutex.Unlock()
+	if fake.ProcessesStub != nil {
+		return fake.ProcessesStub(log, id, bundlePath)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processesReturns.result1, fake.processesReturns.result2
+}
+
+func (fake *FakeOCIRuntime) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) ProcessesArgsForCall(i int) (lager.Logger, string, string) {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return fake.processesArgsForCall[i].log, fake.processesArgsForCall[i].id, fake.processesArgsForCall[i].bundlePath
+}
+
+func (fake *FakeOCIRuntime) ProcessesReturns(result1 []gardener.ContainerProcess, result2 error) {
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOCIRuntime) ProcessesReturnsOnCall(i int, result1 []gardener.ContainerProcess, result2 error) {
+	fake.ProcessesStub = nil
+	if fake.processesReturnsOnCall == nil {
+		fake.processesReturnsOnCall = make(map[int]struct {
+			result1 []gardener.ContainerProcess
+			result2 error
+		})
+	}
+	fake.processesReturnsOnCall[i] = struct {
+		result1 []gardener.ContainerProcess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOCIRuntime) UpdateLimits(log lager.Logger, id string, resources specs.LinuxResources) error {
+	fake.updateLimitsMutex.Lock()
+	ret, specificReturn := fake.updateLimitsReturnsOnCall[len(fake.updateLimitsArgsForCall)]
+	fake.updateLimitsArgsForCall = append(fake.updateLimitsArgsForCall, struct {
+		log       lager.Logger
+		id        string
+		resources specs.LinuxResources
+	}{log, id, resources})
+	fake.recordInvocation("UpdateLimits", []interface{}{log, id, resources})
+	fake.updateLimitsMutex.Unlock()
+	if fake.UpdateLimitsStub != nil {
+		return fake.UpdateLimitsStub(log, id, resources)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.updateLimitsReturns.result1
+}
+
+func (fake *FakeOCIRuntime) UpdateLimitsCallCount() int {
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
+	return len(fake.updateLimitsArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) UpdateLimitsArgsForCall(i int) (lager.Logger, string, specs.LinuxResources) {
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
+	return fake.updateLimitsArgsForCall[i].log, fake.updateLimitsArgsForCall[i].id, fake.updateLimitsArgsForCall[i].resources
+}
+
+func (fake *FakeOCIRuntime) UpdateLimitsReturns(result1 error) {
+	fake.UpdateLimitsStub = nil
+	fake.updateLimitsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) UpdateLimitsReturnsOnCall(i int, result1 error) {
+	fake.UpdateLimitsStub = nil
+	if fake.updateLimitsReturnsOnCall == nil {
+		fake.updateLimitsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateLimitsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeOCIRuntime) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -563,6 +759,12 @@ func (fake *FakeOCIRuntime) Invocations() map[string][][]interface{} {
 	defer fake.statsMutex.RUnlock()
 	fake.watchEventsMutex.RLock()
 	defer fake.watchEventsMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
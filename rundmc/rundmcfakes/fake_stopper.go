@@ -9,7 +9,7 @@ import (
 )
 
 type FakeStopper struct {
-	StopAllStub        func(log lager.Logger, cgroupName string, save []int, kill bool) error
+	StopAllStub        func(log lager.Logger, cgroupName string, save []int, kill bool) ([]int, error)
 	stopAllMutex       sync.RWMutex
 	stopAllArgsForCall []struct {
 		log        lager.Logger
@@ -18,16 +18,18 @@ type FakeStopper struct {
 		kill       bool
 	}
 	stopAllReturns struct {
-		result1 error
+		result1 []int
+		result2 error
 	}
 	stopAllReturnsOnCall map[int]struct {
-		result1 error
+		result1 []int
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStopper) StopAll(log lager.Logger, cgroupName string, save []int, kill bool) error {
+func (fake *FakeStopper) StopAll(log lager.Logger, cgroupName string, save []int, kill bool) ([]int, error) {
 	var saveCopy []int
 	if save != nil {
 		saveCopy = make([]int, len(save))
@@ -47,9 +49,9 @@ func (fake *FakeStopper) StopAll(log lager.Logger, cgroupName string, save []int
 		return fake.StopAllStub(log, cgroupName, save, kill)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fake.stopAllReturns.result1
+	return fake.stopAllReturns.result1, fake.stopAllReturns.result2
 }
 
 func (fake *FakeStopper) StopAllCallCount() int {
@@ -64,23 +66,26 @@ func (fake *FakeStopper) StopAllArgsForCall(i int) (lager.Logger, string, []int,
 	return fake.stopAllArgsForCall[i].log, fake.stopAllArgsForCall[i].cgroupName, fake.stopAllArgsForCall[i].save, fake.stopAllArgsForCall[i].kill
 }
 
-func (fake *FakeStopper) StopAllReturns(result1 error) {
+func (fake *FakeStopper) StopAllReturns(result1 []int, result2 error) {
 	fake.StopAllStub = nil
 	fake.stopAllReturns = struct {
-		result1 error
-	}{result1}
+		result1 []int
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStopper) StopAllReturnsOnCall(i int, result1 error) {
+func (fake *FakeStopper) StopAllReturnsOnCall(i int, result1 []int, result2 error) {
 	fake.StopAllStub = nil
 	if fake.stopAllReturnsOnCall == nil {
 		fake.stopAllReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 []int
+			result2 error
 		})
 	}
 	fake.stopAllReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 []int
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeStopper) Invocations() map[string][][]interface{} {
@@ -0,0 +1,37 @@
+package runrunc
+
+import "strings"
+
+// The garden ProcessSpec has no field for supplementary groups, so groups in
+// addition to those of the user in the container's /etc/group are requested
+// with this reserved environment variable, as a comma separated list of group
+// names or IDs. It is removed from the environment of the process.
+const ProcessAdditionalGroupsEnv = "GARDEN_PROCESS_ADDITIONAL_GROUPS"
+
+// ExtractAdditionalGroups parses the additional groups out of the environment,
+// and returns the environment without them
+func ExtractAdditionalGroups(env []string) ([]string, []string) {
+	var groups []string
+	remaining := []string{}
+	found := false
+
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, ProcessAdditionalGroupsEnv+"=") {
+			remaining = append(remaining, kv)
+			continue
+		}
+		found = true
+
+		for _, group := range strings.Split(strings.TrimPrefix(kv, ProcessAdditionalGroupsEnv+"="), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	if !found {
+		return nil, env
+	}
+
+	return groups, remaining
+}
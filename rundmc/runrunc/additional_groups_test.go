@@ -0,0 +1,22 @@
+package runrunc_test
+
+import (
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExtractAdditionalGroups", func() {
+	It("parses the groups and removes them from the environment", func() {
+		groups, env := runrunc.ExtractAdditionalGroups([]string{"FOO=bar", "GARDEN_PROCESS_ADDITIONAL_GROUPS=vcap, 1001,,"})
+		Expect(groups).To(Equal([]string{"vcap", "1001"}))
+		Expect(env).To(Equal([]string{"FOO=bar"}))
+	})
+
+	It("leaves the environment alone when no groups are requested", func() {
+		env := []string{"FOO=bar"}
+		groups, actualEnv := runrunc.ExtractAdditionalGroups(env)
+		Expect(groups).To(BeEmpty())
+		Expect(actualEnv).To(Equal(env))
+	})
+})
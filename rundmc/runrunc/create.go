@@ -1,6 +1,7 @@
 package runrunc
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -79,7 +80,29 @@ func (c *Creator) Create(log lager.Logger, bundlePath, id string, pio garden.Pro
 	}
 	cmd.Stdout = pio.Stdout
 	cmd.Stderr = pio.Stderr
-	err := c.commandRunner.Run(cmd)
+
+	// the detached container inherits runc's stderr, so it cannot be captured
+	// through a pipe, which would only be closed once the container exits.
+	// Without a stderr of its own it goes to a file in the bundle instead.
+	var stderrFile *os.File
+	if cmd.Stderr == nil {
+		var err error
+		stderrFile, err = os.OpenFile(filepath.Join(bundlePath, "create.stderr"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("runc create: open stderr file: %s", err)
+		}
+		defer stderrFile.Close()
+		cmd.Stderr = stderrFile
+	}
+
+	err := runAndRecord(log, c.commandRunner, cmd, nil)
+	if err != nil && stderrFile != nil {
+		stderr, readErr := ioutil.ReadFile(stderrFile.Name())
+		if readErr != nil {
+			log.Error("read-stderr-failed", readErr)
+		}
+		err = withStderr(err, bytes.NewBuffer(stderr))
+	}
 
 	log.Info("completing")
 	defer func() {
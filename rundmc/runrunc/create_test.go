@@ -29,6 +29,7 @@ var _ = Describe("Create", func() {
 		logger         *lagertest.TestLogger
 		logs           string
 		runcExitStatus error
+		runcStderr     string
 		recievedStdin  string
 
 		runner *runrunc.Creator
@@ -37,6 +38,7 @@ var _ = Describe("Create", func() {
 	BeforeEach(func() {
 		logs = ""
 		runcExitStatus = nil
+		runcStderr = ""
 		commandRunner = fake_command_runner.New()
 		logger = lagertest.NewTestLogger("test")
 
@@ -64,6 +66,11 @@ var _ = Describe("Create", func() {
 				recievedStdin = string(stdinBytes)
 			}
 
+			if cmd.Stderr != nil {
+				_, err := io.WriteString(cmd.Stderr, runcStderr)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
 			Expect(logFile.Close()).To(Succeed())
 			return runcExitStatus
 		})
@@ -119,6 +126,25 @@ var _ = Describe("Create", func() {
 		It("returns runc's exit status", func() {
 			Expect(runner.Create(logger, bundlePath, "some-id", garden.ProcessIO{})).To(MatchError("runc run: some-error: "))
 		})
+
+		Context("when runc writes to stderr", func() {
+			BeforeEach(func() {
+				runcStderr = "cgroup mount failed\n"
+			})
+
+			It("includes the stderr in the error", func() {
+				Expect(runner.Create(logger, bundlePath, "some-id", garden.ProcessIO{})).To(MatchError("runc run: some-error: stderr: cgroup mount failed: "))
+			})
+		})
+
+		It("logs the failed invocation", func() {
+			Expect(runner.Create(logger, bundlePath, "some-id", garden.ProcessIO{})).NotTo(Succeed())
+
+			data := logData(logger, "test.create.runc-failed")
+			Expect(data).To(HaveKeyWithValue("args", ContainElement("--detach")))
+			Expect(data).To(HaveKey("duration"))
+			Expect(data).To(HaveKeyWithValue("error", "some-error"))
+		})
 	})
 
 	Describe("forwarding logs from runC", func() {
@@ -12,6 +12,7 @@ import (
 
 //go:generate counterfeiter . UidGenerator
 //go:generate counterfeiter . UserLookupper
+//go:generate counterfeiter . GroupLookupper
 //go:generate counterfeiter . EnvDeterminer
 //go:generate counterfeiter . Mkdirer
 //go:generate counterfeiter . BundleLoader
@@ -31,6 +32,16 @@ type UserLookupper interface {
 	Lookup(rootFsPath string, user string) (*ExecUser, error)
 }
 
+type GroupLookupper interface {
+	LookupGroups(rootFsPath string, groups []string) ([]int, error)
+}
+
+type GroupLookupFunc func(rootFsPath string, groups []string) ([]int, error)
+
+func (fn GroupLookupFunc) LookupGroups(rootFsPath string, groups []string) ([]int, error) {
+	return fn(rootFsPath, groups)
+}
+
 type Mkdirer interface {
 	MkdirAs(rootFSPathFile string, uid, gid int, mode os.FileMode, recreate bool, path ...string) error
 }
@@ -65,6 +76,14 @@ type ExecRunner interface {
 	Attach(log lager.Logger, processID string, io garden.ProcessIO, processesPath string) (garden.Process, error)
 }
 
+// An ExitReportingExecRunner runs processes which report when they exit
+// without being waited for, which is how the live processes of a container
+// are counted for the process limit
+type ExitReportingExecRunner interface {
+	ExecRunner
+	ReportsExits() bool
+}
+
 type PreparedSpec struct {
 	specs.Process
 	ContainerRootHostUID uint32
@@ -80,6 +99,9 @@ type ProcessSpec struct {
 	garden.ProcessSpec
 	ContainerUID int
 	ContainerGID int
+
+	// ContainerAdditionalGIDs are the supplementary groups of the process
+	ContainerAdditionalGIDs []int
 }
 
 //go:generate counterfeiter . Waiter
@@ -9,6 +9,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/idmapper"
@@ -20,18 +22,26 @@ type Execer struct {
 	processBuilder ProcessBuilder
 	mkdirer        Mkdirer
 	userLookuper   UserLookupper
+	groupLookuper  GroupLookupper
 	runner         ExecRunner
 	processIDGen   UidGenerator
+
+	// processOverrider may be nil on platforms which do not support
+	// process overrides
+	processOverrider ProcessOverrider
 }
 
-func NewExecer(bundleLoader BundleLoader, processBuilder ProcessBuilder, mkdirer Mkdirer, userLookuper UserLookupper, runner ExecRunner, processIDGen UidGenerator) *Execer {
+func NewExecer(bundleLoader BundleLoader, processBuilder ProcessBuilder, mkdirer Mkdirer, userLookuper UserLookupper, groupLookuper GroupLookupper, runner ExecRunner, processIDGen UidGenerator, processOverrider ProcessOverrider) *Execer {
 	return &Execer{
 		bundleLoader:   bundleLoader,
 		processBuilder: processBuilder,
 		mkdirer:        mkdirer,
 		userLookuper:   userLookuper,
+		groupLookuper:  groupLookuper,
 		runner:         runner,
 		processIDGen:   processIDGen,
+
+		processOverrider: processOverrider,
 	}
 }
 
@@ -42,6 +52,20 @@ func (e *Execer) Exec(log lager.Logger, bundlePath, sandboxHandle string, spec g
 	log.Info("start")
 	defer log.Info("finished")
 
+	overrides, env, err := ExtractProcessOverrides(spec.Env)
+	if err != nil {
+		log.Error("invalid-process-overrides", err)
+		return nil, err
+	}
+	spec.Env = env
+
+	additionalGroups, env := ExtractAdditionalGroups(spec.Env)
+	spec.Env = env
+
+	if !overrides.Empty() && e.processOverrider == nil {
+		return nil, errors.New("process overrides are not supported on this platform")
+	}
+
 	ctrInitPid, err := ioutil.ReadFile(filepath.Join(bundlePath, "pidfile"))
 	if err != nil {
 		log.Error("read-pidfile-failed", err)
@@ -54,12 +78,28 @@ func (e *Execer) Exec(log lager.Logger, bundlePath, sandboxHandle string, spec g
 		return nil, err
 	}
 
+	var additionalGIDs []int
+	additionalGIDs = append(additionalGIDs, user.Sgids...)
+	if len(additionalGroups) > 0 {
+		gids, err := e.groupLookuper.LookupGroups(rootfsPath, additionalGroups)
+		if err != nil {
+			log.Error("group-lookup-failed", err)
+			return nil, err
+		}
+		additionalGIDs = append(additionalGIDs, gids...)
+	}
+
 	bundle, err := e.bundleLoader.Load(bundlePath)
 	if err != nil {
 		log.Error("load-bundle-failed", err)
 		return nil, err
 	}
 
+	if err := checkOOMScoreAdj(overrides, bundle.Process().OOMScoreAdj); err != nil {
+		log.Error("invalid-process-overrides", err)
+		return nil, err
+	}
+
 	hostUID := idmapper.MappingList(bundle.Spec.Linux.UIDMappings).Map(user.Uid)
 	hostGID := idmapper.MappingList(bundle.Spec.Linux.GIDMappings).Map(user.Gid)
 
@@ -74,11 +114,16 @@ func (e *Execer) Exec(log lager.Logger, bundlePath, sandboxHandle string, spec g
 	}
 
 	preparedSpec := e.processBuilder.BuildProcess(bundle, ProcessSpec{
-		ProcessSpec:  spec,
-		ContainerUID: user.Uid,
-		ContainerGID: user.Gid,
+		ProcessSpec:             spec,
+		ContainerUID:            user.Uid,
+		ContainerGID:            user.Gid,
+		ContainerAdditionalGIDs: additionalGIDs,
 	})
 
+	if !overrides.Empty() {
+		preparedSpec.Process.Args = e.processOverrider.StopBeforeExec(preparedSpec.Process.Args)
+	}
+
 	processesPath := filepath.Join(bundlePath, "processes")
 
 	processID := spec.ID
@@ -99,10 +144,58 @@ func (e *Execer) Exec(log lager.Logger, bundlePath, sandboxHandle string, spec g
 		return nil, err // this could *almost* be a panic: a valid spec should always encode (but out of caution we'll error)
 	}
 
-	return e.runner.Run(
+	process, err := e.runner.Run(
 		log, processID, processPath, sandboxHandle, bundlePath, preparedSpec.ContainerRootHostUID,
 		preparedSpec.ContainerRootHostGID, io, preparedSpec.Terminal, bytes.NewReader(encodedSpec), nil,
 	)
+	if err != nil || overrides.Empty() {
+		return process, err
+	}
+
+	if err := e.override(log, processPath, bundle.CGroupPath(), overrides); err != nil {
+		log.Error("override-process-failed", err)
+		// don't leave behind a process which escaped the overrides it asked for
+		process.Signal(garden.SignalKill)
+		return nil, err
+	}
+
+	return process, nil
+}
+
+func (e *Execer) override(log lager.Logger, processPath, cgroupsPath string, overrides ProcessOverrides) error {
+	pidContents, err := ioutil.ReadFile(filepath.Join(processPath, "pidfile"))
+	if err != nil {
+		return fmt.Errorf("reading process pidfile: %s", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidContents)))
+	if err != nil {
+		return fmt.Errorf("parsing process pidfile: %s", err)
+	}
+
+	return e.processOverrider.Override(log, pid, cgroupsPath, overrides)
+}
+
+// RemoveProcessCgroups removes the cgroups created beneath a container's
+// cgroups path for processes which asked to be placed in one
+func (e *Execer) RemoveProcessCgroups(log lager.Logger, cgroupsPath string) error {
+	if e.processOverrider == nil {
+		return nil
+	}
+
+	return e.processOverrider.RemoveCgroups(log, cgroupsPath)
+}
+
+func checkOOMScoreAdj(overrides ProcessOverrides, containerOOMScoreAdj *int) error {
+	if overrides.OOMScoreAdj == nil || containerOOMScoreAdj == nil {
+		return nil
+	}
+
+	if *overrides.OOMScoreAdj < *containerOOMScoreAdj {
+		return fmt.Errorf("%s must not be lower than the container's oom_score_adj of %d: '%d'", ProcessOOMScoreAdjEnv, *containerOOMScoreAdj, *overrides.OOMScoreAdj)
+	}
+
+	return nil
 }
 
 // Attach attaches to an already running process by guid
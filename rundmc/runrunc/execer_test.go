@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
 	"code.cloudfoundry.org/guardian/rundmc/goci"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -24,8 +27,11 @@ var _ = Describe("Execer", func() {
 		processBuilder     *fakes.FakeProcessBuilder
 		mkdirer            *fakes.FakeMkdirer
 		userLookuper       *fakes.FakeUserLookupper
+		groupLookuper      *fakes.FakeGroupLookupper
 		processIDGenerator *fakes.FakeUidGenerator
 		execRunner         *fakes.FakeExecRunner
+		processOverrider   *fakes.FakeProcessOverrider
+		execerOverrider    runrunc.ProcessOverrider
 
 		execer *runrunc.Execer
 
@@ -82,16 +88,23 @@ var _ = Describe("Execer", func() {
 		mkdirer = new(fakes.FakeMkdirer)
 		userLookuper = new(fakes.FakeUserLookupper)
 		userLookuper.LookupReturns(user, nil)
+		groupLookuper = new(fakes.FakeGroupLookupper)
 		processIDGenerator = new(fakes.FakeUidGenerator)
 		execRunner = new(fakes.FakeExecRunner)
+		processOverrider = new(fakes.FakeProcessOverrider)
+		execerOverrider = processOverrider
+	})
 
+	JustBeforeEach(func() {
 		execer = runrunc.NewExecer(
 			bundleLoader,
 			processBuilder,
 			mkdirer,
 			userLookuper,
+			groupLookuper,
 			execRunner,
 			processIDGenerator,
+			execerOverrider,
 		)
 	})
 
@@ -207,6 +220,87 @@ var _ = Describe("Execer", func() {
 				Expect(actualProcessSpec.Dir).To(Equal(user.Home))
 			})
 		})
+
+		It("does not look up additional groups", func() {
+			Expect(groupLookuper.LookupGroupsCallCount()).To(Equal(0))
+		})
+
+		Context("when the user has supplementary groups", func() {
+			BeforeEach(func() {
+				userLookuper.LookupReturns(&runrunc.ExecUser{Uid: 1, Gid: 2, Home: "/some/home", Sgids: []int{3, 4}}, nil)
+			})
+
+			It("builds a process with the groups", func() {
+				_, actualProcessSpec := processBuilder.BuildProcessArgsForCall(0)
+				Expect(actualProcessSpec.ContainerAdditionalGIDs).To(Equal([]int{3, 4}))
+			})
+		})
+
+		Context("when additional groups are requested in the environment", func() {
+			BeforeEach(func() {
+				spec.Env = []string{"FOO=bar", "GARDEN_PROCESS_ADDITIONAL_GROUPS=vcap, 1001"}
+				userLookuper.LookupReturns(&runrunc.ExecUser{Uid: 1, Gid: 2, Home: "/some/home", Sgids: []int{3}}, nil)
+				groupLookuper.LookupGroupsReturns([]int{1000, 1001}, nil)
+			})
+
+			It("looks up the groups in the container's rootfs", func() {
+				Expect(groupLookuper.LookupGroupsCallCount()).To(Equal(1))
+				rootfsPath, groups := groupLookuper.LookupGroupsArgsForCall(0)
+				Expect(rootfsPath).To(Equal(filepath.Join("/proc", "some-pid", "root")))
+				Expect(groups).To(Equal([]string{"vcap", "1001"}))
+			})
+
+			It("builds a process with the user's groups and the additional groups", func() {
+				_, actualProcessSpec := processBuilder.BuildProcessArgsForCall(0)
+				Expect(actualProcessSpec.ContainerAdditionalGIDs).To(Equal([]int{3, 1000, 1001}))
+				Expect(actualProcessSpec.Env).To(Equal([]string{"FOO=bar"}))
+			})
+		})
+
+		It("does not override the process", func() {
+			Expect(processOverrider.OverrideCallCount()).To(Equal(0))
+		})
+
+		Context("when process overrides are requested in the environment", func() {
+			BeforeEach(func() {
+				spec.Env = []string{"FOO=bar", "GARDEN_PROCESS_NICE=10", "GARDEN_PROCESS_CGROUP=health"}
+				execRunner.RunStub = func(_ lager.Logger, _, processPath, _, _ string, _, _ uint32, _ garden.ProcessIO, _ bool, _ io.Reader, _ func() error) (garden.Process, error) {
+					Expect(ioutil.WriteFile(filepath.Join(processPath, "pidfile"), []byte("1234\n"), 0600)).To(Succeed())
+					return new(gardenfakes.FakeProcess), nil
+				}
+			})
+
+			It("removes the overrides from the process environment", func() {
+				_, actualProcessSpec := processBuilder.BuildProcessArgsForCall(0)
+				Expect(actualProcessSpec.Env).To(Equal([]string{"FOO=bar"}))
+			})
+
+			Context("when the process is started", func() {
+				BeforeEach(func() {
+					preparedProc.Process.Args = []string{"some-program"}
+					processOverrider.StopBeforeExecStub = func(args []string) []string {
+						return append([]string{"stop"}, args...)
+					}
+				})
+
+				It("stops it before it execs its program", func() {
+					_, _, _, _, _, _, _, _, _, procJSON, _ := execRunner.RunArgsForCall(0)
+					var process specs.Process
+					Expect(json.NewDecoder(procJSON).Decode(&process)).To(Succeed())
+					Expect(process.Args).To(Equal([]string{"stop", "some-program"}))
+				})
+			})
+
+			It("overrides the started process", func() {
+				Expect(processOverrider.OverrideCallCount()).To(Equal(1))
+				_, pid, cgroupsPath, overrides := processOverrider.OverrideArgsForCall(0)
+				Expect(pid).To(Equal(1234))
+				Expect(cgroupsPath).To(Equal(bndl.CGroupPath()))
+				Expect(*overrides.Nice).To(Equal(10))
+				Expect(overrides.OOMScoreAdj).To(BeNil())
+				Expect(overrides.CgroupSubPath).To(Equal("health"))
+			})
+		})
 	})
 
 	Describe("Failed Exec", func() {
@@ -265,5 +359,79 @@ var _ = Describe("Execer", func() {
 				Expect(execErr).To(MatchError(ContainSubstring("run")))
 			})
 		})
+
+		Context("when looking up additional groups fails", func() {
+			BeforeEach(func() {
+				spec.Env = []string{"GARDEN_PROCESS_ADDITIONAL_GROUPS=nosuchgroup"}
+				groupLookuper.LookupGroupsReturns(nil, errors.New("no-group"))
+			})
+
+			It("returns an error without running the process", func() {
+				Expect(execErr).To(MatchError("no-group"))
+				Expect(execRunner.RunCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a process override is invalid", func() {
+			BeforeEach(func() {
+				spec.Env = []string{"GARDEN_PROCESS_NICE=fast"}
+			})
+
+			It("returns an error without running the process", func() {
+				Expect(execErr).To(MatchError("GARDEN_PROCESS_NICE must be an integer between 0 and 19: 'fast'"))
+				Expect(execRunner.RunCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when process overrides are requested", func() {
+			var process *gardenfakes.FakeProcess
+
+			BeforeEach(func() {
+				spec.Env = []string{"GARDEN_PROCESS_OOM_SCORE_ADJ=500"}
+				process = new(gardenfakes.FakeProcess)
+				execRunner.RunStub = func(_ lager.Logger, _, processPath, _, _ string, _, _ uint32, _ garden.ProcessIO, _ bool, _ io.Reader, _ func() error) (garden.Process, error) {
+					Expect(ioutil.WriteFile(filepath.Join(processPath, "pidfile"), []byte("1234"), 0600)).To(Succeed())
+					return process, nil
+				}
+			})
+
+			Context("and overriding the process fails", func() {
+				BeforeEach(func() {
+					processOverrider.OverrideReturns(errors.New("no-override"))
+				})
+
+				It("kills the process and returns the error", func() {
+					Expect(execErr).To(MatchError("no-override"))
+					Expect(process.SignalCallCount()).To(Equal(1))
+					Expect(process.SignalArgsForCall(0)).To(Equal(garden.SignalKill))
+				})
+			})
+
+			Context("and the oom score adjustment is lower than the container's", func() {
+				BeforeEach(func() {
+					score := 800
+					bundleLoader.LoadReturns(goci.Bndl{Spec: specs.Spec{
+						Process: &specs.Process{OOMScoreAdj: &score},
+						Linux:   &specs.Linux{},
+					}}, nil)
+				})
+
+				It("returns an error without running the process", func() {
+					Expect(execErr).To(MatchError("GARDEN_PROCESS_OOM_SCORE_ADJ must not be lower than the container's oom_score_adj of 800: '500'"))
+					Expect(execRunner.RunCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("and the platform does not support process overrides", func() {
+				BeforeEach(func() {
+					execerOverrider = nil
+				})
+
+				It("returns an error without running the process", func() {
+					Expect(execErr).To(MatchError("process overrides are not supported on this platform"))
+					Expect(execRunner.RunCallCount()).To(Equal(0))
+				})
+			})
+		})
 	})
 })
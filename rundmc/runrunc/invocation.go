@@ -0,0 +1,82 @@
+package runrunc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/commandrunner"
+	"code.cloudfoundry.org/lager"
+)
+
+// runAndRecord runs a runc command and logs its arguments, duration, exit code
+// and, when stderr is captured, what it wrote to stderr. Failures are logged
+// as errors so that they are visible without debug logging.
+func runAndRecord(log lager.Logger, runner commandrunner.CommandRunner, cmd *exec.Cmd, stderr *bytes.Buffer) error {
+	start := time.Now()
+	err := runner.Run(cmd)
+
+	data := lager.Data{
+		"args":      cmd.Args,
+		"duration":  time.Since(start).String(),
+		"exit-code": exitCode(err),
+	}
+	if stderr != nil {
+		data["stderr"] = stderr.String()
+	}
+
+	if err != nil {
+		log.Error("runc-failed", err, data)
+	} else {
+		log.Debug("runc-finished", data)
+	}
+
+	return err
+}
+
+// captureStderr tees the stderr of a command into the returned buffer. It
+// must not be used for commands which leave processes holding on to their
+// stderr, such as 'runc run --detach', as running them would then block.
+func captureStderr(cmd *exec.Cmd) *bytes.Buffer {
+	stderr := new(bytes.Buffer)
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
+
+	return stderr
+}
+
+// withStderr adds anything runc wrote to stderr to its error, as runc often
+// reports the actual cause of a failure there rather than in its log file.
+func withStderr(err error, stderr *bytes.Buffer) error {
+	if err == nil || stderr == nil {
+		return err
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		return err
+	}
+
+	return fmt.Errorf("%s: stderr: %s", err, msg)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+
+	return -1
+}
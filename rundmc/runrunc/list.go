@@ -0,0 +1,60 @@
+package runrunc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type Lister struct {
+	runner RuncCmdRunner
+	runc   RuncBinary
+}
+
+func NewLister(runner RuncCmdRunner, runc RuncBinary) *Lister {
+	return &Lister{
+		runner: runner,
+		runc:   runc,
+	}
+}
+
+// A ListedContainer is a container known to runc, and the bundle it was
+// created from
+type ListedContainer struct {
+	ID     string `json:"id"`
+	Bundle string `json:"bundle"`
+}
+
+// List returns all the containers known to runc
+func (l *Lister) List(log lager.Logger) ([]ListedContainer, error) {
+	log = log.Session("list")
+
+	log.Debug("started")
+	defer log.Debug("finished")
+
+	buf := new(bytes.Buffer)
+	err := l.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		cmd := l.runc.ListCommand(logFile)
+		cmd.Stdout = buf
+		return cmd
+	})
+	if err != nil {
+		return nil, fmt.Errorf("runc list: %s", err)
+	}
+
+	var containers []ListedContainer
+	// runc prints 'null' rather than an empty list when there are no containers
+	if err := json.NewDecoder(buf).Decode(&containers); err != nil {
+		log.Error("decode-list-failed", err)
+		return nil, fmt.Errorf("runc list: %s", err)
+	}
+
+	if containers == nil {
+		containers = []ListedContainer{}
+	}
+
+	return containers, nil
+}
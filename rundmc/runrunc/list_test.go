@@ -0,0 +1,115 @@
+package runrunc_test
+
+import (
+	"errors"
+	"os/exec"
+
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	. "code.cloudfoundry.org/commandrunner/fake_command_runner/matchers"
+)
+
+var _ = Describe("List", func() {
+	var (
+		commandRunner *fake_command_runner.FakeCommandRunner
+		runner        *fakes.FakeRuncCmdRunner
+		runcBinary    *fakes.FakeRuncBinary
+		logger        *lagertest.TestLogger
+
+		listCmdOutput string
+		listCmdExit   error
+
+		lister *runrunc.Lister
+	)
+
+	BeforeEach(func() {
+		runner = new(fakes.FakeRuncCmdRunner)
+		runcBinary = new(fakes.FakeRuncBinary)
+		commandRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+
+		lister = runrunc.NewLister(runner, runcBinary)
+
+		runcBinary.ListCommandStub = func(logFile string) *exec.Cmd {
+			return exec.Command("funC-list", "--log", logFile, "list", "--format", "json")
+		}
+
+		listCmdExit = nil
+		listCmdOutput = `[
+			{"id": "container-1", "pid": 4, "status": "running", "bundle": "/depot/container-1"},
+			{"id": "container-2", "pid": 5, "status": "stopped", "bundle": "/depot/container-2"}
+		]`
+	})
+
+	JustBeforeEach(func() {
+		runner.RunAndLogStub = func(_ lager.Logger, fn runrunc.LoggingCmd) error {
+			return commandRunner.Run(fn("potato.log"))
+		}
+
+		commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "funC-list",
+		}, func(cmd *exec.Cmd) error {
+			cmd.Stdout.Write([]byte(listCmdOutput))
+			return listCmdExit
+		})
+	})
+
+	It("returns the ids and bundles of the containers", func() {
+		containers, err := lister.List(logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(containers).To(ConsistOf(
+			runrunc.ListedContainer{ID: "container-1", Bundle: "/depot/container-1"},
+			runrunc.ListedContainer{ID: "container-2", Bundle: "/depot/container-2"},
+		))
+	})
+
+	It("forwards runc logs", func() {
+		_, err := lister.List(logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(commandRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+			Path: "funC-list",
+			Args: []string{"--log", "potato.log", "list", "--format", "json"},
+		}))
+	})
+
+	Context("when there are no containers", func() {
+		BeforeEach(func() {
+			listCmdOutput = "null"
+		})
+
+		It("returns an empty list", func() {
+			containers, err := lister.List(logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(containers).To(BeEmpty())
+		})
+	})
+
+	Context("when listing fails", func() {
+		BeforeEach(func() {
+			listCmdExit = errors.New("boom")
+		})
+
+		It("returns the error", func() {
+			_, err := lister.List(logger)
+			Expect(err).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	Context("when the list output is not JSON", func() {
+		BeforeEach(func() {
+			listCmdOutput = "potato"
+		})
+
+		It("returns a reasonable error", func() {
+			_, err := lister.List(logger)
+			Expect(err).To(MatchError(ContainSubstring("runc list: invalid character 'p'")))
+		})
+	})
+})
@@ -37,8 +37,10 @@ func (l *logRunner) RunAndLog(log lager.Logger, loggingCmd LoggingCmd) (err erro
 	if err != nil {
 		return err
 	}
-	err = l.runner.Run(loggingCmd(logFile.Name()))
-	return forwardLogs(log, logFile, err)
+	cmd := loggingCmd(logFile.Name())
+	stderr := captureStderr(cmd)
+	err = runAndRecord(log, l.runner, cmd, stderr)
+	return withStderr(forwardLogs(log, logFile, err), stderr)
 }
 
 func forwardLogs(log lager.Logger, logFile *os.File, err error) error {
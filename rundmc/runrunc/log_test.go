@@ -13,6 +13,7 @@ import (
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("RunAndLog", func() {
@@ -94,6 +95,57 @@ var _ = Describe("RunAndLog", func() {
 		})).To(MatchError(MatchRegexp("potato: .*System error.*POTATO.*")))
 	})
 
+	It("logs the arguments, duration and exit code of the command", func() {
+		Expect(logRunner.RunAndLog(logger, func(logFile string) *exec.Cmd {
+			return exec.Command("something.exe", "--potato")
+		})).To(Succeed())
+
+		data := logData(logger, "test.run.runc-finished")
+		Expect(data).To(HaveKeyWithValue("args", ConsistOf("something.exe", "--potato")))
+		Expect(data).To(HaveKey("duration"))
+		Expect(data).To(HaveKeyWithValue("exit-code", BeNumerically("==", 0)))
+	})
+
+	Context("when the command writes to stderr and fails", func() {
+		BeforeEach(func() {
+			commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "something.exe",
+			}, func(cmd *exec.Cmd) error {
+				ioutil.WriteFile(cmd.Args[1], []byte(logs), 0777)
+				cmd.Stderr.Write([]byte("banana is not a valid container\n"))
+				return errors.New("potato")
+			})
+		})
+
+		It("adds stderr to the returned error", func() {
+			Expect(logRunner.RunAndLog(logger, func(logFile string) *exec.Cmd {
+				return exec.Command("something.exe", logFile)
+			})).To(MatchError(MatchRegexp("potato: .*POTATO.*: stderr: banana is not a valid container$")))
+		})
+
+		It("logs the failure with stderr", func() {
+			logRunner.RunAndLog(logger, func(logFile string) *exec.Cmd {
+				return exec.Command("something.exe", logFile)
+			})
+
+			data := logData(logger, "test.run.runc-failed")
+			Expect(data).To(HaveKeyWithValue("stderr", "banana is not a valid container\n"))
+			Expect(data).To(HaveKeyWithValue("error", "potato"))
+			Expect(data).To(HaveKeyWithValue("exit-code", BeNumerically("==", -1)))
+		})
+
+		It("still writes stderr to the command's own stderr", func() {
+			stderr := gbytes.NewBuffer()
+			logRunner.RunAndLog(logger, func(logFile string) *exec.Cmd {
+				cmd := exec.Command("something.exe", logFile)
+				cmd.Stderr = stderr
+				return cmd
+			})
+
+			Expect(stderr).To(gbytes.Say("banana is not a valid container"))
+		})
+	})
+
 	It("deletes the log file when it's done", func() {
 		var logFileName string
 
@@ -135,3 +187,14 @@ var _ = Describe("RunAndLog", func() {
 		})
 	})
 })
+
+func logData(logger *lagertest.TestLogger, message string) lager.Data {
+	for _, log := range logger.Logs() {
+		if log.Message == message {
+			return log.Data
+		}
+	}
+
+	Fail("no log with message " + message)
+	return nil
+}
@@ -28,7 +28,7 @@ func (p *ProcBuilder) BuildProcess(bndl goci.Bndl, spec ProcessSpec) *PreparedSp
 			User: specs.User{
 				UID:            uint32(spec.ContainerUID),
 				GID:            uint32(spec.ContainerGID),
-				AdditionalGids: []uint32{},
+				AdditionalGids: additionalGids(spec),
 				Username:       spec.User,
 			},
 			Cwd:             spec.Dir,
@@ -36,6 +36,8 @@ func (p *ProcBuilder) BuildProcess(bndl goci.Bndl, spec ProcessSpec) *PreparedSp
 			Rlimits:         toRlimits(spec.Limits),
 			Terminal:        spec.TTY != nil,
 			ApparmorProfile: bndl.Process().ApparmorProfile,
+			NoNewPrivileges: bndl.Process().NoNewPrivileges,
+			OOMScoreAdj:     bndl.Process().OOMScoreAdj,
 		},
 	}
 }
@@ -78,3 +80,17 @@ func containerRootHostID(mappings []specs.LinuxIDMapping) uint32 {
 	}
 	return 0
 }
+
+func additionalGids(spec ProcessSpec) []uint32 {
+	gids := []uint32{}
+	seen := map[int]bool{spec.ContainerGID: true}
+	for _, gid := range spec.ContainerAdditionalGIDs {
+		if seen[gid] {
+			continue
+		}
+		seen[gid] = true
+		gids = append(gids, uint32(gid))
+	}
+
+	return gids
+}
@@ -190,11 +190,50 @@ var _ = Describe("ProcBuilder", func() {
 					Expect(preparedProc.Process.ApparmorProfile).To(Equal("default-profile"))
 				})
 
+				It("does not set no_new_privs", func() {
+					Expect(preparedProc.Process.NoNewPrivileges).To(BeFalse())
+				})
+
+				Context("when the bundle's process sets no_new_privs", func() {
+					BeforeEach(func() {
+						bndl.Spec.Process.NoNewPrivileges = true
+					})
+
+					It("sets no_new_privs", func() {
+						Expect(preparedProc.Process.NoNewPrivileges).To(BeTrue())
+					})
+				})
+
+				Context("when the bundle's process sets an oom_score_adj", func() {
+					BeforeEach(func() {
+						score := 500
+						bndl.Spec.Process.OOMScoreAdj = &score
+					})
+
+					It("gives the process the same oom_score_adj", func() {
+						Expect(*preparedProc.Process.OOMScoreAdj).To(Equal(500))
+					})
+				})
+
 				It("passes the UID and GID", func() {
 					Expect(preparedProc.User.UID).To(Equal(uint32(1)))
 					Expect(preparedProc.User.GID).To(Equal(uint32(2)))
 				})
 
+				It("has no additional groups by default", func() {
+					Expect(preparedProc.User.AdditionalGids).To(BeEmpty())
+				})
+
+				Context("when the process has additional groups", func() {
+					BeforeEach(func() {
+						processSpec.ContainerAdditionalGIDs = []int{10, 2, 20, 10}
+					})
+
+					It("passes them once each, leaving out the primary group", func() {
+						Expect(preparedProc.User.AdditionalGids).To(Equal([]uint32{10, 20}))
+					})
+				})
+
 				It("passes the username, which is used on Windows", func() {
 					Expect(preparedProc.User.Username).To(Equal("Froderick"))
 				})
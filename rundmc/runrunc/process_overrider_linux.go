@@ -0,0 +1,218 @@
+package runrunc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// StopBeforeExecFlag makes the container init binary stop itself and then
+// exec the rest of its args once it is continued
+const StopBeforeExecFlag = "--stop-before-exec"
+
+// CgroupProcessOverrider applies process overrides through the host's view of
+// the process, and the cgroup hierarchies mounted beneath CgroupRoot
+type CgroupProcessOverrider struct {
+	CgroupRoot string
+	ProcRoot   string
+
+	// InitPath is the path of the init binary in containers, which stops the
+	// process before exec'ing its program
+	InitPath string
+
+	// StopTimeout is how long to wait for a process to stop itself
+	StopTimeout time.Duration
+}
+
+func (o CgroupProcessOverrider) StopBeforeExec(args []string) []string {
+	return append([]string{o.InitPath, StopBeforeExecFlag}, args...)
+}
+
+func (o CgroupProcessOverrider) Override(log lager.Logger, pid int, cgroupsPath string, overrides ProcessOverrides) error {
+	log = log.Session("override-process", lager.Data{"pid": pid})
+
+	if err := o.waitUntilStopped(pid); err != nil {
+		log.Error("wait-until-stopped-failed", err)
+		return err
+	}
+
+	if overrides.CgroupSubPath != "" {
+		if err := o.moveToCgroup(pid, cgroupsPath, overrides.CgroupSubPath); err != nil {
+			log.Error("move-to-cgroup-failed", err)
+			return err
+		}
+	}
+
+	if overrides.Nice != nil {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, pid, *overrides.Nice); err != nil {
+			log.Error("set-nice-failed", err)
+			return fmt.Errorf("setting nice value: %s", err)
+		}
+	}
+
+	if overrides.OOMScoreAdj != nil {
+		if err := o.raiseOOMScoreAdj(pid, *overrides.OOMScoreAdj); err != nil {
+			log.Error("set-oom-score-adj-failed", err)
+			return err
+		}
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGCONT); err != nil {
+		log.Error("continue-failed", err)
+		return fmt.Errorf("continuing process: %s", err)
+	}
+
+	return nil
+}
+
+// RemoveCgroups removes the process cgroups beneath the container's cgroup in
+// every hierarchy, deepest first, as the kernel only removes empty cgroups
+func (o CgroupProcessOverrider) RemoveCgroups(log lager.Logger, cgroupsPath string) error {
+	hierarchies, err := ioutil.ReadDir(o.CgroupRoot)
+	if err != nil {
+		return fmt.Errorf("listing cgroup hierarchies: %s", err)
+	}
+
+	for _, hierarchy := range hierarchies {
+		containerCgroup := filepath.Join(o.CgroupRoot, hierarchy.Name(), cgroupsPath)
+
+		var cgroups []string
+		filepath.Walk(containerCgroup, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() && path != containerCgroup {
+				cgroups = append(cgroups, path)
+			}
+			return nil
+		})
+
+		sort.Sort(sort.Reverse(sort.StringSlice(cgroups)))
+		for _, cgroup := range cgroups {
+			if err := os.Remove(cgroup); err != nil && !os.IsNotExist(err) {
+				log.Error("remove-cgroup-failed", err, lager.Data{"cgroup": cgroup})
+				return fmt.Errorf("removing cgroup '%s': %s", cgroup, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitUntilStopped waits for the process to stop itself before exec'ing its
+// program, as until then it may still be runc's setup process
+func (o CgroupProcessOverrider) waitUntilStopped(pid int) error {
+	statPath := filepath.Join(o.ProcRoot, strconv.Itoa(pid), "stat")
+	deadline := time.Now().Add(o.StopTimeout)
+
+	for {
+		contents, err := ioutil.ReadFile(statPath)
+		if err != nil {
+			return fmt.Errorf("reading process state: %s", err)
+		}
+
+		// the state follows the command, which is in parentheses and may contain spaces
+		fields := strings.Fields(string(contents[strings.LastIndex(string(contents), ")")+1:]))
+		if len(fields) > 0 && fields[0] == "T" {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("process did not stop before exec within %s", o.StopTimeout)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// raiseOOMScoreAdj sets the process's oom_score_adj, which must not be lower
+// than the one it was started with
+func (o CgroupProcessOverrider) raiseOOMScoreAdj(pid, oomScoreAdj int) error {
+	oomScoreAdjPath := filepath.Join(o.ProcRoot, strconv.Itoa(pid), "oom_score_adj")
+
+	contents, err := ioutil.ReadFile(oomScoreAdjPath)
+	if err != nil {
+		return fmt.Errorf("reading oom score adjustment: %s", err)
+	}
+
+	current, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return fmt.Errorf("parsing oom score adjustment: %s", err)
+	}
+
+	if oomScoreAdj < current {
+		return fmt.Errorf("%s must not be lower than the process's oom_score_adj of %d: '%d'", ProcessOOMScoreAdjEnv, current, oomScoreAdj)
+	}
+
+	if err := ioutil.WriteFile(oomScoreAdjPath, []byte(strconv.Itoa(oomScoreAdj)), 0644); err != nil {
+		return fmt.Errorf("setting oom score adjustment: %s", err)
+	}
+
+	return nil
+}
+
+// moveToCgroup creates the sub-cgroup in every hierarchy in which the
+// container has a cgroup, and moves the process into it. A new cpuset cgroup
+// has no cpus or mems, so they are copied from the container's cgroup.
+func (o CgroupProcessOverrider) moveToCgroup(pid int, cgroupsPath, subPath string) error {
+	hierarchies, err := ioutil.ReadDir(o.CgroupRoot)
+	if err != nil {
+		return fmt.Errorf("listing cgroup hierarchies: %s", err)
+	}
+
+	for _, hierarchy := range hierarchies {
+		containerCgroup := filepath.Join(o.CgroupRoot, hierarchy.Name(), cgroupsPath)
+		if _, err := os.Stat(containerCgroup); err != nil {
+			continue
+		}
+
+		processCgroup := filepath.Join(containerCgroup, subPath)
+		if err := makeCgroup(containerCgroup, processCgroup); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(processCgroup, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644); err != nil {
+			return fmt.Errorf("moving process to cgroup '%s': %s", processCgroup, err)
+		}
+	}
+
+	return nil
+}
+
+// makeCgroup creates each cgroup from parent down to cgroup, initialising the
+// cpuset of each from its parent's
+func makeCgroup(parent, cgroup string) error {
+	rel, err := filepath.Rel(parent, cgroup)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		child := filepath.Join(parent, name)
+		if err := os.Mkdir(child, 0755); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("creating cgroup: %s", err)
+		}
+
+		for _, file := range []string{"cpuset.cpus", "cpuset.mems"} {
+			value, err := ioutil.ReadFile(filepath.Join(parent, file))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("reading %s: %s", file, err)
+			}
+
+			if err := ioutil.WriteFile(filepath.Join(child, file), value, 0644); err != nil {
+				return fmt.Errorf("initialising %s of cgroup '%s': %s", file, child, err)
+			}
+		}
+
+		parent = child
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package runrunc_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CgroupProcessOverrider", func() {
+	var (
+		logger     *lagertest.TestLogger
+		cgroupRoot string
+		cmd        *exec.Cmd
+		overrider  runrunc.CgroupProcessOverrider
+	)
+
+	BeforeEach(func() {
+		var err error
+		cgroupRoot, err = ioutil.TempDir("", "cgroups")
+		Expect(err).NotTo(HaveOccurred())
+
+		containerCgroup := filepath.Join(cgroupRoot, "cpuset", "garden", "some-handle")
+		Expect(os.MkdirAll(containerCgroup, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(containerCgroup, "cpuset.cpus"), []byte("0-3"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(containerCgroup, "cpuset.mems"), []byte("0"), 0644)).To(Succeed())
+
+		cmd = exec.Command("sleep", "60")
+		Expect(cmd.Start()).To(Succeed())
+		Expect(cmd.Process.Signal(syscall.SIGSTOP)).To(Succeed())
+
+		logger = lagertest.NewTestLogger("test")
+		overrider = runrunc.CgroupProcessOverrider{
+			CgroupRoot:  cgroupRoot,
+			ProcRoot:    "/proc",
+			InitPath:    "/tmp/garden-init",
+			StopTimeout: 5 * time.Second,
+		}
+	})
+
+	AfterEach(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		Expect(os.RemoveAll(cgroupRoot)).To(Succeed())
+	})
+
+	Describe("StopBeforeExec", func() {
+		It("runs the process through the init binary", func() {
+			Expect(overrider.StopBeforeExec([]string{"ls", "-l"})).To(Equal([]string{"/tmp/garden-init", "--stop-before-exec", "ls", "-l"}))
+		})
+	})
+
+	Describe("Override", func() {
+		It("moves the process to the sub-cgroup with the container's cpuset", func() {
+			Expect(overrider.Override(logger, cmd.Process.Pid, "garden/some-handle", runrunc.ProcessOverrides{CgroupSubPath: "health/checks"})).To(Succeed())
+
+			for _, cgroup := range []string{"health", "health/checks"} {
+				cgroupPath := filepath.Join(cgroupRoot, "cpuset", "garden", "some-handle", cgroup)
+				Expect(ioutil.ReadFile(filepath.Join(cgroupPath, "cpuset.cpus"))).To(Equal([]byte("0-3")))
+				Expect(ioutil.ReadFile(filepath.Join(cgroupPath, "cpuset.mems"))).To(Equal([]byte("0")))
+			}
+
+			procs := filepath.Join(cgroupRoot, "cpuset", "garden", "some-handle", "health", "checks", "cgroup.procs")
+			Expect(ioutil.ReadFile(procs)).To(Equal([]byte(strconv.Itoa(cmd.Process.Pid))))
+		})
+
+		It("lowers the priority of the process and continues it", func() {
+			nice := 10
+			Expect(overrider.Override(logger, cmd.Process.Pid, "garden/some-handle", runrunc.ProcessOverrides{Nice: &nice})).To(Succeed())
+
+			priority, err := syscall.Getpriority(syscall.PRIO_PROCESS, cmd.Process.Pid)
+			Expect(err).NotTo(HaveOccurred())
+			// the raw getpriority syscall returns 20 - nice
+			Expect(priority).To(Equal(10))
+
+			Eventually(func() string { return processState(cmd.Process.Pid) }).Should(Equal("S"))
+		})
+
+		It("refuses to lower the oom score adjustment of the process", func() {
+			current, err := ioutil.ReadFile(filepath.Join("/proc", strconv.Itoa(cmd.Process.Pid), "oom_score_adj"))
+			Expect(err).NotTo(HaveOccurred())
+			currentScore, err := strconv.Atoi(string(current[:len(current)-1]))
+			Expect(err).NotTo(HaveOccurred())
+
+			lower := currentScore - 1
+			Expect(overrider.Override(logger, cmd.Process.Pid, "garden/some-handle", runrunc.ProcessOverrides{OOMScoreAdj: &lower})).To(MatchError(ContainSubstring("must not be lower")))
+		})
+
+		Context("when the process does not stop", func() {
+			BeforeEach(func() {
+				Expect(cmd.Process.Signal(syscall.SIGCONT)).To(Succeed())
+				overrider.StopTimeout = 100 * time.Millisecond
+			})
+
+			It("returns an error", func() {
+				nice := 10
+				Expect(overrider.Override(logger, cmd.Process.Pid, "garden/some-handle", runrunc.ProcessOverrides{Nice: &nice})).To(MatchError(ContainSubstring("did not stop")))
+			})
+		})
+	})
+
+	Describe("RemoveCgroups", func() {
+		It("removes the cgroups beneath the container's cgroup, but not the container's", func() {
+			containerCgroup := filepath.Join(cgroupRoot, "cpuset", "garden", "some-handle")
+			Expect(os.MkdirAll(filepath.Join(containerCgroup, "health", "checks"), 0755)).To(Succeed())
+
+			Expect(overrider.RemoveCgroups(logger, "garden/some-handle")).To(Succeed())
+
+			Expect(filepath.Join(containerCgroup, "health")).NotTo(BeADirectory())
+			Expect(containerCgroup).To(BeADirectory())
+		})
+	})
+})
+
+func processState(pid int) string {
+	contents, err := ioutil.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	Expect(err).NotTo(HaveOccurred())
+
+	var (
+		ignoredPid int
+		comm       string
+		state      string
+	)
+	_, err = fmt.Sscanf(string(contents), "%d %s %s", &ignoredPid, &comm, &state)
+	Expect(err).NotTo(HaveOccurred())
+	return state
+}
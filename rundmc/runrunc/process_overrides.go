@@ -0,0 +1,103 @@
+package runrunc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// The garden ProcessSpec has no fields for per-process scheduling settings, so
+// they are requested with these reserved environment variables, which are
+// removed from the environment of the process
+const (
+	ProcessNiceEnv        = "GARDEN_PROCESS_NICE"
+	ProcessOOMScoreAdjEnv = "GARDEN_PROCESS_OOM_SCORE_ADJ"
+	ProcessCgroupEnv      = "GARDEN_PROCESS_CGROUP"
+)
+
+//go:generate counterfeiter . ProcessOverrider
+
+// A ProcessOverrider applies per-process overrides to a process which has
+// been started in a container with the given cgroups path. The process is
+// started with the args returned by StopBeforeExec, so that it stops itself
+// before exec'ing its program, and Override continues it once the overrides
+// apply, so that nothing it forks can escape them.
+type ProcessOverrider interface {
+	StopBeforeExec(args []string) []string
+	Override(log lager.Logger, pid int, cgroupsPath string, overrides ProcessOverrides) error
+	RemoveCgroups(log lager.Logger, cgroupsPath string) error
+}
+
+// ProcessOverrides let a process differ from the rest of its container, e.g. so
+// that a health check does not compete with the main process for resources.
+// They can only lower the priority of a process: the nice value may only be
+// raised, and the oom_score_adj may not be lowered below the container's.
+type ProcessOverrides struct {
+	Nice        *int
+	OOMScoreAdj *int
+
+	// CgroupSubPath is a cgroup beneath the container's cgroup in which to
+	// place the process
+	CgroupSubPath string
+}
+
+func (o ProcessOverrides) Empty() bool {
+	return o.Nice == nil && o.OOMScoreAdj == nil && o.CgroupSubPath == ""
+}
+
+// ExtractProcessOverrides parses the process overrides out of the environment,
+// and returns the environment without them
+func ExtractProcessOverrides(env []string) (ProcessOverrides, []string, error) {
+	var overrides ProcessOverrides
+	remaining := []string{}
+
+	for _, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			remaining = append(remaining, kv)
+			continue
+		}
+
+		name, value := parts[0], parts[1]
+		switch name {
+		case ProcessNiceEnv:
+			nice, err := boundedInt(name, value, 0, 19)
+			if err != nil {
+				return ProcessOverrides{}, nil, err
+			}
+			overrides.Nice = &nice
+		case ProcessOOMScoreAdjEnv:
+			oomScoreAdj, err := boundedInt(name, value, -1000, 1000)
+			if err != nil {
+				return ProcessOverrides{}, nil, err
+			}
+			overrides.OOMScoreAdj = &oomScoreAdj
+		case ProcessCgroupEnv:
+			subPath := filepath.Clean(value)
+			if value == "" || filepath.IsAbs(subPath) || subPath == "." || strings.HasPrefix(subPath, "..") {
+				return ProcessOverrides{}, nil, fmt.Errorf("%s must be a path beneath the container's cgroup: '%s'", name, value)
+			}
+			overrides.CgroupSubPath = subPath
+		default:
+			remaining = append(remaining, kv)
+		}
+	}
+
+	if overrides.Empty() {
+		return overrides, env, nil
+	}
+
+	return overrides, remaining, nil
+}
+
+func boundedInt(name, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be an integer between %d and %d: '%s'", name, min, max, value)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,47 @@
+package runrunc_test
+
+import (
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExtractProcessOverrides", func() {
+	It("parses the overrides and removes them from the environment", func() {
+		overrides, env, err := runrunc.ExtractProcessOverrides([]string{
+			"FOO=bar",
+			"GARDEN_PROCESS_NICE=5",
+			"GARDEN_PROCESS_OOM_SCORE_ADJ=1000",
+			"GARDEN_PROCESS_CGROUP=health/checks",
+			"BAZ",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(env).To(Equal([]string{"FOO=bar", "BAZ"}))
+		Expect(*overrides.Nice).To(Equal(5))
+		Expect(*overrides.OOMScoreAdj).To(Equal(1000))
+		Expect(overrides.CgroupSubPath).To(Equal("health/checks"))
+		Expect(overrides.Empty()).To(BeFalse())
+	})
+
+	It("returns empty overrides when none are requested", func() {
+		overrides, env, err := runrunc.ExtractProcessOverrides([]string{"FOO=bar"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(env).To(Equal([]string{"FOO=bar"}))
+		Expect(overrides.Empty()).To(BeTrue())
+	})
+
+	DescribeTable("rejecting invalid overrides",
+		func(kv, expectedErr string) {
+			_, _, err := runrunc.ExtractProcessOverrides([]string{kv})
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("a nice value out of range", "GARDEN_PROCESS_NICE=20", "GARDEN_PROCESS_NICE must be an integer between 0 and 19: '20'"),
+		Entry("a nice value which raises the priority", "GARDEN_PROCESS_NICE=-20", "GARDEN_PROCESS_NICE must be an integer between 0 and 19: '-20'"),
+		Entry("a non-numeric oom score adjustment", "GARDEN_PROCESS_OOM_SCORE_ADJ=low", "GARDEN_PROCESS_OOM_SCORE_ADJ must be an integer between -1000 and 1000: 'low'"),
+		Entry("an absolute cgroup", "GARDEN_PROCESS_CGROUP=/health", "GARDEN_PROCESS_CGROUP must be a path beneath the container's cgroup: '/health'"),
+		Entry("a cgroup outside the container's", "GARDEN_PROCESS_CGROUP=../other", "GARDEN_PROCESS_CGROUP must be a path beneath the container's cgroup: '../other'"),
+		Entry("an empty cgroup", "GARDEN_PROCESS_CGROUP=", "GARDEN_PROCESS_CGROUP must be a path beneath the container's cgroup: ''"),
+	)
+})
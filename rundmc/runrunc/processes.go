@@ -0,0 +1,97 @@
+package runrunc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+type ProcessLister struct {
+	runner RuncCmdRunner
+	runc   RuncBinary
+}
+
+func NewProcessLister(runner RuncCmdRunner, runc RuncBinary) *ProcessLister {
+	return &ProcessLister{
+		runner: runner,
+		runc:   runc,
+	}
+}
+
+// Processes returns the processes running in the container, along with the
+// garden process IDs of those which were started by garden
+func (l *ProcessLister) Processes(log lager.Logger, id, bundlePath string) ([]gardener.ContainerProcess, error) {
+	log = log.Session("processes", lager.Data{"id": id})
+
+	log.Debug("started")
+	defer log.Debug("finished")
+
+	buf := new(bytes.Buffer)
+	err := l.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		cmd := l.runc.PsCommand(id, logFile)
+		cmd.Stdout = buf
+		return cmd
+	})
+	if err != nil {
+		return nil, fmt.Errorf("runc ps: %s", err)
+	}
+
+	var pids []int
+	if err := json.NewDecoder(buf).Decode(&pids); err != nil {
+		log.Error("decode-ps-failed", err)
+		return nil, fmt.Errorf("runc ps: %s", err)
+	}
+
+	processIDs := processIDsByPid(log, filepath.Join(bundlePath, "processes"))
+
+	processes := []gardener.ContainerProcess{}
+	for _, pid := range pids {
+		processes = append(processes, gardener.ContainerProcess{
+			Pid:       pid,
+			ProcessID: processIDs[pid],
+		})
+	}
+
+	return processes, nil
+}
+
+// processIDsByPid reads the pidfile of every process garden has started in
+// the container. Processes which have exited or are still starting up may not
+// have a pidfile, so they are skipped.
+func processIDsByPid(log lager.Logger, processesPath string) map[int]string {
+	processIDs := map[int]string{}
+
+	entries, err := ioutil.ReadDir(processesPath)
+	if err != nil {
+		log.Debug("read-processes-dir-failed", lager.Data{"error": err.Error()})
+		return processIDs
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(processesPath, entry.Name(), "pidfile"))
+		if err != nil {
+			continue
+		}
+
+		pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+		if err != nil {
+			continue
+		}
+
+		processIDs[pid] = entry.Name()
+	}
+
+	return processIDs
+}
@@ -0,0 +1,135 @@
+package runrunc_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/commandrunner/fake_command_runner"
+	. "code.cloudfoundry.org/commandrunner/fake_command_runner/matchers"
+)
+
+var _ = Describe("Processes", func() {
+	var (
+		commandRunner *fake_command_runner.FakeCommandRunner
+		runner        *fakes.FakeRuncCmdRunner
+		runcBinary    *fakes.FakeRuncBinary
+		logger        *lagertest.TestLogger
+		bundlePath    string
+
+		psCmdOutput string
+		psCmdExit   error
+
+		processLister *runrunc.ProcessLister
+	)
+
+	writePidfile := func(processID, pid string) {
+		processPath := filepath.Join(bundlePath, "processes", processID)
+		Expect(os.MkdirAll(processPath, 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(processPath, "pidfile"), []byte(pid), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		runner = new(fakes.FakeRuncCmdRunner)
+		runcBinary = new(fakes.FakeRuncBinary)
+		commandRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+
+		var err error
+		bundlePath, err = ioutil.TempDir("", "processes-bundle")
+		Expect(err).NotTo(HaveOccurred())
+
+		processLister = runrunc.NewProcessLister(runner, runcBinary)
+
+		runcBinary.PsCommandStub = func(id, logFile string) *exec.Cmd {
+			return exec.Command("funC-ps", "--log", logFile, "ps", "--format", "json", id)
+		}
+
+		psCmdExit = nil
+		psCmdOutput = `[10, 11, 12]`
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		runner.RunAndLogStub = func(_ lager.Logger, fn runrunc.LoggingCmd) error {
+			return commandRunner.Run(fn("potato.log"))
+		}
+
+		commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "funC-ps",
+		}, func(cmd *exec.Cmd) error {
+			cmd.Stdout.Write([]byte(psCmdOutput))
+			return psCmdExit
+		})
+	})
+
+	It("returns the PIDs in the container", func() {
+		processes, err := processLister.Processes(logger, "some-handle", bundlePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(processes).To(Equal([]gardener.ContainerProcess{{Pid: 10}, {Pid: 11}, {Pid: 12}}))
+	})
+
+	It("forwards runc logs", func() {
+		_, err := processLister.Processes(logger, "some-handle", bundlePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(commandRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+			Path: "funC-ps",
+			Args: []string{"--log", "potato.log", "ps", "--format", "json", "some-handle"},
+		}))
+	})
+
+	Context("when garden started some of the processes", func() {
+		BeforeEach(func() {
+			writePidfile("process-1", "11")
+			writePidfile("process-2", "12\n")
+			writePidfile("exited-process", "99")
+			Expect(os.MkdirAll(filepath.Join(bundlePath, "processes", "starting-process"), 0700)).To(Succeed())
+		})
+
+		It("includes their process IDs", func() {
+			processes, err := processLister.Processes(logger, "some-handle", bundlePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processes).To(Equal([]gardener.ContainerProcess{
+				{Pid: 10},
+				{Pid: 11, ProcessID: "process-1"},
+				{Pid: 12, ProcessID: "process-2"},
+			}))
+		})
+	})
+
+	Context("when ps fails", func() {
+		BeforeEach(func() {
+			psCmdExit = errors.New("boom")
+		})
+
+		It("returns the error", func() {
+			_, err := processLister.Processes(logger, "some-handle", bundlePath)
+			Expect(err).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	Context("when the ps output is not JSON", func() {
+		BeforeEach(func() {
+			psCmdOutput = "potato"
+		})
+
+		It("returns a reasonable error", func() {
+			_, err := processLister.Processes(logger, "some-handle", bundlePath)
+			Expect(err).To(MatchError(ContainSubstring("runc ps: invalid character 'p'")))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"os/exec"
 
 	"code.cloudfoundry.org/commandrunner"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 const DefaultRootPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
@@ -21,6 +22,9 @@ type RunRunc struct {
 	*Stater
 	*Killer
 	*Deleter
+	*Updater
+	*Lister
+	*ProcessLister
 }
 
 //go:generate counterfeiter . RuncBinary
@@ -31,21 +35,29 @@ type RuncBinary interface {
 	StatsCommand(id, logFile string) *exec.Cmd
 	KillCommand(id, signal, logFile string) *exec.Cmd
 	DeleteCommand(id string, force bool, logFile string) *exec.Cmd
+	ListCommand(logFile string) *exec.Cmd
+	PsCommand(id, logFile string) *exec.Cmd
+	UpdateCommand(id string, resources specs.LinuxResources, logFile string) *exec.Cmd
 }
 
 func New(
 	runner commandrunner.CommandRunner, runcCmdRunner RuncCmdRunner,
 	runc RuncBinary, dadooPath, runcPath string, runcExtraArgs []string, bundleLoader BundleLoader, processBuilder ProcessBuilder,
-	mkdirer Mkdirer, userLookuper UserLookupper, execRunner ExecRunner, uidGenerator UidGenerator,
+	mkdirer Mkdirer, userLookuper UserLookupper, groupLookuper GroupLookupper, execRunner ExecRunner, uidGenerator UidGenerator,
+	processOverrider ProcessOverrider,
 ) *RunRunc {
 	return &RunRunc{
 		Creator: NewCreator(runcPath, runcExtraArgs, runner),
-		Execer:  NewExecer(bundleLoader, processBuilder, mkdirer, userLookuper, execRunner, uidGenerator),
+		Execer:  NewExecer(bundleLoader, processBuilder, mkdirer, userLookuper, groupLookuper, execRunner, uidGenerator, processOverrider),
 
 		OomWatcher: NewOomWatcher(runner, runc),
 		Statser:    NewStatser(runcCmdRunner, runc),
 		Stater:     NewStater(runcCmdRunner, runc),
 		Killer:     NewKiller(runcCmdRunner, runc),
 		Deleter:    NewDeleter(runcCmdRunner, runc),
+		Updater:    NewUpdater(runcCmdRunner, runc),
+		Lister:     NewLister(runcCmdRunner, runc),
+
+		ProcessLister: NewProcessLister(runcCmdRunner, runc),
 	}
 }
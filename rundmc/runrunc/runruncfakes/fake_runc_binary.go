@@ -6,10 +6,11 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type FakeRuncBinary struct {
-	ExecCommandStub        func(id, processJSONPath, pidFilePath string) *exec.Cmd
+	ExecCommandStub        func(id string, processJSONPath string, pidFilePath string) *exec.Cmd
 	execCommandMutex       sync.RWMutex
 	execCommandArgsForCall []struct {
 		id              string
@@ -33,7 +34,7 @@ type FakeRuncBinary struct {
 	eventsCommandReturnsOnCall map[int]struct {
 		result1 *exec.Cmd
 	}
-	StateCommandStub        func(id, logFile string) *exec.Cmd
+	StateCommandStub        func(id string, logFile string) *exec.Cmd
 	stateCommandMutex       sync.RWMutex
 	stateCommandArgsForCall []struct {
 		id      string
@@ -45,7 +46,7 @@ type FakeRuncBinary struct {
 	stateCommandReturnsOnCall map[int]struct {
 		result1 *exec.Cmd
 	}
-	StatsCommandStub        func(id, logFile string) *exec.Cmd
+	StatsCommandStub        func(id string, logFile string) *exec.Cmd
 	statsCommandMutex       sync.RWMutex
 	statsCommandArgsForCall []struct {
 		id      string
@@ -57,7 +58,7 @@ type FakeRuncBinary struct {
 	statsCommandReturnsOnCall map[int]struct {
 		result1 *exec.Cmd
 	}
-	KillCommandStub        func(id, signal, logFile string) *exec.Cmd
+	KillCommandStub        func(id string, signal string, logFile string) *exec.Cmd
 	killCommandMutex       sync.RWMutex
 	killCommandArgsForCall []struct {
 		id      string
@@ -83,6 +84,42 @@ type FakeRuncBinary struct {
 	deleteCommandReturnsOnCall map[int]struct {
 		result1 *exec.Cmd
 	}
+	ListCommandStub        func(logFile string) *exec.Cmd
+	listCommandMutex       sync.RWMutex
+	listCommandArgsForCall []struct {
+		logFile string
+	}
+	listCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	listCommandReturnsOnCall map[int]struct {
+		result1 *exec.Cmd
+	}
+	PsCommandStub        func(id string, logFile string) *exec.Cmd
+	psCommandMutex       sync.RWMutex
+	psCommandArgsForCall []struct {
+		id      string
+		logFile string
+	}
+	psCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	psCommandReturnsOnCall map[int]struct {
+		result1 *exec.Cmd
+	}
+	UpdateCommandStub        func(id string, resources specs.LinuxResources, logFile string) *exec.Cmd
+	updateCommandMutex       sync.RWMutex
+	updateCommandArgsForCall []struct {
+		id        string
+		resources specs.LinuxResources
+		logFile   string
+	}
+	updateCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	updateCommandReturnsOnCall map[int]struct {
+		result1 *exec.Cmd
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -383,6 +420,153 @@ func (fake *FakeRuncBinary) DeleteCommandReturnsOnCall(i int, result1 *exec.Cmd)
 	}{result1}
 }
 
+func (fake *FakeRuncBinary) ListCommand(logFile string) *exec.Cmd {
+	fake.listCommandMutex.Lock()
+	ret, specificReturn := fake.listCommandReturnsOnCall[len(fake.listCommandArgsForCall)]
+	fake.listCommandArgsForCall = append(fake.listCommandArgsForCall, struct {
+		logFile string
+	}{logFile})
+	fake.recordInvocation("ListCommand", []interface{}{logFile})
+	fake.listCommandMutex.Unlock()
+	if fake.ListCommandStub != nil {
+		return fake.ListCommandStub(logFile)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.listCommandReturns.result1
+}
+
+func (fake *FakeRuncBinary) ListCommandCallCount() int {
+	fake.listCommandMutex.RLock()
+	defer fake.listCommandMutex.RUnlock()
+	return len(fake.listCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) ListCommandArgsForCall(i int) string {
+	fake.listCommandMutex.RLock()
+	defer fake.listCommandMutex.RUnlock()
+	return fake.listCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) ListCommandReturns(result1 *exec.Cmd) {
+	fake.ListCommandStub = nil
+	fake.listCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) ListCommandReturnsOnCall(i int, result1 *exec.Cmd) {
+	fake.ListCommandStub = nil
+	if fake.listCommandReturnsOnCall == nil {
+		fake.listCommandReturnsOnCall = make(map[int]struct {
+			result1 *exec.Cmd
+		})
+	}
+	fake.listCommandReturnsOnCall[i] = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) PsCommand(id string, logFile string) *exec.Cmd {
+	fake.psCommandMutex.Lock()
+	ret, specificReturn := fake.psCommandReturnsOnCall[len(fake.psCommandArgsForCall)]
+	fake.psCommandArgsForCall = append(fake.psCommandArgsForCall, struct {
+		id      string
+		logFile string
+	}{id, logFile})
+	fake.recordInvocation("PsCommand", []interface{}{id, logFile})
+	fake.psCommandMutex.Unlock()
+	if fake.PsCommandStub != nil {
+		return fake.PsCommandStub(id, logFile)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.psCommandReturns.result1
+}
+
+func (fake *FakeRuncBinary) PsCommandCallCount() int {
+	fake.psCommandMutex.RLock()
+	defer fake.psCommandMutex.RUnlock()
+	return len(fake.psCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) PsCommandArgsForCall(i int) (string, string) {
+	fake.psCommandMutex.RLock()
+	defer fake.psCommandMutex.RUnlock()
+	return fake.psCommandArgsForCall[i].id, fake.psCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) PsCommandReturns(result1 *exec.Cmd) {
+	fake.PsCommandStub = nil
+	fake.psCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) PsCommandReturnsOnCall(i int, result1 *exec.Cmd) {
+	fake.PsCommandStub = nil
+	if fake.psCommandReturnsOnCall == nil {
+		fake.psCommandReturnsOnCall = make(map[int]struct {
+			result1 *exec.Cmd
+		})
+	}
+	fake.psCommandReturnsOnCall[i] = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) UpdateCommand(id string, resources specs.LinuxResources, logFile string) *exec.Cmd {
+	fake.updateCommandMutex.Lock()
+	ret, specificReturn := fake.updateCommandReturnsOnCall[len(fake.updateCommandArgsForCall)]
+	fake.updateCommandArgsForCall = append(fake.updateCommandArgsForCall, struct {
+		id        string
+		resources specs.LinuxResources
+		logFile   string
+	}{id, resources, logFile})
+	fake.recordInvocation("UpdateCommand", []interface{}{id, resources, logFile})
+	fake.updateCommandMutex.Unlock()
+	if fake.UpdateCommandStub != nil {
+		return fake.UpdateCommandStub(id, resources, logFile)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.updateCommandReturns.result1
+}
+
+func (fake *FakeRuncBinary) UpdateCommandCallCount() int {
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
+	return len(fake.updateCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) UpdateCommandArgsForCall(i int) (string, specs.LinuxResources, string) {
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
+	return fake.updateCommandArgsForCall[i].id, fake.updateCommandArgsForCall[i].resources, fake.updateCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) UpdateCommandReturns(result1 *exec.Cmd) {
+	fake.UpdateCommandStub = nil
+	fake.updateCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) UpdateCommandReturnsOnCall(i int, result1 *exec.Cmd) {
+	fake.UpdateCommandStub = nil
+	if fake.updateCommandReturnsOnCall == nil {
+		fake.updateCommandReturnsOnCall = make(map[int]struct {
+			result1 *exec.Cmd
+		})
+	}
+	fake.updateCommandReturnsOnCall[i] = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
 func (fake *FakeRuncBinary) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -398,6 +582,12 @@ func (fake *FakeRuncBinary) Invocations() map[string][][]interface{} {
 	defer fake.killCommandMutex.RUnlock()
 	fake.deleteCommandMutex.RLock()
 	defer fake.deleteCommandMutex.RUnlock()
+	fake.listCommandMutex.RLock()
+	defer fake.listCommandMutex.RUnlock()
+	fake.psCommandMutex.RLock()
+	defer fake.psCommandMutex.RUnlock()
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
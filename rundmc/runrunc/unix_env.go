@@ -43,3 +43,17 @@ func UnixEnvFor(bndl goci.Bndl, spec ProcessSpec) []string {
 
 	return envWithUser(envWithDefaultPath(defaultPath, requestedEnv), spec.User)
 }
+
+// UnixEnvWithDefaultPath behaves like UnixEnvFor, except that processes which
+// do not set a PATH are given defaultPath whichever user they run as. An empty
+// defaultPath keeps the UnixEnvFor defaults.
+func UnixEnvWithDefaultPath(defaultPath string) EnvFunc {
+	if defaultPath == "" {
+		return EnvFunc(UnixEnvFor)
+	}
+
+	return func(bndl goci.Bndl, spec ProcessSpec) []string {
+		requestedEnv := append(bndl.Spec.Process.Env, spec.Env...)
+		return envWithUser(envWithDefaultPath("PATH="+defaultPath, requestedEnv), spec.User)
+	}
+}
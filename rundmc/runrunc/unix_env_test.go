@@ -278,4 +278,39 @@ var _ = Describe("UnixEnvFor", func() {
 			})
 		})
 	})
+
+	Describe("UnixEnvWithDefaultPath", func() {
+		It("appends the configured PATH for both root and non-root users", func() {
+			envFor := runrunc.UnixEnvWithDefaultPath("/opt/bin:/bin")
+
+			rootEnv := envFor(goci.Bundle(), runrunc.ProcessSpec{
+				ProcessSpec:  garden.ProcessSpec{Env: []string{"a=1"}, User: "root"},
+				ContainerUID: 0,
+			})
+			Expect(rootEnv).To(Equal([]string{"a=1", "PATH=/opt/bin:/bin", "USER=root"}))
+
+			userEnv := envFor(goci.Bundle(), runrunc.ProcessSpec{
+				ProcessSpec:  garden.ProcessSpec{Env: []string{"a=1"}, User: "alice"},
+				ContainerUID: 1000,
+			})
+			Expect(userEnv).To(Equal([]string{"a=1", "PATH=/opt/bin:/bin", "USER=alice"}))
+		})
+
+		It("does not override a PATH set by the process", func() {
+			env := runrunc.UnixEnvWithDefaultPath("/opt/bin")(goci.Bundle(), runrunc.ProcessSpec{
+				ProcessSpec: garden.ProcessSpec{Env: []string{"PATH=a"}},
+			})
+			Expect(env).To(Equal([]string{"PATH=a", "USER=root"}))
+		})
+
+		Context("when the configured PATH is empty", func() {
+			It("falls back to the standard defaults", func() {
+				env := runrunc.UnixEnvWithDefaultPath("")(goci.Bundle(), runrunc.ProcessSpec{
+					ProcessSpec:  garden.ProcessSpec{User: "alice"},
+					ContainerUID: 1000,
+				})
+				Expect(env).To(Equal([]string{runrunc.DefaultPath, "USER=alice"}))
+			})
+		})
+	})
 })
@@ -0,0 +1,33 @@
+package runrunc
+
+import (
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+type Updater struct {
+	runner RuncCmdRunner
+	runc   RuncBinary
+}
+
+func NewUpdater(runner RuncCmdRunner, runc RuncBinary) *Updater {
+	return &Updater{
+		runner: runner,
+		runc:   runc,
+	}
+}
+
+// UpdateLimits applies new resource limits to the cgroups of a running
+// container
+func (u *Updater) UpdateLimits(log lager.Logger, id string, resources specs.LinuxResources) error {
+	log = log.Session("update-limits", lager.Data{"id": id})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	return u.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		return u.runc.UpdateCommand(id, resources, logFile)
+	})
+}
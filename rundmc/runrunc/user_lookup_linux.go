@@ -15,11 +15,18 @@ const (
 func LookupUser(rootFsPath, userName string) (*ExecUser, error) {
 	defaultUser := &user.ExecUser{Uid: DefaultUID, Gid: DefaultGID, Home: DefaultHome}
 	passwdPath := filepath.Join(rootFsPath, "etc", "passwd")
+	groupPath := filepath.Join(rootFsPath, "etc", "group")
 
-	execUser, err := user.GetExecUserPath(userName, defaultUser, passwdPath, "")
+	execUser, err := user.GetExecUserPath(userName, defaultUser, passwdPath, groupPath)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ExecUser{Uid: execUser.Uid, Gid: execUser.Gid, Home: execUser.Home, Sgids: execUser.Sgids}, nil
 }
+
+// LookupGroups resolves group names or IDs to IDs using the /etc/group of the
+// rootfs. IDs do not need to have an entry in /etc/group.
+func LookupGroups(rootFsPath string, groups []string) ([]int, error) {
+	return user.GetAdditionalGroupsPath(groups, filepath.Join(rootFsPath, "etc", "group"))
+}
@@ -54,6 +54,22 @@ _dovecot:*:214:6:Dovecot Administrator:/var/empty:/usr/bin/false`,
 				Expect(user.Gid).To(BeEquivalentTo(777))             // the GID of the beast
 				Expect(user.Home).To(Equal("/home/fieryunderworld")) // the Home of the beast
 			})
+
+			Context("when /etc/group lists the user as a member of groups", func() {
+				BeforeEach(func() {
+					Expect(ioutil.WriteFile(filepath.Join(rootFsPath, "etc", "group"), []byte(
+						`hell:x:777:
+furnace:x:800:devil,_lda
+choir:x:900:_dovecot`,
+					), 0777)).To(Succeed())
+				})
+
+				It("gets the user's supplementary groups", func() {
+					user, err := runrunc.LookupUser(rootFsPath, "devil")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(user.Sgids).To(ConsistOf(800))
+				})
+			})
 		})
 
 		Context("when /etc/passwd exists with no matching users", func() {
@@ -84,3 +100,33 @@ _dovecot:*:214:6:Dovecot Administrator:/var/empty:/usr/bin/false`,
 			}))
 	})
 })
+
+var _ = Describe("LookupGroups", func() {
+	var rootFsPath string
+
+	BeforeEach(func() {
+		var err error
+		rootFsPath, err = ioutil.TempDir("", "grouptestdir")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(rootFsPath, "etc"), 0777)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(rootFsPath, "etc", "group"), []byte(
+			`furnace:x:800:devil
+choir:x:900:`,
+		), 0777)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootFsPath)).To(Succeed())
+	})
+
+	It("resolves group names and IDs", func() {
+		gids, err := runrunc.LookupGroups(rootFsPath, []string{"choir", "1234"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gids).To(Equal([]int{900, 1234}))
+	})
+
+	It("returns an error for unknown group names", func() {
+		_, err := runrunc.LookupGroups(rootFsPath, []string{"nosuchgroup"})
+		Expect(err).To(HaveOccurred())
+	})
+})
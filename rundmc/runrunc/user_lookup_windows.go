@@ -1,6 +1,9 @@
 package runrunc
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 const (
 	DefaultUID  int    = 0
@@ -15,3 +18,10 @@ func LookupUser(rootFsPath, userName string) (*ExecUser, error) {
 	}
 	return user, nil
 }
+
+func LookupGroups(rootFsPath string, groups []string) ([]int, error) {
+	if len(groups) > 0 {
+		return nil, errors.New("additional groups are not supported on Windows")
+	}
+	return nil, nil
+}
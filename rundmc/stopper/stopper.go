@@ -1,6 +1,11 @@
 package stopper
 
-import "syscall"
+import (
+	"syscall"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+)
 
 //go:generate counterfeiter . Killer
 //go:generate counterfeiter . CgroupPathResolver
@@ -18,13 +23,22 @@ type Retrier interface {
 	Run(work func() error) error
 }
 
+// termCheckInterval is how often StopAll checks whether the processes it
+// sent TERM to have exited
+const termCheckInterval = 1 * time.Second
+
+// CgroupStopper stops the processes in a cgroup by sending them TERM, giving
+// them termTimeout on the clock to exit, and then KILLing whatever is left
+// until the retrier gives up.
 type CgroupStopper struct {
 	killer             Killer
 	retrier            Retrier
 	cgroupPathResolver CgroupPathResolver
+	clock              clock.Clock
+	termTimeout        time.Duration
 }
 
-func New(cgroupPathResolver CgroupPathResolver, killer Killer, retrier Retrier) *CgroupStopper {
+func New(cgroupPathResolver CgroupPathResolver, killer Killer, retrier Retrier, clock clock.Clock, termTimeout time.Duration) *CgroupStopper {
 	if killer == nil {
 		killer = DefaultKiller{}
 	}
@@ -33,5 +47,7 @@ func New(cgroupPathResolver CgroupPathResolver, killer Killer, retrier Retrier)
 		killer:             killer,
 		cgroupPathResolver: cgroupPathResolver,
 		retrier:            retrier,
+		clock:              clock,
+		termTimeout:        termTimeout,
 	}
 }
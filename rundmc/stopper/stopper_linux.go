@@ -8,7 +8,12 @@ import (
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 )
 
-func (stopper *CgroupStopper) StopAll(log lager.Logger, cgroupName string, exceptions []int, kill bool) error {
+// StopAll sends TERM to every process in the cgroup other than the
+// exceptions, until they have all exited or the TERM timeout has passed, and
+// then KILLs whatever is left. It returns the pids which were still running
+// when it escalated to KILL. If kill is true, TERM is skipped and nothing is
+// reported as escalated.
+func (stopper *CgroupStopper) StopAll(log lager.Logger, cgroupName string, exceptions []int, kill bool) ([]int, error) {
 	log = log.Session("stop-all", lager.Data{
 		"name": cgroupName,
 	})
@@ -18,43 +23,83 @@ func (stopper *CgroupStopper) StopAll(log lager.Logger, cgroupName string, excep
 
 	devicesSubsystemPath, err := stopper.cgroupPathResolver.Resolve(cgroupName, "devices")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var escalated []int
 	if !kill {
-		stopper.retrier.Run(func() error {
-			return stopper.killAllRemaining(syscall.SIGTERM, devicesSubsystemPath, exceptions)
-		})
+		stopper.terminate(devicesSubsystemPath, exceptions)
+
+		escalated, err = remainingPids(devicesSubsystemPath, exceptions)
+		if err != nil {
+			log.Error("listing-remaining-pids-failed", err)
+		}
+
+		if len(escalated) > 0 {
+			log.Info("escalating-to-kill", lager.Data{"pids": escalated})
+		}
 	}
 
 	stopper.retrier.Run(func() error {
 		return stopper.killAllRemaining(syscall.SIGKILL, devicesSubsystemPath, exceptions)
 	})
 
-	return nil // we killed, so everything must die
+	return escalated, nil // we killed, so everything must die
+}
+
+// terminate sends TERM to the remaining processes every termCheckInterval,
+// until none are left or the TERM timeout has passed on the clock
+func (stopper *CgroupStopper) terminate(cgroupPath string, exceptions []int) {
+	deadline := stopper.clock.Now().Add(stopper.termTimeout)
+
+	for {
+		if err := stopper.killAllRemaining(syscall.SIGTERM, cgroupPath, exceptions); err == nil {
+			return
+		}
+
+		remaining := deadline.Sub(stopper.clock.Now())
+		if remaining <= 0 {
+			return
+		}
+
+		if remaining > termCheckInterval {
+			remaining = termCheckInterval
+		}
+
+		stopper.clock.Sleep(remaining)
+	}
 }
 
 func (stopper *CgroupStopper) killAllRemaining(signal syscall.Signal, cgroupPath string, exceptions []int) error {
-	pidsInCgroup, err := cgroups.GetAllPids(cgroupPath)
+	pidsToKill, err := remainingPids(cgroupPath, exceptions)
 	if err != nil {
 		return err
 	}
 
-	var pidsToKill []int
+	if len(pidsToKill) == 0 {
+		return nil
+	}
+
+	stopper.killer.Kill(signal, pidsToKill...)
+	return fmt.Errorf("still running after signal %s, %v", signal, pidsToKill)
+}
+
+func remainingPids(cgroupPath string, exceptions []int) ([]int, error) {
+	pidsInCgroup, err := cgroups.GetAllPids(cgroupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
 	for _, pid := range pidsInCgroup {
 		if contains(exceptions, pid) {
 			continue
 		}
 
-		pidsToKill = append(pidsToKill, pid)
-	}
-
-	if len(pidsToKill) == 0 {
-		return nil
+		pids = append(pids, pid)
 	}
 
-	stopper.killer.Kill(signal, pidsToKill...)
-	return fmt.Errorf("still running after signal %s, %v", signal, pidsToKill)
+	return pids, nil
 }
 
 func contains(a []int, b int) bool {
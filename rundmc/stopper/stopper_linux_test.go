@@ -6,10 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"code.cloudfoundry.org/guardian/rundmc/stopper"
 	fakes "code.cloudfoundry.org/guardian/rundmc/stopper/stopperfakes"
 	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/pivotal-golang/clock/fakeclock"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -19,6 +22,7 @@ var _ = Describe("CgroupStopper", func() {
 		fakeCgroupResolver *fakes.FakeCgroupPathResolver
 		fakeKiller         *fakes.FakeKiller
 		fakeRetrier        *fakes.FakeRetrier
+		fakeClock          *fakeclock.FakeClock
 
 		subject                          *stopper.CgroupStopper
 		devicesCgroupPath, fakeCgroupDir string
@@ -28,6 +32,7 @@ var _ = Describe("CgroupStopper", func() {
 		fakeCgroupResolver = new(fakes.FakeCgroupPathResolver)
 		fakeKiller = new(fakes.FakeKiller)
 		fakeRetrier = new(fakes.FakeRetrier)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
 
 		var err error
 		fakeCgroupDir, err = ioutil.TempDir("", "fakecgroupdir")
@@ -48,7 +53,7 @@ var _ = Describe("CgroupStopper", func() {
 			return fn()
 		}
 
-		subject = stopper.New(fakeCgroupResolver, fakeKiller, fakeRetrier)
+		subject = stopper.New(fakeCgroupResolver, fakeKiller, fakeRetrier, fakeClock, 0)
 	})
 
 	AfterEach(func() {
@@ -56,34 +61,45 @@ var _ = Describe("CgroupStopper", func() {
 	})
 
 	It("does not send any signal to processes in the exceptions list", func() {
-		Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)).To(Succeed())
+		_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)
+		Expect(err).NotTo(HaveOccurred())
 		Expect(fakeKiller).To(HaveKilled(0, syscall.SIGTERM, 1, 9))
 		Expect(fakeKiller).To(HaveKilled(1, syscall.SIGKILL, 1, 9))
 	})
 
 	Context("when the kill flag is true", func() {
 		It("sends a KILL to all processes found in the cgroup", func() {
-			Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, true)).To(Succeed())
+			_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, true)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeKiller).To(HaveKilled(0, syscall.SIGKILL, 1, 3, 5, 9))
 		})
 
+		It("does not report any processes as escalated", func() {
+			escalated, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(escalated).To(BeEmpty())
+		})
+
 		It("does not send TERM to processes found in the cgroup", func() {
-			Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, true)).To(Succeed())
+			_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, true)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeKiller).NotTo(HaveKilled(0, syscall.SIGTERM, 1, 3, 5, 9))
 		})
 	})
 
 	Context("when the kill flag is false", func() {
 		It("eventually returns successfully even if the cgroup.procs is unchanged (because it eventually gives up and SIGKILLs)", func() {
-			Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)).To(Succeed())
+			_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("sends TERM to all the processes found in the cgroup", func() {
-			Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, false)).To(Succeed())
+			_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", nil, false)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeKiller).To(HaveKilled(0, syscall.SIGTERM, 1, 3, 5, 9))
 		})
 
-		It("repeatedly sends TERM and KILL to the remaining processes until the retrier gives up", func() {
+		It("sends KILL to the remaining processes until the retrier gives up", func() {
 			fakeRetrier.RunStub = func(fn func() error) error {
 				Expect(ioutil.WriteFile(filepath.Join(devicesCgroupPath, "cgroup.procs"), []byte(`3
 9`), 0700)).To(Succeed())
@@ -91,11 +107,12 @@ var _ = Describe("CgroupStopper", func() {
 				return err
 			}
 
-			Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)).To(Succeed())
+			_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)
+			Expect(err).NotTo(HaveOccurred())
 
-			Expect(fakeKiller).To(HaveKilled(0, syscall.SIGTERM, 3))
+			Expect(fakeKiller).To(HaveKilled(0, syscall.SIGTERM, 1, 3, 5))
 			Expect(fakeKiller).To(HaveKilled(1, syscall.SIGKILL, 3))
-			Expect(fakeRetrier.RunCallCount()).To(Equal(2))
+			Expect(fakeRetrier.RunCallCount()).To(Equal(1))
 		})
 
 		Describe("telling the retrier whether it should continue", func() {
@@ -107,9 +124,10 @@ var _ = Describe("CgroupStopper", func() {
 					return nil
 				}
 
-				Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)).To(Succeed())
+				_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)
+				Expect(err).NotTo(HaveOccurred())
 
-				Expect(fakeRetrier.RunCallCount()).To(Equal(2))
+				Expect(fakeRetrier.RunCallCount()).To(Equal(1))
 			})
 
 			It("tells the retrier that it is not yet done if there are processes left", func() {
@@ -118,22 +136,86 @@ var _ = Describe("CgroupStopper", func() {
 					return nil
 				}
 
-				Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)).To(Succeed())
+				_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)
+				Expect(err).NotTo(HaveOccurred())
 
-				Expect(fakeKiller).To(HaveKilled(0, syscall.SIGTERM, 1, 3, 5))
 				Expect(fakeKiller).To(HaveKilled(1, syscall.SIGKILL, 1, 3, 5))
-				Expect(fakeRetrier.RunCallCount()).To(Equal(2))
+				Expect(fakeRetrier.RunCallCount()).To(Equal(1))
 			})
 		})
 
-		Context("and processes are still in cgroup.procs after the TERM was sent as often as the retrier is willing", func() {
+		Context("when there is a TERM timeout", func() {
+			var stopped chan []int
+
+			BeforeEach(func() {
+				subject = stopper.New(fakeCgroupResolver, fakeKiller, fakeRetrier, fakeClock, 3*time.Second)
+				stopped = make(chan []int, 1)
+			})
+
+			stopAll := func() {
+				go func() {
+					defer GinkgoRecover()
+					escalated, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)
+					Expect(err).NotTo(HaveOccurred())
+					stopped <- escalated
+				}()
+			}
+
+			It("keeps sending TERM until the timeout has passed on the clock, and then KILLs", func() {
+				stopAll()
+
+				for i := 1; i <= 3; i++ {
+					Eventually(fakeClock.WatcherCount).Should(Equal(1))
+					Expect(fakeKiller).To(HaveKilled(i-1, syscall.SIGTERM, 1, 3, 5))
+					Consistently(stopped).ShouldNot(Receive())
+					fakeClock.Increment(time.Second)
+				}
+
+				Eventually(stopped).Should(Receive(ConsistOf(1, 3, 5)))
+				Expect(fakeKiller).To(HaveKilled(3, syscall.SIGTERM, 1, 3, 5))
+				Expect(fakeKiller).To(HaveKilled(4, syscall.SIGKILL, 1, 3, 5))
+			})
+
+			It("stops waiting as soon as the processes have exited", func() {
+				stopAll()
+
+				Eventually(fakeClock.WatcherCount).Should(Equal(1))
+				Expect(ioutil.WriteFile(filepath.Join(devicesCgroupPath, "cgroup.procs"), []byte(`9
+`), 0700)).To(Succeed())
+				fakeClock.Increment(time.Second)
+
+				Eventually(stopped).Should(Receive(BeEmpty()))
+				Expect(fakeKiller.KillCallCount()).To(Equal(1))
+			})
+		})
+
+		It("reports that nothing needed escalating when every process exits after TERM", func() {
+			fakeKiller.KillStub = func(signal syscall.Signal, pids ...int) {
+				Expect(ioutil.WriteFile(filepath.Join(devicesCgroupPath, "cgroup.procs"), []byte(`9
+`), 0700)).To(Succeed())
+			}
+
+			escalated, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{9}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(escalated).To(BeEmpty())
+		})
+
+		Context("and processes are still in cgroup.procs after the TERM timeout", func() {
 			It("eventually sends KILL to processes", func() {
-				Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)).To(Succeed())
+				_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeKiller).To(HaveKilled(1, syscall.SIGKILL, 1, 9))
 			})
 
+			It("reports the processes which needed escalating to KILL", func() {
+				escalated, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(escalated).To(ConsistOf(1, 9))
+			})
+
 			It("always returns success if it killed, because kill always works", func() {
-				Expect(subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)).To(Succeed())
+				_, err := subject.StopAll(lagertest.NewTestLogger("test"), "foo", []int{3, 5}, false)
+				Expect(err).NotTo(HaveOccurred())
 			})
 		})
 	})
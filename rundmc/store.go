@@ -3,6 +3,10 @@ package rundmc
 import (
 	"strings"
 	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	"code.cloudfoundry.org/lager"
 )
 
 //go:generate counterfeiter . Properties
@@ -40,6 +44,22 @@ func (e *events) Events(handle string) []string {
 	return nil
 }
 
+// lifecycleEvents records events in an EventStore and reports OOMs to a
+// LifecycleNotifier
+type lifecycleEvents struct {
+	EventStore
+	log       lager.Logger
+	lifecycle gardener.LifecycleNotifier
+}
+
+func (e *lifecycleEvents) OnEvent(handle, event string) error {
+	if event == runrunc.OOMEvent {
+		e.lifecycle.Notify(e.log, gardener.LifecycleEvent{Type: gardener.ContainerOOMedEvent, Handle: handle})
+	}
+
+	return e.EventStore.OnEvent(handle, event)
+}
+
 type states struct {
 	props Properties
 }
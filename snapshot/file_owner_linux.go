@@ -0,0 +1,11 @@
+package snapshot
+
+import (
+	"os"
+	"syscall"
+)
+
+func fileOwner(info os.FileInfo) (int, int) {
+	stat := info.Sys().(*syscall.Stat_t)
+	return int(stat.Uid), int(stat.Gid)
+}
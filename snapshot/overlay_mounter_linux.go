@@ -0,0 +1,27 @@
+package snapshot
+
+import (
+	"fmt"
+	"syscall"
+)
+
+type OverlayMounter struct{}
+
+func (OverlayMounter) MountOverlay(lowerDir, upperDir, workDir, target string) error {
+	data := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", lowerDir, upperDir, workDir)
+	if err := syscall.Mount("overlay", target, "overlay", 0, data); err != nil {
+		return fmt.Errorf("mount overlay on %s: %s", target, err)
+	}
+
+	return nil
+}
+
+// Unmount unmounts target, treating a target which is not mounted, e.g.
+// after a reboot, as already unmounted
+func (OverlayMounter) Unmount(target string) error {
+	if err := syscall.Unmount(target, 0); err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
+		return fmt.Errorf("unmount %s: %s", target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,510 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock"
+)
+
+const baseHandlePrefix = "snapshot-base-"
+
+//go:generate counterfeiter . VolumeStore
+//go:generate counterfeiter . Mounter
+
+// A VolumeStore provisions full root filesystems, e.g. the garden-shed graph
+type VolumeStore interface {
+	Create(log lager.Logger, handle string, spec gardener.RootfsSpec) (specs.Spec, error)
+	Destroy(log lager.Logger, handle string) error
+	Metrics(log lager.Logger, handle string, namespaced bool) (garden.ContainerDiskStat, error)
+	GC(log lager.Logger) error
+}
+
+type Mounter interface {
+	MountOverlay(lowerDir, upperDir, workDir, target string) error
+	Unmount(target string) error
+}
+
+// Snapshotter provisions container root filesystems as overlay snapshots of a
+// base rootfs which is created once per rootfs URL by the VolumeStore, so
+// that only the first container from an image pays for extracting or copying
+// it. Creates which the snapshots cannot serve, such as those with a disk
+// quota, are passed through to the VolumeStore. Specs with rootfs layers get
+// a base per layer, which are stacked as the overlay's lower dirs, so that
+// layers are shared between the containers of different combinations.
+type Snapshotter struct {
+	store   VolumeStore
+	mounter Mounter
+	dir     string
+	clock   clock.Clock
+
+	// Retention is how long an unused base rootfs is kept for reuse before
+	// GC destroys it
+	Retention time.Duration
+
+	// ContainerRootUID and ContainerRootGID own the root of the snapshots of
+	// unprivileged containers
+	ContainerRootUID int
+	ContainerRootGID int
+
+	// basesMutex guards the base and snapshot records, and baseLocks
+	// serialise the creation of each base, so that fetching one image does
+	// not hold up snapshots of the others
+	basesMutex sync.Mutex
+	baseLocks  map[string]*sync.Mutex
+}
+
+type base struct {
+	Handle     string
+	Namespaced bool
+	Spec       specs.Spec
+}
+
+type snapshot struct {
+	BaseKey   string
+	LayerKeys []string `json:",omitempty"`
+}
+
+func NewSnapshotter(store VolumeStore, mounter Mounter, dir string, retention time.Duration, rootUID, rootGID int, clock clock.Clock) *Snapshotter {
+	return &Snapshotter{
+		store:            store,
+		mounter:          mounter,
+		dir:              dir,
+		clock:            clock,
+		Retention:        retention,
+		ContainerRootUID: rootUID,
+		ContainerRootGID: rootGID,
+		baseLocks:        map[string]*sync.Mutex{},
+	}
+}
+
+func (s *Snapshotter) Create(log lager.Logger, handle string, spec gardener.RootfsSpec) (specs.Spec, error) {
+	key, ok := s.baseKey(spec)
+	if !ok {
+		if len(spec.Layers) > 0 {
+			return specs.Spec{}, errors.New("rootfs layers require a rootfs which can be snapshotted: a local directory or a docker image pinned by digest, without a disk quota")
+		}
+		return s.store.Create(log, handle, spec)
+	}
+
+	layerKeys, layerSpecs, err := s.layers(spec)
+	if err != nil {
+		return specs.Spec{}, err
+	}
+
+	log = log.Session("snapshot-create", lager.Data{"handle": handle, "rootfs": spec.RootFS.String()})
+
+	log.Info("start")
+	defer log.Info("finished")
+
+	containerDir := s.containerDir(handle)
+	upperDir := filepath.Join(containerDir, "upper")
+	workDir := filepath.Join(containerDir, "work")
+	rootfsDir := filepath.Join(containerDir, "rootfs")
+
+	b, layers, err := s.reserveBases(log, key, spec, layerKeys, layerSpecs, containerDir)
+	if err != nil {
+		return specs.Spec{}, err
+	}
+
+	// overlay stacks its lower dirs from the top down
+	lowerDirs := []string{b.Spec.Root.Path}
+	for _, layer := range layers {
+		lowerDirs = append([]string{layer.Spec.Root.Path}, lowerDirs...)
+	}
+
+	if err := s.makeSnapshotDirs(lowerDirs[0], spec.Namespaced, upperDir, workDir, rootfsDir); err != nil {
+		os.RemoveAll(containerDir)
+		return specs.Spec{}, fmt.Errorf("creating snapshot dir: %s", err)
+	}
+
+	if err := s.mounter.MountOverlay(strings.Join(lowerDirs, ":"), upperDir, workDir, rootfsDir); err != nil {
+		log.Error("mount-failed", err)
+		os.RemoveAll(containerDir)
+		return specs.Spec{}, fmt.Errorf("mounting snapshot: %s", err)
+	}
+
+	// the image config, e.g. the env, is that of the base rootfs, not its layers
+	runtimeSpec := b.Spec
+	runtimeSpec.Root = &specs.Root{Path: rootfsDir, Readonly: b.Spec.Root.Readonly}
+	return runtimeSpec, nil
+}
+
+// makeSnapshotDirs creates the dirs of a snapshot. The root of the snapshot
+// takes its mode and owner from the upper dir, so the upper dir gets the mode
+// of the root of the top lower dir, and its owner, or the container's root
+// user for unprivileged containers.
+func (s *Snapshotter) makeSnapshotDirs(topLowerDir string, namespaced bool, upperDir, workDir, rootfsDir string) error {
+	info, err := os.Stat(topLowerDir)
+	if err != nil {
+		return err
+	}
+
+	uid, gid := fileOwner(info)
+	if namespaced {
+		uid, gid = s.ContainerRootUID, s.ContainerRootGID
+	}
+
+	for _, dir := range []string{workDir, rootfsDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Mkdir(upperDir, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	// Mkdir applies the umask, and cannot set the sticky, setuid or setgid bits
+	if err := os.Chmod(upperDir, info.Mode()&(os.ModePerm|os.ModeSticky|os.ModeSetuid|os.ModeSetgid)); err != nil {
+		return err
+	}
+
+	return os.Chown(upperDir, uid, gid)
+}
+
+// AppliesRootfsLayers reports that the snapshotter stacks RootfsSpec.Layers
+// over the rootfs
+func (s *Snapshotter) AppliesRootfsLayers() bool {
+	return true
+}
+
+func (s *Snapshotter) Destroy(log lager.Logger, handle string) error {
+	containerDir := s.containerDir(handle)
+	if _, err := os.Stat(filepath.Join(containerDir, "snapshot.json")); os.IsNotExist(err) {
+		return s.store.Destroy(log, handle)
+	}
+
+	log = log.Session("snapshot-destroy", lager.Data{"handle": handle})
+
+	if err := s.mounter.Unmount(filepath.Join(containerDir, "rootfs")); err != nil {
+		log.Error("unmount-failed", err)
+		return fmt.Errorf("unmounting snapshot: %s", err)
+	}
+
+	return os.RemoveAll(containerDir)
+}
+
+// Metrics reports the bytes written by the container as exclusive, and those
+// plus the size of the shared base rootfs and layers as the total
+func (s *Snapshotter) Metrics(log lager.Logger, handle string, namespaced bool) (garden.ContainerDiskStat, error) {
+	var snap snapshot
+	if err := readJSON(filepath.Join(s.containerDir(handle), "snapshot.json"), &snap); os.IsNotExist(err) {
+		return s.store.Metrics(log, handle, namespaced)
+	} else if err != nil {
+		return garden.ContainerDiskStat{}, err
+	}
+
+	var shared uint64
+	for _, key := range append([]string{snap.BaseKey}, snap.LayerKeys...) {
+		var b base
+		if err := readJSON(s.basePath(key), &b); err != nil {
+			return garden.ContainerDiskStat{}, err
+		}
+
+		baseStat, err := s.store.Metrics(log, b.Handle, b.Namespaced)
+		if err != nil {
+			return garden.ContainerDiskStat{}, err
+		}
+		shared += baseStat.TotalBytesUsed
+	}
+
+	exclusive, err := du(filepath.Join(s.containerDir(handle), "upper"))
+	if err != nil {
+		return garden.ContainerDiskStat{}, err
+	}
+
+	return garden.ContainerDiskStat{
+		TotalBytesUsed:     shared + exclusive,
+		ExclusiveBytesUsed: exclusive,
+	}, nil
+}
+
+// GC destroys the base root filesystems which no snapshot uses and which
+// have not been used for longer than the retention period, and then collects
+// the garbage of the VolumeStore
+func (s *Snapshotter) GC(log lager.Logger) error {
+	log = log.Session("snapshot-gc")
+
+	s.basesMutex.Lock()
+	defer s.basesMutex.Unlock()
+
+	inUse, err := s.basesInUse()
+	if err != nil {
+		return err
+	}
+
+	baseFiles, err := filepath.Glob(filepath.Join(s.dir, "bases", "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range baseFiles {
+		key := strings.TrimSuffix(filepath.Base(path), ".json")
+		if inUse[key] {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || s.clock.Since(info.ModTime()) < s.Retention {
+			continue
+		}
+
+		var b base
+		if err := readJSON(path, &b); err != nil {
+			log.Error("reading-base-failed", err, lager.Data{"key": key})
+			continue
+		}
+
+		if err := s.store.Destroy(log, b.Handle); err != nil {
+			log.Error("destroying-base-failed", err, lager.Data{"key": key})
+			continue
+		}
+
+		os.Remove(path)
+		log.Info("destroyed-base", lager.Data{"key": key})
+	}
+
+	return s.store.GC(log)
+}
+
+// reserveBases records that the snapshot in containerDir uses the base
+// rootfs for key and those for the layer keys, so that GC cannot destroy them
+// while they are used, and then finds or creates them
+func (s *Snapshotter) reserveBases(log lager.Logger, key string, spec gardener.RootfsSpec, layerKeys []string, layerSpecs []gardener.RootfsSpec, containerDir string) (base, []base, error) {
+	if err := s.recordSnapshot(containerDir, snapshot{BaseKey: key, LayerKeys: layerKeys}); err != nil {
+		return base{}, nil, err
+	}
+
+	b, err := s.getOrCreateBase(log, key, spec)
+	if err != nil {
+		os.RemoveAll(containerDir)
+		return base{}, nil, err
+	}
+
+	var layers []base
+	for i, layerKey := range layerKeys {
+		layer, err := s.getOrCreateBase(log, layerKey, layerSpecs[i])
+		if err != nil {
+			os.RemoveAll(containerDir)
+			return base{}, nil, fmt.Errorf("creating rootfs layer %s: %s", layerSpecs[i].RootFS, err)
+		}
+		layers = append(layers, layer)
+	}
+
+	return b, layers, nil
+}
+
+func (s *Snapshotter) recordSnapshot(containerDir string, snap snapshot) error {
+	s.basesMutex.Lock()
+	defer s.basesMutex.Unlock()
+
+	if err := os.MkdirAll(containerDir, 0755); err != nil {
+		return fmt.Errorf("creating snapshot dir: %s", err)
+	}
+
+	return writeJSON(filepath.Join(containerDir, "snapshot.json"), snap)
+}
+
+// layers returns the base keys and specs of the rootfs layers of spec, each
+// of which must be able to be snapshotted
+func (s *Snapshotter) layers(spec gardener.RootfsSpec) ([]string, []gardener.RootfsSpec, error) {
+	var (
+		keys       []string
+		layerSpecs []gardener.RootfsSpec
+	)
+	for _, layer := range spec.Layers {
+		layerSpec := spec
+		layerSpec.RootFS = layer
+		layerSpec.Layers = nil
+
+		key, ok := s.baseKey(layerSpec)
+		if !ok {
+			return nil, nil, fmt.Errorf("rootfs layer %s cannot be snapshotted: it must be a local directory or a docker image pinned by digest", layer)
+		}
+
+		keys = append(keys, key)
+		layerSpecs = append(layerSpecs, layerSpec)
+	}
+
+	return keys, layerSpecs, nil
+}
+
+// getOrCreateBase finds or creates the base rootfs for key. Only the lock of
+// the key is held while the VolumeStore creates the base, which may mean
+// fetching an image.
+func (s *Snapshotter) getOrCreateBase(log lager.Logger, key string, spec gardener.RootfsSpec) (base, error) {
+	lock := s.baseLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	path := s.basePath(key)
+	b, err := s.readBase(path)
+	if err == nil {
+		return b, nil
+	}
+	if !os.IsNotExist(err) {
+		return base{}, err
+	}
+
+	log.Info("creating-base", lager.Data{"key": key})
+
+	b = base{Handle: baseHandlePrefix + key[:16], Namespaced: spec.Namespaced}
+	b.Spec, err = s.store.Create(log, b.Handle, spec)
+	if err != nil {
+		return base{}, err
+	}
+
+	return b, s.writeBase(path, b)
+}
+
+func (s *Snapshotter) baseLock(key string) *sync.Mutex {
+	s.basesMutex.Lock()
+	defer s.basesMutex.Unlock()
+
+	lock, ok := s.baseLocks[key]
+	if !ok {
+		lock = new(sync.Mutex)
+		s.baseLocks[key] = lock
+	}
+
+	return lock
+}
+
+func (s *Snapshotter) readBase(path string) (base, error) {
+	s.basesMutex.Lock()
+	defer s.basesMutex.Unlock()
+
+	var b base
+	if err := readJSON(path, &b); err != nil {
+		return base{}, err
+	}
+
+	s.touch(path)
+	return b, nil
+}
+
+func (s *Snapshotter) writeBase(path string, b base) error {
+	s.basesMutex.Lock()
+	defer s.basesMutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating bases dir: %s", err)
+	}
+
+	if err := writeJSON(path, b); err != nil {
+		return err
+	}
+
+	s.touch(path)
+	return nil
+}
+
+// touch records that the base was used now, as GC destroys the bases which
+// have not been used for the retention period
+func (s *Snapshotter) touch(path string) {
+	now := s.clock.Now()
+	os.Chtimes(path, now, now)
+}
+
+func (s *Snapshotter) basesInUse() (map[string]bool, error) {
+	snapshotFiles, err := filepath.Glob(filepath.Join(s.dir, "containers", "*", "snapshot.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := map[string]bool{}
+	for _, path := range snapshotFiles {
+		var snap snapshot
+		if err := readJSON(path, &snap); err != nil {
+			return nil, err
+		}
+		inUse[snap.BaseKey] = true
+		for _, key := range snap.LayerKeys {
+			inUse[key] = true
+		}
+	}
+
+	return inUse, nil
+}
+
+// baseKey identifies the base rootfs which can serve the spec. Only rootfs
+// URLs which always refer to the same contents can be snapshotted: local
+// directories, whose modification time is part of the key, and docker images
+// pinned by digest.
+func (s *Snapshotter) baseKey(spec gardener.RootfsSpec) (string, bool) {
+	if spec.QuotaSize > 0 || spec.RootFS == nil {
+		return "", false
+	}
+
+	id := spec.RootFS.String()
+	switch spec.RootFS.Scheme {
+	case "", "file":
+		info, err := os.Stat(spec.RootFS.Path)
+		if err != nil || !info.IsDir() {
+			return "", false
+		}
+		id = fmt.Sprintf("%s@%d", id, info.ModTime().UnixNano())
+	case "docker":
+		if !strings.Contains(id, "@sha256:") {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s:%t", id, spec.Namespaced)))), true
+}
+
+func (s *Snapshotter) containerDir(handle string) string {
+	return filepath.Join(s.dir, "containers", handle)
+}
+
+func (s *Snapshotter) basePath(key string) string {
+	return filepath.Join(s.dir, "bases", key+".json")
+}
+
+func du(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+
+	return size, err
+}
+
+func writeJSON(path string, value interface{}) error {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
+
+func readJSON(path string, value interface{}) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(contents, value)
+}
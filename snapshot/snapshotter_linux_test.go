@@ -0,0 +1,73 @@
+package snapshot_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/snapshot"
+	fakes "code.cloudfoundry.org/guardian/snapshot/snapshotfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshotter with overlay", func() {
+	var (
+		logger      *lagertest.TestLogger
+		tmpDir      string
+		snapshotter *snapshot.Snapshotter
+		rootfsSpec  gardener.RootfsSpec
+	)
+
+	BeforeEach(func() {
+		if os.Getuid() != 0 {
+			Skip("mounting overlays requires root")
+		}
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "snapshotter-overlay")
+		Expect(err).NotTo(HaveOccurred())
+
+		rootfsDir := filepath.Join(tmpDir, "rootfs")
+		Expect(os.Mkdir(rootfsDir, 0755)).To(Succeed())
+		baseDir := filepath.Join(tmpDir, "base")
+		Expect(os.Mkdir(baseDir, 0755)).To(Succeed())
+		Expect(os.Chmod(baseDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(baseDir, "file"), []byte("from-base"), 0644)).To(Succeed())
+
+		store := new(fakes.FakeVolumeStore)
+		store.CreateReturns(specs.Spec{Root: &specs.Root{Path: baseDir}}, nil)
+
+		logger = lagertest.NewTestLogger("test")
+		rootfsSpec = gardener.RootfsSpec{RootFS: &url.URL{Path: rootfsDir}, Namespaced: true}
+		snapshotter = snapshot.NewSnapshotter(store, snapshot.OverlayMounter{}, filepath.Join(tmpDir, "snapshots"), time.Hour, 4294967294, 4294967293, fakeclock.NewFakeClock(time.Now()))
+	})
+
+	AfterEach(func() {
+		if tmpDir != "" {
+			snapshotter.Destroy(logger, "first")
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		}
+	})
+
+	It("gives the root of the snapshot the base's mode, owned by the container's root user", func() {
+		runtimeSpec, err := snapshotter.Create(logger, "first", rootfsSpec)
+		Expect(err).NotTo(HaveOccurred())
+
+		info, err := os.Stat(runtimeSpec.Root.Path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		Expect(info.Sys().(*syscall.Stat_t).Uid).To(BeEquivalentTo(4294967294))
+		Expect(info.Sys().(*syscall.Stat_t).Gid).To(BeEquivalentTo(4294967293))
+
+		Expect(ioutil.ReadFile(filepath.Join(runtimeSpec.Root.Path, "file"))).To(Equal([]byte("from-base")))
+	})
+})
@@ -0,0 +1,462 @@
+package snapshot_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/snapshot"
+	fakes "code.cloudfoundry.org/guardian/snapshot/snapshotfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshotter", func() {
+	var (
+		logger      *lagertest.TestLogger
+		store       *fakes.FakeVolumeStore
+		mounter     *fakes.FakeMounter
+		clock       *fakeclock.FakeClock
+		tmpDir      string
+		rootfsDir   string
+		baseDir     string
+		snapshotDir string
+		rootfsSpec  gardener.RootfsSpec
+
+		snapshotter *snapshot.Snapshotter
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "snapshotter")
+		Expect(err).NotTo(HaveOccurred())
+
+		rootfsDir = filepath.Join(tmpDir, "rootfs")
+		Expect(os.Mkdir(rootfsDir, 0755)).To(Succeed())
+		snapshotDir = filepath.Join(tmpDir, "snapshots")
+
+		logger = lagertest.NewTestLogger("test")
+		store = new(fakes.FakeVolumeStore)
+		baseDir = filepath.Join(tmpDir, "graph", "base")
+		Expect(os.MkdirAll(baseDir, 0755)).To(Succeed())
+		store.CreateReturns(specs.Spec{
+			Root:    &specs.Root{Path: baseDir},
+			Process: &specs.Process{Env: []string{"PATH=/bin"}},
+		}, nil)
+		mounter = new(fakes.FakeMounter)
+		clock = fakeclock.NewFakeClock(time.Now())
+
+		rootfsSpec = gardener.RootfsSpec{RootFS: &url.URL{Path: rootfsDir}, Namespaced: true}
+
+		snapshotter = snapshot.NewSnapshotter(store, mounter, snapshotDir, time.Hour, 1000, 1001, clock)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	Describe("Create", func() {
+		It("creates a base rootfs for the first container from a rootfs", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(store.CreateCallCount()).To(Equal(1))
+			_, handle, spec := store.CreateArgsForCall(0)
+			Expect(handle).To(HavePrefix("snapshot-base-"))
+			Expect(spec).To(Equal(rootfsSpec))
+		})
+
+		It("mounts an overlay snapshot of the base rootfs", func() {
+			runtimeSpec, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mounter.MountOverlayCallCount()).To(Equal(1))
+			lower, upper, work, target := mounter.MountOverlayArgsForCall(0)
+			Expect(lower).To(Equal(baseDir))
+			Expect(upper).To(Equal(filepath.Join(snapshotDir, "containers", "first", "upper")))
+			Expect(work).To(Equal(filepath.Join(snapshotDir, "containers", "first", "work")))
+			Expect(target).To(Equal(filepath.Join(snapshotDir, "containers", "first", "rootfs")))
+
+			Expect(runtimeSpec.Root.Path).To(Equal(target))
+			Expect(runtimeSpec.Process.Env).To(Equal([]string{"PATH=/bin"}))
+		})
+
+		It("gives the snapshot's upper dir the mode of the base rootfs, owned by the container's root user", func() {
+			Expect(os.Chmod(baseDir, 0751)).To(Succeed())
+
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(filepath.Join(snapshotDir, "containers", "first", "upper"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0751)))
+			Expect(info.Sys().(*syscall.Stat_t).Uid).To(BeEquivalentTo(1000))
+			Expect(info.Sys().(*syscall.Stat_t).Gid).To(BeEquivalentTo(1001))
+		})
+
+		Context("when the container is privileged", func() {
+			BeforeEach(func() {
+				rootfsSpec.Namespaced = false
+				Expect(os.Chown(baseDir, 2000, 2001)).To(Succeed())
+			})
+
+			It("gives the snapshot's upper dir the owner of the base rootfs", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(snapshotDir, "containers", "first", "upper"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Sys().(*syscall.Stat_t).Uid).To(BeEquivalentTo(2000))
+				Expect(info.Sys().(*syscall.Stat_t).Gid).To(BeEquivalentTo(2001))
+			})
+		})
+
+		It("does not hold up snapshots of other rootfses while creating a base rootfs", func() {
+			otherRootfsDir := filepath.Join(tmpDir, "other-rootfs")
+			Expect(os.Mkdir(otherRootfsDir, 0755)).To(Succeed())
+
+			fetching := make(chan struct{})
+			release := make(chan struct{})
+			store.CreateStub = func(_ lager.Logger, _ string, spec gardener.RootfsSpec) (specs.Spec, error) {
+				if spec.RootFS.Path == rootfsDir {
+					close(fetching)
+					<-release
+				}
+				return specs.Spec{Root: &specs.Root{Path: baseDir}}, nil
+			}
+
+			done := make(chan error)
+			go func() {
+				_, err := snapshotter.Create(logger, "slow", rootfsSpec)
+				done <- err
+			}()
+			<-fetching
+
+			_, err := snapshotter.Create(logger, "fast", gardener.RootfsSpec{RootFS: &url.URL{Path: otherRootfsDir}, Namespaced: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			close(release)
+			Expect(<-done).To(Succeed())
+		})
+
+		It("reuses the base rootfs for later containers from the same rootfs", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = snapshotter.Create(logger, "second", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(store.CreateCallCount()).To(Equal(1))
+			Expect(mounter.MountOverlayCallCount()).To(Equal(2))
+			lower, _, _, _ := mounter.MountOverlayArgsForCall(1)
+			Expect(lower).To(Equal(baseDir))
+		})
+
+		It("does not share a base rootfs between namespaced and privileged containers", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			rootfsSpec.Namespaced = false
+			_, err = snapshotter.Create(logger, "second", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(store.CreateCallCount()).To(Equal(2))
+		})
+
+		It("creates a new base rootfs when the rootfs directory changes", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			later := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(rootfsDir, later, later)).To(Succeed())
+			_, err = snapshotter.Create(logger, "second", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(store.CreateCallCount()).To(Equal(2))
+		})
+
+		Context("when the spec has a disk quota", func() {
+			BeforeEach(func() {
+				rootfsSpec.QuotaSize = 1024
+			})
+
+			It("passes the create through to the store", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, handle, _ := store.CreateArgsForCall(0)
+				Expect(handle).To(Equal("first"))
+				Expect(mounter.MountOverlayCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the rootfs is a docker image which is not pinned by digest", func() {
+			BeforeEach(func() {
+				rootfsSpec.RootFS = &url.URL{Scheme: "docker", Path: "/busybox", Fragment: "latest"}
+			})
+
+			It("passes the create through to the store", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, handle, _ := store.CreateArgsForCall(0)
+				Expect(handle).To(Equal("first"))
+			})
+		})
+
+		Context("when the rootfs is a docker image pinned by digest", func() {
+			BeforeEach(func() {
+				rootfsSpec.RootFS = &url.URL{Scheme: "docker", Path: "/busybox@sha256:abc123"}
+			})
+
+			It("snapshots it", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, handle, _ := store.CreateArgsForCall(0)
+				Expect(handle).To(HavePrefix("snapshot-base-"))
+			})
+		})
+
+		Context("when creating the base rootfs fails", func() {
+			BeforeEach(func() {
+				store.CreateReturns(specs.Spec{}, errors.New("fetch failed"))
+			})
+
+			It("returns the error and creates it again next time", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).To(MatchError("fetch failed"))
+
+				snapshotter.Create(logger, "second", rootfsSpec)
+				Expect(store.CreateCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when mounting the snapshot fails", func() {
+			BeforeEach(func() {
+				mounter.MountOverlayReturns(errors.New("no overlay"))
+			})
+
+			It("returns the error and removes the snapshot", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).To(MatchError(ContainSubstring("no overlay")))
+				Expect(filepath.Join(snapshotDir, "containers", "first")).NotTo(BeADirectory())
+			})
+		})
+
+		Context("when the spec has rootfs layers", func() {
+			var layerDir string
+
+			BeforeEach(func() {
+				layerDir = filepath.Join(tmpDir, "layer")
+				Expect(os.Mkdir(layerDir, 0755)).To(Succeed())
+
+				store.CreateStub = func(_ lager.Logger, handle string, spec gardener.RootfsSpec) (specs.Spec, error) {
+					root := filepath.Join(tmpDir, "graph", filepath.Base(spec.RootFS.Path))
+					Expect(os.MkdirAll(root, 0755)).To(Succeed())
+					return specs.Spec{
+						Root:    &specs.Root{Path: root},
+						Process: &specs.Process{Env: []string{"PATH=/bin"}},
+					}, nil
+				}
+
+				rootfsSpec.Layers = []*url.URL{
+					{Path: layerDir},
+					{Scheme: "docker", Path: "/framework@sha256:abc"},
+				}
+			})
+
+			It("creates a base for the rootfs and each of its layers", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(store.CreateCallCount()).To(Equal(3))
+				_, _, layerSpec := store.CreateArgsForCall(1)
+				Expect(layerSpec.RootFS.Path).To(Equal(layerDir))
+				Expect(layerSpec.Layers).To(BeEmpty())
+				Expect(layerSpec.Namespaced).To(BeTrue())
+			})
+
+			It("mounts the layers over the rootfs, top layer first", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				lower, _, _, _ := mounter.MountOverlayArgsForCall(0)
+				graph := filepath.Join(tmpDir, "graph")
+				Expect(lower).To(Equal(strings.Join([]string{
+					filepath.Join(graph, "framework@sha256:abc"),
+					filepath.Join(graph, "layer"),
+					filepath.Join(graph, "rootfs"),
+				}, ":")))
+			})
+
+			It("shares the layers with containers from other rootfses", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherRootfsDir := filepath.Join(tmpDir, "other-rootfs")
+				Expect(os.Mkdir(otherRootfsDir, 0755)).To(Succeed())
+				rootfsSpec.RootFS = &url.URL{Path: otherRootfsDir}
+				_, err = snapshotter.Create(logger, "second", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(store.CreateCallCount()).To(Equal(4))
+			})
+
+			Context("when a layer cannot be snapshotted", func() {
+				BeforeEach(func() {
+					rootfsSpec.Layers = append(rootfsSpec.Layers, &url.URL{Scheme: "docker", Path: "/framework"})
+				})
+
+				It("returns an error without creating anything", func() {
+					_, err := snapshotter.Create(logger, "first", rootfsSpec)
+					Expect(err).To(MatchError(ContainSubstring("cannot be snapshotted")))
+					Expect(store.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the spec has a disk quota", func() {
+				BeforeEach(func() {
+					rootfsSpec.QuotaSize = 1024
+				})
+
+				It("returns an error rather than passing the create through to the store", func() {
+					_, err := snapshotter.Create(logger, "first", rootfsSpec)
+					Expect(err).To(HaveOccurred())
+					Expect(store.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the snapshot is destroyed", func() {
+				It("keeps the layers until the retention period has passed", func() {
+					_, err := snapshotter.Create(logger, "first", rootfsSpec)
+					Expect(err).NotTo(HaveOccurred())
+
+					clock.Increment(2 * time.Hour)
+					Expect(snapshotter.GC(logger)).To(Succeed())
+					Expect(store.DestroyCallCount()).To(Equal(0))
+
+					Expect(snapshotter.Destroy(logger, "first")).To(Succeed())
+					clock.Increment(2 * time.Hour)
+					Expect(snapshotter.GC(logger)).To(Succeed())
+					Expect(store.DestroyCallCount()).To(Equal(3))
+				})
+			})
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("unmounts and removes a snapshot", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(snapshotter.Destroy(logger, "first")).To(Succeed())
+
+			Expect(mounter.UnmountCallCount()).To(Equal(1))
+			Expect(mounter.UnmountArgsForCall(0)).To(Equal(filepath.Join(snapshotDir, "containers", "first", "rootfs")))
+			Expect(filepath.Join(snapshotDir, "containers", "first")).NotTo(BeADirectory())
+			Expect(store.DestroyCallCount()).To(Equal(0))
+		})
+
+		It("passes the destroy of anything which is not a snapshot through to the store", func() {
+			Expect(snapshotter.Destroy(logger, "not-a-snapshot")).To(Succeed())
+
+			Expect(store.DestroyCallCount()).To(Equal(1))
+			_, handle := store.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("not-a-snapshot"))
+		})
+
+		Context("when unmounting fails", func() {
+			BeforeEach(func() {
+				mounter.UnmountReturns(errors.New("busy"))
+			})
+
+			It("returns the error and keeps the snapshot", func() {
+				_, err := snapshotter.Create(logger, "first", rootfsSpec)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(snapshotter.Destroy(logger, "first")).To(MatchError(ContainSubstring("busy")))
+				Expect(filepath.Join(snapshotDir, "containers", "first")).To(BeADirectory())
+			})
+		})
+	})
+
+	Describe("Metrics", func() {
+		BeforeEach(func() {
+			store.MetricsReturns(garden.ContainerDiskStat{TotalBytesUsed: 1000, ExclusiveBytesUsed: 1000}, nil)
+		})
+
+		It("reports the bytes written to the snapshot as exclusive", func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(snapshotDir, "containers", "first", "upper", "file"), make([]byte, 24), 0600)).To(Succeed())
+
+			stat, err := snapshotter.Metrics(logger, "first", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stat).To(Equal(garden.ContainerDiskStat{TotalBytesUsed: 1024, ExclusiveBytesUsed: 24}))
+
+			_, handle, namespaced := store.MetricsArgsForCall(0)
+			Expect(handle).To(HavePrefix("snapshot-base-"))
+			Expect(namespaced).To(BeTrue())
+		})
+
+		It("passes the metrics of anything which is not a snapshot through to the store", func() {
+			stat, err := snapshotter.Metrics(logger, "not-a-snapshot", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stat.TotalBytesUsed).To(BeEquivalentTo(1000))
+		})
+	})
+
+	Describe("GC", func() {
+		BeforeEach(func() {
+			_, err := snapshotter.Create(logger, "first", rootfsSpec)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("collects the garbage of the store", func() {
+			Expect(snapshotter.GC(logger)).To(Succeed())
+			Expect(store.GCCallCount()).To(Equal(1))
+		})
+
+		It("does not destroy a base rootfs which a snapshot uses", func() {
+			clock.Increment(2 * time.Hour)
+			Expect(snapshotter.GC(logger)).To(Succeed())
+			Expect(store.DestroyCallCount()).To(Equal(0))
+		})
+
+		Context("when no snapshot uses the base rootfs", func() {
+			BeforeEach(func() {
+				Expect(snapshotter.Destroy(logger, "first")).To(Succeed())
+			})
+
+			It("keeps it for the retention period", func() {
+				clock.Increment(30 * time.Minute)
+				Expect(snapshotter.GC(logger)).To(Succeed())
+				Expect(store.DestroyCallCount()).To(Equal(0))
+			})
+
+			It("destroys it once the retention period has passed", func() {
+				clock.Increment(2 * time.Hour)
+				Expect(snapshotter.GC(logger)).To(Succeed())
+
+				Expect(store.DestroyCallCount()).To(Equal(1))
+				_, handle := store.DestroyArgsForCall(0)
+				Expect(handle).To(HavePrefix("snapshot-base-"))
+
+				snapshotter.Create(logger, "second", rootfsSpec)
+				Expect(store.CreateCallCount()).To(Equal(2))
+			})
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// KernelFeatureError is returned in place of the raw runc or mount error when
+// a container could not be created because the host kernel lacks a feature
+// the container needs.
+type KernelFeatureError struct {
+	Feature string
+	Reason  error
+	Cause   error
+}
+
+func (e KernelFeatureError) Error() string {
+	return fmt.Sprintf("the host does not support %s: %s (create failed with: %s)", e.Feature, e.Reason, e.Cause)
+}
+
+// Unwrap returns the error the create failed with
+func (e KernelFeatureError) Unwrap() error {
+	return e.Cause
+}
+
+// Fragments of the errors which the creation of a container fails with when
+// the host lacks a feature. A missing feature is only blamed for a failure
+// which mentions one of them, so that an unrelated failure on a host which
+// happens to lack the feature is reported unchanged.
+var (
+	userNamespaceSymptoms  = []string{"user namespace", "uid_map", "gid_map", "setgroups", "unshare", "no space left on device"}
+	layeredFSSymptoms      = []string{"overlay", "aufs", "unknown filesystem type", "no such device"}
+	swapAccountingSymptoms = []string{"memsw"}
+)
+
+// KernelFeatureDiagnoser works out whether a failed create was caused by a
+// missing kernel feature. Only the features that the container spec actually
+// needs, and whose absence the failure is consistent with, are checked, so
+// that unrelated failures are reported unchanged.
+type KernelFeatureDiagnoser struct {
+	ProcPath string
+
+	// MemoryCgroupPath is the root of the memory cgroup hierarchy, used to
+	// detect whether swap accounting is enabled
+	MemoryCgroupPath string
+
+	// RequireLayeredFS should be false when an image plugin is responsible for
+	// producing root filesystems.
+	RequireLayeredFS bool
+}
+
+// Diagnose returns a KernelFeatureError if createErr can be explained by a
+// missing kernel feature, or nil otherwise.
+func (d KernelFeatureDiagnoser) Diagnose(spec garden.ContainerSpec, createErr error) error {
+	checker := PreflightChecker{ProcPath: d.ProcPath}
+
+	if !spec.Privileged && mentions(createErr, userNamespaceSymptoms) {
+		if err := checker.checkUserNamespaces(); err != nil {
+			return KernelFeatureError{Feature: "user namespaces", Reason: err, Cause: createErr}
+		}
+	}
+
+	if d.RequireLayeredFS && mentions(createErr, layeredFSSymptoms) {
+		if err := checker.checkLayeredFS(); err != nil {
+			return KernelFeatureError{Feature: "layered filesystems", Reason: err, Cause: createErr}
+		}
+	}
+
+	if spec.Limits.Memory.LimitInBytes > 0 && mentions(createErr, swapAccountingSymptoms) {
+		if err := d.checkSwapAccounting(); err != nil {
+			return KernelFeatureError{Feature: "memory swap accounting", Reason: err, Cause: createErr}
+		}
+	}
+
+	return nil
+}
+
+func mentions(err error, fragments []string) bool {
+	message := strings.ToLower(err.Error())
+	for _, fragment := range fragments {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d KernelFeatureDiagnoser) checkSwapAccounting() error {
+	if _, err := os.Stat(filepath.Join(d.MemoryCgroupPath, "memory.memsw.limit_in_bytes")); os.IsNotExist(err) {
+		return fmt.Errorf("swap accounting is disabled: boot the kernel with the swapaccount=1 parameter")
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package sysinfo_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/sysinfo"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KernelFeatureDiagnoser", func() {
+	var (
+		procPath   string
+		memoryPath string
+		spec       garden.ContainerSpec
+		createErr  error
+		diagnoser  sysinfo.KernelFeatureDiagnoser
+	)
+
+	writeFile := func(path, contents string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		procPath, err = ioutil.TempDir("", "diagnoser-proc")
+		Expect(err).NotTo(HaveOccurred())
+		memoryPath, err = ioutil.TempDir("", "diagnoser-memory")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeFile(filepath.Join(procPath, "self", "ns", "user"), "")
+		writeFile(filepath.Join(procPath, "filesystems"), "nodev\tsysfs\n\text4\nnodev\toverlay\n")
+		writeFile(filepath.Join(memoryPath, "memory.memsw.limit_in_bytes"), "9223372036854771712\n")
+
+		spec = garden.ContainerSpec{Handle: "banana"}
+		spec.Limits.Memory.LimitInBytes = 1024 * 1024
+		createErr = errors.New("runc create failed")
+
+		diagnoser = sysinfo.KernelFeatureDiagnoser{
+			ProcPath:         procPath,
+			MemoryCgroupPath: memoryPath,
+			RequireLayeredFS: true,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(procPath)).To(Succeed())
+		Expect(os.RemoveAll(memoryPath)).To(Succeed())
+	})
+
+	It("returns nil when the host has every feature the container needs", func() {
+		Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+	})
+
+	Context("when user namespaces are disabled", func() {
+		BeforeEach(func() {
+			writeFile(filepath.Join(procPath, "sys", "user", "max_user_namespaces"), "0\n")
+			createErr = errors.New("runc create failed: nsenter: failed to unshare namespaces: No space left on device")
+		})
+
+		It("returns a kernel feature error including the original error", func() {
+			err := diagnoser.Diagnose(spec, createErr)
+			Expect(err).To(BeAssignableToTypeOf(sysinfo.KernelFeatureError{}))
+			Expect(err.(sysinfo.KernelFeatureError).Feature).To(Equal("user namespaces"))
+			Expect(err.(sysinfo.KernelFeatureError).Unwrap()).To(Equal(createErr))
+			Expect(err).To(MatchError(ContainSubstring("max_user_namespaces")))
+			Expect(err).To(MatchError(ContainSubstring("runc create failed")))
+		})
+
+		Context("and the failure does not look like a user namespace failure", func() {
+			BeforeEach(func() {
+				createErr = errors.New("runc create failed: exec: \"potato\": executable file not found")
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+
+		Context("and the container is privileged", func() {
+			BeforeEach(func() {
+				spec.Privileged = true
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+	})
+
+	Context("when neither overlay nor aufs is available", func() {
+		BeforeEach(func() {
+			writeFile(filepath.Join(procPath, "filesystems"), "nodev\tsysfs\n\text4\n")
+			createErr = errors.New("mounting rootfs: unknown filesystem type 'overlay'")
+		})
+
+		It("returns a kernel feature error", func() {
+			err := diagnoser.Diagnose(spec, createErr)
+			Expect(err).To(MatchError(ContainSubstring("modprobe overlay")))
+		})
+
+		Context("and the failure does not look like a mount failure", func() {
+			BeforeEach(func() {
+				createErr = errors.New("image not found")
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+
+		Context("and a layered filesystem is not required", func() {
+			BeforeEach(func() {
+				diagnoser.RequireLayeredFS = false
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+	})
+
+	Context("when swap accounting is disabled", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(filepath.Join(memoryPath, "memory.memsw.limit_in_bytes"))).To(Succeed())
+			createErr = errors.New("runc create failed: open /sys/fs/cgroup/memory/banana/memory.memsw.limit_in_bytes: no such file or directory")
+		})
+
+		It("returns a kernel feature error", func() {
+			err := diagnoser.Diagnose(spec, createErr)
+			Expect(err).To(MatchError(ContainSubstring("swapaccount=1")))
+		})
+
+		Context("and the failure does not mention swap accounting", func() {
+			BeforeEach(func() {
+				createErr = errors.New("runc create failed: cgroup mount failed")
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+
+		Context("and the container has no memory limit", func() {
+			BeforeEach(func() {
+				spec.Limits.Memory.LimitInBytes = 0
+			})
+
+			It("returns nil", func() {
+				Expect(diagnoser.Diagnose(spec, createErr)).To(Succeed())
+			})
+		})
+	})
+})
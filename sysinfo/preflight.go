@@ -0,0 +1,202 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var requiredCgroupSubsystems = []string{"cpu", "cpuacct", "cpuset", "memory", "devices", "blkio", "freezer"}
+
+type PreflightResult struct {
+	Name string
+	Err  error
+}
+
+// PreflightChecker validates that the host is able to run garden containers.
+// Each check reports an error describing what is wrong and how to fix it.
+type PreflightChecker struct {
+	ProcPath         string
+	MinKernelVersion string
+	IPTablesBin      string
+	Dirs             []string
+
+	// RequireLayeredFS should be false when an image plugin is responsible for
+	// producing root filesystems.
+	RequireLayeredFS bool
+}
+
+func (c PreflightChecker) Check() []PreflightResult {
+	results := []PreflightResult{
+		{Name: "kernel-version", Err: c.checkKernelVersion()},
+		{Name: "cgroup-subsystems", Err: c.checkCgroupSubsystems()},
+		{Name: "user-namespaces", Err: c.checkUserNamespaces()},
+		{Name: "iptables", Err: c.checkIPTables()},
+	}
+
+	if c.RequireLayeredFS {
+		results = append(results, PreflightResult{Name: "layered-filesystem", Err: c.checkLayeredFS()})
+	}
+
+	for _, dir := range c.Dirs {
+		results = append(results, PreflightResult{Name: "directory " + dir, Err: checkWritableDir(dir)})
+	}
+
+	return results
+}
+
+func (c PreflightChecker) checkKernelVersion() error {
+	release, err := ioutil.ReadFile(filepath.Join(c.ProcPath, "sys", "kernel", "osrelease"))
+	if err != nil {
+		return fmt.Errorf("reading kernel release: %s", err)
+	}
+
+	actual, err := parseKernelVersion(string(release))
+	if err != nil {
+		return err
+	}
+
+	min, err := parseKernelVersion(c.MinKernelVersion)
+	if err != nil {
+		return err
+	}
+
+	if actual[0] < min[0] || (actual[0] == min[0] && actual[1] < min[1]) {
+		return fmt.Errorf("kernel %s is too old: upgrade to %s or later", strings.TrimSpace(string(release)), c.MinKernelVersion)
+	}
+
+	return nil
+}
+
+func parseKernelVersion(release string) ([2]int, error) {
+	var version [2]int
+
+	parts := strings.SplitN(strings.TrimSpace(release), ".", 3)
+	if len(parts) < 2 {
+		return version, fmt.Errorf("unknown kernel version format: %s", release)
+	}
+
+	for i := range version {
+		digits := strings.TrimRightFunc(parts[i], func(r rune) bool { return r < '0' || r > '9' })
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return version, fmt.Errorf("unknown kernel version format: %s", release)
+		}
+		version[i] = n
+	}
+
+	return version, nil
+}
+
+func (c PreflightChecker) checkCgroupSubsystems() error {
+	f, err := os.Open(filepath.Join(c.ProcPath, "cgroups"))
+	if err != nil {
+		return fmt.Errorf("reading cgroups: %s: the kernel must be built with cgroup support", err)
+	}
+	defer f.Close()
+
+	enabled := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		enabled[fields[0]] = fields[3] == "1"
+	}
+
+	var missing []string
+	for _, subsystem := range requiredCgroupSubsystems {
+		if !enabled[subsystem] {
+			missing = append(missing, subsystem)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("cgroup subsystems not enabled: %s: check the kernel config and the cgroup_enable/cgroup_disable boot parameters", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (c PreflightChecker) checkUserNamespaces() error {
+	if _, err := os.Stat(filepath.Join(c.ProcPath, "self", "ns", "user")); err != nil {
+		return fmt.Errorf("user namespaces are not supported: the kernel must be built with CONFIG_USER_NS")
+	}
+
+	max, err := ioutil.ReadFile(filepath.Join(c.ProcPath, "sys", "user", "max_user_namespaces"))
+	if err == nil && strings.TrimSpace(string(max)) == "0" {
+		return fmt.Errorf("user namespaces are disabled: set user.max_user_namespaces to a non-zero value with sysctl")
+	}
+
+	return nil
+}
+
+func (c PreflightChecker) checkIPTables() error {
+	info, err := os.Stat(c.IPTablesBin)
+	if err != nil {
+		return fmt.Errorf("iptables not found: %s: install iptables or pass --iptables-bin", err)
+	}
+
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("iptables binary '%s' is not executable", c.IPTablesBin)
+	}
+
+	return nil
+}
+
+func (c PreflightChecker) checkLayeredFS() error {
+	filesystems, err := ioutil.ReadFile(filepath.Join(c.ProcPath, "filesystems"))
+	if err != nil {
+		return fmt.Errorf("reading supported filesystems: %s", err)
+	}
+
+	for _, line := range strings.Split(string(filesystems), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[len(fields)-1] {
+		case "aufs", "overlay":
+			return nil
+		}
+	}
+
+	return fmt.Errorf("neither aufs nor overlay is supported: load the module with 'modprobe aufs' or 'modprobe overlay', or configure an image plugin")
+}
+
+func checkWritableDir(dir string) error {
+	// the directory is created on startup, so only its nearest existing
+	// ancestor needs to be writable
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("'%s' is not a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+
+	probe, err := ioutil.TempFile(dir, ".gdn-check")
+	if err != nil {
+		return fmt.Errorf("'%s' is not writable: %s: fix its permissions or choose another directory", dir, err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
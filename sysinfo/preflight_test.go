@@ -0,0 +1,201 @@
+package sysinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/sysinfo"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreflightChecker", func() {
+	var (
+		procPath    string
+		iptablesBin string
+		depotDir    string
+		checker     sysinfo.PreflightChecker
+	)
+
+	writeProcFile := func(path, contents string) {
+		fullPath := filepath.Join(procPath, path)
+		Expect(os.MkdirAll(filepath.Dir(fullPath), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(fullPath, []byte(contents), 0644)).To(Succeed())
+	}
+
+	resultFor := func(name string) error {
+		for _, result := range checker.Check() {
+			if result.Name == name {
+				return result.Err
+			}
+		}
+		Fail("no result for " + name)
+		return nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		procPath, err = ioutil.TempDir("", "preflight-proc")
+		Expect(err).NotTo(HaveOccurred())
+		depotDir, err = ioutil.TempDir("", "preflight-depot")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeProcFile("sys/kernel/osrelease", "4.4.0-98-generic\n")
+		writeProcFile("cgroups", `#subsys_name	hierarchy	num_cgroups	enabled
+cpuset	2	4	1
+cpu	3	64	1
+cpuacct	3	64	1
+blkio	4	64	1
+memory	5	106	1
+devices	6	64	1
+freezer	7	4	1
+`)
+		writeProcFile("self/ns/user", "")
+		writeProcFile("filesystems", "nodev\tsysfs\n\text4\nnodev\taufs\n")
+
+		iptablesBin = filepath.Join(procPath, "iptables")
+		Expect(ioutil.WriteFile(iptablesBin, []byte{}, 0755)).To(Succeed())
+
+		checker = sysinfo.PreflightChecker{
+			ProcPath:         procPath,
+			MinKernelVersion: "4.4",
+			IPTablesBin:      iptablesBin,
+			Dirs:             []string{depotDir},
+			RequireLayeredFS: true,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(procPath)).To(Succeed())
+		Expect(os.RemoveAll(depotDir)).To(Succeed())
+	})
+
+	It("passes every check on a suitable host", func() {
+		for _, result := range checker.Check() {
+			Expect(result.Err).NotTo(HaveOccurred(), result.Name)
+		}
+	})
+
+	Context("when the kernel is older than the minimum version", func() {
+		BeforeEach(func() {
+			writeProcFile("sys/kernel/osrelease", "3.19.0-25-generic\n")
+		})
+
+		It("reports the required version", func() {
+			Expect(resultFor("kernel-version")).To(MatchError(ContainSubstring("upgrade to 4.4 or later")))
+		})
+	})
+
+	Context("when the kernel version cannot be parsed", func() {
+		BeforeEach(func() {
+			writeProcFile("sys/kernel/osrelease", "banana\n")
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("kernel-version")).To(MatchError(ContainSubstring("unknown kernel version format")))
+		})
+	})
+
+	Context("when a required cgroup subsystem is disabled", func() {
+		BeforeEach(func() {
+			writeProcFile("cgroups", `#subsys_name	hierarchy	num_cgroups	enabled
+cpuset	2	4	1
+cpu	3	64	1
+cpuacct	3	64	1
+blkio	4	64	1
+memory	5	106	0
+devices	6	64	1
+`)
+		})
+
+		It("lists the missing subsystems", func() {
+			Expect(resultFor("cgroup-subsystems")).To(MatchError(ContainSubstring("memory, freezer")))
+		})
+	})
+
+	Context("when user namespaces are not supported", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(filepath.Join(procPath, "self", "ns", "user"))).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("user-namespaces")).To(MatchError(ContainSubstring("CONFIG_USER_NS")))
+		})
+	})
+
+	Context("when user namespaces are disabled with sysctl", func() {
+		BeforeEach(func() {
+			writeProcFile("sys/user/max_user_namespaces", "0\n")
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("user-namespaces")).To(MatchError(ContainSubstring("max_user_namespaces")))
+		})
+	})
+
+	Context("when iptables does not exist", func() {
+		BeforeEach(func() {
+			Expect(os.Remove(iptablesBin)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("iptables")).To(MatchError(ContainSubstring("iptables not found")))
+		})
+	})
+
+	Context("when iptables is not executable", func() {
+		BeforeEach(func() {
+			Expect(os.Chmod(iptablesBin, 0644)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("iptables")).To(MatchError(ContainSubstring("is not executable")))
+		})
+	})
+
+	Context("when neither aufs nor overlay is available", func() {
+		BeforeEach(func() {
+			writeProcFile("filesystems", "nodev\tsysfs\n\text4\n")
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("layered-filesystem")).To(MatchError(ContainSubstring("neither aufs nor overlay")))
+		})
+
+		Context("and a layered filesystem is not required", func() {
+			BeforeEach(func() {
+				checker.RequireLayeredFS = false
+			})
+
+			It("skips the check", func() {
+				for _, result := range checker.Check() {
+					Expect(result.Name).NotTo(Equal("layered-filesystem"))
+				}
+			})
+		})
+	})
+
+	Context("when a directory does not exist yet", func() {
+		BeforeEach(func() {
+			checker.Dirs = []string{filepath.Join(depotDir, "does", "not", "exist")}
+		})
+
+		It("checks its nearest existing parent", func() {
+			Expect(resultFor("directory " + checker.Dirs[0])).To(Succeed())
+		})
+	})
+
+	Context("when a directory is a file", func() {
+		BeforeEach(func() {
+			path := filepath.Join(depotDir, "file")
+			Expect(ioutil.WriteFile(path, []byte{}, 0644)).To(Succeed())
+			checker.Dirs = []string{path}
+		})
+
+		It("returns an error", func() {
+			Expect(resultFor("directory " + checker.Dirs[0])).To(MatchError(ContainSubstring("is not a directory")))
+		})
+	})
+})
@@ -1,6 +1,10 @@
 package sysinfo
 
-import "github.com/cloudfoundry/gosigar"
+import (
+	"runtime"
+
+	"github.com/cloudfoundry/gosigar"
+)
 
 type ResourcesProvider struct {
 	depotPath string
@@ -34,6 +38,10 @@ func (provider ResourcesProvider) TotalDisk() (uint64, error) {
 	return fromKBytesToBytes(disk.Total), nil
 }
 
+func (provider ResourcesProvider) TotalCPUs() (uint64, error) {
+	return uint64(runtime.NumCPU()), nil
+}
+
 func fromKBytesToBytes(kbytes uint64) uint64 {
 	return kbytes * 1024
 }
@@ -35,4 +35,17 @@ var _ = Describe("", func() {
 			Expect(totalDisk).To(BeNumerically(">", 0))
 		})
 	})
+
+	Describe("TotalCPUs", func() {
+		BeforeEach(func() {
+			provider = sysinfo.NewResourcesProvider("/")
+		})
+
+		It("provides a nonzero number of cpus", func() {
+			totalCPUs, err := provider.TotalCPUs()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(totalCPUs).To(BeNumerically(">", 0))
+		})
+	})
 })
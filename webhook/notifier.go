@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . PropertyGetter
+
+type PropertyGetter interface {
+	All(handle string) (garden.Properties, error)
+}
+
+// Notifier POSTs container lifecycle events as JSON to a URL. Events are sent
+// in the background so that a slow or unavailable receiver does not hold up
+// garden; failed deliveries are logged and dropped.
+type Notifier struct {
+	url        string
+	properties PropertyGetter
+	client     *http.Client
+}
+
+func NewNotifier(url string, properties PropertyGetter, timeout time.Duration) *Notifier {
+	return &Notifier{
+		url:        url,
+		properties: properties,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *Notifier) Notify(log lager.Logger, event gardener.LifecycleEvent) {
+	if event.Properties == nil {
+		event.Properties, _ = n.properties.All(event.Handle)
+	}
+
+	go func() {
+		log = log.Session("webhook", lager.Data{"type": event.Type, "handle": event.Handle})
+		if err := n.send(event); err != nil {
+			log.Error("failed", err)
+			return
+		}
+
+		log.Debug("sent")
+	}()
+}
+
+func (n *Notifier) send(event gardener.LifecycleEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/guardian/webhook"
+	"code.cloudfoundry.org/guardian/webhook/webhookfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		logger     *lagertest.TestLogger
+		server     *ghttp.Server
+		properties *webhookfakes.FakePropertyGetter
+		received   chan gardener.LifecycleEvent
+		notifier   *webhook.Notifier
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		properties = new(webhookfakes.FakePropertyGetter)
+		received = make(chan gardener.LifecycleEvent, 1)
+
+		server = ghttp.NewServer()
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/events"),
+			ghttp.VerifyContentType("application/json"),
+			func(w http.ResponseWriter, r *http.Request) {
+				var event gardener.LifecycleEvent
+				Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+				received <- event
+			},
+		))
+
+		notifier = webhook.NewNotifier(server.URL()+"/events", properties, time.Second)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the event", func() {
+		notifier.Notify(logger, gardener.LifecycleEvent{
+			Type:       gardener.ContainerCreatedEvent,
+			Handle:     "some-handle",
+			Properties: garden.Properties{"foo": "bar"},
+		})
+
+		Eventually(received).Should(Receive(Equal(gardener.LifecycleEvent{
+			Type:       gardener.ContainerCreatedEvent,
+			Handle:     "some-handle",
+			Properties: garden.Properties{"foo": "bar"},
+		})))
+		Expect(properties.AllCallCount()).To(Equal(0))
+	})
+
+	Context("when the event has no properties", func() {
+		BeforeEach(func() {
+			properties.AllReturns(garden.Properties{"looked": "up"}, nil)
+		})
+
+		It("looks up the container's properties", func() {
+			notifier.Notify(logger, gardener.LifecycleEvent{Type: gardener.ContainerOOMedEvent, Handle: "some-handle"})
+
+			Eventually(received).Should(Receive(Equal(gardener.LifecycleEvent{
+				Type:       gardener.ContainerOOMedEvent,
+				Handle:     "some-handle",
+				Properties: garden.Properties{"looked": "up"},
+			})))
+			Expect(properties.AllArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Context("and they cannot be looked up", func() {
+			BeforeEach(func() {
+				properties.AllReturns(nil, errors.New("gone"))
+			})
+
+			It("still posts the event", func() {
+				notifier.Notify(logger, gardener.LifecycleEvent{Type: gardener.ContainerExitedEvent, Handle: "some-handle"})
+
+				Eventually(received).Should(Receive(Equal(gardener.LifecycleEvent{
+					Type:   gardener.ContainerExitedEvent,
+					Handle: "some-handle",
+				})))
+			})
+		})
+	})
+
+	Context("when the properties change while the event is sent", func() {
+		var manager *properties.Manager
+
+		BeforeEach(func() {
+			manager = properties.NewManager()
+			manager.Set("some-handle", "foo", "bar")
+
+			notifier = webhook.NewNotifier(server.URL()+"/events", manager, time.Second)
+		})
+
+		It("sends the properties as they were when notified", func() {
+			notifier.Notify(logger, gardener.LifecycleEvent{Type: gardener.ContainerOOMedEvent, Handle: "some-handle"})
+			for i := 0; i < 100; i++ {
+				manager.Set("some-handle", "foo", "baz")
+			}
+
+			Eventually(received).Should(Receive(Equal(gardener.LifecycleEvent{
+				Type:       gardener.ContainerOOMedEvent,
+				Handle:     "some-handle",
+				Properties: garden.Properties{"foo": "bar"},
+			})))
+		})
+	})
+
+	Context("when the webhook returns an error status", func() {
+		BeforeEach(func() {
+			server.SetHandler(0, ghttp.RespondWith(http.StatusInternalServerError, ""))
+		})
+
+		It("logs the failure", func() {
+			notifier.Notify(logger, gardener.LifecycleEvent{Type: gardener.ContainerDestroyedEvent, Handle: "some-handle"})
+
+			Eventually(logger).Should(gbytes.Say("test.webhook.failed"))
+		})
+	})
+
+	Context("when the webhook does not respond in time", func() {
+		BeforeEach(func() {
+			server.SetHandler(0, func(http.ResponseWriter, *http.Request) {
+				time.Sleep(2 * time.Second)
+			})
+		})
+
+		It("does not block the caller", func() {
+			start := time.Now()
+			notifier.Notify(logger, gardener.LifecycleEvent{Type: gardener.ContainerCreatedEvent, Handle: "some-handle"})
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+
+			Eventually(logger, "3s").Should(gbytes.Say("test.webhook.failed"))
+		})
+	})
+})